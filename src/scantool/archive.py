@@ -0,0 +1,111 @@
+"""
+FILE: archive.py
+
+PROBLEM:
+  Code often arrives as a .zip or .tar.gz — a submission, a vendored
+  dependency, a release tarball. Extracting it to scan means disk writes,
+  cleanup, and trusting the archive's paths and sizes.
+
+SOLUTION:
+  Iterate archive entries without extracting: regular files only, each
+  with a normalized "/"-separated name inside the archive and a bounded
+  reader. FileScanner.scan_archive feeds the bytes to scan_content.
+
+  Untrusted archives are handled defensively:
+    - zip-slip: absolute names, drive letters and ".." components are
+      skipped (nothing is written, but keys stay inside the archive)
+    - decompression bombs: read_limited() never reads more than the limit
+      plus one byte, whatever size the header claims
+    - symlinks, hard links and devices are skipped
+
+SCOPE:
+  ✓ zip, tar, tar.gz / tgz, tar.bz2, tar.xz
+  ✗ Nested archives are listed, not opened; encrypted zips are not read
+"""
+
+import stat
+import tarfile
+import zipfile
+from dataclasses import dataclass
+from datetime import datetime
+from pathlib import PurePosixPath
+from typing import Callable, Iterator, Optional
+
+ARCHIVE_SUFFIXES = (".zip", ".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz")
+
+
+@dataclass
+class ArchiveEntry:
+    """One regular file inside an archive."""
+
+    name: str                           # safe relative path, "/"-separated
+    size: int                           # uncompressed size the header declares
+    mtime: float                        # seconds since the epoch, 0 if unknown
+    open_content: Callable[[], object]  # binary file object for the content
+
+
+def is_archive(path: str) -> bool:
+    return path.lower().endswith(ARCHIVE_SUFFIXES)
+
+
+def safe_entry_name(name: str) -> Optional[str]:
+    """Entry name as a relative posix path, or None when it would escape
+    the archive root (absolute, drive letter, "..")."""
+    candidate = name.replace("\\", "/")
+    if candidate.startswith("/") or (len(candidate) > 1 and candidate[1] == ":"):
+        return None
+    parts = [part for part in PurePosixPath(candidate).parts if part not in ("", ".")]
+    if not parts or ".." in parts:
+        return None
+    return "/".join(parts)
+
+
+def read_limited(entry: ArchiveEntry, limit: Optional[int]) -> Optional[bytes]:
+    """Entry content, or None when it is larger than limit bytes — decided
+    by what decompresses, not by the header."""
+    with entry.open_content() as handle:
+        data = handle.read() if limit is None else handle.read(limit + 1)
+    if limit is not None and len(data) > limit:
+        return None
+    return data
+
+
+def _zip_entries(archive: zipfile.ZipFile) -> Iterator[ArchiveEntry]:
+    for info in archive.infolist():
+        if info.is_dir() or stat.S_ISLNK(info.external_attr >> 16):
+            continue
+        name = safe_entry_name(info.filename)
+        if name is None:
+            continue
+        try:
+            mtime = datetime(*info.date_time).timestamp()
+        except ValueError:
+            mtime = 0.0
+        yield ArchiveEntry(name, info.file_size, mtime,
+                           lambda info=info: archive.open(info))
+
+
+def _tar_entries(archive: tarfile.TarFile) -> Iterator[ArchiveEntry]:
+    for member in archive:
+        if not member.isfile():
+            continue
+        name = safe_entry_name(member.name)
+        if name is None:
+            continue
+        yield ArchiveEntry(name, member.size, float(member.mtime),
+                           lambda member=member: archive.extractfile(member))
+
+
+def iter_entries(path: str) -> Iterator[ArchiveEntry]:
+    """Regular files of a zip or tar archive in archive order. ValueError
+    when the file is neither."""
+    if zipfile.is_zipfile(path):
+        with zipfile.ZipFile(path) as archive:
+            yield from _zip_entries(archive)
+        return
+    try:
+        archive = tarfile.open(path, "r:*")
+    except tarfile.TarError as e:
+        raise ValueError(f"Not a zip or tar archive: {path}") from e
+    with archive:
+        yield from _tar_entries(archive)
@@ -0,0 +1,127 @@
+"""
+FILE: content_hashes.py
+
+PROBLEM:
+  A client that caches scan results, or dedups vendored copies, needs to
+  know "has this file / this function changed since I last looked" without
+  re-reading and diffing it. The scan reads every byte already; it only
+  has to say so.
+
+SOLUTION:
+  Every scanned file carries content_hash in its file-info metadata:
+  SHA-256 of the raw bytes as read, BOM and line endings included, so it
+  matches `sha256sum`. Every declaration carries body_hash from
+  symbol_ids (sha1 of its source lines, trailing whitespace and blank
+  lines dropped). This module just collects both per file and groups the
+  files whose content hashes are equal.
+
+SCOPE:
+  ✓ Every file the scanner parses, whatever its language
+  ✗ Neither hash is AST-normalized: reformatting or editing a comment
+    inside a function changes its body_hash, any edit changes the file's
+  ✗ Files listed but not read (unsupported types, skipped as too large
+    or binary) have no hash
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from .languages import StructureNode
+
+# Nodes without a body of their own
+_NON_SYMBOL_TYPES = {"file-info", "imports", "error", "parse-error"}
+
+
+@dataclass
+class SymbolHash:
+    symbol_id: str
+    line: int
+    body_hash: str
+
+    def to_dict(self) -> dict:
+        return {"id": self.symbol_id, "line": self.line, "body_hash": self.body_hash}
+
+
+@dataclass
+class FileHash:
+    file: str
+    content_hash: str
+    symbols: list[SymbolHash] = field(default_factory=list)
+
+    def to_dict(self, include_symbols: bool = False) -> dict:
+        data = {"file": self.file, "content_hash": self.content_hash}
+        if include_symbols:
+            data["symbols"] = [s.to_dict() for s in self.symbols]
+        return data
+
+
+def content_hash(structures: Optional[list[StructureNode]]) -> Optional[str]:
+    """The file-info content_hash of one file's scan, None if it wasn't read."""
+    if not structures or structures[0].type != "file-info":
+        return None
+    return (structures[0].file_metadata or {}).get("content_hash")
+
+
+def collect_hashes(results: dict[str, Optional[list[StructureNode]]]) -> list[FileHash]:
+    """A FileHash per read file of a directory scan, by path, with its
+    declarations' body hashes in source order."""
+    hashes = []
+    for file_path in sorted(results):
+        structures = results[file_path]
+        digest = content_hash(structures)
+        if digest is None:
+            continue
+        entry = FileHash(file_path, digest)
+
+        def walk(nodes: list[StructureNode]):
+            for node in nodes:
+                if node.type in _NON_SYMBOL_TYPES:
+                    continue
+                if node.symbol_id and node.body_hash:
+                    entry.symbols.append(SymbolHash(node.symbol_id, node.start_line,
+                                                    node.body_hash))
+                walk(node.children)
+
+        walk(structures)
+        hashes.append(entry)
+    return hashes
+
+
+def identical_files(hashes: list[FileHash]) -> list[list[str]]:
+    """Groups of two or more files with the same bytes, each sorted, the
+    groups by their first path."""
+    groups: dict[str, list[str]] = {}
+    for entry in hashes:
+        groups.setdefault(entry.content_hash, []).append(entry.file)
+    return sorted((sorted(files) for files in groups.values() if len(files) > 1),
+                  key=lambda files: files[0])
+
+
+def format_hashes(hashes: list[FileHash], directory: str,
+                  include_symbols: bool = False) -> str:
+    """"hash  file" lines like sha256sum, optionally the symbols under each,
+    then the groups of identical files."""
+    if not hashes:
+        return f"No readable files in {directory}"
+
+    root = Path(directory).resolve()
+
+    def shown(file_path: str) -> str:
+        try:
+            return Path(file_path).resolve().relative_to(root).as_posix()
+        except ValueError:
+            return file_path
+
+    lines = [f"{len(hashes)} files in {directory} (SHA-256 of the raw bytes)"]
+    for entry in hashes:
+        lines.append(f"{entry.content_hash}  {shown(entry.file)}")
+        if include_symbols:
+            for symbol in entry.symbols:
+                lines.append(f"    {symbol.body_hash}  @{symbol.line} {symbol.symbol_id}")
+    groups = identical_files(hashes)
+    if groups:
+        lines.append(f"\n{len(groups)} groups of identical files:")
+        for files in groups:
+            lines.append("- " + ", ".join(shown(f) for f in files))
+    return "\n".join(lines)
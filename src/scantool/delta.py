@@ -54,6 +54,17 @@ def node_key(node, parent_chain: str = "") -> str:
     return f"{parent_chain}/{node.type}:{node.name}"
 
 
+def block_hash(node, source_lines: list[str]) -> str:
+    """sha1 of a node's source lines, whitespace-normalized: trailing-space
+    and blank-line edits are not structural changes."""
+    block = "\n".join(
+        line.rstrip()
+        for line in source_lines[node.start_line - 1:node.end_line]
+        if line.strip()
+    )
+    return hashlib.sha1(block.encode()).hexdigest()
+
+
 def node_hashes(structures, source_lines: list[str]) -> dict[str, str]:
     """Fingerprint every named node by its source block — the shared
     primitive for both session deltas and ref diffs."""
@@ -63,14 +74,7 @@ def node_hashes(structures, source_lines: list[str]) -> dict[str, str]:
         for node in nodes or []:
             if node.type != "file-info" and node.name:
                 key = node_key(node, chain)
-                # whitespace-normalized: trailing-space/blank-line edits are
-                # not structural changes
-                block = "\n".join(
-                    line.rstrip()
-                    for line in source_lines[node.start_line - 1:node.end_line]
-                    if line.strip()
-                )
-                hashes[key] = hashlib.sha1(block.encode()).hexdigest()
+                hashes[key] = block_hash(node, source_lines)
                 walk(node.children, key)
             else:
                 walk(node.children, chain)
@@ -4,6 +4,7 @@ from pathlib import Path
 from typing import Optional
 from datetime import datetime
 from .languages import StructureNode, is_unsupported_stub
+from .package_groups import file_package
 
 
 class DirectoryFormatter:
@@ -64,7 +65,7 @@ class DirectoryFormatter:
     def __init__(self, show_signatures: bool = True, show_decorators: bool = True,
                  show_docstrings: bool = True, show_complexity: bool = False,
                  include_structures: bool = True, flatten_structures: bool = False,
-                 include_glimpse: bool = True):
+                 include_glimpse: bool = True, show_packages: bool = False):
         """
         Initialize directory formatter with display options.
 
@@ -73,6 +74,9 @@ class DirectoryFormatter:
                                without nested children (methods). Reduces output by ~50%.
             include_glimpse: One line per file with the most salient node's
                              depth-1 skeleton gist (~25 tokens per code file)
+            show_packages: Label directories with the packages their files
+                           declare; in a directory with several (Go external
+                           _test packages), each file names its own
         """
         self.show_signatures = show_signatures
         self.show_decorators = show_decorators
@@ -81,6 +85,7 @@ class DirectoryFormatter:
         self.include_structures = include_structures
         self.flatten_structures = flatten_structures
         self.include_glimpse = include_glimpse
+        self.show_packages = show_packages
 
     @classmethod
     def _glimpse_line(cls, structures) -> Optional[str]:
@@ -134,7 +139,7 @@ class DirectoryFormatter:
         tree = self._build_tree(base_path, file_structures)
 
         # Format as text
-        lines = [f"{base_path.name}/ {self._format_stats(tree)}"]
+        lines = [f"{base_path.name}/ {self._format_stats(tree)}{self._package_label(tree)}"]
         lines.extend(self._format_tree_node(tree, ""))
 
         return "\n".join(lines)
@@ -226,9 +231,20 @@ class DirectoryFormatter:
 
         return f"({', '.join(parts)})" if parts else ""
 
+    @staticmethod
+    def _packages(node: dict) -> list[str]:
+        """Packages declared by the files directly in a directory node."""
+        return sorted({package for child in node["files"].values()
+                       if (package := file_package(child["structures"]))})
+
+    def _package_label(self, node: dict) -> str:
+        packages = self._packages(node) if self.show_packages else []
+        return f" [package {', '.join(packages)}]" if packages else ""
+
     def _format_tree_node(self, node: dict, prefix: str) -> list[str]:
         """Recursively format a tree node and its children."""
         lines = []
+        mixed = self.show_packages and len(self._packages(node)) > 1
 
         # Get sorted children and files
         dirs = sorted(node["children"].items())
@@ -244,7 +260,7 @@ class DirectoryFormatter:
             if is_dir:
                 # Directory
                 stats_str = self._format_stats(child)
-                lines.append(f"{prefix}{connector} {name}/ {stats_str}")
+                lines.append(f"{prefix}{connector} {name}/ {stats_str}{self._package_label(child)}")
 
                 # Recurse into directory
                 child_prefix = prefix + (self.SPACE if is_last else self.VERTICAL)
@@ -253,6 +269,8 @@ class DirectoryFormatter:
             else:
                 # File
                 structures = child["structures"]
+                if mixed and file_package(structures):
+                    name = f"{name} (package {file_package(structures)})"
 
                 # Unsupported file: only a file-info stub with the unsupported flag
                 if is_unsupported_stub(structures):
@@ -266,8 +284,10 @@ class DirectoryFormatter:
 
                     # Build metadata string: size, relative time, git churn
                     churn = metadata.get("churn_90d")
+                    skipped = metadata.get("skipped")
                     meta_parts = [size, modified_relative,
-                                  f"{churn}x/90d" if churn else ""]
+                                  f"{churn}x/90d" if churn else "",
+                                  f"skipped: {skipped}" if skipped else ""]
                     meta_str = ", ".join(p for p in meta_parts if p)
                     lines.append(f"{prefix}{connector} {name} [{meta_str}]")
                 else:
@@ -289,7 +309,9 @@ class DirectoryFormatter:
                         churn = file_metadata.get("churn_90d")
 
                         meta_parts = [size, modified_relative,
-                                      f"{churn}x/90d" if churn else ""]
+                                      f"{churn}x/90d" if churn else "",
+                                      "invalid UTF-8" if file_metadata.get("encoding_warning")
+                                      else ""]
                         metadata_str = " [" + ", ".join(p for p in meta_parts if p) + "]"
 
                     # Format file line
@@ -351,6 +373,8 @@ class DirectoryFormatter:
         if hasattr(node, "modifiers") and node.modifiers:
             modifiers_str = " ".join(node.modifiers)
             parts.append(f"[{modifiers_str}]")
+        if getattr(node, "deprecated", None):
+            parts.append("[deprecated]")
 
         lines.append(" ".join(parts))
 
@@ -0,0 +1,109 @@
+"""
+FILE: file_json.py
+
+PROBLEM:
+  The JSON view of one file's scan result is needed by the MCP tools and
+  by the NDJSON stream of the scanner itself; the scanner can't import the
+  server to get it.
+
+SOLUTION:
+  One function from (structures, path) to the per-file object of
+  output_format="json": {"file", "structures", ...file-level facts}. The
+  result schema (result_schema.py) describes exactly this object.
+"""
+
+from typing import Optional
+
+from .languages import StructureNode, function_kind, parse_errors, skip_reason
+from .positions import SourcePositions
+
+
+def file_to_dict(structures: list[StructureNode], file_path: str,
+                 positions: Optional[SourcePositions] = None) -> dict:
+    """The JSON object of one file's structures; with positions, every
+    node carries its "positions" ranges in that file."""
+
+    def node_to_dict(node: StructureNode) -> dict:
+        """Convert a single node to dictionary."""
+        result = {
+            "type": node.type,
+            "name": node.name,
+            "start_line": node.start_line,
+            "end_line": node.end_line,
+        }
+
+        if node.type != "file-info":
+            result["line_count"] = node.line_count
+        if node.start_offset is not None:
+            result["start_offset"] = node.start_offset
+            result["end_offset"] = node.end_offset
+            result["start_utf16_column"] = node.start_utf16_column
+            result["end_utf16_column"] = node.end_utf16_column
+        if node.symbol_id:
+            result["id"] = node.symbol_id
+        if node.body_hash:
+            result["body_hash"] = node.body_hash
+        if node.signature:
+            result["signature"] = node.signature
+        if node.full_signature:
+            result["full_signature"] = node.full_signature
+        if node.decorators:
+            result["decorators"] = node.decorators
+        if node.docstring:
+            result["docstring"] = node.docstring
+        if node.doc:
+            result["doc"] = node.doc
+        if node.deprecated:
+            result["deprecated"] = True
+        if node.annotations:
+            result["annotations"] = node.annotations
+        if node.modifiers:
+            result["modifiers"] = node.modifiers
+        if node.fields is not None:
+            result["fields"] = [
+                {"name": f.name, "type": f.type, **({"tag": f.tag} if f.tag else {})}
+                for f in node.fields
+            ]
+        if node.visibility:
+            result["visibility"] = node.visibility
+        if node.receiver_type:
+            result["receiver_type"] = node.receiver_type
+        if node.receiver_kind:
+            result["receiver_kind"] = node.receiver_kind
+        kind = function_kind(node)
+        if kind:
+            result["func_kind"] = kind
+        if node.methods:
+            result["methods"] = node.methods
+        if node.uses_packages:
+            result["uses_packages"] = node.uses_packages
+        if node.complexity:
+            result["complexity"] = node.complexity
+        if positions is not None and (ranges := positions.of(node)) is not None:
+            result["positions"] = ranges
+        if node.children:
+            result["children"] = [node_to_dict(child) for child in node.children]
+
+        return result
+
+    data = {
+        "file": file_path,
+        "structures": [node_to_dict(s) for s in structures]
+    }
+    # File-level facts live on the file-info node: the language (mixed
+    # directory results stay distinguishable), the file/package doc and the
+    # code/comment/blank line breakdown
+    if structures and structures[0].type == "file-info" and structures[0].file_metadata:
+        for key in ("language", "package", "doc", "lines", "generated", "build_constraint",
+                    "imports", "content_hash", "encoding_warning", "meta"):
+            value = structures[0].file_metadata.get(key)
+            if value:
+                data[key] = value
+    reason = skip_reason(structures)
+    if reason:
+        data["skipped"] = reason
+    errors = parse_errors(file_path, structures)
+    if errors:
+        data["parse_errors"] = [e.to_dict() for e in errors]
+
+    return data
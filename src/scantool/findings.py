@@ -0,0 +1,82 @@
+"""
+FILE: findings.py
+
+PROBLEM:
+  The scan already knows things a reviewer or CI job wants flagged — a
+  function too complex to review, a file that doesn't parse — but only as
+  numbers and nodes inside a structure tree, not as findings with a rule
+  and a location.
+
+SOLUTION:
+  A flat list of Finding records (rule, message, file, line range) derived
+  from scan results, with a fixed rule catalogue (RULES) so exporters such
+  as SARIF can emit one rule per category and reference it by index.
+
+SCOPE:
+  ✓ Pure function of scan_directory/scan_file results
+  ✗ No suppression/baseline handling — that belongs to the consumer (CI)
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from .languages import StructureNode, is_unsupported_stub
+from .symbol_filter import cyclomatic_complexity
+
+DEFAULT_COMPLEXITY_THRESHOLD = 15
+
+
+@dataclass(frozen=True)
+class Rule:
+    id: str
+    name: str
+    description: str
+    level: str  # SARIF level: "error", "warning", "note"
+
+
+RULES: dict[str, Rule] = {rule.id: rule for rule in (
+    Rule("complexity/cyclomatic", "HighCyclomaticComplexity",
+         "Function cyclomatic complexity is above the threshold", "warning"),
+    Rule("syntax/parse-error", "ParseError",
+         "Source region could not be parsed", "error"),
+)}
+
+
+@dataclass
+class Finding:
+    rule_id: str          # key into RULES
+    message: str
+    file: str
+    start_line: int
+    end_line: int
+    symbol: Optional[str] = None
+
+
+def collect_findings(results: dict[str, Optional[list[StructureNode]]],
+                     complexity_threshold: int = DEFAULT_COMPLEXITY_THRESHOLD) -> list[Finding]:
+    """Findings for every scanned file, in path then line order."""
+    findings = []
+    for file_path in sorted(results):
+        structures = results[file_path]
+        if not structures or is_unsupported_stub(structures):
+            continue
+
+        def walk(nodes: list[StructureNode]):
+            for node in nodes:
+                if node.type == "parse-error":
+                    findings.append(Finding(
+                        "syntax/parse-error", "Invalid syntax", file_path,
+                        node.start_line, node.end_line))
+                elif node.type in ("function", "method"):
+                    score = cyclomatic_complexity(node)
+                    if score is not None and score > complexity_threshold:
+                        findings.append(Finding(
+                            "complexity/cyclomatic",
+                            f"{node.name} has cyclomatic complexity {score} "
+                            f"(threshold {complexity_threshold})",
+                            file_path, node.start_line, node.end_line, symbol=node.name))
+                walk(node.children)
+
+        walk(structures)
+    findings.sort(key=lambda f: (f.file, f.start_line, f.rule_id))
+    return findings
@@ -18,7 +18,8 @@ class TreeFormatter:
 
     def __init__(self, show_signatures: bool = True, show_decorators: bool = True,
                  show_docstrings: bool = True, show_complexity: bool = False,
-                 condense: bool = True):
+                 condense: bool = True, show_positions: bool = True,
+                 show_code: bool = True):
         """
         Initialize formatter with display options.
 
@@ -29,12 +30,16 @@ class TreeFormatter:
             show_complexity: Display complexity metrics
             condense: Show condensed skeletons (pseudocode lines without
                 line numbers) instead of verbatim excerpts where available
+            show_positions: Display @line positions
+            show_code: Display skeletons/excerpts of salient nodes
         """
         self.show_signatures = show_signatures
         self.show_decorators = show_decorators
         self.show_docstrings = show_docstrings
         self.show_complexity = show_complexity
         self.condense = condense
+        self.show_positions = show_positions
+        self.show_code = show_code
 
     def format(self, file_path: str, structures: list[StructureNode]) -> str:
         """Format the structure as a pretty tree."""
@@ -43,7 +48,7 @@ class TreeFormatter:
 
         # Get file line range (excluding metadata nodes with line 0)
         content_nodes = [s for s in self._flatten(structures) if s.start_line > 0 or s.end_line > 0]
-        if content_nodes:
+        if content_nodes and self.show_positions:
             min_line = min(s.start_line for s in content_nodes)
             max_line = max(s.end_line for s in content_nodes)
             lines = [f"{Path(file_path).name} ({min_line}-{max_line})"]
@@ -83,11 +88,15 @@ class TreeFormatter:
                 modified_str = ""
 
             churn = meta.get("churn_90d")
+            constraint = meta.get("build_constraint")
+            warning = meta.get("encoding_warning")
             parts = [
                 f"{prefix}{connector} {node.type}:",
                 meta['size_formatted'],
                 f"modified: {modified_str}" if modified_str else "",
-                f"churn: {churn} commits/90d" if churn else ""
+                f"churn: {churn} commits/90d" if churn else "",
+                f"build: {constraint}" if constraint else "",
+                f"[{warning}]" if warning else ""
             ]
             lines.append(" ".join(p for p in parts if p))
             return lines
@@ -101,13 +110,15 @@ class TreeFormatter:
             parts.append(node.signature)
 
         # Add line numbers in compact format @startline
-        if node.start_line > 0 or node.end_line > 0:
+        if self.show_positions and (node.start_line > 0 or node.end_line > 0):
             parts.append(f"@{node.start_line}")
 
         # Add modifiers if present
         if node.modifiers:
             modifiers_str = " ".join(node.modifiers)
             parts.append(f"[{modifiers_str}]")
+        if node.deprecated:
+            parts.append("[deprecated]")
 
         # Per-node git activity (only set when counts differ across nodes)
         if node.recent_edits:
@@ -137,13 +148,13 @@ class TreeFormatter:
 
         # Add code for salient (high-entropy) nodes: condensed skeleton when
         # available, verbatim excerpt otherwise
-        if node.code_skeleton and self.condense:
+        if self.show_code and node.code_skeleton and self.condense:
             # Plain pseudocode lines, no line numbers — that absence is what
             # distinguishes condensed skeletons from verbatim excerpts
             code_prefix = prefix + (self.SPACE if is_last else self.VERTICAL) + " "  # 2-space indent
             for line in node.code_skeleton:
                 lines.append(f"{code_prefix}{line}")
-        elif node.code_excerpt:
+        elif self.show_code and node.code_excerpt:
             code_prefix = prefix + (self.SPACE if is_last else self.VERTICAL) + " "  # 2-space indent
 
             # No blank line (token-optimized)
@@ -14,6 +14,8 @@ SOLUTION:
 
 SCOPE:
   ✓ churn (commits per file in window), co-change (files changed together)
+  ✓ changed files against a ref (scan_directory's git diff base) — the one
+    explicit request here, so it raises instead of returning None
   ✗ Not per-function churn (requires hunk→node mapping)
   ✗ Not blame/author analysis
 """
@@ -239,3 +241,38 @@ def file_churn(file_path: str, window_days: int = 90) -> Optional[int]:
         return int(out.strip())
     except ValueError:
         return None
+
+
+def changed_files(directory: str, ref: str) -> set[str]:
+    """Absolute paths of files under directory that differ from ref in the
+    working tree (committed, staged, unstaged and untracked). Deleted files
+    are left out; renamed files appear at their new path.
+
+    Unlike the signals above this is an explicit request, not an optional
+    label — silently falling back to "everything" would hide the mistake,
+    so failures raise ValueError instead of returning None.
+    """
+    toplevel = _run_git(directory, "rev-parse", "--show-toplevel")
+    if toplevel is None:
+        raise ValueError(f"{directory} is not in a git repository — "
+                         f"a git diff base needs one")
+    toplevel = toplevel.strip()
+    if _run_git(directory, "rev-parse", "--verify", "--quiet", f"{ref}^{{commit}}") is None:
+        raise ValueError(f"Unknown git ref: {ref!r}")
+
+    # Both listings are toplevel-relative ("-- ." limits them to directory)
+    name_status = _run_git(directory, "diff", "--name-status", "-M", ref, "--", ".")
+    untracked = _run_git(directory, "ls-files", "--others", "--exclude-standard", "--full-name")
+    if name_status is None or untracked is None:
+        raise ValueError(f"git diff against {ref!r} failed")
+
+    paths = set()
+    for line in name_status.splitlines():
+        parts = line.split("\t")
+        if len(parts) < 2 or parts[0].startswith("D"):
+            continue
+        paths.add(os.path.realpath(os.path.join(toplevel, parts[-1])))
+    for rel_path in untracked.splitlines():
+        if rel_path:
+            paths.add(os.path.realpath(os.path.join(toplevel, rel_path)))
+    return paths
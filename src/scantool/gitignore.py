@@ -23,12 +23,12 @@ class GitignoreParser:
                 continue
             self.patterns.append(self._compile_pattern(pattern))
 
-    def _compile_pattern(self, pattern: str) -> tuple[re.Pattern, bool]:
+    def _compile_pattern(self, pattern: str) -> tuple[re.Pattern, bool, bool]:
         """
         Compile a gitignore pattern to regex.
 
         Returns:
-            Tuple of (compiled_regex, is_negation)
+            Tuple of (compiled_regex, is_negation, is_dir_only)
         """
         is_negation = pattern.startswith('!')
         if is_negation:
@@ -41,12 +41,13 @@ class GitignoreParser:
         else:
             is_dir_only = False
 
-        # Anchored pattern (starts with /)
+        # Anchored pattern: a leading slash, or a slash anywhere but the end,
+        # makes the pattern relative to the .gitignore's own directory
         if pattern.startswith('/'):
             pattern = pattern[1:]
             anchored = True
         else:
-            anchored = False
+            anchored = '/' in pattern
 
         # Convert gitignore glob to regex
         regex_parts = []
@@ -55,12 +56,14 @@ class GitignoreParser:
             char = pattern[i]
             if char == '*':
                 if i + 1 < len(pattern) and pattern[i + 1] == '*':
-                    # ** matches any number of directories
-                    regex_parts.append('.*')
                     i += 2
-                    # Skip following /
                     if i < len(pattern) and pattern[i] == '/':
+                        # "**/" matches zero or more leading directories
+                        regex_parts.append('(?:.*/)?')
                         i += 1
+                    else:
+                        # trailing "/**" (or a bare "**") matches everything inside
+                        regex_parts.append('.*')
                     continue
                 else:
                     # * matches anything except /
@@ -83,22 +86,43 @@ class GitignoreParser:
 
         regex_str = ''.join(regex_parts)
 
-        # Build final pattern
-        # Pattern should match:
-        # 1. The exact name (.venv matches .venv)
-        # 2. The name as a directory (.venv matches .venv/)
-        # 3. Anything under it (.venv matches .venv/foo/bar.py)
+        # The regex captures whether the pattern matched the path itself or
+        # one of its parent directories (group "inside"): a directory-only
+        # pattern may match a file only through a parent directory.
+        prefix = '^' if anchored else '(?:^|/)'
+        final_pattern = f'{prefix}{regex_str}(?P<inside>/.*)?$'
 
-        if anchored:
-            # Must match from start
-            # Matches: exact name, or name followed by / and anything
-            final_pattern = f'^{regex_str}(?:/.*)?$'
-        else:
-            # Can match anywhere in path
-            # Matches at start or after /, then exact name or name/ with anything
-            final_pattern = f'(?:^|/){regex_str}(?:/.*)?$'
+        return (re.compile(final_pattern), is_negation, is_dir_only)
+
+    def check(self, path: str, is_dir: bool = False) -> Optional[bool]:
+        """
+        Evaluate the patterns against a path; the last matching pattern wins.
 
-        return (re.compile(final_pattern), is_negation)
+        Args:
+            path: Relative path to check (forward slashes)
+            is_dir: Whether the path is a directory
+
+        Returns:
+            True if ignored, False if re-included by a negation, None if no
+            pattern matched at all
+        """
+        # Normalize path (remove leading ./ and a trailing directory slash)
+        if path.startswith('./'):
+            path = path[2:]
+        if path.endswith('/'):
+            path = path.rstrip('/')
+            is_dir = True
+
+        verdict = None
+        for regex, is_negation, is_dir_only in self.patterns:
+            match = regex.search(path)
+            if not match:
+                continue
+            if is_dir_only and not is_dir and match.group('inside') is None:
+                continue
+            verdict = not is_negation
+
+        return verdict
 
     def matches(self, path: str, is_dir: bool = False) -> bool:
         """
@@ -111,16 +135,49 @@ class GitignoreParser:
         Returns:
             True if path should be ignored
         """
-        # Normalize path (remove leading ./ if present)
+        return bool(self.check(path, is_dir))
+
+
+class GitignoreTree:
+    """Root-level gitignore rules plus nested .gitignore files found while
+    walking a tree. A nested file applies only to paths below its own
+    directory, with paths matched relative to it; deeper files take
+    precedence (a nested "!keep.log" re-includes what the root ignored)."""
+
+    def __init__(self, root: Optional[GitignoreParser] = None):
+        self.root = root
+        self.nested: dict[str, GitignoreParser] = {}  # rel dir -> parser
+
+    def add_directory(self, rel_dir: str, abs_dir: Path) -> None:
+        """Load abs_dir/.gitignore (if any) as rules scoped to rel_dir."""
+        if not rel_dir or rel_dir in self.nested:
+            return
+        gitignore_path = abs_dir / '.gitignore'
+        if not gitignore_path.is_file():
+            return
+        try:
+            patterns = gitignore_path.read_text(encoding='utf-8').splitlines()
+        except (OSError, UnicodeDecodeError):
+            return
+        parser = GitignoreParser(patterns)
+        if parser.patterns:
+            self.nested[rel_dir] = parser
+
+    def matches(self, path: str, is_dir: bool = False) -> bool:
+        """Check a root-relative path against all applicable .gitignore files."""
         if path.startswith('./'):
             path = path[2:]
-
-        ignored = False
-        for regex, is_negation in self.patterns:
-            if regex.search(path):
-                ignored = not is_negation
-
-        return ignored
+        path = path.replace('\\', '/')
+        verdict = self.root.check(path, is_dir) if self.root else None
+        parts = path.rstrip('/').split('/')
+        for depth in range(1, len(parts)):
+            parser = self.nested.get('/'.join(parts[:depth]))
+            if parser is None:
+                continue
+            nested_verdict = parser.check('/'.join(parts[depth:]), is_dir)
+            if nested_verdict is not None:
+                verdict = nested_verdict
+        return bool(verdict)
 
 
 def load_gitignore(directory: Path) -> Optional[GitignoreParser]:
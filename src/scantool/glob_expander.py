@@ -1,8 +1,14 @@
-"""Expand bash-style brace patterns in glob expressions."""
+"""Expand bash-style brace patterns in glob expressions, match doublestar
+globs ("internal/**", "**/*_test.go") against relative paths, and read the
+.scanignore file of exclude globs at a scan root."""
 
 import re
+from functools import lru_cache
+from pathlib import Path
 from typing import List
 
+SCANIGNORE_FILE = ".scanignore"
+
 
 def expand_braces(pattern: str) -> List[str]:
     """
@@ -46,3 +52,70 @@ def expand_braces(pattern: str) -> List[str]:
         expanded.extend(expand_braces(new_pattern))
 
     return expanded
+
+
+def _segment_regex(segment: str) -> str:
+    """One path segment: * and ? stay within the segment, [...] is a class."""
+    out = []
+    i = 0
+    while i < len(segment):
+        char = segment[i]
+        if char == "*":
+            out.append("[^/]*")
+        elif char == "?":
+            out.append("[^/]")
+        elif char == "[" and segment.find("]", i + 2) != -1:
+            end = segment.find("]", i + 2)  # "[]a]": a leading ] is literal
+            body = segment[i + 1:end]
+            if body.startswith("!"):
+                body = "^" + body[1:]
+            out.append(f"[{body}]")
+            i = end
+        else:
+            out.append(re.escape(char))
+        i += 1
+    return "".join(out)
+
+
+@lru_cache(maxsize=256)
+def compile_doublestar(pattern: str) -> re.Pattern:
+    """Regex for a doublestar glob over a "/"-separated relative path.
+
+    "**" as a whole segment matches zero or more directories ("**/x.go"
+    also matches "x.go"; "internal/**" everything below internal/); "*"
+    and "?" never cross a "/". Braces are expanded first.
+    """
+    alternatives = []
+    for expanded in expand_braces(pattern):
+        segments = expanded.strip("/").split("/")
+        parts = []
+        for i, segment in enumerate(segments):
+            last = i == len(segments) - 1
+            if segment == "**":
+                parts.append(".*" if last else "(?:[^/]+/)*")
+            else:
+                parts.append(_segment_regex(segment) + ("" if last else "/"))
+        alternatives.append("".join(parts))
+    return re.compile("^(?:" + "|".join(alternatives) + ")$")
+
+
+def matches_doublestar(rel_path: str, patterns: List[str]) -> bool:
+    """Whether a "/"-separated relative path matches any of the patterns."""
+    return any(compile_doublestar(p).match(rel_path) for p in patterns)
+
+
+def load_scanignore(directory: Path) -> List[str]:
+    """Exclude globs from directory/.scanignore: one doublestar glob per
+    line, blank lines and "#" comment lines skipped; a trailing "/" means
+    the whole directory ("gen/" = "gen/**"). [] if there is none."""
+    try:
+        text = (directory / SCANIGNORE_FILE).read_text(encoding="utf-8", errors="replace")
+    except OSError:
+        return []
+    patterns = []
+    for line in text.splitlines():
+        line = line.strip()
+        if not line or line.startswith("#"):
+            continue
+        patterns.append(line + "**" if line.endswith("/") else line)
+    return patterns
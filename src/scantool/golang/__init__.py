@@ -0,0 +1,11 @@
+"""Go source analysis over tree-sitter-go syntax trees.
+
+The structure scan (languages/go.py) answers "what is declared where". The
+modules here answer package-level Go questions that need the syntax tree
+itself — method sets, interface satisfaction, ... — each as plain functions
+over parsed files (syntax.GoFile) that the MCP tools wrap.
+
+Submodules are imported directly (``from scantool.golang import syntax``);
+this package deliberately re-exports nothing, because languages/go.py uses
+the syntax helpers and must not pull in the scanner on import.
+"""
@@ -0,0 +1,318 @@
+"""
+FILE: apistub.py
+
+PROBLEM:
+  The public API of a Go package is spread over its files and buried in
+  bodies and unexported helpers. go doc prints it, but not as Go: nothing
+  an LLM, a doc site or a mock generator can take as a compilable file.
+
+SOLUTION:
+  Render the exported API of one package directory as a single synthetic
+  .go file, declarations with their doc comments and no implementation:
+    - consts, then vars, then functions, then types — each type followed
+      by its exported methods, whichever file declares them
+    - function and method bodies are panic("stub"), so every signature
+      compiles whatever it returns
+    - structs keep exported fields (and embedded exported types);
+      interfaces keep exported methods and embedded/constraint elements;
+      anything left out is marked "// contains filtered or unexported
+      fields" like go doc does
+    - const/var declarations are kept verbatim for their exported specs;
+      a const group with implicit (iota-repeated) specs is kept whole, as
+      dropping a spec would change what the others mean
+    - imports: exactly those the rendered declarations qualify names
+      with, taken from the package's own import specs (aliases kept)
+  The result goes through gofmt when it is on PATH; without it the
+  layout is this module's (tabs, one blank line between declarations).
+
+SCOPE:
+  ✓ Generics, method receivers, grouped declarations, struct tags
+  ✗ A var/const value or alias target naming unexported identifiers
+    (var Default = newClient()) is kept as written and won't compile
+  ✗ Dot imports are not carried over; build tags and cgo are ignored
+"""
+
+import re
+import subprocess
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .formatting import gofmt_path
+from .imports import default_names, import_list, qualifiers
+from .syntax import GoFile
+
+_GOFMT_TIMEOUT = 10.0
+
+_HEADER = "// Code generated by scantool render_api_stub. DO NOT EDIT."
+
+_HIDDEN = "// contains filtered or unexported fields"
+
+_STUB_BODY = '{\n\tpanic("stub")\n}'
+
+
+@dataclass
+class ApiStub:
+    directory: str
+    package: Optional[str]
+    source: str = ""
+    imports: list[str] = field(default_factory=list)  # paths, sorted
+    symbols: int = 0      # exported declarations rendered
+    gofmt: bool = False   # source went through gofmt
+
+    def to_dict(self) -> dict:
+        return {"directory": self.directory, "package": self.package,
+                "imports": self.imports, "symbols": self.symbols,
+                "gofmt": self.gofmt, "source": self.source}
+
+
+def _is_exported(name: Optional[str]) -> bool:
+    return bool(name) and name[:1].isupper()
+
+
+def _doc(node, source: bytes) -> str:
+    """The comment group directly above node, as written, with a trailing
+    newline ("" without one). A trailing comment of the previous line is
+    not part of it."""
+    comments = []
+    next_row = node.start_point[0]
+    prev = node.prev_sibling
+    while prev is not None and prev.type == "comment" and prev.end_point[0] >= next_row - 1:
+        before = prev.prev_sibling
+        if before is not None and before.type != "comment" and \
+                before.end_point[0] == prev.start_point[0]:
+            break
+        comments.append(syntax.node_text(prev, source))
+        next_row = prev.start_point[0]
+        prev = before
+    return "".join(f"{c}\n" for c in reversed(comments))
+
+
+def _indented(text: str) -> str:
+    return "\n".join("\t" + line if line else line for line in text.split("\n"))
+
+
+def _element_lines(node, source: bytes, keep) -> list[str]:
+    """Lines of the kept members of a struct field list or interface body
+    (each with its doc comment), the hidden marker if any were left out."""
+    lines, hidden = [], False
+    for member in node.named_children:
+        if member.type == "comment":
+            continue
+        text = keep(member)
+        if text is None:
+            hidden = True
+            continue
+        lines.append(_indented(_doc(member, source) + text))
+    if hidden:
+        lines.append("\t" + _HIDDEN)
+    return lines
+
+
+def _struct(type_node, source: bytes) -> str:
+    fields = next((c for c in type_node.named_children
+                   if c.type == "field_declaration_list"), None)
+    if fields is None:
+        return "struct{}"
+
+    def keep(member) -> Optional[str]:
+        if member.type != "field_declaration":
+            return None
+        names = member.children_by_field_name("name")
+        type_node = member.child_by_field_name("type")
+        if not names:  # embedded: pkg.T and *T are exported when T is
+            embedded = syntax.base_type_name(type_node, source) or ""
+            return syntax.node_text(member, source) if _is_exported(
+                embedded.rsplit(".", 1)[-1]) else None
+        exported = [syntax.node_text(n, source) for n in names
+                    if _is_exported(syntax.node_text(n, source))]
+        if not exported:
+            return None
+        if len(exported) == len(names):
+            return syntax.node_text(member, source)
+        tag = member.child_by_field_name("tag")
+        tag_text = f" {syntax.node_text(tag, source)}" if tag is not None else ""
+        return f"{', '.join(exported)} {syntax.node_text(type_node, source)}{tag_text}"
+
+    lines = _element_lines(fields, source, keep)
+    return "struct {\n" + "\n".join(lines) + "\n}" if lines else "struct{}"
+
+
+def _interface(type_node, source: bytes) -> str:
+    def keep(member) -> Optional[str]:
+        if member.type in ("method_elem", "method_spec"):
+            name = member.child_by_field_name("name")
+            return syntax.node_text(member, source) if name is not None and \
+                _is_exported(syntax.node_text(name, source)) else None
+        text = syntax.node_text(member, source)
+        # An embedded unexported interface is left out; constraints stay
+        simple = re.fullmatch(r"[\w.]+", text)
+        if simple and not _is_exported(text.rsplit(".", 1)[-1]):
+            return None
+        return text
+
+    lines = _element_lines(type_node, source, keep)
+    return "interface {\n" + "\n".join(lines) + "\n}" if lines else "interface{}"
+
+
+def _type(spec, source: bytes) -> str:
+    type_node = spec.child_by_field_name("type")
+    if spec.type == "type_spec" and type_node is not None and \
+            type_node.type in ("struct_type", "interface_type"):
+        head = source[spec.start_byte:type_node.start_byte].decode("utf-8", errors="replace")
+        body = (_struct(type_node, source) if type_node.type == "struct_type"
+                else _interface(type_node, source))
+        return f"type {head}{body}"
+    return f"type {syntax.node_text(spec, source)}"
+
+
+def _function(decl, source: bytes) -> str:
+    name = syntax.node_text(decl.child_by_field_name("name"), source)
+    receiver = decl.child_by_field_name("receiver")
+    receiver_text = f"{syntax.format_parameter_list(receiver, source)} " if receiver else ""
+    return f"func {receiver_text}{syntax.format_header(decl, name, source)} {_STUB_BODY}"
+
+
+def _specs(decl, spec_type: str) -> list:
+    specs = []
+    for child in decl.named_children:
+        if child.type == spec_type:
+            specs.append(child)
+        elif child.type.endswith("_spec_list"):
+            specs.extend(c for c in child.named_children if c.type == spec_type)
+    return specs
+
+
+def _value_declaration(decl, source: bytes) -> Optional[tuple[str, int]]:
+    """(rendered const/var declaration, exported names in it), or None
+    when it declares nothing exported."""
+    keyword = "const" if decl.type == "const_declaration" else "var"
+    specs = _specs(decl, f"{keyword}_spec")
+
+    def exported(spec) -> int:
+        return sum(_is_exported(syntax.node_text(n, source))
+                   for n in spec.children_by_field_name("name"))
+
+    count = sum(exported(spec) for spec in specs)
+    if not count:
+        return None
+    # Some grammar versions wrap grouped specs in a *_spec_list node
+    if not any(c.type == "(" or c.type.endswith("_spec_list") for c in decl.children):
+        return syntax.node_text(decl, source), count
+    implicit = keyword == "const" and any(s.child_by_field_name("value") is None
+                                          for s in specs)
+    kept = specs if implicit else [s for s in specs if exported(s)]
+    body = "\n".join(_indented(_doc(s, source) + syntax.node_text(s, source)) for s in kept)
+    return f"{keyword} (\n{body}\n)", count
+
+
+def _package_doc(files: list[GoFile]) -> str:
+    ordered = sorted(files, key=lambda f: (Path(f.path).name != "doc.go", f.path))
+    for go_file in ordered:
+        clause = next((c for c in go_file.root.children if c.type == "package_clause"), None)
+        doc = _doc(clause, go_file.source) if clause is not None else ""
+        if doc.strip():
+            return doc
+    return ""
+
+
+def _imports(body: str, files: list[GoFile]) -> list[tuple[Optional[str], str]]:
+    """(alias, path) of the package imports the rendered body uses."""
+    source = body.encode("utf-8")
+    root = syntax.parse(source)
+    used = qualifiers(GoFile(path="stub.go", source=source, root=root, package=None))
+    found: dict[str, tuple[Optional[str], str]] = {}
+    for go_file in files:
+        for spec in import_list(go_file):
+            if spec.blank or spec.dot:
+                continue
+            names = {spec.name} if spec.name else default_names(spec.path)
+            for qualifier in names & used:
+                found.setdefault(qualifier, (spec.name, spec.path))
+    return sorted(set(found.values()), key=lambda imp: imp[1])
+
+
+def _gofmt(source: str) -> Optional[str]:
+    """gofmt's rendering of source; None without gofmt or when it fails."""
+    gofmt = gofmt_path()
+    if gofmt is None:
+        return None
+    try:
+        result = subprocess.run([gofmt], input=source.encode("utf-8"),
+                                capture_output=True, timeout=_GOFMT_TIMEOUT)
+    except (OSError, subprocess.TimeoutExpired):
+        return None
+    return result.stdout.decode("utf-8") if result.returncode == 0 else None
+
+
+def render_api_stub(files: list[GoFile], directory: str) -> ApiStub:
+    """The exported API of the package in `directory` (files directly in
+    it, _test.go files never) as stub Go source."""
+    target = str(Path(directory).resolve())
+    package_files = sorted((f for f in files
+                            if f.directory == target and not f.path.endswith("_test.go")),
+                           key=lambda f: f.path)
+    names = [f.package for f in package_files if f.package]
+    stub = ApiStub(directory=target,
+                   package=max(set(names), key=names.count) if names else None)
+    if stub.package is None:
+        return stub
+    package_files = [f for f in package_files if f.package == stub.package]
+
+    consts, variables, functions = [], [], []
+    types: dict[str, str] = {}            # name -> rendered type
+    methods: dict[str, list[str]] = {}    # receiver type -> rendered methods
+    for go_file in package_files:
+        source = go_file.source
+        for decl in go_file.root.children:
+            name_node = decl.child_by_field_name("name")
+            if decl.type == "function_declaration" and name_node is not None:
+                if _is_exported(syntax.node_text(name_node, source)):
+                    functions.append(_doc(decl, source) + _function(decl, source))
+            elif decl.type == "method_declaration" and name_node is not None:
+                receiver_type, _ = syntax.receiver(decl, source)
+                if _is_exported(syntax.node_text(name_node, source)) and \
+                        _is_exported(receiver_type):
+                    methods.setdefault(receiver_type, []).append(
+                        _doc(decl, source) + _function(decl, source))
+            elif decl.type in ("const_declaration", "var_declaration"):
+                rendered = _value_declaration(decl, source)
+                if rendered is not None:
+                    text, count = rendered
+                    (consts if decl.type == "const_declaration" else variables).append(
+                        _doc(decl, source) + text)
+                    stub.symbols += count
+            elif decl.type == "type_declaration":
+                group_doc = _doc(decl, source)
+                specs = [c for c in decl.named_children if c.type in ("type_spec", "type_alias")]
+                for spec in specs:
+                    name = syntax.node_text(spec.child_by_field_name("name"), source)
+                    if _is_exported(name):
+                        doc = _doc(spec, source) or (group_doc if len(specs) == 1 else "")
+                        types[name] = doc + _type(spec, source)
+
+    declarations = consts + variables + functions
+    for name, rendered in types.items():
+        declarations.append(rendered)
+        declarations.extend(methods.get(name, []))
+    stub.symbols += len(functions) + len(types) + sum(
+        len(methods.get(name, [])) for name in types)
+
+    body = "\n\n".join(declarations)
+    imports = _imports(body, package_files)
+    stub.imports = [path for _, path in imports]
+    specs = [f'{alias} "{path}"' if alias else f'"{path}"' for alias, path in imports]
+    parts = [_HEADER, "", f"{_package_doc(package_files)}package {stub.package}"]
+    if len(specs) == 1:
+        parts += ["", f"import {specs[0]}"]
+    elif specs:
+        parts += ["", "import (\n" + "\n".join(f"\t{s}" for s in specs) + "\n)"]
+    if body:
+        parts += ["", body]
+    source = "\n".join(parts) + "\n"
+
+    formatted = _gofmt(source)
+    stub.gofmt = formatted is not None
+    stub.source = formatted if formatted is not None else source
+    return stub
@@ -0,0 +1,157 @@
+"""
+FILE: assertions.py
+
+PROBLEM:
+  `v := x.(*Conn)` panics the moment x holds anything else; `v, ok :=
+  x.(*Conn)` and type switches don't. Finding the risky ones means
+  reading every assertion in the tree — grep for ".(" drowns in calls.
+
+SOLUTION:
+  Walk every type_assertion_expression and type_switch_statement and
+  report, with the enclosing function:
+    - assertions: operand, asserted type, and the form — comma-ok
+      (`v, ok := x.(T)`, `var v, ok = x.(T)`, assignments too) is
+      panic-safe, everything else (single-value use, call argument,
+      return value, chained x.(T).M()) can panic
+    - type switches: operand, the case types in order, whether there is
+      a default case, and the bound variable (`switch v := x.(type)`)
+  Switches never panic; they are listed for the refactoring view of how
+  a value's dynamic type is used.
+
+SCOPE:
+  ✓ Functions, methods, function literals and package-level initializers
+  ✗ No proof of safety: a single-value assertion right after a type
+    check of its own (reflect, a prior switch) is still reported
+"""
+
+from collections import Counter
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_ASSIGNMENTS = ("short_var_declaration", "assignment_statement")
+
+
+@dataclass
+class TypeAssertion:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    kind: str  # assertion, switch
+    operand: str  # the x of x.(T)
+    types: list[str] = field(default_factory=list)  # T, or the case types
+    comma_ok: bool = False  # assertions: v, ok form
+    has_default: bool = False  # switches
+    binding: Optional[str] = None  # switches: v of switch v := x.(type)
+
+    @property
+    def can_panic(self) -> bool:
+        return self.kind == "assertion" and not self.comma_ok
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "line": self.line, "function": self.function,
+                "kind": self.kind, "operand": self.operand, "types": self.types}
+        if self.kind == "assertion":
+            data["comma_ok"] = self.comma_ok
+            data["can_panic"] = self.can_panic
+        else:
+            data["has_default"] = self.has_default
+            if self.binding:
+                data["binding"] = self.binding
+        return data
+
+
+def _is_comma_ok(assertion) -> bool:
+    """assertion is the single value of a two-target assignment or var spec."""
+    values = assertion.parent
+    while values is not None and values.type == "parenthesized_expression":
+        assertion, values = values, values.parent
+    if values is None or values.type != "expression_list" or len(values.named_children) != 1:
+        return False
+    statement = values.parent
+    if statement is None:
+        return False
+    if statement.type in _ASSIGNMENTS:
+        left = statement.child_by_field_name("left")
+        return left is not None and len(left.named_children) == 2
+    if statement.type == "var_spec":
+        return len(statement.children_by_field_name("name")) == 2
+    return False
+
+
+def _switch(node, go_file: GoFile) -> TypeAssertion:
+    source = go_file.source
+    value = node.child_by_field_name("value")
+    alias = node.child_by_field_name("alias")
+    types, has_default = [], False
+    for clause in node.named_children:
+        if clause.type == "type_case":
+            types.extend(syntax.normalized_text(t, source)
+                         for t in clause.children_by_field_name("type"))
+        elif clause.type == "default_case":
+            has_default = True
+    return TypeAssertion(
+        go_file.path, syntax.line_of(node), syntax.enclosing_function(node, source),
+        "switch", syntax.normalized_text(value, source) if value is not None else "",
+        types, has_default=has_default,
+        binding=syntax.normalized_text(alias, source) if alias is not None else None)
+
+
+def _file_assertions(go_file: GoFile) -> list[TypeAssertion]:
+    source = go_file.source
+    found = []
+    for node in syntax.walk(go_file.root):
+        if node.type == "type_switch_statement":
+            found.append(_switch(node, go_file))
+        elif node.type == "type_assertion_expression":
+            operand = node.child_by_field_name("operand")
+            asserted = node.child_by_field_name("type")
+            found.append(TypeAssertion(
+                go_file.path, syntax.line_of(node), syntax.enclosing_function(node, source),
+                "assertion", syntax.normalized_text(operand, source) if operand else "",
+                [syntax.normalized_text(asserted, source)] if asserted else [],
+                comma_ok=_is_comma_ok(node)))
+    return found
+
+
+def find_type_assertions(files: list[GoFile], include_tests: bool = False,
+                         panicking_only: bool = False) -> list[TypeAssertion]:
+    """Type assertions and type switches in file then line order;
+    panicking_only keeps the single-value assertions only."""
+    assertions = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        assertions.extend(a for a in _file_assertions(go_file)
+                          if a.can_panic or not panicking_only)
+    assertions.sort(key=lambda a: (a.file, a.line))
+    return assertions
+
+
+def format_type_assertions(assertions: list[TypeAssertion], scope: str) -> str:
+    """Tally, then per file "@line function: x.(T)" with the form."""
+    if not assertions:
+        return f"No type assertions or type switches found in {scope}"
+
+    counts = Counter("can panic" if a.can_panic else "comma-ok" if a.kind == "assertion"
+                     else "type switch" for a in assertions)
+    tally = ", ".join(f"{counts[label]} {label}" for label in ("can panic", "comma-ok", "type switch")
+                      if counts[label])
+    lines = [f"{len(assertions)} type assertions/switches in {scope} ({tally})"]
+    current_file = None
+    for assertion in assertions:
+        if assertion.file != current_file:
+            current_file = assertion.file
+            lines.append(f"\n{current_file}")
+        prefix = f"- @{assertion.line} {assertion.function}: "
+        if assertion.kind == "switch":
+            cases = [*assertion.types, *(["default"] if assertion.has_default else [])]
+            bound = f"{assertion.binding} := " if assertion.binding else ""
+            lines.append(f"{prefix}switch {bound}{assertion.operand}.(type)  "
+                         f"cases: {', '.join(cases) or '(none)'}")
+        else:
+            form = "comma-ok" if assertion.comma_ok else "[can panic]"
+            lines.append(f"{prefix}{assertion.operand}.({', '.join(assertion.types)})  {form}")
+    return "\n".join(lines)
@@ -0,0 +1,142 @@
+"""
+FILE: buildtags.py
+
+PROBLEM:
+  A file guarded by "//go:build linux" is only part of the package on
+  Linux. Scanned unconditionally, its declarations look always present,
+  and a second file declaring the same function for windows looks like a
+  duplicate.
+
+SOLUTION:
+  Read the build constraint from the file header — the comment lines
+  before the package clause — in both forms: "//go:build expr" and the
+  legacy "// +build a,b c" lines (space = or, comma = and, lines and-ed),
+  which are rewritten as a //go:build expression. A //go:build line wins
+  when both are present, as in the go command. Evaluating an expression
+  against a tag set answers whether the file is part of that build.
+
+SCOPE:
+  ✓ Full //go:build grammar: !, &&, ||, parentheses
+  ✓ "go1.N" release tags count as satisfied (a current toolchain)
+  ✗ File name constraints (_linux.go, _amd64_test.go) — only the comment
+    form; the tag set is taken as given, GOOS/GOARCH are not implied
+"""
+
+import re
+from typing import Iterable, Optional
+
+_GO_BUILD = re.compile(r"^//go:build\s+(.+?)\s*$")
+_PLUS_BUILD = re.compile(r"^//\s*\+build(?:\s+(.*?))?\s*$")
+_TOKEN = re.compile(r"\s*(\(|\)|!|&&|\|\||[A-Za-z0-9_.]+)")
+_RELEASE_TAG = re.compile(r"^go1\.\d+$")
+
+
+def _header_lines(source: bytes) -> Iterable[str]:
+    """Line comments before the first line of code; block comments are
+    skipped over, blank lines ignored."""
+    in_block = False
+    for raw in source.decode("utf-8", errors="replace").splitlines():
+        line = raw.strip()
+        if in_block:
+            in_block = "*/" not in line
+            continue
+        if not line:
+            continue
+        if line.startswith("/*"):
+            in_block = "*/" not in line[2:]
+            continue
+        if not line.startswith("//"):
+            return
+        yield line
+
+
+def _plus_build_expression(lines: list[str]) -> str:
+    """"// +build" line arguments as one //go:build expression."""
+    clauses = []
+    for line in lines:
+        options = [terms for terms in (
+            [term for term in option.split(",") if term] for option in line.split()) if terms]
+        if len(options) == 1:
+            clauses.append(" && ".join(options[0]))
+        elif options:
+            clauses.append(" || ".join(f"({' && '.join(terms)})" if len(terms) > 1 else terms[0]
+                                       for terms in options))
+    if len(clauses) == 1:
+        return clauses[0]
+    return " && ".join(f"({clause})" if "||" in clause else clause for clause in clauses)
+
+
+def find_constraint(source: bytes) -> Optional[str]:
+    """Build constraint of a Go file as a //go:build expression, or None."""
+    plus_build = []
+    for line in _header_lines(source):
+        match = _GO_BUILD.match(line)
+        if match:
+            return match.group(1)
+        match = _PLUS_BUILD.match(line)
+        if match and match.group(1):
+            plus_build.append(match.group(1))
+    return _plus_build_expression(plus_build) if plus_build else None
+
+
+def _tokens(expression: str) -> list[str]:
+    tokens, position = [], 0
+    expression = expression.rstrip()
+    while position < len(expression):
+        match = _TOKEN.match(expression, position)
+        if not match:
+            raise ValueError(f"unexpected {expression[position:].strip()!r} in "
+                             f"build constraint {expression!r}")
+        tokens.append(match.group(1))
+        position = match.end()
+    return tokens
+
+
+def satisfied(expression: str, tags: Iterable[str]) -> bool:
+    """Whether the build constraint holds for the tag set. ValueError when
+    the expression does not parse."""
+    tags = set(tags)
+    tokens = _tokens(expression)
+    position = 0
+
+    def peek() -> Optional[str]:
+        return tokens[position] if position < len(tokens) else None
+
+    def take() -> str:
+        nonlocal position
+        if position >= len(tokens):
+            raise ValueError(f"unexpected end of build constraint {expression!r}")
+        position += 1
+        return tokens[position - 1]
+
+    def either() -> bool:
+        value = both()
+        while peek() == "||":
+            take()
+            value = both() or value
+        return value
+
+    def both() -> bool:
+        value = negated()
+        while peek() == "&&":
+            take()
+            value = negated() and value
+        return value
+
+    def negated() -> bool:
+        token = take()
+        if token == "!":
+            return not negated()
+        if token == "(":
+            value = either()
+            if take() != ")":
+                raise ValueError(f"missing ) in build constraint {expression!r}")
+            return value
+        if token in (")", "&&", "||"):
+            raise ValueError(f"unexpected {token!r} in build constraint {expression!r}")
+        return token in tags or bool(_RELEASE_TAG.match(token))
+
+    value = either()
+    if peek() is not None:
+        raise ValueError(f"unexpected {peek()!r} in build constraint {expression!r}")
+    return value
@@ -0,0 +1,409 @@
+"""
+FILE: calls.py
+
+PROBLEM:
+  "Who calls what" inside a package is answered today by grepping for a
+  name — which can't tell s.Save from repo.Save, and misses calls made
+  through a variable whose type is only visible at its declaration.
+
+SOLUTION:
+  Walk every function and method body for call expressions and record an
+  edge from the enclosing declaration to the callee, one per distinct call
+  expression (all call lines kept). Callees are resolved within the
+  package (= directory + package clause):
+    function    Name(...) naming a package-level function
+    method      x.Name(...) where x's type is known and declares Name —
+                x being the receiver, a parameter, a variable initialised
+                from a composite literal / new(T) / a package function's
+                first result, or a struct field of a known type (s.repo)
+    external    pkg.Name(...) through an import, or a method of a type
+                from another package (pkg.T)
+    unresolved  everything else, kept as written (s.repo.Save when repo
+                is an interface or a func-typed field)
+  Builtins (len, append, ...) and conversions to declared types are not
+  calls and produce no edge.
+
+SCOPE:
+  ✓ Calls inside closures count for the enclosing declaration
+  ✓ Methods promoted through embedded struct fields
+  ✗ No type checking: variables are typed only from the forms above, and
+    one type per name per function (shadowing is not tracked)
+  ✗ Interface method calls can't be resolved to implementations
+  ✗ Cross-package calls are not followed into the other package
+"""
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from tree_sitter import Node
+
+from . import syntax
+from .syntax import GoFile
+
+CALL_KINDS = ("function", "method", "external", "unresolved")
+
+_BUILTINS = frozenset((
+    "append", "cap", "clear", "close", "complex", "copy", "delete", "imag", "len",
+    "make", "max", "min", "new", "panic", "print", "println", "real", "recover",
+    # conversions to predeclared types
+    "any", "bool", "byte", "complex64", "complex128", "error", "float32", "float64",
+    "int", "int8", "int16", "int32", "int64", "rune", "string",
+    "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+))
+_VERSION_ELEMENT = re.compile(r"^v\d+$")
+
+
+@dataclass
+class GoFunction:
+    name: str  # "Func" or "Type.Method"
+    package: Optional[str]
+    file: str
+    start_line: int
+
+
+@dataclass
+class CallEdge:
+    caller: str      # GoFunction.name of the enclosing declaration
+    callee: str      # resolved name ("Type.Method") or the call as written
+    expression: str  # callee expression as written ("s.repo.Save")
+    kind: str        # one of CALL_KINDS
+    file: str
+    lines: list[int] = field(default_factory=list)
+    target_file: Optional[str] = None  # declaration of a resolved callee
+    target_line: Optional[int] = None
+
+
+@dataclass
+class CallGraph:
+    functions: list[GoFunction] = field(default_factory=list)
+    edges: list[CallEdge] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {
+            "functions": [{"name": f.name, "package": f.package, "file": f.file,
+                           "line": f.start_line} for f in self.functions],
+            "edges": [{
+                "from": e.caller, "to": e.callee, "expression": e.expression,
+                "kind": e.kind, "file": e.file, "lines": e.lines,
+                **({"target": {"file": e.target_file, "line": e.target_line}}
+                   if e.target_file else {}),
+            } for e in self.edges],
+        }
+
+
+def import_names(go_file: GoFile) -> set[str]:
+    """Names imported packages are referenced by in this file: the alias,
+    else the last path element (skipping a /vN major-version suffix)."""
+    names = set()
+    for node in syntax.walk(go_file.root):
+        if node.type != "import_spec":
+            continue
+        alias = node.child_by_field_name("name")
+        if alias is not None:
+            if alias.type == "package_identifier":
+                names.add(syntax.node_text(alias, go_file.source))
+            continue  # dot and blank imports add no qualifier
+        path_node = node.child_by_field_name("path")
+        if path_node is None:
+            continue
+        elements = syntax.node_text(path_node, go_file.source).strip('"`').split("/")
+        if len(elements) > 1 and _VERSION_ELEMENT.match(elements[-1]):
+            elements.pop()
+        names.add(re.sub(r"\.v\d+$", "", elements[-1]).replace("-", "_"))
+    return names
+
+
+class Package:
+    """Declarations of one package that call resolution looks things up in."""
+
+    def __init__(self, files: list[GoFile]):
+        self.functions: dict[str, tuple[GoFile, Node]] = {}
+        self.methods: dict[tuple[str, str], tuple[GoFile, Node]] = {}
+        self.types: set[str] = set()
+        self.fields: dict[str, dict[str, Optional[str]]] = {}  # type -> field -> type
+        self.embedded: dict[str, list[str]] = {}
+        self.globals: dict[str, str] = {}
+        for go_file in files:
+            for spec in syntax.type_specs(go_file.root):
+                name_node = spec.child_by_field_name("name")
+                if name_node is None:
+                    continue
+                type_name = syntax.node_text(name_node, go_file.source)
+                self.types.add(type_name)
+                type_node = spec.child_by_field_name("type")
+                if type_node is not None and type_node.type == "struct_type":
+                    self._struct_fields(type_name, type_node, go_file.source)
+            for decl in go_file.root.children:
+                name_node = decl.child_by_field_name("name")
+                if decl.type == "function_declaration" and name_node is not None:
+                    self.functions[syntax.node_text(name_node, go_file.source)] = (go_file, decl)
+                elif decl.type == "method_declaration" and name_node is not None:
+                    receiver_type, _ = syntax.receiver(decl, go_file.source)
+                    if receiver_type is not None:
+                        self.methods[(receiver_type,
+                                      syntax.node_text(name_node, go_file.source))] = (go_file, decl)
+        for go_file in files:
+            for decl in go_file.root.children:
+                if decl.type == "var_declaration":
+                    self.globals.update(_declared_types(decl, go_file.source, self, {}))
+
+    def _struct_fields(self, type_name: str, struct: Node, source: bytes) -> None:
+        fields: dict[str, Optional[str]] = {}
+        embedded = []
+        for node in syntax.walk(struct):
+            if node.type != "field_declaration":
+                continue
+            field_type = syntax.base_type_name(node.child_by_field_name("type"), source)
+            names = node.children_by_field_name("name")
+            if not names:
+                if field_type:
+                    embedded.append(field_type)
+                    fields[field_type.rsplit(".", 1)[-1]] = field_type
+                continue
+            for name in names:
+                fields[syntax.node_text(name, source)] = field_type
+        self.fields[type_name] = fields
+        self.embedded[type_name] = embedded
+
+    def find_method(self, type_name: str, method: str,
+                    seen: Optional[set] = None) -> Optional[tuple[str, GoFile, Node]]:
+        """(owning type, file, declaration) — embedded fields searched too."""
+        found = self.methods.get((type_name, method))
+        if found is not None:
+            return type_name, *found
+        seen = seen or set()
+        seen.add(type_name)
+        for embed in self.embedded.get(type_name, ()):
+            if embed not in seen and "." not in embed:
+                promoted = self.find_method(embed, method, seen)
+                if promoted is not None:
+                    return promoted
+        return None
+
+    def result_type(self, function: str) -> Optional[str]:
+        """Base type of a package function's first result."""
+        entry = self.functions.get(function)
+        if entry is None:
+            return None
+        go_file, decl = entry
+        result = decl.child_by_field_name("result")
+        if result is not None and result.type == "parameter_list":
+            first = next((c for c in result.named_children
+                          if c.type == "parameter_declaration"), None)
+            result = first.child_by_field_name("type") if first is not None else None
+        return syntax.base_type_name(result, go_file.source)
+
+
+def _expression_type(node: Optional[Node], source: bytes, package: Package,
+                     scope: dict[str, str]) -> Optional[str]:
+    """Base type of an expression, for the forms listed in the module doc."""
+    if node is None:
+        return None
+    if node.type == "parenthesized_expression":
+        return _expression_type(next(iter(node.named_children), None), source, package, scope)
+    if node.type == "unary_expression" and node.children and node.children[0].type == "&":
+        return _expression_type(node.child_by_field_name("operand"), source, package, scope)
+    if node.type == "composite_literal":
+        return syntax.base_type_name(node.child_by_field_name("type"), source)
+    if node.type == "identifier":
+        name = syntax.node_text(node, source)
+        return scope.get(name, package.globals.get(name))
+    if node.type == "selector_expression":
+        owner = _expression_type(node.child_by_field_name("operand"), source, package, scope)
+        field_node = node.child_by_field_name("field")
+        if owner is None or field_node is None:
+            return None
+        return package.fields.get(owner, {}).get(syntax.node_text(field_node, source))
+    if node.type == "call_expression":
+        function = node.child_by_field_name("function")
+        if function is None or function.type != "identifier":
+            return None
+        name = syntax.node_text(function, source)
+        if name == "new":
+            arguments = node.child_by_field_name("arguments")
+            first = next(iter(arguments.named_children), None) if arguments else None
+            return syntax.base_type_name(first, source)
+        return package.result_type(name)
+    return None
+
+
+def _declared_types(node: Node, source: bytes, package: Package,
+                    scope: dict[str, str]) -> dict[str, str]:
+    """Variable name -> base type for the var specs and := declarations
+    under node (one flat scope, later declarations win)."""
+    found: dict[str, str] = {}
+    combined = dict(scope)
+    for child in syntax.walk(node):
+        if child.type == "var_spec":
+            names = [syntax.node_text(n, source) for n in child.children_by_field_name("name")]
+            declared = syntax.base_type_name(child.child_by_field_name("type"), source)
+            values = child.child_by_field_name("value")
+            value_nodes = values.named_children if values is not None else []
+        elif child.type == "short_var_declaration":
+            left = child.child_by_field_name("left")
+            right = child.child_by_field_name("right")
+            names = [syntax.node_text(n, source) for n in left.named_children] if left else []
+            declared = None
+            value_nodes = right.named_children if right is not None else []
+        else:
+            continue
+        for i, name in enumerate(names):
+            type_name = declared
+            if type_name is None and i < len(value_nodes) and (
+                    i == 0 or len(value_nodes) == len(names)):
+                type_name = _expression_type(value_nodes[i], source, package, combined)
+            if type_name is not None and name != "_":
+                found[name] = combined[name] = type_name
+    return found
+
+
+def _parameter_scope(decl: Node, source: bytes) -> dict[str, str]:
+    """Receiver and parameter names -> base type."""
+    scope = {}
+    for field_name in ("receiver", "parameters"):
+        params = decl.child_by_field_name(field_name)
+        if params is None:
+            continue
+        for param in params.named_children:
+            if param.type not in ("parameter_declaration", "variadic_parameter_declaration"):
+                continue
+            type_name = syntax.base_type_name(param.child_by_field_name("type"), source)
+            if type_name is None or param.type == "variadic_parameter_declaration":
+                continue
+            for name in param.children_by_field_name("name"):
+                scope[syntax.node_text(name, source)] = type_name
+    return scope
+
+
+def function_scope(decl: Node, source: bytes, package: Package) -> dict[str, str]:
+    """Variable name -> base type inside a function or method: receiver,
+    parameters and the variables declared in its body."""
+    scope = _parameter_scope(decl, source)
+    body = decl.child_by_field_name("body")
+    if body is not None:
+        scope.update(_declared_types(body, source, package, scope))
+    return scope
+
+
+def resolve_call(call: Node, go_file: GoFile, package: Package, scope: dict[str, str],
+             imports: set[str]) -> Optional[tuple[str, str, str, Optional[GoFile], Optional[Node]]]:
+    """(callee, expression, kind, target file, target declaration), or None
+    for builtins and conversions."""
+    source = go_file.source
+    function = call.child_by_field_name("function")
+    if function is None:
+        return None
+    expression = syntax.normalized_text(function, source)
+
+    if function.type == "identifier":
+        if expression in package.functions:
+            return expression, expression, "function", *package.functions[expression]
+        if expression in _BUILTINS or expression in package.types:
+            return None
+        return expression, expression, "unresolved", None, None
+
+    if function.type == "selector_expression":
+        operand = function.child_by_field_name("operand")
+        field_node = function.child_by_field_name("field")
+        method = syntax.node_text(field_node, source) if field_node is not None else ""
+        if (operand is not None and operand.type == "identifier"
+                and syntax.node_text(operand, source) in imports
+                and syntax.node_text(operand, source) not in scope):
+            return expression, expression, "external", None, None
+        owner = _expression_type(operand, source, package, scope)
+        if owner is not None and "." in owner:
+            return f"{owner}.{method}", expression, "external", None, None
+        if owner is not None:
+            found = package.find_method(owner, method)
+            if found is not None:
+                owning_type, target_file, target_decl = found
+                return f"{owning_type}.{method}", expression, "method", target_file, target_decl
+
+    # Function values, interface methods, calls on call results, ...
+    return expression, expression, "unresolved", None, None
+
+
+def build_call_graph(files: list[GoFile], include_tests: bool = False) -> CallGraph:
+    """Intra-package call edges of the given files, per package in file order."""
+    packages: dict[tuple[str, str], list[GoFile]] = {}
+    for go_file in files:
+        if not include_tests and go_file.path.endswith("_test.go"):
+            continue
+        packages.setdefault((go_file.directory, go_file.package or ""), []).append(go_file)
+
+    graph = CallGraph()
+    for package_files in packages.values():
+        package = Package(package_files)
+        for go_file in package_files:
+            imports = import_names(go_file)
+            for decl in go_file.root.children:
+                if decl.type not in ("function_declaration", "method_declaration"):
+                    continue
+                name_node = decl.child_by_field_name("name")
+                body = decl.child_by_field_name("body")
+                if name_node is None:
+                    continue
+                name = syntax.node_text(name_node, go_file.source)
+                if decl.type == "method_declaration":
+                    receiver_type, _ = syntax.receiver(decl, go_file.source)
+                    name = f"{receiver_type}.{name}" if receiver_type else name
+                graph.functions.append(GoFunction(name, go_file.package, go_file.path,
+                                                  syntax.line_of(decl)))
+                if body is None:
+                    continue
+                scope = function_scope(decl, go_file.source, package)
+
+                edges: dict[str, CallEdge] = {}
+                for node in syntax.walk(body):
+                    if node.type != "call_expression":
+                        continue
+                    resolved = resolve_call(node, go_file, package, scope, imports)
+                    if resolved is None:
+                        continue
+                    callee, expression, kind, target_file, target_decl = resolved
+                    edge = edges.get(expression)
+                    if edge is None:
+                        edge = edges[expression] = CallEdge(
+                            caller=name, callee=callee, expression=expression, kind=kind,
+                            file=go_file.path,
+                            target_file=target_file.path if target_file else None,
+                            target_line=syntax.line_of(target_decl) if target_decl else None)
+                    line = syntax.line_of(node)
+                    if line not in edge.lines:
+                        edge.lines.append(line)
+                graph.edges.extend(sorted(edges.values(), key=lambda e: e.lines[0]))
+    return graph
+
+
+def format_call_graph(graph: CallGraph, scope: str) -> str:
+    """Per file: each function with its outgoing calls, resolved ones with
+    the declaration they point to."""
+    if not graph.functions:
+        return f"No Go functions found in {scope}"
+
+    counts = {kind: sum(1 for e in graph.edges if e.kind == kind) for kind in CALL_KINDS}
+    lines = [f"{len(graph.functions)} functions, {len(graph.edges)} call edges in {scope} "
+             f"({', '.join(f'{kind} {counts[kind]}' for kind in CALL_KINDS)})"]
+
+    by_caller: dict[tuple[str, str], list[CallEdge]] = {}
+    for edge in graph.edges:
+        by_caller.setdefault((edge.file, edge.caller), []).append(edge)
+    current_file = None
+    for function in graph.functions:
+        if function.file != current_file:
+            current_file = function.file
+            lines.append(f"\n{current_file}")
+        lines.append(f"- {function.name} @{function.start_line}")
+        # pop: several init() in one file share their edges under the first
+        for edge in by_caller.pop((function.file, function.name), []):
+            if edge.target_file:
+                via = f"{edge.expression} → " if edge.expression != edge.callee else ""
+                lines.append(f"    {via}{edge.callee} "
+                             f"{Path(edge.target_file).name}@{edge.target_line}")
+            else:
+                lines.append(f"    {edge.expression}  [{edge.kind}]")
+    lines.append("note: best-effort and intra-package — variables are typed from "
+                 "declarations only, interface calls are not resolved")
+    return "\n".join(lines)
@@ -0,0 +1,95 @@
+"""
+FILE: comments.py
+
+PROBLEM:
+  Leftover TODO/FIXME/HACK markers are what a pre-release sweep looks for,
+  but grep also hits "TODO" inside string literals and identifiers, and
+  loses the author in "TODO(marius): ..." to ad-hoc parsing.
+
+SOLUTION:
+  Walk the comment nodes of the syntax tree only — string literals are
+  separate nodes, so they can never match. Each comment line starting with
+  a tag becomes a marker with file, line, tag, optional (author) and the
+  text after the tag.
+
+SCOPE:
+  ✓ Line and block comments; every line of a block comment is checked
+  ✓ Custom tags, matched case-sensitively as whole words
+  ✗ A tag in the middle of a sentence ("see the TODO list") is not a marker
+"""
+
+import re
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+DEFAULT_TAGS = ("TODO", "FIXME", "HACK")
+
+
+@dataclass
+class CommentMarker:
+    file: str
+    line: int
+    tag: str
+    text: str                     # comment text after the tag (and author, colon)
+    author: Optional[str] = None  # "marius" in "TODO(marius): ..."
+
+
+def _marker_pattern(tags: tuple[str, ...]) -> re.Pattern:
+    alternatives = "|".join(re.escape(tag) for tag in sorted(tags, key=len, reverse=True))
+    return re.compile(rf"^(?P<tag>{alternatives})\b(?:\((?P<author>[^)]*)\))?:?\s*(?P<text>.*)$")
+
+
+def _comment_lines(text: str) -> list[str]:
+    """Comment body lines with markers stripped: "// x" → ["x"], block
+    comments one entry per line with leading "*" decoration removed."""
+    if text.startswith("//"):
+        return [text[2:].strip()]
+    body = text[2:-2] if text.endswith("*/") else text[2:]
+    return [line.strip().lstrip("*").strip() for line in body.split("\n")]
+
+
+def scan_comments(files: list[GoFile],
+                  tags: Optional[list[str]] = None) -> list[CommentMarker]:
+    """Tagged comments of the given files, in file then line order."""
+    pattern = _marker_pattern(tuple(tags) if tags else DEFAULT_TAGS)
+    markers = []
+    for go_file in files:
+        for node in syntax.walk(go_file.root):
+            if node.type != "comment":
+                continue
+            for offset, line in enumerate(_comment_lines(syntax.node_text(node, go_file.source))):
+                match = pattern.match(line)
+                if match is None:
+                    continue
+                markers.append(CommentMarker(
+                    file=go_file.path,
+                    line=syntax.line_of(node) + offset,
+                    tag=match.group("tag"),
+                    text=match.group("text").strip(),
+                    author=(match.group("author") or "").strip() or None,
+                ))
+    markers.sort(key=lambda m: (m.file, m.line))
+    return markers
+
+
+def format_comments(markers: list[CommentMarker], scope: str) -> str:
+    """Per file: "@line TAG(author): text" lines, with a per-tag tally."""
+    if not markers:
+        return f"No tagged comments found in {scope}"
+
+    counts: dict[str, int] = {}
+    for marker in markers:
+        counts[marker.tag] = counts.get(marker.tag, 0) + 1
+    tally = ", ".join(f"{tag} {count}" for tag, count in sorted(counts.items()))
+    lines = [f"{len(markers)} tagged comments in {scope} ({tally})"]
+    current_file = None
+    for marker in markers:
+        if marker.file != current_file:
+            current_file = marker.file
+            lines.append(f"\n{current_file}")
+        author = f"({marker.author})" if marker.author else ""
+        lines.append(f"- @{marker.line} {marker.tag}{author}: {marker.text}")
+    return "\n".join(lines)
@@ -0,0 +1,189 @@
+"""
+FILE: concurrency.py
+
+PROBLEM:
+  A concurrency review starts from "where are goroutines started, and
+  which channels connect them?" The answer is spread over `go` statements,
+  `make(chan T, n)` calls and `<-` on either side of a channel, usually
+  inside closures — grep for "go " or "<-" is noisy, and for
+  `go func() {...}()` it can't say which function did the launching.
+
+SOLUTION:
+  Walk the syntax tree for four kinds of operation:
+    go       go_statement — target is the function called, "func literal"
+             for go func() {...}()
+    make     make(chan T) / make(chan T, n) — target is the channel type;
+             buffered when n is an integer literal other than 0, unbuffered
+             without n or with 0, unknown for any other capacity expression
+    send     ch <- v
+    receive  <-ch, as a value, a statement or a select case
+  Each one carries file, line and enclosing function. A go statement is
+  attributed to the function that runs it, while what happens inside the
+  launched literal reads "func literal in F". Sends and receives that are
+  select cases are marked as such.
+
+SCOPE:
+  ✓ Functions, methods, closures and package-level initializers
+  ✗ `for v := range ch` and close(ch) are not listed: without types, a
+    range over a channel can't be told from one over a slice
+  ✗ A capacity given by a named constant counts as unknown
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+OPERATION_KINDS = ("go", "make", "send", "receive")
+
+_EXPRESSION_LIMIT = 60
+
+
+@dataclass
+class ConcurrencyOp:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    package: Optional[str]
+    kind: str        # one of OPERATION_KINDS
+    target: str      # go: callee; make: channel type; send/receive: channel
+    expression: str  # the operation as written, on one line
+    buffered: Optional[bool] = None  # make: None when the capacity isn't a literal
+    capacity: Optional[str] = None   # make: capacity argument as written
+    select: bool = False  # send/receive: a case of a select statement
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "line": self.line, "function": self.function,
+                "package": self.package, "kind": self.kind, "target": self.target,
+                "expression": self.expression}
+        if self.kind == "make":
+            data["buffered"] = self.buffered
+            data["capacity"] = self.capacity
+        elif self.kind in ("send", "receive"):
+            data["select"] = self.select
+        return data
+
+
+def _int_literal(text: str) -> int:
+    digits = text.replace("_", "")
+    try:
+        return int(digits, 0)
+    except ValueError:  # legacy octal: 010
+        return int(digits, 8)
+
+
+def _channel_make(call, source: bytes) -> Optional[tuple[str, Optional[bool], Optional[str]]]:
+    """(channel type, buffered, capacity) of make(chan T[, n]); None for
+    any other call."""
+    function = call.child_by_field_name("function")
+    arguments = call.child_by_field_name("arguments")
+    if function is None or arguments is None or \
+            syntax.node_text(function, source) != "make":
+        return None
+    values = [a for a in arguments.named_children if a.type != "comment"]
+    if not values or values[0].type != "channel_type":
+        return None
+    channel_type = syntax.normalized_text(values[0], source)
+    if len(values) < 2:
+        return channel_type, False, None
+    capacity = values[1]
+    text = syntax.node_text(capacity, source)
+    if capacity.type != "int_literal":
+        return channel_type, None, text
+    return channel_type, _int_literal(text) != 0, text
+
+
+def _go_target(statement, source: bytes) -> str:
+    call = next((c for c in statement.named_children if c.type != "comment"), None)
+    function = call.child_by_field_name("function") if call is not None and \
+        call.type == "call_expression" else None
+    if function is None:
+        return syntax.normalized_text(call, source) if call is not None else ""
+    if function.type == "func_literal":
+        return "func literal"
+    return syntax.normalized_text(function, source)
+
+
+def _in_select(node) -> bool:
+    """node is the communication of a select case (ch <- v, <-ch, v := <-ch)."""
+    parent = node.parent
+    if parent is not None and parent.type == "receive_statement":
+        node, parent = parent, parent.parent
+    return parent is not None and parent.type == "communication_case" and \
+        parent.child_by_field_name("communication") == node
+
+
+def _expression(node, source: bytes) -> str:
+    text = syntax.normalized_text(node, source)
+    return text if len(text) <= _EXPRESSION_LIMIT else text[:_EXPRESSION_LIMIT] + "…"
+
+
+def find_concurrency(files: list[GoFile], include_tests: bool = False,
+                     kinds: Optional[list[str]] = None) -> list[ConcurrencyOp]:
+    """go statements, channel makes, sends and receives in file then line
+    order; kinds limits them to some of OPERATION_KINDS."""
+    wanted = set(OPERATION_KINDS if kinds is None else kinds)
+    unknown = wanted - set(OPERATION_KINDS)
+    if unknown:
+        raise ValueError(f"Unknown kinds {sorted(unknown)}; expected some of "
+                         f"{', '.join(OPERATION_KINDS)}")
+    found = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        source = go_file.source
+
+        def add(node, kind: str, target: str, **extra):
+            found.append(ConcurrencyOp(
+                go_file.path, syntax.line_of(node),
+                syntax.enclosing_function(node, source), go_file.package,
+                kind, target, _expression(node, source), **extra))
+
+        for node in syntax.walk(go_file.root):
+            if node.type == "go_statement" and "go" in wanted:
+                add(node, "go", _go_target(node, source))
+            elif node.type == "call_expression" and "make" in wanted:
+                made = _channel_make(node, source)
+                if made is not None:
+                    add(node, "make", made[0], buffered=made[1], capacity=made[2])
+            elif node.type == "send_statement" and "send" in wanted:
+                channel = node.child_by_field_name("channel")
+                add(node, "send", syntax.normalized_text(channel, source) if channel else "",
+                    select=_in_select(node))
+            elif node.type == "unary_expression" and "receive" in wanted:
+                operator = node.child_by_field_name("operator")
+                if operator is None or operator.type != "<-":
+                    continue
+                operand = node.child_by_field_name("operand")
+                add(node, "receive", syntax.normalized_text(operand, source) if operand else "",
+                    select=_in_select(node))
+    found.sort(key=lambda op: (op.file, op.line))
+    return found
+
+
+def format_concurrency(ops: list[ConcurrencyOp], scope: str) -> str:
+    """Tally, then per file "@line function: expression" with the kind."""
+    if not ops:
+        return f"No goroutine launches or channel operations found in {scope}"
+
+    counts = {kind: sum(1 for op in ops if op.kind == kind) for kind in OPERATION_KINDS}
+    buffered = sum(1 for op in ops if op.kind == "make" and op.buffered)
+    unbuffered = sum(1 for op in ops if op.kind == "make" and op.buffered is False)
+    lines = [f"{len(ops)} concurrency operations in {scope} ({counts['go']} go, "
+             f"{counts['make']} make ({buffered} buffered, {unbuffered} unbuffered), "
+             f"{counts['send']} send, {counts['receive']} receive)"]
+    current_file = None
+    for op in ops:
+        if op.file != current_file:
+            current_file = op.file
+            lines.append(f"\n{current_file} (package {op.package or '?'})")
+        if op.kind == "make":
+            note = "buffered" if op.buffered else "unbuffered" if op.buffered is False \
+                else f"capacity {op.capacity}, unknown"
+        elif op.kind == "go":
+            note = f"go {op.target}"
+        else:
+            note = f"{op.kind} on {op.target}" + (", select case" if op.select else "")
+        lines.append(f"- @{op.line} {op.function}: {op.expression}  [{note}]")
+    return "\n".join(lines)
@@ -0,0 +1,378 @@
+"""
+FILE: constants.py
+
+PROBLEM:
+  Config knobs and enums live in const blocks, and the values are the
+  point — but an iota enum says "Sunday Weekday = iota; Monday; Tuesday",
+  so reading Tuesday's value means counting specs and replaying the
+  repeated expression by hand.
+
+SOLUTION:
+  Walk the package-level const declarations, track iota (the spec index
+  within its block) and Go's implicit repetition (a spec without "= ..."
+  reuses the previous expression list and type), and evaluate each
+  expression with a small constant folder: basic literals, iota, other
+  constants of the package (any file, any order), unary/binary operators
+  with Go's integer semantics, conversions T(x), and len/min/max. Anything
+  else gets no value and a note saying what stopped the evaluation —
+  never a guess.
+
+SCOPE:
+  ✓ Typed constants report their type (declared, inherited from the
+    repeated spec, or from a conversion / typed operand)
+  ✓ Integer, float, string, rune and bool constants
+  ✗ Constants from other packages (time.Second) and complex numbers are
+    not evaluated; no overflow checks against the type's size
+  ✗ Function-local const declarations are not listed
+"""
+
+import json
+import re
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .literals import decode_interpreted
+from .syntax import GoFile
+
+_BUILTIN_TYPES = {
+    "bool", "string", "byte", "rune", "uintptr", "float32", "float64",
+    "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+}
+_INTEGER_TYPES = {"byte", "rune", "uintptr", "int", "int8", "int16", "int32", "int64",
+                  "uint", "uint8", "uint16", "uint32", "uint64"}
+
+_LEGACY_OCTAL = re.compile(r"^0[0-7]+$")
+_RUNE_ESCAPES = {"a": 7, "b": 8, "f": 12, "n": 10, "r": 13, "t": 9, "v": 11,
+                 "\\": 92, "'": 39, '"': 34}
+_MAX_SHIFT = 4096
+
+
+@dataclass
+class Constant:
+    name: str
+    file: str
+    line: int
+    type: Optional[str] = None   # declared or inferred type, None if untyped
+    value: Optional[str] = None  # Go literal rendering; None when not evaluated
+    expression: Optional[str] = None  # as written (the repeated one for implicit specs)
+    iota: Optional[int] = None   # spec index, for specs that use iota
+    note: Optional[str] = None   # why value is None
+
+    def to_dict(self) -> dict:
+        data = {"name": self.name, "file": self.file, "line": self.line,
+                "type": self.type, "value": self.value, "expression": self.expression}
+        if self.iota is not None:
+            data["iota"] = self.iota
+        if self.note:
+            data["note"] = self.note
+        return data
+
+
+@dataclass
+class ConstBlock:
+    file: str
+    line: int
+    grouped: bool  # const ( ... ) rather than a single const
+    constants: list[Constant] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "grouped": self.grouped,
+                "constants": [c.to_dict() for c in self.constants]}
+
+
+class _NotConstant(Exception):
+    """Expression the folder can't evaluate; the message is the note."""
+
+
+@dataclass
+class _Spec:
+    name: str
+    expression: Optional[object]  # expression node, None if the value list is short
+    type_text: Optional[str]
+    iota: int
+    source: bytes
+    constant: Constant
+
+
+def _parse_int(text: str) -> int:
+    text = text.replace("_", "")
+    if _LEGACY_OCTAL.match(text):
+        return int(text, 8)
+    return int(text, 0)
+
+
+def _parse_float(text: str) -> float:
+    text = text.replace("_", "")
+    return float.fromhex(text) if text.lower().startswith("0x") else float(text)
+
+
+def _parse_rune(text: str) -> int:
+    body = text[1:-1]
+    if not body.startswith("\\"):
+        return ord(body)
+    kind = body[1]
+    if kind in _RUNE_ESCAPES:
+        return _RUNE_ESCAPES[kind]
+    if kind in "xuU":
+        return int(body[2:], 16)
+    return int(body[1:], 8)
+
+
+def _divide(a, b, op: str):
+    if b == 0:
+        raise _NotConstant("division by zero")
+    if isinstance(a, int) and isinstance(b, int):
+        quotient = abs(a) // abs(b) * (1 if (a >= 0) == (b >= 0) else -1)  # truncated, like Go
+        return quotient if op == "/" else a - b * quotient
+    if op == "%":
+        raise _NotConstant("% on floats")
+    return a / b
+
+
+def _binary(op: str, a, b):
+    if op in ("&&", "||"):
+        if not (isinstance(a, bool) and isinstance(b, bool)):
+            raise _NotConstant(f"{op} on non-bool operands")
+        return (a and b) if op == "&&" else (a or b)
+    comparisons = {"==": lambda: a == b, "!=": lambda: a != b, "<": lambda: a < b,
+                   "<=": lambda: a <= b, ">": lambda: a > b, ">=": lambda: a >= b}
+    if op in comparisons:
+        return comparisons[op]()
+    if isinstance(a, bool) or isinstance(b, bool):
+        raise _NotConstant(f"{op} on bool operands")
+    if isinstance(a, str) or isinstance(b, str):
+        if op == "+" and isinstance(a, str) and isinstance(b, str):
+            return a + b
+        raise _NotConstant(f"{op} on string operands")
+    if op in ("/", "%"):
+        return _divide(a, b, op)
+    if op in ("+", "-", "*"):
+        return a + b if op == "+" else a - b if op == "-" else a * b
+    if not (isinstance(a, int) and isinstance(b, int)):
+        raise _NotConstant(f"{op} on non-integer operands")
+    if op in ("<<", ">>"):
+        if b < 0 or b > _MAX_SHIFT:
+            raise _NotConstant(f"shift count {b}")
+        return a << b if op == "<<" else a >> b
+    bitwise = {"&": a & b, "|": a | b, "^": a ^ b, "&^": a & ~b}
+    if op in bitwise:
+        return bitwise[op]
+    raise _NotConstant(f"operator {op}")
+
+
+def render(value) -> str:
+    """Go literal spelling of a folded value."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, str):
+        return json.dumps(value, ensure_ascii=False)
+    return repr(value)
+
+
+class _Folder:
+    """Lazy constant folding over one package's specs, cycle-safe."""
+
+    def __init__(self, specs: dict[str, _Spec], types: set[str]):
+        self.specs = specs
+        self.types = types  # type names declared in the package
+        self.done: dict[str, tuple] = {}  # name -> (value, type)
+        self.active: set[str] = set()
+
+    def constant(self, name: str) -> tuple:
+        if name in self.done:
+            return self.done[name]
+        spec = self.specs.get(name)
+        if spec is None:
+            raise _NotConstant(f"refers to {name}, not a constant of this package")
+        if name in self.active:
+            raise _NotConstant(f"{name} is defined in terms of itself")
+        if spec.expression is None:
+            raise _NotConstant("no value in the expression list")
+        self.active.add(name)
+        try:
+            value, value_type = self.fold(spec.expression, spec.iota, spec.source)
+        finally:
+            self.active.discard(name)
+        if spec.type_text:
+            value, value_type = self.convert(spec.type_text, value), spec.type_text
+        self.done[name] = (value, value_type)
+        return self.done[name]
+
+    def convert(self, type_name: str, value):
+        if type_name in _INTEGER_TYPES and isinstance(value, float):
+            if not value.is_integer():
+                raise _NotConstant(f"{value!r} truncated by {type_name}")
+            return int(value)
+        if type_name in ("float32", "float64") and isinstance(value, int) \
+                and not isinstance(value, bool):
+            return float(value)
+        return value
+
+    def fold(self, node, iota: int, source: bytes) -> tuple:
+        """(value, type) of a constant expression; type None = untyped."""
+        kind = node.type
+        text = syntax.node_text(node, source)
+        if kind == "int_literal":
+            return _parse_int(text), None
+        if kind == "float_literal":
+            return _parse_float(text), None
+        if kind == "rune_literal":
+            return _parse_rune(text), None
+        if kind == "interpreted_string_literal":
+            return decode_interpreted(source[node.start_byte + 1:node.end_byte - 1]), None
+        if kind == "raw_string_literal":
+            return text[1:-1].replace("\r", ""), None
+        if kind in ("true", "false"):
+            return kind == "true", None
+        if kind == "iota" or (kind == "identifier" and text == "iota"):
+            return iota, None
+        if kind == "identifier":
+            return self.constant(text)
+        if kind == "parenthesized_expression":
+            return self.fold(node.named_children[0], iota, source)
+        if kind == "unary_expression":
+            operand = node.child_by_field_name("operand")
+            op = syntax.node_text(node.child_by_field_name("operator"), source)
+            value, value_type = self.fold(operand, iota, source)
+            if op == "!" and isinstance(value, bool):
+                return not value, value_type
+            if isinstance(value, (bool, str)):
+                raise _NotConstant(f"unary {op} on {render(value)}")
+            if op in ("-", "+"):
+                return (-value if op == "-" else value), value_type
+            if op == "^" and isinstance(value, int):
+                return ~value, value_type
+            raise _NotConstant(f"unary {op}")
+        if kind == "binary_expression":
+            op = syntax.node_text(node.child_by_field_name("operator"), source)
+            left, left_type = self.fold(node.child_by_field_name("left"), iota, source)
+            right, right_type = self.fold(node.child_by_field_name("right"), iota, source)
+            value = _binary(op, left, right)
+            if isinstance(value, bool):
+                return value, None
+            if op in ("<<", ">>"):
+                return value, left_type
+            return value, left_type or right_type
+        if kind == "call_expression":
+            return self.call(node, iota, source)
+        if kind == "selector_expression":
+            raise _NotConstant(f"refers to {text}")
+        raise _NotConstant(f"unsupported expression {kind}")
+
+    def call(self, node, iota: int, source: bytes) -> tuple:
+        """Conversions T(x) and the len/min/max builtins."""
+        function = node.child_by_field_name("function")
+        name = syntax.node_text(function, source)
+        arguments = node.child_by_field_name("arguments")
+        args = [a for a in (arguments.named_children if arguments else []) if a.type != "comment"]
+        if name in ("len", "min", "max"):
+            values = [self.fold(a, iota, source) for a in args]
+            if name == "len":
+                if len(values) == 1 and isinstance(values[0][0], str):
+                    return len(values[0][0].encode("utf-8")), None
+                raise _NotConstant("len of a non-constant")
+            if not values:
+                raise _NotConstant(f"{name}() without arguments")
+            pick = min if name == "min" else max
+            return pick(values, key=lambda v: v[0])[0], next((t for _, t in values if t), None)
+        is_type = (name in _BUILTIN_TYPES or name in self.types
+                   or (function.type == "selector_expression" and not name.startswith("unsafe.")))
+        if is_type and len(args) == 1:
+            value, _ = self.fold(args[0], iota, source)
+            return self.convert(name, value), name
+        raise _NotConstant(f"call to {name}")
+
+
+def _collect(go_file: GoFile, blocks: list[ConstBlock], specs: list[_Spec]):
+    source = go_file.source
+    for decl in go_file.root.children:
+        if decl.type != "const_declaration":
+            continue
+        const_specs = [c for c in decl.named_children if c.type == "const_spec"]
+        block = ConstBlock(go_file.path, syntax.line_of(decl),
+                           grouped=any(c.type == "(" for c in decl.children))
+        previous_values, previous_type = [], None
+        for iota, spec in enumerate(const_specs):
+            value_list = spec.child_by_field_name("value")
+            type_node = spec.child_by_field_name("type")
+            if value_list is not None:
+                previous_values = [v for v in value_list.named_children if v.type != "comment"]
+                previous_type = syntax.normalized_text(type_node, source) if type_node else None
+            for index, name_node in enumerate(spec.children_by_field_name("name")):
+                name = syntax.node_text(name_node, source)
+                expression = previous_values[index] if index < len(previous_values) else None
+                uses_iota = expression is not None and any(
+                    n.type == "iota" or (n.type == "identifier"
+                                         and syntax.node_text(n, source) == "iota")
+                    for n in syntax.walk(expression))
+                constant = Constant(
+                    name=name, file=go_file.path, line=syntax.line_of(spec),
+                    type=previous_type,
+                    expression=syntax.normalized_text(expression, source) if expression else None,
+                    iota=iota if uses_iota else None,
+                )
+                if name != "_":
+                    block.constants.append(constant)
+                    specs.append(_Spec(name, expression, previous_type, iota, source, constant))
+        blocks.append(block)
+
+
+def list_constants(files: list[GoFile], include_tests: bool = False) -> list[ConstBlock]:
+    """Package-level const blocks of the given files with folded values,
+    in file then line order."""
+    packages: dict[str, list[GoFile]] = {}
+    for go_file in files:
+        if include_tests or not go_file.path.endswith("_test.go"):
+            packages.setdefault(go_file.directory, []).append(go_file)
+
+    all_blocks = []
+    for package_files in packages.values():
+        blocks: list[ConstBlock] = []
+        specs: list[_Spec] = []
+        types = set()
+        for go_file in package_files:
+            _collect(go_file, blocks, specs)
+            types.update(syntax.node_text(s.child_by_field_name("name"), go_file.source)
+                         for s in syntax.type_specs(go_file.root))
+        # A name declared twice (per-platform files) folds to the last one
+        folder = _Folder({spec.name: spec for spec in specs}, types)
+        for spec in specs:
+            try:
+                value, value_type = folder.constant(spec.name)
+                spec.constant.value = render(value)
+                spec.constant.type = spec.constant.type or value_type
+            except _NotConstant as e:
+                spec.constant.note = str(e)
+            except (ValueError, OverflowError) as e:
+                spec.constant.note = f"not evaluated: {e}"
+        all_blocks.extend(b for b in blocks if b.constants)
+    all_blocks.sort(key=lambda b: (b.file, b.line))
+    return all_blocks
+
+
+def format_constants(blocks: list[ConstBlock], scope: str) -> str:
+    """Per file: grouped blocks with one "Name Type = value" line per
+    constant, iota index when used, and the note for unevaluated ones."""
+    if not blocks:
+        return f"No constants found in {scope}"
+
+    constants = [c for b in blocks for c in b.constants]
+    evaluated = sum(1 for c in constants if c.value is not None)
+    lines = [f"{len(constants)} constants in {scope} ({evaluated} evaluated)"]
+    current_file = None
+    for block in blocks:
+        if block.file != current_file:
+            current_file = block.file
+            lines.append(f"\n{current_file}")
+        if block.grouped:
+            lines.append(f"- const ( @{block.line}")
+        for constant in block.constants:
+            typed = f" {constant.type}" if constant.type else ""
+            value = constant.value if constant.value is not None else f"? ({constant.note})"
+            iota = f"  [iota {constant.iota}]" if constant.iota is not None else ""
+            prefix = "    " if block.grouped else f"- @{constant.line} "
+            lines.append(f"{prefix}{constant.name}{typed} = {value}{iota}")
+        if block.grouped:
+            lines.append("  )")
+    return "\n".join(lines)
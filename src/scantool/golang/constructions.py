@@ -0,0 +1,272 @@
+"""
+FILE: constructions.py
+
+PROBLEM:
+  "How is this type built?" — the answer is its composite literals, spread
+  over every package that constructs one: `User{Name: n}`, `&User{...}`,
+  `models.User{}` from another package, and the element literals of
+  `[]User{{...}}` that don't repeat the type at all. Which fields callers
+  set, and which they habitually leave at the zero value, is only visible
+  with all of them side by side.
+
+SOLUTION:
+  Walk every composite_literal and keep the ones whose type (through
+  pointers and type arguments) is the requested one:
+    "User"         unqualified User{} in any package, and x.User{} for
+                   any qualifier
+    "models.User"  models.User{} where the qualifier is (or aliases) an
+                   import named models, and User{} inside package models
+  Element literals with an elided type count when the outer literal is a
+  slice, array or map (values) of the type. For each literal: the fields
+  set by key, or — positional literals — by their order in the struct
+  declaration when one of the scanned packages declares the type; the
+  declared fields it leaves out; whether it is &T{...}.
+
+SCOPE:
+  ✓ Any package of the scanned tree, closures and package-level vars too
+  ✗ Type aliases and a local type shadowing the name are not followed;
+    constructors (NewUser) are calls, not literals, and not listed
+  ✗ Omitted fields need exactly one declaration in the scanned files; for
+    a type from another module, or a bare T that several packages
+    declare, only the fields set are known
+"""
+
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .calls import Package
+from .imports import default_names, import_paths
+from .syntax import GoFile
+
+_ELIDING_TYPES = ("slice_type", "array_type", "implicit_length_array_type")
+
+
+@dataclass
+class Construction:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    package: Optional[str]
+    type: str      # as written: "User", "models.User"; "" for an elided element
+    pointer: bool = False  # &T{...}
+    fields: list[str] = field(default_factory=list)  # set, in literal order
+    positional: bool = False  # values by position, not by field name
+    omitted: Optional[list[str]] = None  # declared fields not set; None = declaration unknown
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "function": self.function,
+                "package": self.package, "type": self.type, "pointer": self.pointer,
+                "fields": self.fields, "positional": self.positional,
+                "omitted": self.omitted}
+
+
+@dataclass
+class ConstructionReport:
+    type_name: str
+    declared: Optional[list[str]] = None  # fields of the declaration, in order
+    declaration: Optional[tuple[str, int]] = None  # (file, line)
+    candidates: int = 0  # packages declaring a struct of that name
+    constructions: list[Construction] = field(default_factory=list)
+
+    def field_counts(self) -> dict[str, int]:
+        """Field -> literals setting it; declared fields first, in order."""
+        counts = {name: 0 for name in self.declared or []}
+        for construction in self.constructions:
+            for name in construction.fields:
+                counts[name] = counts.get(name, 0) + 1
+        return counts
+
+    def to_dict(self) -> dict:
+        declaration = None
+        if self.declaration is not None:
+            declaration = {"file": self.declaration[0], "line": self.declaration[1]}
+        return {"type": self.type_name, "declaration": declaration,
+                "declaring_packages": self.candidates, "declared_fields": self.declared, "field_counts": self.field_counts(),
+                "constructions": [c.to_dict() for c in self.constructions]}
+
+
+def _split(type_name: str) -> tuple[Optional[str], str]:
+    package, _, name = type_name.rpartition(".")
+    return package or None, name
+
+
+def _declarations(files: list[GoFile], package: Optional[str],
+                  name: str) -> list[tuple[list[str], tuple[str, int]]]:
+    """(fields, (file, line)) of each struct declaring the type, one per
+    package — in packages named package, or in any package when None."""
+    groups: dict[tuple[str, str], list[GoFile]] = {}
+    for go_file in files:
+        if package is None or go_file.package == package:
+            groups.setdefault((go_file.directory, go_file.package or ""), []).append(go_file)
+    found = []
+    for key in sorted(groups):
+        spec = next(((go_file, spec) for go_file in groups[key]
+                     for spec in syntax.type_specs(go_file.root)
+                     if _declares(spec, go_file.source, name)), None)
+        if spec is not None:
+            fields = Package(groups[key]).fields.get(name, {})
+            found.append((list(fields), (spec[0].path, syntax.line_of(spec[1]))))
+    return found
+
+
+def _declares(spec, source: bytes, name: str) -> bool:
+    name_node = spec.child_by_field_name("name")
+    type_node = spec.child_by_field_name("type")
+    return name_node is not None and syntax.node_text(name_node, source) == name \
+        and type_node is not None and type_node.type == "struct_type"
+
+
+def _matches(type_node, go_file: GoFile, package: Optional[str], name: str,
+             paths: dict[str, str]) -> Optional[str]:
+    """The literal's type as written when it names the wanted type, else None."""
+    written = syntax.base_type_name(type_node, go_file.source)
+    if written is None:
+        return None
+    qualifier, _, base = written.rpartition(".")
+    if base != name:
+        return None
+    if package is None:
+        return written
+    if not qualifier:
+        return written if go_file.package == package else None
+    path = paths.get(qualifier)
+    if qualifier == package or (path is not None and package in default_names(path)):
+        return written
+    return None
+
+
+def _element_type(type_node):
+    """Element (or map value) type of a slice, array or map type; None otherwise."""
+    if type_node is None:
+        return None
+    if type_node.type in _ELIDING_TYPES:
+        return type_node.child_by_field_name("element")
+    if type_node.type == "map_type":
+        return type_node.child_by_field_name("value")
+    return None
+
+
+def _literal_fields(body, source: bytes) -> tuple[list[str], int]:
+    """(keys set by name, number of positional values) of a literal_value."""
+    keys, positional = [], 0
+    for element in body.named_children:
+        if element.type == "keyed_element":
+            key = element.child_by_field_name("key")
+            if key is None:
+                key = next(iter(element.named_children), None)
+            if key is not None:
+                keys.append(syntax.node_text(key, source).strip())
+        elif element.type != "comment":
+            positional += 1
+    return keys, positional
+
+
+def _values(body):
+    """The value nodes of a literal_value's elements."""
+    for element in body.named_children:
+        if element.type == "keyed_element":
+            value = element.child_by_field_name("value")
+            if value is None and len(element.named_children) > 1:
+                value = element.named_children[-1]
+            if value is not None:
+                yield value
+        elif element.type != "comment":
+            yield element
+
+
+def find_constructions(files: list[GoFile], type_name: str,
+                       include_tests: bool = False) -> ConstructionReport:
+    """Composite literals of type_name ("T" or "pkg.T") in file then line
+    order, with the fields each one sets and omits."""
+    package, name = _split(type_name)
+    if not name.isidentifier() or (package is not None and not package.isidentifier()):
+        raise ValueError(f"type_name must be T or pkg.T, got {type_name!r}")
+    report = ConstructionReport(type_name)
+    declarations = _declarations(files, package, name)
+    report.candidates = len(declarations)
+    if len(declarations) == 1:  # several: which one a literal builds is unknown
+        report.declared, report.declaration = declarations[0]
+
+    def record(go_file: GoFile, node, body, written: str, pointer: bool):
+        keys, positional = _literal_fields(body, go_file.source)
+        construction = Construction(
+            go_file.path, syntax.line_of(node),
+            syntax.enclosing_function(node, go_file.source), go_file.package,
+            written, pointer=pointer, fields=keys, positional=positional > 0)
+        if positional and report.declared is not None:
+            construction.fields = report.declared[:positional]
+        if report.declared is not None:
+            construction.omitted = [f for f in report.declared if f not in construction.fields]
+        report.constructions.append(construction)
+
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        paths = import_paths(go_file)
+        for node in syntax.walk(go_file.root):
+            if node.type != "composite_literal":
+                continue
+            type_node = node.child_by_field_name("type")
+            body = node.child_by_field_name("body")
+            if body is None:
+                continue
+            parent = node.parent
+            pointer = parent is not None and parent.type == "unary_expression" and \
+                parent.children[0].type == "&"
+            written = _matches(type_node, go_file, package, name, paths)
+            if written is not None:
+                record(go_file, node, body, written, pointer)
+                continue
+            # []User{{...}}, map[string]*User{"a": {...}}: elements without a type
+            element = _element_type(type_node)
+            if element is None:
+                continue
+            element_pointer = element.type == "pointer_type"
+            if _matches(element, go_file, package, name, paths) is None:
+                continue
+            for value in _values(body):
+                if value.type == "literal_element":
+                    value = next(iter(value.named_children), value)
+                if value.type == "literal_value":
+                    record(go_file, value, value, "", element_pointer)
+
+    report.constructions.sort(key=lambda c: (c.file, c.line))
+    return report
+
+
+def format_constructions(report: ConstructionReport, scope: str) -> str:
+    """Header with the declaration, how often each field is set, then per
+    file "@line function: &T{fields}" with what the literal leaves out."""
+    constructions = report.constructions
+    if not constructions:
+        return f"No composite literals of {report.type_name} found in {scope}"
+
+    lines = [f"{len(constructions)} composite literals of {report.type_name} in {scope}"]
+    if report.declaration is not None:
+        file, line = report.declaration
+        lines.append(f"declared in {file}@{line} with {len(report.declared)} fields")
+        counts = report.field_counts()
+        lines.append("fields set: " + ", ".join(
+            f"{name} {count}/{len(constructions)}" for name, count in counts.items()))
+    elif report.candidates > 1:
+        lines.append(f"declared in {report.candidates} packages — pass pkg.T to see "
+                     "omitted fields")
+    else:
+        lines.append("declaration not in the scanned files — omitted fields unknown")
+
+    current_file = None
+    for construction in constructions:
+        if construction.file != current_file:
+            current_file = construction.file
+            lines.append(f"\n{current_file}")
+        shown = construction.type or f"{report.type_name} (elided)"
+        fields = ", ".join(construction.fields)
+        if construction.positional:
+            fields += " (positional)" if fields else "positional"
+        note = ""
+        if construction.omitted:
+            note = f" — omits {', '.join(construction.omitted)}"
+        lines.append(f"- @{construction.line} {construction.function}: "
+                     f"{'&' if construction.pointer else ''}{shown}{{{fields}}}{note}")
+    return "\n".join(lines)
@@ -0,0 +1,138 @@
+"""
+FILE: deadcode.py
+
+PROBLEM:
+  Unexported functions, types and methods can only be used inside their
+  own package, so one that no file of the package mentions is dead weight
+  — but nothing in a per-file scan says "nobody calls flushLocked".
+
+SOLUTION:
+  Per package (directory), collect every identifier use in its files —
+  plain, type and field identifiers, so calls, conversions, type
+  references, selectors (x.method) and method values all count — leaving
+  out the declaring name itself. An unexported declaration whose name
+  never shows up is reported. init, main and "_" are entry points or
+  placeholders and never reported. _test.go files are reference sources
+  (and their own declarations candidates) only on request.
+
+SCOPE:
+  ✓ Functions, methods (per receiver type) and types, grouped specs too
+  ✓ Methods matched by name: any x.name selector or interface method
+    spec of that name keeps every method called name alive
+  ✓ Receivers don't count: a type only its methods mention is reported
+  ✗ Heuristic: reflection, cgo //export, go:linkname and code generated
+    at build time use symbols without naming them — false positives
+  ✗ No reachability: code only used by other dead code (or itself,
+    recursively) counts as used
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+ENTRY_POINTS = {"init", "main", "_"}
+
+_IDENTIFIERS = {"identifier", "type_identifier", "field_identifier"}
+
+
+@dataclass
+class DeadSymbol:
+    name: str
+    kind: str  # function, method, type
+    file: str
+    line: int
+    receiver: Optional[str] = None  # methods: receiver base type
+
+    @property
+    def display_name(self) -> str:
+        return f"{self.receiver}.{self.name}" if self.receiver else self.name
+
+    def to_dict(self) -> dict:
+        data = {"name": self.display_name, "kind": self.kind, "file": self.file,
+                "line": self.line}
+        if self.receiver:
+            data["receiver"] = self.receiver
+        return data
+
+
+def _is_exported(name: str) -> bool:
+    return name[:1].isupper()
+
+
+def _declarations(go_file: GoFile) -> list[tuple[DeadSymbol, object]]:
+    """Unexported candidates of a file with their declaring name node."""
+    source = go_file.source
+    found = []
+    for decl in go_file.root.children:
+        name_node = decl.child_by_field_name("name")
+        if decl.type == "function_declaration" and name_node is not None:
+            found.append((DeadSymbol(syntax.node_text(name_node, source), "function",
+                                     go_file.path, syntax.line_of(decl)), name_node))
+        elif decl.type == "method_declaration" and name_node is not None:
+            receiver_type, _ = syntax.receiver(decl, source)
+            found.append((DeadSymbol(syntax.node_text(name_node, source), "method",
+                                     go_file.path, syntax.line_of(decl), receiver_type), name_node))
+        elif decl.type == "type_declaration":
+            for spec in decl.named_children:
+                name_node = spec.child_by_field_name("name")
+                if spec.type in ("type_spec", "type_alias") and name_node is not None:
+                    found.append((DeadSymbol(syntax.node_text(name_node, source), "type",
+                                             go_file.path, syntax.line_of(spec)), name_node))
+    return [(symbol, node) for symbol, node in found
+            if not _is_exported(symbol.name) and symbol.name not in ENTRY_POINTS]
+
+
+def _reference_nodes(root):
+    """All nodes of a file except method receivers: a type named only by
+    its own methods' receivers is still unused."""
+    for decl in root.children:
+        skip = decl.child_by_field_name("receiver") if decl.type == "method_declaration" else None
+        for child in decl.children:
+            if skip is None or child.id != skip.id:
+                yield from syntax.walk(child)
+
+
+def find_dead_code(files: list[GoFile], include_tests: bool = False) -> list[DeadSymbol]:
+    """Unexported declarations never referenced within their package, in
+    file then line order."""
+    packages: dict[str, list[GoFile]] = {}
+    for go_file in files:
+        if include_tests or not go_file.path.endswith("_test.go"):
+            packages.setdefault(go_file.directory, []).append(go_file)
+
+    dead = []
+    for package_files in packages.values():
+        candidates = []
+        declaring: set[tuple[str, int]] = set()
+        for go_file in package_files:
+            for symbol, name_node in _declarations(go_file):
+                candidates.append(symbol)
+                declaring.add((go_file.path, name_node.start_byte))
+
+        referenced: set[str] = set()
+        for go_file in package_files:
+            for node in _reference_nodes(go_file.root):
+                if node.type in _IDENTIFIERS and (go_file.path, node.start_byte) not in declaring:
+                    referenced.add(syntax.node_text(node, go_file.source))
+
+        dead.extend(symbol for symbol in candidates if symbol.name not in referenced)
+    dead.sort(key=lambda s: (s.file, s.line))
+    return dead
+
+
+def format_dead_code(symbols: list[DeadSymbol], scope: str) -> str:
+    """Per file: "@line kind name" lines, with the heuristic's caveat."""
+    if not symbols:
+        return f"No unreferenced unexported symbols in {scope}"
+
+    lines = [f"{len(symbols)} unreferenced unexported symbols in {scope} "
+             "(heuristic: reflection, cgo and go:linkname uses are invisible)"]
+    current_file = None
+    for symbol in symbols:
+        if symbol.file != current_file:
+            current_file = symbol.file
+            lines.append(f"\n{current_file}")
+        lines.append(f"- @{symbol.line} {symbol.kind} {symbol.display_name}")
+    return "\n".join(lines)
@@ -0,0 +1,170 @@
+"""
+FILE: defers.py
+
+PROBLEM:
+  defer is easy to get wrong in two ways a reader skims past. Inside a
+  for body it runs when the function returns, not per iteration — files
+  stay open until the loop is done, and a closure captures the loop
+  variable. And `defer f.Close()` throws away Close's error, which for a
+  file being written is the error that says the data didn't make it.
+
+SOLUTION:
+  Walk every defer_statement and report, with its enclosing function:
+    call        the deferred call as written ("func() {…}()" for a closure)
+    in_loop     a for statement lies between the defer and its function
+                (a defer inside a func literal in the loop runs at the
+                literal's return, so it doesn't count)
+    drops_error the call returns an error nobody sees: a same-package
+                function or method whose last error result is known from
+                its declaration (resolved like call_graph), or — callee not
+                in the package — a method named like one of the
+                error-returning cleanups (Close, Flush, Sync, Commit,
+                Rollback by default)
+
+SCOPE:
+  ✓ Functions, methods, closures and package-level func literals
+  ✗ drops_error for callees outside the package goes by method name only:
+    a Close() of a type that returns nothing is flagged too, os.Remove(x)
+    and other error-returning functions are not
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .calls import Package, function_scope, import_names, resolve_call
+from .errorhandling import error_result
+from .syntax import GoFile
+
+DEFAULT_ERROR_METHODS = ("Close", "Flush", "Sync", "Commit", "Rollback")
+
+_FUNCTIONS = ("function_declaration", "method_declaration", "func_literal")
+_LOOPS = ("for_statement",)
+
+
+@dataclass
+class Defer:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    package: Optional[str]
+    call: str      # the deferred call as written, on one line
+    in_loop: bool = False
+    loop_line: Optional[int] = None  # innermost enclosing for statement
+    drops_error: bool = False
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "function": self.function,
+                "package": self.package, "call": self.call, "in_loop": self.in_loop,
+                "loop_line": self.loop_line, "drops_error": self.drops_error}
+
+
+def _loop(defer) -> Optional[int]:
+    """Line of the innermost for statement around defer within its function."""
+    current = defer.parent
+    while current is not None and current.type not in _FUNCTIONS:
+        if current.type in _LOOPS:
+            return syntax.line_of(current)
+        current = current.parent
+    return None
+
+
+def _rendered(call, source: bytes) -> str:
+    function = call.child_by_field_name("function")
+    if function is None or function.type != "func_literal":
+        return syntax.normalized_text(call, source)
+    parameters = function.child_by_field_name("parameters")
+    arguments = call.child_by_field_name("arguments")
+    return (f"func{syntax.normalized_text(parameters, source) if parameters else '()'} {{…}}"
+            f"{syntax.normalized_text(arguments, source) if arguments else '()'}")
+
+
+def _drops_error(call, go_file: GoFile, package: Package, scope: dict[str, str],
+                 imports: set[str], error_methods: set[str]) -> bool:
+    function = call.child_by_field_name("function")
+    if function is None:
+        return False
+    if function.type == "func_literal":
+        return error_result(function, go_file.source) is not None
+    resolved = resolve_call(call, go_file, package, scope, imports)
+    if resolved is None:
+        return False
+    if resolved[4] is not None:
+        return error_result(resolved[4], resolved[3].source) is not None
+    if function.type != "selector_expression":
+        return False
+    field = function.child_by_field_name("field")
+    return field is not None and syntax.node_text(field, go_file.source) in error_methods
+
+
+def _top_level(node):
+    """The top-level declaration node sits in."""
+    while node.parent is not None and node.parent.parent is not None:
+        node = node.parent
+    return node
+
+
+def find_defers(files: list[GoFile], include_tests: bool = False, loops_only: bool = False,
+                error_methods: Optional[list[str]] = None) -> list[Defer]:
+    """defer statements in file then line order; loops_only keeps the ones
+    in a loop. error_methods names the outside-package methods taken to
+    return an error (default DEFAULT_ERROR_METHODS)."""
+    methods = set(DEFAULT_ERROR_METHODS if error_methods is None else error_methods)
+    packages: dict[tuple[str, str], list[GoFile]] = {}
+    for go_file in files:
+        if not include_tests and go_file.path.endswith("_test.go"):
+            continue
+        packages.setdefault((go_file.directory, go_file.package or ""), []).append(go_file)
+
+    found = []
+    for package_files in packages.values():
+        package = Package(package_files)
+        for go_file in package_files:
+            imports = import_names(go_file)
+            scopes: dict[int, dict[str, str]] = {}  # top-level decl start byte -> scope
+            for node in syntax.walk(go_file.root):
+                if node.type != "defer_statement":
+                    continue
+                call = next((c for c in node.named_children if c.type == "call_expression"), None)
+                if call is None:
+                    continue
+                loop_line = _loop(node)
+                if loops_only and loop_line is None:
+                    continue
+                decl = _top_level(node)
+                if decl.start_byte not in scopes:
+                    scopes[decl.start_byte] = function_scope(decl, go_file.source, package) \
+                        if decl.type in ("function_declaration", "method_declaration") else {}
+                found.append(Defer(
+                    go_file.path, syntax.line_of(node),
+                    syntax.enclosing_function(node, go_file.source), go_file.package,
+                    _rendered(call, go_file.source),
+                    in_loop=loop_line is not None, loop_line=loop_line,
+                    drops_error=_drops_error(call, go_file, package, scopes[decl.start_byte],
+                                             imports, methods)))
+    found.sort(key=lambda d: (d.file, d.line))
+    return found
+
+
+def format_defers(defers: list[Defer], scope: str) -> str:
+    """Tally, then per file "@line function: defer call" with the flags."""
+    if not defers:
+        return f"No defer statements found in {scope}"
+
+    in_loops = sum(1 for d in defers if d.in_loop)
+    dropping = sum(1 for d in defers if d.drops_error)
+    lines = [f"{len(defers)} defers in {scope} ({in_loops} inside a loop, "
+             f"{dropping} dropping an error)"]
+    current_file = None
+    for defer in defers:
+        if defer.file != current_file:
+            current_file = defer.file
+            lines.append(f"\n{current_file}")
+        notes = []
+        if defer.in_loop:
+            notes.append(f"in loop @{defer.loop_line} — runs at function return")
+        if defer.drops_error:
+            notes.append("error dropped")
+        note = f"  [{'; '.join(notes)}]" if notes else ""
+        lines.append(f"- @{defer.line} {defer.function}: defer {defer.call}{note}")
+    return "\n".join(lines)
@@ -0,0 +1,324 @@
+"""
+FILE: diagram.py
+
+PROBLEM:
+  Package documentation wants a picture of the types — what holds what,
+  what implements what — and a hand-drawn diagram is stale the day after
+  it is committed.
+
+SOLUTION:
+  Build a Mermaid classDiagram for one package (the non-test .go files
+  directly in a directory) from its syntax trees:
+    - every named type as a class: struct fields, interface methods, and
+      the methods declared on it in any file of the package; + exported,
+      - unexported
+    - Outer *-- Inner where a struct embeds a package-local type,
+      Child --|> Parent where an interface embeds a local interface
+    - Owner --> Held : field where a field's type mentions a local type
+      ("*" multiplicity through slices, arrays, maps and channels)
+    - Impl ..|> Iface from the structural implementers analysis
+      (interfaces.find_implementers), labelled "pointer" when only *Impl
+      has the method set
+
+  Member text is made safe for the Mermaid parser GitHub and mkdocs use:
+  inline struct/interface types collapse to struct / interface / any
+  (braces would end the class body), channel arrows become ←, the ~ of
+  type sets becomes ≈ (Mermaid reads ~ as generics), and a type named
+  like a Mermaid keyword (link, note, style...) is backtick-quoted.
+
+SCOPE:
+  ✓ Generic types with one type parameter drawn as Name~T~
+  ✗ Generics with several parameters are drawn without them (Mermaid
+    can't express a comma inside ~ ~)
+  ✗ Types from other packages appear in member text only, never as classes
+  ✗ No implements arrows to an interface embedding one from outside the
+    package (io.Reader) — its full method set is unknown
+"""
+
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .interfaces import find_implementers, list_interfaces
+from .syntax import GoFile
+
+# Words the Mermaid class-diagram grammar reserves at the start of a statement
+_MERMAID_KEYWORDS = {"class", "classDef", "cssClass", "callback", "call", "click",
+                     "direction", "end", "href", "link", "namespace", "note", "style"}
+
+# Type constructors whose element types are held many times
+_MANY = {"slice_type", "array_type", "map_type", "channel_type", "implicit_length_array_type"}
+
+_KIND_BY_NODE = {"struct_type": "struct", "interface_type": "interface",
+                 "function_type": "func"}
+
+
+@dataclass
+class DiagramType:
+    name: str
+    kind: str  # struct, interface, func, alias, other
+    file: str
+    line: int
+    type_params: list[str] = field(default_factory=list)
+    underlying: Optional[str] = None  # non-struct, non-interface types: "string", "[]User"
+    fields: list[str] = field(default_factory=list)   # "Name string", embedded: "*Base"
+    methods: list[str] = field(default_factory=list)  # "Get(key string) (string, error)"
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "kind": self.kind, "file": self.file, "line": self.line,
+                "type_params": self.type_params, "underlying": self.underlying,
+                "fields": self.fields, "methods": self.methods}
+
+
+@dataclass
+class DiagramEdge:
+    source: str
+    target: str
+    kind: str  # embeds, field, implements
+    label: Optional[str] = None  # field names; "pointer" for *T-only implementers
+    many: bool = False  # held through a slice, array, map or channel
+
+    def to_dict(self) -> dict:
+        return {"source": self.source, "target": self.target, "kind": self.kind,
+                "label": self.label, "many": self.many}
+
+
+@dataclass
+class ClassDiagram:
+    directory: str
+    package: Optional[str]
+    types: list[DiagramType] = field(default_factory=list)
+    edges: list[DiagramEdge] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {"directory": self.directory, "package": self.package,
+                "types": [t.to_dict() for t in self.types],
+                "edges": [e.to_dict() for e in self.edges]}
+
+
+def _render_type(node, source: bytes) -> str:
+    """Type text with inline struct and interface literals collapsed."""
+    pieces, cursor = [], node.start_byte
+    stack = [node]
+    while stack:
+        current = stack.pop()
+        if current.type in ("struct_type", "interface_type"):
+            pieces.append(source[cursor:current.start_byte].decode("utf-8", errors="replace"))
+            if current.type == "struct_type":
+                pieces.append("struct")
+            else:
+                pieces.append("interface" if current.named_children else "any")
+            cursor = current.end_byte
+            continue
+        stack.extend(reversed(current.children))
+    pieces.append(source[cursor:node.end_byte].decode("utf-8", errors="replace"))
+    return " ".join("".join(pieces).split())
+
+
+def _local_references(type_node, source: bytes, local: set[str],
+                      many: bool = False) -> list[tuple[str, bool]]:
+    """(local type name, held many times) for each local type a type
+    expression mentions, in source order."""
+    if type_node.type == "type_identifier":
+        name = syntax.node_text(type_node, source)
+        return [(name, many)] if name in local else []
+    if type_node.type in ("qualified_type", "struct_type", "interface_type", "function_type"):
+        return []  # other packages; inline literals are not holders of the outer type
+    many = many or type_node.type in _MANY
+    found = []
+    for child in type_node.named_children:
+        found.extend(_local_references(child, source, local, many))
+    return found
+
+
+def _type_params(spec, source: bytes) -> list[str]:
+    params = spec.child_by_field_name("type_parameters")
+    if params is None:
+        return []
+    return [syntax.node_text(name, source)
+            for decl in params.named_children
+            for name in decl.children_by_field_name("name")]
+
+
+def _struct_members(diagram_type: DiagramType, struct, source: bytes, local: set[str],
+                    edges: list[DiagramEdge]) -> None:
+    held: dict[tuple[str, bool], list[str]] = {}
+    field_list = next((c for c in struct.children if c.type == "field_declaration_list"), None)
+    for decl in field_list.named_children if field_list is not None else []:
+        if decl.type != "field_declaration":
+            continue
+        type_node = decl.child_by_field_name("type")
+        if type_node is None:
+            continue
+        type_text = _render_type(type_node, source)
+        names = [syntax.node_text(n, source) for n in decl.children_by_field_name("name")]
+        if not names:
+            pointer = "*" if any(c.type == "*" for c in decl.children) else ""
+            diagram_type.fields.append(pointer + type_text)
+            embedded = syntax.base_type_name(type_node, source)
+            if embedded in local:
+                edges.append(DiagramEdge(diagram_type.name, embedded, "embeds"))
+            continue
+        diagram_type.fields.extend(f"{name} {type_text}" for name in names)
+        for target, many in dict.fromkeys(_local_references(type_node, source, local)):
+            held.setdefault((target, many), []).extend(names)
+    edges.extend(DiagramEdge(diagram_type.name, target, "field", ", ".join(names), many)
+                 for (target, many), names in held.items())
+
+
+def _render_header(node, name: str, source: bytes) -> str:
+    """format_header with inline struct and interface types collapsed."""
+    header = syntax.format_header(node, name, source)
+    if "{" not in header:
+        return header
+    parts = [name]
+    for field_name in ("type_parameters", "parameters"):
+        child = node.child_by_field_name(field_name)
+        if child is not None:
+            parts.append(_render_type(child, source))
+    result = node.child_by_field_name("result")
+    if result is not None:
+        parts.append(" " + _render_type(result, source))
+    return "".join(parts)
+
+
+def build_class_diagram(files: list[GoFile], directory: str) -> ClassDiagram:
+    """Class diagram of the package in `directory`, from its non-test files
+    that live directly in it."""
+    target = str(Path(directory).resolve())
+    in_dir = [f for f in files if f.directory == target and not f.path.endswith("_test.go")]
+    packages = Counter(f.package for f in in_dir if f.package)
+    diagram = ClassDiagram(directory=target,
+                           package=packages.most_common(1)[0][0] if packages else None)
+
+    specs = []
+    for go_file in in_dir:
+        for decl in go_file.root.children:
+            if decl.type == "type_declaration":
+                specs.extend((go_file, spec) for spec in decl.named_children
+                             if spec.type in ("type_spec", "type_alias")
+                             and spec.child_by_field_name("name") is not None)
+    local = {syntax.node_text(spec.child_by_field_name("name"), go_file.source)
+             for go_file, spec in specs}
+
+    types: dict[str, DiagramType] = {}
+    for go_file, spec in specs:
+        source = go_file.source
+        name = syntax.node_text(spec.child_by_field_name("name"), source)
+        if name in types:
+            continue
+        type_node = spec.child_by_field_name("type")
+        kind = ("alias" if spec.type == "type_alias"
+                else _KIND_BY_NODE.get(type_node.type if type_node else "", "other"))
+        diagram_type = DiagramType(name, kind, go_file.path, syntax.line_of(spec),
+                                   type_params=_type_params(spec, source))
+        types[name] = diagram_type
+        if type_node is None:
+            continue
+        if kind == "struct":
+            _struct_members(diagram_type, type_node, source, local, diagram.edges)
+        elif kind == "interface":
+            for elem in type_node.named_children:
+                if elem.type in ("method_elem", "method_spec"):
+                    method_name = syntax.node_text(elem.child_by_field_name("name"), source)
+                    diagram_type.methods.append(
+                        _render_header(elem, method_name, source))
+                elif elem.type != "comment":
+                    # A lone name (Reader, io.Reader) is an embed; unions are type sets
+                    single = elem.named_children[0] if len(elem.named_children) == 1 else None
+                    embedded = syntax.base_type_name(elem, source) \
+                        or syntax.base_type_name(single, source)
+                    if embedded in local:
+                        diagram.edges.append(DiagramEdge(name, embedded, "embeds"))
+                    else:
+                        diagram_type.fields.append(_render_type(elem, source))
+        else:
+            diagram_type.underlying = _render_type(type_node, source)
+            if kind == "alias":
+                continue
+            for held, many in dict.fromkeys(_local_references(type_node, source, local)):
+                diagram.edges.append(DiagramEdge(name, held, "field", None, many))
+
+    for go_file in in_dir:
+        for decl in go_file.root.children:
+            name_node = decl.child_by_field_name("name")
+            if decl.type != "method_declaration" or name_node is None:
+                continue
+            receiver_type, _ = syntax.receiver(decl, go_file.source)
+            owner = types.get(receiver_type)
+            if owner is not None:
+                owner.methods.append(_render_header(
+                    decl, syntax.node_text(name_node, go_file.source), go_file.source))
+
+    for info in list_interfaces(in_dir):
+        if not info.methods and not info.embeds:
+            continue  # every type satisfies an empty interface
+        result = find_implementers(in_dir, info.name)
+        if result.unresolved_embeds:
+            continue  # only part of the method set is known
+        for implementer in result.implementers:
+            diagram.edges.append(DiagramEdge(implementer.type_name, info.name, "implements",
+                                             "pointer" if implementer.pointer_only else None))
+
+    diagram.types = sorted(types.values(), key=lambda t: (t.file, t.line))
+    return diagram
+
+
+# ── Mermaid ──────────────────────────────────────────────────────────────────
+
+def _mermaid_name(name: str) -> str:
+    return f"`{name}`" if name in _MERMAID_KEYWORDS else name
+
+
+def _member(text: str) -> str:
+    """A member line with its visibility marker and Mermaid-safe text."""
+    text = text.replace("<-", "←")
+    if text.startswith("~") or "|" in text:
+        return text.replace("~", "≈")  # type set: no visibility; Mermaid reads ~ as generics
+    head = text.split(" ", 1)[0].split("(", 1)[0].lstrip("*").rsplit(".", 1)[-1]
+    return ("+" if head[:1].isupper() else "-") + text
+
+
+def _annotation(diagram_type: DiagramType) -> Optional[str]:
+    if diagram_type.kind in ("interface", "func", "alias"):
+        return diagram_type.kind
+    if diagram_type.kind == "other" and diagram_type.underlying:
+        simple = all(part.isidentifier() for part in diagram_type.underlying.split("."))
+        return diagram_type.underlying if simple else "type"
+    return None
+
+
+def format_mermaid(diagram: ClassDiagram) -> str:
+    """The diagram as Mermaid classDiagram source."""
+    lines = ["classDiagram",
+             f"%% package {diagram.package or '(none)'} — {diagram.directory}"]
+    for diagram_type in diagram.types:
+        name = _mermaid_name(diagram_type.name)
+        if len(diagram_type.type_params) == 1:
+            name += f"~{diagram_type.type_params[0]}~"
+        annotation = _annotation(diagram_type)
+        members = [_member(f) for f in diagram_type.fields] \
+            + [_member(m) for m in diagram_type.methods]
+        if annotation is None and not members:
+            lines.append(f"    class {name}")
+            continue
+        lines.append(f"    class {name} {{")
+        if annotation:
+            lines.append(f"        <<{annotation}>>")
+        lines.extend(f"        {member}" for member in members)
+        lines.append("    }")
+
+    kinds = {t.name: t.kind for t in diagram.types}
+    for edge in diagram.edges:
+        source, target = _mermaid_name(edge.source), _mermaid_name(edge.target)
+        if edge.kind == "embeds":
+            arrow = "--|>" if kinds.get(edge.source) == "interface" else "*--"
+            line = f"{source} {arrow} {target}"
+        elif edge.kind == "implements":
+            line = f"{source} ..|> {target}"
+        else:
+            line = f'{source} --> "*" {target}' if edge.many else f"{source} --> {target}"
+        lines.append(f"    {line}" + (f" : {edge.label}" if edge.label else ""))
+    return "\n".join(lines)
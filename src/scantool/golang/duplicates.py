@@ -0,0 +1,146 @@
+"""
+FILE: duplicates.py
+
+PROBLEM:
+  Copy-pasted logic drifts: a bug fixed in one copy stays in the other.
+  The directory scan's DUPLICATE check only sees byte-identical blocks, so
+  a pasted function whose variables were renamed goes unnoticed.
+
+SOLUTION:
+  Render every function and method body as its syntax-tree token stream —
+  comments dropped, whitespace and formatting gone by construction — and
+  hash it twice: as written (exact copies) and with identifiers replaced
+  by their order of first appearance (copies that only rename variables,
+  fields, types or callees). Bodies of at least min_lines whose renamed
+  hashes match form a group; a group is "exact" when the copies are also
+  token-identical.
+
+SCOPE:
+  ✓ Across files and packages; methods shown as Type.Method
+  ✓ String and number literals compared verbatim — a copy with a
+    different constant is not a duplicate
+  ✗ Semantic clones (same logic, different statements or order) and
+    partial copies (a duplicated block inside a longer function)
+"""
+
+import hashlib
+from dataclasses import dataclass, field
+
+from . import syntax
+from .syntax import GoFile
+
+DEFAULT_MIN_LINES = 5
+
+_IDENTIFIERS = {"identifier", "field_identifier", "type_identifier",
+                "package_identifier", "label_name"}
+_ATOMS = {"interpreted_string_literal", "raw_string_literal", "rune_literal",
+          "int_literal", "float_literal", "imaginary_literal"}
+
+
+@dataclass
+class DuplicateFunction:
+    name: str  # Type.Method for methods
+    file: str
+    line: int
+    end_line: int
+
+    @property
+    def line_count(self) -> int:
+        return self.end_line - self.line + 1
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "file": self.file, "line": self.line,
+                "end_line": self.end_line}
+
+
+@dataclass
+class DuplicateGroup:
+    kind: str  # "exact" or "renamed"
+    functions: list[DuplicateFunction] = field(default_factory=list)
+
+    @property
+    def lines(self) -> int:
+        return min(f.line_count for f in self.functions)
+
+    def to_dict(self) -> dict:
+        return {"kind": self.kind, "lines": self.lines,
+                "functions": [f.to_dict() for f in self.functions]}
+
+
+def _tokens(body, source: bytes, rename: bool) -> list[str]:
+    """Leaf tokens of a syntax subtree, comments left out and literals
+    whole; with rename, each identifier becomes its first-appearance index."""
+    names: dict[str, str] = {}
+    tokens = []
+    stack = [body]
+    while stack:
+        node = stack.pop()
+        if node.type == "comment":
+            continue
+        if rename and node.type in _IDENTIFIERS:
+            tokens.append(names.setdefault(syntax.node_text(node, source), f"${len(names)}"))
+        elif node.type in _ATOMS or not node.children:
+            tokens.append(syntax.node_text(node, source) if node.is_named else node.type)
+        else:
+            stack.extend(reversed(node.children))
+    return tokens
+
+
+def _digest(tokens: list[str]) -> str:
+    return hashlib.sha1("\x00".join(tokens).encode("utf-8")).hexdigest()
+
+
+def _name(decl, source: bytes) -> str:
+    name = syntax.node_text(decl.child_by_field_name("name"), source)
+    if decl.type == "method_declaration":
+        receiver_type, _ = syntax.receiver(decl, source)
+        return f"{receiver_type}.{name}" if receiver_type else name
+    return name
+
+
+def find_duplicates(files: list[GoFile], min_lines: int = DEFAULT_MIN_LINES,
+                    include_tests: bool = False) -> list[DuplicateGroup]:
+    """Groups of functions with identical bodies up to renaming, largest
+    first; members in file then line order."""
+    by_shape: dict[str, list[tuple[DuplicateFunction, str]]] = {}
+    for go_file in files:
+        if not include_tests and go_file.path.endswith("_test.go"):
+            continue
+        for decl in go_file.root.children:
+            if decl.type not in ("function_declaration", "method_declaration"):
+                continue
+            body = decl.child_by_field_name("body")
+            if body is None or decl.child_by_field_name("name") is None:
+                continue
+            function = DuplicateFunction(_name(decl, go_file.source), go_file.path,
+                                         syntax.line_of(decl), decl.end_point[0] + 1)
+            if function.line_count < min_lines:
+                continue
+            by_shape.setdefault(_digest(_tokens(body, go_file.source, rename=True)), []).append(
+                (function, _digest(_tokens(body, go_file.source, rename=False))))
+
+    groups = []
+    for members in by_shape.values():
+        if len(members) < 2:
+            continue
+        exact = len({digest for _, digest in members}) == 1
+        functions = sorted((f for f, _ in members), key=lambda f: (f.file, f.line))
+        groups.append(DuplicateGroup("exact" if exact else "renamed", functions))
+    groups.sort(key=lambda g: (-g.lines, -len(g.functions),
+                               g.functions[0].file, g.functions[0].line))
+    return groups
+
+
+def format_duplicates(groups: list[DuplicateGroup], scope: str) -> str:
+    """One block per group: size and kind, then its locations."""
+    if not groups:
+        return f"No duplicate function bodies in {scope}"
+
+    exact = sum(1 for g in groups if g.kind == "exact")
+    lines = [f"{len(groups)} duplicate groups in {scope} "
+             f"({exact} exact, {len(groups) - exact} renamed)"]
+    for group in groups:
+        lines.append(f"\n- {group.kind}, {len(group.functions)}x, {group.lines}+ lines")
+        for function in group.functions:
+            lines.append(f"  {function.file} @{function.line} {function.name}")
+    return "\n".join(lines)
@@ -0,0 +1,205 @@
+"""
+FILE: errorhandling.py
+
+PROBLEM:
+  find_unchecked_errors looks at one call at a time. The API question is
+  different: which of this package's error-returning functions have
+  their errors dropped habitually — `_ = store.Flush()`, `defer f.Close()`,
+  `s.Save(u)` as a bare statement — across all their callers?
+
+SOLUTION:
+  For every function and method of a package whose results include an
+  error, find its call sites among the package's calls (resolved like
+  call_graph does: package functions, methods through receiver,
+  parameter, variable and field types) and classify what happens to the
+  error result at each:
+    checked    assigned to a variable (x, err := f(); err = f())
+    returned   returned as is (return f())
+    used       passed on or compared (wrap(f()), if f() != nil)
+    blank      assigned to _ (x, _ := f(); _ = f())
+    ignored    the call is a statement of its own, go f() or defer f()
+  blank + ignored are the dropped errors; the ignore ratio is dropped /
+  call sites. "checked" means the error lands in a variable — whether
+  that variable is then looked at is find_unchecked_errors' job.
+
+SCOPE:
+  ✓ Functions, methods (promoted ones too), calls inside closures
+  ✗ Same package only: callers in other packages, calls through
+    interfaces and function values are not seen — an exported API's
+    ratio covers its own package's use of it
+  ✗ A function whose error comes last is assumed to follow Go's
+    convention; results are counted by position, not by type checking
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from tree_sitter import Node
+
+from . import syntax
+from .calls import Package, function_scope, import_names, resolve_call
+from .syntax import GoFile
+
+HANDLING_KINDS = ("checked", "returned", "used", "blank", "ignored")
+DROPPED_KINDS = ("blank", "ignored")
+
+_ASSIGNMENTS = ("short_var_declaration", "assignment_statement")
+_DROPPING_STATEMENTS = ("expression_statement", "go_statement", "defer_statement")
+
+
+@dataclass
+class CallSite:
+    file: str
+    line: int
+    caller: str
+    handling: str  # one of HANDLING_KINDS
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "caller": self.caller,
+                "handling": self.handling}
+
+
+@dataclass
+class ErrorFunction:
+    name: str  # "Func" or "Type.Method"
+    file: str
+    line: int
+    error_index: int  # position of the error among the results
+    results: int      # number of results
+    sites: list[CallSite] = field(default_factory=list)
+
+    def count(self, handling: str) -> int:
+        return sum(1 for s in self.sites if s.handling == handling)
+
+    @property
+    def dropped(self) -> int:
+        return sum(1 for s in self.sites if s.handling in DROPPED_KINDS)
+
+    @property
+    def ignore_ratio(self) -> float:
+        return self.dropped / len(self.sites) if self.sites else 0.0
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "file": self.file, "line": self.line,
+                "calls": len(self.sites), "dropped": self.dropped,
+                "ignore_ratio": round(self.ignore_ratio, 3),
+                "handling": {kind: self.count(kind) for kind in HANDLING_KINDS},
+                "sites": [s.to_dict() for s in self.sites]}
+
+
+def error_result(decl: Node, source: bytes) -> Optional[tuple[int, int]]:
+    """(index of the last error result, number of results), None when the
+    declaration returns no error."""
+    results = syntax.result_types(decl.child_by_field_name("result"), source)
+    indexes = [i for i, result in enumerate(results) if result == "error"]
+    return (indexes[-1], len(results)) if indexes else None
+
+
+def _handling(call: Node, error_index: int, results: int, source: bytes) -> str:
+    """What the call's context does with its error result."""
+    node = call
+    parent = node.parent
+    while parent is not None and parent.type == "parenthesized_expression":
+        node, parent = parent, parent.parent
+    if parent is None:
+        return "used"
+    if parent.type in _DROPPING_STATEMENTS:
+        return "ignored"
+    if parent.type == "expression_list":
+        owner = parent.parent
+        values = [v for v in parent.named_children if v.type != "comment"]
+        if owner is not None and owner.type == "return_statement":
+            return "returned"
+        targets = None
+        if owner is not None and owner.type in _ASSIGNMENTS and \
+                owner.child_by_field_name("right") == parent:
+            left = owner.child_by_field_name("left")
+            targets = left.named_children if left is not None else []
+        elif owner is not None and owner.type == "var_spec":
+            targets = owner.children_by_field_name("name")
+        if targets is not None:
+            # One call feeding all targets, or one of several single values
+            index = error_index if len(values) == 1 and results > 1 else \
+                next((i for i, v in enumerate(values) if v == node), None)
+            if index is None or index >= len(targets):
+                return "checked"
+            return "blank" if syntax.node_text(targets[index], source) == "_" else "checked"
+    return "used"
+
+
+def error_handling_report(files: list[GoFile], include_tests: bool = False,
+                          min_calls: int = 1) -> list[ErrorFunction]:
+    """Error-returning functions with at least min_calls same-package call
+    sites, the most-dropped first (ratio, then dropped count, then name)."""
+    packages: dict[tuple[str, str], list[GoFile]] = {}
+    for go_file in files:
+        if not include_tests and go_file.path.endswith("_test.go"):
+            continue
+        packages.setdefault((go_file.directory, go_file.package or ""), []).append(go_file)
+
+    report = []
+    for package_files in packages.values():
+        package = Package(package_files)
+        # Declarations by (file, start byte): resolve_call hands back the node
+        functions: dict[tuple[str, int], ErrorFunction] = {}
+        declarations = [*package.functions.items(),
+                        *((f"{owner}.{method}", entry)
+                          for (owner, method), entry in package.methods.items())]
+        for name, (go_file, decl) in declarations:
+            error = error_result(decl, go_file.source)
+            if error is not None:
+                functions[(go_file.path, decl.start_byte)] = ErrorFunction(
+                    name, go_file.path, syntax.line_of(decl), *error)
+        if not functions:
+            continue
+
+        for go_file in package_files:
+            imports = import_names(go_file)
+            for decl in go_file.root.children:
+                if decl.type not in ("function_declaration", "method_declaration"):
+                    continue
+                body = decl.child_by_field_name("body")
+                if body is None:
+                    continue
+                scope = function_scope(decl, go_file.source, package)
+                for node in syntax.walk(body):
+                    if node.type != "call_expression":
+                        continue
+                    resolved = resolve_call(node, go_file, package, scope, imports)
+                    if resolved is None or resolved[3] is None:
+                        continue
+                    _, _, _, target_file, target_decl = resolved
+                    target = functions.get((target_file.path, target_decl.start_byte))
+                    if target is None:
+                        continue
+                    target.sites.append(CallSite(
+                        go_file.path, syntax.line_of(node),
+                        syntax.enclosing_function(node, go_file.source),
+                        _handling(node, target.error_index, target.results, go_file.source)))
+        report.extend(f for f in functions.values() if len(f.sites) >= min_calls)
+
+    report.sort(key=lambda f: (-f.ignore_ratio, -f.dropped, f.name, f.file))
+    return report
+
+
+def format_error_handling(report: list[ErrorFunction], scope: str) -> str:
+    """Per function: dropped / calls with the handling tally, then the
+    sites that drop the error."""
+    if not report:
+        return f"No error-returning functions with same-package call sites in {scope}"
+
+    dropping = sum(1 for f in report if f.dropped)
+    lines = [f"{len(report)} error-returning functions with call sites in {scope} "
+             f"({dropping} with dropped errors) — same-package calls only"]
+    for function in report:
+        tally = ", ".join(f"{function.count(kind)} {kind}" for kind in HANDLING_KINDS
+                          if function.count(kind))
+        lines.append(f"- {function.name} ({Path(function.file).name}@{function.line}): "
+                     f"{function.dropped}/{len(function.sites)} calls drop the error "
+                     f"({function.ignore_ratio:.0%}) — {tally}")
+        dropped = [s for s in function.sites if s.handling in DROPPED_KINDS]
+        if dropped:
+            lines.append("    dropped: " + ", ".join(
+                f"{Path(s.file).name}@{s.line} in {s.caller} ({s.handling})" for s in dropped))
+    return "\n".join(lines)
@@ -0,0 +1,112 @@
+"""
+FILE: formatting.py
+
+PROBLEM:
+  "Which of these Go files aren't gofmt-clean?" needs a separate gofmt -l
+  run today, and a file that doesn't parse looks the same as a badly
+  formatted one in a plain yes/no answer.
+
+SOLUTION:
+  Pipe the bytes the scan already read through gofmt (stdin, no second
+  disk read) — the go/format.Source printer, so the verdict is gofmt's own
+  — and compare its output with the original. Those are the file's raw
+  bytes, not the parsers' normalized source: a CRLF or BOM file is one
+  gofmt rewrites. Three states: formatted,
+  unformatted (optionally with a unified diff), and unknown when the file
+  has syntax errors or gofmt can't run. Python has no Go printer, so
+  without a gofmt binary on PATH every file is unknown rather than
+  guessed at.
+
+SCOPE:
+  ✓ Parse errors detected from the tree-sitter tree before running gofmt
+  ✓ Unified diff original → gofmt output, a/ and b/ prefixed like git
+  ✗ Needs gofmt (Go toolchain) on PATH; no re-implementation of its rules
+"""
+
+import difflib
+import shutil
+import subprocess
+from dataclasses import dataclass
+from typing import Optional
+
+from .syntax import GoFile
+
+FORMAT_STATES = ("formatted", "unformatted", "unknown")
+
+_GOFMT_TIMEOUT = 10.0
+
+
+@dataclass
+class FormatCheck:
+    file: str
+    state: str                    # one of FORMAT_STATES
+    reason: Optional[str] = None  # why unknown
+    diff: Optional[str] = None    # unformatted, when requested
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "gofmt": self.state}
+        if self.reason:
+            data["reason"] = self.reason
+        if self.diff is not None:
+            data["diff"] = self.diff
+        return data
+
+
+def gofmt_path() -> Optional[str]:
+    return shutil.which("gofmt")
+
+
+def _unified_diff(original: bytes, formatted: bytes, path: str) -> str:
+    before = original.decode("utf-8", errors="replace").splitlines(keepends=True)
+    after = formatted.decode("utf-8", errors="replace").splitlines(keepends=True)
+    return "".join(difflib.unified_diff(before, after, f"a/{path}", f"b/{path}"))
+
+
+def check_format(go_file: GoFile, gofmt: Optional[str], with_diff: bool = False) -> FormatCheck:
+    """gofmt verdict for one parsed file; gofmt is the binary path (None = missing)."""
+    if go_file.root.has_error:
+        return FormatCheck(go_file.path, "unknown", reason="syntax errors")
+    if gofmt is None:
+        return FormatCheck(go_file.path, "unknown", reason="gofmt not found on PATH")
+    original = go_file.raw_source if go_file.raw_source is not None else go_file.source
+    try:
+        result = subprocess.run([gofmt], input=original, capture_output=True,
+                                timeout=_GOFMT_TIMEOUT)
+    except (OSError, subprocess.TimeoutExpired) as e:
+        return FormatCheck(go_file.path, "unknown", reason=f"gofmt failed: {e}")
+    if result.returncode != 0:
+        message = result.stderr.decode("utf-8", errors="replace").strip().splitlines()
+        return FormatCheck(go_file.path, "unknown",
+                           reason=message[0] if message else f"gofmt exited {result.returncode}")
+    if result.stdout == original:
+        return FormatCheck(go_file.path, "formatted")
+    return FormatCheck(go_file.path, "unformatted",
+                       diff=_unified_diff(original, result.stdout, go_file.path)
+                       if with_diff else None)
+
+
+def check_formatting(files: list[GoFile], with_diff: bool = False,
+                     gofmt: Optional[str] = None) -> list[FormatCheck]:
+    """Verdicts for the given files, in their order (gofmt: binary path,
+    default the one on PATH)."""
+    gofmt = gofmt or gofmt_path()
+    return [check_format(go_file, gofmt, with_diff) for go_file in files]
+
+
+def format_format_checks(checks: list[FormatCheck], scope: str) -> str:
+    """Tally line, then the files that aren't clean: unformatted ones (with
+    their diff) and unknown ones with the reason."""
+    if not checks:
+        return f"No Go files found in {scope}"
+
+    counts = {state: sum(1 for c in checks if c.state == state) for state in FORMAT_STATES}
+    lines = [f"gofmt: {len(checks)} files in {scope} — "
+             + ", ".join(f"{counts[s]} {s}" for s in FORMAT_STATES)]
+    for check in checks:
+        if check.state == "unformatted":
+            lines.append(f"\n✗ {check.file}")
+            if check.diff:
+                lines.append(check.diff.rstrip("\n"))
+        elif check.state == "unknown":
+            lines.append(f"\n? {check.file} ({check.reason})")
+    return "\n".join(lines)
@@ -0,0 +1,102 @@
+"""
+FILE: generate.py
+
+PROBLEM:
+  `go generate` runs whatever commands the package's //go:generate lines
+  name — stringer, mockgen, protoc, a go run of a local tool — and the
+  files they write are the ones marked "Code generated ... DO NOT EDIT."
+  Nothing lists those steps: they are comments scattered over the
+  package, and grep for "go:generate" also hits prose, indented comments
+  and "// go:generate" lines the go tool ignores.
+
+SOLUTION:
+  Report every directive the go tool would run: a // line comment that
+  starts at column 0 with exactly "//go:generate" followed by a space or
+  tab — no space after "//", no indentation, not inside a /* */ block.
+  The command is the rest of the line, trimmed. A "-command NAME ..."
+  line defines NAME as an alias for later directives of its file rather
+  than running anything; it is reported with the alias it defines.
+
+SCOPE:
+  ✓ _test.go files too, which go generate also reads
+  ✓ Directives anywhere in the file, at column 0
+  ✗ $GOFILE, $GOPACKAGE and other variables are not expanded
+  ✗ Which files a command writes is not known
+"""
+
+import re
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_DIRECTIVE = re.compile(r"//go:generate[ \t](.*)$")
+
+
+@dataclass
+class GenerateDirective:
+    file: str
+    line: int
+    package: Optional[str]
+    command: str  # as written, without the //go:generate prefix
+    alias: Optional[str] = None  # name a "-command NAME ..." line defines
+
+    @property
+    def program(self) -> str:
+        """The command's first word: the alias for -command lines."""
+        words = self.command.split()
+        return self.alias or (words[0] if words else "")
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "line": self.line, "package": self.package,
+                "command": self.command, "program": self.program}
+        if self.alias:
+            data["alias"] = self.alias
+        return data
+
+
+def _directives(go_file: GoFile) -> list[GenerateDirective]:
+    found = []
+    for node in syntax.walk(go_file.root):
+        if node.type != "comment" or node.start_point[1] != 0:
+            continue
+        match = _DIRECTIVE.match(syntax.node_text(node, go_file.source))
+        if match is None:
+            continue
+        command = match.group(1).strip()
+        words = command.split()
+        alias = words[1] if len(words) > 2 and words[0] == "-command" else None
+        found.append(GenerateDirective(go_file.path, syntax.line_of(node), go_file.package,
+                                       command, alias))
+    return found
+
+
+def find_generate_directives(files: list[GoFile],
+                             include_tests: bool = True) -> list[GenerateDirective]:
+    """Every //go:generate directive, in file then line order (the order
+    go generate runs them)."""
+    found = []
+    for go_file in sorted(files, key=lambda f: f.path):
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        found.extend(_directives(go_file))
+    return found
+
+
+def format_generate_directives(directives: list[GenerateDirective], scope: str) -> str:
+    """Tally, then per file "@line command"."""
+    if not directives:
+        return f"No //go:generate directives found in {scope}"
+
+    programs = sorted({d.program for d in directives if d.alias is None})
+    lines = [f"{len(directives)} //go:generate directives in {scope} "
+             f"(runs {', '.join(programs) or 'nothing'})"]
+    current_file = None
+    for directive in directives:
+        if directive.file != current_file:
+            current_file = directive.file
+            lines.append(f"\n{current_file} (package {directive.package or '?'})")
+        defines = f"  [defines {directive.alias}]" if directive.alias else ""
+        lines.append(f"- @{directive.line} {directive.command}{defines}")
+    return "\n".join(lines)
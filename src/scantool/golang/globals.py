@@ -0,0 +1,246 @@
+"""
+FILE: globals.py
+
+PROBLEM:
+  Package-level vars are the mutable global state of a Go program — the
+  first thing a reviewer hunts for — but in a symbol outline they look
+  like constants, and `var cache = newCache()` (code that runs at
+  package init) reads the same as `var mu sync.Mutex` (a zero value).
+
+SOLUTION:
+  Walk the package-level var declarations, grouped as written (one block
+  per `var (...)` or single var), and report for every name:
+    - the type: declared, or inferred from a simple initializer —
+      basic literals (untyped defaults: int, float64, string, rune),
+      T{...} and &T{...}, conversions T(x) of builtin or package types,
+      make(T, ...), new(T), func literals, errors.New / fmt.Errorf
+    - the initializer as written, on one line
+    - how it is initialized: "zero" (no initializer), "static" (literals,
+      composite literals, conversions, func literals — no code of the
+      program runs) or "runtime" (calls a function at package init)
+  `var a, b = f()` shares one multi-value initializer: both names carry
+  it, with their result index.
+
+SCOPE:
+  ✓ Grouped and single declarations, multi-name specs, exported flag
+  ✗ Blank vars (var _ I = (*T)(nil), the compile-time interface check)
+    are not listed; function-local vars neither
+  ✗ Types of other initializers (calls, selectors, operators) are left
+    empty rather than guessed; a conversion to another package's type
+    (time.Duration(5)) looks like a call and counts as "runtime"
+"""
+
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_BUILTIN_TYPES = {
+    "bool", "string", "byte", "rune", "uintptr", "float32", "float64", "complex64",
+    "complex128", "error", "any",
+    "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+}
+_LITERAL_TYPES = {"int_literal": "int", "float_literal": "float64",
+                  "imaginary_literal": "complex128", "rune_literal": "rune",
+                  "interpreted_string_literal": "string", "raw_string_literal": "string",
+                  "true": "bool", "false": "bool"}
+# Calls that make no program code run at init
+_STATIC_BUILTINS = {"make", "new", "len", "cap", "min", "max", "complex", "real", "imag"}
+_ERROR_CONSTRUCTORS = {"errors.New", "fmt.Errorf"}
+# Function positions that make a call a conversion: ([]byte)(s), (*T)(nil)
+_TYPE_EXPRESSIONS = ("parenthesized_type", "pointer_type", "slice_type", "map_type",
+                     "array_type", "channel_type", "function_type", "interface_type")
+
+INIT_KINDS = ("zero", "static", "runtime")
+
+
+@dataclass
+class GlobalVar:
+    name: str
+    file: str
+    line: int
+    exported: bool
+    type: Optional[str] = None         # declared or inferred, None when unknown
+    type_inferred: bool = False        # type comes from the initializer
+    initializer: Optional[str] = None  # as written, None for zero values
+    init: str = "zero"                 # zero, static, runtime
+    result: Optional[int] = None       # index into a shared multi-value initializer
+
+    def to_dict(self) -> dict:
+        data = {"name": self.name, "file": self.file, "line": self.line,
+                "exported": self.exported, "type": self.type,
+                "initializer": self.initializer, "init": self.init}
+        if self.type_inferred:
+            data["type_inferred"] = True
+        if self.result is not None:
+            data["result"] = self.result
+        return data
+
+
+@dataclass
+class VarBlock:
+    file: str
+    line: int
+    grouped: bool  # var ( ... ) rather than a single var
+    vars: list[GlobalVar] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "grouped": self.grouped,
+                "vars": [v.to_dict() for v in self.vars]}
+
+
+def _is_type_name(name: str, types: set[str]) -> bool:
+    return name in _BUILTIN_TYPES or name in types
+
+
+def _inferred_type(node, source: bytes, types: set[str]) -> Optional[str]:
+    """Type of a simple initializer expression, None when not obvious."""
+    kind = node.type
+    if kind in _LITERAL_TYPES:
+        return _LITERAL_TYPES[kind]
+    if kind == "parenthesized_expression" and node.named_children:
+        return _inferred_type(node.named_children[0], source, types)
+    if kind == "composite_literal":
+        type_node = node.child_by_field_name("type")
+        return syntax.normalized_text(type_node, source) if type_node is not None else None
+    if kind == "unary_expression":
+        operator = node.child_by_field_name("operator")
+        operand = node.child_by_field_name("operand")
+        op = syntax.node_text(operator, source) if operator is not None else ""
+        if op == "&" and operand is not None and operand.type == "composite_literal":
+            inner = _inferred_type(operand, source, types)
+            return f"*{inner}" if inner else None
+        if op in ("-", "+") and operand is not None and operand.type in _LITERAL_TYPES:
+            return _LITERAL_TYPES[operand.type]
+        return None
+    if kind == "func_literal":
+        return syntax.format_header(node, "func", source)
+    if kind == "call_expression":
+        function = node.child_by_field_name("function")
+        arguments = node.child_by_field_name("arguments")
+        args = [a for a in (arguments.named_children if arguments else []) if a.type != "comment"]
+        name = syntax.normalized_text(function, source) if function is not None else ""
+        if name in _ERROR_CONSTRUCTORS:
+            return "error"
+        if name in ("make", "new") and args:
+            first = syntax.normalized_text(args[0], source)
+            return first if name == "make" else f"*{first}"
+        if function is not None and len(args) == 1 and (
+                _is_type_name(name, types) or function.type in _TYPE_EXPRESSIONS):
+            return name.removeprefix("(").removesuffix(")")
+    return None
+
+
+def _runs_code(node, source: bytes, types: set[str]) -> bool:
+    """node calls a function of the program (anything but a conversion or
+    a pure builtin); func literal bodies don't run at init."""
+    stack = [node]
+    while stack:
+        current = stack.pop()
+        if current.type == "func_literal":
+            continue
+        if current.type == "call_expression":
+            function = current.child_by_field_name("function")
+            name = syntax.normalized_text(function, source) if function is not None else ""
+            if function is not None and function.type == "identifier":
+                if name not in _STATIC_BUILTINS and not _is_type_name(name, types):
+                    return True
+            elif function is not None and function.type not in _TYPE_EXPRESSIONS:
+                # pkg.F(...), x.Method(...), f()(...); Box[int](x) converts
+                if not _is_type_name(name.split("[", 1)[0], types):
+                    return True
+        stack.extend(current.children)
+    return False
+
+
+def _var_specs(decl) -> list:
+    specs = []
+    for child in decl.named_children:
+        if child.type == "var_spec":
+            specs.append(child)
+        elif child.type == "var_spec_list":  # some grammar versions group specs in one
+            specs.extend(c for c in child.named_children if c.type == "var_spec")
+    return specs
+
+
+def _block(decl, go_file: GoFile, types: set[str]) -> VarBlock:
+    source = go_file.source
+    block = VarBlock(go_file.path, syntax.line_of(decl),
+                     grouped=any(c.type == "(" or c.type == "var_spec_list" for c in decl.children))
+    for spec in _var_specs(decl):
+        names = spec.children_by_field_name("name")
+        type_node = spec.child_by_field_name("type")
+        value_list = spec.child_by_field_name("value")
+        values = [v for v in (value_list.named_children if value_list else [])
+                  if v.type != "comment"]
+        shared = len(values) == 1 and len(names) > 1
+        for index, name_node in enumerate(names):
+            name = syntax.node_text(name_node, source)
+            if name == "_":
+                continue
+            value = values[0] if shared else values[index] if index < len(values) else None
+            variable = GlobalVar(name=name, file=go_file.path, line=syntax.line_of(spec),
+                                 exported=name[:1].isupper(),
+                                 result=index if shared else None)
+            if type_node is not None:
+                variable.type = syntax.normalized_text(type_node, source)
+            elif value is not None and not shared:
+                variable.type = _inferred_type(value, source, types)
+                variable.type_inferred = variable.type is not None
+            if value is not None:
+                variable.initializer = syntax.normalized_text(value, source)
+                variable.init = "runtime" if _runs_code(value, source, types) else "static"
+            block.vars.append(variable)
+    return block
+
+
+def list_globals(files: list[GoFile], include_tests: bool = False) -> list[VarBlock]:
+    """Package-level var blocks of the given files, in file then line order."""
+    packages: dict[str, list[GoFile]] = {}
+    for go_file in files:
+        if include_tests or not go_file.path.endswith("_test.go"):
+            packages.setdefault(go_file.directory, []).append(go_file)
+
+    blocks = []
+    for package_files in packages.values():
+        types = {syntax.node_text(s.child_by_field_name("name"), f.source)
+                 for f in package_files for s in syntax.type_specs(f.root)}
+        for go_file in package_files:
+            for decl in go_file.root.children:
+                if decl.type == "var_declaration":
+                    block = _block(decl, go_file, types)
+                    if block.vars:
+                        blocks.append(block)
+    blocks.sort(key=lambda b: (b.file, b.line))
+    return blocks
+
+
+def format_globals(blocks: list[VarBlock], scope: str) -> str:
+    """Per file: grouped blocks with one "name Type = initializer  [init]"
+    line per variable."""
+    if not blocks:
+        return f"No package-level variables found in {scope}"
+
+    variables = [v for b in blocks for v in b.vars]
+    counts = {kind: sum(1 for v in variables if v.init == kind) for kind in INIT_KINDS}
+    tally = ", ".join(f"{counts[kind]} {kind}" for kind in INIT_KINDS if counts[kind])
+    lines = [f"{len(variables)} package-level variables in {scope} ({tally})"]
+    current_file = None
+    for block in blocks:
+        if block.file != current_file:
+            current_file = block.file
+            lines.append(f"\n{current_file}")
+        if block.grouped:
+            lines.append(f"- var ( @{block.line}")
+        for variable in block.vars:
+            typed = f" {variable.type}" if variable.type and not variable.type_inferred else ""
+            inferred = f"  ({variable.type})" if variable.type_inferred else ""
+            value = f" = {variable.initializer}" if variable.initializer else ""
+            result = f" [{variable.result}]" if variable.result is not None else ""
+            prefix = "    " if block.grouped else f"- @{variable.line} "
+            lines.append(f"{prefix}{variable.name}{typed}{value}{result}{inferred}"
+                         f"  [{variable.init}]")
+        if block.grouped:
+            lines.append("  )")
+    return "\n".join(lines)
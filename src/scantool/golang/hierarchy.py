@@ -0,0 +1,320 @@
+"""
+FILE: hierarchy.py
+
+PROBLEM:
+  Go has no inheritance, but embedding builds the same kind of tree: a
+  Server embeds a Base that embeds a Logger interface, and Server.Log()
+  works although Server declares no Log. Reading a struct doesn't say
+  which members it gains that way, and nothing says which types embed a
+  given one.
+
+SOLUTION:
+  For one package (the non-test .go files directly in a directory):
+    - every struct's and interface's embeds, as written (Base, *Cache,
+      sync.Mutex), resolved to the package-local type where there is one
+    - the reverse, embedded_by, kept as a separate list so the direction
+      is never in doubt: Server embeds Base, Base is embedded by Server
+    - a forest from outermost to innermost: roots are the types no local
+      type embeds, children their local embeds
+    - optionally, the fields and methods each type gains through
+      embedding, found with Go's selector rule: the shallowest depth
+      wins, two candidates at the same depth are ambiguous and neither is
+      promoted, and the type's own members shadow everything embedded
+
+SCOPE:
+  ✓ Pointer embeds (*T); a *T-receiver method reached without a pointer
+    on the path is flagged pointer_only (only in *Outer's method set)
+  ✓ Embed cycles through pointers (type A struct{ *B }; type B struct{ *A })
+  ✗ Same package only: the members of sync.Mutex or io.Reader are not
+    known; such embeds are listed as unresolved
+  ✗ Aliases are not followed to the type they name
+"""
+
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_KIND_BY_NODE = {"struct_type": "struct", "interface_type": "interface"}
+
+
+@dataclass
+class Embed:
+    type: str              # as written: "Base", "*Cache", "sync.Mutex", "List[int]"
+    name: str              # the field name the embed declares: "Base", "Cache", "Mutex"
+    target: Optional[str]  # package-local type behind it; None for other packages
+    kind: str              # target's kind (struct, interface, other, alias); "external" if None
+    pointer: bool = False
+
+    def to_dict(self) -> dict:
+        return {"type": self.type, "name": self.name, "target": self.target,
+                "kind": self.kind, "pointer": self.pointer}
+
+
+@dataclass
+class PromotedMember:
+    name: str
+    member: str                  # "field" or "method"
+    via: list[str]               # embedded field path: ["Base", "Logger"]
+    signature: Optional[str] = None  # methods: "Log(msg string)"
+    pointer_only: bool = False   # *T receiver reached without a pointer embed
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "member": self.member, "via": self.via,
+                "signature": self.signature, "pointer_only": self.pointer_only}
+
+
+@dataclass
+class HierarchyType:
+    name: str
+    kind: str  # struct or interface
+    file: str
+    line: int
+    embeds: list[Embed] = field(default_factory=list)
+    embedded_by: list[str] = field(default_factory=list)  # local types embedding this one
+    promoted: list[PromotedMember] = field(default_factory=list)
+    ambiguous: list[str] = field(default_factory=list)   # same name at the same depth
+    unresolved: list[str] = field(default_factory=list)  # embeds from other packages
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "kind": self.kind, "file": self.file, "line": self.line,
+                "embeds": [e.to_dict() for e in self.embeds],
+                "embedded_by": self.embedded_by,
+                "promoted": [p.to_dict() for p in self.promoted],
+                "ambiguous": self.ambiguous, "unresolved": self.unresolved}
+
+
+@dataclass
+class TypeHierarchy:
+    directory: str
+    package: Optional[str]
+    types: list[HierarchyType] = field(default_factory=list)  # those that embed or are embedded
+    roots: list[str] = field(default_factory=list)  # outermost types of the forest
+
+    def to_dict(self) -> dict:
+        return {"directory": self.directory, "package": self.package,
+                "types": [t.to_dict() for t in self.types], "roots": self.roots}
+
+
+@dataclass
+class _Members:
+    """What a local type brings to an embedding: its own selectors."""
+
+    kind: str
+    file: str
+    line: int
+    fields: list[str] = field(default_factory=list)  # embedded field names included
+    methods: dict[str, tuple[str, bool]] = field(default_factory=dict)  # name -> (sig, *T)
+    embeds: list[Embed] = field(default_factory=list)
+
+
+def _embed(type_node, written: str, pointer: bool, source: bytes,
+           kinds: dict[str, str]) -> Optional[Embed]:
+    base = syntax.base_type_name(type_node, source)
+    if base is None:
+        return None
+    target = base if base in kinds else None
+    return Embed(type=written, name=base.rsplit(".", 1)[-1], target=target,
+                 kind=kinds[target] if target else "external", pointer=pointer)
+
+
+def _collect(in_dir: list[GoFile]) -> dict[str, _Members]:
+    specs = []
+    for go_file in in_dir:
+        for decl in go_file.root.children:
+            if decl.type == "type_declaration":
+                specs.extend((go_file, spec) for spec in decl.named_children
+                             if spec.type in ("type_spec", "type_alias")
+                             and spec.child_by_field_name("name") is not None)
+    kinds: dict[str, str] = {}
+    for go_file, spec in specs:
+        name = syntax.node_text(spec.child_by_field_name("name"), go_file.source)
+        type_node = spec.child_by_field_name("type")
+        kinds.setdefault(name, "alias" if spec.type == "type_alias"
+                         else _KIND_BY_NODE.get(type_node.type if type_node else "", "other"))
+
+    members: dict[str, _Members] = {}
+    for go_file, spec in specs:
+        source = go_file.source
+        name = syntax.node_text(spec.child_by_field_name("name"), source)
+        if name in members:
+            continue
+        info = members[name] = _Members(kinds[name], go_file.path, syntax.line_of(spec))
+        type_node = spec.child_by_field_name("type")
+        if info.kind == "struct":
+            field_list = next((c for c in type_node.children
+                               if c.type == "field_declaration_list"), None)
+            for decl in field_list.named_children if field_list is not None else []:
+                field_type = decl.child_by_field_name("type")
+                if decl.type != "field_declaration" or field_type is None:
+                    continue
+                names = [syntax.node_text(n, source) for n in decl.children_by_field_name("name")]
+                if names:
+                    info.fields.extend(names)
+                    continue
+                star = any(c.type == "*" for c in decl.children)
+                embed = _embed(field_type, ("*" if star else "")
+                               + syntax.normalized_text(field_type, source),
+                               star or field_type.type == "pointer_type", source, kinds)
+                if embed is not None:
+                    info.embeds.append(embed)
+                    info.fields.append(embed.name)
+        elif info.kind == "interface":
+            for elem in type_node.named_children:
+                if elem.type in ("method_elem", "method_spec"):
+                    method_name = syntax.node_text(elem.child_by_field_name("name"), source)
+                    info.methods[method_name] = (
+                        syntax.format_header(elem, method_name, source), False)
+                elif elem.type != "comment":
+                    # A lone name (Reader, io.Reader) is an embed; unions are type sets
+                    single = elem.named_children[0] if len(elem.named_children) == 1 else None
+                    node = elem if syntax.base_type_name(elem, source) else single
+                    embed = _embed(node, syntax.normalized_text(elem, source), False,
+                                   source, kinds) if node is not None else None
+                    if embed is not None:
+                        info.embeds.append(embed)
+
+    for go_file in in_dir:
+        for decl in go_file.root.children:
+            name_node = decl.child_by_field_name("name")
+            if decl.type != "method_declaration" or name_node is None:
+                continue
+            receiver_type, is_pointer = syntax.receiver(decl, go_file.source)
+            owner = members.get(receiver_type)
+            if owner is not None:
+                method_name = syntax.node_text(name_node, go_file.source)
+                owner.methods[method_name] = (
+                    syntax.format_header(decl, method_name, go_file.source), is_pointer)
+    return members
+
+
+def _promote(name: str, members: dict[str, _Members], hierarchy_type: HierarchyType) -> None:
+    """Fields and methods reachable through embeds, breadth first by depth."""
+    own = members[name]
+    blocked = set(own.fields) | set(own.methods)
+    expanded = {name}
+    # (embedded type, field path, a pointer somewhere on the path)
+    level = [(e.target, [e.name], e.pointer) for e in own.embeds if e.target]
+    while level:
+        candidates: dict[str, list[PromotedMember]] = {}
+        next_level = []
+        for target, via, through_pointer in level:
+            info = members[target]
+            for field_name in info.fields:
+                candidates.setdefault(field_name, []).append(
+                    PromotedMember(field_name, "field", via))
+            for method_name, (signature, pointer_receiver) in info.methods.items():
+                candidates.setdefault(method_name, []).append(PromotedMember(
+                    method_name, "method", via, signature,
+                    pointer_only=pointer_receiver and not through_pointer))
+            next_level.extend((e.target, via + [e.name], through_pointer or e.pointer)
+                              for e in info.embeds if e.target and e.target not in expanded)
+        expanded.update(target for target, _, _ in level)
+        for member_name, found in candidates.items():
+            if member_name in blocked:
+                continue
+            blocked.add(member_name)
+            # An interface may embed two interfaces declaring the same method
+            if len(found) > 1 and own.kind != "interface":
+                hierarchy_type.ambiguous.append(member_name)
+            else:
+                hierarchy_type.promoted.append(found[0])
+        level = [entry for entry in next_level if entry[0] not in expanded]
+
+
+def build_type_hierarchy(files: list[GoFile], directory: str,
+                         include_promoted: bool = True) -> TypeHierarchy:
+    """Embedding relationships of the package in `directory`, from its
+    non-test files that live directly in it."""
+    target = str(Path(directory).resolve())
+    in_dir = [f for f in files if f.directory == target and not f.path.endswith("_test.go")]
+    packages = Counter(f.package for f in in_dir if f.package)
+    hierarchy = TypeHierarchy(directory=target,
+                              package=packages.most_common(1)[0][0] if packages else None)
+
+    members = _collect(in_dir)
+    embedded_by: dict[str, list[str]] = {}
+    for name, info in members.items():
+        for embed in info.embeds:
+            if embed.target and name not in embedded_by.setdefault(embed.target, []):
+                embedded_by[embed.target].append(name)
+
+    for name, info in members.items():
+        if info.kind not in ("struct", "interface") or not (info.embeds or name in embedded_by):
+            continue
+        hierarchy_type = HierarchyType(name, info.kind, info.file, info.line,
+                                       embeds=info.embeds,
+                                       embedded_by=embedded_by.get(name, []),
+                                       unresolved=[e.type for e in info.embeds if not e.target])
+        if include_promoted:
+            _promote(name, members, hierarchy_type)
+        hierarchy.types.append(hierarchy_type)
+    hierarchy.types.sort(key=lambda t: (t.file, t.line))
+
+    reached: set[str] = set()
+
+    def reach(name: str) -> None:
+        stack = [name]
+        while stack:
+            current = stack.pop()
+            if current not in reached:
+                reached.add(current)
+                stack.extend(e.target for e in members[current].embeds if e.target)
+
+    for hierarchy_type in hierarchy.types:
+        if not hierarchy_type.embedded_by:
+            hierarchy.roots.append(hierarchy_type.name)
+            reach(hierarchy_type.name)
+    # Types only reachable through an embed cycle have no outermost type
+    for hierarchy_type in hierarchy.types:
+        if hierarchy_type.name not in reached:
+            hierarchy.roots.append(hierarchy_type.name)
+            reach(hierarchy_type.name)
+    return hierarchy
+
+
+def format_type_hierarchy(hierarchy: TypeHierarchy) -> str:
+    """Tree view: the forest outer → embedded, then per type its embeds,
+    embedded-by and promoted members."""
+    if not hierarchy.types:
+        return f"No embedding in package {hierarchy.package or '(none)'} — {hierarchy.directory}"
+
+    by_name = {t.name: t for t in hierarchy.types}
+    lines = [f"package {hierarchy.package or '(none)'} — {hierarchy.directory}: "
+             f"{len(hierarchy.types)} types, {len(hierarchy.roots)} trees",
+             "", "forest (outer → embedded):"]
+
+    def walk(name: str, written: str, depth: int, path: tuple) -> None:
+        hierarchy_type = by_name.get(name)
+        kind = f" ({hierarchy_type.kind})" if hierarchy_type else ""
+        if name in path:
+            lines.append(f"{'  ' * depth}{written}{kind} (cycle)")
+            return
+        lines.append(f"{'  ' * depth}{written}{kind}")
+        for embed in hierarchy_type.embeds if hierarchy_type else []:
+            if embed.target:
+                walk(embed.target, embed.type, depth + 1, path + (name,))
+
+    for root in hierarchy.roots:
+        walk(root, root, 1, ())
+
+    for hierarchy_type in hierarchy.types:
+        lines += ["", f"{hierarchy_type.name} ({hierarchy_type.kind}) "
+                      f"{hierarchy_type.file}:{hierarchy_type.line}"]
+        embeds = [e.type + ("" if e.target else " (external)") for e in hierarchy_type.embeds]
+        lines.append(f"  embeds: {', '.join(embeds) or '—'}")
+        lines.append(f"  embedded by: {', '.join(hierarchy_type.embedded_by) or '—'}")
+        for member in ("field", "method"):
+            promoted = [p for p in hierarchy_type.promoted if p.member == member]
+            for p in promoted:
+                what = p.signature or p.name
+                note = "  [*T only]" if p.pointer_only else ""
+                lines.append(f"  promoted {member}: {what}  via {'.'.join(p.via)}{note}")
+        if hierarchy_type.ambiguous:
+            lines.append(f"  ambiguous: {', '.join(hierarchy_type.ambiguous)}")
+        if hierarchy_type.unresolved:
+            lines.append(f"  members unknown: {', '.join(hierarchy_type.unresolved)}")
+    return "\n".join(lines)
@@ -0,0 +1,391 @@
+"""
+FILE: imports.py
+
+PROBLEM:
+  "Which packages import which" is the first map a newcomer needs, and the
+  import cycle nobody can find is the usual reason to want it. Per-file
+  import lists don't answer either — Go dependencies are between packages.
+
+SOLUTION:
+  Collect import specs from the syntax tree, aggregate them per containing
+  package (= directory), and classify each edge:
+    std       first path element has no dot ("fmt", "net/http")
+    internal  under the module path from the nearest go.mod
+    external  everything else (third-party modules)
+  Cycles are the strongly connected components of the internal subgraph.
+
+  Per file, import_list() details each spec: path, local name (alias, "."
+  or "_"), and whether the name is used — a best-effort check for the
+  qualifier in selector expressions and qualified types of the file.
+
+SCOPE:
+  ✓ Package import paths derived from go.mod + directory, like the go tool
+  ✓ _test.go files excluded by default (external test packages would loop
+    back onto the package under test)
+  ✗ No build-tag or GOOS/GOARCH filtering — every file counts
+  ✗ Without a go.mod nothing is "internal"; packages are keyed by directory
+  ✗ Usage assumes the package name from the path ("gopkg.in/yaml.v3" →
+    yaml, "go-isatty" → isatty); a differently named package without an
+    alias, or a local shadowing the name, can be misjudged
+"""
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_MODULE_LINE = re.compile(r"^\s*module\s+(\S+)", re.MULTILINE)
+_GO_LINE = re.compile(r"^\s*go\s+(\S+)", re.MULTILINE)
+_MAJOR_VERSION = re.compile(r"^v[0-9]+$")
+
+EDGE_KINDS = ("std", "internal", "external")
+
+
+@dataclass
+class ImportEdge:
+    source: str  # importing package path
+    target: str  # imported path as written
+    kind: str    # one of EDGE_KINDS
+    files: list[str] = field(default_factory=list)  # importing files
+
+
+@dataclass
+class ImportGraph:
+    module: Optional[str]  # module path from go.mod, None if not found
+    packages: list[str] = field(default_factory=list)
+    edges: list[ImportEdge] = field(default_factory=list)
+    cycles: list[list[str]] = field(default_factory=list)  # internal packages, sorted
+
+    def to_dict(self) -> dict:
+        return {
+            "module": self.module,
+            "packages": self.packages,
+            "edges": [{"from": e.source, "to": e.target, "kind": e.kind} for e in self.edges],
+            "cycles": self.cycles,
+        }
+
+
+def find_module(start: Path) -> tuple[Optional[Path], Optional[str]]:
+    """(directory of the nearest go.mod at or above start, its module path)."""
+    current = start if start.is_dir() else start.parent
+    for directory in (current, *current.parents):
+        go_mod = directory / "go.mod"
+        if go_mod.is_file():
+            try:
+                match = _MODULE_LINE.search(go_mod.read_text(errors="replace"))
+            except OSError:
+                return None, None
+            return directory, match.group(1).strip('"') if match else None
+    return None, None
+
+
+def go_version(module_dir: Path) -> Optional[str]:
+    """The go directive of module_dir/go.mod ("1.22"), None without one."""
+    try:
+        match = _GO_LINE.search((module_dir / "go.mod").read_text(errors="replace"))
+    except OSError:
+        return None
+    return match.group(1) if match else None
+
+
+@dataclass
+class GoImport:
+    """One import spec of a file."""
+
+    path: str
+    name: Optional[str]   # alias as written ("." and "_" included), None without
+    line: int
+    used: Optional[bool]  # None for blank and dot imports: no qualifier to look for
+
+    @property
+    def blank(self) -> bool:
+        return self.name == "_"
+
+    @property
+    def dot(self) -> bool:
+        return self.name == "."
+
+    def to_dict(self) -> dict:
+        return {"path": self.path, "name": self.name, "line": self.line,
+                "blank": self.blank, "dot": self.dot, "used": self.used}
+
+
+def default_names(import_path: str) -> set[str]:
+    """Package names an unaliased import is likely referred to by: the last
+    path element without a major-version suffix ("/v2", "yaml.v3"), and for
+    hyphenated elements the usual spellings ("go-isatty" → isatty)."""
+    elements = import_path.split("/")
+    last = elements[-1]
+    if _MAJOR_VERSION.match(last) and len(elements) > 1:
+        last = elements[-2]
+    last = re.sub(r"\.v[0-9]+$", "", last)
+    names = {last, last.removeprefix("go-"), last.split("-")[-1], last.replace("-", "")}
+    return {name for name in names if name.isidentifier()}
+
+
+def qualifiers(go_file: GoFile) -> set[str]:
+    """Identifiers used as package qualifiers outside the import block:
+    the X of X.Sel expressions and of X.Type types."""
+    used = set()
+    for decl in go_file.root.children:
+        if decl.type == "import_declaration":
+            continue
+        for node in syntax.walk(decl):
+            if node.type == "qualified_type":
+                package = node.child_by_field_name("package")
+                if package is not None:
+                    used.add(syntax.node_text(package, go_file.source))
+            elif node.type == "selector_expression":
+                operand = node.child_by_field_name("operand")
+                if operand is not None and operand.type == "identifier":
+                    used.add(syntax.node_text(operand, go_file.source))
+    return used
+
+
+def import_list(go_file: GoFile) -> list[GoImport]:
+    """Import specs of one file with their local names and usage."""
+    used_qualifiers = qualifiers(go_file)
+    imports = []
+    for decl in go_file.root.children:
+        if decl.type != "import_declaration":
+            continue
+        for node in syntax.walk(decl):
+            if node.type != "import_spec":
+                continue
+            path_node = node.child_by_field_name("path")
+            if path_node is None:
+                continue
+            path = syntax.node_text(path_node, go_file.source).strip('"`')
+            name_node = node.child_by_field_name("name")
+            name = syntax.node_text(name_node, go_file.source) if name_node else None
+            if name in ("_", "."):
+                used = None
+            else:
+                used = bool(({name} if name else default_names(path)) & used_qualifiers)
+            imports.append(GoImport(path, name, syntax.line_of(node), used))
+    return imports
+
+
+def import_paths(go_file: GoFile) -> dict[str, str]:
+    """Local package name -> import path for the imports of a file (the
+    alias, else every default_names spelling; blank and dot imports left
+    out)."""
+    names = {}
+    for spec in import_list(go_file):
+        if spec.blank or spec.dot:
+            continue
+        for name in ({spec.name} if spec.name else default_names(spec.path)):
+            names[name] = spec.path
+    return names
+
+
+# Nodes whose "name" / "left" identifiers declare a local name
+_DECLARING = {"parameter_declaration", "variadic_parameter_declaration", "var_spec",
+              "const_spec", "short_var_declaration", "range_clause", "type_switch_statement"}
+
+
+def _declared_locals(node, source: bytes) -> set[str]:
+    """Identifiers a function declares anywhere in it (parameters,
+    receiver, results, :=, var, range, type switch bindings)."""
+    names = set()
+    for inner in syntax.walk(node):
+        if inner.type not in _DECLARING:
+            continue
+        targets = inner.children_by_field_name("name") + \
+            inner.children_by_field_name("left") + inner.children_by_field_name("alias")
+        for target in targets:
+            for ident in syntax.walk(target):
+                if ident.type == "identifier":
+                    names.add(syntax.node_text(ident, source))
+    return names
+
+
+def package_uses(function, source: bytes, known: set[str]) -> list[str]:
+    """Import qualifiers (local names in known) a function's body refers
+    to through X.Sel expressions and X.Type types, sorted. A name the
+    function declares itself (a parameter, a local url := ...) hides the
+    package anywhere in the function."""
+    body = function.child_by_field_name("body")
+    if body is None or not known:
+        return []
+    hidden = _declared_locals(function, source)
+    used = set()
+    for node in syntax.walk(body):
+        if node.type == "qualified_type":
+            package = node.child_by_field_name("package")
+        elif node.type == "selector_expression":
+            package = node.child_by_field_name("operand")
+            if package is not None and package.type != "identifier":
+                continue
+        else:
+            continue
+        name = syntax.node_text(package, source) if package is not None else None
+        if name in known and name not in hidden:
+            used.add(name)
+    return sorted(used)
+
+
+def format_import_list(files: list[GoFile], scope: str, unused_only: bool = False) -> str:
+    """Per file: one line per import — alias, path, line, used / unused."""
+    sections = []
+    total = unused = 0
+    for go_file in files:
+        imports = import_list(go_file)
+        total += len(imports)
+        unused += sum(1 for imp in imports if imp.used is False)
+        shown = [imp for imp in imports if imp.used is False] if unused_only else imports
+        if not shown:
+            continue
+        lines = [f"\n{go_file.path}"]
+        for imp in shown:
+            alias = f"{imp.name} " if imp.name else ""
+            status = ("blank" if imp.blank else "dot" if imp.dot
+                      else "used" if imp.used else "UNUSED")
+            lines.append(f"  {alias}\"{imp.path}\" @{imp.line}  {status}")
+        sections.append("\n".join(lines))
+    if not files:
+        return f"No Go files found in {scope}"
+    header = f"{total} imports in {len(files)} files under {scope}, {unused} unused"
+    if unused_only and not unused:
+        return header
+    return header + "\n" + "\n".join(sections)
+
+
+def file_imports(go_file: GoFile) -> list[str]:
+    """Import paths of one file, in declaration order."""
+    paths = []
+    for decl in go_file.root.children:
+        if decl.type != "import_declaration":
+            continue
+        for node in syntax.walk(decl):
+            if node.type == "import_spec":
+                path_node = node.child_by_field_name("path")
+                if path_node is not None:
+                    paths.append(syntax.node_text(path_node, go_file.source).strip('"`'))
+    return paths
+
+
+def classify(import_path: str, module: Optional[str]) -> str:
+    if module and (import_path == module or import_path.startswith(module + "/")):
+        return "internal"
+    if "." not in import_path.split("/", 1)[0]:
+        return "std"
+    return "external"
+
+
+def _package_path(directory: Path, scope_root: Path,
+                  module_dir: Optional[Path], module: Optional[str]) -> str:
+    if module_dir is not None and module:
+        try:
+            rel = directory.relative_to(module_dir).as_posix()
+            return module if rel == "." else f"{module}/{rel}"
+        except ValueError:
+            pass
+    try:
+        return directory.relative_to(scope_root).as_posix()
+    except ValueError:
+        return directory.as_posix()
+
+
+def _cycles(packages: set[str], edges: list[ImportEdge]) -> list[list[str]]:
+    """Strongly connected components of size > 1 (Tarjan)."""
+    graph: dict[str, list[str]] = {p: [] for p in packages}
+    for edge in edges:
+        if edge.kind == "internal" and edge.target in packages:
+            graph[edge.source].append(edge.target)
+
+    index: dict[str, int] = {}
+    low: dict[str, int] = {}
+    stack: list[str] = []
+    on_stack: set[str] = set()
+    components = []
+
+    def visit(node: str):
+        index[node] = low[node] = len(index)
+        stack.append(node)
+        on_stack.add(node)
+        for target in graph[node]:
+            if target not in index:
+                visit(target)
+                low[node] = min(low[node], low[target])
+            elif target in on_stack:
+                low[node] = min(low[node], index[target])
+        if low[node] == index[node]:
+            component = []
+            while True:
+                member = stack.pop()
+                on_stack.discard(member)
+                component.append(member)
+                if member == node:
+                    break
+            if len(component) > 1:
+                components.append(sorted(component))
+
+    for package in sorted(graph):
+        if package not in index:
+            visit(package)
+    return sorted(components)
+
+
+def build_import_graph(files: list[GoFile], scope_root: str,
+                       include_tests: bool = False) -> ImportGraph:
+    """Package-level import graph of the given files."""
+    root = Path(scope_root).resolve()
+    if root.is_file():
+        root = root.parent
+    module_dir, module = find_module(root)
+    graph = ImportGraph(module=module)
+
+    packages: set[str] = set()
+    edges: dict[tuple[str, str], ImportEdge] = {}
+    for go_file in files:
+        if not include_tests and go_file.path.endswith("_test.go"):
+            continue
+        package = _package_path(Path(go_file.directory), root, module_dir, module)
+        packages.add(package)
+        for import_path in file_imports(go_file):
+            edge = edges.get((package, import_path))
+            if edge is None:
+                edge = edges[(package, import_path)] = ImportEdge(
+                    package, import_path, classify(import_path, module))
+            if go_file.path not in edge.files:
+                edge.files.append(go_file.path)
+
+    graph.packages = sorted(packages)
+    graph.edges = sorted(edges.values(), key=lambda e: (e.source, EDGE_KINDS.index(e.kind), e.target))
+    graph.cycles = _cycles(packages, graph.edges)
+    return graph
+
+
+def format_import_graph(graph: ImportGraph, scope: str) -> str:
+    """Per package: its imports grouped by kind, then any cycles."""
+    if not graph.packages:
+        return f"No Go packages found in {scope}"
+
+    counts = {kind: sum(1 for e in graph.edges if e.kind == kind) for kind in EDGE_KINDS}
+    module = f"module {graph.module}" if graph.module else "no go.mod — nothing classified internal"
+    lines = [f"{len(graph.packages)} packages, {len(graph.edges)} import edges in {scope} "
+             f"({module}; std {counts['std']}, internal {counts['internal']}, "
+             f"external {counts['external']})"]
+
+    by_source: dict[str, list[ImportEdge]] = {}
+    for edge in graph.edges:
+        by_source.setdefault(edge.source, []).append(edge)
+    for package in graph.packages:
+        lines.append(f"\n{package}")
+        package_edges = by_source.get(package, [])
+        if not package_edges:
+            lines.append("  (no imports)")
+        for kind in EDGE_KINDS:
+            targets = [e.target for e in package_edges if e.kind == kind]
+            if targets:
+                lines.append(f"  {kind}: {', '.join(targets)}")
+
+    if graph.cycles:
+        lines.append(f"\nIMPORT CYCLES ({len(graph.cycles)}):")
+        for cycle in graph.cycles:
+            lines.append(f"- {' <-> '.join(cycle)}")
+    return "\n".join(lines)
@@ -0,0 +1,106 @@
+"""
+FILE: inits.py
+
+PROBLEM:
+  init() functions run on import, before main, with nobody calling them:
+  registering drivers, reading environment variables, mutating package
+  state that other inits then depend on. A package may have any number
+  of them — several per file even — and since they share one name a
+  symbol listing shows at most one, and "go to definition" none.
+
+SOLUTION:
+  List every package-level `func init()` declaration separately, in the
+  order Go runs them within a package: files by name (the order the go
+  tool hands them to the compiler), then as they appear in each file.
+  Each carries its position in that order out of the package's total,
+  its length and the functions it calls, so registration side effects
+  and cross-package dependencies are visible without opening it.
+
+SCOPE:
+  ✓ Several inits per file and per package; _test.go files on request
+    (an external _test package is a package of its own)
+  ✗ Package-level variable initializers, which run before any init, are
+    not listed — see list_globals
+  ✗ The order across packages (by import dependency) is not computed
+"""
+
+from collections import defaultdict
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+
+@dataclass
+class InitFunc:
+    file: str
+    line: int
+    end_line: int
+    package: Optional[str]
+    order: int = 0  # 1-based run position within its package
+    of: int = 0     # init functions in the package
+    calls: list[str] = field(default_factory=list)  # callees as written, first call first
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "end_line": self.end_line,
+                "package": self.package, "order": self.order, "of": self.of,
+                "calls": self.calls}
+
+
+def _calls(body, source: bytes) -> list[str]:
+    found = []
+    for node in syntax.walk(body):
+        if node.type != "call_expression":
+            continue
+        callee = syntax.normalized_text(node.child_by_field_name("function"), source)
+        if callee not in found:
+            found.append(callee)
+    return found
+
+
+def find_inits(files: list[GoFile], include_tests: bool = False) -> list[InitFunc]:
+    """Every init declaration in file then line order, numbered in run
+    order per package (directory and package name)."""
+    found = []
+    for go_file in sorted(files, key=lambda f: (f.directory, Path(f.path).name)):
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        source = go_file.source
+        for decl in go_file.root.children:
+            if decl.type != "function_declaration":
+                continue
+            name = decl.child_by_field_name("name")
+            if name is None or syntax.node_text(name, source) != "init":
+                continue
+            body = decl.child_by_field_name("body")
+            found.append(InitFunc(go_file.path, syntax.line_of(decl), decl.end_point[0] + 1,
+                                  go_file.package,
+                                  calls=_calls(body, source) if body is not None else []))
+
+    by_package = defaultdict(list)
+    for init in found:
+        by_package[(str(Path(init.file).parent), init.package)].append(init)
+    for inits in by_package.values():
+        for order, init in enumerate(inits, 1):
+            init.order, init.of = order, len(inits)
+    found.sort(key=lambda i: (i.file, i.line))
+    return found
+
+
+def format_inits(inits: list[InitFunc], scope: str) -> str:
+    """Tally, then per file "@line-end init #order/of: calls ..."."""
+    if not inits:
+        return f"No init functions found in {scope}"
+
+    packages = {(str(Path(i.file).parent), i.package) for i in inits}
+    lines = [f"{len(inits)} init functions in {scope} across {len(packages)} packages"]
+    current_file = None
+    for init in inits:
+        if init.file != current_file:
+            current_file = init.file
+            lines.append(f"\n{current_file} (package {init.package or '?'})")
+        calls = f": calls {', '.join(init.calls)}" if init.calls else ": no calls"
+        lines.append(f"- @{init.line}-{init.end_line} init #{init.order} of {init.of}{calls}")
+    return "\n".join(lines)
@@ -0,0 +1,234 @@
+"""
+FILE: interfaces.py
+
+PROBLEM:
+  A package's abstractions are its interfaces, but the structure scan shows
+  an interface as one line — the method set an implementation must provide
+  is only visible by reading the source.
+
+SOLUTION:
+  Read interface types straight from the syntax tree: every method with its
+  reconstructed signature, and embedded interfaces by the name they are
+  referenced with (io.Reader stays io.Reader — expanding it would need type
+  resolution across packages we don't have).
+
+  Implementers are found structurally: a concrete type satisfies an
+  interface when its method set (value receivers for T, value + pointer
+  receivers for *T) contains every method by name and parameter/result
+  types. Embeds declared in scope are expanded for this check.
+
+SCOPE:
+  ✓ Package-level named interfaces, grouped declarations included
+  ✓ Type-set elements (int | ~string) of constraint interfaces listed as embeds
+  ✗ No type checking — types are compared as written
+  ✗ Embedded interfaces from outside the scope (io.Reader) can't be checked
+"""
+
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+# Interface element node types across tree-sitter-go grammar versions
+_METHOD_ELEMS = ("method_elem", "method_spec")
+_EMBED_ELEMS = ("type_elem", "constraint_elem", "interface_type_name",
+                "type_identifier", "qualified_type", "generic_type")
+
+
+@dataclass
+class InterfaceMethod:
+    name: str
+    signature: str  # "Read(p []byte) (n int, err error)"
+    key: tuple      # syntax.method_key — name + parameter/result types
+
+
+@dataclass
+class InterfaceInfo:
+    name: str
+    file: str
+    package: Optional[str]
+    directory: str
+    start_line: int
+    end_line: int
+    methods: list[InterfaceMethod] = field(default_factory=list)
+    embeds: list[str] = field(default_factory=list)  # referenced names, unexpanded
+
+
+def list_interfaces(files: list[GoFile]) -> list[InterfaceInfo]:
+    """All package-level interface types of the given files, in file order."""
+    found = []
+    for go_file in files:
+        for spec in syntax.type_specs(go_file.root):
+            type_node = spec.child_by_field_name("type")
+            name_node = spec.child_by_field_name("name")
+            if type_node is None or name_node is None or type_node.type != "interface_type":
+                continue
+            info = InterfaceInfo(
+                name=syntax.node_text(name_node, go_file.source),
+                file=go_file.path,
+                package=go_file.package,
+                directory=go_file.directory,
+                start_line=syntax.line_of(spec),
+                end_line=spec.end_point[0] + 1,
+            )
+            for elem in type_node.named_children:
+                if elem.type in _METHOD_ELEMS:
+                    method_name = syntax.node_text(elem.child_by_field_name("name"),
+                                                   go_file.source)
+                    info.methods.append(InterfaceMethod(
+                        name=method_name,
+                        signature=syntax.format_header(elem, method_name, go_file.source),
+                        key=syntax.method_key(elem, method_name, go_file.source),
+                    ))
+                elif elem.type in _EMBED_ELEMS:
+                    info.embeds.append(syntax.normalized_text(elem, go_file.source))
+            found.append(info)
+    return found
+
+
+def format_interfaces(interfaces: list[InterfaceInfo], scope: str) -> str:
+    """Per file: each interface with its embeds and method signatures."""
+    if not interfaces:
+        return f"No interfaces found in {scope}"
+
+    lines = [f"{len(interfaces)} interfaces in {scope}"]
+    current_file = None
+    for info in interfaces:
+        if info.file != current_file:
+            current_file = info.file
+            lines.append(f"\n{current_file}")
+        member_count = len(info.methods)
+        lines.append(f"- {info.name} @{info.start_line}-{info.end_line}"
+                     f"  ({member_count} method{'s' if member_count != 1 else ''})")
+        if info.embeds:
+            lines.append(f"    embeds: {', '.join(info.embeds)}")
+        for method in info.methods:
+            lines.append(f"    {method.signature}")
+    return "\n".join(lines)
+
+
+# ── Implementers ─────────────────────────────────────────────────────────────
+
+@dataclass
+class Implementer:
+    type_name: str
+    file: str
+    start_line: int
+    pointer_only: bool  # satisfied by *T only (some methods have pointer receivers)
+
+
+@dataclass
+class ImplementersResult:
+    interface: Optional[InterfaceInfo]
+    implementers: list[Implementer] = field(default_factory=list)
+    unresolved_embeds: list[str] = field(default_factory=list)
+    candidates: list[InterfaceInfo] = field(default_factory=list)  # same-name interfaces
+
+
+def _required_methods(info: InterfaceInfo, by_name: dict[str, list[InterfaceInfo]],
+                      unresolved: list[str], seen: set[tuple]) -> dict[tuple, InterfaceMethod]:
+    """Method set of an interface with embeds expanded where they are
+    declared in scope (same package first). Returns key -> method."""
+    required = {m.key: m for m in info.methods}
+    for embed in info.embeds:
+        candidates = by_name.get(embed, [])
+        target = next((c for c in candidates if c.directory == info.directory),
+                      candidates[0] if candidates else None)
+        if target is None:
+            unresolved.append(embed)
+            continue
+        if (target.directory, target.name) in seen:
+            continue
+        seen.add((target.directory, target.name))
+        required.update(_required_methods(target, by_name, unresolved, seen))
+    return required
+
+
+def find_implementers(files: list[GoFile], interface_name: str) -> ImplementersResult:
+    """Concrete types whose method sets structurally satisfy the interface.
+
+    Methods match on name plus parameter and result types as written —
+    no type checking, so aliases, dot-imports or same-named types from
+    different packages can mislead. Value-receiver methods belong to both
+    T and *T; pointer-receiver methods only to *T. Embedded interfaces
+    declared outside the scope can't be checked and are reported.
+    """
+    interfaces = list_interfaces(files)
+    by_name: dict[str, list[InterfaceInfo]] = {}
+    for info in interfaces:
+        by_name.setdefault(info.name, []).append(info)
+
+    short_name = interface_name.rsplit(".", 1)[-1]
+    matches = by_name.get(short_name, [])
+    if not matches:
+        return ImplementersResult(interface=None)
+    target = matches[0]
+    result = ImplementersResult(interface=target, candidates=matches[1:])
+    required = _required_methods(target, by_name, result.unresolved_embeds,
+                                 {(target.directory, target.name)})
+
+    # Concrete named types per package, and their methods by receiver kind
+    # keyed by (directory, package, type name)
+    types: dict[tuple, tuple[GoFile, int]] = {}
+    value_methods: dict[tuple, set[tuple]] = {}
+    pointer_methods: dict[tuple, set[tuple]] = {}
+    for go_file in files:
+        for spec in syntax.type_specs(go_file.root):
+            type_node = spec.child_by_field_name("type")
+            name_node = spec.child_by_field_name("name")
+            if name_node is None or type_node is None or type_node.type == "interface_type":
+                continue
+            key = (go_file.directory, go_file.package or "",
+                   syntax.node_text(name_node, go_file.source))
+            types.setdefault(key, (go_file, syntax.line_of(spec)))
+        for decl in go_file.root.children:
+            if decl.type != "method_declaration":
+                continue
+            receiver_type, is_pointer = syntax.receiver(decl, go_file.source)
+            name_node = decl.child_by_field_name("name")
+            if receiver_type is None or name_node is None:
+                continue
+            method_name = syntax.node_text(name_node, go_file.source)
+            bucket = pointer_methods if is_pointer else value_methods
+            bucket.setdefault((go_file.directory, go_file.package or "", receiver_type),
+                              set()).add(
+                syntax.method_key(decl, method_name, go_file.source))
+
+    needed = set(required)
+    for key, (go_file, line) in sorted(types.items(), key=lambda item: item[0]):
+        values = value_methods.get(key, set())
+        if needed <= values:
+            pointer_only = False
+        elif needed <= values | pointer_methods.get(key, set()):
+            pointer_only = True
+        else:
+            continue
+        result.implementers.append(Implementer(
+            type_name=key[2], file=go_file.path, start_line=line, pointer_only=pointer_only))
+    return result
+
+
+def format_implementers(result: ImplementersResult, interface_name: str) -> str:
+    if result.interface is None:
+        return f"No interface named {interface_name} in scope"
+
+    info = result.interface
+    lines = [f"{info.name} ({info.file}@{info.start_line}): "
+             f"{len(result.implementers)} implementers"]
+    for impl in result.implementers:
+        receiver_note = "  (pointer receiver methods — only the pointer satisfies it)" \
+            if impl.pointer_only else ""
+        type_label = f"*{impl.type_name}" if impl.pointer_only else impl.type_name
+        lines.append(f"- {type_label} {impl.file}@{impl.start_line}{receiver_note}")
+    notes = []
+    if result.unresolved_embeds:
+        notes.append(f"embedded interfaces outside the scope were not checked: "
+                     f"{', '.join(result.unresolved_embeds)}")
+    if result.candidates:
+        notes.append(f"{len(result.candidates)} other interfaces named {info.name} "
+                     f"exist in scope; checked the one above")
+    notes.append("structural match on method names and parameter/result types as "
+                 "written — no type checking (aliases and same-named types can mislead)")
+    lines.extend(f"note: {n}" for n in notes)
+    return "\n".join(lines)
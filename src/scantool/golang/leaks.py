@@ -0,0 +1,196 @@
+"""
+FILE: leaks.py
+
+PROBLEM:
+  An exported function that takes or returns an unexported type is an
+  API another package can call but not fully use: it can't declare a
+  variable of the result type, write the parameter's type, or name it in
+  its own signatures (`func New() *client`). It compiles, go vet says
+  nothing, and golint's "exported func returns unexported type" only
+  looks at bare results.
+
+SOLUTION:
+  For every exported function, and exported method of an exported type,
+  in a package's non-test files: take each parameter and result type,
+  unwrap it to the named types inside — pointers, slices, arrays, maps
+  (key and value), channels, parenthesized types, generic types and
+  their type arguments, variadic parameters, func-typed parameters'
+  own parameters and results — and report the unexported names the
+  package declares itself. The function's own type parameters and
+  builtins (string, error, any) are not declared types and never match.
+
+SCOPE:
+  ✓ One entry per leaking parameter or result, with the declaration of
+    the leaked type
+  ✓ Packages are directories; types declared in any file of the package
+  ✗ Aliases (type id = string) are not declared types: an alias of an
+    exported type is nameable through it
+  ✗ Unexported field types of exported structs are not checked
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+# Wrappers unwrapped to their element types (no fields: every named child)
+_UNWRAP = {"pointer_type": (), "slice_type": ("element",), "array_type": ("element",),
+           "implicit_length_array_type": ("element",), "map_type": ("key", "value"),
+           "channel_type": ("value",), "parenthesized_type": (), "type_elem": ()}
+
+
+@dataclass
+class LeakedType:
+    file: str
+    line: int
+    function: str  # Name, or Type.Name for methods
+    position: str  # "parameter name", "parameter 2", "result", "result 2"
+    type_text: str  # the parameter or result type as written
+    leaked: str  # the unexported type name
+    declared_file: str
+    declared_line: int
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "function": self.function,
+                "position": self.position, "type": self.type_text, "leaked": self.leaked,
+                "declared_file": self.declared_file, "declared_line": self.declared_line}
+
+
+def _named_types(type_node, source: bytes) -> list[str]:
+    """Unqualified named types a type expression mentions, in order."""
+    if type_node is None:
+        return []
+    kind = type_node.type
+    if kind == "type_identifier":
+        return [syntax.node_text(type_node, source)]
+    if kind == "generic_type":
+        found = _named_types(type_node.child_by_field_name("type"), source)
+        arguments = type_node.child_by_field_name("type_arguments")
+        for argument in arguments.named_children if arguments is not None else []:
+            found.extend(_named_types(argument, source))
+        return found
+    if kind == "function_type":
+        found = []
+        for part in ("parameters", "result"):
+            child = type_node.child_by_field_name(part)
+            if child is None:
+                continue
+            if child.type != "parameter_list":
+                found.extend(_named_types(child, source))
+                continue
+            for param in child.named_children:
+                found.extend(_named_types(param.child_by_field_name("type"), source))
+        return found
+    if kind in _UNWRAP:
+        fields = _UNWRAP[kind]
+        children = [type_node.child_by_field_name(f) for f in fields] if fields \
+            else type_node.named_children
+        found = []
+        for child in children:
+            found.extend(_named_types(child, source))
+        return found
+    return []  # qualified types, struct / interface literals
+
+
+def _slots(function, source: bytes) -> list[tuple[str, object]]:
+    """(position label, type node) per parameter and result."""
+    slots = []
+    for part, label in (("parameters", "parameter"), ("result", "result")):
+        node = function.child_by_field_name(part)
+        if node is None:
+            continue
+        if node.type != "parameter_list":
+            slots.append((label, node))
+            continue
+        params = [p for p in node.named_children
+                  if p.type in ("parameter_declaration", "variadic_parameter_declaration")]
+        total = sum(max(1, len(p.children_by_field_name("name"))) for p in params)
+        index = 0
+        for param in params:
+            names = [syntax.node_text(n, source) for n in param.children_by_field_name("name")]
+            for name in names or [None]:
+                index += 1
+                position = f"{label} {name}" if name else \
+                    label if total == 1 else f"{label} {index}"
+                slots.append((position, param.child_by_field_name("type")))
+    return slots
+
+
+def _type_params(function, source: bytes) -> set[str]:
+    params = function.child_by_field_name("type_parameters")
+    if params is None:
+        return set()
+    return {syntax.node_text(name, source)
+            for decl in params.named_children
+            for name in decl.children_by_field_name("name")}
+
+
+def find_leaked_unexported(files: list[GoFile]) -> list[LeakedType]:
+    """Exported functions and methods whose signature names an unexported
+    type of their own package, in file then line order."""
+    packages: dict[tuple, list[GoFile]] = {}
+    for go_file in files:
+        if not go_file.path.endswith("_test.go"):
+            packages.setdefault((go_file.directory, go_file.package), []).append(go_file)
+
+    found = []
+    for package_files in packages.values():
+        declared: dict[str, tuple[str, int]] = {}
+        for go_file in package_files:
+            for spec in syntax.type_specs(go_file.root):
+                name = syntax.node_text(spec.child_by_field_name("name"), go_file.source)
+                if not name[:1].isupper():
+                    declared.setdefault(name, (go_file.path, syntax.line_of(spec)))
+        if not declared:
+            continue
+        for go_file in package_files:
+            source = go_file.source
+            for decl in go_file.root.children:
+                if decl.type not in ("function_declaration", "method_declaration"):
+                    continue
+                name_node = decl.child_by_field_name("name")
+                name = syntax.node_text(name_node, source) if name_node is not None else ""
+                if not name[:1].isupper():
+                    continue
+                function = name
+                if decl.type == "method_declaration":
+                    receiver_type, _ = syntax.receiver(decl, source)
+                    if not receiver_type or not receiver_type[:1].isupper():
+                        continue
+                    function = f"{receiver_type}.{name}"
+                hidden = _type_params(decl, source)
+                for position, type_node in _slots(decl, source):
+                    leaked = []
+                    for type_name in _named_types(type_node, source):
+                        if type_name in declared and type_name not in hidden \
+                                and type_name not in leaked:
+                            leaked.append(type_name)
+                    for type_name in leaked:
+                        declared_file, declared_line = declared[type_name]
+                        found.append(LeakedType(
+                            go_file.path, syntax.line_of(decl), function, position,
+                            syntax.normalized_text(type_node, source), type_name,
+                            declared_file, declared_line))
+    found.sort(key=lambda leak: (leak.file, leak.line))
+    return found
+
+
+def format_leaked_unexported(leaks: list[LeakedType], scope: str) -> str:
+    """Tally, then per file "@line Function: position type → leaked
+    (declared at)"."""
+    if not leaks:
+        return f"No exported functions with unexported types in their signatures in {scope}"
+
+    functions = {(leak.file, leak.function) for leak in leaks}
+    types = {(leak.declared_file, leak.leaked) for leak in leaks}
+    lines = [f"{len(functions)} exported functions and methods expose {len(types)} "
+             f"unexported types in {scope}"]
+    current_file: Optional[str] = None
+    for leak in leaks:
+        if leak.file != current_file:
+            current_file = leak.file
+            lines.append(f"\n{current_file}")
+        lines.append(f"- @{leak.line} {leak.function}: {leak.position} {leak.type_text}"
+                     f" → {leak.leaked} ({leak.declared_file}@{leak.declared_line})")
+    return "\n".join(lines)
@@ -0,0 +1,153 @@
+"""
+FILE: literals.py
+
+PROBLEM:
+  Secret audits and i18n sweeps both start from "every string in the
+  code". grep for quotes also hits comments and can't tell a backtick
+  string from an escaped "\\x41" that is really "A".
+
+SOLUTION:
+  Collect interpreted ("...") and raw (`...`) string literal nodes from
+  the syntax tree — comments are separate nodes, so they never match — with
+  file, line and the declaration they sit in (function, Type.Method, or
+  const/var name). Interpreted strings are decoded per the Go spec
+  (\\n, \\x41, \\101, \\u00e9, ...); raw strings are taken verbatim minus
+  carriage returns, as the compiler does. An optional regex filters on the
+  decoded value.
+
+SCOPE:
+  ✓ Import paths and struct tags left out — they are not text
+  ✓ Literals at package level carry their const/var name, closures their
+    enclosing function
+  ✗ Rune literals ('x') and strings built at runtime (concatenation of
+    constants is seen as the separate literals)
+"""
+
+import json
+import re
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+LITERAL_KINDS = {"interpreted_string_literal": "interpreted", "raw_string_literal": "raw"}
+
+_SIMPLE_ESCAPES = {"a": 0x07, "b": 0x08, "f": 0x0C, "n": 0x0A, "r": 0x0D, "t": 0x09,
+                   "v": 0x0B, "\\": 0x5C, "'": 0x27, '"': 0x22}
+
+_ESCAPE = re.compile(rb"\\(?:([abfnrtv\\'\"])|x([0-9A-Fa-f]{2})|([0-7]{3})"
+                     rb"|u([0-9A-Fa-f]{4})|U([0-9A-Fa-f]{8}))")
+
+_DISPLAY_LIMIT = 120
+
+
+@dataclass
+class StringLiteral:
+    file: str
+    line: int
+    kind: str                        # "interpreted" or "raw"
+    value: str                       # decoded
+    container: Optional[str] = None  # enclosing function / Type.Method / const or var
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "kind": self.kind,
+                "value": self.value, "container": self.container}
+
+
+def decode_interpreted(body: bytes) -> str:
+    """Value of an interpreted string body (between the quotes). \\x and
+    octal escapes are single bytes, \\u and \\U code points — Go strings
+    are bytes, decoded here as UTF-8."""
+    out = bytearray()
+    position = 0
+    for match in _ESCAPE.finditer(body):
+        out += body[position:match.start()]
+        simple, hex_byte, octal, short, long = match.groups()
+        if simple:
+            out.append(_SIMPLE_ESCAPES[simple.decode()])
+        elif hex_byte or octal:
+            out.append(int(hex_byte, 16) if hex_byte else int(octal, 8))
+        else:
+            code_point = int(short or long, 16)
+            out += (chr(code_point) if code_point <= 0x10FFFF else "�").encode(
+                "utf-8", errors="replace")
+        position = match.end()
+    out += body[position:]
+    return out.decode("utf-8", errors="replace")
+
+
+def string_value(node, source: bytes) -> str:
+    """Decoded value of a string literal node."""
+    body = source[node.start_byte + 1:node.end_byte - 1]
+    if node.type == "raw_string_literal":
+        return body.replace(b"\r", b"").decode("utf-8", errors="replace")
+    return decode_interpreted(body)
+
+
+def _container(node, source: bytes) -> Optional[str]:
+    """Nearest enclosing declaration name, innermost first."""
+    current = node.parent
+    while current is not None:
+        if current.type in ("function_declaration", "method_declaration"):
+            name = syntax.node_text(current.child_by_field_name("name"), source)
+            if current.type == "method_declaration":
+                receiver_type, _ = syntax.receiver(current, source)
+                return f"{receiver_type}.{name}" if receiver_type else name
+            return name
+        if current.type in ("const_spec", "var_spec"):
+            names = [syntax.node_text(n, source) for n in current.children_by_field_name("name")]
+            return ", ".join(names) or None
+        current = current.parent
+    return None
+
+
+def _is_metadata(node) -> bool:
+    """Import paths and struct tags."""
+    parent = node.parent
+    return parent is not None and (
+        parent.type == "import_spec"
+        or (parent.type == "field_declaration" and parent.child_by_field_name("tag") == node))
+
+
+def extract_strings(files: list[GoFile], pattern: Optional[str] = None) -> list[StringLiteral]:
+    """String literals of the given files in file then source order;
+    pattern is a regex searched in the decoded value (re.error if invalid)."""
+    matcher = re.compile(pattern) if pattern else None
+    literals = []
+    for go_file in files:
+        for node in syntax.walk(go_file.root):
+            if node.type not in LITERAL_KINDS or _is_metadata(node):
+                continue
+            value = string_value(node, go_file.source)
+            if matcher is not None and not matcher.search(value):
+                continue
+            literals.append(StringLiteral(
+                file=go_file.path,
+                line=syntax.line_of(node),
+                kind=LITERAL_KINDS[node.type],
+                value=value,
+                container=_container(node, go_file.source),
+            ))
+    return literals
+
+
+def format_strings(literals: list[StringLiteral], scope: str) -> str:
+    """Per file: "@line container: "value"" lines, raw strings marked, long
+    values cut (JSON output keeps them whole)."""
+    if not literals:
+        return f"No string literals found in {scope}"
+
+    raw_count = sum(1 for literal in literals if literal.kind == "raw")
+    lines = [f"{len(literals)} string literals in {scope} ({raw_count} raw)"]
+    current_file = None
+    for literal in literals:
+        if literal.file != current_file:
+            current_file = literal.file
+            lines.append(f"\n{current_file}")
+        shown = literal.value if len(literal.value) <= _DISPLAY_LIMIT \
+            else literal.value[:_DISPLAY_LIMIT] + "…"
+        marker = " raw" if literal.kind == "raw" else ""
+        container = f" {literal.container}" if literal.container else ""
+        lines.append(f"- @{literal.line}{marker}{container}: {json.dumps(shown, ensure_ascii=False)}")
+    return "\n".join(lines)
@@ -0,0 +1,215 @@
+"""
+FILE: naming.py
+
+PROBLEM:
+  Two naming rules every Go reviewer applies by hand (Effective Go, the
+  Go Code Review Comments): an exported name is read with its package in
+  front, so user.UserService stutters — user.Service says the same; and
+  a type's methods all name their receiver the same short way, never a
+  generic this or self. Both are checks across many declarations (every
+  method of a type, spread over files), tedious to do by eye.
+
+SOLUTION:
+  Per package (a directory's files that declare the same package name):
+    stutter   exported top-level types, functions, consts and vars whose
+              name starts with the package name, case-insensitively,
+              followed by an upper-case letter (user.UserService, not
+              user.User or user.Username); the suggestion is the rest
+    receiver  methods grouped by receiver base type; a type is reported
+              when its methods use more than one receiver name, or a
+              generic one (this, self). The most used name is the
+              suggestion, the first used name on a tie
+  Unnamed and blank (_) receivers say nothing about naming and are not
+  compared.
+
+SCOPE:
+  ✓ Methods of one type across the package's files, pointer and value
+    receivers alike
+  ✗ Package main is not checked for stutter — nobody imports it
+  ✗ Receiver names are not checked against the type name (s for Store)
+"""
+
+from collections import Counter, defaultdict
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+GENERIC_RECEIVERS = ("this", "self")
+
+_DECLARATION_KINDS = {"type_declaration": "type", "const_declaration": "const",
+                      "var_declaration": "var"}
+
+
+@dataclass
+class Stutter:
+    name: str
+    kind: str  # type, function, const, var
+    package: str
+    file: str
+    line: int
+
+    @property
+    def suggestion(self) -> str:
+        return self.name[len(self.package):]
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "kind": self.kind, "package": self.package,
+                "file": self.file, "line": self.line, "suggestion": self.suggestion}
+
+
+@dataclass
+class ReceiverUse:
+    method: str
+    receiver: str  # identifier as written
+    file: str
+    line: int
+
+    def to_dict(self) -> dict:
+        return {"method": self.method, "receiver": self.receiver,
+                "file": self.file, "line": self.line}
+
+
+@dataclass
+class ReceiverNames:
+    """One type whose methods name their receiver inconsistently or
+    generically."""
+    type_name: str
+    package: Optional[str]
+    directory: str
+    uses: list[ReceiverUse] = field(default_factory=list)  # file then line order
+
+    @property
+    def counts(self) -> Counter:
+        return Counter(use.receiver for use in self.uses)
+
+    @property
+    def suggestion(self) -> str:
+        counts = Counter(use.receiver for use in self.uses
+                         if use.receiver not in GENERIC_RECEIVERS)
+        if not counts:
+            return self.type_name[:1].lower()
+        best = max(counts.values())
+        return next(use.receiver for use in self.uses if counts.get(use.receiver) == best)
+
+    @property
+    def generic(self) -> list[str]:
+        return [name for name in self.counts if name in GENERIC_RECEIVERS]
+
+    def to_dict(self) -> dict:
+        return {"type": self.type_name, "package": self.package, "directory": self.directory,
+                "names": dict(self.counts), "suggestion": self.suggestion,
+                "generic": self.generic, "methods": [u.to_dict() for u in self.uses]}
+
+
+@dataclass
+class NamingReport:
+    stutters: list[Stutter] = field(default_factory=list)
+    receivers: list[ReceiverNames] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {"stutters": [s.to_dict() for s in self.stutters],
+                "receivers": [r.to_dict() for r in self.receivers]}
+
+
+def stutters(package: Optional[str], name: str) -> bool:
+    """Whether name, read as package.name, repeats the package name."""
+    if not package or not name[:1].isupper() or len(name) <= len(package):
+        return False
+    return name.lower().startswith(package.lower()) and name[len(package)].isupper()
+
+
+def _declared_names(decl) -> list:
+    """Name nodes a top-level declaration introduces (grouped specs too,
+    some grammar versions wrap them in a *_spec_list)."""
+    if decl.type == "function_declaration":
+        name = decl.child_by_field_name("name")
+        return [name] if name is not None else []
+    specs = list(decl.named_children)
+    for spec_list in [c for c in specs if c.type.endswith("_spec_list")]:
+        specs.extend(spec_list.named_children)
+    names = []
+    for spec in specs:
+        if spec.type in ("type_spec", "type_alias"):
+            name = spec.child_by_field_name("name")
+            names.extend([name] if name is not None else [])
+        elif spec.type in ("const_spec", "var_spec"):
+            names.extend(spec.children_by_field_name("name"))
+    return names
+
+
+def _receiver_name(method, source: bytes) -> Optional[str]:
+    receiver_list = method.child_by_field_name("receiver")
+    param = next((c for c in receiver_list.named_children
+                  if c.type == "parameter_declaration"), None) if receiver_list else None
+    name = param.child_by_field_name("name") if param is not None else None
+    text = syntax.node_text(name, source) if name is not None else None
+    return text if text and text != "_" else None
+
+
+def naming_report(files: list[GoFile], include_tests: bool = False) -> NamingReport:
+    """Stutter candidates in file then line order, then the types with
+    inconsistent or generic receiver names by package and type name."""
+    report = NamingReport()
+    methods: dict[tuple, list[ReceiverUse]] = defaultdict(list)
+    for go_file in sorted(files, key=lambda f: f.path):
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        source = go_file.source
+        for decl in go_file.root.children:
+            if decl.type == "method_declaration":
+                receiver_type, _ = syntax.receiver(decl, source)
+                receiver = _receiver_name(decl, source)
+                name = decl.child_by_field_name("name")
+                if receiver_type and receiver and name is not None:
+                    key = (go_file.directory, go_file.package, receiver_type)
+                    methods[key].append(ReceiverUse(syntax.node_text(name, source), receiver,
+                                                    go_file.path, syntax.line_of(decl)))
+                continue
+            kind = "function" if decl.type == "function_declaration" else \
+                _DECLARATION_KINDS.get(decl.type)
+            if kind is None or go_file.package == "main":
+                continue
+            for name_node in _declared_names(decl):
+                name = syntax.node_text(name_node, source)
+                if stutters(go_file.package, name):
+                    report.stutters.append(Stutter(name, kind, go_file.package, go_file.path,
+                                                   syntax.line_of(name_node)))
+
+    for (directory, package, type_name), uses in sorted(
+            methods.items(), key=lambda item: (item[0][0], item[0][1] or "", item[0][2])):
+        group = ReceiverNames(type_name, package, directory, uses)
+        if len(group.counts) > 1 or group.generic:
+            report.receivers.append(group)
+    return report
+
+
+def format_naming_report(report: NamingReport, scope: str) -> str:
+    """Tally, the stutters "@line kind pkg.Name → pkg.Rest" per file, then
+    per type its receiver names and the methods using each."""
+    if not report.stutters and not report.receivers:
+        return f"No naming issues found in {scope}"
+
+    lines = [f"Naming in {scope}: {len(report.stutters)} stutter candidates, "
+             f"{len(report.receivers)} types with inconsistent receiver names"]
+    if report.stutters:
+        lines.append("\nStutter (the name repeats its package):")
+        current_file = None
+        for stutter in report.stutters:
+            if stutter.file != current_file:
+                current_file = stutter.file
+                lines.append(f"{current_file}")
+            lines.append(f"- @{stutter.line} {stutter.kind} {stutter.package}.{stutter.name}"
+                         f" → {stutter.package}.{stutter.suggestion}")
+    if report.receivers:
+        lines.append("\nReceiver names:")
+        for group in report.receivers:
+            names = ", ".join(f"{name} ×{count}" for name, count in group.counts.items())
+            generic = f"; generic: {', '.join(group.generic)}" if group.generic else ""
+            lines.append(f"- {group.package or '?'}.{group.type_name} ({group.directory}): "
+                         f"{names} — use {group.suggestion}{generic}")
+            for use in group.uses:
+                if use.receiver != group.suggestion:
+                    lines.append(f"    {use.file}@{use.line} ({use.receiver}) {use.method}")
+    return "\n".join(lines)
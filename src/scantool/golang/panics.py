@@ -0,0 +1,158 @@
+"""
+FILE: panics.py
+
+PROBLEM:
+  "Which functions can take the process down?" — `panic(...)` unwinds the
+  stack and can be recovered by a deferred recover(); `os.Exit` and
+  `log.Fatal*` end the process on the spot, deferred calls skipped. In a
+  library both are suspect, the second always: an importer can't get
+  control back. grep for "panic(" also hits comments, strings and
+  `recover()` handlers.
+
+SOLUTION:
+  Walk the call expressions and match them against two configurable
+  sets of callees:
+    panic  builtin panic, log.Panic / Panicf / Panicln
+    exit   os.Exit, log.Fatal / Fatalf / Fatalln
+  Callees are written as import path + "." + function ("os.Exit",
+  "github.com/sirupsen/logrus.Fatal"); a call's qualifier is resolved
+  through the file's imports, aliases included. Each call carries the
+  enclosing function, the package (exits in package main are usually
+  fine) and, for panics, whether that function defers a recover().
+
+SCOPE:
+  ✓ Functions, methods, closures and package-level initializers
+  ✗ Method calls on logger values (logger.Fatal(...)) are not matched —
+    only package-level functions named in the sets
+  ✗ A local variable shadowing "panic" or an import name is not noticed
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .imports import import_paths
+from .syntax import GoFile
+
+CALL_KINDS = ("panic", "exit")
+DEFAULT_PANIC_CALLS = ("panic", "log.Panic", "log.Panicf", "log.Panicln")
+DEFAULT_EXIT_CALLS = ("os.Exit", "log.Fatal", "log.Fatalf", "log.Fatalln")
+
+_ARGUMENT_LIMIT = 60
+
+
+@dataclass
+class PanicCall:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    package: Optional[str]
+    kind: str      # one of CALL_KINDS
+    callee: str    # as configured: "panic", "os.Exit", ...
+    arguments: str  # as written, on one line
+    recovered: bool = False  # panic in a function that defers a recover()
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "line": self.line, "function": self.function,
+                "package": self.package, "kind": self.kind, "callee": self.callee,
+                "arguments": self.arguments}
+        if self.kind == "panic":
+            data["recovered"] = self.recovered
+        return data
+
+
+def _callee(call, go_file: GoFile, qualifiers: dict[str, str]) -> Optional[str]:
+    """"panic", "os.Exit", "github.com/x/y.F" — the function a call names,
+    None for method calls and anything else."""
+    function = call.child_by_field_name("function")
+    if function is None:
+        return None
+    if function.type == "identifier":
+        return syntax.node_text(function, go_file.source)
+    if function.type != "selector_expression":
+        return None
+    operand = function.child_by_field_name("operand")
+    field = function.child_by_field_name("field")
+    if operand is None or field is None or operand.type != "identifier":
+        return None
+    path = qualifiers.get(syntax.node_text(operand, go_file.source))
+    return f"{path}.{syntax.node_text(field, go_file.source)}" if path else None
+
+
+def _defers_recover(call, source: bytes) -> bool:
+    """The innermost function around call defers a call that recovers."""
+    current = call.parent
+    while current is not None and current.type not in (
+            "function_declaration", "method_declaration", "func_literal"):
+        current = current.parent
+    body = current.child_by_field_name("body") if current is not None else None
+    if body is None:
+        return False
+    for node in syntax.walk(body):
+        if node.type != "defer_statement":
+            continue
+        for inner in syntax.walk(node):
+            if inner.type == "call_expression":
+                function = inner.child_by_field_name("function")
+                if function is not None and syntax.node_text(function, source) == "recover":
+                    return True
+    return False
+
+
+def find_panics(files: list[GoFile], include_tests: bool = False,
+                panic_calls: Optional[list[str]] = None,
+                exit_calls: Optional[list[str]] = None,
+                exclude_main: bool = False) -> list[PanicCall]:
+    """panic and exit calls in file then line order. The call sets default
+    to DEFAULT_PANIC_CALLS / DEFAULT_EXIT_CALLS; exclude_main leaves out
+    files of package main."""
+    kinds = {callee: "panic" for callee in (
+        DEFAULT_PANIC_CALLS if panic_calls is None else panic_calls)}
+    kinds.update({callee: "exit" for callee in (
+        DEFAULT_EXIT_CALLS if exit_calls is None else exit_calls)})
+    found = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        if exclude_main and go_file.package == "main":
+            continue
+        qualifiers = import_paths(go_file)
+        for node in syntax.walk(go_file.root):
+            if node.type != "call_expression":
+                continue
+            callee = _callee(node, go_file, qualifiers)
+            kind = kinds.get(callee) if callee is not None else None
+            if kind is None:
+                continue
+            arguments = node.child_by_field_name("arguments")
+            text = syntax.normalized_text(arguments, go_file.source)[1:-1] if arguments else ""
+            found.append(PanicCall(
+                go_file.path, syntax.line_of(node),
+                syntax.enclosing_function(node, go_file.source), go_file.package,
+                kind, callee, text,
+                recovered=kind == "panic" and _defers_recover(node, go_file.source)))
+    found.sort(key=lambda c: (c.file, c.line))
+    return found
+
+
+def format_panics(calls: list[PanicCall], scope: str) -> str:
+    """Tally, then per file "@line function: callee(args)" with the kind."""
+    if not calls:
+        return f"No panic or exit calls found in {scope}"
+
+    panics = sum(1 for c in calls if c.kind == "panic")
+    exits = len(calls) - panics
+    library_exits = sum(1 for c in calls if c.kind == "exit" and c.package != "main")
+    lines = [f"{len(calls)} panic/exit calls in {scope} ({panics} panic, {exits} exit; "
+             f"{library_exits} exits outside package main)"]
+    current_file = None
+    for call in calls:
+        if call.file != current_file:
+            current_file = call.file
+            lines.append(f"\n{current_file} (package {call.package or '?'})")
+        arguments = call.arguments if len(call.arguments) <= _ARGUMENT_LIMIT \
+            else call.arguments[:_ARGUMENT_LIMIT] + "…"
+        note = "exit — deferred calls don't run" if call.kind == "exit" else \
+            "panic, recovered in the function" if call.recovered else "panic"
+        lines.append(f"- @{call.line} {call.function}: {call.callee}({arguments})  [{note}]")
+    return "\n".join(lines)
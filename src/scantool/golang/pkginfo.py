@@ -0,0 +1,105 @@
+"""
+FILE: pkginfo.py
+
+PROBLEM:
+  The first question in an unfamiliar Go tree is "what am I looking at":
+  the package a directory holds, the module it belongs to and the path
+  other code imports it by. The answer is spread over the package
+  clauses of the files and a go.mod somewhere above.
+
+SOLUTION:
+  package_info(files, directory) reads the package clauses of the .go
+  files directly in the directory (the package is what most non-test
+  files say, as in summarize_package; every name found is listed, the
+  external test package too) and the nearest go.mod at or above it: its
+  module path and go directive. The import path is the module path plus
+  the directory relative to the go.mod, like the go tool derives it.
+
+SCOPE:
+  ✓ Modules, nested modules (the nearest go.mod wins), directories
+    without Go files (module fields only)
+  ✗ No go.mod (GOPATH-style trees, loose files): module, go version and
+    import path are left empty rather than guessed from $GOPATH
+  ✗ go.work workspaces and replace directives are not read
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from .imports import find_module, go_version
+from .syntax import GoFile
+
+
+@dataclass
+class PackageInfo:
+    directory: str
+    package: Optional[str] = None  # the package of the directory, None without Go files
+    packages: list[str] = field(default_factory=list)  # every name found, most files first
+    files: int = 0
+    test_files: int = 0
+    module: Optional[str] = None       # module path from the nearest go.mod
+    module_dir: Optional[str] = None   # directory of that go.mod
+    go_version: Optional[str] = None   # its go directive
+    import_path: Optional[str] = None  # module + directory relative to module_dir
+
+    def to_dict(self) -> dict:
+        return {"directory": self.directory, "package": self.package,
+                "packages": self.packages, "files": self.files,
+                "test_files": self.test_files, "module": self.module,
+                "module_dir": self.module_dir, "go_version": self.go_version,
+                "import_path": self.import_path}
+
+
+def package_info(files: list[GoFile], directory: str) -> PackageInfo:
+    """Package name(s) of the files directly in directory, with the module
+    and import path from the nearest go.mod (empty without one)."""
+    target = Path(directory).resolve()
+    info = PackageInfo(directory=str(target))
+
+    counts: dict[str, int] = {}
+    own: dict[str, int] = {}  # without external test packages: candidates for package
+    for go_file in files:
+        if go_file.directory != str(target):
+            continue
+        is_test = go_file.path.endswith("_test.go")
+        if is_test:
+            info.test_files += 1
+        else:
+            info.files += 1
+        name = go_file.package or "(none)"
+        counts[name] = counts.get(name, 0) + 1
+        if not (is_test and name.endswith("_test")):
+            own[name] = own.get(name, 0) + 1
+    info.packages = sorted(counts, key=lambda n: (-counts[n], n))
+    if own:
+        info.package = max(own, key=lambda n: (own[n], n != "main", n))
+
+    module_dir, module = find_module(target)
+    if module_dir is not None:
+        info.module_dir = str(module_dir)
+        info.go_version = go_version(module_dir)
+    if module_dir is not None and module:
+        info.module = module
+        rel = target.relative_to(module_dir).as_posix()
+        info.import_path = module if rel == "." else f"{module}/{rel}"
+    return info
+
+
+def format_package_info(info: PackageInfo) -> str:
+    """package, import path, module and file counts, one per line."""
+    if info.package is None:
+        lines = [f"No Go files directly in {info.directory}"]
+    else:
+        lines = [f"package {info.package} — {info.directory}",
+                 f"{info.files} files (+{info.test_files} test)"
+                 + (f"; packages: {', '.join(info.packages)}" if len(info.packages) > 1 else "")]
+    if info.module:
+        version = f" (go {info.go_version})" if info.go_version else ""
+        lines.append(f"module {info.module}{version} — {info.module_dir}/go.mod")
+        lines.append(f"import path: {info.import_path}")
+    elif info.module_dir:
+        lines.append(f"go.mod without a module line — {info.module_dir}/go.mod")
+    else:
+        lines.append("no go.mod at or above the directory (GOPATH-style or loose files)")
+    return "\n".join(lines)
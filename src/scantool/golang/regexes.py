@@ -0,0 +1,355 @@
+"""
+FILE: regexes.py
+
+PROBLEM:
+  Input validation lives in regular expressions, and reviewing it means
+  finding every one: `regexp.MustCompile(...)` at package level panics at
+  init on a bad pattern, inside a function only when that code path runs —
+  and a pattern built at runtime can't be checked by reading it at all.
+
+SOLUTION:
+  Walk the calls of the regexp package's compile functions (Compile,
+  MustCompile and their POSIX variants), through whatever name the file
+  imports "regexp" as, and report per call: the function, the location
+  and enclosing function, and the pattern —
+    static   a string literal, a concatenation of literals, or a
+             package-level string constant: the value is decoded and,
+             optionally, checked against RE2 syntax (re2_error)
+    dynamic  anything else (variables, fmt.Sprintf, parameters): shown as
+             written; a MustCompile of one is a panic waiting for input
+  Must* calls panic on a bad pattern, the others return an error.
+
+  re2_error mirrors the checks of Go's regexp/syntax parser and returns
+  its error text ("missing closing ): `(ab`"): unbalanced parentheses and
+  brackets, Perl syntax RE2 doesn't support (lookaround, atomic groups),
+  backreferences and other bad escapes, nested repetition, missing
+  repetition arguments, repeat counts over 1000, inverted ranges, unknown
+  [:classes:].
+
+SCOPE:
+  ✓ Package-level and function-local calls, aliased imports of regexp
+  ✗ \\p{Name} accepts any name — Unicode scripts are not checked; POSIX
+    calls are checked with the Perl-mode rules
+  ✗ Constants from other packages and typed constant expressions beyond
+    string concatenation count as dynamic
+"""
+
+import re
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .imports import import_list
+from .literals import LITERAL_KINDS, string_value
+from .syntax import GoFile
+
+COMPILE_FUNCTIONS = ("MustCompile", "Compile", "MustCompilePOSIX", "CompilePOSIX")
+
+_MAX_REPEAT = 1000  # regexp/syntax's limit on {n,m} counts
+_REPEAT = re.compile(r"\{(\d+)(,(\d*))?\}")
+_NAMED = re.compile(r"\(\?P?<([^>]*)>")
+_FLAGS = re.compile(r"\(\?(-?[imsU]+|[imsU]+-[imsU]+)?([:)])")
+_POSIX_CLASSES = {"alnum", "alpha", "ascii", "blank", "cntrl", "digit", "graph",
+                  "lower", "print", "punct", "space", "upper", "word", "xdigit"}
+_UNICODE_CLASS = re.compile(r"p(?:\{\^?[A-Za-z_]+\}|[A-Za-z])", re.IGNORECASE)
+_HEX = re.compile(r"x(?:[0-9A-Fa-f]{2}|\{[0-9A-Fa-f]{1,8}\})")
+_OCTAL = re.compile(r"0[0-7]{0,2}|[1-7][0-7]{1,2}")
+_SIMPLE_ESCAPES = set("afnrtvAzbBdDsSwW")
+_CLASS_ESCAPES = set("afnrtvdDsSwW")
+
+
+@dataclass
+class RegexCall:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    call: str      # one of COMPILE_FUNCTIONS
+    expression: str  # the pattern argument as written
+    pattern: Optional[str] = None  # decoded value, None when dynamic
+    error: Optional[str] = None    # RE2 syntax error of pattern, when checked
+
+    @property
+    def panics(self) -> bool:
+        return self.call.startswith("Must")
+
+    @property
+    def dynamic(self) -> bool:
+        return self.pattern is None
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "line": self.line, "function": self.function,
+                "call": self.call, "panics": self.panics, "dynamic": self.dynamic,
+                "expression": self.expression, "pattern": self.pattern}
+        if self.error is not None:
+            data["error"] = self.error
+        return data
+
+
+# ── RE2 syntax check ─────────────────────────────────────────────────────────
+
+def _escape_end(pattern: str, i: int, in_class: bool) -> tuple[int, Optional[str]]:
+    """(index after the escape at pattern[i] == "\\", error or None)."""
+    if i + 1 >= len(pattern):
+        return len(pattern), "trailing backslash at end of expression: ``"
+    c = pattern[i + 1]
+    rest = pattern[i + 1:]
+    if c.isascii() and not c.isalnum():
+        return i + 2, None
+    if c in ("0", "1", "2", "3", "4", "5", "6", "7") and (match := _OCTAL.match(rest)):
+        return i + 1 + match.end(), None
+    if c == "x":
+        match = _HEX.match(rest)
+        if match is None:
+            return i + 2, f"invalid escape sequence: `{pattern[i:i + 4]}`"
+        return i + 1 + match.end(), None
+    if c in "pP":
+        match = _UNICODE_CLASS.match(rest)
+        if match is None:
+            return i + 2, f"invalid character class range: `{pattern[i:i + 3]}`"
+        return i + 1 + match.end(), None
+    if c == "Q" and not in_class:
+        end = pattern.find("\\E", i + 2)
+        return (len(pattern) if end < 0 else end + 2), None
+    if c in (_CLASS_ESCAPES if in_class else _SIMPLE_ESCAPES):
+        return i + 2, None
+    return i + 2, f"invalid escape sequence: `\\{c}`"
+
+
+def _class_end(pattern: str, start: int) -> tuple[int, Optional[str]]:
+    """(index after the class opened at pattern[start] == "[", error)."""
+    i = start + 1
+    if i < len(pattern) and pattern[i] == "^":
+        i += 1
+    first = True
+    previous: Optional[str] = None  # last single character, for ranges
+    while i < len(pattern) and (pattern[i] != "]" or first):
+        first = False
+        if pattern.startswith("[:", i):
+            end = pattern.find(":]", i + 2)
+            if end >= 0:
+                name = pattern[i + 2:end].removeprefix("^")
+                if name not in _POSIX_CLASSES:
+                    return end + 2, f"invalid character class range: `{pattern[i:end + 2]}`"
+                i, previous = end + 2, None
+                continue
+        if pattern[i] == "\\":
+            end, error = _escape_end(pattern, i, in_class=True)
+            if error:
+                return end, error
+            escaped = pattern[i + 1:end]
+            previous = escaped if len(escaped) == 1 and not escaped.isalnum() else None
+            i = end
+        elif pattern[i] == "-" and previous is not None and i + 1 < len(pattern) \
+                and pattern[i + 1] != "]":
+            high = pattern[i + 1]
+            if high == "\\":
+                end, error = _escape_end(pattern, i + 1, in_class=True)
+                if error:
+                    return end, error
+                high = pattern[i + 2:end]
+                i = end
+            else:
+                i += 2
+            if len(high) == 1 and high < previous:
+                return i, f"invalid character class range: `{previous}-{high}`"
+            previous = None
+        else:
+            previous = pattern[i]
+            i += 1
+    if i >= len(pattern):
+        return i, f"missing closing ]: `{pattern[start:]}`"
+    return i + 1, None
+
+
+def re2_error(pattern: str) -> Optional[str]:
+    """The error Go's regexp.Compile would report for pattern (without the
+    "error parsing regexp: " prefix), None when it compiles."""
+    depth = 0
+    atom = False        # an operand a repetition can apply to precedes
+    repeat = "none"     # none, repeat, lazy: what the last operator was
+    repeat_start = 0    # where the last operator began
+    i = 0
+    while i < len(pattern):
+        c = pattern[i]
+        if c in "*+?" or (c == "{" and _REPEAT.match(pattern, i)):
+            if c == "{":
+                match = _REPEAT.match(pattern, i)
+                low = int(match.group(1))
+                high = low if match.group(2) is None else \
+                    int(match.group(3)) if match.group(3) else None
+                if low > _MAX_REPEAT or (high is not None and (high > _MAX_REPEAT or high < low)):
+                    return f"invalid repeat count: `{match.group(0)}`"
+                end = match.end()
+            else:
+                end = i + 1
+            if c == "?" and repeat == "repeat":
+                repeat, i = "lazy", i + 1
+                continue
+            if repeat != "none":
+                return f"invalid nested repetition operator: `{pattern[repeat_start:end]}`"
+            if not atom:
+                return f"missing argument to repetition operator: `{pattern[i:end]}`"
+            repeat, repeat_start, i = "repeat", i, end
+            continue
+        repeat = "none"
+        if c == "(":
+            if pattern.startswith("(?", i):
+                flags = _FLAGS.match(pattern, i)
+                if pattern.startswith(("(?P", "(?<"), i):  # (?<=...) lands here too, as in Go
+                    named = _NAMED.match(pattern, i)
+                    if named is None:
+                        return f"invalid named capture: `{pattern[i:]}`"
+                    if not named.group(1) or not all(ch.isalnum() or ch == "_"
+                                                     for ch in named.group(1)):
+                        return f"invalid named capture: `{named.group(0)}`"
+                    i = named.end()
+                elif pattern.startswith("(?:", i):
+                    i += 3
+                elif flags is not None and (flags.group(1) or flags.group(2) == ":"):
+                    i = flags.end()
+                    if flags.group(2) == ")":  # (?i) sets flags, opens nothing
+                        atom = False
+                        continue
+                else:
+                    return f"invalid or unsupported Perl syntax: `{pattern[i:i + 3]}`"
+            else:
+                i += 1
+            depth += 1
+            atom = False
+            continue
+        if c == ")":
+            if depth == 0:
+                return f"unexpected ): `{pattern}`"
+            depth -= 1
+            atom, i = True, i + 1
+            continue
+        if c == "|":
+            atom, i = False, i + 1
+            continue
+        if c == "[":
+            i, error = _class_end(pattern, i)
+            if error:
+                return error
+        elif c == "\\":
+            i, error = _escape_end(pattern, i, in_class=False)
+            if error:
+                return error
+        else:
+            i += 1
+        atom = True
+    if depth:
+        return f"missing closing ): `{pattern}`"
+    return None
+
+
+# ── Calls ────────────────────────────────────────────────────────────────────
+
+def _string_constants(files: list[GoFile]) -> dict[tuple[str, str], str]:
+    """(directory, name) -> value of package-level constants whose value is
+    one string literal."""
+    constants = {}
+    for go_file in files:
+        for decl in go_file.root.children:
+            if decl.type != "const_declaration":
+                continue
+            for spec in syntax.walk(decl):
+                if spec.type != "const_spec":
+                    continue
+                names = spec.children_by_field_name("name")
+                value_list = spec.child_by_field_name("value")
+                values = [v for v in (value_list.named_children if value_list else [])
+                          if v.type != "comment"]
+                for name, value in zip(names, values):
+                    if value.type in LITERAL_KINDS:
+                        constants[(go_file.directory, syntax.node_text(name, go_file.source))] = \
+                            string_value(value, go_file.source)
+    return constants
+
+
+def _static_value(node, go_file: GoFile, constants: dict) -> Optional[str]:
+    """Value of a literal, literal + literal, or string constant; None otherwise."""
+    if node.type in LITERAL_KINDS:
+        return string_value(node, go_file.source)
+    if node.type == "parenthesized_expression" and node.named_children:
+        return _static_value(node.named_children[0], go_file, constants)
+    if node.type == "identifier":
+        return constants.get((go_file.directory, syntax.node_text(node, go_file.source)))
+    if node.type == "binary_expression":
+        operator = node.child_by_field_name("operator")
+        if operator is None or syntax.node_text(operator, go_file.source) != "+":
+            return None
+        left = _static_value(node.child_by_field_name("left"), go_file, constants)
+        right = _static_value(node.child_by_field_name("right"), go_file, constants)
+        return left + right if left is not None and right is not None else None
+    return None
+
+
+def _regexp_names(go_file: GoFile) -> set[str]:
+    return {spec.name or "regexp" for spec in import_list(go_file)
+            if spec.path == "regexp" and not spec.blank and not spec.dot}
+
+
+def find_regexes(files: list[GoFile], include_tests: bool = False,
+                 check: bool = True) -> list[RegexCall]:
+    """regexp compile calls in file then line order; check runs re2_error
+    on every static pattern."""
+    files = [f for f in files if include_tests or not f.path.endswith("_test.go")]
+    constants = _string_constants(files)
+    calls = []
+    for go_file in files:
+        names = _regexp_names(go_file)
+        if not names:
+            continue
+        for node in syntax.walk(go_file.root):
+            if node.type != "call_expression":
+                continue
+            function = node.child_by_field_name("function")
+            if function is None or function.type != "selector_expression":
+                continue
+            operand = function.child_by_field_name("operand")
+            field = function.child_by_field_name("field")
+            if operand is None or field is None or operand.type != "identifier" \
+                    or syntax.node_text(operand, go_file.source) not in names:
+                continue
+            call = syntax.node_text(field, go_file.source)
+            arguments = node.child_by_field_name("arguments")
+            args = [a for a in (arguments.named_children if arguments else [])
+                    if a.type != "comment"]
+            if call not in COMPILE_FUNCTIONS or not args:
+                continue
+            pattern = _static_value(args[0], go_file, constants)
+            calls.append(RegexCall(
+                go_file.path, syntax.line_of(node),
+                syntax.enclosing_function(node, go_file.source), call,
+                syntax.normalized_text(args[0], go_file.source), pattern,
+                re2_error(pattern) if check and pattern is not None else None))
+    calls.sort(key=lambda c: (c.file, c.line))
+    return calls
+
+
+def format_regexes(calls: list[RegexCall], scope: str) -> str:
+    """Tally, then per file "@line function: Call `pattern`" with flags."""
+    if not calls:
+        return f"No regexp compile calls found in {scope}"
+
+    must = sum(1 for c in calls if c.panics)
+    invalid = sum(1 for c in calls if c.error)
+    dynamic = sum(1 for c in calls if c.dynamic)
+    lines = [f"{len(calls)} regexes in {scope} ({must} Must*, {len(calls) - must} returning "
+             f"an error; {invalid} invalid, {dynamic} dynamic)"]
+    current_file = None
+    for call in calls:
+        if call.file != current_file:
+            current_file = call.file
+            lines.append(f"\n{current_file}")
+        shown = f"`{call.pattern}`" if call.pattern is not None else f"({call.expression})"
+        flags = []
+        if call.error:
+            flags.append(f"invalid: {call.error}" + (" — panics" if call.panics else ""))
+        elif call.dynamic and call.panics:
+            flags.append("dynamic — panics on a bad pattern")
+        elif call.dynamic:
+            flags.append("dynamic")
+        marker = f"  [{'; '.join(flags)}]" if flags else ""
+        lines.append(f"- @{call.line} {call.function}: {call.call} {shown}{marker}")
+    return "\n".join(lines)
@@ -0,0 +1,229 @@
+"""
+FILE: shadowing.py
+
+PROBLEM:
+  `x, err := f()` inside an if or a loop body declares a new err that
+  hides the outer one: the outer err keeps its old value, and a later
+  `return err` returns nil while the real error is lost. The compiler
+  accepts it, vet doesn't report it by default, and the two lines look
+  like plain assignments.
+
+SOLUTION:
+  Per function, track Go's block scopes while walking the body in
+  source order, and report each declaration whose name is already
+  declared in an enclosing scope of the same function, with both
+  locations. Scopes follow the spec:
+    function   receiver, parameters and named results share the scope of
+               the body's outer block
+    block      every { ... } block
+    statement  if, for, switch, type switch and select open a scope for
+               their init clause (if x := f(); ..., for i := 0; ...,
+               for k, v := range ...); else branches nest inside it
+    clause     each case / default clause of a switch, type switch or
+               select is its own scope (case v := <-ch:)
+    closure    a func literal's parameters open a scope inside the
+               enclosing function, so closures shadow outer locals too
+  Declarations: :=, var and const specs, parameters and named results,
+  range variables, the type switch alias and select receives. A := only
+  declares the names not already in its own scope — the others are
+  assignments, as in Go. A declaration's scope starts after it, so in
+  `x := x + 1` the right side still reads the outer x.
+  The self copies `v := v` and `switch v := v.(type)` are idioms rather
+  than mistakes; they are marked and left out unless asked for.
+
+SCOPE:
+  ✓ Function and method bodies, closures, package-level func literals
+  ✗ Package-level names (globals, imports, builtins like len) are not
+    tracked: only shadowing between scopes of one function is reported
+  ✗ Labels, and types declared inside functions, are not declarations
+    here
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_FUNCTIONS = ("function_declaration", "method_declaration", "func_literal")
+_STATEMENT_SCOPES = ("if_statement", "for_statement", "expression_switch_statement",
+                     "type_switch_statement", "select_statement")
+_CLAUSES = ("expression_case", "default_case", "type_case", "communication_case")
+
+
+@dataclass
+class Shadow:
+    file: str
+    line: int
+    function: str  # enclosing function, see syntax.enclosing_function
+    name: str
+    kind: str        # the inner declaration: ":=", "var", "const", "param", "range", ...
+    outer_line: int
+    outer_kind: str  # how the shadowed name was declared
+    self_copy: bool = False  # v := v / switch v := v.(type)
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "function": self.function,
+                "name": self.name, "kind": self.kind, "outer_line": self.outer_line,
+                "outer_kind": self.outer_kind, "self_copy": self.self_copy}
+
+
+class _Walker:
+    """Walks one file, keeping the stack of scopes of the current function
+    (name -> (line, kind)); empty at package level."""
+
+    def __init__(self, go_file: GoFile):
+        self.go_file = go_file
+        self.source = go_file.source
+        self.scopes: list[dict[str, tuple[int, str]]] = []
+        self.found: list[Shadow] = []
+
+    def text(self, node) -> str:
+        return syntax.node_text(node, self.source)
+
+    def declare(self, name_node, kind: str, self_copy: bool = False, reuse: bool = False):
+        """Declare a name in the innermost scope, recording a shadow when an
+        outer scope has it. reuse: an existing name in the innermost scope is
+        assigned, not redeclared (:= semantics)."""
+        if not self.scopes or name_node is None or name_node.type != "identifier":
+            return
+        name = self.text(name_node)
+        if name == "_":
+            return
+        current = self.scopes[-1]
+        if reuse and name in current:
+            return
+        outer = next((scope[name] for scope in reversed(self.scopes[:-1]) if name in scope), None)
+        if outer is not None:
+            self.found.append(Shadow(
+                self.go_file.path, syntax.line_of(name_node),
+                syntax.enclosing_function(name_node, self.source), name, kind,
+                outer[0], outer[1], self_copy=self_copy))
+        current[name] = (syntax.line_of(name_node), kind)
+
+    def visit_children(self, node):
+        for child in node.children:
+            self.visit(child)
+
+    def scoped(self, node, visit=None):
+        self.scopes.append({})
+        try:
+            (visit or self.visit_children)(node)
+        finally:
+            self.scopes.pop()
+
+    def parameters(self, node, kind: str):
+        if node is None or node.type != "parameter_list":
+            return
+        for parameter in node.named_children:
+            if parameter.type in ("parameter_declaration", "variadic_parameter_declaration"):
+                for name in parameter.children_by_field_name("name"):
+                    self.declare(name, kind)
+
+    def function(self, node):
+        def body(_):
+            self.parameters(node.child_by_field_name("receiver"), "receiver")
+            self.parameters(node.child_by_field_name("parameters"), "param")
+            self.parameters(node.child_by_field_name("result"), "result")
+            block = node.child_by_field_name("body")
+            if block is not None:  # same scope as the parameters
+                self.visit_children(block)
+
+        self.scoped(node, body)
+
+    def short_var(self, node, kind: str = ":="):
+        left = node.child_by_field_name("left")
+        right = node.child_by_field_name("right")
+        if right is not None:
+            self.visit(right)
+        if left is None:
+            return
+        names = [n for n in left.named_children if n.type != "comment"] \
+            if left.type == "expression_list" else [left]
+        values = [v for v in right.named_children if v.type != "comment"] \
+            if right is not None and right.type == "expression_list" else [right]
+        for index, name in enumerate(names):
+            copy = len(names) == len(values) and values[index] is not None and \
+                self.text(values[index]) == self.text(name)
+            self.declare(name, kind, self_copy=copy, reuse=True)
+
+    def spec(self, node, kind: str):
+        value = node.child_by_field_name("value")
+        if value is not None:
+            self.visit(value)
+        for name in node.children_by_field_name("name"):
+            self.declare(name, kind)
+
+    def type_switch(self, node):
+        def body(_):
+            alias = node.child_by_field_name("alias")
+            value = node.child_by_field_name("value")
+            for child in node.children:
+                if child == alias:
+                    continue
+                self.visit(child)
+                if child == value and alias is not None:
+                    for name in alias.named_children or [alias]:
+                        self.declare(name, "type switch",
+                                     self_copy=self.text(name) == self.text(value))
+        self.scoped(node, body)
+
+    def visit(self, node):
+        node_type = node.type
+        if node_type in _FUNCTIONS:
+            self.function(node)
+        elif node_type == "block" or node_type in _CLAUSES:
+            self.scoped(node)
+        elif node_type == "type_switch_statement":
+            self.type_switch(node)
+        elif node_type in _STATEMENT_SCOPES:
+            self.scoped(node)
+        elif node_type == "short_var_declaration":
+            self.short_var(node)
+        elif node_type in ("range_clause", "receive_statement") and \
+                any(c.type == ":=" for c in node.children):
+            self.short_var(node, "range" if node_type == "range_clause" else "select")
+        elif node_type == "var_spec":
+            self.spec(node, "var")
+        elif node_type == "const_spec":
+            self.spec(node, "const")
+        else:
+            self.visit_children(node)
+
+
+def find_shadowing(files: list[GoFile], include_tests: bool = False,
+                   names: Optional[list[str]] = None,
+                   include_self_copies: bool = False) -> list[Shadow]:
+    """Declarations shadowing a variable of an enclosing scope of the same
+    function, in file then line order. names keeps only those names
+    (["err"]); self copies (v := v) are left out unless asked for."""
+    wanted = set(names) if names else None
+    found = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        walker = _Walker(go_file)
+        walker.visit(go_file.root)
+        found.extend(s for s in walker.found
+                     if (wanted is None or s.name in wanted)
+                     and (include_self_copies or not s.self_copy))
+    found.sort(key=lambda s: (s.file, s.line))
+    return found
+
+
+def format_shadowing(shadows: list[Shadow], scope: str) -> str:
+    """Tally, then per file "@line function: name (kind) shadows @line"."""
+    if not shadows:
+        return f"No shadowed variables found in {scope}"
+
+    errors = sum(1 for s in shadows if s.name == "err")
+    lines = [f"{len(shadows)} shadowing declarations in {scope} ({errors} of err)"]
+    current_file = None
+    for shadow in shadows:
+        if shadow.file != current_file:
+            current_file = shadow.file
+            lines.append(f"\n{current_file}")
+        note = "  [self copy]" if shadow.self_copy else ""
+        lines.append(f"- @{shadow.line} {shadow.function}: {shadow.name} ({shadow.kind}) "
+                     f"shadows {shadow.outer_kind} @{shadow.outer_line}{note}")
+    return "\n".join(lines)
@@ -0,0 +1,180 @@
+"""
+FILE: structtags.py
+
+PROBLEM:
+  encoding/json, sqlx, validator and friends read struct tags through
+  reflect.StructTag.Lookup, which gives up silently at the first syntax
+  error: `json: "id"` (space after the colon), `json:"id" db:"user_id`
+  (unbalanced quote) or `json:"id",db:"x"` compile fine and the framework
+  just never sees the later keys. A typo in the key (`jsno:"id"`) is
+  equally silent.
+
+SOLUTION:
+  Take every struct field's tag literal from the syntax tree, decode it
+  (raw or interpreted string) and parse it the way reflect.StructTag
+  does: optional spaces, a key of printable non-space characters other
+  than : and ", a colon, a double-quoted value valid for
+  strconv.Unquote. The first syntax error ends the parse as it ends
+  Lookup — reported, with everything after it unreachable. Also
+  reported, as go vet's structtag check does: pairs not separated by a
+  space (reflect still reads them), and keys given twice (Lookup only
+  finds the first). With an allow-list of keys, any other key is
+  reported too.
+
+SCOPE:
+  ✓ Named and anonymous structs anywhere, embedded fields
+  ✗ Values are not checked against each framework's own grammar
+    (json:"name,omitempty" options, validate rules)
+"""
+
+import re
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .literals import decode_interpreted, string_value
+from .syntax import GoFile
+
+# Escapes strconv.Unquote accepts in a double-quoted string
+_VALID_ESCAPE = re.compile(r"\\(?:[abfnrtv\\\"]|x[0-9A-Fa-f]{2}|[0-7]{3}"
+                           r"|u[0-9A-Fa-f]{4}|U[0-9A-Fa-f]{8})")
+
+
+@dataclass
+class StructTag:
+    file: str
+    line: int
+    struct: str  # type name, or the enclosing function for anonymous structs
+    field: str   # field names; the type name for an embedded field
+    tag: str     # literal as written, quotes included
+    pairs: list[tuple[str, str]] = field(default_factory=list)  # key, decoded value
+    problems: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "struct": self.struct,
+                "field": self.field, "tag": self.tag,
+                "pairs": [{"key": key, "value": value} for key, value in self.pairs],
+                "problems": self.problems}
+
+
+def _unquote(quoted: str) -> tuple[Optional[str], Optional[str]]:
+    """(value, None) for a valid double-quoted Go string, else (None, why)."""
+    body = quoted[1:-1]
+    if "\n" in body:
+        return None, "newline in value"
+    position = 0
+    while True:
+        backslash = body.find("\\", position)
+        if backslash < 0:
+            break
+        match = _VALID_ESCAPE.match(body, backslash)
+        if match is None:
+            return None, f"invalid escape {body[backslash:backslash + 2]!r}"
+        position = match.end()
+    return decode_interpreted(body.encode("utf-8")), None
+
+
+def parse_struct_tag(tag: str) -> tuple[list[tuple[str, str]], list[str]]:
+    """(key, value) pairs of a decoded tag as reflect.StructTag.Lookup
+    sees them, and the problems found; parsing stops at a syntax error,
+    like Lookup."""
+    pairs, problems, seen = [], [], set()
+    rest = tag
+    while rest:
+        rest = rest.lstrip(" ")
+        if not rest:
+            break
+        i = 0
+        while i < len(rest) and rest[i] > " " and rest[i] not in ':"' and rest[i] != "\x7f":
+            i += 1
+        key = rest[:i]
+        if i == 0:
+            found = {":": "colon without a key", '"': "value without a key"}.get(
+                rest[0], f"invalid character {rest[0]!r} in key")
+            problems.append(f"{found} at {rest[:20]!r}")
+            break
+        if i >= len(rest) or rest[i] != ":":
+            problems.append(f"missing colon after key {key}")
+            break
+        if i + 1 >= len(rest) or rest[i + 1] != '"':
+            problems.append(f"value of {key} is not quoted right after the colon")
+            break
+        rest = rest[i + 1:]
+        j = 1
+        while j < len(rest) and rest[j] != '"':
+            if rest[j] == "\\":
+                j += 1
+            j += 1
+        if j >= len(rest):
+            problems.append(f"unbalanced quotes: value of {key} is never closed")
+            break
+        quoted, rest = rest[:j + 1], rest[j + 1:]
+        value, error = _unquote(quoted)
+        if error is not None:
+            problems.append(f"bad value for {key}: {error}")
+            break
+        if key in seen:
+            problems.append(f"duplicate key {key} (Lookup finds the first)")
+        seen.add(key)
+        pairs.append((key, value))
+        if rest and rest[0] != " ":
+            problems.append(f"no space after {key}:{quoted}")
+    return pairs, problems
+
+
+def _owner(node, source: bytes) -> str:
+    current = node.parent
+    while current is not None:
+        if current.type == "type_spec":
+            return syntax.node_text(current.child_by_field_name("name"), source)
+        if current.type in ("function_declaration", "method_declaration", "func_literal"):
+            return syntax.enclosing_function(node, source)
+        current = current.parent
+    return "(package level)"
+
+
+def find_struct_tags(files: list[GoFile], include_tests: bool = False,
+                     known_keys: Optional[list[str]] = None) -> list[StructTag]:
+    """Every tagged struct field with its parsed pairs and problems, in
+    file then line order. known_keys: an allow-list, other keys are
+    problems too."""
+    allowed = set(known_keys) if known_keys else None
+    found = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        source = go_file.source
+        for node in syntax.walk(go_file.root):
+            if node.type != "field_declaration":
+                continue
+            tag_node = node.child_by_field_name("tag")
+            if tag_node is None:
+                continue
+            names = [syntax.node_text(n, source) for n in node.children_by_field_name("name")]
+            label = ", ".join(names) or \
+                syntax.base_type_name(node.child_by_field_name("type"), source) or ""
+            pairs, problems = parse_struct_tag(string_value(tag_node, source))
+            if allowed is not None:
+                problems.extend(f"unknown key {key}" for key, _ in pairs if key not in allowed)
+            found.append(StructTag(go_file.path, syntax.line_of(tag_node), _owner(node, source),
+                                   label, syntax.node_text(tag_node, source), pairs, problems))
+    found.sort(key=lambda t: (t.file, t.line))
+    return found
+
+
+def format_struct_tags(tags: list[StructTag], scope: str, include_valid: bool = False) -> str:
+    """Tally, then per file "@line Struct.Field tag: problems"; valid tags
+    only with include_valid."""
+    if not tags:
+        return f"No struct tags found in {scope}"
+
+    bad = [t for t in tags if t.problems]
+    lines = [f"{len(tags)} struct tags in {scope}, {len(bad)} with problems"]
+    current_file = None
+    for tag in tags if include_valid else bad:
+        if tag.file != current_file:
+            current_file = tag.file
+            lines.append(f"\n{current_file}")
+        status = "; ".join(tag.problems) if tag.problems else "ok"
+        lines.append(f"- @{tag.line} {tag.struct}.{tag.field} {tag.tag}: {status}")
+    return "\n".join(lines)
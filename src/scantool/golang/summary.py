@@ -0,0 +1,196 @@
+"""
+FILE: summary.py
+
+PROBLEM:
+  A Go package is a directory, not a file: its types' methods are spread
+  over several files next to each other, and per-file scans never show
+  "Store has 12 methods" or how much of the package is exported.
+
+SOLUTION:
+  Merge the syntax trees of the Go files directly in one directory: count
+  exported vs unexported top-level symbols, attach every method to its
+  receiver type whichever file declares it, and take the package doc from
+  doc.go or else the first file that has one. More than one package name
+  (a stray `package main`) is reported as a warning — the go tool refuses
+  to build such a directory. External test packages (pkg_test in
+  _test.go files) are legitimate and don't count.
+
+SCOPE:
+  ✓ Functions, methods, types, constants and variables (grouped specs too)
+  ✓ _test.go files counted separately, their symbols only on request
+  ✗ No build-tag filtering — platform-specific files all count
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+
+@dataclass
+class TypeSummary:
+    name: str
+    kind: str  # struct, interface, func, alias, other
+    file: str
+    start_line: int
+    exported: bool
+    methods: list[str] = field(default_factory=list)  # names, declaration order
+
+
+@dataclass
+class PackageSummary:
+    directory: str
+    package: Optional[str]
+    files: list[str] = field(default_factory=list)
+    test_files: list[str] = field(default_factory=list)
+    doc: Optional[str] = None
+    exported: int = 0
+    unexported: int = 0
+    counts: dict[str, int] = field(default_factory=dict)  # kind -> symbols
+    types: list[TypeSummary] = field(default_factory=list)
+    warnings: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {
+            "directory": self.directory,
+            "package": self.package,
+            "files": len(self.files),
+            "test_files": len(self.test_files),
+            "doc": self.doc,
+            "symbols": {"exported": self.exported, "unexported": self.unexported,
+                        **self.counts},
+            "types": [{"name": t.name, "kind": t.kind, "file": t.file, "line": t.start_line,
+                       "exported": t.exported, "methods": t.methods} for t in self.types],
+            "warnings": self.warnings,
+        }
+
+
+_TYPE_KINDS = {"struct_type": "struct", "interface_type": "interface",
+               "function_type": "func"}
+
+
+def _is_exported(name: str) -> bool:
+    return name[:1].isupper()
+
+
+def _type_specs(go_file: GoFile):
+    """type_spec and type_alias ("type A = B") nodes at package level."""
+    for decl in go_file.root.children:
+        if decl.type == "type_declaration":
+            yield from (c for c in decl.named_children if c.type in ("type_spec", "type_alias"))
+
+
+def _package_doc(go_file: GoFile) -> Optional[str]:
+    from ..languages.go import GoLanguage  # deferred: the languages import the golang helpers
+    return GoLanguage().extract_file_doc(go_file.source, go_file.root)
+
+
+def summarize_package(files: list[GoFile], directory: str,
+                      include_tests: bool = False) -> PackageSummary:
+    """Summary of the package in `directory`, from those files that live
+    directly in it (subdirectories are other packages)."""
+    target = str(Path(directory).resolve())
+    in_dir = [f for f in files if f.directory == target]
+    summary = PackageSummary(directory=target, package=None)
+
+    names: dict[str, list[str]] = {}
+    counted = []
+    for go_file in in_dir:
+        is_test = go_file.path.endswith("_test.go")
+        (summary.test_files if is_test else summary.files).append(go_file.path)
+        package = go_file.package or "(none)"
+        if not (is_test and package.endswith("_test")):
+            names.setdefault(package, []).append(Path(go_file.path).name)
+        if include_tests or not is_test:
+            counted.append(go_file)
+
+    if names:
+        # The package is what most files say; the rest are the stray ones
+        summary.package = max(names, key=lambda n: (len(names[n]), n != "main", n))
+        if len(names) > 1:
+            listed = "; ".join(f"{n} ({', '.join(sorted(f))})" for n, f in sorted(names.items()))
+            summary.warnings.append(f"multiple package names in one directory: {listed}")
+
+    doc_files = sorted((f for f in in_dir if not f.path.endswith("_test.go")),
+                       key=lambda f: (Path(f.path).name != "doc.go", f.path))
+    summary.doc = next((doc for doc in map(_package_doc, doc_files) if doc), None)
+
+    types: dict[str, TypeSummary] = {}
+    methods: list[tuple[str, str]] = []
+
+    def count(kind: str, name: str):
+        summary.counts[kind] = summary.counts.get(kind, 0) + 1
+        if _is_exported(name):
+            summary.exported += 1
+        else:
+            summary.unexported += 1
+
+    for go_file in counted:
+        source = go_file.source
+        for spec in _type_specs(go_file):
+            name_node = spec.child_by_field_name("name")
+            if name_node is None:
+                continue
+            name = syntax.node_text(name_node, source)
+            type_node = spec.child_by_field_name("type")
+            kind = ("alias" if spec.type == "type_alias"
+                    else _TYPE_KINDS.get(type_node.type if type_node else "", "other"))
+            types.setdefault(name, TypeSummary(name, kind, go_file.path, syntax.line_of(spec),
+                                               _is_exported(name)))
+            count("types", name)
+        for decl in go_file.root.children:
+            name_node = decl.child_by_field_name("name")
+            if decl.type == "function_declaration" and name_node is not None:
+                name = syntax.node_text(name_node, source)
+                if name != "_":
+                    count("functions", name)
+            elif decl.type == "method_declaration" and name_node is not None:
+                receiver_type, _ = syntax.receiver(decl, source)
+                name = syntax.node_text(name_node, source)
+                methods.append((receiver_type or "?", name))
+                count("methods", name)
+            elif decl.type in ("const_declaration", "var_declaration"):
+                kind = "constants" if decl.type == "const_declaration" else "variables"
+                spec_type = "const_spec" if kind == "constants" else "var_spec"
+                for spec in syntax.walk(decl):
+                    if spec.type != spec_type:
+                        continue
+                    for name_node in spec.children_by_field_name("name"):
+                        name = syntax.node_text(name_node, source)
+                        if name != "_":
+                            count(kind, name)
+
+    for receiver_type, name in methods:
+        owner = types.get(receiver_type)
+        if owner is None:
+            summary.warnings.append(f"method {receiver_type}.{name} on a type not declared here")
+            continue
+        owner.methods.append(name)
+    summary.types = sorted(types.values(), key=lambda t: (not t.exported, t.name))
+    return summary
+
+
+def format_package_summary(summary: PackageSummary) -> str:
+    if not summary.files and not summary.test_files:
+        return f"No Go files directly in {summary.directory}"
+
+    counts = ", ".join(f"{summary.counts[k]} {k}"
+                       for k in ("types", "functions", "methods", "constants", "variables")
+                       if summary.counts.get(k))
+    lines = [f"package {summary.package} — {summary.directory}",
+             f"{len(summary.files)} files (+{len(summary.test_files)} test); "
+             f"{summary.exported} exported, {summary.unexported} unexported symbols"
+             + (f" ({counts})" if counts else "")]
+    if summary.doc:
+        lines.append(f"doc: {summary.doc.splitlines()[0]}")
+    if summary.types:
+        lines.append("\ntypes:")
+        for t in summary.types:
+            method_count = len(t.methods)
+            methods = (f"  {method_count} method{'s' if method_count != 1 else ''}: "
+                       f"{', '.join(t.methods)}") if t.methods else ""
+            lines.append(f"- {t.name} ({t.kind}) {Path(t.file).name}@{t.start_line}{methods}")
+    lines.extend(f"warning: {w}" for w in summary.warnings)
+    return "\n".join(lines)
@@ -0,0 +1,97 @@
+"""
+FILE: surface.py
+
+PROBLEM:
+  API growth is easy to miss in review: a helper exported for one test,
+  a const block made public "for now". A CI check wants one cheap number
+  per package to snapshot and compare between commits — and the kind
+  behind a jump (twelve new consts vs. twelve new methods) to decide
+  whether it matters.
+
+SOLUTION:
+  Count the exported top-level declarations of each package's non-test
+  files, by kind: functions, methods (exported name and receiver type),
+  types (aliases included), consts and vars, one per exported name —
+  the same declarations find_undocumented checks. Only top-level
+  declarations are read, never function bodies. Totals per package and
+  for the whole scope.
+
+SCOPE:
+  ✓ Packages are (directory, package name); generated files count — their
+    API is as public as any
+  ✗ Exported struct fields and interface methods are not counted
+  ✗ No comparison against a previous snapshot — diff the JSON in CI
+"""
+
+from collections import Counter
+from dataclasses import dataclass, field
+from typing import Optional
+
+from .syntax import GoFile
+from .undocumented import exported_declarations
+
+KINDS = ("function", "method", "type", "const", "var")
+
+
+@dataclass
+class PackageSurface:
+    directory: str
+    package: Optional[str]
+    counts: Counter = field(default_factory=Counter)
+
+    @property
+    def total(self) -> int:
+        return sum(self.counts.values())
+
+    def to_dict(self) -> dict:
+        return {"directory": self.directory, "package": self.package, "total": self.total,
+                "counts": {kind: self.counts[kind] for kind in KINDS}}
+
+
+@dataclass
+class ApiSurface:
+    packages: list[PackageSurface] = field(default_factory=list)  # by directory, package
+
+    @property
+    def counts(self) -> Counter:
+        return sum((package.counts for package in self.packages), Counter())
+
+    @property
+    def total(self) -> int:
+        return sum(package.total for package in self.packages)
+
+    def to_dict(self) -> dict:
+        counts = self.counts
+        return {"total": self.total, "counts": {kind: counts[kind] for kind in KINDS},
+                "packages": [package.to_dict() for package in self.packages]}
+
+
+def api_surface(files: list[GoFile]) -> ApiSurface:
+    """Exported declarations counted by kind per package (test files
+    left out)."""
+    packages: dict[tuple, PackageSurface] = {}
+    for go_file in files:
+        if go_file.path.endswith("_test.go"):
+            continue
+        key = (go_file.directory, go_file.package)
+        surface = packages.setdefault(key, PackageSurface(*key))
+        surface.counts.update(kind for _, kind, _, _, _ in exported_declarations(go_file))
+    return ApiSurface([packages[key] for key in
+                       sorted(packages, key=lambda key: (key[0], key[1] or ""))])
+
+
+def _breakdown(counts: Counter) -> str:
+    return ", ".join(f"{counts[kind]} {kind}s" for kind in KINDS)
+
+
+def format_api_surface(surface: ApiSurface, scope: str) -> str:
+    """The total with its kind breakdown, then one line per package."""
+    if not surface.packages:
+        return f"No Go packages found in {scope}"
+
+    lines = [f"API surface of {scope}: {surface.total} exported symbols "
+             f"({_breakdown(surface.counts)}) in {len(surface.packages)} packages"]
+    for package in surface.packages:
+        lines.append(f"- {package.package or '?'} ({package.directory}): {package.total} — "
+                     f"{_breakdown(package.counts)}")
+    return "\n".join(lines)
@@ -0,0 +1,300 @@
+"""Shared tree-sitter-go helpers: parsing, node text, signature rendering and
+loading the Go files of a scan scope."""
+
+import time
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Iterator, Optional
+
+import tree_sitter_go
+from tree_sitter import Language, Node, Parser
+
+from ..source_text import normalize_source
+
+_GO_LANGUAGE = Language(tree_sitter_go.language())
+
+
+@dataclass
+class GoFile:
+    """One parsed Go source file."""
+
+    path: str
+    source: bytes
+    root: Node
+    package: Optional[str]  # package clause name, None if missing
+    # The bytes as on disk (BOM, CRLF kept) for checks of the exact bytes;
+    # source is normalized. None: source is all there is
+    raw_source: Optional[bytes] = None
+
+    @property
+    def directory(self) -> str:
+        """Go packages are directories — the package identity in a scope."""
+        return str(Path(self.path).parent)
+
+
+def parse(source: bytes) -> Node:
+    """Parse Go source; a fresh Parser per call keeps this thread-safe."""
+    parser = Parser()
+    parser.language = _GO_LANGUAGE
+    return parser.parse(source).root_node
+
+
+def node_text(node: Node, source: bytes) -> str:
+    return source[node.start_byte:node.end_byte].decode("utf-8", errors="replace")
+
+
+def normalized_text(node: Node, source: bytes) -> str:
+    """Node text on one line with whitespace runs collapsed."""
+    return " ".join(node_text(node, source).split())
+
+
+def walk(node: Node) -> Iterator[Node]:
+    """All descendants of node (pre-order, node included)."""
+    stack = [node]
+    while stack:
+        current = stack.pop()
+        yield current
+        stack.extend(reversed(current.children))
+
+
+def line_of(node: Node) -> int:
+    return node.start_point[0] + 1
+
+
+def package_name(root: Node, source: bytes) -> Optional[str]:
+    clause = next((c for c in root.children if c.type == "package_clause"), None)
+    if clause is None:
+        return None
+    ident = next((c for c in clause.named_children if c.type == "package_identifier"), None)
+    return node_text(ident, source) if ident else None
+
+
+# ── Signatures ───────────────────────────────────────────────────────────────
+
+_PARAMETER_TYPES = ("parameter_declaration", "variadic_parameter_declaration")
+
+
+def _parameter_type(param: Node, source: bytes) -> str:
+    type_node = param.child_by_field_name("type")
+    type_text = normalized_text(type_node, source) if type_node else ""
+    if param.type == "variadic_parameter_declaration":
+        type_text = "..." + type_text
+    return type_text
+
+
+def format_parameter_list(node: Node, source: bytes) -> str:
+    """Render a parameter_list as "(a, b int, opts ...Option)" — grouping
+    and variadics kept, comments and line breaks dropped."""
+    params = []
+    for child in node.named_children:
+        if child.type not in _PARAMETER_TYPES:
+            continue  # comments
+        names = [node_text(n, source) for n in child.children_by_field_name("name")]
+        type_text = _parameter_type(child, source)
+        params.append(f"{', '.join(names)} {type_text}" if names else type_text)
+    return f"({', '.join(params)})"
+
+
+def parameter_types(node: Optional[Node], source: bytes) -> tuple[str, ...]:
+    """Types of a parameter_list, one per parameter ("a, b int" → int, int)."""
+    if node is None:
+        return ()
+    types = []
+    for child in node.named_children:
+        if child.type not in _PARAMETER_TYPES:
+            continue
+        count = max(1, len(child.children_by_field_name("name")))
+        types.extend([_parameter_type(child, source)] * count)
+    return tuple(types)
+
+
+def result_types(node: Optional[Node], source: bytes) -> tuple[str, ...]:
+    """Types of a function result: () for none, one entry per result."""
+    if node is None:
+        return ()
+    if node.type == "parameter_list":
+        return parameter_types(node, source)
+    return (normalized_text(node, source),)
+
+
+def format_header(node: Node, name: str, source: bytes) -> str:
+    """"Name[T any](a, b int) (*T, error)" for a function, method or
+    interface method node (receiver not included)."""
+    parts = [name]
+    type_params = node.child_by_field_name("type_parameters")
+    if type_params:
+        parts.append(normalized_text(type_params, source))
+    params = node.child_by_field_name("parameters")
+    parts.append(format_parameter_list(params, source) if params else "()")
+    result = node.child_by_field_name("result")
+    if result:
+        rendered = (format_parameter_list(result, source) if result.type == "parameter_list"
+                    else normalized_text(result, source))
+        parts.append(" " + rendered)
+    return "".join(parts)
+
+
+def method_key(node: Node, name: str, source: bytes) -> tuple:
+    """Identity of a method for method-set comparison: name plus parameter
+    and result TYPES (parameter names don't matter to Go)."""
+    return (name,
+            parameter_types(node.child_by_field_name("parameters"), source),
+            result_types(node.child_by_field_name("result"), source))
+
+
+def base_type_name(type_node: Optional[Node], source: bytes) -> Optional[str]:
+    """Named type behind a type expression: *T, T[K], pkg.T → "T"/"pkg.T"."""
+    while type_node is not None and type_node.type in ("pointer_type", "generic_type",
+                                                       "parenthesized_type"):
+        inner = type_node.child_by_field_name("type")
+        if inner is None:
+            inner = next((c for c in type_node.named_children), None)
+        type_node = inner
+    if type_node is None:
+        return None
+    if type_node.type in ("type_identifier", "qualified_type"):
+        return node_text(type_node, source)
+    return None
+
+
+def receiver(method: Node, source: bytes) -> tuple[Optional[str], bool]:
+    """(receiver base type name, is_pointer) of a method_declaration."""
+    receiver_list = method.child_by_field_name("receiver")
+    if receiver_list is None:
+        return None, False
+    param = next((c for c in receiver_list.named_children
+                  if c.type == "parameter_declaration"), None)
+    if param is None:
+        return None, False
+    type_node = param.child_by_field_name("type")
+    # func (p (*T)) M() is a pointer receiver too
+    while type_node is not None and type_node.type == "parenthesized_type":
+        type_node = next(iter(type_node.named_children), None)
+    is_pointer = type_node is not None and type_node.type == "pointer_type"
+    return base_type_name(type_node, source), is_pointer
+
+
+def enclosing_function(node: Node, source: bytes) -> str:
+    """Name of the function node sits in — Name, Type.Name, or "func
+    literal in Name" for closures ("func literal" at package level), and
+    "(package level)" outside any function."""
+    literal = False
+    current = node
+    while current is not None:
+        if current.type == "func_literal":
+            literal = True
+        elif current.type in ("function_declaration", "method_declaration"):
+            name = node_text(current.child_by_field_name("name"), source)
+            if current.type == "method_declaration":
+                receiver_type, _ = receiver(current, source)
+                name = f"{receiver_type}.{name}" if receiver_type else name
+            return f"func literal in {name}" if literal else name
+        current = current.parent
+    return "func literal" if literal else "(package level)"
+
+
+def type_specs(root: Node) -> Iterator[Node]:
+    """Every type_spec declared at package level (grouped declarations too)."""
+    for decl in root.children:
+        if decl.type != "type_declaration":
+            continue
+        for spec in decl.named_children:
+            if spec.type == "type_spec":
+                yield spec
+
+
+# ── Loading ──────────────────────────────────────────────────────────────────
+
+def load_go_files(path: str, respect_gitignore: bool = True,
+                  max_file_size: Optional[int] = None,
+                  max_files: Optional[int] = None,
+                  max_total_bytes: Optional[int] = None,
+                  timeout: Optional[float] = None) -> list[GoFile]:
+    """Parse the Go files of a scope: a single .go file, or every .go file
+    under a directory using scan_directory's walk rules (gitignore, noise
+    dirs, generated-file names). Files the directory scan would not parse
+    are left out too: unreadable ones, binary ones (NUL byte) and those
+    over max_file_size (default: scan_directory's) — each file is read and
+    parsed once, here.
+
+    max_files, max_total_bytes and timeout (wall-clock seconds) bound the
+    load like scan_directory's limits, raising LimitExceeded or
+    ScanCancelled past them. Their results are empty: an analysis of part
+    of a package set (dead code, duplicates, import cycles) would mislead."""
+    # deferred: scanner imports the languages, which import these helpers
+    from ..languages.go import GoLanguage
+    from ..scanner import (
+        DEFAULT_MAX_FILE_SIZE, FileScanner, LimitExceeded, ScanCancelled, is_binary_head,
+    )
+
+    deadline = time.monotonic() + timeout if timeout is not None else None
+
+    target = Path(path)
+    if not target.exists():
+        raise FileNotFoundError(f"Path not found: {path}")
+    if target.is_file():
+        paths = [str(target.resolve())]
+    else:
+        walked = FileScanner().walk_files(str(target), "**/*.go",
+                                          respect_gitignore=respect_gitignore)
+        paths = []
+        for p in walked:
+            if deadline is not None and time.monotonic() > deadline:
+                raise ScanCancelled("timed out", {})
+            if GoLanguage.should_skip(p.name):
+                continue
+            if max_files is not None and len(paths) >= max_files:
+                raise LimitExceeded("max_files", max_files, {})
+            paths.append(str(p))
+        paths.sort()
+    if max_file_size is None:
+        max_file_size = DEFAULT_MAX_FILE_SIZE
+
+    files = []
+    total_bytes = 0
+    for file_path in paths:
+        if deadline is not None and time.monotonic() > deadline:
+            raise ScanCancelled("timed out", {})
+        try:
+            if max_file_size and Path(file_path).stat().st_size > max_file_size:
+                continue
+            raw = Path(file_path).read_bytes()
+        except OSError:
+            continue
+        if is_binary_head(raw):
+            continue
+        total_bytes += len(raw)
+        if max_total_bytes is not None and total_bytes > max_total_bytes:
+            raise LimitExceeded("max_total_bytes", max_total_bytes, {})
+        files.append(_go_file(file_path, raw))
+    return files
+
+
+def go_files_of_scan(results: dict) -> list[GoFile]:
+    """Parse the Go files of a scan_directory result, without walking the
+    tree again: the scan applied the walk rules and limits already, so
+    generated-file names (GoLanguage.should_skip) are not in it. Its stubs
+    (too large, binary, unreadable, timed out) are left out, as are files
+    deleted since; files with a "Code generated" header are kept, as
+    load_go_files keeps them."""
+    from ..languages import is_unsupported_stub, skip_reason  # deferred like load_go_files'
+
+    files = []
+    for file_path in sorted(results):
+        structures = results[file_path]
+        if (not file_path.endswith(".go") or not structures
+                or skip_reason(structures) or is_unsupported_stub(structures)):
+            continue
+        try:
+            raw = Path(file_path).read_bytes()
+        except OSError:
+            continue
+        files.append(_go_file(file_path, raw))
+    return files
+
+
+def _go_file(file_path: str, raw: bytes) -> GoFile:
+    source = normalize_source(raw)
+    root = parse(source)
+    return GoFile(path=file_path, source=source, root=root,
+                  package=package_name(root, source), raw_source=raw)
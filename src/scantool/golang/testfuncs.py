@@ -0,0 +1,238 @@
+"""
+FILE: testfuncs.py
+
+PROBLEM:
+  "How many tests, benchmarks and fuzz targets does this repo have, and
+  what do they exercise?" is a dashboard question that today needs go test
+  -list per package — and says nothing about which functions are tested.
+
+SOLUTION:
+  Find the functions go test runs, by go test's own rules: in _test.go
+  files, TestXxx(*testing.T), BenchmarkXxx(*testing.B), FuzzXxx(*testing.F)
+  and ExampleXxx() with no parameters or results — where Xxx is empty or
+  does not start with a lower-case letter — plus TestMain(*testing.M). The
+  testing import's local name (alias, dot import) is honoured.
+
+  A naming-convention heuristic links each to the source declaration it
+  appears to cover: TestFormatUser → FormatUser, Test_parse → parse,
+  TestStore_Get / ExampleStore_Get → Store.Get, TestFormatUserEmpty →
+  FormatUser (longest declared name the rest starts with, at a word
+  boundary). Exported functions and methods no test name points at are
+  listed as untested.
+
+SCOPE:
+  ✓ Per package directory: internal and external (_test package) tests
+  ✗ Coverage by name only — a test calling Parse without naming it is not
+    linked; use go test -cover for real coverage
+  ✗ Subtests (t.Run) and table cases are not counted separately
+"""
+
+from dataclasses import dataclass, field
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+KINDS = ("test", "benchmark", "fuzz", "example", "main")
+
+_PREFIXES = {"test": "Test", "benchmark": "Benchmark", "fuzz": "Fuzz", "example": "Example"}
+_TESTING_TYPES = {"test": "T", "benchmark": "B", "fuzz": "F"}  # parameter *testing.X
+
+
+@dataclass
+class GoTest:
+    name: str
+    kind: str  # one of KINDS
+    file: str
+    line: int
+    target: Optional[str] = None  # heuristic: covered declaration, Type.Method for methods
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "kind": self.kind, "file": self.file,
+                "line": self.line, "target": self.target}
+
+
+@dataclass
+class SourceFunction:
+    name: str  # Type.Method for methods
+    file: str
+    line: int
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "file": self.file, "line": self.line}
+
+
+@dataclass
+class GoTestSuite:
+    tests: list[GoTest] = field(default_factory=list)
+    untested: list[SourceFunction] = field(default_factory=list)  # exported, heuristic
+
+    def counts(self) -> dict[str, int]:
+        return {kind: sum(1 for t in self.tests if t.kind == kind) for kind in KINDS}
+
+    def to_dict(self) -> dict:
+        return {"counts": self.counts(), "tests": [t.to_dict() for t in self.tests],
+                "untested": [f.to_dict() for f in self.untested]}
+
+
+def _testing_qualifier(go_file: GoFile) -> Optional[str]:
+    """Local name of the "testing" import: "testing", an alias, "" for a
+    dot import; None when not imported."""
+    for node in syntax.walk(go_file.root):
+        if node.type != "import_spec":
+            continue
+        path_node = node.child_by_field_name("path")
+        if path_node is None or syntax.node_text(path_node, go_file.source).strip('"`') != "testing":
+            continue
+        alias = node.child_by_field_name("name")
+        if alias is None:
+            return "testing"
+        alias_text = syntax.node_text(alias, go_file.source)
+        if alias_text == ".":
+            return ""
+        return None if alias_text == "_" else alias_text
+    return None
+
+
+def _has_test_name(name: str, prefix: str) -> bool:
+    """go test's rule: the prefix, then nothing or a non-lower-case rune."""
+    if not name.startswith(prefix):
+        return False
+    rest = name[len(prefix):]
+    return not rest or not rest[0].islower()
+
+
+def _kind(decl, go_file: GoFile, qualifier: Optional[str]) -> Optional[str]:
+    source = go_file.source
+    name = syntax.node_text(decl.child_by_field_name("name"), source)
+    if decl.child_by_field_name("type_parameters") is not None:
+        return None
+    params = syntax.parameter_types(decl.child_by_field_name("parameters"), source)
+    results = syntax.result_types(decl.child_by_field_name("result"), source)
+    if results:
+        return None
+    if _has_test_name(name, "Example"):
+        return "example" if not params else None
+    if qualifier is None or len(params) != 1:
+        return None
+    prefix = f"{qualifier}." if qualifier else ""
+    if name == "TestMain":
+        return "main" if params[0] == f"*{prefix}M" else None
+    for kind, type_name in _TESTING_TYPES.items():
+        if _has_test_name(name, _PREFIXES[kind]) and params[0] == f"*{prefix}{type_name}":
+            return kind
+    return None
+
+
+def _source_functions(files: list[GoFile]) -> tuple[list[SourceFunction], set[str]]:
+    """Functions and methods of non-test files, and the type names declared."""
+    functions, types = [], set()
+    for go_file in files:
+        for decl in go_file.root.children:
+            name_node = decl.child_by_field_name("name")
+            if decl.type == "function_declaration" and name_node is not None:
+                functions.append(SourceFunction(syntax.node_text(name_node, go_file.source),
+                                                go_file.path, syntax.line_of(decl)))
+            elif decl.type == "method_declaration" and name_node is not None:
+                receiver_type, _ = syntax.receiver(decl, go_file.source)
+                name = syntax.node_text(name_node, go_file.source)
+                functions.append(SourceFunction(f"{receiver_type}.{name}" if receiver_type else name,
+                                                go_file.path, syntax.line_of(decl)))
+        for spec in syntax.type_specs(go_file.root):
+            name_node = spec.child_by_field_name("name")
+            if name_node is not None:
+                types.add(syntax.node_text(name_node, go_file.source))
+    return functions, types
+
+
+def _spellings(functions: list[SourceFunction], types: set[str]) -> dict[str, str]:
+    """How a test name may spell each declaration → the declaration."""
+    spellings: dict[str, str] = {name: name for name in types}
+    for function in functions:
+        owner, _, method = function.name.rpartition(".")
+        forms = [f"{owner}_{method}", f"{owner}{method}"] if owner else [function.name]
+        for form in forms:
+            spellings.setdefault(form, function.name)
+            spellings.setdefault(form[:1].upper() + form[1:], function.name)
+    return spellings
+
+
+def _target(test: GoTest, spellings: dict[str, str]) -> Optional[str]:
+    if test.kind == "main":
+        return None
+    rest = test.name[len(_PREFIXES[test.kind]):].lstrip("_")
+    if not rest:
+        return None
+    if rest in spellings:
+        return spellings[rest]
+    # Longest declared name the rest starts with, ending at a word boundary
+    for end in range(len(rest) - 1, 0, -1):
+        following = rest[end]
+        if (following.isupper() or following.isdigit() or following == "_") \
+                and rest[:end] in spellings:
+            return spellings[rest[:end]]
+    return None
+
+
+def scan_tests(files: list[GoFile]) -> GoTestSuite:
+    """Test, benchmark, fuzz and example functions of the _test.go files,
+    each with its heuristic target, in file then line order."""
+    packages: dict[str, list[GoFile]] = {}
+    for go_file in files:
+        packages.setdefault(go_file.directory, []).append(go_file)
+
+    suite = GoTestSuite()
+    for package_files in packages.values():
+        sources = [f for f in package_files if not f.path.endswith("_test.go")]
+        functions, types = _source_functions(sources)
+        spellings = _spellings(functions, types)
+
+        targets = set()
+        for go_file in package_files:
+            if not go_file.path.endswith("_test.go"):
+                continue
+            qualifier = _testing_qualifier(go_file)
+            for decl in go_file.root.children:
+                if decl.type != "function_declaration" or decl.child_by_field_name("name") is None:
+                    continue
+                kind = _kind(decl, go_file, qualifier)
+                if kind is None:
+                    continue
+                test = GoTest(syntax.node_text(decl.child_by_field_name("name"), go_file.source),
+              kind, go_file.path, syntax.line_of(decl))
+                test.target = _target(test, spellings)
+                targets.add(test.target)
+                suite.tests.append(test)
+
+        suite.untested.extend(
+            f for f in functions
+            if f.name not in targets and all(part[:1].isupper() for part in f.name.split(".")))
+
+    suite.tests.sort(key=lambda t: (t.file, t.line))
+    suite.untested.sort(key=lambda f: (f.file, f.line))
+    return suite
+
+
+def format_tests(suite: GoTestSuite, scope: str) -> str:
+    """Counts by kind, then per file "@line kind Name → target" lines and
+    the untested exported functions."""
+    if not suite.tests:
+        return f"No test functions found in {scope}"
+
+    counts = suite.counts()
+    by_kind = ", ".join(f"{counts[kind]} {kind}" for kind in KINDS[:-1] if counts[kind])
+    main = " (+ TestMain)" if counts["main"] else ""
+    matched = sum(1 for t in suite.tests if t.target)
+    lines = [f"{len(suite.tests)} test functions in {scope}: {by_kind}{main}",
+             f"targets by naming convention (heuristic): {matched} of {len(suite.tests)} matched"]
+    current_file = None
+    for test in suite.tests:
+        if test.file != current_file:
+            current_file = test.file
+            lines.append(f"\n{current_file}")
+        target = f" → {test.target}" if test.target else ""
+        lines.append(f"- @{test.line} {test.kind} {test.name}{target}")
+    if suite.untested:
+        lines.append(f"\nexported functions no test name points at (heuristic, "
+                     f"{len(suite.untested)}): " + ", ".join(f.name for f in suite.untested))
+    return "\n".join(lines)
@@ -0,0 +1,235 @@
+"""
+FILE: typedeps.py
+
+PROBLEM:
+  Reading a data model means following field types: Order holds *User
+  and []Item, Item holds Money, and so on until only builtins and other
+  packages' types are left. Doing it by hand means jumping declaration to
+  declaration and keeping the visited set in one's head.
+
+SOLUTION:
+  Starting at one named type of a package (the non-test .go files
+  directly in a directory), resolve the types its fields mention to the
+  package's own type declarations and walk them breadth-first:
+    - pointer, slice, array, map (key and value), channel and
+      parenthesized types are unwrapped to the named types inside;
+      generic instantiations count the base type and every type argument
+    - an inline struct's fields count as the outer field's
+    - a non-struct type (type IDs []User, type Money int64) depends on
+      its underlying type, an alias on its target
+    - pkg.T is an external leaf: listed, never followed
+    - builtins (string, error, any) and the type's own type parameters
+      are not dependencies
+  Result: every dependency with the shortest depth it is reached at, and
+  the edges owner → type with the fields that create them.
+
+SCOPE:
+  ✓ Cycles (Node → *Node, A → B → A): edges are kept, each type is
+    visited once
+  ✗ Interface method signatures and func-typed fields are not followed:
+    they describe behaviour, not data held
+  ✗ Dot imports make another package's names look local; they resolve
+    only if the package declares the same name
+"""
+
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+# Wrappers whose element types are what a field holds (no fields: every
+# named child; type_elem wraps generic type arguments)
+_UNWRAP = {"pointer_type": (), "slice_type": ("element",), "array_type": ("element",),
+           "implicit_length_array_type": ("element",), "map_type": ("key", "value"),
+           "channel_type": ("value",), "parenthesized_type": (), "type_elem": ()}
+
+_KIND_BY_NODE = {"struct_type": "struct", "interface_type": "interface",
+                 "function_type": "func"}
+
+
+@dataclass
+class TypeDependency:
+    name: str  # local "Item", external "time.Time"
+    kind: str  # struct, interface, func, alias, other; "external" for pkg.T
+    depth: int  # 1 = mentioned by the root's own fields
+    file: Optional[str] = None  # local declarations only
+    line: Optional[int] = None
+
+    @property
+    def external(self) -> bool:
+        return self.kind == "external"
+
+    def to_dict(self) -> dict:
+        return {"name": self.name, "kind": self.kind, "depth": self.depth,
+                "external": self.external, "file": self.file, "line": self.line}
+
+
+@dataclass
+class TypeEdge:
+    source: str
+    target: str
+    fields: list[str] = field(default_factory=list)  # empty for an underlying type
+
+    def to_dict(self) -> dict:
+        return {"source": self.source, "target": self.target, "fields": self.fields}
+
+
+@dataclass
+class TypeDependencies:
+    directory: str
+    package: Optional[str]
+    type_name: str
+    file: Optional[str] = None  # None: no such type in the package
+    line: Optional[int] = None
+    dependencies: list[TypeDependency] = field(default_factory=list)
+    edges: list[TypeEdge] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {"directory": self.directory, "package": self.package,
+                "type_name": self.type_name, "file": self.file, "line": self.line,
+                "dependencies": [d.to_dict() for d in self.dependencies],
+                "edges": [e.to_dict() for e in self.edges]}
+
+
+def _type_params(spec, source: bytes) -> set[str]:
+    params = spec.child_by_field_name("type_parameters")
+    if params is None:
+        return set()
+    return {syntax.node_text(name, source)
+            for decl in params.named_children
+            for name in decl.children_by_field_name("name")}
+
+
+def _references(type_node, source: bytes, local: set[str], hidden: set[str]) -> list[str]:
+    """Local and qualified type names a type expression holds, in source
+    order; hidden are type parameters that shadow package names."""
+    if type_node is None:
+        return []
+    node_type = type_node.type
+    if node_type == "type_identifier":
+        name = syntax.node_text(type_node, source)
+        return [name] if name in local and name not in hidden else []
+    if node_type == "qualified_type":
+        return [syntax.normalized_text(type_node, source)]
+    if node_type == "generic_type":
+        found = _references(type_node.child_by_field_name("type"), source, local, hidden)
+        arguments = type_node.child_by_field_name("type_arguments")
+        for argument in arguments.named_children if arguments is not None else []:
+            found.extend(_references(argument, source, local, hidden))
+        return found
+    if node_type == "struct_type":
+        return [name for _, names in _fields(type_node, source, local, hidden)
+                for name in names]
+    if node_type in _UNWRAP:
+        fields = _UNWRAP[node_type]
+        children = [type_node.child_by_field_name(f) for f in fields] if fields \
+            else type_node.named_children
+        found = []
+        for child in children:
+            found.extend(_references(child, source, local, hidden))
+        return found
+    return []  # interface_type, function_type, builtins' neighbours
+
+
+def _fields(struct, source: bytes, local: set[str],
+            hidden: set[str]) -> list[tuple[str, list[str]]]:
+    """(field label, referenced types) per field of a struct type; an
+    embedded field is labelled with its type name."""
+    found = []
+    field_list = next((c for c in struct.children if c.type == "field_declaration_list"), None)
+    for decl in field_list.named_children if field_list is not None else []:
+        if decl.type != "field_declaration":
+            continue
+        type_node = decl.child_by_field_name("type")
+        names = [syntax.node_text(n, source) for n in decl.children_by_field_name("name")]
+        label = ", ".join(names) or (syntax.base_type_name(type_node, source) or "")
+        found.append((label, _references(type_node, source, local, hidden)))
+    return found
+
+
+def type_dependencies(files: list[GoFile], directory: str, type_name: str) -> TypeDependencies:
+    """Transitive field-type dependencies of type_name within the package
+    in `directory` (its non-test files that live directly in it)."""
+    target = str(Path(directory).resolve())
+    in_dir = [f for f in files if f.directory == target and not f.path.endswith("_test.go")]
+    packages = Counter(f.package for f in in_dir if f.package)
+    result = TypeDependencies(directory=target, type_name=type_name,
+                              package=packages.most_common(1)[0][0] if packages else None)
+
+    specs: dict[str, tuple[GoFile, object]] = {}
+    for go_file in in_dir:
+        for decl in go_file.root.children:
+            if decl.type != "type_declaration":
+                continue
+            for spec in decl.named_children:
+                name_node = spec.child_by_field_name("name")
+                if spec.type in ("type_spec", "type_alias") and name_node is not None:
+                    specs.setdefault(syntax.node_text(name_node, go_file.source), (go_file, spec))
+    if type_name not in specs:
+        return result
+    local = set(specs)
+
+    root_file, root_spec = specs[type_name]
+    result.file, result.line = root_file.path, syntax.line_of(root_spec)
+    seen = {type_name: None}
+    queue = [(type_name, 0)]
+    while queue:
+        owner, depth = queue.pop(0)
+        go_file, spec = specs[owner]
+        source = go_file.source
+        type_node = spec.child_by_field_name("type")
+        hidden = _type_params(spec, source)
+        if type_node is not None and type_node.type == "struct_type":
+            held = _fields(type_node, source, local, hidden)
+        else:
+            held = [("", _references(type_node, source, local, hidden))]
+
+        edges: dict[str, TypeEdge] = {}
+        for label, names in held:
+            for name in names:
+                edge = edges.setdefault(name, TypeEdge(owner, name))
+                if label and label not in edge.fields:
+                    edge.fields.append(label)
+                if name in seen:
+                    continue
+                if name in specs:
+                    dep_file, dep_spec = specs[name]
+                    dep_type = dep_spec.child_by_field_name("type")
+                    kind = "alias" if dep_spec.type == "type_alias" else \
+                        _KIND_BY_NODE.get(dep_type.type if dep_type is not None else "", "other")
+                    seen[name] = TypeDependency(name, kind, depth + 1, dep_file.path,
+                                                syntax.line_of(dep_spec))
+                    queue.append((name, depth + 1))
+                else:
+                    seen[name] = TypeDependency(name, "external", depth + 1)
+        result.edges.extend(edges.values())
+
+    result.dependencies = [d for d in seen.values() if d is not None]
+    return result
+
+
+def format_type_dependencies(result: TypeDependencies) -> str:
+    """Tally, the local and external dependencies by depth, then the edges
+    "Owner → Type  (fields)"."""
+    if result.file is None:
+        return f"No type named {result.type_name} in {result.directory}"
+
+    local = [d for d in result.dependencies if not d.external]
+    external = [d for d in result.dependencies if d.external]
+    lines = [f"{result.type_name} ({result.file}@{result.line}) depends on "
+             f"{len(result.dependencies)} types: {len(local)} local, {len(external)} external"]
+    if local:
+        lines.append("\nLocal:")
+        lines.extend(f"- {d.name} ({d.kind}, depth {d.depth}) {d.file}@{d.line}" for d in local)
+    if external:
+        lines.append("\nExternal:")
+        lines.extend(f"- {d.name} (depth {d.depth})" for d in external)
+    if result.edges:
+        lines.append("\nEdges:")
+        for edge in result.edges:
+            via = f"  ({', '.join(edge.fields)})" if edge.fields else ""
+            lines.append(f"- {edge.source} → {edge.target}{via}")
+    return "\n".join(lines)
@@ -0,0 +1,189 @@
+"""
+FILE: unchecked.py
+
+PROBLEM:
+  `u, err := repo.Find(id)` followed by `u.Save()` compiles fine — err is
+  used later, so vet is silent — yet u is nil whenever Find failed. The
+  bug only shows at runtime, as a nil-pointer panic far from the call.
+
+SOLUTION:
+  Best-effort, per block. For every `x, err := f()` / `x, err = f()`
+  whose last target is an error variable (err, or a name ending in Err)
+  and whose right side is a call, look at the statements that follow it
+  in the SAME block, in order:
+    - one that mentions err (if err != nil, return ..., err, wrap(err),
+      errors.Is(err, ...)) checks it — done, nothing reported
+    - one that assigns err anew before any mention drops the first
+      error: reported as "overwritten"
+    - one that mentions x before that uses a value that may be invalid:
+      reported as "used" with the line of the use
+  Nested blocks of a later statement are searched as part of it, so a
+  use inside `for ... { x.Do() }` counts as a use.
+
+SCOPE:
+  ✓ Functions, methods and function literals; switch/select cases are
+    blocks of their own
+  ✗ Straight-line only: a check on another branch, a goto or an early
+    return in a helper is invisible, so false positives are possible
+  ✗ No shadowing: an inner `err :=` that hides the outer err is taken for
+    the same variable
+  ✗ `if x, err := f(); ...` initializers are not followed
+"""
+
+import re
+from collections import Counter
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+_ERROR_NAME = re.compile(r"^(err|\w+Err)$")
+
+_ASSIGNMENTS = ("short_var_declaration", "assignment_statement")
+
+# Nodes whose named children run in order as one straight-line sequence
+_BLOCKS = ("block", "statement_list", "expression_case", "default_case",
+           "type_case", "communication_case")
+
+
+@dataclass
+class UncheckedError:
+    file: str
+    line: int
+    function: str  # Name, Type.Name, or "func literal in Name"
+    statement: str  # the assignment, on one line
+    error: str  # the error variable
+    kind: str  # used, overwritten
+    variable: str  # the value used, or the error overwritten
+    use_line: int
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "function": self.function,
+                "statement": self.statement, "error": self.error, "kind": self.kind,
+                "variable": self.variable, "use_line": self.use_line}
+
+
+def _targets(assignment, source: bytes) -> list[str]:
+    """Left-hand identifiers of an assignment ("" for anything else)."""
+    left = assignment.child_by_field_name("left")
+    if left is None:
+        return []
+    return [syntax.node_text(n, source) if n.type == "identifier" else ""
+            for n in left.named_children]
+
+
+def _is_call(assignment) -> bool:
+    right = assignment.child_by_field_name("right")
+    values = right.named_children if right is not None else []
+    return len(values) == 1 and values[0].type == "call_expression"
+
+
+def _mentions(node, name: str, source: bytes) -> Optional[int]:
+    """Line of the first identifier use of name inside node, if any."""
+    for child in syntax.walk(node):
+        if child.type == "identifier" and syntax.node_text(child, source) == name:
+            return syntax.line_of(child)
+    return None
+
+
+def _reassigns(statement, error: str, source: bytes) -> bool:
+    """statement assigns error without reading it first (err = wrap(err)
+    reads it)."""
+    if statement.type not in _ASSIGNMENTS or error not in _targets(statement, source):
+        return False
+    right = statement.child_by_field_name("right")
+    return right is None or _mentions(right, error, source) is None
+
+
+def _statements(block) -> list:
+    statements = []
+    for child in block.named_children:
+        if child.type == "statement_list":
+            statements.extend(_statements(child))
+        elif child.type != "comment":
+            statements.append(child)
+    return statements
+
+
+def _follow(assignment, following: list, go_file: GoFile) -> Optional[UncheckedError]:
+    """The finding of one err-returning assignment, given the statements
+    after it in its block."""
+    source = go_file.source
+    targets = _targets(assignment, source)
+    error = targets[-1]
+    values = [name for name in targets[:-1] if name and name != "_"]
+
+    def finding(kind: str, variable: str, line: int) -> UncheckedError:
+        return UncheckedError(go_file.path, syntax.line_of(assignment),
+                              syntax.enclosing_function(assignment, source),
+                              syntax.normalized_text(assignment, source),
+                              error, kind, variable, line)
+
+    for statement in following:
+        if _reassigns(statement, error, source):
+            return finding("overwritten", error, syntax.line_of(statement))
+        if _mentions(statement, error, source) is not None:
+            return None
+        for value in values:
+            line = _mentions(statement, value, source)
+            if line is not None:
+                return finding("used", value, line)
+    return None
+
+
+def _file_unchecked(go_file: GoFile) -> list[UncheckedError]:
+    source = go_file.source
+    found = []
+    for block in syntax.walk(go_file.root):
+        if block.type not in _BLOCKS or block.parent is None:
+            continue
+        if block.type == "statement_list" and block.parent.type in _BLOCKS:
+            continue  # its statements belong to the enclosing block's sequence
+        statements = _statements(block)
+        for index, statement in enumerate(statements):
+            if statement.type not in _ASSIGNMENTS or not _is_call(statement):
+                continue
+            targets = _targets(statement, source)
+            if not targets or not _ERROR_NAME.match(targets[-1]):
+                continue
+            unchecked = _follow(statement, statements[index + 1:], go_file)
+            if unchecked is not None:
+                found.append(unchecked)
+    return found
+
+
+def find_unchecked_errors(files: list[GoFile],
+                          include_tests: bool = False) -> list[UncheckedError]:
+    """err-returning assignments whose err is overwritten, or whose value
+    is used, before err is looked at — in file then line order."""
+    unchecked = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        unchecked.extend(_file_unchecked(go_file))
+    unchecked.sort(key=lambda u: (u.file, u.line))
+    return unchecked
+
+
+def format_unchecked_errors(findings: list[UncheckedError], scope: str) -> str:
+    """Per file: "@line function: statement" with what happens before the
+    check."""
+    if not findings:
+        return f"No unchecked errors found in {scope}"
+
+    kinds = Counter(finding.kind for finding in findings)
+    tally = ", ".join(f"{count} {kind}" for kind, count in kinds.items())
+    lines = [f"{len(findings)} possibly unchecked errors in {scope} ({tally}) "
+             f"— same-block heuristic, verify before fixing"]
+    current_file = None
+    for finding in findings:
+        if finding.file != current_file:
+            current_file = finding.file
+            lines.append(f"\n{current_file}")
+        if finding.kind == "used":
+            what = f"{finding.variable} used @{finding.use_line} before {finding.error} is checked"
+        else:
+            what = f"{finding.error} overwritten @{finding.use_line} before it is checked"
+        lines.append(f"- @{finding.line} {finding.function}: {finding.statement}  — {what}")
+    return "\n".join(lines)
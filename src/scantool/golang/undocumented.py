@@ -0,0 +1,165 @@
+"""
+FILE: undocumented.py
+
+PROBLEM:
+  golint-style checks fail on every exported declaration without a doc
+  comment, and pkg.go.dev shows those as bare signatures. A per-file scan
+  carries docstrings, but not "which exported names have none" across a
+  module — the list an agent needs before it writes the missing comments.
+
+SOLUTION:
+  Walk the top-level declarations of each non-test file and report the
+  exported ones whose doc comment — the comment group directly above, a
+  blank line ends it — is absent or has no text:
+    - functions; methods whose name AND receiver type are exported
+    - types, consts and vars, one entry per exported name; in a grouped
+      declaration (const ( ... )) the comment above the group documents
+      every spec in it, as golint accepts
+  Directive lines (//go:generate, //nolint:..., //export-style "word:word"
+  lines without a space) are not doc text, like go/doc treats them.
+
+SCOPE:
+  ✓ Generated files (// Code generated ... DO NOT EDIT.) skipped unless asked
+  ✗ No check that the comment starts with the name ("Foo does ...")
+  ✗ Exported fields and interface methods are not checked
+"""
+
+import re
+from collections import Counter
+from dataclasses import dataclass
+from typing import Iterator, Optional
+
+from . import syntax
+from .syntax import GoFile
+
+# go/ast's directive rule: "//" directly followed by word:word
+_DIRECTIVE = re.compile(r"^//(line |extern |export |[a-z0-9]+:[a-z0-9])")
+
+_SPEC_DECLARATIONS = {"type_declaration": ("type", ("type_spec", "type_alias")),
+                      "const_declaration": ("const", ("const_spec",)),
+                      "var_declaration": ("var", ("var_spec",))}
+
+
+@dataclass
+class UndocumentedSymbol:
+    name: str
+    kind: str  # function, method, type, const, var
+    file: str
+    line: int
+    receiver: Optional[str] = None  # methods: receiver base type
+
+    @property
+    def display_name(self) -> str:
+        return f"{self.receiver}.{self.name}" if self.receiver else self.name
+
+    def to_dict(self) -> dict:
+        data = {"name": self.display_name, "kind": self.kind, "file": self.file,
+                "line": self.line}
+        if self.receiver:
+            data["receiver"] = self.receiver
+        return data
+
+
+def _is_exported(name: Optional[str]) -> bool:
+    return bool(name) and name[:1].isupper()
+
+
+def _has_doc(node, source: bytes) -> bool:
+    """Whether the comment group directly above node has any doc text."""
+    next_row = node.start_point[0]
+    prev = node.prev_sibling
+    while prev is not None and prev.type == "comment" and prev.end_point[0] >= next_row - 1:
+        text = syntax.node_text(prev, source).strip()
+        if not _DIRECTIVE.match(text):
+            body = text[2:-2] if text.startswith("/*") else text[2:]
+            if body.strip(" \t\r\n*/"):
+                return True
+        next_row = prev.start_point[0]
+        prev = prev.prev_sibling
+    return False
+
+
+def _spec_names(spec) -> list:
+    if spec.type in ("type_spec", "type_alias"):
+        name = spec.child_by_field_name("name")
+        return [name] if name is not None else []
+    return spec.children_by_field_name("name")
+
+
+def exported_declarations(go_file: GoFile) -> Iterator[tuple]:
+    """(name, kind, line, receiver, documented) per exported top-level
+    declaration, in source order: functions, methods whose name and
+    receiver type are exported, and one entry per exported type, const
+    and var name."""
+    source = go_file.source
+    for decl in go_file.root.children:
+        name_node = decl.child_by_field_name("name")
+        if decl.type == "function_declaration" and name_node is not None:
+            name = syntax.node_text(name_node, source)
+            if _is_exported(name):
+                yield name, "function", syntax.line_of(decl), None, _has_doc(decl, source)
+        elif decl.type == "method_declaration" and name_node is not None:
+            name = syntax.node_text(name_node, source)
+            receiver_type, _ = syntax.receiver(decl, source)
+            if _is_exported(name) and _is_exported(receiver_type):
+                yield (name, "method", syntax.line_of(decl), receiver_type,
+                       _has_doc(decl, source))
+        elif decl.type in _SPEC_DECLARATIONS:
+            kind, spec_types = _SPEC_DECLARATIONS[decl.type]
+            # documents the single spec, or the whole group
+            group_doc = _has_doc(decl, source)
+            # Some grammar versions wrap grouped specs in a *_spec_list node
+            spec_lists = [c for c in decl.named_children if c.type.endswith("_spec_list")]
+            grouped = bool(spec_lists) or any(c.type == "(" for c in decl.children)
+            specs = [c for c in decl.named_children if c.type in spec_types]
+            for spec_list in spec_lists:
+                specs.extend(c for c in spec_list.named_children if c.type in spec_types)
+            for spec in specs:
+                documented = group_doc or (grouped and _has_doc(spec, source))
+                for spec_name in _spec_names(spec):
+                    name = syntax.node_text(spec_name, source)
+                    if _is_exported(name):
+                        yield name, kind, syntax.line_of(spec), None, documented
+
+
+def _file_undocumented(go_file: GoFile) -> list[UndocumentedSymbol]:
+    return [UndocumentedSymbol(name, kind, go_file.path, line, receiver)
+            for name, kind, line, receiver, documented in exported_declarations(go_file)
+            if not documented]
+
+
+def _is_generated(go_file: GoFile) -> bool:
+    from ..languages.go import GoLanguage  # deferred: the languages import the golang helpers
+    return GoLanguage().is_generated(go_file.source, go_file.root)
+
+
+def find_undocumented(files: list[GoFile],
+                      include_generated: bool = False) -> list[UndocumentedSymbol]:
+    """Exported declarations without a doc comment, in file then line order.
+    _test.go files are never checked."""
+    undocumented = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go"):
+            continue
+        if not include_generated and _is_generated(go_file):
+            continue
+        undocumented.extend(_file_undocumented(go_file))
+    undocumented.sort(key=lambda s: (s.file, s.line))
+    return undocumented
+
+
+def format_undocumented(symbols: list[UndocumentedSymbol], scope: str) -> str:
+    """Per file: "@line kind name" lines."""
+    if not symbols:
+        return f"Every exported symbol in {scope} has a doc comment"
+
+    kinds = Counter(symbol.kind for symbol in symbols)
+    tally = ", ".join(f"{count} {kind}" for kind, count in kinds.items())
+    lines = [f"{len(symbols)} exported symbols without a doc comment in {scope} ({tally})"]
+    current_file = None
+    for symbol in symbols:
+        if symbol.file != current_file:
+            current_file = symbol.file
+            lines.append(f"\n{current_file}")
+        lines.append(f"- @{symbol.line} {symbol.kind} {symbol.display_name}")
+    return "\n".join(lines)
@@ -0,0 +1,139 @@
+"""
+FILE: wide.py
+
+PROBLEM:
+  A function taking eight parameters, or a struct with forty fields, is a
+  refactoring target every reviewer recognizes ("pass an options struct",
+  "split this type") — but nobody counts by hand, and a grouped list
+  like `func Dial(host, port, user, pass string)` hides four parameters
+  behind one type.
+
+SOLUTION:
+  Count per declaration and report those above a threshold:
+    parameters  every name of a grouped parameter (a, b, c int = 3), one
+                per unnamed parameter, the variadic one as one; the
+                receiver is not a parameter. Functions, methods and func
+                literals
+    fields      every name of a grouped field (X, Y float64 = 2), one per
+                embedded type, in named struct types
+  Thresholds are the team's convention; the defaults flag what most
+  linters' defaults would (more than 5 parameters, 15 fields).
+
+SCOPE:
+  ✓ Struct types declared inside functions too
+  ✗ Results are not counted — see find_unchecked_errors for (T, error)
+  ✗ Anonymous struct types (fields typed struct{...}, literals) are not
+    checked
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from . import syntax
+from .syntax import GoFile
+
+DEFAULT_MAX_PARAMS = 5
+DEFAULT_MAX_FIELDS = 15
+
+
+@dataclass
+class WideDeclaration:
+    file: str
+    line: int
+    kind: str  # function, method, func literal, struct
+    name: str  # Name, Type.Name for methods, "func literal in F" for closures
+    count: int  # parameters, or fields for structs
+    limit: int
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "line": self.line, "kind": self.kind, "name": self.name,
+                "count": self.count, "limit": self.limit}
+
+
+def parameter_count(function) -> int:
+    """Parameters of a function, method or func literal, grouped names
+    counted one by one."""
+    params = function.child_by_field_name("parameters")
+    if params is None:
+        return 0
+    return sum(max(1, len(p.children_by_field_name("name"))) for p in params.named_children
+               if p.type in ("parameter_declaration", "variadic_parameter_declaration"))
+
+
+def field_count(struct) -> int:
+    """Fields of a struct_type, grouped names counted one by one."""
+    fields = next((c for c in struct.children if c.type == "field_declaration_list"), None)
+    if fields is None:
+        return 0
+    return sum(max(1, len(f.children_by_field_name("name"))) for f in fields.named_children
+               if f.type == "field_declaration")
+
+
+def _function_name(node, source: bytes) -> tuple[str, str]:
+    if node.type == "func_literal":
+        return "func literal", syntax.enclosing_function(node, source)
+    name = syntax.node_text(node.child_by_field_name("name"), source)
+    if node.type == "method_declaration":
+        receiver_type, _ = syntax.receiver(node, source)
+        return "method", f"{receiver_type}.{name}" if receiver_type else name
+    return "function", name
+
+
+def find_wide_signatures(files: list[GoFile], max_params: Optional[int] = DEFAULT_MAX_PARAMS,
+                         max_fields: Optional[int] = DEFAULT_MAX_FIELDS,
+                         include_tests: bool = False) -> list[WideDeclaration]:
+    """Functions with more than max_params parameters and structs with
+    more than max_fields fields (None = that check off), in file then
+    line order."""
+    found = []
+    for go_file in files:
+        if go_file.path.endswith("_test.go") and not include_tests:
+            continue
+        source = go_file.source
+        for node in syntax.walk(go_file.root):
+            if node.type in ("function_declaration", "method_declaration", "func_literal"):
+                if max_params is None:
+                    continue
+                count = parameter_count(node)
+                if count > max_params:
+                    kind, name = _function_name(node, source)
+                    found.append(WideDeclaration(go_file.path, syntax.line_of(node), kind,
+                                                 name, count, max_params))
+            elif node.type == "type_spec" and max_fields is not None:
+                struct = node.child_by_field_name("type")
+                if struct is None or struct.type != "struct_type":
+                    continue
+                count = field_count(struct)
+                if count > max_fields:
+                    name = syntax.node_text(node.child_by_field_name("name"), source)
+                    found.append(WideDeclaration(go_file.path, syntax.line_of(node), "struct",
+                                                 name, count, max_fields))
+    found.sort(key=lambda wide: (wide.file, wide.line))
+    return found
+
+
+def format_wide_signatures(wide: list[WideDeclaration], scope: str,
+                           max_params: Optional[int] = DEFAULT_MAX_PARAMS,
+                           max_fields: Optional[int] = DEFAULT_MAX_FIELDS) -> str:
+    """Tally, then per file "@line kind Name: N parameters (max M)"."""
+    checks = {}
+    if max_params is not None:
+        checks["parameters"] = f"functions with more than {max_params} parameters"
+    if max_fields is not None:
+        checks["fields"] = f"structs with more than {max_fields} fields"
+    if not wide:
+        return f"No {' or '.join(checks.values())} in {scope}"
+
+    structs = sum(1 for w in wide if w.kind == "struct")
+    counts = {"parameters": len(wide) - structs, "fields": structs}
+    lines = [", ".join(f"{counts[check]} {text}" for check, text in checks.items())
+             + f" in {scope}"]
+    current_file = None
+    for item in wide:
+        if item.file != current_file:
+            current_file = item.file
+            lines.append(f"\n{current_file}")
+        unit = "fields" if item.kind == "struct" else "parameters"
+        lines.append(f"- @{item.line} {item.kind} {item.name}: {item.count} {unit} "
+                     f"(max {item.limit})")
+    return "\n".join(lines)
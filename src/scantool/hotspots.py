@@ -0,0 +1,186 @@
+"""
+FILE: hotspots.py
+
+PROBLEM:
+  An agent that just opened a codebase asks "where should I look first?".
+  The scan knows the answer in pieces — function complexity per node,
+  line counts per file, TODO comments in the text — but not as a ranking.
+
+SOLUTION:
+  One row per code file of a directory scan with four metrics:
+    max_complexity  highest cyclomatic complexity of a function in it
+    avg_complexity  mean over its functions and methods
+    todos           TODO/FIXME/HACK/XXX markers inside comments
+    lines           lines of code (total lines where comments can't be told)
+  The score is a weighted sum of the metrics, each divided by its maximum
+  over the scanned files (so 0..1, relative to this codebase), scaled to
+  0..100. Weights are configurable; ranking by a single metric is sorting
+  by it. Every row carries each metric's share of the score, so the
+  reason a file ranks high is visible, not just the number.
+
+SCOPE:
+  ✓ Every language the scanner outlines; complexity is exact for Go,
+    branches + 1 elsewhere (symbol_filter.cyclomatic_complexity)
+  ✓ Markers are counted in comment tokens where the language reports
+    them, in the whole text otherwise
+  ✗ A triage ranking, not a quality metric: scores are not comparable
+    across codebases
+"""
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from .languages import StructureNode, get_language, is_unsupported_stub
+from .symbol_filter import cyclomatic_complexity
+
+METRICS = ("max_complexity", "avg_complexity", "todos", "lines")
+SORT_KEYS = ("score", *METRICS)
+DEFAULT_WEIGHTS = {"max_complexity": 0.35, "avg_complexity": 0.25, "todos": 0.2, "lines": 0.2}
+DEFAULT_TOP = 10
+
+_MARKER = re.compile(rb"\b(?:TODO|FIXME|HACK|XXX)\b")
+
+
+@dataclass
+class Hotspot:
+    file: str
+    score: float = 0.0
+    max_complexity: int = 0
+    max_function: Optional[str] = None  # the function with max_complexity
+    avg_complexity: float = 0.0
+    functions: int = 0
+    todos: int = 0
+    lines: int = 0
+    shares: dict[str, float] = field(default_factory=dict)  # metric -> points of score
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "score": self.score,
+                "max_complexity": self.max_complexity, "max_function": self.max_function,
+                "avg_complexity": self.avg_complexity, "functions": self.functions,
+                "todos": self.todos, "lines": self.lines, "shares": self.shares}
+
+
+def check_weights(weights: Optional[dict[str, float]]) -> dict[str, float]:
+    """weights over DEFAULT_WEIGHTS (metrics left out keep their default);
+    ValueError for an unknown metric, a negative weight or all zero."""
+    merged = dict(DEFAULT_WEIGHTS)
+    for metric, weight in (weights or {}).items():
+        if metric not in METRICS:
+            raise ValueError(f"Unknown hotspot metric {metric!r} — "
+                             f"use one of: {', '.join(METRICS)}")
+        if weight < 0:
+            raise ValueError(f"Weight of {metric} must not be negative, got {weight}")
+        merged[metric] = float(weight)
+    if not any(merged.values()):
+        raise ValueError("At least one hotspot weight must be above 0")
+    return merged
+
+
+def _count_markers(file_path: str) -> int:
+    try:
+        source = Path(file_path).read_bytes()
+    except OSError:
+        return 0
+    language = get_language(Path(file_path).suffix.lower())
+    spans = language.comment_spans(source) if language is not None else None
+    if spans is None:
+        return len(_MARKER.findall(source))
+    lines = source.split(b"\n")
+    count = 0
+    for (start_row, start_col), (end_row, end_col) in spans:
+        if start_row == end_row:
+            text = lines[start_row][start_col:end_col] if start_row < len(lines) else b""
+        else:
+            text = b"\n".join([lines[start_row][start_col:], *lines[start_row + 1:end_row],
+                               lines[end_row][:end_col] if end_row < len(lines) else b""])
+        count += len(_MARKER.findall(text))
+    return count
+
+
+def _file_hotspot(file_path: str, structures: list[StructureNode]) -> Optional[Hotspot]:
+    """Metrics of one scanned file; None for files without code (no
+    functions and no markers)."""
+    spot = Hotspot(file=file_path)
+    scores = []
+
+    def walk(nodes: list[StructureNode], owner: Optional[str]):
+        for node in nodes:
+            if node.type in ("function", "method"):
+                score = cyclomatic_complexity(node)
+                if score is not None:
+                    scores.append(score)
+                    if score > spot.max_complexity:
+                        spot.max_complexity = score
+                        receiver = node.receiver_type or owner
+                        spot.max_function = f"{receiver}.{node.name}" if receiver else node.name
+            walk(node.children, node.name if node.type not in ("function", "method") else owner)
+
+    walk(structures, None)
+    info = structures[0] if structures and structures[0].type == "file-info" else None
+    line_counts = (info.file_metadata or {}).get("lines", {}) if info is not None else {}
+    spot.lines = line_counts.get("code", line_counts.get("total", 0))
+    spot.functions = len(scores)
+    spot.avg_complexity = round(sum(scores) / len(scores), 1) if scores else 0.0
+    spot.todos = _count_markers(file_path)
+    if not scores and not spot.todos:
+        return None
+    return spot
+
+
+def find_hotspots(results: dict[str, Optional[list[StructureNode]]], top: int = DEFAULT_TOP,
+                  sort_by: str = "score",
+                  weights: Optional[dict[str, float]] = None) -> list[Hotspot]:
+    """The top files of a directory scan by score (or by one metric), ties
+    by path. Every file is scored before the top are cut."""
+    if sort_by not in SORT_KEYS:
+        raise ValueError(f"Unknown sort_by {sort_by!r} — use one of: {', '.join(SORT_KEYS)}")
+    if top < 1:
+        raise ValueError(f"top must be at least 1, got {top}")
+    weights = check_weights(weights)
+
+    spots = []
+    for file_path in sorted(results):
+        structures = results[file_path]
+        if not structures or is_unsupported_stub(structures):
+            continue
+        spot = _file_hotspot(file_path, structures)
+        if spot is not None:
+            spots.append(spot)
+
+    maxima = {metric: max((getattr(s, metric) for s in spots), default=0) for metric in METRICS}
+    total_weight = sum(weights.values())
+    for spot in spots:
+        for metric in METRICS:
+            share = getattr(spot, metric) / maxima[metric] if maxima[metric] else 0.0
+            spot.shares[metric] = round(100 * weights[metric] * share / total_weight, 1)
+        spot.score = round(sum(spot.shares.values()), 1)
+
+    spots.sort(key=lambda s: (-getattr(s, sort_by), s.file))
+    return spots[:top]
+
+
+def format_hotspots(spots: list[Hotspot], directory: str, sort_by: str = "score") -> str:
+    """Ranked lines "1. file  score N" with the metrics and their shares."""
+    if not spots:
+        return f"No code files with functions or TODO markers in {directory}"
+
+    order = "score" if sort_by == "score" else sort_by.replace("_", " ")
+    lines = [f"Top {len(spots)} hotspots in {directory} by {order}"]
+    root = Path(directory).resolve()
+    for rank, spot in enumerate(spots, 1):
+        try:
+            shown = Path(spot.file).resolve().relative_to(root).as_posix()
+        except ValueError:
+            shown = spot.file
+        lines.append(f"{rank}. {shown}  score {spot.score:g}")
+        worst = f" ({spot.max_function})" if spot.max_function else ""
+        lines.append(f"   complexity max {spot.max_complexity}{worst} "
+                     f"avg {spot.avg_complexity:g} over {spot.functions} functions · "
+                     f"{spot.todos} TODOs · {spot.lines} lines")
+        shares = ", ".join(f"{metric.replace('_', ' ')} {spot.shares[metric]:g}"
+                           for metric in METRICS if spot.shares.get(metric))
+        if shares:
+            lines.append(f"   score from: {shares}")
+    return "\n".join(lines)
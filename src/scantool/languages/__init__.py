@@ -17,6 +17,13 @@ Usage:
     for ext, lang_cls in registry.items():
         print(f"{ext}: {lang_cls.get_language_name()}")
 
+    # Add a language from outside this package
+    register_language(MyDslLanguage)
+
+Out-of-tree languages can also be installed as plugins: an entry point in
+the "scantool.languages" group naming a BaseLanguage subclass is
+registered when the registry is first built, after the built-ins.
+
 Models are available from the models submodule:
     from scantool.languages.models import (
         StructureNode,
@@ -29,11 +36,14 @@ Models are available from the models submodule:
 
 import importlib
 import pkgutil
+import threading
+from importlib.metadata import entry_points
 from typing import Dict, Type, Optional
 
 from .base import BaseLanguage
 from .models import (
     StructureNode,
+    StructField,
     ImportInfo,
     EntryPointInfo,
     DefinitionInfo,
@@ -42,6 +52,23 @@ from .models import (
     FileNode,
     CodeMapResult,
     is_unsupported_stub,
+    FUNC_KINDS,
+    function_kind,
+    ParseError,
+    parse_errors,
+    SKIP_BINARY,
+    SKIP_INVALID_ENCODING,
+    SKIP_MAX_DEPTH,
+    SKIP_PANIC,
+    SKIP_PARSE_ERROR,
+    SKIP_PERMISSION,
+    SKIP_POST_PROCESS,
+    SKIP_TIMED_OUT,
+    SKIP_TOO_LARGE,
+    SKIP_UNREADABLE,
+    SkippedFile,
+    skip_reason,
+    skipped_files,
 )
 
 __all__ = [
@@ -50,9 +77,28 @@ __all__ = [
     "LanguageRegistry",
     "get_language",
     "get_registry",
+    "register_language",
     # Models
     "StructureNode",
+    "StructField",
     "is_unsupported_stub",
+    "FUNC_KINDS",
+    "function_kind",
+    "ParseError",
+    "parse_errors",
+    "SKIP_BINARY",
+    "SKIP_INVALID_ENCODING",
+    "SKIP_MAX_DEPTH",
+    "SKIP_PANIC",
+    "SKIP_PARSE_ERROR",
+    "SKIP_PERMISSION",
+    "SKIP_POST_PROCESS",
+    "SKIP_TIMED_OUT",
+    "SKIP_TOO_LARGE",
+    "SKIP_UNREADABLE",
+    "SkippedFile",
+    "skip_reason",
+    "skipped_files",
     "ImportInfo",
     "EntryPointInfo",
     "DefinitionInfo",
@@ -63,24 +109,40 @@ __all__ = [
 ]
 
 
+ENTRY_POINT_GROUP = "scantool.languages"
+
+
 class LanguageRegistry:
     """Registry of all language handlers.
 
     Provides lookup by file extension and auto-discovers language
-    implementations in this package.
+    implementations in this package, then installed plugins. Built-in and
+    external languages go through the same register().
+
+    Thread safety: scans run concurrently (scan_directory's worker pool,
+    background cache warming), so registration takes a lock and may happen
+    at any time. Lookups do not lock; a lookup racing a register() sees the
+    old or the new handler, never a mix. FileScanner instantiates the
+    handler class per file, so a handler's scan() needs no locking unless it
+    shares module-level state; the instances get() caches are shared across
+    threads and must not keep per-scan state.
     """
 
     _instance: Optional["LanguageRegistry"] = None
+    _lock = threading.RLock()  # singleton construction, register(), get()'s instance cache
     _languages: Dict[str, Type[BaseLanguage]]
     _instances: Dict[str, BaseLanguage]
 
     def __new__(cls):
         """Singleton pattern for registry."""
-        if cls._instance is None:
-            cls._instance = super().__new__(cls)
-            cls._instance._languages = {}
-            cls._instance._instances = {}
-            cls._instance._discover_languages()
+        with cls._lock:
+            if cls._instance is None:
+                instance = super().__new__(cls)
+                instance._languages = {}
+                instance._instances = {}
+                instance._discover_languages()
+                instance._discover_plugins()
+                cls._instance = instance
         return cls._instance
 
     def _discover_languages(self):
@@ -104,20 +166,46 @@ class LanguageRegistry:
             except ImportError:
                 pass  # Skip modules that fail to import
 
-    def register(self, language_cls: Type[BaseLanguage]):
+    def _discover_plugins(self):
+        """Register languages installed under the scantool.languages entry
+        point group. A broken plugin is skipped, never fatal."""
+        for entry_point in entry_points(group=ENTRY_POINT_GROUP):
+            try:
+                self.register(entry_point.load())
+            except Exception:
+                pass  # Skip plugins that fail to load or are not languages
+
+    def register(self, language_cls: Type[BaseLanguage]) -> list[str]:
         """Register a language handler.
 
+        An extension already claimed by a language of equal or higher
+        priority is left to that language.
+
         Args:
             language_cls: BaseLanguage subclass to register
+
+        Returns:
+            The extensions now handled by language_cls
+
+        Raises:
+            TypeError: language_cls is not a BaseLanguage subclass
         """
-        for ext in language_cls.get_extensions():
-            ext_lower = ext.lower()
-            # Check priority if extension already registered
-            if ext_lower in self._languages:
-                existing = self._languages[ext_lower]
-                if language_cls.get_priority() <= existing.get_priority():
-                    continue
-            self._languages[ext_lower] = language_cls
+        if not (isinstance(language_cls, type) and issubclass(language_cls, BaseLanguage)):
+            raise TypeError(f"Not a BaseLanguage subclass: {language_cls!r}")
+        claimed = []
+        with self._lock:
+            for ext in language_cls.get_extensions():
+                ext_lower = ext.lower()
+                # Check priority if extension already registered
+                if ext_lower in self._languages:
+                    existing = self._languages[ext_lower]
+                    if existing is not language_cls and \
+                            language_cls.get_priority() <= existing.get_priority():
+                        continue
+                self._languages[ext_lower] = language_cls
+                self._instances.pop(ext_lower, None)
+                claimed.append(ext_lower)
+        return claimed
 
     def get(self, extension: str) -> Optional[BaseLanguage]:
         """Get language handler instance for extension.
@@ -133,10 +221,10 @@ class LanguageRegistry:
             return None
 
         # Cache instances
-        if ext_lower not in self._instances:
-            self._instances[ext_lower] = self._languages[ext_lower]()
-
-        return self._instances[ext_lower]
+        with self._lock:
+            if ext_lower not in self._instances:
+                self._instances[ext_lower] = self._languages[ext_lower]()
+            return self._instances[ext_lower]
 
     def get_class(self, extension: str) -> Optional[Type[BaseLanguage]]:
         """Get language handler class for extension.
@@ -193,6 +281,22 @@ def get_registry() -> LanguageRegistry:
     return _registry
 
 
+def register_language(language_cls: Type[BaseLanguage]) -> list[str]:
+    """Register a language handler with the global registry.
+
+    The entry point for languages outside this package (an in-house DSL);
+    built-in languages are registered the same way during discovery.
+    Safe to call while scans run — see LanguageRegistry.
+
+    Args:
+        language_cls: BaseLanguage subclass to register
+
+    Returns:
+        The extensions now handled by language_cls
+    """
+    return get_registry().register(language_cls)
+
+
 def get_language(extension: str) -> Optional[BaseLanguage]:
     """Get language handler instance for extension.
 
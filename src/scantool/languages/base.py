@@ -14,6 +14,7 @@ from abc import ABC, abstractmethod
 from typing import Optional
 
 from .models import (
+    StructField,
     StructureNode,
     ImportInfo,
     EntryPointInfo,
@@ -96,6 +97,7 @@ class BaseLanguage(ABC):
         """
         self.show_errors = show_errors
         self.fallback_on_errors = fallback_on_errors
+        self._last_parse = None  # (source, tree) of the last _parse()
 
     # ===========================================================================
     # Metadata (REQUIRED - classmethod)
@@ -122,6 +124,12 @@ class BaseLanguage(ABC):
         """
         pass
 
+    @classmethod
+    def get_file_language_name(cls, filename: str) -> str:
+        """Language of one file, for handlers covering several languages
+        (TypeScript vs JavaScript). Default: get_language_name()."""
+        return cls.get_language_name()
+
     @classmethod
     def get_priority(cls) -> int:
         """Return priority for this language (higher = preferred).
@@ -219,7 +227,7 @@ class BaseLanguage(ABC):
                 f"{type(self).__name__} has no tree-sitter parser; override scan()"
             )
         try:
-            tree = parser.parse(source_code)
+            tree = self._parse(source_code)
 
             # Check if we should use fallback due to too many errors
             if self._should_use_fallback(tree.root_node):
@@ -236,6 +244,18 @@ class BaseLanguage(ABC):
                 end_line=1
             )]
 
+    def _parse(self, source_code: bytes):
+        """tree-sitter tree of source_code. The last one is kept, so scan()
+        and the file-info hooks after it (extract_file_doc, is_generated,
+        import_list, extract_namespace, comment_spans) share one parse of
+        the same bytes. Raises what the parser raises."""
+        last = getattr(self, "_last_parse", None)
+        if last is not None and last[0] is source_code:
+            return last[1]
+        tree = self.parser.parse(source_code)
+        self._last_parse = (source_code, tree)
+        return tree
+
     def _extract_structure(self, root, source_code: bytes) -> list[StructureNode]:
         """Tree-sitter traversal used by the default scan().
 
@@ -246,6 +266,61 @@ class BaseLanguage(ABC):
             "or override scan()"
         )
 
+    def extract_file_doc(self, source_code: bytes, root=None) -> Optional[str]:
+        """File-level documentation (Go: the package doc comment), surfaced
+        as file_metadata["doc"]. None = no such concept or none present.
+
+        root, here and in the hooks below: the syntax tree of source_code
+        when the caller has one; else the tree of the last scan() is used."""
+        return None
+
+    def is_generated(self, source_code: bytes, root=None) -> bool:
+        """Whether the file is machine-generated (Go: the "// Code generated
+        ... DO NOT EDIT." header), surfaced as file_metadata["generated"]."""
+        return False
+
+    def build_constraint(self, source_code: bytes) -> Optional[str]:
+        """Condition under which the file is compiled (Go: the //go:build
+        expression), surfaced as file_metadata["build_constraint"]. None =
+        always part of the build."""
+        return None
+
+    def import_list(self, source_code: bytes, root=None) -> Optional[list[dict]]:
+        """The file's imports in detail (Go: path, alias, blank/dot, used),
+        surfaced as file_metadata["imports"]. None = not provided."""
+        return None
+
+    def extract_namespace(self, source_code: bytes, root=None) -> Optional[str]:
+        """Namespace the file declares into (Go: the package name); symbol
+        IDs then use its directory (symbol_ids.symbol_namespace). None = no
+        such concept; the file's path without suffix is used."""
+        return None
+
+    #: tree-sitter node types that are comments, for the per-file code /
+    #: comment / blank line breakdown. Empty = no breakdown for this language.
+    COMMENT_NODE_TYPES: tuple[str, ...] = ()
+
+    def comment_spans(self, source_code: bytes) -> Optional[list[tuple[tuple[int, int], tuple[int, int]]]]:
+        """(start_point, end_point) of every comment token, rows and byte
+        columns 0-based as tree-sitter reports them. None = this language
+        can't tell comments from code."""
+        parser = getattr(self, "parser", None)
+        if not self.COMMENT_NODE_TYPES or parser is None:
+            return None
+        try:
+            root = self._parse(source_code).root_node
+        except Exception:
+            return None
+        spans = []
+        stack = [root]
+        while stack:
+            node = stack.pop()
+            if node.type in self.COMMENT_NODE_TYPES:
+                spans.append((node.start_point, node.end_point))
+                continue
+            stack.extend(node.children)
+        return spans
+
     #: Regex fallback for severely malformed files: list of pattern specs.
     #:   pattern (required) — regex with the structure name in group 1
     #:   type (required) — StructureNode type
@@ -644,6 +719,31 @@ class BaseLanguage(ABC):
         """True if the definition is public API by its declared visibility."""
         return any(m in self._PUBLIC_MODIFIERS for m in defn.modifiers)
 
+    def is_exported(self, node: StructureNode,
+                    parent: Optional[StructureNode] = None) -> bool:
+        """Is this scanned node part of the file's public API?
+
+        Default: a public visibility token in the node's modifiers. A member is
+        only exported through an exported container — a public method on a
+        private class is not reachable API. Languages whose visibility is a
+        naming convention (Go capitalization, Python underscores) override.
+        """
+        if parent is not None and not self.is_exported(parent):
+            return False
+        return any(m in self._PUBLIC_MODIFIERS for m in node.modifiers or [])
+
+    def is_exported_field(self, field: StructField) -> bool:
+        """Is this field of an exported struct part of the public API?
+        Default: yes — languages that list fields override when their
+        fields carry their own visibility."""
+        return True
+
+    def owner_name(self, node: StructureNode) -> Optional[str]:
+        """Type a top-level node belongs to, for languages that declare
+        members outside their type body (Go methods: the receiver type).
+        None = membership is expressed by nesting (node.children)."""
+        return None
+
     def is_offgraph_reachable(self, defn: "DefinitionInfo", content: str) -> bool:
         """For a zero-inbound definition, is it reachable by a channel the call
         graph cannot see (public API, framework dispatch, dispatch-by-name,
@@ -785,6 +885,20 @@ class BaseLanguage(ABC):
         except (UnicodeDecodeError, AttributeError):
             return source_code[node.start_byte:node.end_byte].decode("utf-8", errors="replace")
 
+    @staticmethod
+    def _set_offsets(structure: StructureNode, node, source_code: bytes) -> None:
+        """Byte offsets of a tree-sitter node, and its start/end columns in
+        UTF-16 code units (the LSP default) — tree-sitter columns are bytes,
+        which differ as soon as a line holds a non-ASCII character."""
+        def utf16_column(offset: int, byte_column: int) -> int:
+            prefix = source_code[offset - byte_column:offset].decode("utf-8", errors="replace")
+            return len(prefix.encode("utf-16-le")) // 2
+
+        structure.start_offset = node.start_byte
+        structure.end_offset = node.end_byte
+        structure.start_utf16_column = utf16_column(node.start_byte, node.start_point[1])
+        structure.end_utf16_column = utf16_column(node.end_byte, node.end_point[1])
+
     def _get_ancestors(self, root, target) -> list:
         """Get all ancestor nodes of a target node (root first, parent last).
 
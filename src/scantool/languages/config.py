@@ -1,8 +1,9 @@
 """Config language support - analyzer for configuration files.
 
 This module provides ConfigLanguage for analyzing configuration files
-(.json, .yaml, .yml, .toml, .ini). Since config files don't have
-traditional code structure, scan() returns an empty list.
+(.json, .yaml, .yml, .toml, .ini). Config files don't have traditional
+code structure: the JSON, YAML and TOML handlers below outline their keys
+(config_keys), .ini files scan to an empty list.
 
 Key functionality:
 - extract_imports(): Extract file path references from config files
@@ -16,6 +17,7 @@ from typing import Optional
 from pathlib import Path
 
 from .base import BaseLanguage
+from .config_keys import json_keys, toml_keys, yaml_keys
 from .models import (
     StructureNode,
     ImportInfo,
@@ -29,7 +31,9 @@ class ConfigLanguage(BaseLanguage):
     """Language handler for configuration files (.json, .yaml, .yml, .toml, .ini).
 
     Config files don't have traditional code structure (classes, functions),
-    so scan() returns an empty list. The primary value is in:
+    so scan() returns an empty list; JsonLanguage, YamlLanguage and
+    TomlLanguage take over their extensions and list keys. The primary
+    value is in:
     - extract_imports(): Find file path references
     - find_entry_points(): Find project configs and scripts
     """
@@ -590,3 +594,64 @@ class ConfigLanguage(BaseLanguage):
             return content[:index].count('\n') + 1
         except ValueError:
             return 0
+
+
+class JsonLanguage(ConfigLanguage):
+    """JSON and JSONC files: top-level keys and the keys under them,
+    typed (see config_keys). Analysis is ConfigLanguage's."""
+
+    @classmethod
+    def get_extensions(cls) -> list[str]:
+        return [".json"]
+
+    @classmethod
+    def get_language_name(cls) -> str:
+        return "JSON"
+
+    @classmethod
+    def get_priority(cls) -> int:
+        """Above ConfigLanguage, which also lists the extension."""
+        return 11
+
+    def scan(self, source_code: bytes) -> Optional[list[StructureNode]]:
+        return json_keys(source_code.decode("utf-8", errors="replace"))
+
+
+class YamlLanguage(ConfigLanguage):
+    """YAML files: keys of each document's top-level mapping, one level
+    down, typed (see config_keys)."""
+
+    @classmethod
+    def get_extensions(cls) -> list[str]:
+        return [".yaml", ".yml"]
+
+    @classmethod
+    def get_language_name(cls) -> str:
+        return "YAML"
+
+    @classmethod
+    def get_priority(cls) -> int:
+        return 11
+
+    def scan(self, source_code: bytes) -> Optional[list[StructureNode]]:
+        return yaml_keys(source_code.decode("utf-8", errors="replace"))
+
+
+class TomlLanguage(ConfigLanguage):
+    """TOML files: root keys and tables with their keys and subtables,
+    typed (see config_keys)."""
+
+    @classmethod
+    def get_extensions(cls) -> list[str]:
+        return [".toml"]
+
+    @classmethod
+    def get_language_name(cls) -> str:
+        return "TOML"
+
+    @classmethod
+    def get_priority(cls) -> int:
+        return 11
+
+    def scan(self, source_code: bytes) -> Optional[list[StructureNode]]:
+        return toml_keys(source_code.decode("utf-8", errors="replace"))
@@ -0,0 +1,413 @@
+"""
+FILE: config_keys.py
+
+PROBLEM:
+  An agent reading a repo wants the shape of its config files — which
+  top-level sections a docker-compose.yml, a tsconfig.json or a
+  pyproject.toml has, and what kind of value each holds — without the
+  values themselves, which can run to thousands of lines.
+
+SOLUTION:
+  Per format, the keys of the top-level mapping and of the mappings
+  directly under it (KEY_DEPTH levels), as "key" nodes in source order
+  with the value type as signature: string, number, bool, null, object,
+  array (TOML adds datetime). A key's lines span its whole value, so
+  nested keys sit inside their parent's range.
+    JSON  a position-tracking reader; // and /* */ comments and trailing
+          commas are accepted (tsconfig, VS Code settings)
+    YAML  indentation-based: mapping keys at the block's indent, block
+          scalars (| and >) skipped; keys of every document of a
+          multi-document file
+    TOML  tomllib for the value types, a line scan for positions:
+          [table] and [[array]] headers and dotted keys become their
+          nested keys
+  Invalid input keeps the keys read before the error and adds a
+  parse-error node where it was found.
+
+SCOPE:
+  ✓ JSON / JSONC, YAML block and flow values, TOML 1.0
+  ✗ YAML: anchors and tags are stripped, aliases typed "alias"; only the
+    core schema's true/false count as bool (yes/no are strings); keys
+    inside flow mappings ({a: 1}) and sequences are not listed
+  ✗ Mappings nested inside arrays, and TOML inline tables, have no keys
+    here — only their type
+"""
+
+import json
+import re
+import tomllib
+from bisect import bisect_right
+from datetime import date, datetime, time
+from typing import Optional
+
+from .models import StructureNode
+
+# Levels of keys listed: top-level keys and the keys of their values
+KEY_DEPTH = 2
+
+_JSON_NUMBER = re.compile(r"-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?")
+_JSON_LITERALS = {"true": "bool", "false": "bool", "null": "null"}
+
+
+def _key(name: str, line: int, value_type: Optional[str] = None) -> StructureNode:
+    return StructureNode(type="key", name=name, start_line=line, end_line=line,
+                         signature=value_type)
+
+
+def _error(message: str, line: int, column: int) -> StructureNode:
+    return StructureNode(type="parse-error", name=message, start_line=line, end_line=line,
+                         start_column=column)
+
+
+# ── JSON ─────────────────────────────────────────────────────────────────────
+
+
+class _JsonSyntaxError(ValueError):
+    def __init__(self, message: str, pos: int):
+        super().__init__(message)
+        self.pos = pos
+
+
+class _JsonReader:
+    """Recursive descent over the text, recording object keys above KEY_DEPTH."""
+
+    def __init__(self, text: str):
+        self.text = text
+        self.pos = 0
+        self.line_starts = [0] + [i + 1 for i, c in enumerate(text) if c == "\n"]
+
+    def line(self, pos: int) -> int:
+        return bisect_right(self.line_starts, pos)
+
+    def column(self, pos: int) -> int:
+        return pos - self.line_starts[self.line(pos) - 1] + 1
+
+    def skip(self):
+        """Whitespace and comments."""
+        text = self.text
+        while self.pos < len(text):
+            if text[self.pos].isspace():
+                self.pos += 1
+            elif text.startswith("//", self.pos):
+                end = text.find("\n", self.pos)
+                self.pos = len(text) if end < 0 else end
+            elif text.startswith("/*", self.pos):
+                end = text.find("*/", self.pos + 2)
+                if end < 0:
+                    raise _JsonSyntaxError("unterminated comment", self.pos)
+                self.pos = end + 2
+            else:
+                return
+
+    def value(self, depth: int, keys: list[StructureNode]) -> str:
+        """Read one value, appending the keys of an object at depth to keys."""
+        self.skip()
+        if self.pos >= len(self.text):
+            raise _JsonSyntaxError("unexpected end of input", self.pos)
+        char = self.text[self.pos]
+        if char == "{":
+            self.object(depth, keys)
+            return "object"
+        if char == "[":
+            self.array()
+            return "array"
+        if char == '"':
+            self.string()
+            return "string"
+        for literal, value_type in _JSON_LITERALS.items():
+            if self.text.startswith(literal, self.pos):
+                self.pos += len(literal)
+                return value_type
+        match = _JSON_NUMBER.match(self.text, self.pos)
+        if match:
+            self.pos = match.end()
+            return "number"
+        raise _JsonSyntaxError(f"unexpected character {char!r}", self.pos)
+
+    def string(self) -> str:
+        try:
+            value, self.pos = json.decoder.scanstring(self.text, self.pos + 1)
+        except json.JSONDecodeError as e:
+            raise _JsonSyntaxError(e.msg, e.pos) from None
+        return value
+
+    def separated(self, close: str, item):
+        """Read items up to close, comma-separated, a trailing comma allowed."""
+        self.pos += 1
+        while True:
+            self.skip()
+            if self.text.startswith(close, self.pos):
+                self.pos += 1
+                return
+            item()
+            self.skip()
+            if self.text.startswith(",", self.pos):
+                self.pos += 1
+            elif not self.text.startswith(close, self.pos):
+                raise _JsonSyntaxError(f"expected ',' or '{close}'", self.pos)
+
+    def object(self, depth: int, keys: list[StructureNode]):
+        def member():
+            start = self.pos
+            if not self.text.startswith('"', start):
+                raise _JsonSyntaxError("expected a string key", start)
+            name = self.string()
+            self.skip()
+            if not self.text.startswith(":", self.pos):
+                raise _JsonSyntaxError("expected ':'", self.pos)
+            self.pos += 1
+            node = _key(name, self.line(start))
+            if depth < KEY_DEPTH:
+                keys.append(node)
+            node.signature = self.value(depth + 1, node.children)
+            node.end_line = self.line(self.pos - 1)
+
+        self.separated("}", member)
+
+    def array(self):
+        self.separated("]", lambda: self.value(KEY_DEPTH, []))
+
+
+def json_keys(text: str) -> list[StructureNode]:
+    """Keys of a JSON (or JSONC) document whose top-level value is an object."""
+    reader = _JsonReader(text)
+    keys: list[StructureNode] = []
+    try:
+        reader.value(0, keys)
+        reader.skip()
+        if reader.pos < len(text):
+            raise _JsonSyntaxError("extra data after the top-level value", reader.pos)
+    except _JsonSyntaxError as e:
+        pos = min(e.pos, len(text))
+        keys.append(_error(str(e), reader.line(pos), reader.column(pos)))
+    return keys
+
+
+# ── YAML ─────────────────────────────────────────────────────────────────────
+
+_YAML_KEY = re.compile(
+    r"""(?P<key>"(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s#'"\[\]{},&*!|>%@`-][^#]*?|-[^\s#][^#]*?)"""
+    r""":(?:[ \t]+(?P<value>.*?))?[ \t]*$""")
+_YAML_DOCUMENT = re.compile(r"^(?:---|\.\.\.)(?:\s|$)")
+_YAML_NUMBER = re.compile(r"[-+]?(?:\d[\d_]*(?:\.\d*)?(?:[eE][-+]?\d+)?|\.\d+(?:[eE][-+]?\d+)?"
+                          r"|0x[0-9a-fA-F]+|0o[0-7]+|\.(?:inf|Inf|INF)|\.(?:nan|NaN|NAN))")
+_YAML_BOOLS = {"true", "True", "TRUE", "false", "False", "FALSE"}
+_YAML_NULLS = {"null", "Null", "NULL", "~"}
+
+
+def _indent(line: str) -> int:
+    return len(line) - len(line.lstrip(" "))
+
+
+def _is_content(line: str) -> bool:
+    stripped = line.strip()
+    return bool(stripped) and not stripped.startswith("#") and not stripped.startswith("%")
+
+
+def _is_sequence_item(stripped: str) -> bool:
+    return stripped == "-" or stripped.startswith("- ")
+
+
+def _yaml_inline(value: str) -> str:
+    """The value written after "key:" without its comment, anchor and tag;
+    "" when the value is on the following lines."""
+    value = re.sub(r"\s+#.*$", "", value).strip()
+    while value[:1] in ("&", "!"):
+        _, _, value = value.partition(" ")
+        value = value.strip()
+    return value
+
+
+def _yaml_scalar(value: str) -> str:
+    """Type of an inline value: a flow collection or a scalar."""
+    if value.startswith("*"):
+        return "alias"
+    if value[0] in "|>":
+        return "string"
+    if value[0] == "[":
+        return "array"
+    if value[0] == "{":
+        return "object"
+    if value[0] in "\"'":
+        return "string"
+    if value in _YAML_BOOLS:
+        return "bool"
+    if value in _YAML_NULLS:
+        return "null"
+    if _YAML_NUMBER.fullmatch(value):
+        return "number"
+    return "string"
+
+
+def _yaml_unquote(key: str) -> str:
+    if len(key) >= 2 and key[0] == key[-1] == '"':
+        try:
+            return json.loads(key)
+        except ValueError:
+            return key[1:-1]
+    if len(key) >= 2 and key[0] == key[-1] == "'":
+        return key[1:-1].replace("''", "'")
+    return key.strip()
+
+
+def _yaml_block(lines: list[str], start: int, stop: int, indent: int,
+                depth: int) -> list[StructureNode]:
+    """Keys at exactly indent in lines[start:stop]; deeper lines belong to
+    the key above them."""
+    keys = []
+    i = start
+    while i < stop:
+        line = lines[i]
+        if not _is_content(line) or _YAML_DOCUMENT.match(line):
+            i += 1
+            continue
+        level = _indent(line)
+        stripped = line.strip()
+        match = _YAML_KEY.fullmatch(stripped)
+        if level != indent or match is None or _is_sequence_item(stripped):
+            i += 1
+            continue
+        value = _yaml_inline(match.group("value") or "")
+        j, last = i + 1, i
+        while j < stop:
+            following = lines[j]
+            if _YAML_DOCUMENT.match(following):
+                break
+            if _is_content(following):
+                inner = following.strip()
+                # key:\n- item — a sequence may sit at the key's own indent
+                if _indent(following) < level or (_indent(following) == level and not (
+                        not value and _is_sequence_item(inner))):
+                    break
+                last = j
+            j += 1
+        value_type = _yaml_scalar(value) if value else None
+        if value_type is None:
+            nested = next((lines[k] for k in range(i + 1, last + 1) if _is_content(lines[k])), None)
+            value_type = "null" if nested is None else \
+                "array" if _is_sequence_item(nested.strip()) else "object"
+        node = _key(_yaml_unquote(match.group("key")), i + 1, value_type)
+        node.end_line = last + 1
+        if value_type == "object" and not value and depth + 1 < KEY_DEPTH:
+            child = next(k for k in range(i + 1, last + 1) if _is_content(lines[k]))
+            node.children = _yaml_block(lines, child, last + 1, _indent(lines[child]), depth + 1)
+        keys.append(node)
+        i = j
+    return keys
+
+
+def yaml_keys(text: str) -> list[StructureNode]:
+    """Keys of the top-level mapping of each document of a YAML file."""
+    lines = text.split("\n")
+    first = next((i for i, line in enumerate(lines)
+                  if _is_content(line) and not _YAML_DOCUMENT.match(line)), None)
+    if first is None:
+        return []
+    return _yaml_block(lines, 0, len(lines), _indent(lines[first]), 0)
+
+
+# ── TOML ─────────────────────────────────────────────────────────────────────
+
+_TOML_BARE_KEY = r"[A-Za-z0-9_-]+"
+_TOML_KEY_PART = rf"""(?:{_TOML_BARE_KEY}|"(?:[^"\\]|\\.)*"|'[^']*')"""
+_TOML_DOTTED = rf"{_TOML_KEY_PART}(?:\s*\.\s*{_TOML_KEY_PART})*"
+_TOML_HEADER = re.compile(rf"\[(?P<array>\[)?\s*(?P<path>{_TOML_DOTTED})\s*\]\]?\s*(?:#.*)?")
+_TOML_ASSIGNMENT = re.compile(rf"(?P<path>{_TOML_DOTTED})\s*=\s*(?P<value>.*)")
+_TOML_STRINGS = re.compile(r'"(?:[^"\\]|\\.)*"|\'[^\']*\'')
+
+
+def _toml_path(path: str) -> list[str]:
+    parts = re.findall(_TOML_KEY_PART, path)
+    return [part[1:-1] if part[:1] in "\"'" else part for part in parts]
+
+
+def _toml_type(value) -> str:
+    if isinstance(value, bool):
+        return "bool"
+    if isinstance(value, (int, float)):
+        return "number"
+    if isinstance(value, str):
+        return "string"
+    if isinstance(value, (datetime, date, time)):
+        return "datetime"
+    if isinstance(value, list):
+        return "array"
+    return "object"
+
+
+def _toml_open(value: str) -> tuple[Optional[str], int]:
+    """What a value line leaves open: (multi-line string delimiter, bracket
+    depth) — a value spanning lines swallows the lines up to its end."""
+    for delimiter in ('"""', "'''"):
+        if value.startswith(delimiter) and value.count(delimiter) == 1:
+            return delimiter, 0
+    plain = _TOML_STRINGS.sub('""', value.split(" #")[0])
+    return None, plain.count("[") + plain.count("{") - plain.count("]") - plain.count("}")
+
+
+def toml_keys(text: str) -> list[StructureNode]:
+    """Keys of a TOML document: root keys and tables, with their keys and
+    subtables one level down."""
+    data, error = None, None
+    try:
+        data = tomllib.loads(text)
+    except tomllib.TOMLDecodeError as e:
+        found = re.search(r"\(at line (\d+), column (\d+)\)", str(e))
+        line, column = (int(found.group(1)), int(found.group(2))) if found else (1, 1)
+        error = _error(re.sub(r"\s*\(at .*\)$", "", str(e)), line, column)
+
+    keys: list[StructureNode] = []
+    nodes: dict[tuple[str, ...], StructureNode] = {}
+
+    def node_for(path: tuple[str, ...], line: int) -> Optional[StructureNode]:
+        """The node of a top-level or second-level path, created on first use;
+        its end line grows to every line inside it."""
+        if not path:
+            return None
+        path = path[:KEY_DEPTH]
+        for length in range(1, len(path) + 1):
+            prefix = path[:length]
+            node = nodes.get(prefix)
+            if node is None:
+                value = data
+                for index, part in enumerate(prefix):
+                    value = value.get(part) if isinstance(value, dict) else None
+                    if isinstance(value, list) and value and isinstance(value[-1], dict) \
+                            and index < len(prefix) - 1:
+                        value = value[-1]  # [[array]] of tables: its latest entry
+                node = _key(prefix[-1], line, _toml_type(value) if data is not None else None)
+                nodes[prefix] = node
+                (keys if length == 1 else nodes[prefix[:-1]].children).append(node)
+            node.end_line = max(node.end_line, line)
+        return nodes[path]
+
+    table: tuple[str, ...] = ()   # the current [table]
+    current: tuple[str, ...] = ()  # the key whose value is being read
+    open_string, depth = None, 0
+    for number, line in enumerate(text.split("\n"), start=1):
+        stripped = line.strip()
+        if open_string is not None:
+            if open_string in stripped:
+                open_string = None
+            node_for(current, number)
+            continue
+        if depth > 0:
+            depth += _toml_open(stripped)[1]
+            node_for(current, number)
+            continue
+        if not stripped or stripped.startswith("#"):
+            continue
+        header = _TOML_HEADER.fullmatch(stripped)
+        if header is not None:
+            table = tuple(_toml_path(header.group("path")))
+            node_for(table, number)
+            continue
+        assignment = _TOML_ASSIGNMENT.fullmatch(stripped)
+        if assignment is None:
+            continue
+        current = table + tuple(_toml_path(assignment.group("path")))
+        node_for(current, number)
+        open_string, depth = _toml_open(assignment.group("value").strip())
+    if error is not None:
+        keys.append(error)
+    return keys
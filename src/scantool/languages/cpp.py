@@ -24,21 +24,40 @@ from .models import (
     CallInfo,
 )
 
+# typedef'd type specifiers that have a body of their own -> node type
+_TAGGED_KINDS = {"struct_specifier": "struct", "union_specifier": "union",
+                 "enum_specifier": "enum"}
+
+# Extensions reported as plain C; .h is ambiguous and counted as C
+_C_EXTENSIONS = (".c", ".h")
+
 
 class CCppLanguage(BaseLanguage):
     """Unified language handler for C/C++ files.
 
     Provides both structure scanning and semantic analysis:
-    - scan(): Extract structs, classes, functions, methods with signatures and metadata
+    - scan(): Extract structs, unions, classes, enums, functions (definitions
+      and prototypes), methods, typedefs and #define macros with signatures
+      and metadata
     - extract_imports(): Find #include statements
     - find_entry_points(): Find main functions, WinMain, DllMain, test macros
     - extract_definitions(): Convert scan() output to DefinitionInfo
     - extract_calls(): Find function/method calls (not yet implemented)
 
     Supports: .c, .cc, .cpp, .cxx, .h, .hpp, .hh, .hxx
+    (.c and .h files report language "C", the others "C++")
+
+    Limits — the parser sees the file as written, not preprocessed:
+    - Declarations in every branch of #if/#ifdef/#else are listed, whichever
+      the build would pick; a branch that doesn't parse on its own may come
+      out as a parse error
+    - Declarations produced by macro expansion (DEFINE_HANDLER(foo)) are not
+      seen; a macro's value is not expanded or evaluated
+    - Include guards (#ifndef X / #define X) are not reported as macros
     """
 
     CONDENSE_STRATEGY = "skeleton"
+    COMMENT_NODE_TYPES = ("comment",)
 
     # ── Reachability contract (dead-code detection) ──────────────────────────
     # C/C++ has whole-program linkage the single-file call graph cannot see, so the
@@ -81,6 +100,10 @@ class CCppLanguage(BaseLanguage):
     def get_language_name(cls) -> str:
         return "C/C++"
 
+    @classmethod
+    def get_file_language_name(cls, filename: str) -> str:
+        return "C" if filename.lower().endswith(_C_EXTENSIONS) else "C++"
+
     @classmethod
     def get_priority(cls) -> int:
         return 10
@@ -197,6 +220,58 @@ class CCppLanguage(BaseLanguage):
                     ):
                         added.modifiers.insert(0, access)
 
+        def handle_typedef(node: Node, parent_structures: list) -> None:
+            # `typedef struct {...} Point;` names the struct itself; any other
+            # typedef becomes a "typedef" node whose signature is the aliased type.
+            type_node = node.child_by_field_name("type")
+            aliases = [(d, self._declarator_name(d, source_code))
+                       for d in node.children_by_field_name("declarator")]
+            aliases = [(d, name) for d, name in aliases if name]
+            if type_node is None or not aliases:
+                return
+            comment = self._extract_comment(node, source_code)
+            kind = _TAGGED_KINDS.get(type_node.type)
+            body = type_node.child_by_field_name("body") if kind else None
+            aliased = self._get_node_text(type_node, source_code)
+            if body is not None:
+                tag = type_node.child_by_field_name("name")
+                if tag is None:
+                    tagged = StructureNode(
+                        type=kind,
+                        name=aliases[0][1],
+                        start_line=node.start_point[0] + 1,
+                        end_line=node.end_point[0] + 1,
+                        docstring=comment,
+                        modifiers=["typedef"],
+                        children=[]
+                    )
+                    if kind != "enum":
+                        traverse_members(body, tagged.children, "public")
+                    parent_structures.append(tagged)
+                    aliases = aliases[1:]
+                    aliased = f"{kind} {tagged.name}"
+                else:
+                    before = len(parent_structures)
+                    traverse(type_node, parent_structures)
+                    tag_name = self._get_node_text(tag, source_code)
+                    if any(name == tag_name for _, name in aliases):
+                        for added in parent_structures[before:]:
+                            added.modifiers.append("typedef")
+                    aliases = [(d, name) for d, name in aliases if name != tag_name]
+                    aliased = f"{kind} {tag_name}"
+            for declarator, name in aliases:
+                shape = self._get_node_text(declarator, source_code)
+                parent_structures.append(StructureNode(
+                    type="typedef",
+                    name=name,
+                    start_line=node.start_point[0] + 1,
+                    end_line=node.end_point[0] + 1,
+                    signature=self._normalize_signature(
+                        aliased if shape == name else f"{aliased} {shape}"),
+                    docstring=comment,
+                    children=[]
+                ))
+
         def traverse(node: Node, parent_structures: list):
             # Handle parse errors
             if node.type == "ERROR":
@@ -205,13 +280,14 @@ class CCppLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     parent_structures.append(error_node)
                 return
 
-            # Structs
-            if node.type == "struct_specifier":
+            # Structs and unions
+            if node.type in ("struct_specifier", "union_specifier"):
                 struct_node = self._extract_struct(node, source_code)
                 if struct_node:
                     parent_structures.append(struct_node)
@@ -266,6 +342,16 @@ class CCppLanguage(BaseLanguage):
                 if method_node:
                     parent_structures.append(method_node)
 
+            # typedefs: the alias, or the name of the anonymous struct it defines
+            elif node.type == "type_definition":
+                handle_typedef(node, parent_structures)
+
+            # Object-like and function-like macros
+            elif node.type in ("preproc_def", "preproc_function_def"):
+                macro_node = self._extract_macro(node, source_code)
+                if macro_node:
+                    parent_structures.append(macro_node)
+
             # Preprocessor includes
             elif node.type == "preproc_include":
                 self._handle_include(node, parent_structures, source_code)
@@ -279,7 +365,7 @@ class CCppLanguage(BaseLanguage):
         return structures
 
     def _extract_struct(self, node: Node, source_code: bytes) -> Optional[StructureNode]:
-        """Extract struct declaration."""
+        """Extract struct (or union) declaration."""
         name_node = node.child_by_field_name("name")
         if not name_node:
             # Anonymous struct
@@ -291,7 +377,7 @@ class CCppLanguage(BaseLanguage):
         comment = self._extract_comment(node, source_code)
 
         return StructureNode(
-            type="struct",
+            type=_TAGGED_KINDS[node.type],
             name=name,
             start_line=node.start_point[0] + 1,
             end_line=node.end_point[0] + 1,
@@ -534,6 +620,49 @@ class CCppLanguage(BaseLanguage):
 
         return None
 
+    def _declarator_name(self, declarator: Node, source_code: bytes) -> Optional[str]:
+        """Name a typedef declarator introduces: `Point`, `*PointPtr`,
+        `(*cmp_fn)(const void *, const void *)`, `Buffer[16]`."""
+        node = declarator
+        while node is not None:
+            if node.type in ("type_identifier", "identifier", "primitive_type"):
+                return self._get_node_text(node, source_code)
+            inner = node.child_by_field_name("declarator")
+            if inner is None and node.type.startswith("parenthesized") and node.named_children:
+                inner = node.named_children[-1]
+            node = inner
+        return None
+
+    def _extract_macro(self, node: Node, source_code: bytes) -> Optional[StructureNode]:
+        """Extract a #define; include guards (`#ifndef X` / `#define X`) are skipped."""
+        name_node = node.child_by_field_name("name")
+        if not name_node:
+            return None
+        name = self._get_node_text(name_node, source_code)
+
+        parent = node.parent
+        if node.type == "preproc_def" and node.child_by_field_name("value") is None \
+                and parent is not None and parent.type == "preproc_ifdef":
+            guard = parent.child_by_field_name("name")
+            if guard is not None and self._get_node_text(guard, source_code) == name:
+                return None
+
+        # The directive's node includes its newline: end on the last line of text
+        end_row, end_column = node.end_point
+        if end_column == 0 and end_row > node.start_point[0]:
+            end_row -= 1
+
+        params_node = node.child_by_field_name("parameters")
+        return StructureNode(
+            type="macro",
+            name=name,
+            start_line=node.start_point[0] + 1,
+            end_line=end_row + 1,
+            signature=self._get_node_text(params_node, source_code) if params_node else None,
+            docstring=self._extract_comment(node, source_code),
+            children=[]
+        )
+
     def _extract_function_signature(self, declarator: Node, source_code: bytes) -> Optional[str]:
         """Extract function signature (parameters)."""
         params_node = declarator.child_by_field_name("parameters")
@@ -16,20 +16,37 @@ import tree_sitter_go
 from tree_sitter import Language, Parser, Node
 
 from .base import BaseLanguage
+from ..golang import buildtags
+from ..golang import imports as go_imports
+from ..golang import syntax as go_syntax
 from .models import (
     StructureNode,
+    StructField,
     ImportInfo,
     EntryPointInfo,
     DefinitionInfo,
     CallInfo,
 )
 
+# The generated-code marker gofmt and go vet recognise (go/ast.IsGenerated)
+_GENERATED_HEADER = re.compile(r"^// Code generated .* DO NOT EDIT\.$")
+# "@owner: payments-team" — a structured tag line in a doc comment
+_DOC_ANNOTATION = re.compile(r"^@([\w.-]+):\s*(.*)$")
+# Top-level nodes the structure scan turns into symbols
+_DECLARATION_TYPES = ("function_declaration", "method_declaration", "type_declaration",
+                      "const_declaration", "var_declaration")
+# The specs of a type declaration: "type A struct{}" and "type A = B"
+_TYPE_SPECS = ("type_spec", "type_alias")
+# const/var declaration -> (node type, spec type) of the names it declares
+_VALUE_DECLARATIONS = {"const_declaration": ("const", "const_spec"),
+                       "var_declaration": ("var", "var_spec")}
+
 
 class GoLanguage(BaseLanguage):
     """Unified language handler for Go files (.go).
 
     Provides both structure scanning and semantic analysis:
-    - scan(): Extract structs, interfaces, functions, methods with signatures
+    - scan(): Extract structs, interfaces, functions, methods, consts and vars with signatures
     - extract_imports(): Find import statements
     - find_entry_points(): Find main functions, init functions, HTTP handlers
     - extract_definitions(): Convert scan() output to DefinitionInfo
@@ -37,6 +54,7 @@ class GoLanguage(BaseLanguage):
     """
 
     CONDENSE_STRATEGY = "skeleton"
+    COMMENT_NODE_TYPES = ("comment",)
 
     # Reachability: Go exports by capitalization — an upper-case identifier is
     # public API (reachable from outside the corpus); a lower-case one that is
@@ -46,6 +64,36 @@ class GoLanguage(BaseLanguage):
     def is_offgraph_reachable(self, defn, content: str) -> bool:
         return bool(defn.name) and defn.name[0].isupper()
 
+    # Receiver type of a method signature: "(s *UserService) ..." → UserService
+    _RECEIVER_TYPE = re.compile(r"^\(\s*(?:\w+\s+)?\*?\s*(\w+)")
+
+    def is_exported(self, node: StructureNode,
+                    parent: Optional[StructureNode] = None) -> bool:
+        """Go exports by capitalization. A capitalized method on an unexported
+        receiver is still unreachable from other packages, so methods also
+        need an exported receiver type."""
+        if parent is not None and not self.is_exported(parent):
+            return False
+        if not node.name or not node.name[0].isupper():
+            return False
+        receiver = self.owner_name(node)
+        if receiver and not receiver[0].isupper():
+            return False
+        return True
+
+    def is_exported_field(self, field: StructField) -> bool:
+        """A named field by its name; an embedded one by its type's name
+        ("*sync.Mutex" → Mutex, "Base[T]" → Base), as go/ast decides."""
+        name = field.name or field.type.lstrip("*").split("[", 1)[0].rsplit(".", 1)[-1]
+        return name[:1].isupper()
+
+    def owner_name(self, node: StructureNode) -> Optional[str]:
+        """Receiver type of a method ("(s *Store[T]) ..." → "Store")."""
+        if node.type != "method" or not node.signature:
+            return None
+        receiver = self._RECEIVER_TYPE.match(node.signature)
+        return receiver.group(1) if receiver else None
+
     def __init__(self, **kwargs):
         super().__init__(**kwargs)
         self.parser = Parser()
@@ -130,35 +178,50 @@ class GoLanguage(BaseLanguage):
     def _extract_structure(self, root: Node, source_code: bytes) -> list[StructureNode]:
         """Extract structure using tree-sitter."""
         structures = []
+        go_file = go_syntax.GoFile(path="", source=source_code, root=root,
+                                   package=go_syntax.package_name(root, source_code))
+        imported = frozenset(go_imports.import_paths(go_file))
 
-        def traverse(node: Node, parent_structures: list):
+        def declared(structure: Optional[StructureNode], node: Node,
+                     parent_structures: list) -> None:
+            if structure is None:
+                return
+            self._set_offsets(structure, node, source_code)
+            self._set_spans(structure, node)
+            if self.show_errors and node.has_error:
+                # The declaration parsed; report the broken spots inside it
+                structure.children.extend(self._nested_errors(node, source_code))
+            parent_structures.append(structure)
+
+        def traverse(node: Node, parent_structures: list, in_error: bool = False):
             # Handle parse errors
-            if node.type == "ERROR":
-                if self.show_errors:
-                    error_node = StructureNode(
-                        type="parse-error",
-                        name="invalid syntax",
-                        start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
-                    )
-                    parent_structures.append(error_node)
+            if node.type == "ERROR" or node.is_missing:
+                if self.show_errors and not in_error:
+                    parent_structures.append(self._error_node(node, source_code))
+                # Declarations the parser completed inside a broken region
+                # (a typo above them swallowed them) are still recovered
+                for child in node.children:
+                    traverse(child, parent_structures, in_error=True)
                 return
 
-            # Type declarations (struct, interface)
+            # Type declarations (struct, interface): one node per type_spec
             if node.type == "type_declaration":
-                type_node = self._extract_type(node, source_code)
-                if type_node:
-                    parent_structures.append(type_node)
+                for structure, span in self._extract_types(node, source_code):
+                    declared(structure, span, parent_structures)
 
             # Function declarations (standalone functions)
             elif node.type == "function_declaration":
-                func_node = self._extract_function(node, source_code)
-                parent_structures.append(func_node)
+                declared(self._extract_function(node, source_code, imported), node,
+                         parent_structures)
 
             # Method declarations (functions with receivers)
             elif node.type == "method_declaration":
-                method_node = self._extract_method(node, source_code)
-                parent_structures.append(method_node)
+                declared(self._extract_method(node, source_code, imported), node,
+                         parent_structures)
+
+            # Constant and variable declarations: one node per declared name
+            elif node.type in _VALUE_DECLARATIONS:
+                parent_structures.extend(self._extract_values(node, source_code))
 
             # Import declarations
             elif node.type == "import_declaration":
@@ -167,23 +230,59 @@ class GoLanguage(BaseLanguage):
             else:
                 # Keep traversing
                 for child in node.children:
-                    traverse(child, parent_structures)
+                    traverse(child, parent_structures, in_error)
 
         traverse(root, structures)
+        self._link_methods(structures)
         return structures
 
-    def _extract_type(self, node: Node, source_code: bytes) -> Optional[StructureNode]:
-        """Extract type declaration (struct, interface, etc.)."""
-        # type_declaration has a type_spec child
-        type_spec = None
-        for child in node.children:
-            if child.type == "type_spec":
-                type_spec = child
-                break
-
-        if not type_spec:
-            return None
-
+    @staticmethod
+    def _set_spans(structure: StructureNode, node: Node) -> None:
+        """Byte ranges of the declared name and of the body: the block of a
+        function or method, the type expression of a type."""
+        if node.type == "type_declaration":
+            node = next((c for c in node.children if c.type in _TYPE_SPECS), node)
+        if node.type in _TYPE_SPECS:
+            body = node.child_by_field_name("type")
+        else:
+            body = node.child_by_field_name("body")
+        name = node.child_by_field_name("name")
+        if name is not None:
+            structure.name_span = (name.start_byte, name.end_byte)
+        if body is not None:
+            structure.body_span = (body.start_byte, body.end_byte)
+
+    @staticmethod
+    def _link_methods(structures: list[StructureNode]) -> None:
+        """Methods stay top-level nodes (the flat view); each type declared
+        in the file lists the names of its methods found here."""
+        types = {node.name: node for node in structures
+                 if node.type in ("struct", "interface", "type")}
+        for node in structures:
+            owner = types.get(node.receiver_type) if node.type == "method" else None
+            if owner is not None:
+                owner.methods = [*(owner.methods or ()), node.name]
+
+    def _extract_types(self, node: Node,
+                       source_code: bytes) -> list[tuple[StructureNode, Node]]:
+        """(node, the syntax it spans) per type of a type declaration. A
+        spec of a group spans its own lines and carries its own doc comment,
+        or the group's when it has none — as _extract_values does."""
+        specs = [c for c in node.named_children if c.type in _TYPE_SPECS]
+        grouped = len(specs) != 1 or any(c.type == "(" for c in node.children)
+        group_doc = self._extract_doc(node, source_code)
+        types = []
+        for spec in specs:
+            span = spec if grouped else node
+            doc = (self._extract_doc(spec, source_code) if grouped else None) or group_doc
+            structure = self._extract_type(spec, span, doc, source_code)
+            if structure is not None:
+                types.append((structure, span))
+        return types
+
+    def _extract_type(self, type_spec: Node, span: Node, doc: Optional[str],
+                      source_code: bytes) -> Optional[StructureNode]:
+        """Extract one type (struct, interface, etc.) of a declaration."""
         # Get type name
         name_node = type_spec.child_by_field_name("name")
         name = self._get_node_text(name_node, source_code) if name_node else "unnamed"
@@ -204,42 +303,128 @@ class GoLanguage(BaseLanguage):
             # For other types (aliases, etc.), use generic "type"
             struct_type = "type"
 
-        # Extract comments
-        docstring = self._extract_comment(node, source_code)
+        # Doc comment: first line as the docstring
+        docstring = next((line for line in doc.splitlines() if line), None) if doc else None
+        deprecated, annotations = self._doc_annotations(doc)
+
+        fields = self._extract_struct_fields(type_node, source_code) \
+            if type_kind == "struct_type" else None
 
         # Check for exported (public) types
         modifiers = self._extract_type_modifiers(name)
 
         # Calculate complexity
-        complexity = self._calculate_complexity(node)
+        complexity = self._calculate_complexity(span)
 
         return StructureNode(
             type=struct_type,
             name=name,
-            start_line=node.start_point[0] + 1,
-            end_line=node.end_point[0] + 1,
+            start_line=span.start_point[0] + 1,
+            end_line=span.end_point[0] + 1,
             docstring=docstring,
+            doc=doc,
+            deprecated=deprecated,
+            annotations=annotations,
             modifiers=modifiers,
+            fields=fields,
             complexity=complexity,
+            visibility=self._visibility(name),
             children=[]
         )
 
-    def _extract_function(self, node: Node, source_code: bytes) -> StructureNode:
-        """Extract standalone function declaration."""
+    def _extract_values(self, node: Node, source_code: bytes) -> list[StructureNode]:
+        """Nodes of a const or var declaration, one per name. A spec of a
+        group spans its own lines and carries its own doc comment, or the
+        group's when it has none (as go doc shows it); the signature is the
+        declared type — values stay out of the outline."""
+        kind, spec_type = _VALUE_DECLARATIONS[node.type]
+        specs = []
+        for child in node.named_children:
+            if child.type == spec_type:
+                specs.append(child)
+            elif child.type.endswith("_spec_list"):  # some grammar versions group specs in one
+                specs.extend(c for c in child.named_children if c.type == spec_type)
+        grouped = len(specs) != 1 or any(c.type == "(" or c.type.endswith("_spec_list")
+                                         for c in node.children)
+        group_doc = self._extract_doc(node, source_code)
+
+        values = []
+        for spec in specs:
+            span = spec if grouped else node
+            doc = (self._extract_doc(spec, source_code) if grouped else None) or group_doc
+            docstring = next((line for line in doc.splitlines() if line), None) if doc else None
+            deprecated, annotations = self._doc_annotations(doc)
+            type_node = spec.child_by_field_name("type")
+            for name_node in spec.children_by_field_name("name"):
+                name = self._get_node_text(name_node, source_code)
+                structure = StructureNode(
+                    type=kind,
+                    name=name,
+                    start_line=span.start_point[0] + 1,
+                    end_line=span.end_point[0] + 1,
+                    signature=self._get_node_text(type_node, source_code) if type_node else None,
+                    docstring=docstring,
+                    doc=doc,
+                    deprecated=deprecated,
+                    annotations=annotations,
+                    modifiers=self._extract_type_modifiers(name),
+                    visibility=self._visibility(name),
+                    children=[]
+                )
+                self._set_offsets(structure, span, source_code)
+                structure.name_span = (name_node.start_byte, name_node.end_byte)
+                values.append(structure)
+        return values
+
+    def _extract_struct_fields(self, struct_node: Node, source_code: bytes) -> list[StructField]:
+        """Fields of a struct_type in declaration order. `a, b int` yields
+        one field per name; an embedded field has an empty name and keeps
+        its pointer star in the type ("*Base")."""
+        fields = []
+        field_list = next((c for c in struct_node.children
+                           if c.type == "field_declaration_list"), None)
+        if field_list is None:
+            return fields
+        for decl in field_list.named_children:
+            if decl.type != "field_declaration":
+                continue
+            type_node = decl.child_by_field_name("type")
+            type_text = (self._normalize_signature(self._get_node_text(type_node, source_code))
+                         if type_node else "")
+            tag_node = decl.child_by_field_name("tag")
+            tag = self._get_node_text(tag_node, source_code) if tag_node else None
+            names = [self._get_node_text(n, source_code)
+                     for n in decl.children_by_field_name("name")]
+            if not names:
+                if any(c.type == "*" for c in decl.children):
+                    type_text = "*" + type_text
+                fields.append(StructField(name="", type=type_text, tag=tag))
+                continue
+            fields.extend(StructField(name=name, type=type_text, tag=tag) for name in names)
+        return fields
+
+    def _extract_function(self, node: Node, source_code: bytes,
+                          imported: frozenset[str] = frozenset()) -> StructureNode:
+        """Extract standalone function declaration; imported are the file's
+        import qualifiers, for uses_packages."""
         name_node = node.child_by_field_name("name")
         name = self._get_node_text(name_node, source_code) if name_node else "unnamed"
 
         # Get signature
         signature = self._extract_signature(node, source_code)
+        full_signature = self._build_full_signature(node, name, source_code)
 
         # Extract comments
         docstring = self._extract_comment(node, source_code)
+        doc = self._extract_doc(node, source_code)
+        deprecated, annotations = self._doc_annotations(doc)
 
         # Check for exported (public) functions
         modifiers = self._extract_function_modifiers(name, node, source_code)
 
         # Calculate complexity
         complexity = self._calculate_complexity(node)
+        complexity["cyclomatic"] = self._cyclomatic_complexity(node)
 
         return StructureNode(
             type="function",
@@ -247,14 +432,22 @@ class GoLanguage(BaseLanguage):
             start_line=node.start_point[0] + 1,
             end_line=node.end_point[0] + 1,
             signature=signature,
+            full_signature=full_signature,
             docstring=docstring,
+            doc=doc,
+            deprecated=deprecated,
+            annotations=annotations,
             modifiers=modifiers,
             complexity=complexity,
+            visibility=self._visibility(name),
+            uses_packages=go_imports.package_uses(node, source_code, imported) or None,
             children=[]
         )
 
-    def _extract_method(self, node: Node, source_code: bytes) -> StructureNode:
-        """Extract method declaration (function with receiver)."""
+    def _extract_method(self, node: Node, source_code: bytes,
+                        imported: frozenset[str] = frozenset()) -> StructureNode:
+        """Extract method declaration (function with receiver); imported as
+        for _extract_function."""
         name_node = node.child_by_field_name("name")
         name = self._get_node_text(name_node, source_code) if name_node else "unnamed"
 
@@ -263,18 +456,23 @@ class GoLanguage(BaseLanguage):
         receiver_text = None
         if receiver_node:
             receiver_text = self._get_node_text(receiver_node, source_code).strip()
+        receiver_type, is_pointer = go_syntax.receiver(node, source_code)
 
         # Get signature
         signature = self._extract_signature(node, source_code, receiver_text)
+        full_signature = self._build_full_signature(node, name, source_code)
 
         # Extract comments
         docstring = self._extract_comment(node, source_code)
+        doc = self._extract_doc(node, source_code)
+        deprecated, annotations = self._doc_annotations(doc)
 
         # Check for exported (public) methods
         modifiers = self._extract_function_modifiers(name, node, source_code)
 
         # Calculate complexity
         complexity = self._calculate_complexity(node)
+        complexity["cyclomatic"] = self._cyclomatic_complexity(node)
 
         return StructureNode(
             type="method",
@@ -282,9 +480,17 @@ class GoLanguage(BaseLanguage):
             start_line=node.start_point[0] + 1,
             end_line=node.end_point[0] + 1,
             signature=signature,
+            full_signature=full_signature,
             docstring=docstring,
+            doc=doc,
+            deprecated=deprecated,
+            annotations=annotations,
             modifiers=modifiers,
             complexity=complexity,
+            receiver_type=receiver_type,
+            receiver_kind="pointer" if is_pointer else "value",
+            visibility=self._visibility(name),
+            uses_packages=go_imports.package_uses(node, source_code, imported) or None,
             children=[]
         )
 
@@ -320,29 +526,156 @@ class GoLanguage(BaseLanguage):
         signature = "".join(parts) if parts else None
         return self._normalize_signature(signature) if signature else None
 
-    def _extract_comment(self, node: Node, source_code: bytes) -> Optional[str]:
-        """Extract comment immediately preceding a declaration."""
-        # In Go, comments are typically previous siblings
-        prev = node.prev_sibling
+    # Decision points for cyclomatic complexity. Only non-default cases count:
+    # a switch with N cases and a default adds N paths.
+    _DECISION_NODES = frozenset({
+        "if_statement", "for_statement",
+        "expression_case", "type_case", "communication_case",
+    })
+
+    def _cyclomatic_complexity(self, node: Node) -> int:
+        """McCabe complexity of a function: 1 + if/for/case/&&/||.
+
+        Function literals inside the body count toward the enclosing
+        function — they are not extracted as symbols of their own.
+        """
+        count = 1
+        stack = [node.child_by_field_name("body") or node]
+        while stack:
+            current = stack.pop()
+            if current.type in self._DECISION_NODES:
+                count += 1
+            elif current.type == "binary_expression":
+                operator = current.child_by_field_name("operator")
+                if operator is not None and operator.type in ("&&", "||"):
+                    count += 1
+            stack.extend(current.children)
+        return count
+
+    def _build_full_signature(self, node: Node, name: str, source_code: bytes) -> str:
+        """Rebuild "(s *S) Name[T any](a, b int, rest ...string) (*T, error)"
+        from the declaration's fields. Parameter grouping, variadics and
+        named results are kept; comments and line breaks inside the lists
+        are dropped."""
+        header = go_syntax.format_header(node, name, source_code)
+        receiver_node = node.child_by_field_name("receiver")
+        if receiver_node:
+            return go_syntax.format_parameter_list(receiver_node, source_code) + " " + header
+        return header
 
+    def _extract_comment(self, node: Node, source_code: bytes) -> Optional[str]:
+        """Extract comment immediately preceding a declaration (first line)."""
+        lines = self._doc_comment_lines(node, source_code)
+        return next((line for line in lines if line), None)
+
+    def _extract_doc(self, node: Node, source_code: bytes) -> Optional[str]:
+        """Full doc comment of a declaration, markers stripped."""
+        return "\n".join(self._doc_comment_lines(node, source_code)).strip() or None
+
+    @staticmethod
+    def _doc_annotations(doc: Optional[str]) -> tuple[Optional[bool], Optional[dict[str, str]]]:
+        """(deprecated, annotations) of a doc comment. Deprecated follows
+        the Go convention: a paragraph that starts with "Deprecated:".
+        Annotations are "@key: value" lines; a repeated key keeps the last."""
+        if not doc:
+            return None, None
+        paragraphs = re.split(r"\n\s*\n", doc)
+        deprecated = any(p.lstrip().startswith("Deprecated:") for p in paragraphs) or None
+        annotations = {}
+        for line in doc.splitlines():
+            match = _DOC_ANNOTATION.match(line.strip())
+            if match:
+                annotations[match.group(1)] = match.group(2).strip()
+        return deprecated, annotations or None
+
+    def _doc_comment_lines(self, node: Node, source_code: bytes) -> list[str]:
+        """Lines of the comment group directly above node. A blank line ends
+        the group, so a detached comment is not mistaken for a doc comment;
+        neither is a comment trailing the code on the line above."""
         comments = []
-        while prev and prev.type == "comment":
-            comment_text = self._get_node_text(prev, source_code).strip()
-            # Remove comment markers
-            if comment_text.startswith("//"):
-                comment_text = comment_text[2:].strip()
-            elif comment_text.startswith("/*"):
-                comment_text = comment_text[2:].strip()
-                if comment_text.endswith("*/"):
-                    comment_text = comment_text[:-2].strip()
-            if comment_text:
-                comments.insert(0, comment_text)
+        next_row = node.start_point[0]
+        prev = node.prev_sibling
+        while prev and prev.type == "comment" and prev.end_point[0] >= next_row - 1:
+            before = prev.prev_sibling
+            if before is not None and before.end_point[0] == prev.start_point[0]:
+                break  # a trailing comment of the line above ("A = 1 // one"), not a doc
+
+            comments.insert(0, self._strip_comment_markers(
+                self._get_node_text(prev, source_code)))
+            next_row = prev.start_point[0]
             prev = prev.prev_sibling
+        return [line for text in comments for line in text.splitlines()]
+
+    @staticmethod
+    def _strip_comment_markers(comment_text: str) -> str:
+        comment_text = comment_text.strip()
+        if comment_text.startswith("//"):
+            return comment_text[2:].strip()
+        if comment_text.startswith("/*"):
+            comment_text = comment_text[2:]
+            if comment_text.endswith("*/"):
+                comment_text = comment_text[:-2]
+            return "\n".join(line.strip().lstrip("*").strip()
+                             for line in comment_text.strip().splitlines())
+        return comment_text
+
+    def _root(self, source_code: bytes, root: Optional[Node]) -> Optional[Node]:
+        """root, or the root of source_code's tree; None when it fails to parse."""
+        if root is not None:
+            return root
+        try:
+            return self._parse(source_code).root_node
+        except Exception:
+            return None
 
-        if comments:
-            # Return first line
-            return comments[0]
-        return None
+    def extract_file_doc(self, source_code: bytes, root: Optional[Node] = None) -> Optional[str]:
+        """Package doc comment: the comment group directly above `package`."""
+        root = self._root(source_code, root)
+        if root is None:
+            return None
+        clause = next((c for c in root.children if c.type == "package_clause"), None)
+        return self._extract_doc(clause, source_code) if clause else None
+
+    def is_generated(self, source_code: bytes, root: Optional[Node] = None) -> bool:
+        """The go/ast.IsGenerated rule: a "// Code generated ... DO NOT
+        EDIT." line comment anywhere before the package clause."""
+        root = self._root(source_code, root)
+        if root is None:
+            return False
+        for child in root.children:
+            if child.type == "package_clause":
+                break
+            if child.type == "comment" and _GENERATED_HEADER.match(
+                    go_syntax.node_text(child, source_code).rstrip("\r")):
+                return True
+        return False
+
+    def build_constraint(self, source_code: bytes) -> Optional[str]:
+        """//go:build expression of the file header; legacy "// +build"
+        lines are rewritten into one."""
+        return buildtags.find_constraint(source_code)
+
+    def import_list(self, source_code: bytes, root: Optional[Node] = None) -> Optional[list[dict]]:
+        """Import specs with alias and a best-effort usage check
+        (golang.imports.import_list)."""
+        root = self._root(source_code, root)
+        if root is None:
+            return None
+        go_file = go_syntax.GoFile(path="", source=source_code, root=root,
+                                   package=go_syntax.package_name(root, source_code))
+        return [imp.to_dict() for imp in go_imports.import_list(go_file)]
+
+    def extract_namespace(self, source_code: bytes, root: Optional[Node] = None) -> Optional[str]:
+        """Package clause name ("package users" → "users")."""
+        root = self._root(source_code, root)
+        if root is None:
+            return None
+        return go_syntax.package_name(root, source_code)
+
+    @staticmethod
+    def _visibility(name: str) -> str:
+        """Go spec: exported iff the identifier starts with an upper-case letter."""
+        return "exported" if name[:1].isupper() else "unexported"
 
     def _extract_type_modifiers(self, name: str) -> list[str]:
         """Extract modifiers for types (public/private based on capitalization)."""
@@ -363,10 +696,52 @@ class GoLanguage(BaseLanguage):
 
         return modifiers
 
+    def _error_node(self, node: Node, source_code: bytes) -> StructureNode:
+        """parse-error node for an ERROR node, or a token the parser had to
+        assume (MISSING: an unclosed brace, a missing paren)."""
+        error_node = StructureNode(
+            type="parse-error",
+            name=f"missing {node.type}" if node.is_missing else "invalid syntax",
+            start_line=node.start_point[0] + 1,
+            end_line=node.end_point[0] + 1,
+            start_column=node.start_point[1] + 1
+        )
+        self._set_offsets(error_node, node, source_code)
+        return error_node
+
+    def _nested_errors(self, node: Node, source_code: bytes) -> list[StructureNode]:
+        """The outermost ERROR and MISSING nodes below node, in source order."""
+        errors = []
+        stack = list(reversed(node.children))
+        while stack:
+            current = stack.pop()
+            if current.type == "ERROR" or current.is_missing:
+                errors.append(self._error_node(current, source_code))
+            elif current.has_error:
+                stack.extend(reversed(current.children))
+        return errors
+
+    def _should_use_fallback(self, root_node) -> bool:
+        """Regex fallback only when the tree holds no declaration at all:
+        what tree-sitter completed, even amid errors, beats regex guesses."""
+        if not super()._should_use_fallback(root_node):
+            return False
+        return not any(node.type in _DECLARATION_TYPES for node in go_syntax.walk(root_node))
+
     def _fallback_extract(self, source_code: bytes) -> list[StructureNode]:
         """Regex-based extraction for severely malformed files."""
         text = source_code.decode('utf-8', errors='replace')
         structures = []
+        if self.show_errors:
+            # The regex results carry no positions of their own errors;
+            # the whole file is the broken region
+            structures.append(StructureNode(
+                type="parse-error",
+                name="invalid syntax",
+                start_line=1,
+                end_line=max(1, len(text.splitlines())),
+                start_column=1
+            ))
 
         # Find type declarations
         for match in re.finditer(r'^type\s+(\w+)\s+(struct|interface)', text, re.MULTILINE):
@@ -37,6 +37,7 @@ class JavaLanguage(BaseLanguage):
     """
 
     CONDENSE_STRATEGY = "skeleton"
+    COMMENT_NODE_TYPES = ("line_comment", "block_comment")
 
     # Reachability: public API (`public` in modifiers) is reachable from outside
     # the corpus; non-public & unused → dead candidate. Annotations (Spring etc.)
@@ -125,7 +126,8 @@ class JavaLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     parent_structures.append(error_node)
                 return
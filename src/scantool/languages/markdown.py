@@ -138,7 +138,8 @@ class MarkdownLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     # Add to current parent or root
                     if heading_stack:
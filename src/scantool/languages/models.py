@@ -14,6 +14,15 @@ from typing import Optional
 # ===========================================================================
 
 
+@dataclass
+class StructField:
+    """One field of a struct/record type."""
+
+    name: str  # "" for an embedded field
+    type: str  # Type expression as written, e.g. "*User", "map[string]int"
+    tag: Optional[str] = None  # Raw tag literal incl. backticks, e.g. `json:"id"`
+
+
 @dataclass
 class StructureNode:
     """Represents a node in the file structure with rich metadata.
@@ -27,13 +36,33 @@ class StructureNode:
     start_line: int
     end_line: int
     children: list["StructureNode"] = field(default_factory=list)
+    start_column: Optional[int] = None  # 1-based byte column; set on parse-error nodes
+    start_offset: Optional[int] = None  # Byte offset of the first byte in the file (0-based)
+    end_offset: Optional[int] = None  # Byte offset just past the last byte (exclusive)
+    start_utf16_column: Optional[int] = None  # 0-based UTF-16 code units, as LSP counts
+    end_utf16_column: Optional[int] = None  # 0-based UTF-16 column of end_offset on end_line
+    name_span: Optional[tuple[int, int]] = None  # Byte offsets of the declared identifier (Go)
+    body_span: Optional[tuple[int, int]] = None  # Byte offsets of the body / type expression (Go)
 
     # Enhanced metadata (optional)
+    symbol_id: Optional[str] = None  # Position-free identity, e.g. "method:users.Service.Get"
     signature: Optional[str] = None  # Function signature with types
+    full_signature: Optional[str] = None  # Declaration header incl. name, e.g. "(s *S) Get(id int64) error"
+    body_hash: Optional[str] = None  # sha1 of the declaration's lines (delta.block_hash): comments count
     decorators: list[str] = field(default_factory=list)  # @decorators
     docstring: Optional[str] = None  # First line of docstring
+    doc: Optional[str] = None  # Full doc comment, comment markers stripped
+    deprecated: Optional[bool] = None  # Go: doc has a paragraph starting "Deprecated:"
+    annotations: Optional[dict[str, str]] = None  # "@key: value" lines of the doc comment
     complexity: Optional[dict] = None  # {"lines": int, "depth": int, "branches": int}
     modifiers: list[str] = field(default_factory=list)  # async, static, public, etc.
+    fields: Optional[list[StructField]] = None  # Struct fields (Go), declaration order
+    receiver_type: Optional[str] = None  # Go method: receiver base type, no "*" or type args
+    receiver_kind: Optional[str] = None  # Go method: "value" or "pointer" receiver
+    func_kind: Optional[str] = None  # FUNC_KINDS override; None = derived from type (function_kind)
+    visibility: Optional[str] = None  # Go: "exported" or "unexported" (identifier case)
+    methods: Optional[list[str]] = None  # Go type: names of its methods in the same file
+    uses_packages: Optional[list[str]] = None  # Go function: import qualifiers its body uses
     file_metadata: Optional[dict] = None  # File-level metadata: size, timestamps
 
     # Entropy-based saliency (set by FileScanner._annotate_salient_code)
@@ -43,6 +72,11 @@ class StructureNode:
     recent_edits: Optional[int] = None  # Distinct commits behind this node's lines (90d window)
     delta_status: Optional[str] = None  # "new"/"changed" vs previous scan (delta mode)
 
+    @property
+    def line_count(self) -> int:
+        """Lines the declaration spans, start and end line included."""
+        return self.end_line - self.start_line + 1
+
     def __repr__(self):
         return f"{self.type}: {self.name} ({self.start_line}-{self.end_line})"
 
@@ -62,6 +96,115 @@ def is_unsupported_stub(structures: Optional[list["StructureNode"]]) -> bool:
     )
 
 
+# What a function-like node is, emitted as "func_kind": a free function, a
+# method (constructors included) or a function literal bound to a name
+FUNC_KINDS = ("function", "method", "func_literal")
+_TYPE_FUNC_KINDS = {"function": "function", "method": "method", "constructor": "method"}
+
+
+def function_kind(node: "StructureNode") -> Optional[str]:
+    """FUNC_KINDS entry of a function, method or constructor node (the
+    scanner's func_kind if it set one); None for every other node."""
+    if node.type not in _TYPE_FUNC_KINDS:
+        return None
+    return node.func_kind or _TYPE_FUNC_KINDS[node.type]
+
+
+# Reasons a file in a directory scan was listed but not (fully) scanned
+SKIP_TOO_LARGE = "too_large"      # over scan_directory's max_file_size
+SKIP_PARSE_ERROR = "parse_error"  # the file could not be scanned; result is an error node
+SKIP_PANIC = "panic"              # the scanner itself crashed on the file (logged with the stack)
+SKIP_TIMED_OUT = "timed_out"      # parsing took longer than scan_directory's file_timeout
+SKIP_BINARY = "binary"            # NUL byte in the first 8000 bytes
+SKIP_INVALID_ENCODING = "invalid_encoding"  # not valid UTF-8, scanned with invalid_encoding="skip"
+SKIP_PERMISSION = "permission_denied"  # file or directory not readable
+SKIP_UNREADABLE = "unreadable"    # any other OS error listing or reading it
+SKIP_MAX_DEPTH = "max_depth"      # directory below max_depth, listed on request
+SKIP_POST_PROCESS = "post_process"  # a FileScanner post-processor raised on the result
+
+
+@dataclass
+class SkippedFile:
+    """A file (or a directory it could not list) a directory scan did not
+    produce a structure for, and why."""
+
+    path: str
+    reason: str  # SKIP_* constant
+    detail: Optional[str] = None  # size, error message
+
+
+def skip_reason(structures: Optional[list["StructureNode"]]) -> Optional[str]:
+    """Why a file's scan result carries no structure, None if it does."""
+    if not structures:
+        return None
+    node = structures[0]
+    if node.type == "error":
+        return (node.file_metadata or {}).get("skipped", SKIP_PARSE_ERROR)
+    if node.type == "file-info" and node.file_metadata:
+        return node.file_metadata.get("skipped")
+    return None
+
+
+def skipped_files(results: dict[str, Optional[list["StructureNode"]]]) -> list[SkippedFile]:
+    """Every skipped file of a scan_directory result, in result order."""
+    skipped = []
+    for path, structures in results.items():
+        reason = skip_reason(structures)
+        if reason is None:
+            continue
+        node = structures[0]
+        metadata = node.file_metadata or {}
+        detail = node.name if node.type == "error" else \
+            metadata.get("error") or metadata.get("encoding_warning") or \
+            metadata.get("size_formatted")
+        skipped.append(SkippedFile(path=path, reason=reason, detail=detail))
+    return skipped
+
+
+@dataclass
+class ParseError:
+    """A syntax error or scan failure in one file, positioned for diagnostics."""
+
+    file: str
+    line: int
+    column: Optional[int]  # 1-based byte column; None when the whole file failed to scan
+    message: str
+    end_line: Optional[int] = None
+    reason: Optional[str] = None  # SKIP_PANIC / SKIP_TIMED_OUT / SKIP_POST_PROCESS for a failed scan
+
+    def to_dict(self) -> dict:
+        data = {"line": self.line, "message": self.message}
+        if self.column is not None:
+            data["column"] = self.column
+        if self.end_line is not None and self.end_line != self.line:
+            data["end_line"] = self.end_line
+        if self.reason is not None:
+            data["reason"] = self.reason
+        return data
+
+
+def parse_errors(file_path: str, structures: Optional[list["StructureNode"]]) -> list[ParseError]:
+    """Every parse-error node of a file result (any depth, source order),
+    or the scan failure when the file has an error node instead."""
+    if not structures:
+        return []
+    if structures[0].type == "error":
+        reason = (structures[0].file_metadata or {}).get("skipped")
+        return [ParseError(file_path, 1, None, structures[0].name, reason=reason)]
+    errors = []
+
+    def walk(nodes: list["StructureNode"]):
+        for node in nodes:
+            if node.type == "parse-error":
+                errors.append(ParseError(file_path, node.start_line, node.start_column,
+                                         node.name, node.end_line))
+            walk(node.children)
+
+    walk(structures)
+    errors.sort(key=lambda e: (e.line, e.column or 0))
+    return errors
+
+
 # ===========================================================================
 # Analysis models (from analyzers)
 # ===========================================================================
@@ -130,7 +130,8 @@ class PHPLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     parent_structures.append(error_node)
                 return
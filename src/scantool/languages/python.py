@@ -41,6 +41,8 @@ class PythonLanguage(BaseLanguage):
     - extract_calls(): Find function/method calls
     """
 
+    COMMENT_NODE_TYPES = ("comment",)
+
     def __init__(self, **kwargs):
         super().__init__(**kwargs)
         self.parser = Parser()
@@ -60,6 +62,13 @@ class PythonLanguage(BaseLanguage):
         name = defn.name
         return name.startswith("_") or name.startswith(self._DISPATCH_PREFIXES)
 
+    def is_exported(self, node, parent=None) -> bool:
+        """Python has no visibility keywords: a leading underscore marks a name
+        private by convention (dunders included — protocol, not API)."""
+        if parent is not None and not self.is_exported(parent):
+            return False
+        return bool(node.name) and not node.name.startswith("_")
+
     # ===========================================================================
     # Metadata (REQUIRED)
     # ===========================================================================
@@ -154,7 +163,8 @@ class PythonLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     parent_structures.append(error_node)
                 return
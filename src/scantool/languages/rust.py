@@ -37,6 +37,7 @@ class RustLanguage(BaseLanguage):
     """
 
     CONDENSE_STRATEGY = "skeleton"
+    COMMENT_NODE_TYPES = ("line_comment", "block_comment")
     IMPORT_GROUP_LABEL = "use statements"
 
     # ── Reachability contract (dead-code detection) ──────────────────────────
@@ -148,7 +149,8 @@ class RustLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     parent_structures.append(error_node)
                 return
@@ -213,6 +213,8 @@ COMMON_SKIP_FILES = {
     ".dockerignore",
     ".prettierignore",
     ".eslintignore",
+    # Our own config
+    ".scanignore",
 }
 
 # File extensions to skip (compiled/binary)
@@ -30,6 +30,11 @@ class TypeScriptLanguage(BaseLanguage):
 
     Provides both structure scanning and semantic analysis:
     - scan(): Extract classes, interfaces, functions, methods with signatures and metadata
+      Node types: class, method, function (declarations and arrow functions
+      assigned to const/let), interface, type-alias, constant (exported
+      non-function consts only — module-private consts are noise). Syntax
+      the grammar can't parse becomes a parse-error node; heavily broken
+      files fall back to regex extraction of the same declaration kinds.
     - extract_imports(): Find import/require statements
     - find_entry_points(): Find exports, app instances
     - extract_definitions(): Convert scan() output to DefinitionInfo
@@ -37,6 +42,7 @@ class TypeScriptLanguage(BaseLanguage):
     """
 
     CONDENSE_STRATEGY = "skeleton"
+    COMMENT_NODE_TYPES = ("comment",)
 
     # ── Reachability contract (dead-code detection) ──────────────────────────
     # Off-graph channels the static call graph cannot see in TS/JS:
@@ -79,6 +85,13 @@ class TypeScriptLanguage(BaseLanguage):
     def get_language_name(cls) -> str:
         return "TypeScript/JavaScript"
 
+    _TYPESCRIPT_EXTENSIONS = (".ts", ".tsx", ".mts", ".cts")
+
+    @classmethod
+    def get_file_language_name(cls, filename: str) -> str:
+        return "TypeScript" if filename.lower().endswith(cls._TYPESCRIPT_EXTENSIONS) \
+            else "JavaScript"
+
     @classmethod
     def get_priority(cls) -> int:
         return 10
@@ -165,7 +178,8 @@ class TypeScriptLanguage(BaseLanguage):
                         type="parse-error",
                         name="invalid syntax",
                         start_line=node.start_point[0] + 1,
-                        end_line=node.end_point[0] + 1
+                        end_line=node.end_point[0] + 1,
+                        start_column=node.start_point[1] + 1
                     )
                     parent_structures.append(error_node)
                 return
@@ -198,11 +212,17 @@ class TypeScriptLanguage(BaseLanguage):
                 method_node = self._extract_method(node, source_code)
                 parent_structures.append(method_node)
 
-            # Arrow functions (const foo = () => {})
+            # Type aliases (type Id = string | number)
+            elif node.type == "type_alias_declaration":
+                parent_structures.append(self._extract_type_alias(node, source_code))
+
+            # Arrow functions (const foo = () => {}); other exported consts
             elif node.type == "lexical_declaration":
                 arrow_func = self._extract_arrow_function(node, source_code)
                 if arrow_func:
                     parent_structures.append(arrow_func)
+                elif node.parent is not None and node.parent.type == "export_statement":
+                    parent_structures.extend(self._extract_constants(node, source_code))
 
             # Export statements (may contain other structures)
             elif node.type == "export_statement":
@@ -376,6 +396,7 @@ class TypeScriptLanguage(BaseLanguage):
                     return StructureNode(
                         type="function",
                         name=name,
+                        func_kind="func_literal",
                         start_line=node.start_point[0] + 1,
                         end_line=node.end_point[0] + 1,
                         signature=signature,
@@ -387,6 +408,64 @@ class TypeScriptLanguage(BaseLanguage):
 
         return None
 
+    # Long aliased types (big unions, mapped types) are cut in the signature
+    _ALIAS_SIGNATURE_LIMIT = 80
+
+    def _extract_type_alias(self, node: Node, source_code: bytes) -> StructureNode:
+        """Extract `type Name<T> = ...`; the signature is the aliased type."""
+        name_node = node.child_by_field_name("name")
+        name = self._get_node_text(name_node, source_code) if name_node else "unnamed"
+
+        parts = []
+        type_params = node.child_by_field_name("type_parameters")
+        if type_params:
+            parts.append(self._get_node_text(type_params, source_code))
+        value_node = node.child_by_field_name("value")
+        if value_node:
+            value = self._normalize_signature(self._get_node_text(value_node, source_code))
+            if len(value) > self._ALIAS_SIGNATURE_LIMIT:
+                value = value[:self._ALIAS_SIGNATURE_LIMIT].rstrip() + "…"
+            parts.append(f" = {value}")
+        signature = "".join(parts).strip() if parts else None
+
+        return StructureNode(
+            type="type-alias",
+            name=name,
+            start_line=node.start_point[0] + 1,
+            end_line=node.end_point[0] + 1,
+            signature=signature,
+            docstring=self._extract_jsdoc(node.parent if node.parent.type == "export_statement" else node,
+                                          source_code),
+            children=[]
+        )
+
+    def _extract_constants(self, node: Node, source_code: bytes) -> list[StructureNode]:
+        """Extract `export const A = ...` declarators that aren't functions.
+        `let` bindings are mutable state, not constants, and are skipped."""
+        if not node.children or node.children[0].type != "const":
+            return []
+        docstring = self._extract_jsdoc(node.parent, source_code)
+        constants = []
+        for child in node.children:
+            if child.type != "variable_declarator":
+                continue
+            name_node = child.child_by_field_name("name")
+            if name_node is None or name_node.type != "identifier":
+                continue  # destructuring patterns
+            type_node = child.child_by_field_name("type")
+            signature = self._normalize_signature(
+                self._get_node_text(type_node, source_code)) if type_node else None
+            constants.append(StructureNode(
+                type="constant",
+                name=self._get_node_text(name_node, source_code),
+                start_line=child.start_point[0] + 1,
+                end_line=child.end_point[0] + 1,
+                signature=signature,
+                docstring=docstring,
+                children=[]
+            ))
+        return constants
+
     def _extract_signature(self, node: Node, source_code: bytes) -> Optional[str]:
         """Extract function/method signature with parameters and return type."""
         parts = []
@@ -564,6 +643,16 @@ class TypeScriptLanguage(BaseLanguage):
                 signature=f"{generics}({params})"
             ))
 
+        # Find type aliases
+        for match in re.finditer(r'^\s*(?:export\s+)?type\s+(\w+)\s*(?:<[^=]*>)?\s*=', text, re.MULTILINE):
+            line_num = text[:match.start()].count('\n') + 1
+            structures.append(StructureNode(
+                type="type-alias",
+                name=match.group(1) + " (fallback)",
+                start_line=line_num,
+                end_line=line_num
+            ))
+
         # Find arrow functions
         for match in re.finditer(r'^\s*(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s*)?\([^)]*\)\s*=>', text, re.MULTILINE):
             line_num = text[:match.start()].count('\n') + 1
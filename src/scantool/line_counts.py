@@ -0,0 +1,57 @@
+"""
+FILE: line_counts.py
+
+PROBLEM:
+  Lines of code is the cheapest health metric there is, but "code" needs a
+  comment/code split that regex heuristics get wrong — a "//" inside a
+  string, a comment trailing a statement.
+
+SOLUTION:
+  Classify each line from the comment token positions the language's
+  parser reports (BaseLanguage.comment_spans):
+    code     any non-whitespace outside comment tokens — so a statement
+             with a trailing comment is code
+    comment  only comment text (and whitespace); blank lines inside a
+             block comment count as comment too
+    blank    whitespace only, outside any comment
+
+SCOPE:
+  ✓ Exact for tree-sitter languages that declare COMMENT_NODE_TYPES
+  ✗ Docstrings are string literals, so they count as code
+"""
+
+from typing import Optional
+
+Span = tuple[tuple[int, int], tuple[int, int]]
+
+
+def count_lines(source_code: bytes, comment_spans: Optional[list[Span]]) -> dict:
+    """{"total", "code", "comment", "blank"}; without comment_spans only
+    total and blank (every other line would be a guess)."""
+    lines = source_code.split(b"\n")
+    if lines and lines[-1] == b"":
+        lines.pop()  # trailing newline ends the last line, it doesn't start one
+
+    if comment_spans is None:
+        blank = sum(1 for line in lines if not line.strip())
+        return {"total": len(lines), "blank": blank}
+
+    # Per line: byte column ranges covered by comments
+    covered: dict[int, list[tuple[int, int]]] = {}
+    for (start_row, start_col), (end_row, end_col) in comment_spans:
+        for row in range(start_row, end_row + 1):
+            low = start_col if row == start_row else 0
+            high = end_col if row == end_row else len(lines[row]) if row < len(lines) else 0
+            covered.setdefault(row, []).append((low, high))
+
+    counts = {"total": len(lines), "code": 0, "comment": 0, "blank": 0}
+    for row, line in enumerate(lines):
+        ranges = covered.get(row)
+        if not ranges:
+            counts["blank" if not line.strip() else "code"] += 1
+            continue
+        outside = bytearray(line)
+        for low, high in ranges:
+            outside[low:high] = b" " * (min(high, len(line)) - low)
+        counts["code" if bytes(outside).strip() else "comment"] += 1
+    return counts
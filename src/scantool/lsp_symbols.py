@@ -0,0 +1,171 @@
+"""
+FILE: lsp_symbols.py
+
+PROBLEM:
+  Editors ask a language server for textDocument/documentSymbol and expect
+  a DocumentSymbol tree: LSP SymbolKind numbers, 0-based positions in
+  UTF-16 code units, and two ranges per symbol — `range` covering the
+  whole declaration (what the editor folds and highlights) and
+  `selectionRange` covering just the name (where "go to symbol" puts the
+  cursor), which must lie inside `range`. The scan gives 1-based lines,
+  node types by language and flat Go methods.
+
+SOLUTION:
+  document_symbols(structures, source) converts one file's scan result:
+    - kind from the node type (SYMBOL_KINDS; unknown types are Variable)
+    - range from the UTF-16 columns the tree-sitter languages record, or
+      whole lines for languages without them
+    - selectionRange is the first occurrence of the name as a word in the
+      declaration, after decorators and a Go receiver; the start of the
+      range (empty) when the name isn't written out (anonymous, headings
+      with markup)
+    - Go methods become children of their receiver type when the type is
+      declared in the same file, gopls-style; the others stay top-level
+    - detail is the signature
+  The file-info node, import groups and parse errors are not symbols.
+
+SCOPE:
+  ✓ Every language the scanner outlines; CRLF and BOM files (offsets
+    index the file's bytes, see source_text)
+  ✗ Struct fields are not listed: the scan keeps their names and types
+    but no positions
+"""
+
+import re
+from bisect import bisect_right
+from typing import Optional
+
+from .languages import StructureNode
+
+# LSP SymbolKind values (LSP 3.17 §documentSymbol)
+MODULE, NAMESPACE, PACKAGE, CLASS, METHOD, PROPERTY, FIELD = 2, 3, 4, 5, 6, 7, 8
+CONSTRUCTOR, ENUM, INTERFACE, FUNCTION, VARIABLE, CONSTANT = 9, 10, 11, 12, 13, 14
+STRING, KEY, ENUM_MEMBER, STRUCT = 15, 20, 22, 23
+
+# Node type -> SymbolKind, across languages
+SYMBOL_KINDS: dict[str, int] = {
+    "function": FUNCTION, "method": METHOD, "constructor": CONSTRUCTOR,
+    "class": CLASS, "struct": STRUCT, "record": STRUCT, "union": STRUCT,
+    "interface": INTERFACE, "protocol": INTERFACE, "trait": INTERFACE,
+    "enum": ENUM, "enum-member": ENUM_MEMBER, "variant": ENUM_MEMBER,
+    "type": CLASS, "type-alias": CLASS, "typealias": CLASS,  # as gopls does
+    "typedef": CLASS, "macro": CONSTANT,
+    "const": CONSTANT, "constant": CONSTANT,
+    "var": VARIABLE, "variable": VARIABLE, "property": PROPERTY, "field": FIELD,
+    "module": MODULE, "namespace": NAMESPACE, "package": PACKAGE, "impl": NAMESPACE,
+    "heading": STRING, "section": STRING, "key": KEY, "table": KEY,
+}
+
+# Nodes that aren't symbols of the document
+_NOT_SYMBOLS = {"file-info", "imports", "import", "error", "parse-error"}
+
+_LINE_BREAK = re.compile(rb"\r\n|\r|\n")
+_UTF8_BOM = b"\xef\xbb\xbf"
+
+
+class _Text:
+    """Line starts and UTF-16 columns of a file's bytes."""
+
+    def __init__(self, source: bytes):
+        self.source = source
+        self.bom = len(_UTF8_BOM) if source.startswith(_UTF8_BOM) else 0
+        self.starts = [self.bom] + [m.end() for m in _LINE_BREAK.finditer(source)]
+        self.ends = [m.start() for m in _LINE_BREAK.finditer(source)] + [len(source)]
+
+    def _utf16(self, start: int, end: int) -> int:
+        return len(self.source[start:end].decode("utf-8", errors="replace")
+                   .encode("utf-16-le")) // 2
+
+    def line_end(self, line: int) -> int:
+        """UTF-16 length of 0-based line (0 past the end of the file)."""
+        if line >= len(self.starts):
+            return 0
+        return self._utf16(self.starts[line], self.ends[line])
+
+    def line_span(self, line: int) -> tuple[int, int]:
+        """Byte range of 0-based line, without its line break."""
+        if line >= len(self.starts):
+            return len(self.source), len(self.source)
+        return self.starts[line], self.ends[line]
+
+    def position(self, offset: int) -> dict:
+        """LSP position of a byte offset."""
+        line = max(0, bisect_right(self.starts, offset) - 1)
+        return {"line": line, "character": self._utf16(self.starts[line], offset)}
+
+
+def _position(line: int, character: Optional[int]) -> dict:
+    return {"line": max(0, line), "character": max(0, character or 0)}
+
+
+def _range(node: StructureNode, text: _Text) -> dict:
+    start_line, end_line = node.start_line - 1, max(node.start_line, node.end_line) - 1
+    if node.start_utf16_column is not None and node.end_utf16_column is not None:
+        return {"start": _position(start_line, node.start_utf16_column),
+                "end": _position(end_line, node.end_utf16_column)}
+    return {"start": _position(start_line, 0),
+            "end": _position(end_line, text.line_end(end_line))}
+
+
+def _selection_range(node: StructureNode, text: _Text, full: dict) -> dict:
+    """Range of the name inside full, the empty start of full without one."""
+    if node.start_offset is not None and node.end_offset is not None:
+        begin, end = node.start_offset, node.end_offset
+    else:
+        begin, _ = text.line_span(full["start"]["line"])
+        _, end = text.line_span(full["end"]["line"])
+    region = text.source[begin:end]
+    skip = 0
+    if node.decorators:
+        last = region.rfind(node.decorators[-1].encode("utf-8"))
+        skip = last + len(node.decorators[-1].encode("utf-8")) if last >= 0 else 0
+    if node.receiver_type:  # Go: func (r *T) Name — the name follows the receiver
+        closing = region.find(b")", skip)
+        skip = closing + 1 if closing >= 0 else skip
+    name = node.name.rsplit(".", 1)[-1].encode("utf-8")
+    match = re.compile(rb"(?<![\w$])" + re.escape(name) + rb"(?![\w$])").search(region, skip) \
+        if name else None
+    if match is None:
+        return {"start": full["start"], "end": full["start"]}
+    return {"start": text.position(begin + match.start()),
+            "end": text.position(begin + match.end())}
+
+
+def _symbol(node: StructureNode, text: _Text) -> dict:
+    full = _range(node, text)
+    symbol = {"name": node.name or "(anonymous)",
+              "kind": SYMBOL_KINDS.get(node.type, VARIABLE),
+              "range": full,
+              "selectionRange": _selection_range(node, text, full)}
+    if node.signature:
+        symbol["detail"] = node.signature
+    if node.deprecated:
+        symbol["tags"] = [1]  # SymbolTag.Deprecated
+    children = [_symbol(child, text) for child in node.children
+                if child.type not in _NOT_SYMBOLS]
+    if children:
+        symbol["children"] = children
+    return symbol
+
+
+def document_symbols(structures: list[StructureNode], source: bytes) -> list[dict]:
+    """DocumentSymbol[] for one file's scan result; source is the file's
+    bytes as on disk (positions are computed against it)."""
+    text = _Text(source)
+    nodes = [n for n in structures if n.type not in _NOT_SYMBOLS]
+    owners = {n.name: n for n in nodes
+              if n.type in ("struct", "interface", "type") and n.methods is not None}
+    symbols, by_owner = [], {}
+    for node in nodes:
+        owner = owners.get(node.receiver_type) if node.type == "method" else None
+        if owner is not None:
+            by_owner.setdefault(owner.name, []).append(_symbol(node, text))
+            continue
+        symbols.append((node, _symbol(node, text)))
+    result = []
+    for node, symbol in symbols:
+        methods = by_owner.get(node.name) if owners.get(node.name) is node else None
+        if methods:
+            symbol.setdefault("children", []).extend(methods)
+        result.append(symbol)
+    return result
@@ -0,0 +1,79 @@
+"""
+FILE: package_groups.py
+
+PROBLEM:
+  A directory scan's JSON is a flat map of file paths. Go code is
+  organised by package — one directory, one package, plus perhaps an
+  external _test package next to it — and an agent navigating a big
+  result wants that unit, not hundreds of paths to regroup itself.
+
+SOLUTION:
+  Group the file results by (directory, package): the package is the
+  namespace the file declares (Go: the package clause, surfaced as
+  file_metadata["package"]). A directory holding `package users` and
+  `package users_test` files gives two groups, never one merged; files
+  of languages without packages (and non-code files) form the
+  directory's group without a package. Groups come in directory order
+  (parents before children, so the list reads as the directory tree),
+  within a directory the named packages first, by name.
+
+SCOPE:
+  ✓ Any file result set: a full scan, a page, a filtered view
+  ✗ Go build constraints are not applied: a directory with files of the
+    same package for different platforms is one group
+"""
+
+import posixpath
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from .languages import StructureNode
+
+
+@dataclass
+class PackageGroup:
+    dir: str  # relative to the scan root, "/" separators, "." for the root itself
+    package: Optional[str]  # None: files that declare no package
+    files: list[str] = field(default_factory=list)  # result keys, sorted
+
+    def to_dict(self) -> dict:
+        return {"dir": self.dir, "package": self.package, "files": self.files}
+
+
+def file_package(structures: Optional[list[StructureNode]]) -> Optional[str]:
+    """Package recorded on a scan result's file-info node, if any."""
+    if not structures or structures[0].type != "file-info":
+        return None
+    return (structures[0].file_metadata or {}).get("package")
+
+
+def _relative_dir(file_path: str, root: Path) -> str:
+    directory = Path(file_path).resolve().parent
+    try:
+        relative = directory.relative_to(root).as_posix()
+    except ValueError:
+        return directory.as_posix()
+    return relative or "."
+
+
+def group_by_package(results: dict[str, Optional[list[StructureNode]]],
+                     root: str) -> list[PackageGroup]:
+    """The result keys grouped by directory (relative to root) and
+    declared package."""
+    root_path = Path(root).resolve()
+    groups: dict[tuple[str, Optional[str]], PackageGroup] = {}
+    for file_path in sorted(results):
+        structures = results[file_path]
+        directory = _relative_dir(file_path, root_path)
+        package = file_package(structures)
+        key = (directory, package)
+        if key not in groups:
+            groups[key] = PackageGroup(directory, package)
+        groups[key].files.append(file_path)
+
+    def order(group: PackageGroup):
+        parts = () if group.dir == "." else tuple(posixpath.normpath(group.dir).split("/"))
+        return parts, group.package is None, group.package or ""
+
+    return sorted(groups.values(), key=order)
@@ -0,0 +1,80 @@
+"""
+FILE: pagination.py
+
+PROBLEM:
+  A scan of a large directory is one response: megabytes of JSON that an
+  MCP client truncates or rejects outright. max_files only cuts the result
+  off — there is no way to get the rest.
+
+SOLUTION:
+  Cursor pagination over the file results in sorted path order. A page is
+  up to `limit` files; its cursor encodes the last path served and a
+  digest of the whole sorted file set. The next call resumes strictly
+  after that path, so the pages tile the set exactly while it is
+  unchanged. A digest mismatch means files were added or removed between
+  calls: the page is still served (resuming after the same path) and
+  flagged stale, so the caller knows to restart for a consistent view.
+
+SCOPE:
+  ✓ Opaque, URL-safe cursors; no server-side state
+  ✗ Not a snapshot: a file edited between pages shows its current content
+"""
+
+import base64
+import bisect
+import hashlib
+import json
+from dataclasses import dataclass, field
+from typing import Optional
+
+_CURSOR_VERSION = 1
+
+
+@dataclass
+class Page:
+    results: dict = field(default_factory=dict)  # path -> structures, sorted by path
+    offset: int = 0          # index of the first file of the page in the sorted set
+    total: int = 0           # files in the whole set
+    next_cursor: Optional[str] = None  # None on the last page
+    stale: bool = False      # the file set changed since the cursor was issued
+
+
+def _digest(paths: list[str]) -> str:
+    return hashlib.sha1("\n".join(paths).encode("utf-8")).hexdigest()[:16]
+
+
+def encode_cursor(after: str, digest: str) -> str:
+    data = json.dumps({"v": _CURSOR_VERSION, "after": after, "set": digest})
+    return base64.urlsafe_b64encode(data.encode("utf-8")).decode("ascii")
+
+
+def decode_cursor(cursor: str) -> tuple[str, str]:
+    """(last path served, file-set digest). ValueError for anything that
+    isn't a cursor of this version."""
+    try:
+        data = json.loads(base64.urlsafe_b64decode(cursor.encode("ascii")))
+        if data["v"] != _CURSOR_VERSION:
+            raise ValueError
+        return data["after"], data["set"]
+    except (ValueError, KeyError, TypeError) as e:
+        raise ValueError(f"Invalid cursor: {cursor!r} — pass the next_cursor "
+                         f"of the previous page, or none for the first") from e
+
+
+def paginate(results: dict, limit: int, cursor: Optional[str] = None) -> Page:
+    """One page of a scan_directory result: up to limit files after the
+    cursor's position (from the start without one)."""
+    if limit < 1:
+        raise ValueError(f"limit must be at least 1, got {limit}")
+    paths = sorted(results)
+    digest = _digest(paths)
+    page = Page(total=len(paths))
+    if cursor is not None:
+        after, cursor_digest = decode_cursor(cursor)
+        page.offset = bisect.bisect_right(paths, after)
+        page.stale = cursor_digest != digest
+    selected = paths[page.offset:page.offset + limit]
+    page.results = {path: results[path] for path in selected}
+    if page.offset + len(selected) < len(paths):
+        page.next_cursor = encode_cursor(selected[-1], digest)
+    return page
@@ -0,0 +1,72 @@
+"""
+FILE: path_style.py
+
+PROBLEM:
+  Directory results carry absolute paths — right for opening a file in an
+  editor, wrong for output that is diffed or checked into test fixtures:
+  the checkout location leaks into every key, and on Windows so do the
+  backslashes.
+
+SOLUTION:
+  One place that renders a result path in the requested style:
+    - "absolute": the path as scanned (native separators)
+    - "relative": relative to the scan root, always with "/" separators,
+      so the same tree gives the same JSON on every machine and OS
+  The tools apply it to every path field of a result at output time; the
+  scan itself, the cache and cursors keep working on absolute paths.
+  A scan over several roots makes each path relative to the root it lies
+  under; a relative path two roots both have (service/config.go and
+  lib/config.go are both "config.go") is prefixed with its root's name,
+  or with the root's absolute path when two roots share a name too.
+
+SCOPE:
+  ✓ Paths outside the root (followed symlinks) come out as "../..." paths
+    (outside every root: relative to the first)
+  ✗ A path on another Windows drive than the root stays absolute
+"""
+
+import os
+
+PATH_STYLES = ("absolute", "relative")
+
+
+def check_path_style(path_style: str) -> None:
+    if path_style not in PATH_STYLES:
+        raise ValueError(f"Unknown path_style {path_style!r} — "
+                         f"use one of: {', '.join(PATH_STYLES)}")
+
+
+def style_path(path: str, root: str, path_style: str) -> str:
+    """path (absolute, as scanned under root) in path_style."""
+    if path_style == "absolute":
+        return os.path.abspath(path)
+    try:
+        relative = os.path.relpath(path, root)
+    except ValueError:  # Windows: different drive, no relative form
+        return path
+    return relative.replace(os.sep, "/")
+
+
+def root_of(path: str, roots: list[str]) -> str:
+    """The deepest root path lies under, else the first root."""
+    containing = [root for root in roots
+                  if path == root or path.startswith(root.rstrip(os.sep) + os.sep)]
+    return max(containing, key=len) if containing else roots[0]
+
+
+def style_paths(paths, roots: list[str], path_style: str) -> dict[str, str]:
+    """Each path (absolute, as scanned under one of roots) in path_style;
+    relative paths that collide across roots get their root as prefix."""
+    if path_style == "absolute" or len(roots) == 1:
+        return {path: style_path(path, roots[0], path_style) for path in paths}
+    roots = [os.path.abspath(root) for root in roots]
+    names = [os.path.basename(root) for root in roots]
+    labels = {root: os.path.basename(root) if names.count(os.path.basename(root)) == 1
+              else root.replace(os.sep, "/") for root in roots}
+    placed = {path: root_of(os.path.abspath(path), roots) for path in paths}
+    relative = {path: style_path(path, root, "relative") for path, root in placed.items()}
+    owners: dict[str, set[str]] = {}
+    for path, styled in relative.items():
+        owners.setdefault(styled, set()).add(placed[path])
+    return {path: f"{labels[placed[path]]}/{styled}" if len(owners[styled]) > 1 else styled
+            for path, styled in relative.items()}
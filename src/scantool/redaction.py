@@ -0,0 +1,124 @@
+"""
+FILE: redaction.py
+
+PROBLEM:
+  Scanning a codebase with secrets or personal data in string literals
+  (tokens in test fixtures, customer emails in seed data, DSNs as
+  defaults) copies those values into every response — into signatures
+  with default arguments, skeleton and excerpt lines, constant values,
+  extracted strings — and from there into agent logs and LLM context.
+
+SOLUTION:
+  One output-time pass that replaces each string literal's value:
+    "off"     values as scanned
+    "length"  "<string:N>", N = characters between the quotes as written
+    "hash"    "<string:#1a2b3c4d>", the first 8 hex of the SHA-256 of the
+              same text — equal literals stay recognisably equal
+  redact_code() works on code text: "...", '...', `...` and Python
+  triple-quoted strings with their r/b/f/u prefixes. A literal spanning
+  lines keeps its line breaks, so line numbers of the text after it stay
+  right. redact_structures() applies it to the code-bearing fields of a
+  scan result (signatures, decorators, excerpts, skeletons) and returns
+  copies: cached and remembered results keep the real text. Languages
+  where '...' is a character (Go runes, Rust chars and lifetimes, C-like
+  languages) only get their double-quoted and backtick strings redacted.
+
+SCOPE:
+  ✓ scan_file (focus reads too), scan_file_content, scan_directory,
+    and the Go extract_strings, list_constants and list_globals tools
+  ✗ Docstrings and doc comments are documentation, not values: kept
+  ✗ Struct tags (`json:"id"`) are field metadata: kept
+  ✗ Rust raw strings (r#"..."#) and heredocs are not recognised
+"""
+
+import hashlib
+import re
+from dataclasses import replace
+from pathlib import Path
+from typing import Optional
+
+from .languages import StructureNode
+
+REDACT_MODES = ("off", "length", "hash")
+
+# Extensions whose '...' is a character or lifetime, never a string
+_CHAR_QUOTE_EXTENSIONS = {".go", ".rs", ".c", ".h", ".cc", ".cpp", ".cxx", ".hh", ".hpp",
+                          ".hxx", ".java", ".cs", ".swift", ".zig"}
+
+_PREFIX = r"(?:(?<!\w)[rRbBuUfF]{1,2})?"
+_TRIPLE = r"(?P<triple>\"\"\"|''')(?P<triple_body>(?:\\[\s\S]|[\s\S])*?)(?P=triple)"
+_DOUBLE = r"\"(?P<double_body>(?:\\.|[^\"\\\n])*)\""
+_SINGLE = r"'(?P<single_body>(?:\\.|[^'\\\n])*)'"
+_BACKTICK = r"`(?P<backtick_body>[^`]*)`"
+
+_WITH_SINGLE = re.compile(_PREFIX + f"(?:{_TRIPLE}|{_DOUBLE}|{_SINGLE}|{_BACKTICK})")
+_WITHOUT_SINGLE = re.compile(_PREFIX + f"(?:{_TRIPLE}|{_DOUBLE}|{_BACKTICK})")
+
+
+def check_redact_mode(mode: str) -> None:
+    if mode not in REDACT_MODES:
+        raise ValueError(f"Unknown redact_strings {mode!r} — "
+                         f"use one of: {', '.join(REDACT_MODES)}")
+
+
+def redact_value(value: str, mode: str) -> str:
+    """Placeholder for one string value (the text between the quotes);
+    value itself when mode is "off"."""
+    if mode == "off":
+        return value
+    if mode == "hash":
+        return f"<string:#{hashlib.sha256(value.encode('utf-8')).hexdigest()[:8]}>"
+    return f"<string:{len(value)}>"
+
+
+def single_quoted_strings(file_path: Optional[str]) -> bool:
+    """Whether '...' is a string in the file's language (not a rune or
+    char); True when the language is unknown."""
+    return file_path is None or Path(file_path).suffix.lower() not in _CHAR_QUOTE_EXTENSIONS
+
+
+def redact_code(text: str, mode: str, single_quotes: bool = True) -> str:
+    """text with every string literal replaced by its placeholder; the
+    line breaks inside a multi-line literal are kept after it."""
+    if mode == "off" or not text:
+        return text
+    pattern = _WITH_SINGLE if single_quotes else _WITHOUT_SINGLE
+
+    def placeholder(match: re.Match) -> str:
+        body = next(v for k, v in match.groupdict().items()
+                    if k.endswith("_body") and v is not None)
+        return redact_value(body, mode) + "\n" * body.count("\n")
+
+    return pattern.sub(placeholder, text)
+
+
+def _redact_lines(lines: Optional[list[str]], mode: str, single_quotes: bool) -> Optional[list[str]]:
+    if not lines:
+        return lines
+    return redact_code("\n".join(lines), mode, single_quotes).split("\n")
+
+
+def redact_structures(structures: Optional[list[StructureNode]], mode: str,
+                      file_path: Optional[str] = None) -> Optional[list[StructureNode]]:
+    """Copies of a scan result's nodes with string literals redacted in
+    signatures, decorators, code excerpts and skeletons; file_path picks
+    the quoting rules. The input nodes are not changed."""
+    if mode == "off" or not structures:
+        return structures
+    single_quotes = single_quoted_strings(file_path)
+
+    def redact(node: StructureNode) -> StructureNode:
+        changes = {"children": [redact(child) for child in node.children]}
+        for name in ("signature", "full_signature"):
+            value = getattr(node, name)
+            if value:
+                changes[name] = redact_code(value, mode, single_quotes)
+        if node.decorators:
+            changes["decorators"] = [redact_code(d, mode, single_quotes) for d in node.decorators]
+        for name in ("code_excerpt", "code_skeleton"):
+            value = getattr(node, name)
+            if value:
+                changes[name] = _redact_lines(value, mode, single_quotes)
+        return replace(node, **changes)
+
+    return [redact(node) for node in structures]
@@ -0,0 +1,83 @@
+"""
+FILE: references.py
+
+PROBLEM:
+  An agent showing scan results to a person wants each symbol clickable:
+  "src/users.go:42:6" in a terminal or chat that links it, or a
+  vscode://file/... URI that opens the editor at the spot. Every client
+  rebuilds that string from file, line and column — and the URI form
+  only works with an absolute path, which a relative-path scan doesn't
+  carry.
+
+SOLUTION:
+  An optional "ref" string on every JSON node and parse error, in one of
+  REFERENCE_FORMATS:
+    none    no ref (default)
+    plain   path:line:col with the path as the output shows it
+            (path_style: absolute, or relative to the scan root)
+    vscode  vscode://file/<absolute path>:line:col — always the resolved
+            absolute path, "/" separators, percent-encoded (Windows paths
+            get the leading "/" the URI needs: vscode://file/C:/src/...)
+  Columns are 1-based: the UTF-16 column of the declaration where the
+  language records it (what VS Code counts), the byte column of a parse
+  error, else 1.
+
+SCOPE:
+  ✓ scan_file and scan_directory JSON (nodes at any depth, parse_errors),
+    list_all_symbols JSON
+  ✗ Tree output keeps its compact "@line" form
+"""
+
+from pathlib import Path
+from typing import Optional
+from urllib.parse import quote
+
+REFERENCE_FORMATS = ("none", "plain", "vscode")
+
+
+def check_reference_format(reference_format: str) -> None:
+    if reference_format not in REFERENCE_FORMATS:
+        raise ValueError(f"Unknown reference_format {reference_format!r} — "
+                         f"use one of: {', '.join(REFERENCE_FORMATS)}")
+
+
+def reference(path: str, line: int, column: Optional[int], reference_format: str,
+              absolute_path: Optional[str] = None) -> Optional[str]:
+    """The ref string of a position (column 1-based, None = 1); path is
+    the path as displayed, absolute_path the file on disk for the vscode
+    form (default: path resolved)."""
+    column = column or 1
+    if reference_format == "plain":
+        return f"{path}:{line}:{column}"
+    if reference_format == "vscode":
+        target = Path(absolute_path or path).resolve().as_posix()
+        if not target.startswith("/"):
+            target = "/" + target
+        return f"vscode://file{quote(target, safe='/:')}:{line}:{column}"
+    return None
+
+
+def add_references(data: dict, reference_format: str,
+                   absolute_path: Optional[str] = None) -> dict:
+    """A JSON file result (file_json.file_to_dict) with "ref" on every
+    node and parse error; data["file"] is the displayed path. Changes
+    and returns data."""
+    if reference_format == "none":
+        return data
+    path = data["file"]
+
+    def node(item: dict) -> None:
+        column = item.get("start_utf16_column")
+        item["ref"] = reference(path, item["start_line"],
+                                column + 1 if column is not None else None,
+                                reference_format, absolute_path)
+        for child in item.get("children", ()):
+            node(child)
+
+    for structure in data.get("structures", ()):
+        if structure.get("type") != "file-info":
+            node(structure)
+    for error in data.get("parse_errors", ()):
+        error["ref"] = reference(path, error["line"], error.get("column"),
+                                 reference_format, absolute_path)
+    return data
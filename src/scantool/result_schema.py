@@ -0,0 +1,339 @@
+"""
+FILE: result_schema.py
+
+PROBLEM:
+  Typed clients of the JSON output (output_format="json") have to
+  reverse-engineer its shape from samples, and silently break when a field
+  is added or renamed.
+
+SOLUTION:
+  A hand-maintained JSON Schema (draft 2020-12) for the serialized scan
+  result — file_json.file_to_dict per file, a path-keyed object of those
+  per directory. Hand-maintained because the JSON is a curated view
+  of StructureNode (empty fields omitted, internal fields never emitted),
+  not a dump of it. tests/test_result_schema.py validates real scans
+  against it with additionalProperties closed, so an unlisted field fails
+  the suite instead of drifting.
+
+SCOPE:
+  ✓ scan_file / scan_file_content / scan_directory / search_structures JSON
+  ✗ Tree output — that contract is frozen by the golden tests
+"""
+
+import copy
+
+_SCHEMA_ID = "https://github.com/mariusei/file-scanner-mcp/schemas/scan-result.json"
+
+_STRING_LIST = {"type": "array", "items": {"type": "string"}}
+_REF = {"type": "string", "description": "With reference_format: \"path:line:col\" or "
+                                        "\"vscode://file/<absolute path>:line:col\"."}
+
+RESULT_SCHEMA: dict = {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$id": _SCHEMA_ID,
+    "title": "Scan result",
+    "description": "JSON output of scantool: one file result (scan_file, "
+                   "scan_file_content) or a path-keyed map of file results "
+                   "(scan_directory, search_structures).",
+    "anyOf": [
+        {"$ref": "#/$defs/fileResult"},
+        {"$ref": "#/$defs/directoryResult"},
+        {"$ref": "#/$defs/directoryPage"},
+        {"$ref": "#/$defs/packageGrouped"},
+    ],
+    "$defs": {
+        "directoryResult": {
+            "type": "object",
+            "description": "File path -> file result.",
+            "additionalProperties": {"$ref": "#/$defs/fileResult"},
+        },
+        "directoryPage": {
+            "type": "object",
+            "description": "One page of a scan_directory result (limit/cursor).",
+            "required": ["files", "offset", "total_files", "next_cursor"],
+            "additionalProperties": False,
+            "properties": {
+                "files": {"$ref": "#/$defs/directoryResult"},
+                "offset": {"type": "integer", "minimum": 0,
+                           "description": "Position of the page's first file in the "
+                                          "sorted file set."},
+                "total_files": {"type": "integer", "minimum": 0},
+                "next_cursor": {"type": ["string", "null"],
+                                "description": "Pass as cursor for the next page; null "
+                                               "on the last."},
+            },
+        },
+        "packageGrouped": {
+            "type": "object",
+            "description": "scan_directory result with group_by_package; paged, with "
+                           "the offset, total_files and next_cursor of a directoryPage.",
+            "required": ["packages"],
+            "additionalProperties": False,
+            "properties": {
+                "packages": {"type": "array", "items": {"$ref": "#/$defs/packageGroup"}},
+                "offset": {"type": "integer", "minimum": 0},
+                "total_files": {"type": "integer", "minimum": 0},
+                "next_cursor": {"type": ["string", "null"]},
+            },
+        },
+        "packageGroup": {
+            "type": "object",
+            "description": "The files of one directory that declare one package.",
+            "required": ["dir", "package", "files"],
+            "additionalProperties": False,
+            "properties": {
+                "dir": {"type": "string",
+                        "description": "Directory relative to the scan root, \"/\" "
+                                       "separators, \".\" for the root."},
+                "package": {"type": ["string", "null"],
+                            "description": "Declared package (Go: users, users_test); "
+                                           "null for files declaring none."},
+                "files": {"$ref": "#/$defs/directoryResult"},
+            },
+        },
+        "fileResult": {
+            "type": "object",
+            "required": ["file", "structures"],
+            "additionalProperties": False,
+            "properties": {
+                "file": {"type": "string", "description": "Path (or filename) as scanned."},
+                "language": {"type": "string", "description": "Language name, e.g. \"Go\"."},
+                "package": {"type": "string",
+                            "description": "Package the file declares (Go package clause, "
+                                           "users_test for an external test package); "
+                                           "absent for languages without one."},
+                "doc": {"type": "string", "description": "File-level doc (Go package doc)."},
+                "lines": {"$ref": "#/$defs/lineCounts"},
+                "content_hash": {"type": "string", "pattern": "^[0-9a-f]{64}$",
+                                 "description": "SHA-256 of the file's bytes as on disk "
+                                                "(BOM and line endings included); absent "
+                                                "for files that were not read."},
+                "meta": {"type": "object",
+                         "description": "Fields added by FileScanner post-processors "
+                                        "(library use); absent when none set any."},
+                "generated": {"type": "boolean",
+                              "description": "Machine-generated file (Go \"Code generated "
+                                             "... DO NOT EDIT.\" header); absent otherwise."},
+                "build_constraint": {"type": "string",
+                                     "description": "Go //go:build expression (legacy "
+                                                    "// +build lines rewritten); absent "
+                                                    "when the file is always built."},
+                "imports": {"type": "array", "items": {"$ref": "#/$defs/import"},
+                            "description": "Go import specs in declaration order; absent "
+                                           "without imports."},
+                "encoding_warning": {"type": "string",
+                                     "description": "The file is not valid UTF-8: how many "
+                                                    "invalid sequences and where the first "
+                                                    "is. Names and text cut from them show "
+                                                    "U+FFFD; absent for valid files."},
+                "skipped": {"type": "string",
+                            "description": "Why the file has no structure: too_large, "
+                                           "binary, invalid_encoding (with "
+                                           "invalid_encoding=\"skip\"), parse_error, "
+                                           "panic (the scanner crashed), timed_out, "
+                                           "post_process (a "
+                                           "FileScanner post-processor raised), "
+                                           "permission_denied, unreadable, max_depth "
+                                           "(a directory)"},
+                "parse_errors": {"type": "array", "items": {"$ref": "#/$defs/parseError"},
+                                 "description": "Syntax errors by position, or the scan "
+                                                "failure; absent when there are none."},
+                "structures": {
+                    "type": "array",
+                    "description": "Top-level nodes in source order; the first is "
+                                   "type \"file-info\" when metadata was requested.",
+                    "items": {"$ref": "#/$defs/node"},
+                },
+            },
+        },
+        "node": {
+            "type": "object",
+            "required": ["type", "name"],
+            "additionalProperties": False,
+            "properties": {
+                "type": {"type": "string",
+                         "description": "function, method, class, struct, heading, ..."},
+                "name": {"type": "string"},
+                "start_line": {"type": "integer", "minimum": 1,
+                               "description": "Present unless verbosity=\"names\"."},
+                "end_line": {"type": "integer", "minimum": 1},
+                "line_count": {"type": "integer", "minimum": 1,
+                               "description": "end_line - start_line + 1."},
+                "start_offset": {"type": "integer", "minimum": 0,
+                                 "description": "Byte offset of the node's first byte in the "
+                                                "file (languages that report offsets)."},
+                "end_offset": {"type": "integer", "minimum": 0,
+                               "description": "Byte offset just past the node's last byte."},
+                "start_utf16_column": {"type": "integer", "minimum": 0,
+                                       "description": "0-based column of start_offset on "
+                                                      "start_line in UTF-16 code units (LSP "
+                                                      "character)."},
+                "end_utf16_column": {"type": "integer", "minimum": 0,
+                                     "description": "0-based column of end_offset on end_line "
+                                                    "in UTF-16 code units."},
+                "id": {"type": "string",
+                       "description": "Stable symbol ID: kind:namespace.qualified_name, "
+                                      "no positions."},
+                "body_hash": {"type": "string", "pattern": "^[0-9a-f]{40}$",
+                              "description": "SHA-1 of the declaration's source lines with "
+                                             "trailing whitespace and blank lines dropped. "
+                                             "Line-based, not AST-normalized: comment and "
+                                             "indentation edits inside it change the hash."},
+                "signature": {"type": "string",
+                              "description": "Parameters and result, without the name."},
+                "full_signature": {"type": "string",
+                                   "description": "Declaration header with receiver and name."},
+                "decorators": _STRING_LIST,
+                "docstring": {"type": "string", "description": "First line of the doc."},
+                "doc": {"type": "string", "description": "Full doc comment."},
+                "deprecated": {"type": "boolean",
+                               "description": "Go: the doc has a \"Deprecated:\" paragraph; "
+                                              "absent otherwise."},
+                "annotations": {"type": "object", "additionalProperties": {"type": "string"},
+                                "description": "\"@key: value\" lines of the doc comment."},
+                "modifiers": _STRING_LIST,
+                "fields": {"type": "array", "items": {"$ref": "#/$defs/field"},
+                           "description": "Struct fields in declaration order."},
+                "visibility": {"type": "string", "enum": ["exported", "unexported"],
+                               "description": "Go declarations: by identifier case."},
+                "receiver_kind": {"type": "string", "enum": ["value", "pointer"],
+                                  "description": "Go method: func (t T) vs func (t *T)."},
+                "func_kind": {"type": "string", "enum": ["function", "method", "func_literal"],
+                              "description": "Function, method and constructor nodes: "
+                                             "free function, method (constructors "
+                                             "too) or a function literal bound to a "
+                                             "name (TypeScript/JavaScript arrow "
+                                             "function)."},
+                "receiver_type": {"type": "string",
+                                  "description": "Go method: receiver base type, without "
+                                                 "\"*\" or type parameters."},
+                "methods": {"type": "array", "items": {"type": "string"},
+                            "description": "Go type: its methods declared in the same "
+                                           "file, in source order."},
+                "uses_packages": {"type": "array", "items": {"type": "string"},
+                                  "description": "Go function and method: import "
+                                                 "qualifiers (local package names) its "
+                                                 "body refers to, sorted."},
+                "complexity": {"$ref": "#/$defs/complexity"},
+                "positions": {"$ref": "#/$defs/positions"},
+                "ref": _REF,
+                "children": {"type": "array", "items": {"$ref": "#/$defs/node"}},
+            },
+        },
+        "field": {
+            "type": "object",
+            "required": ["name", "type"],
+            "additionalProperties": False,
+            "properties": {
+                "name": {"type": "string", "description": "\"\" for an embedded field."},
+                "type": {"type": "string", "description": "Type expression as written."},
+                "tag": {"type": "string", "description": "Raw tag literal incl. backticks."},
+            },
+        },
+        "parseError": {
+            "type": "object",
+            "required": ["line", "message"],
+            "additionalProperties": False,
+            "properties": {
+                "line": {"type": "integer", "minimum": 1},
+                "column": {"type": "integer", "minimum": 1,
+                           "description": "1-based byte column; absent when the whole "
+                                          "file failed to scan."},
+                "end_line": {"type": "integer", "minimum": 1,
+                             "description": "Last line of a multi-line error region."},
+                "message": {"type": "string"},
+                "reason": {"type": "string", "enum": ["panic", "timed_out", "post_process"],
+                           "description": "Why the whole file failed to scan: the "
+                                          "scanner crashed, parsing ran past the "
+                                          "per-file deadline, or a post-processor "
+                                          "raised."},
+                "ref": _REF,
+            },
+        },
+        "import": {
+            "type": "object",
+            "required": ["path", "name", "line", "blank", "dot", "used"],
+            "additionalProperties": False,
+            "properties": {
+                "path": {"type": "string"},
+                "name": {"type": ["string", "null"],
+                         "description": "Alias as written, \".\" and \"_\" included."},
+                "line": {"type": "integer", "minimum": 1},
+                "blank": {"type": "boolean"},
+                "dot": {"type": "boolean"},
+                "used": {"type": ["boolean", "null"],
+                         "description": "Package qualifier found in the file (best "
+                                        "effort); null for blank and dot imports."},
+            },
+        },
+        "lineCounts": {
+            "type": "object",
+            "description": "Per-file line breakdown. A line with any code outside "
+                           "comments is code; code/comment only for languages "
+                           "whose parser reports comments.",
+            "required": ["total", "blank"],
+            "additionalProperties": False,
+            "properties": {
+                "total": {"type": "integer", "minimum": 0},
+                "code": {"type": "integer", "minimum": 0},
+                "comment": {"type": "integer", "minimum": 0},
+                "blank": {"type": "integer", "minimum": 0},
+            },
+        },
+        "positions": {
+            "type": "object",
+            "description": "With positions=True: go/token-style ranges of the declaration, "
+                           "its identifier and its body.",
+            "required": ["range"],
+            "additionalProperties": False,
+            "properties": {
+                "range": {"$ref": "#/$defs/range"},
+                "name_range": {"$ref": "#/$defs/range",
+                               "description": "The declared identifier (languages that "
+                                              "record it)."},
+                "body_range": {"$ref": "#/$defs/range",
+                               "description": "Function body block, or a type's type "
+                                              "expression."},
+            },
+        },
+        "range": {
+            "type": "object",
+            "required": ["start", "end"],
+            "additionalProperties": False,
+            "properties": {
+                "start": {"$ref": "#/$defs/position"},
+                "end": {"$ref": "#/$defs/position",
+                        "description": "Just past the last byte."},
+            },
+        },
+        "position": {
+            "type": "object",
+            "required": ["filename", "offset", "line", "column"],
+            "additionalProperties": False,
+            "properties": {
+                "filename": {"type": "string", "description": "The file as the result names it."},
+                "offset": {"type": "integer", "minimum": 0,
+                           "description": "0-based byte offset in the file."},
+                "line": {"type": "integer", "minimum": 1},
+                "column": {"type": "integer", "minimum": 1,
+                           "description": "1-based byte column."},
+            },
+        },
+        "complexity": {
+            "type": "object",
+            "properties": {
+                "lines": {"type": "integer"},
+                "max_depth": {"type": "integer"},
+                "branches": {"type": "integer"},
+                "cyclomatic": {"type": "integer",
+                               "description": "McCabe complexity (languages that compute it)."},
+            },
+            "additionalProperties": {"type": "integer"},
+        },
+    },
+}
+
+
+def result_schema() -> dict:
+    """A copy of the scan result JSON Schema, safe to mutate."""
+    return copy.deepcopy(RESULT_SCHEMA)
@@ -0,0 +1,166 @@
+"""
+FILE: review_brief.py
+
+PROBLEM:
+  A review guided by the scan needs four tool calls — hotspots, complex
+  functions, undocumented API, TODO markers — and then someone to turn
+  their output into instructions. MCP clients offer prompts as a menu
+  ("prompts/list"); without one, every user writes that brief by hand
+  and the findings never reach it.
+
+SOLUTION:
+  build_review_brief() gathers the findings of one directory scan:
+    hotspots             the top files of hotspots.find_hotspots
+    complex functions    functions and methods with cyclomatic complexity
+                         of at least min_complexity, most complex first
+    undocumented         exported Go declarations without a doc comment
+    markers              TODO/FIXME/HACK comments of the Go files
+  format_review_brief() writes them into one message: what to review,
+  each list with file:line locations relative to the directory (cut at
+  top, with a "+N more" line), and what the review should deliver. The
+  server registers it as the review_brief prompt.
+
+SCOPE:
+  ✓ Hotspots and complexity for every language the scanner outlines
+  ✓ Empty sections are kept, saying nothing was found — the reviewer
+    knows the check ran
+  ✗ Undocumented symbols and markers are Go only
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from .golang.comments import CommentMarker, scan_comments
+from .golang.syntax import GoFile
+from .golang.undocumented import UndocumentedSymbol, find_undocumented
+from .hotspots import DEFAULT_TOP, Hotspot, find_hotspots
+from .languages import StructureNode, is_unsupported_stub
+from .symbol_filter import cyclomatic_complexity
+
+DEFAULT_MIN_COMPLEXITY = 10
+
+
+@dataclass
+class ComplexFunction:
+    file: str
+    line: int
+    name: str  # Type.Method for methods
+    complexity: int
+
+
+@dataclass
+class ReviewBrief:
+    directory: str
+    files: int  # code files scanned
+    min_complexity: int
+    hotspots: list[Hotspot] = field(default_factory=list)
+    complex_functions: list[ComplexFunction] = field(default_factory=list)
+    undocumented: list[UndocumentedSymbol] = field(default_factory=list)
+    markers: list[CommentMarker] = field(default_factory=list)
+    go_files: int = 0
+
+
+def complex_functions(results: dict[str, Optional[list[StructureNode]]],
+                      min_complexity: int = DEFAULT_MIN_COMPLEXITY) -> list[ComplexFunction]:
+    """Functions and methods of a directory scan with cyclomatic complexity
+    of at least min_complexity, most complex first (then file and line)."""
+    found = []
+
+    def walk(file_path: str, nodes: list[StructureNode], owner: Optional[str]):
+        for node in nodes:
+            if node.type in ("function", "method"):
+                score = cyclomatic_complexity(node)
+                if score is not None and score >= min_complexity:
+                    receiver = node.receiver_type or owner
+                    name = f"{receiver}.{node.name}" if receiver else node.name
+                    found.append(ComplexFunction(file_path, node.start_line, name, score))
+            walk(file_path, node.children,
+                 node.name if node.type not in ("function", "method") else owner)
+
+    for file_path, structures in results.items():
+        if structures and not is_unsupported_stub(structures):
+            walk(file_path, structures, None)
+    found.sort(key=lambda f: (-f.complexity, f.file, f.line))
+    return found
+
+
+def build_review_brief(directory: str, results: dict[str, Optional[list[StructureNode]]],
+                       go_files: list[GoFile],
+                       min_complexity: int = DEFAULT_MIN_COMPLEXITY,
+                       top: int = DEFAULT_TOP) -> ReviewBrief:
+    """The findings of a scan_directory result (and the directory's parsed
+    Go files) a review brief is written from."""
+    if min_complexity < 1:
+        raise ValueError(f"min_complexity must be at least 1, got {min_complexity}")
+    code_files = [p for p, s in results.items() if s and not is_unsupported_stub(s)]
+    return ReviewBrief(
+        directory=directory,
+        files=len(code_files),
+        min_complexity=min_complexity,
+        hotspots=find_hotspots(results, top=top),
+        complex_functions=complex_functions(results, min_complexity),
+        undocumented=find_undocumented(go_files),
+        markers=scan_comments(go_files),
+        go_files=len(go_files),
+    )
+
+
+def _section(title: str, entries: list[str], top: int, empty: str) -> list[str]:
+    lines = [f"## {title}"]
+    lines.extend(entries[:top] or [empty])
+    if len(entries) > top:
+        lines.append(f"- +{len(entries) - top} more")
+    return lines + [""]
+
+
+def format_review_brief(brief: ReviewBrief, top: int = DEFAULT_TOP) -> str:
+    """The review_brief prompt message: instructions, the findings with
+    locations, and the expected deliverable."""
+    root = Path(brief.directory).resolve()
+
+    def shown(path: str) -> str:
+        try:
+            return Path(path).resolve().relative_to(root).as_posix()
+        except ValueError:
+            return path
+
+    hotspots = []
+    for rank, spot in enumerate(brief.hotspots, 1):
+        worst = f" ({spot.max_function})" if spot.max_function else ""
+        hotspots.append(f"{rank}. {shown(spot.file)} — score {spot.score:g}: complexity max "
+                        f"{spot.max_complexity}{worst}, {spot.todos} TODOs, {spot.lines} lines")
+    functions = [f"- {shown(f.file)}:{f.line} {f.name} — complexity {f.complexity}"
+                 for f in brief.complex_functions]
+    undocumented = [f"- {shown(s.file)}:{s.line} {s.kind} {s.display_name}"
+                    for s in brief.undocumented]
+    markers = [f"- {shown(m.file)}:{m.line} {m.tag}"
+               + (f"({m.author})" if m.author else "") + f": {m.text}"
+               for m in brief.markers]
+    go_only = "- None found." if brief.go_files else "- No Go files in this directory."
+
+    lines = [
+        f"Review the code in {brief.directory} ({brief.files} code files scanned).",
+        "",
+        "The findings below come from a static scan. Use them to decide where to read "
+        "first, read the code itself before judging it (scan_file with focus= or "
+        "get_symbol_source), and say so when a finding turns out to be harmless.",
+        "",
+    ]
+    lines += _section("Hotspots (complexity, TODO markers and size, relative to this codebase)",
+                      hotspots, top, "- None found.")
+    lines += _section(f"Functions with cyclomatic complexity of {brief.min_complexity} or more "
+                      f"({len(functions)})", functions, top, "- None found.")
+    lines += _section(f"Exported Go symbols without a doc comment ({len(undocumented)})",
+                      undocumented, top, go_only)
+    lines += _section(f"TODO / FIXME / HACK markers ({len(markers)})", markers, top, go_only)
+    lines += [
+        "## What to deliver",
+        "1. The three to five most important problems, each with its file:line and why it "
+        "matters (bugs and risky logic before style).",
+        "2. For each complex function you read: whether the complexity is essential, and a "
+        "concrete way to split or simplify it if not.",
+        "3. Missing documentation worth writing first — the exported API others call.",
+        "4. TODO markers that point at real unfinished work, as follow-up tasks.",
+    ]
+    return "\n".join(lines)
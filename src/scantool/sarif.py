@@ -0,0 +1,88 @@
+"""SARIF 2.1.0 export of findings (GitHub code scanning, CI annotations).
+
+Each rule used by a finding is listed once in tool.driver.rules; results
+reference it by ruleId AND ruleIndex (position in that list). Regions are
+1-based lines; findings are line-granular, so startColumn is always 1.
+"""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+from .findings import RULES, Finding
+
+SARIF_VERSION = "2.1.0"
+SARIF_SCHEMA = "https://json.schemastore.org/sarif-2.1.0.json"
+_INFORMATION_URI = "https://github.com/mariusei/file-scanner-mcp"
+
+
+def _artifact_uri(file_path: str, base_dir: Optional[Path]) -> tuple[str, bool]:
+    """(uri, is_relative): paths under base_dir become relative URIs."""
+    path = Path(file_path)
+    if base_dir is not None:
+        try:
+            return path.resolve().relative_to(base_dir).as_posix(), True
+        except ValueError:
+            pass
+    return path.resolve().as_uri() if path.is_absolute() else path.as_posix(), False
+
+
+def export_sarif(findings: list[Finding], base_dir: Optional[str] = None) -> dict:
+    """SARIF log for findings. With base_dir, file URIs are relative to it
+    under uriBaseId %SRCROOT% (what code scanning expects for repo files)."""
+    from . import __version__  # deferred: the package imports the server on init
+
+    base = Path(base_dir).resolve() if base_dir else None
+    used_rules = sorted({f.rule_id for f in findings})
+    rule_index = {rule_id: i for i, rule_id in enumerate(used_rules)}
+
+    results = []
+    for finding in findings:
+        uri, relative = _artifact_uri(finding.file, base)
+        artifact = {"uri": uri}
+        if relative:
+            artifact["uriBaseId"] = "%SRCROOT%"
+        result = {
+            "ruleId": finding.rule_id,
+            "ruleIndex": rule_index[finding.rule_id],
+            "level": RULES[finding.rule_id].level,
+            "message": {"text": finding.message},
+            "locations": [{
+                "physicalLocation": {
+                    "artifactLocation": artifact,
+                    "region": {
+                        "startLine": max(1, finding.start_line),
+                        "startColumn": 1,
+                        "endLine": max(finding.start_line, finding.end_line, 1),
+                    },
+                },
+            }],
+        }
+        if finding.symbol:
+            result["locations"][0]["logicalLocations"] = [{"name": finding.symbol}]
+        results.append(result)
+
+    run = {
+        "tool": {
+            "driver": {
+                "name": "scantool",
+                "version": __version__,
+                "informationUri": _INFORMATION_URI,
+                "rules": [{
+                    "id": rule_id,
+                    "name": RULES[rule_id].name,
+                    "shortDescription": {"text": RULES[rule_id].description},
+                    "defaultConfiguration": {"level": RULES[rule_id].level},
+                } for rule_id in used_rules],
+            },
+        },
+        "results": results,
+    }
+    if base is not None:
+        run["originalUriBaseIds"] = {"%SRCROOT%": {"uri": base.as_uri() + "/"}}
+
+    return {"$schema": SARIF_SCHEMA, "version": SARIF_VERSION, "runs": [run]}
+
+
+def format_sarif(findings: list[Finding], base_dir: Optional[str] = None) -> str:
+    return json.dumps(export_sarif(findings, base_dir), indent=2)
@@ -0,0 +1,99 @@
+"""
+FILE: scan_cache.py
+
+PROBLEM:
+  An agent re-scans the same directory turn after turn, and every call
+  re-parses every file although almost none of them changed in between.
+
+SOLUTION:
+  A per-file result cache consulted by FileScanner.scan_directory. The key
+  is the file's identity as the filesystem reports it: absolute path +
+  mtime_ns + size (plus the scan mode, which changes saliency output). Only
+  files whose key changed are re-parsed. Some editors and tools preserve
+  mtime on write, so an optional content-hash mode adds a SHA-256 of the
+  bytes to the key — one read instead of a parse.
+
+  The store is pluggable: anything with get(key) / put(key, structures)
+  works (ScanCache). LRUScanCache is the bounded in-memory default.
+
+SCOPE:
+  ✓ Transparent — a hit returns a copy of exactly what a fresh scan returned
+  ✓ Callers may mutate results (churn, delta, filters) without poisoning it
+  ✗ No on-disk default — results hold tree-sitter-free plain data, so a
+    pickle/JSON-backed ScanCache is easy to plug in, but none ships here
+"""
+
+import copy
+import hashlib
+import os
+import threading
+from collections import OrderedDict
+from typing import Optional, Protocol, runtime_checkable
+
+from .languages import StructureNode
+
+_DEFAULT_MAX_ENTRIES = 4096
+
+
+@runtime_checkable
+class ScanCache(Protocol):
+    """Storage for per-file scan results, keyed by scan_cache_key()."""
+
+    def get(self, key: tuple) -> Optional[list[StructureNode]]:
+        ...
+
+    def put(self, key: tuple, structures: list[StructureNode]) -> None:
+        ...
+
+
+class LRUScanCache:
+    """In-memory ScanCache bounded to max_entries files (least recently used
+    evicted). Stores and returns deep copies, so cached results are never
+    shared with a caller that mutates them."""
+
+    def __init__(self, max_entries: int = _DEFAULT_MAX_ENTRIES):
+        self.max_entries = max_entries
+        self._entries: "OrderedDict[tuple, list[StructureNode]]" = OrderedDict()
+        self._lock = threading.Lock()
+
+    def get(self, key: tuple) -> Optional[list[StructureNode]]:
+        with self._lock:
+            structures = self._entries.get(key)
+            if structures is None:
+                return None
+            self._entries.move_to_end(key)
+        return copy.deepcopy(structures)
+
+    def put(self, key: tuple, structures: list[StructureNode]) -> None:
+        snapshot = copy.deepcopy(structures)
+        with self._lock:
+            self._entries[key] = snapshot
+            self._entries.move_to_end(key)
+            while len(self._entries) > self.max_entries:
+                self._entries.popitem(last=False)
+
+    def clear(self) -> None:
+        with self._lock:
+            self._entries.clear()
+
+    def __len__(self) -> int:
+        return len(self._entries)
+
+
+def scan_cache_key(path: str, mode: str = "balanced",
+                   content_hash: bool = False) -> Optional[tuple]:
+    """(abs_path, mtime_ns, size, mode[, sha256]) for a file, or None if it
+    can't be stat'ed/read (the file is then scanned uncached)."""
+    abs_path = os.path.abspath(path)
+    try:
+        stat = os.stat(abs_path)
+    except OSError:
+        return None
+    key = (abs_path, stat.st_mtime_ns, stat.st_size, mode)
+    if content_hash:
+        try:
+            with open(abs_path, "rb") as f:
+                key += (hashlib.sha256(f.read()).hexdigest(),)
+        except OSError:
+            return None
+    return key
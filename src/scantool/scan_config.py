@@ -0,0 +1,147 @@
+"""
+FILE: scan_config.py
+
+PROBLEM:
+  Teams scan with the same conventions every time — skip vendor/ and
+  testdata/, bigger size cap, 2 workers in CI, names-only JSON — and have
+  to repeat them in every tool call.
+
+SOLUTION:
+  A project file with default options, three-level precedence:
+    tool-call argument  >  config file  >  built-in default
+  Looked up from the scan root upward, the first of CONFIG_FILES found in
+  a directory wins (.scannerrc and scanner.toml are TOML, read with the
+  standard library; scanner.yaml needs PyYAML). Flat keys:
+      workers = 4                      # parallel file scans
+      skip_dirs = ["vendor", "testdata"]
+      max_file_size = 10_000_000       # bytes, 0 = no limit
+      respect_gitignore = true
+      verbosity = "signatures"         # names, signatures, full
+      redact_strings = "hash"          # off, length, hash (string literals)
+  A file that doesn't parse, an unknown key or a value of the wrong type
+  raises ConfigError naming the file and the key — never a silent
+  fallback to the built-ins.
+
+SCOPE:
+  ✓ Explicit load_config(path), or find_config(start) from a scan root
+  ✗ No merging of several config files: the nearest one is used whole
+"""
+
+import tomllib
+from dataclasses import dataclass, fields
+from pathlib import Path
+from typing import Optional
+
+from .redaction import REDACT_MODES
+from .verbosity import VERBOSITY_LEVELS
+
+CONFIG_FILES = (".scannerrc", "scanner.toml", "scanner.yaml")
+
+
+class ConfigError(ValueError):
+    """A config file that can't be used as written."""
+
+
+@dataclass(frozen=True)
+class ScanConfig:
+    path: Optional[str] = None  # file it was read from, None = no file
+    workers: Optional[int] = None
+    skip_dirs: Optional[tuple[str, ...]] = None
+    max_file_size: Optional[int] = None
+    respect_gitignore: Optional[bool] = None
+    verbosity: Optional[str] = None
+    redact_strings: Optional[str] = None
+
+    def apply(self, options: dict, defaults: Optional[dict] = None) -> dict:
+        """options with the precedence applied: a value the caller passed
+        (not None) is kept, else this config's value, else the built-in
+        default from defaults (None without one)."""
+        merged = dict(options)
+        for name, value in options.items():
+            if value is not None:
+                continue
+            configured = getattr(self, name, None) if name in OPTION_NAMES else None
+            if configured is not None:
+                merged[name] = list(configured) if name == "skip_dirs" else configured
+            else:
+                merged[name] = (defaults or {}).get(name)
+        return merged
+
+
+OPTION_NAMES = tuple(f.name for f in fields(ScanConfig) if f.name != "path")
+
+
+def _check(path: str, key: str, value) -> object:
+    """value converted for key, or ConfigError saying what was expected."""
+    def fail(expected: str):
+        raise ConfigError(f"{path}: {key} must be {expected}, got {value!r}")
+
+    if key == "workers":
+        if isinstance(value, bool) or not isinstance(value, int) or value < 1:
+            fail("an integer of at least 1")
+    elif key == "skip_dirs":
+        if not isinstance(value, list) or not all(isinstance(d, str) for d in value):
+            fail("a list of directory names")
+        return tuple(value)
+    elif key == "max_file_size":
+        if isinstance(value, bool) or not isinstance(value, int) or value < 0:
+            fail("a byte count (0 = no limit)")
+    elif key == "respect_gitignore":
+        if not isinstance(value, bool):
+            fail("true or false")
+    elif key == "verbosity":
+        if value not in VERBOSITY_LEVELS:
+            fail(f"one of {', '.join(VERBOSITY_LEVELS)}")
+    elif key == "redact_strings":
+        if value not in REDACT_MODES:
+            fail(f"one of {', '.join(REDACT_MODES)}")
+    return value
+
+
+def _read(path: Path) -> dict:
+    text = path.read_text(encoding="utf-8")
+    if path.suffix in (".yaml", ".yml"):
+        try:
+            import yaml
+        except ImportError:
+            raise ConfigError(f"{path}: reading YAML needs PyYAML (pip install pyyaml) "
+                              f"— or write the same keys as TOML to .scannerrc") from None
+        try:
+            data = yaml.safe_load(text)
+        except yaml.YAMLError as e:
+            raise ConfigError(f"{path}: invalid YAML: {e}") from e
+        return {} if data is None else data
+    try:
+        return tomllib.loads(text)
+    except tomllib.TOMLDecodeError as e:
+        raise ConfigError(f"{path}: invalid TOML: {e}") from e
+
+
+def load_config(path: str) -> ScanConfig:
+    """The config in one file. FileNotFoundError if it doesn't exist,
+    ConfigError if it isn't a valid config."""
+    config_path = Path(path)
+    if not config_path.is_file():
+        raise FileNotFoundError(f"Config file not found: {path}")
+    data = _read(config_path)
+    if not isinstance(data, dict):
+        raise ConfigError(f"{path}: expected key = value options at the top level")
+    unknown = sorted(set(data) - set(OPTION_NAMES))
+    if unknown:
+        raise ConfigError(f"{path}: unknown option(s) {', '.join(unknown)} — "
+                          f"known: {', '.join(OPTION_NAMES)}")
+    return ScanConfig(path=str(config_path.resolve()),
+                      **{key: _check(path, key, value) for key, value in data.items()})
+
+
+def find_config(start: str) -> ScanConfig:
+    """The nearest config file at or above start (a directory, or a file's
+    directory); an empty ScanConfig when there is none."""
+    directory = Path(start).resolve()
+    if not directory.is_dir():
+        directory = directory.parent
+    for candidate in (directory, *directory.parents):
+        for name in CONFIG_FILES:
+            if (candidate / name).is_file():
+                return load_config(str(candidate / name))
+    return ScanConfig()
@@ -0,0 +1,98 @@
+"""
+FILE: scan_resources.py
+
+PROBLEM:
+  "Here is the structure of this file" is data a client reads (and may
+  cache), not an action it calls — but every scan result is only reachable
+  through a tool call, so clients can't browse or reference them.
+
+SOLUTION:
+  Scan results addressed as MCP resources: scan://<relative-path> names one
+  file under a resource root, and its contents are the JSON file result.
+  The file listing is its own paged resource (scan-index://files, then
+  scan-index://files/<cursor>) so a tree with thousands of files is never
+  returned — or walked into the protocol's resources/list — in one piece.
+
+  Cursors are the last relative path of the previous page, encoded: paging
+  resumes after that path even when files are added or removed in between,
+  where an offset would skip or repeat entries.
+
+SCOPE:
+  ✓ Files the scanners support, walked with scan_directory's rules
+  ✓ Paths that escape the root (.., absolute, symlinks out) are rejected
+  ✗ No resource update notifications — watch.watch yields the events, but
+    nothing pushes them to subscribed clients yet
+"""
+
+import base64
+import binascii
+from pathlib import Path
+from typing import Optional
+from urllib.parse import quote, unquote
+
+SCHEME = "scan"
+INDEX_URI = "scan-index://files"
+DEFAULT_PAGE_SIZE = 500
+
+
+def resource_uri(relative_path: str) -> str:
+    """scan://<relative path>, percent-encoded, "/"-separated."""
+    return f"{SCHEME}://{quote(relative_path, safe='/')}"
+
+
+def resolve_resource_path(uri_path: str, root: Path) -> Path:
+    """File behind the path part of a scan:// URI. Raises ValueError when
+    it leaves the root and FileNotFoundError when it doesn't exist."""
+    relative = unquote(uri_path).lstrip("/")
+    root = root.resolve()
+    target = (root / relative).resolve()
+    if not relative or not target.is_relative_to(root):
+        raise ValueError(f"Not a file under the resource root: {uri_path}")
+    if not target.is_file():
+        raise FileNotFoundError(f"File not found: {uri_path}")
+    return target
+
+
+def encode_cursor(relative_path: str) -> str:
+    return base64.urlsafe_b64encode(relative_path.encode("utf-8")).decode("ascii").rstrip("=")
+
+
+def decode_cursor(cursor: str) -> str:
+    try:
+        padded = cursor + "=" * (-len(cursor) % 4)
+        return base64.urlsafe_b64decode(padded.encode("ascii")).decode("utf-8")
+    except (binascii.Error, UnicodeError, ValueError):
+        raise ValueError(f"Invalid cursor: {cursor}") from None
+
+
+def list_page(scanner, root: Path, cursor: Optional[str] = None,
+              page_size: int = DEFAULT_PAGE_SIZE, respect_gitignore: bool = True) -> dict:
+    """One page of the resource listing: supported files under root sorted
+    by relative path, starting after the cursor's path. nextCursor is None
+    on the last page; the next page is INDEX_URI + "/" + nextCursor."""
+    root = root.resolve()
+    after = decode_cursor(cursor) if cursor else None
+    relative_paths = []
+    for file_path in scanner.walk_files(str(root), respect_gitignore=respect_gitignore):
+        scanner_class = scanner.registry.get_scanner(file_path.suffix.lower())
+        if scanner_class is None or scanner_class.should_skip(file_path.name):
+            continue
+        try:
+            relative = file_path.resolve().relative_to(root).as_posix()
+        except ValueError:
+            continue  # symlinked out of the root
+        if after is None or relative > after:
+            relative_paths.append(relative)
+    relative_paths.sort()
+
+    page = relative_paths[:page_size]
+    has_more = len(relative_paths) > page_size
+    return {
+        "root": str(root),
+        "resources": [{
+            "uri": resource_uri(relative),
+            "name": relative,
+            "mimeType": "application/json",
+        } for relative in page],
+        "nextCursor": encode_cursor(page[-1]) if has_more else None,
+    }
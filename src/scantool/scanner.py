@@ -1,16 +1,62 @@
 """Main file scanner orchestrator using the plugin system."""
 
+import json
+import logging
 import os
+import threading
+import time
+from concurrent.futures import FIRST_COMPLETED, ThreadPoolExecutor, wait
+from dataclasses import dataclass, field
 from datetime import datetime
-from pathlib import Path
-from typing import Optional
+from pathlib import Path, PurePath, PurePosixPath
+from typing import Any, Callable, Iterator, Optional, TextIO
 
 import fnmatch as _fnmatch
-
-from .languages import StructureNode, get_registry
+import hashlib
+
+from .languages import (
+    SKIP_BINARY,
+    SKIP_INVALID_ENCODING,
+    SKIP_MAX_DEPTH,
+    SKIP_PANIC,
+    SKIP_PERMISSION,
+    SKIP_POST_PROCESS,
+    SKIP_TIMED_OUT,
+    SKIP_TOO_LARGE,
+    SKIP_UNREADABLE,
+    StructureNode,
+    get_registry,
+    parse_errors,
+)
 from .languages.skip_patterns import should_skip_directory
-from .gitignore import load_gitignore, GitignoreParser
-from .glob_expander import expand_braces
+from . import archive
+from .file_json import file_to_dict
+from .git_signals import changed_files
+from .golang import buildtags
+from .gitignore import load_gitignore, GitignoreParser, GitignoreTree
+from .line_counts import count_lines
+from .path_style import check_path_style, style_path
+from .scan_cache import ScanCache, scan_cache_key
+from .source_text import encoding_warning, normalize_source, restore_offsets
+from .symbol_ids import assign_symbol_ids
+from .glob_expander import expand_braces, load_scanignore, matches_doublestar
+
+# Files above this size are listed as too_large stubs, not parsed: generated
+# files of this size can take minutes (or all memory) to parse
+DEFAULT_MAX_FILE_SIZE = 5 * 1024 * 1024
+# Archive scans decompress into memory: bound the total and the entry count
+DEFAULT_ARCHIVE_MAX_BYTES = 256 * 1024 * 1024
+DEFAULT_ARCHIVE_MAX_ENTRIES = 100_000
+# A file that fails to parse within this many seconds of its last write is
+# likely being saved: re-read it (backoff doubling from READ_RETRY_BACKOFF)
+DEFAULT_READ_RETRIES = 2
+DEFAULT_RETRY_WINDOW = 0.5
+READ_RETRY_BACKOFF = 0.05
+
+# Binary/non-code files where entropy analysis is meaningless
+_BINARY_EXTENSIONS = {'.png', '.jpg', '.jpeg', '.gif', '.webp', '.bmp', '.ico', '.pdf'}
+
+logger = logging.getLogger(__name__)
 
 
 def _matches_pattern(rel_path: str, pattern: str) -> bool:
@@ -29,12 +75,177 @@ def _matches_pattern(rel_path: str, pattern: str) -> bool:
     return _fnmatch.fnmatch(rel_path, pattern)
 
 
+def _format_size(size_bytes: int) -> str:
+    if size_bytes < 1024:
+        return f"{size_bytes}B"
+    if size_bytes < 1024 * 1024:
+        return f"{size_bytes / 1024:.1f}KB"
+    return f"{size_bytes / (1024 * 1024):.1f}MB"
+
+
+# Bytes sniffed for binary detection — git's heuristic and window
+_BINARY_SNIFF_BYTES = 8000
+# UTF-16/32 text legitimately contains NUL bytes; a BOM says it is text
+_TEXT_BOMS = (b"\xff\xfe", b"\xfe\xff", b"\xef\xbb\xbf")
+
+
+def is_binary_head(head: bytes) -> bool:
+    """NUL byte in the first 8000 bytes, as git decides. BOM-marked UTF-16/32
+    files count as text; UTF-16 without a BOM is misjudged as binary (rare
+    for source code — git has the same blind spot)."""
+    if head.startswith(_TEXT_BOMS):
+        return False
+    return b"\x00" in head[:_BINARY_SNIFF_BYTES]
+
+
+def _looks_binary(file_str: str) -> bool:
+    """is_binary_head on the start of a file on disk."""
+    try:
+        with open(file_str, "rb") as f:
+            head = f.read(_BINARY_SNIFF_BYTES)
+    except OSError:
+        return False
+    return is_binary_head(head)
+
+
+def _file_stub(file_path: Path, file_stats: os.stat_result,
+               skipped: Optional[str] = None) -> StructureNode:
+    """file-info-only result for a file that is listed but not parsed:
+    an unsupported type, or a supported file skipped for `skipped` reason."""
+    return _stub(file_path, file_stats.st_size, file_stats.st_mtime, skipped)
+
+
+def _stub(file_path: PurePath, size: int, mtime: float,
+          skipped: Optional[str] = None) -> StructureNode:
+    """_file_stub from size and mtime (archive entries have no stat)."""
+    metadata = {
+        "size": size,
+        "size_formatted": _format_size(size),
+        "extension": file_path.suffix or "(no extension)",
+        "modified": datetime.fromtimestamp(mtime).isoformat(),
+        "unsupported": True,
+    }
+    if skipped:
+        metadata["skipped"] = skipped
+    return StructureNode(type="file-info", name=file_path.name, start_line=1,
+                         end_line=1, file_metadata=metadata)
+
+
+def _unreadable_stub(path: Path, error: OSError) -> StructureNode:
+    """Stub for a file or directory the scan could not read: the reason
+    (permission denied, else unreadable) and the OS error message."""
+    reason = SKIP_PERMISSION if isinstance(error, PermissionError) else SKIP_UNREADABLE
+    try:
+        stats = os.stat(path)
+        size, mtime = stats.st_size, stats.st_mtime
+    except OSError:
+        size, mtime = 0, 0
+    node = _stub(path, size, mtime, reason)
+    node.file_metadata["error"] = error.strerror or str(error)
+    return node
+
+
+def _failed_stub(message: str, reason: str) -> StructureNode:
+    """Error node for a file whose scan crashed (SKIP_PANIC), ran past its
+    deadline (SKIP_TIMED_OUT) or failed a post-processor
+    (SKIP_POST_PROCESS); the reason is kept as "skipped"."""
+    return StructureNode(type="error", name=message, start_line=1, end_line=1,
+                         file_metadata={"skipped": reason})
+
+
+def _panic_stub(file_str: str, error: Exception) -> StructureNode:
+    """_failed_stub for an exception out of a scanner, logged with the
+    file path and the stack — a crash is a scanner bug, not bad input."""
+    logger.error("Scanner crashed on %s", file_str, exc_info=error)
+    return _failed_stub(f"Failed to scan: {error}", SKIP_PANIC)
+
+
+def _is_generated(structures: Optional[list[StructureNode]]) -> bool:
+    return bool(structures and structures[0].type == "file-info"
+                and structures[0].file_metadata
+                and structures[0].file_metadata.get("generated"))
+
+
+def _excluded_by_build(structures: Optional[list[StructureNode]], tags: list[str]) -> bool:
+    """The file's build constraint is not satisfied by tags (a constraint
+    that doesn't parse keeps the file)."""
+    if not (structures and structures[0].type == "file-info" and structures[0].file_metadata):
+        return False
+    constraint = structures[0].file_metadata.get("build_constraint")
+    if not constraint:
+        return False
+    try:
+        return not buildtags.satisfied(constraint, tags)
+    except ValueError:
+        return False
+
+
 def _estimate_tokens(lines: list[str]) -> int:
     """Rough BPE-token estimate for display lines (~4 chars/token plus
     per-line prefix overhead) — used for budget allocation, not billing."""
     return len("\n".join(lines)) // 4 + len(lines)
 
 
+class ScanCancelled(Exception):
+    """A directory scan stopped early (cancel event set or timeout hit).
+
+    results holds the files that finished, in walk order — callers decide
+    whether partial data is useful.
+    """
+
+    def __init__(self, reason: str, results: dict[str, Optional[list[StructureNode]]]):
+        super().__init__(f"Scan {reason} after {len(results)} files")
+        self.reason = reason  # "cancelled" or "timed out"
+        self.results = results
+
+
+class LimitExceeded(ScanCancelled):
+    """A directory scan stopped at a resource limit: max_files,
+    max_total_bytes or parse_timeout (limit names which one).
+
+    A ScanCancelled, so callers keeping partial results on cancellation
+    keep them here too. Walk limits stop the walk, and the files found so
+    far are still parsed; a parse timeout stops at the slow file (file),
+    which is left out with the other unfinished ones.
+    """
+
+    def __init__(self, limit: str, value: float, results: dict[str, Optional[list[StructureNode]]],
+                 file: Optional[str] = None):
+        reason = f"stopped at {limit}={value:g}" + (f" on {file}" if file else "")
+        super().__init__(reason, results)
+        self.limit = limit
+        self.value = value
+        self.file = file
+
+
+class SymlinkOutsideRoot(ValueError):
+    """A symlink under the scan root resolves outside it (confine_to_root)."""
+
+    def __init__(self, link: str, target: str, root: str):
+        super().__init__(f"Symlink {link} points outside the scan root {root}: {target}")
+        self.link = link
+        self.target = target
+
+
+@dataclass
+class FileResult:
+    """One parsed file as a FileScanner post-processor sees it.
+
+    structures may be changed in place or replaced; meta carries the
+    processor's own fields (metrics, doc links) and ends up in the
+    file-info node's file_metadata["meta"] — without a file-info node
+    (include_file_metadata=False) it is dropped.
+    """
+
+    file: str
+    structures: list[StructureNode]
+    meta: dict[str, Any] = field(default_factory=dict)
+
+
+# Called with each parsed file's result; raising turns it into that file's error
+PostProcessor = Callable[[FileResult], None]
+
+
 class FileScanner:
     """Main scanner that delegates to language-specific scanner plugins."""
 
@@ -42,33 +253,101 @@ class FileScanner:
     # depth-2 measured as best fact-coverage per token (experiments/entropy_metrics/)
     BROAD_TIER_DEPTH = 2
 
-    def __init__(self, show_errors: bool = True, fallback_on_errors: bool = True):
+    def __init__(self, show_errors: bool = True, fallback_on_errors: bool = True,
+                 post_processors: Optional[list[PostProcessor]] = None,
+                 read_retries: int = DEFAULT_READ_RETRIES,
+                 retry_window: float = DEFAULT_RETRY_WINDOW):
         """
         Initialize file scanner.
 
         Args:
             show_errors: Show parse error nodes in output
             fallback_on_errors: Use regex fallback for severely broken files
+            post_processors: Run on every parsed file's result, in order,
+                before it is returned (see add_post_processor)
+            read_retries: Re-reads of a file that fails to parse while it
+                was written less than retry_window seconds ago — a save in
+                progress. Each waits (50 ms, doubling) and re-reads only if
+                the file changed meanwhile; after the last one the parse
+                errors are reported as usual. 0 = read once
+            retry_window: Seconds since the last write within which a
+                failed parse is retried
         """
         self.registry = get_registry()
         self.show_errors = show_errors
         self.fallback_on_errors = fallback_on_errors
+        self.post_processors: list[PostProcessor] = list(post_processors or [])
+        self.read_retries = read_retries
+        self.retry_window = retry_window
+
+    def add_post_processor(self, processor: PostProcessor) -> "FileScanner":
+        """
+        Register a post-processor, run after the ones already registered.
+
+        Each parsed file (scan_file, scan_content and everything built on
+        them: directories, archives) goes through the post-processors as a
+        FileResult before it is returned or cached. A processor that raises
+        stops the chain for that file only: its result becomes an error
+        node with skipped = "post_process" and the scan goes on. Files
+        listed without parsing (unsupported, too large, binary) are not
+        passed in. scan_directory calls processors from its worker
+        threads, so they must be safe to run concurrently.
+
+        Args:
+            processor: Callable taking the FileResult to enrich
+
+        Returns:
+            The scanner, for chaining
+        """
+        self.post_processors.append(processor)
+        return self
+
+    def _post_process(self, file_path: str,
+                      structures: Optional[list[StructureNode]]) -> Optional[list[StructureNode]]:
+        """structures through the post-processors, or the error node of the
+        first one that raised."""
+        if not self.post_processors or structures is None:
+            return structures
+        info = structures[0] if structures and structures[0].type == "file-info" else None
+        meta = dict((info.file_metadata or {}).get("meta") or {}) if info else {}
+        result = FileResult(file_path, structures, meta)
+        for processor in self.post_processors:
+            try:
+                processor(result)
+            except Exception as e:
+                name = getattr(processor, "__name__", repr(processor))
+                logger.warning("Post-processor %s failed on %s: %s", name, file_path, e)
+                return [_failed_stub(f"Post-processor {name} failed: {e}", SKIP_POST_PROCESS)]
+        structures = result.structures
+        if result.meta and structures and structures[0].type == "file-info":
+            structures[0].file_metadata = {**(structures[0].file_metadata or {}),
+                                           "meta": result.meta}
+        return structures
 
     def scan_content(
         self,
         content: str | bytes,
         filename: str,
-        include_metadata: bool = False
+        include_metadata: bool = False,
+        budget: Optional[int] = None,
+        mode: str = "balanced",
+        root: Optional[str] = None
     ) -> Optional[list[StructureNode]]:
         """
         Scan file content directly without requiring a file path.
 
         Useful for scanning remote files (e.g., from GitHub) or content from APIs.
+        Structure and code skeletons are identical to scan_file() on the same
+        bytes; only the file-info metadata differs (no timestamps on content).
 
         Args:
             content: File content as string or bytes
             filename: Filename (used to determine language/scanner type)
             include_metadata: Include basic metadata node (just filename and size)
+            budget: Approximate token cap for code skeletons (see scan_file)
+            mode: Saliency weight profile — "balanced" or "active"
+            root: Root filename is relative to, for symbol ID namespaces
+                (symbol_ids.symbol_namespace); None = its own directory
 
         Returns:
             List of StructureNode objects, or None if file type not supported
@@ -91,22 +370,28 @@ class FileScanner:
 
         # Convert content to bytes if needed
         if isinstance(content, str):
-            source_code = content.encode('utf-8')
+            raw_source = content.encode('utf-8')
         else:
-            source_code = content
+            raw_source = content
+        size_bytes = len(raw_source)
+        source_code = raw_source
+        if suffix not in _BINARY_EXTENSIONS:
+            source_code = normalize_source(raw_source)
 
         # Scan using the appropriate plugin
         structures = scanner.scan(source_code)
+        if structures is not None:
+            assign_symbol_ids(structures, filename, scanner, source_code, root)
+
+        if structures is not None and suffix not in _BINARY_EXTENSIONS:
+            self._annotate_salient_code(structures, filename, source_code,
+                                        language=scanner, budget=budget, mode=mode)
+        if structures is not None and source_code is not raw_source:
+            restore_offsets(structures, raw_source)
 
         # Prepend metadata if requested and structures exist
         if include_metadata and structures is not None:
-            size_bytes = len(source_code)
-            if size_bytes < 1024:
-                size_str = f"{size_bytes}B"
-            elif size_bytes < 1024 * 1024:
-                size_str = f"{size_bytes / 1024:.1f}KB"
-            else:
-                size_str = f"{size_bytes / (1024 * 1024):.1f}MB"
+            size_str = _format_size(size_bytes)
 
             file_info = StructureNode(
                 type="file-info",
@@ -117,11 +402,33 @@ class FileScanner:
                     "size": size_bytes,
                     "size_formatted": size_str,
                     "source": "content",
+                    "language": scanner_class.get_file_language_name(path.name),
+                    "content_hash": hashlib.sha256(raw_source).hexdigest(),
                 }
             )
+            file_doc = scanner.extract_file_doc(source_code)
+            if file_doc:
+                file_info.file_metadata["doc"] = file_doc
+            if scanner.is_generated(source_code):
+                file_info.file_metadata["generated"] = True
+            constraint = scanner.build_constraint(source_code)
+            if constraint:
+                file_info.file_metadata["build_constraint"] = constraint
+            imports = scanner.import_list(source_code)
+            if imports:
+                file_info.file_metadata["imports"] = imports
+            package = scanner.extract_namespace(source_code)
+            if package:
+                file_info.file_metadata["package"] = package
+            warning = encoding_warning(raw_source) if suffix not in _BINARY_EXTENSIONS else None
+            if warning:
+                file_info.file_metadata["encoding_warning"] = warning
+            if suffix not in _BINARY_EXTENSIONS:
+                file_info.file_metadata["lines"] = count_lines(
+                    source_code, scanner.comment_spans(source_code))
             structures = [file_info] + structures
 
-        return structures
+        return self._post_process(filename, structures)
 
     def scan_file(
         self,
@@ -129,7 +436,9 @@ class FileScanner:
         include_file_metadata: bool = True,
         budget: Optional[int] = None,
         line_edits: Optional[dict[int, str]] = None,
-        mode: str = "balanced"
+        mode: str = "balanced",
+        invalid_encoding: str = "warn",
+        root: Optional[str] = None
     ) -> Optional[list[StructureNode]]:
         """
         Scan a single file and return its structure.
@@ -144,6 +453,13 @@ class FileScanner:
                 (from git_signals.recent_line_edits); boosts actively-worked
                 nodes in selection and sets "[N edits/90d]" labels
             mode: Saliency weight profile — "balanced" or "active"
+            invalid_encoding: A text file that isn't valid UTF-8 is parsed
+                anyway with file_metadata["encoding_warning"] saying where
+                ("warn"), or returned as a stub with skipped =
+                "invalid_encoding" and the same warning, unparsed ("skip")
+            root: Scan root the file belongs to: symbol IDs are namespaced
+                by its path below it (symbol_ids.symbol_namespace). None =
+                the file's directory, as for a file scanned on its own
 
         Returns:
             List of StructureNode objects, or None if file type not supported
@@ -169,31 +485,52 @@ class FileScanner:
             fallback_on_errors=self.fallback_on_errors
         )
 
-        # Read file
-        with open(file_path, "rb") as f:
-            source_code = f.read()
-
-        # Scan using the appropriate plugin
-        structures = scanner.scan(source_code)
+        # Read and scan; a parse failure right after a write may be a
+        # half-saved file, read again once the writer has moved on
+        attempt = 0
+        while True:
+            with open(file_path, "rb") as f:
+                raw_source = f.read()
+            source_code = raw_source
+            warning = None
+            if suffix not in _BINARY_EXTENSIONS:
+                warning = encoding_warning(raw_source)
+                if warning and invalid_encoding == "skip":
+                    stub = _file_stub(path, file_stats, SKIP_INVALID_ENCODING)
+                    stub.file_metadata["encoding_warning"] = warning
+                    return [stub]
+                source_code = normalize_source(raw_source)
+            structures = scanner.scan(source_code)
+            if (attempt >= self.read_retries or not parse_errors(file_path, structures)
+                    or time.time() - file_stats.st_mtime > self.retry_window):
+                break
+            time.sleep(READ_RETRY_BACKOFF * 2 ** attempt)
+            attempt += 1
+            fresh_stats = os.stat(file_path)
+            if (fresh_stats.st_mtime_ns, fresh_stats.st_size) == \
+                    (file_stats.st_mtime_ns, file_stats.st_size):
+                break  # no write since: the errors are the file's own
+            logger.debug("Re-reading %s after a parse failure mid-write (retry %d)",
+                         file_path, attempt)
+            file_stats = fresh_stats
+
+        if structures is not None:
+            assign_symbol_ids(structures, file_path, scanner, source_code, root)
 
         # Entropy-based saliency analysis (annotate high-importance code regions)
         # Skip for binary/non-code files where entropy analysis is meaningless
-        binary_extensions = {'.png', '.jpg', '.jpeg', '.gif', '.webp', '.bmp', '.ico', '.pdf'}
-        if structures is not None and suffix not in binary_extensions:
+        if structures is not None and suffix not in _BINARY_EXTENSIONS:
             self._annotate_salient_code(structures, file_path, source_code,
                                         language=scanner, budget=budget,
                                         line_edits=line_edits, mode=mode)
+        if structures is not None and source_code is not raw_source:
+            restore_offsets(structures, raw_source)
 
         # Prepend file metadata if requested and structures exist
         if include_file_metadata and structures is not None:
             # Format file size
             size_bytes = file_stats.st_size
-            if size_bytes < 1024:
-                size_str = f"{size_bytes}B"
-            elif size_bytes < 1024 * 1024:
-                size_str = f"{size_bytes / 1024:.1f}KB"
-            else:
-                size_str = f"{size_bytes / (1024 * 1024):.1f}MB"
+            size_str = _format_size(size_bytes)
 
             # Create file info node
             file_info = StructureNode(
@@ -207,11 +544,32 @@ class FileScanner:
                     "created": datetime.fromtimestamp(file_stats.st_ctime).isoformat(),
                     "modified": datetime.fromtimestamp(file_stats.st_mtime).isoformat(),
                     "permissions": oct(file_stats.st_mode)[-3:],
+                    "language": scanner_class.get_file_language_name(path.name),
+                    "content_hash": hashlib.sha256(raw_source).hexdigest(),
                 }
             )
+            file_doc = scanner.extract_file_doc(source_code)
+            if file_doc:
+                file_info.file_metadata["doc"] = file_doc
+            if scanner.is_generated(source_code):
+                file_info.file_metadata["generated"] = True
+            constraint = scanner.build_constraint(source_code)
+            if constraint:
+                file_info.file_metadata["build_constraint"] = constraint
+            imports = scanner.import_list(source_code)
+            if imports:
+                file_info.file_metadata["imports"] = imports
+            package = scanner.extract_namespace(source_code)
+            if package:
+                file_info.file_metadata["package"] = package
+            if warning:
+                file_info.file_metadata["encoding_warning"] = warning
+            if suffix not in _BINARY_EXTENSIONS:
+                file_info.file_metadata["lines"] = count_lines(
+                    source_code, scanner.comment_spans(source_code))
             structures = [file_info] + structures
 
-        return structures
+        return self._post_process(file_path, structures)
 
     # Display level degradation order: full tier loses depth before the
     # broad tier loses breadth — depth-2 outlines measured as the most
@@ -350,35 +708,36 @@ class FileScanner:
                 import sys
                 print(f"Warning: Entropy analysis failed for {file_path}: {e}", file=sys.stderr)
 
-    def scan_directory(
+    def walk_files(
         self,
         directory: str,
         pattern: str = "**/*",
         respect_gitignore: bool = True,
         exclude_patterns: Optional[list[str]] = None,
-        mode: str = "balanced"
-    ) -> dict[str, Optional[list[StructureNode]]]:
-        """
-        Scan all supported files in a directory.
-
-        Args:
-            directory: Directory path to scan
-            pattern: Glob pattern for files (use "**/*" for recursive, "*" for current dir only)
-            respect_gitignore: Respect .gitignore exclusions (default: True)
-            exclude_patterns: Additional patterns to exclude (gitignore syntax)
-            mode: Saliency weight profile per file — "balanced" or "active"
-
-        Returns:
-            Dictionary mapping file paths to their structures
-        """
-        results = {}
+        skip_dirs: Optional[list[str]] = None,
+        include: Optional[list[str]] = None,
+        exclude: Optional[list[str]] = None,
+        follow_symlinks: bool = False,
+        confine_to_root: bool = False,
+        on_error: Optional[Callable[[Path, OSError], None]] = None,
+        max_depth: Optional[int] = None,
+        on_depth_limit: Optional[Callable[[Path], None]] = None
+    ) -> Iterator[Path]:
+        """Files scan_directory would consider, in walk order (sorted per
+        directory): pattern, gitignore, exclusions and noise-dir pruning
+        applied, nothing read or parsed. Arguments as for scan_directory.
+
+        A subdirectory that can't be listed is skipped and passed to
+        on_error (if given); the walk goes on. Only the root itself failing
+        to list raises. A subdirectory below max_depth that would otherwise
+        be entered is passed to on_depth_limit (if given) instead."""
         dir_path = Path(directory).resolve()
-
         if not dir_path.exists():
             raise FileNotFoundError(f"Directory not found: {directory}")
 
         # Load gitignore if requested
-        gitignore = load_gitignore(dir_path) if respect_gitignore else None
+        gitignore = (GitignoreTree(load_gitignore(dir_path))
+                     if respect_gitignore else None)
 
         # Default exclusions - always applied
         default_exclusions = [
@@ -405,6 +764,12 @@ class FileScanner:
             '.mypy_cache/',   # MyPy cache
         ]
 
+        # An explicit skip list owns directory pruning — the directory
+        # entries of the defaults would silently re-add what it leaves out
+        if skip_dirs is not None:
+            default_exclusions = [p for p in default_exclusions if not p.endswith("/")]
+            skip_set = set(skip_dirs) | {".git"}
+
         # Combine defaults with user-provided exclusions
         all_exclude_patterns = default_exclusions.copy()
         if exclude_patterns:
@@ -416,9 +781,32 @@ class FileScanner:
         # Expand brace patterns (e.g., "**/*.{py,js}" → ["**/*.py", "**/*.js"])
         expanded_patterns = expand_braces(pattern)
 
-        seen_files: set[str] = set()
+        # A checked-in .scanignore at the root adds to the caller's excludes
+        exclude = [*(exclude or ()), *load_scanignore(dir_path)]
+
+        # Whole subtrees an exclude glob covers ("internal/**") are pruned
+        excluded_dirs = [p[:-3] for p in exclude or () if p.endswith("/**")]
 
-        for root, dirs, files in os.walk(str(dir_path)):
+        seen_files: set[str] = set()
+        # Following links: every directory is entered once (link cycles,
+        # two links to one directory) and every file reported once
+        visited_dirs = {str(dir_path)}
+        seen_targets: set[str] = set()
+
+        def confined(link: Path) -> str:
+            target = os.path.realpath(link)
+            if confine_to_root and not Path(target).is_relative_to(dir_path):
+                raise SymlinkOutsideRoot(str(link), target, str(dir_path))
+            return target
+
+        def walk_error(error: OSError) -> None:
+            if error.filename is None or Path(error.filename) == dir_path:
+                raise error
+            if on_error is not None:
+                on_error(Path(error.filename), error)
+
+        for root, dirs, files in os.walk(str(dir_path), onerror=walk_error,
+                                         followlinks=follow_symlinks):
             root_path = Path(root)
             try:
                 rel_root = root_path.relative_to(dir_path)
@@ -428,19 +816,35 @@ class FileScanner:
             rel_root_str = str(rel_root).replace(os.sep, "/")
             if rel_root_str == ".":
                 rel_root_str = ""
+            if gitignore:
+                gitignore.add_directory(rel_root_str, root_path)
 
             # Prune directories in-place so os.walk never descends into them.
+            depth = rel_root_str.count("/") + 1 if rel_root_str else 0
             pruned = []
             for d in sorted(dirs):
-                if d.startswith("."):
-                    continue
-                if should_skip_directory(d):
+                if skip_dirs is not None:
+                    if d in skip_set:
+                        continue
+                elif d.startswith(".") or should_skip_directory(d):
                     continue
                 dir_rel = f"{rel_root_str}/{d}" if rel_root_str else d
                 if gitignore and gitignore.matches(dir_rel + "/", True):
                     continue
                 if exclude_parser and exclude_parser.matches(dir_rel + "/", True):
                     continue
+                if excluded_dirs and matches_doublestar(dir_rel, excluded_dirs):
+                    continue
+                if max_depth is not None and depth >= max_depth:
+                    if on_depth_limit is not None:
+                        on_depth_limit(root_path / d)
+                    continue
+                if follow_symlinks:
+                    real = confined(root_path / d) if (root_path / d).is_symlink() \
+                        else os.path.realpath(root_path / d)
+                    if real in visited_dirs:
+                        continue
+                    visited_dirs.add(real)
                 pruned.append(d)
             dirs[:] = pruned
 
@@ -458,54 +862,520 @@ class FileScanner:
                     continue
 
                 # Check gitignore and additional exclusions
-                if gitignore and gitignore.matches(rel_path_native, False):
+                if gitignore and gitignore.matches(rel_path_raw, False):
                     continue
                 if exclude_parser and exclude_parser.matches(rel_path_native, False):
                     continue
+                # Include/exclude globs: excludes win over includes
+                if exclude and matches_doublestar(rel_path_raw, exclude):
+                    continue
+                if include and not matches_doublestar(rel_path_raw, include):
+                    continue
+                if follow_symlinks or confine_to_root:
+                    target = (confined(file_path) if file_path.is_symlink()
+                              else os.path.realpath(file_path))
+                    if follow_symlinks:
+                        if target in seen_targets:
+                            continue  # reached through another link
+                        seen_targets.add(target)
 
                 seen_files.add(file_str)
+                yield file_path
+
+    def scan_directory(
+        self,
+        directory: str,
+        pattern: str = "**/*",
+        respect_gitignore: bool = True,
+        exclude_patterns: Optional[list[str]] = None,
+        mode: str = "balanced",
+        skip_dirs: Optional[list[str]] = None,
+        cache: Optional[ScanCache] = None,
+        cache_content_hash: bool = False,
+        workers: Optional[int] = None,
+        cancel: Optional[threading.Event] = None,
+        timeout: Optional[float] = None,
+        git_diff_base: Optional[str] = None,
+        max_file_size: Optional[int] = DEFAULT_MAX_FILE_SIZE,
+        exclude_generated: bool = False,
+        include: Optional[list[str]] = None,
+        exclude: Optional[list[str]] = None,
+        max_files: Optional[int] = None,
+        max_total_bytes: Optional[int] = None,
+        parse_timeout: Optional[float] = None,
+        file_timeout: Optional[float] = None,
+        build_tags: Optional[list[str]] = None,
+        follow_symlinks: bool = False,
+        confine_to_root: bool = False,
+        on_file: Optional[Callable[[str, Optional[list[StructureNode]]], None]] = None,
+        max_depth: Optional[int] = None,
+        report_depth_limit: bool = False,
+        modified_since: Optional[datetime] = None,
+        roots: Optional[list[str]] = None,
+        invalid_encoding: str = "warn"
+    ) -> dict[str, Optional[list[StructureNode]]]:
+        """
+        Scan all supported files in a directory.
+
+        Directory symlinks are not followed unless follow_symlinks is set;
+        then each directory is entered once, so link cycles cannot recurse.
+
+        Args:
+            directory: Directory path to scan
+            pattern: Glob pattern for files (use "**/*" for recursive, "*" for current dir only)
+            respect_gitignore: Respect .gitignore exclusions (default: True).
+                Includes nested .gitignore files, each scoped to its own
+                subtree, with "!" negation and trailing-slash dir-only rules.
+            exclude_patterns: Additional patterns to exclude (gitignore syntax)
+            mode: Saliency weight profile per file — "balanced" or "active"
+            skip_dirs: Directory names never descended into. None = built-in
+                noise list (hidden dirs, node_modules, vendor, build output,
+                caches, ...); a list REPLACES it — e.g. ["node_modules"] to
+                scan vendor/ and dot-dirs too. .git is always skipped.
+            cache: Per-file result store (see scan_cache). Files whose path,
+                mtime and size are unchanged are served from it instead of
+                re-parsed. None = always parse.
+            cache_content_hash: Also key the cache on a hash of the file
+                bytes, for tools that rewrite files but keep their mtime
+            workers: Parallel file scans (threads). None = os.cpu_count(),
+                1 = sequential. Result order is the walk order either way.
+            cancel: Event checked between files; once set,
+                the scan stops and raises ScanCancelled with partial results
+            timeout: Seconds before the scan stops the same way (None = no limit)
+            git_diff_base: Only files changed against this git ref (working
+                tree incl. untracked; deleted files skipped, renamed ones at
+                their new path). Pattern and exclusions still apply.
+            max_file_size: Supported files larger than this (bytes) are not
+                parsed; they are listed as file-info stubs with
+                file_metadata["skipped"] = "too_large" (see skipped_files).
+                None = no limit (default: 5 MB)
+            exclude_generated: Drop files whose language marks them
+                generated (Go "// Code generated ... DO NOT EDIT.") from the
+                results; they are still flagged file_metadata["generated"]
+                when kept
+            include: Doublestar globs over the path relative to directory
+                ("**/*_test.go", "cmd/**"); only matching files are
+                scanned. Applied on top of pattern.
+            exclude: Doublestar globs of files to leave out ("internal/**");
+                wins over include. Composes with gitignore and
+                exclude_patterns — excluded by any of them means skipped.
+                A .scanignore file at the root (one glob per line, "#"
+                comments) is read and added to these
+            max_files: Stop walking once this many files are in the results
+                (None = no limit) — bounds a scan pointed at "/"
+            max_total_bytes: Stop walking before the supported files to
+                parse would exceed this many bytes in total (None = no limit)
+            parse_timeout: Seconds one file may take to parse; past it the
+                scan stops. Parsing runs in the worker pool then (even with
+                workers=1) — a Python thread can't be killed, so the stuck
+                parse finishes in the background, unwaited (None = no limit)
+            file_timeout: Seconds one file may take to parse; past it the
+                file alone is given up — kept as an error node with skipped
+                = "timed_out" — and the scan goes on. Also runs in the pool;
+                the stuck parse keeps its worker busy until it returns, so
+                a tree of pathological files can still run into timeout
+                (None = no limit)
+            build_tags: Drop files whose build constraint (Go //go:build or
+                // +build, file_metadata["build_constraint"]) is not
+                satisfied by exactly these tags — list GOOS/GOARCH too,
+                e.g. ["linux", "amd64"] (None = no filtering)
+            follow_symlinks: Descend into directory symlinks. Each directory
+                (by resolved path) is walked once and each file reported
+                once — at the first link path that reaches it
+            confine_to_root: Raise SymlinkOutsideRoot for a symlinked file,
+                or a followed directory symlink, that resolves outside the
+                scan root
+            on_file: Called with (path, structures) for each file as soon as
+                its result is final — stubs during the walk, parsed files as
+                they finish (completion order with workers > 1), always on
+                the calling thread. The structures are then not kept: the
+                returned dict, and the partial results of a ScanCancelled,
+                map the paths to None, so memory stays flat on huge trees
+            max_depth: Directory levels descended below directory: 0 = only
+                the files directly in it, 1 = one level of subdirectories
+                too, and so on (None = no limit). Deeper directories are not
+                entered and not reported
+            report_depth_limit: List each directory max_depth kept the walk
+                out of as a stub with skipped = "max_depth"
+            modified_since: Only files whose mtime is after this time (a
+                naive datetime is local time). Directories are walked
+                regardless, so a recent file deep in an old tree is found;
+                older files are left out of the results, not stubbed, and
+                don't count toward max_files (None = every file)
+            roots: More directories scanned with directory as one set: they
+                are walked after it, in order, and every limit (max_files,
+                max_total_bytes, timeout) and the worker pool apply to the
+                combined files. A file reached from two roots (one nested
+                in the other) is scanned and reported once. Patterns,
+                gitignore, .scanignore and git_diff_base work per root
+            invalid_encoding: Files that aren't valid UTF-8 are parsed with
+                an encoding_warning ("warn") or listed unparsed with skipped
+                = "invalid_encoding" ("skip"), as in scan_file
+
+        Supported files containing a NUL byte in their first 8000 bytes are
+        not parsed either (skipped = "binary"); image/PDF types, whose
+        handlers read binary formats, are exempt.
+
+        A file that crashes its scanner doesn't abort the scan either: the
+        exception is logged with the path and stack trace, and the file is
+        kept as an error node with skipped = "panic" (see parse_errors).
+
+        Unreadable entries don't abort the scan: a subdirectory that can't
+        be listed or a file that can't be read is kept as a stub with
+        skipped = "permission_denied" (or "unreadable" for other OS
+        errors) and the error message, and the walk continues. Only the
+        root itself missing or unlistable raises.
 
-                scanner_class = self.registry.get_scanner(file_path.suffix.lower())
-                if scanner_class:
-                    if scanner_class.should_skip(file_path.name):
+        Returns:
+            Dictionary mapping file paths to their structures
+
+        Raises:
+            ScanCancelled: cancel was set or timeout elapsed (carries the
+                files scanned so far)
+            LimitExceeded: max_files, max_total_bytes or parse_timeout was
+                hit (a ScanCancelled; carries the partial results)
+            ValueError: git_diff_base given outside a git repo, or unknown ref
+            SymlinkOutsideRoot: confine_to_root and a link leaves the root
+                (a ValueError)
+            ValueError: max_depth is negative
+        """
+        if max_depth is not None and max_depth < 0:
+            raise ValueError(f"max_depth must be 0 or more, got {max_depth}")
+        results = {}
+        given = [directory, *(roots or [])]
+        dir_paths = list(dict.fromkeys(Path(d).resolve() for d in given))
+        deadline = time.monotonic() + timeout if timeout is not None else None
+
+        def stop_reason() -> Optional[str]:
+            if cancel is not None and cancel.is_set():
+                return "cancelled"
+            if deadline is not None and time.monotonic() > deadline:
+                return "timed out"
+            return None
+
+        def finished() -> dict[str, Optional[list[StructureNode]]]:
+            return {path: structures for path, structures in results.items()
+                    if path not in unfinished}
+
+        def stop(reason: str):
+            raise ScanCancelled(reason, finished())
+
+        for root in given:
+            if not Path(root).exists():
+                raise FileNotFoundError(f"Directory not found: {root}")
+
+        def emit(file_str: str, structures: Optional[list[StructureNode]]) -> None:
+            if on_file is None:
+                results[file_str] = structures
+            else:
+                results[file_str] = None  # the path still counts toward max_files
+                on_file(file_str, structures)
+
+        def unreadable(path: Path, error: OSError) -> None:
+            emit(str(path), [_unreadable_stub(path, error)])
+
+        def depth_limited(path: Path) -> None:
+            try:
+                mtime = os.stat(path).st_mtime
+            except OSError:
+                mtime = 0
+            emit(str(path), [_stub(path, 0, mtime, SKIP_MAX_DEPTH)])
+
+        # Restrict to files changed against a ref (CI: scan the diff only)
+        only_files = set().union(*(changed_files(str(dir_path), git_diff_base)
+                                   for dir_path in dir_paths)) \
+            if git_diff_base is not None else None
+        cutoff = modified_since.timestamp() if modified_since is not None else None
+
+        pending: list[str] = []  # supported files, parsed after the walk
+        file_roots: dict[str, str] = {}  # pending file -> the root it was walked from
+        unfinished: set[str] = set()  # placeholders not yet scanned
+        walk_limit: Optional[tuple[str, int]] = None  # (limit, value) that ended the walk
+        total_bytes = 0
+
+        walk = ((str(dir_path), file_path) for dir_path in dir_paths
+                for file_path in self.walk_files(
+                    str(dir_path), pattern, respect_gitignore,
+                    exclude_patterns, skip_dirs, include, exclude,
+                    follow_symlinks, confine_to_root, unreadable,
+                    max_depth=max_depth,
+                    on_depth_limit=depth_limited if report_depth_limit else None))
+        for walk_root, file_path in walk:
+            if (reason := stop_reason()) is not None:
+                unfinished.update(pending)
+                stop(reason)
+            file_str = str(file_path)
+            if file_str in results:  # nested roots: walked twice, kept once
+                continue
+            if only_files is not None and os.path.realpath(file_str) not in only_files:
+                continue
+            if cutoff is not None:
+                try:
+                    if os.stat(file_str).st_mtime <= cutoff:
                         continue
-                    try:
-                        results[file_str] = self.scan_file(file_str, mode=mode)
-                    except Exception as e:
-                        results[file_str] = [StructureNode(
-                            type="error",
-                            name=f"Failed to scan: {str(e)}",
-                            start_line=1,
-                            end_line=1
-                        )]
-                else:
+                except OSError:
+                    continue
+            if max_files is not None and len(results) >= max_files:
+                walk_limit = ("max_files", max_files)
+                break
+
+            scanner_class = self.registry.get_scanner(file_path.suffix.lower())
+            if scanner_class:
+                if scanner_class.should_skip(file_path.name):
+                    continue
+                file_size = 0
+                if max_file_size is not None or max_total_bytes is not None:
                     try:
                         file_stats = os.stat(file_str)
-                        size_bytes = file_stats.st_size
-                        if size_bytes < 1024:
-                            size_str = f"{size_bytes}B"
-                        elif size_bytes < 1024 * 1024:
-                            size_str = f"{size_bytes / 1024:.1f}KB"
-                        else:
-                            size_str = f"{size_bytes / (1024 * 1024):.1f}MB"
-                        results[file_str] = [StructureNode(
-                            type="file-info",
-                            name=file_path.name,
-                            start_line=1,
-                            end_line=1,
-                            file_metadata={
-                                "size": size_bytes,
-                                "size_formatted": size_str,
-                                "extension": file_path.suffix or "(no extension)",
-                                "modified": datetime.fromtimestamp(file_stats.st_mtime).isoformat(),
-                                "unsupported": True
-                            }
-                        )]
-                    except Exception:
+                    except OSError:
                         continue
+                    if max_file_size is not None and file_stats.st_size > max_file_size:
+                        emit(file_str, [_file_stub(file_path, file_stats, SKIP_TOO_LARGE)])
+                        continue
+                    file_size = file_stats.st_size
+                if max_total_bytes is not None and total_bytes + file_size > max_total_bytes:
+                    walk_limit = ("max_total_bytes", max_total_bytes)
+                    break
+                # A .c that is really an object file, a .json that is a
+                # blob: sniff before handing bytes to a text grammar
+                if (file_path.suffix.lower() not in _BINARY_EXTENSIONS
+                        and _looks_binary(file_str)):
+                    try:
+                        stub = _file_stub(file_path, os.stat(file_str), SKIP_BINARY)
+                    except OSError:
+                        continue
+                    emit(file_str, [stub])
+                    continue
+                results[file_str] = None  # placeholder: keeps walk order
+                pending.append(file_str)
+                file_roots[file_str] = walk_root
+                total_bytes += file_size
+            else:
+                try:
+                    stub = _file_stub(file_path, os.stat(file_str))
+                except Exception:
+                    continue
+                emit(file_str, [stub])
+
+        started: dict[str, float] = {}  # file -> monotonic time its parse began
+
+        def scan_one(file_str: str) -> Optional[list[StructureNode]]:
+            started[file_str] = time.monotonic()
+            return self._scan_file_cached(file_str, mode, cache, cache_content_hash,
+                                          invalid_encoding, file_roots[file_str])
+
+        def overdue(files, seconds: float) -> list[str]:
+            """The files parsing for longer than seconds."""
+            now = time.monotonic()
+            return [f for f in files if f in started and now - started[f] > seconds]
+
+        def store(file_str: str, structures: Optional[list[StructureNode]]) -> None:
+            unfinished.discard(file_str)
+            if exclude_generated and _is_generated(structures):
+                del results[file_str]
+            elif build_tags is not None and _excluded_by_build(structures, build_tags):
+                del results[file_str]
+            else:
+                emit(file_str, structures)
+
+        # Parse in a bounded pool; results land in their walk-order slots, so
+        # the output never depends on thread scheduling
+        unfinished.update(pending)
+        workers = workers or os.cpu_count() or 1
+        if parse_timeout is None and file_timeout is None and (workers == 1 or len(pending) < 2):
+            for file_str in pending:
+                if (reason := stop_reason()) is not None:
+                    stop(reason)
+                store(file_str, scan_one(file_str))
+        elif pending:
+            pool = ThreadPoolExecutor(max_workers=min(workers, len(pending)))
+            try:
+                futures = {pool.submit(scan_one, f): f for f in pending}
+                not_done = set(futures)
+                while not_done:
+                    done, not_done = wait(not_done, timeout=0.1, return_when=FIRST_COMPLETED)
+                    for future in done:
+                        store(futures[future], future.result())
+                    if not_done and (reason := stop_reason()) is not None:
+                        stop(reason)
+                    if not_done and parse_timeout is not None:
+                        slow = overdue((futures[f] for f in not_done), parse_timeout)
+                        if slow:
+                            raise LimitExceeded("parse_timeout", parse_timeout, finished(),
+                                                file=slow[0])
+                    if not_done and file_timeout is not None:
+                        # Give up on the file, not the scan: its result is never read
+                        by_file = {futures[f]: f for f in not_done}
+                        for slow in overdue(by_file, file_timeout):
+                            logger.warning("Parsing %s took over %gs, skipped", slow, file_timeout)
+                            not_done.discard(by_file[slow])
+                            store(slow, [_failed_stub(
+                                f"Parse timed out after {file_timeout:g}s", SKIP_TIMED_OUT)])
+            finally:
+                # Files already parsing finish in the background; queued ones are dropped
+                pool.shutdown(wait=False, cancel_futures=True)
+
+        if walk_limit is not None:
+            raise LimitExceeded(*walk_limit, results)
+        return results
+
+    def scan_directory_stream(self, directory: str, writer: TextIO,
+                              path_style: str = "absolute", **options) -> int:
+        """
+        Scan a directory like scan_directory, writing each file's result to
+        writer as it is produced: newline-delimited JSON, one
+        output_format="json" file object per line, flushed line by line.
+        Nothing is buffered, so memory stays flat and a consumer can start
+        before the scan finishes.
+
+        Args:
+            directory: Directory path to scan
+            writer: Text stream the lines go to (a file, sys.stdout, a pipe)
+            path_style: "absolute" or "relative" (to directory, "/"
+                separators) for the paths in each line (see path_style)
+            **options: Any scan_directory argument except on_file
+
+        Returns:
+            Number of lines written
+
+        Raises:
+            Whatever scan_directory raises. Every line written before the
+            error is a complete JSON object; the rest are never written.
+        """
+        check_path_style(path_style)
+        root = str(Path(directory).resolve())
+        written = 0
+
+        def write(file_str: str, structures: Optional[list[StructureNode]]) -> None:
+            nonlocal written
+            if not structures:
+                return
+            file_str = style_path(file_str, root, path_style)
+            writer.write(json.dumps(file_to_dict(structures, file_str)) + "\n")
+            writer.flush()
+            written += 1
+
+        self.scan_directory(directory, on_file=write, **options)
+        return written
+
+    def scan_archive(
+        self,
+        archive_path: str,
+        include: Optional[list[str]] = None,
+        exclude: Optional[list[str]] = None,
+        mode: str = "balanced",
+        max_file_size: Optional[int] = DEFAULT_MAX_FILE_SIZE,
+        max_total_bytes: Optional[int] = DEFAULT_ARCHIVE_MAX_BYTES,
+        max_entries: Optional[int] = DEFAULT_ARCHIVE_MAX_ENTRIES
+    ) -> dict[str, Optional[list[StructureNode]]]:
+        """
+        Scan the files of a zip or tar archive in memory, without extracting.
+
+        Supported entries go through scan_content, so results match a
+        scan_file of the extracted file except for the file-info metadata.
+        See archive.py for the zip-slip and decompression-bomb guards.
 
+        Args:
+            archive_path: .zip, .tar, .tar.gz/.tgz, .tar.bz2 or .tar.xz file
+            include: Doublestar globs over the entry path; only matching
+                entries are scanned
+            exclude: Doublestar globs of entries to leave out (wins over
+                include). Entries under noise directories (node_modules,
+                vendor, hidden dirs, ...) are always left out
+            mode: Saliency weight profile — "balanced" or "active"
+            max_file_size: Supported entries decompressing to more than this
+                many bytes are listed with skipped = "too_large", not parsed
+            max_total_bytes: Stop once the entries read so far decompress to
+                more than this in total (None = no limit)
+            max_entries: Stop after this many entries (None = no limit)
+
+        Returns:
+            Dictionary mapping entry paths inside the archive ("src/a.go")
+            to their structures, in archive order
+
+        Raises:
+            FileNotFoundError: archive_path does not exist
+            ValueError: the file is neither a zip nor a tar archive
+            LimitExceeded: max_total_bytes or max_entries was hit (carries
+                the entries scanned so far)
+        """
+        if not os.path.isfile(archive_path):
+            raise FileNotFoundError(f"Archive not found: {archive_path}")
+
+        results: dict[str, Optional[list[StructureNode]]] = {}
+        total_bytes = 0
+        for count, entry in enumerate(archive.iter_entries(archive_path), start=1):
+            if max_entries is not None and count > max_entries:
+                raise LimitExceeded("max_entries", max_entries, results)
+            entry_path = PurePosixPath(entry.name)
+            if any(should_skip_directory(part) for part in entry_path.parts[:-1]):
+                continue
+            if include and not matches_doublestar(entry.name, include):
+                continue
+            if exclude and matches_doublestar(entry.name, exclude):
+                continue
+
+            scanner_class = self.registry.get_scanner(entry_path.suffix.lower())
+            if scanner_class is None:
+                results[entry.name] = [_stub(entry_path, entry.size, entry.mtime)]
+                continue
+            if scanner_class.should_skip(entry_path.name):
+                continue
+            # The declared size can lie; read_limited checks what decompresses
+            content = (None if max_file_size is not None and entry.size > max_file_size
+                       else archive.read_limited(entry, max_file_size))
+            if content is None:
+                results[entry.name] = [_stub(entry_path, entry.size, entry.mtime,
+                                             SKIP_TOO_LARGE)]
+                continue
+            total_bytes += len(content)
+            if max_total_bytes is not None and total_bytes > max_total_bytes:
+                raise LimitExceeded("max_total_bytes", max_total_bytes, results)
+            if (entry_path.suffix.lower() not in _BINARY_EXTENSIONS
+                    and is_binary_head(content)):
+                results[entry.name] = [_stub(entry_path, len(content), entry.mtime,
+                                             SKIP_BINARY)]
+                continue
+            try:
+                results[entry.name] = self.scan_content(content, entry.name,
+                                                        include_metadata=True, mode=mode,
+                                                        root=".")
+            except Exception as e:
+                results[entry.name] = [_panic_stub(f"{archive_path}:{entry.name}", e)]
         return results
 
+    def _scan_file_cached(
+        self,
+        file_str: str,
+        mode: str,
+        cache: Optional[ScanCache],
+        cache_content_hash: bool,
+        invalid_encoding: str = "warn",
+        root: Optional[str] = None
+    ) -> Optional[list[StructureNode]]:
+        """scan_file through the optional result cache. Never raises — a
+        file that can't be read becomes an unreadable stub, one that crashes
+        the scanner a logged panic error node, not a failed walk."""
+        try:
+            key = (scan_cache_key(file_str, mode, cache_content_hash)
+                   if cache is not None else None)
+            if key is not None and invalid_encoding != "warn":
+                key += (invalid_encoding,)
+            if key is not None:
+                key += (root,)  # symbol IDs are relative to it
+            cached = cache.get(key) if key is not None else None
+            if cached is not None:
+                return cached
+            structures = self.scan_file(file_str, mode=mode, invalid_encoding=invalid_encoding,
+                                        root=root)
+            if key is not None and structures is not None:
+                cache.put(key, structures)
+            return structures
+        except OSError as e:
+            return [_unreadable_stub(Path(file_str), e)]
+        except Exception as e:
+            return [_panic_stub(file_str, e)]
+
     def get_supported_extensions(self) -> list[str]:
         """Get list of all supported file extensions."""
         return self.registry.get_supported_extensions()
@@ -516,4 +1386,4 @@ class FileScanner:
 
 
 # For backward compatibility, export StructureNode
-__all__ = ["FileScanner", "StructureNode"]
+__all__ = ["FileScanner", "LimitExceeded", "ScanCancelled", "StructureNode"]
@@ -1,25 +1,151 @@
 """FastMCP server with file scanning tools."""
 
+import asyncio
+import inspect
 import json
 import os
 import re
+import threading
+from datetime import datetime
 from pathlib import Path
 from typing import Optional
 
-from fastmcp import FastMCP
+from fastmcp import Context, FastMCP
 from mcp.types import TextContent
 
+from . import __version__
 from .code_health import analyze_health
+from .code_blocks import DEFAULT_LANGUAGES as DEFAULT_BLOCK_LANGUAGES, format_code_blocks, scan_markdown
+from .content_hashes import collect_hashes, format_hashes, identical_files
+from .hotspots import DEFAULT_TOP, find_hotspots, format_hotspots
+from .review_brief import DEFAULT_MIN_COMPLEXITY, build_review_brief, format_review_brief
+from .symbol_counts import count_symbols as count_file_symbols, format_counts
 from .content_search import search_content, format_hits, find_leads
 from .delta import ScanMemory, apply_node_delta, format_age
+from .pagination import paginate
+from .scan_cache import LRUScanCache
 from .ref_diff import diff_against_ref
+from .result_schema import result_schema
+from .file_json import file_to_dict
+from .positions import SourcePositions
+from .source_text import check_encoding_mode
+from .findings import collect_findings
+from .lsp_symbols import document_symbols
+from .csv_export import format_csv
+from .sarif import format_sarif
+from .stable_json import dumps_stable
+from .scan_resources import (
+    INDEX_URI,
+    SCHEME as SCAN_SCHEME,
+    list_page as list_resource_page,
+    resolve_resource_path,
+)
+from .golang.apistub import render_api_stub as render_go_api_stub
+from .golang.assertions import find_type_assertions as find_go_type_assertions, format_type_assertions
+from .golang.concurrency import find_concurrency as find_go_concurrency, format_concurrency
+from .golang.constructions import find_constructions as find_go_constructions, format_constructions
+from .golang.calls import build_call_graph as build_go_call_graph, format_call_graph
+from .golang.comments import format_comments, scan_comments as scan_go_comments
+from .golang.constants import format_constants, list_constants as list_go_constants
+from .golang.globals import format_globals, list_globals as list_go_globals
+from .golang.defers import find_defers as find_go_defers, format_defers
+from .golang.deadcode import find_dead_code as find_go_dead_code, format_dead_code
+from .golang.diagram import build_class_diagram, format_mermaid
+from .golang.duplicates import (
+    DEFAULT_MIN_LINES as DEFAULT_DUPLICATE_LINES,
+    find_duplicates as find_go_duplicates,
+    format_duplicates,
+)
+from .golang.formatting import check_formatting as check_go_formatting, format_format_checks
+from .golang.hierarchy import build_type_hierarchy, format_type_hierarchy
+from .golang.imports import (
+    build_import_graph,
+    format_import_graph,
+    format_import_list,
+    import_list,
+)
+from .golang.literals import extract_strings as extract_go_strings, format_strings
+from .golang.interfaces import (
+    find_implementers as find_go_implementers,
+    format_implementers,
+    format_interfaces,
+    list_interfaces as list_go_interfaces,
+)
+from .golang.panics import find_panics as find_go_panics, format_panics
+from .golang.generate import (
+    find_generate_directives as find_go_generate_directives, format_generate_directives,
+)
+from .golang.inits import find_inits as find_go_inits, format_inits
+from .golang.pkginfo import format_package_info, package_info as go_package_info
+from .golang.regexes import find_regexes as find_go_regexes, format_regexes
+from .golang.shadowing import find_shadowing as find_go_shadowing, format_shadowing
+from .golang.structtags import find_struct_tags as find_go_struct_tags, format_struct_tags
+from .golang.summary import format_package_summary, summarize_package as summarize_go_package
+from .golang.surface import api_surface as go_api_surface, format_api_surface
+from .golang.testfuncs import format_tests, scan_tests as scan_go_tests
+from .golang.typedeps import format_type_dependencies, type_dependencies as find_go_type_dependencies
+from .golang.undocumented import find_undocumented as find_go_undocumented, format_undocumented
+from .golang.long_functions import (
+    DEFAULT_MIN_LINES, find_long_functions as find_go_long_functions, format_long_functions,
+)
+from .golang.wide import (
+    DEFAULT_MAX_FIELDS, DEFAULT_MAX_PARAMS, find_wide_signatures as find_go_wide_signatures,
+    format_wide_signatures,
+)
+from .golang.leaks import find_leaked_unexported as find_go_leaked_unexported, format_leaked_unexported
+from .golang.naming import format_naming_report, naming_report as go_naming_report
+from .golang.errorhandling import (
+    error_handling_report as go_error_handling_report,
+    format_error_handling,
+)
+from .golang.unchecked import find_unchecked_errors as find_go_unchecked_errors, format_unchecked_errors
+from .golang.syntax import GoFile, go_files_of_scan, load_go_files
 from .focus import format_focus
 from .formatter import TreeFormatter
 from .directory_formatter import DirectoryFormatter
 from .git_signals import collect_git_signals, file_churn, format_activity, recent_line_edits, repo_root
 from .connectivity import connectivity_tail
-from .scanner import FileScanner
-from .languages import StructureNode, is_unsupported_stub
+from .scanner import (
+    DEFAULT_ARCHIVE_MAX_BYTES,
+    DEFAULT_MAX_FILE_SIZE,
+    FileScanner,
+    LimitExceeded,
+    ScanCancelled,
+)
+from .package_groups import group_by_package as group_by_package_dirs
+from .path_style import check_path_style, root_of, style_paths
+from .redaction import (
+    check_redact_mode,
+    redact_code,
+    redact_structures,
+    redact_value,
+    single_quoted_strings,
+)
+from .references import add_references, check_reference_format, reference
+from .scan_config import find_config
+from .symbol_filter import (
+    KIND_NODE_TYPES,
+    check_kinds,
+    compile_name_pattern,
+    filter_exported,
+    filter_line_range,
+    filter_min_complexity,
+    filter_symbols,
+)
+from .verbosity import VERBOSITY_LEVELS, check_verbosity, select_fields, tree_options
+from .symbol_search import (
+    find_symbol as find_symbol_locations,
+    format_locations,
+    format_symbol_table,
+    symbol_table,
+)
+from .symbol_diff import diff_scans, format_symbol_diff
+from .symbol_index import SymbolIndex
+from .symbol_source import symbol_source as extract_symbol_source
+from .watch import ScanEvent, watch
+from .languages import (
+    SKIP_TOO_LARGE, StructureNode, is_unsupported_stub, skip_reason, skipped_files,
+)
 from .preview import preview_directory as preview_dir_func
 from .code_map import CodeMap
 from .consensus import DivergenceConfig, find_divergences, format_divergences
@@ -83,6 +209,42 @@ dir_formatter = DirectoryFormatter()
 # Session-scoped scan memory for delta mode — lives as long as the server
 scan_memory = ScanMemory()
 
+# Per-file parse results for directory scans, keyed by path+mtime+size —
+# repeated scans of a mostly unchanged tree only re-parse the edited files
+scan_cache = LRUScanCache()
+
+# Persistent symbol indexes by (root, respect_gitignore), loaded on first
+# find_symbol(use_index=True) — the two settings index different files
+_symbol_indexes: dict[tuple[str, bool], SymbolIndex] = {}
+_symbol_indexes_lock = threading.Lock()
+
+# Wall-clock limit for one scan_directory call; partial results past it
+_SCAN_TIMEOUT_SECONDS = float(os.environ.get("SCANTOOL_SCAN_TIMEOUT", "120"))
+
+# Defensive bounds for one directory scan of any tool on a server with broad
+# filesystem access (0 = off): files walked, bytes parsed, seconds per file
+_SCAN_LIMITS = {
+    "max_files": ("SCANTOOL_MAX_SCAN_FILES", int(os.environ.get("SCANTOOL_MAX_SCAN_FILES", "100000"))),
+    "max_total_bytes": ("SCANTOOL_MAX_SCAN_BYTES", int(os.environ.get("SCANTOOL_MAX_SCAN_BYTES", str(1 << 30)))),
+    "parse_timeout": ("SCANTOOL_PARSE_TIMEOUT", float(os.environ.get("SCANTOOL_PARSE_TIMEOUT", "30"))),
+}
+
+# Seconds one file may parse before it alone is skipped as timed_out and the
+# scan goes on (0 = off); below parse_timeout, so a stuck parse of untrusted
+# code costs one file, not the scan
+_FILE_TIMEOUT_SECONDS = float(os.environ.get("SCANTOOL_FILE_TIMEOUT", "10"))
+
+# Total decompressed bytes one scan_archive call may read into memory (0 = off)
+_ARCHIVE_MAX_BYTES = int(os.environ.get("SCANTOOL_MAX_ARCHIVE_BYTES", str(DEFAULT_ARCHIVE_MAX_BYTES)))
+
+# JSON output formats: "json-stable" is the canonical, diffable ordering
+_JSON_FORMATS = ("json", "json-stable")
+# Formats for programs: never shortened to a delta or an "unchanged" line
+_STRUCTURED_FORMATS = (*_JSON_FORMATS, "lsp")
+
+# Directory that scan:// resource paths are relative to (default: cwd)
+_RESOURCE_ROOT = Path(os.environ.get("SCANTOOL_RESOURCE_ROOT", ".")).resolve()
+
 
 def _git_activity_section(directory: str) -> str:
     """Git activity for preview output; "" outside git repos (signals are
@@ -93,6 +255,14 @@ def _git_activity_section(directory: str) -> str:
     return format_activity(signals)
 
 
+def _redact_mode(path: str, redact_strings: Optional[str]) -> str:
+    """redact_strings as passed, else the project config's, else "off"."""
+    mode = find_config(path).apply({"redact_strings": redact_strings},
+                                   defaults={"redact_strings": "off"})["redact_strings"]
+    check_redact_mode(mode)
+    return mode
+
+
 def _connectivity_note(file_path: str) -> str:
     """Self-levelling connectivity tail for a scanned file (server layer): candidate
     dead/orphan/drift across the whole corpus, silent when clean. Never raises —
@@ -374,6 +544,12 @@ def scan_file_content(
     show_decorators: bool = True,
     show_docstrings: bool = True,
     show_complexity: bool = False,
+    budget: Optional[int] = None,
+    kinds: Optional[list[str]] = None,
+    name_pattern: Optional[str] = None,
+    qualified_names: bool = False,
+    verbosity: str = "full",
+    redact_strings: str = "off",
     output_format: str = "tree"
 ) -> list[TextContent]:
     """
@@ -387,8 +563,10 @@ def scan_file_content(
     **Recommended for:** HTTP/remote connections, GitHub files, API responses, web content
 
     Use this when you have file content from remote sources (e.g., GitHub API,
-    URLs, or any content not stored locally). The filename parameter is used
-    only to determine the language/file type for parsing.
+    URLs, or any content not stored locally) or an editor buffer that isn't
+    saved yet. The filename parameter is used only to determine the
+    language/file type for parsing. Structure and skeletons match scan_file()
+    on the same bytes; only the file-info line has no timestamps.
 
     More efficient than saving to disk first - directly scans provided content.
 
@@ -399,12 +577,27 @@ def scan_file_content(
         Common:
             content: The file content as a string
             filename: Filename (with extension) to determine parser type
+        Cost & slicing:
+            budget: Approximate token cap for skeleton content, as in
+                scan_file (default: None = no cap)
         Semantics & display:
             show_signatures: Include function signatures with types (default: True)
             show_decorators: Include decorators like @property, @staticmethod (default: True)
             show_docstrings: Include first line of docstrings (default: True)
             show_complexity: Show complexity metrics for long/complex functions (default: False)
-            output_format: Output format - "tree" or "json" (default: "tree")
+            kinds: Only these symbol kinds, as in scan_file (default: None = all)
+            name_pattern: Only symbols whose name this regex matches, as in
+                scan_file (default: None = all)
+            qualified_names: Match methods as Type.Method (default: False)
+            verbosity: Fields returned — "names" (kind and name only: no
+                positions, signatures or docs), "signatures" (+ positions,
+                signatures, modifiers) or "full" (default: "full")
+            redact_strings: String literal values in the output — "off",
+                "length" or "hash", as in scan_file (default: "off")
+            output_format: Output format - "tree", "json", "json-stable"
+                (sorted keys and nodes, for snapshot diffs) or "lsp" (an LSP
+                DocumentSymbol[] as textDocument/documentSymbol returns it)
+                (default: "tree")
 
     Returns:
         Formatted structure output (tree or JSON)
@@ -417,10 +610,15 @@ def scan_file_content(
         )
     """
     try:
+        check_verbosity(verbosity)
+        check_kinds(kinds)
+        names = compile_name_pattern(name_pattern)
+        check_redact_mode(redact_strings)
         structures = scanner.scan_content(
             content=content,
             filename=filename,
-            include_metadata=True
+            include_metadata=True,
+            budget=budget
         )
 
         if structures is None:
@@ -433,20 +631,32 @@ def scan_file_content(
         if not structures:
             return [TextContent(type="text", text=f"{filename} (empty file or no structure found)")]
 
+        if kinds or names:
+            structures = filter_symbols(structures, kinds, names, qualified_names)
+        structures = redact_structures(structures, redact_strings, filename)
+
         # Format output
-        if output_format == "json":
-            return [TextContent(type="text", text=_structures_to_json(structures, filename))]
+        if output_format == "lsp":
+            return [TextContent(type="text", text=json.dumps(
+                document_symbols(structures, content.encode("utf-8")), indent=2))]
+        if output_format in _JSON_FORMATS:
+            return [TextContent(type="text", text=_dump_json(select_fields(
+                _structures_to_json(structures, filename, return_dict=True), verbosity),
+                output_format))]
         else:
             # Use custom formatter with options
-            custom_formatter = TreeFormatter(
+            custom_formatter = TreeFormatter(**tree_options(
+                verbosity,
                 show_signatures=show_signatures,
                 show_decorators=show_decorators,
                 show_docstrings=show_docstrings,
                 show_complexity=show_complexity
-            )
+            ))
             result = custom_formatter.format(filename, structures)
             return [TextContent(type="text", text=result)]
 
+    except ValueError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
     except Exception as e:
         return [TextContent(type="text", text=f"Error scanning content: {e}")]
 
@@ -467,6 +677,18 @@ def scan_file(
     depth: Optional[str] = None,
     delta: bool = True,
     mode: str = "balanced",
+    exported_only: bool = False,
+    min_complexity: Optional[int] = None,
+    start_line: Optional[int] = None,
+    end_line: Optional[int] = None,
+    kinds: Optional[list[str]] = None,
+    name_pattern: Optional[str] = None,
+    qualified_names: bool = False,
+    verbosity: Optional[str] = None,
+    redact_strings: Optional[str] = None,
+    reference_format: str = "none",
+    positions: bool = False,
+    invalid_encoding: str = "warn",
     output_format: str = "tree"
 ) -> list[TextContent]:
     """
@@ -515,11 +737,40 @@ def scan_file(
                 the same file in this session: unchanged file → one line;
                 modified file → full structure but code detail only for new or
                 changed functions ([new]/[changed] labels, removed ones listed).
-                First scan is always full. Pass delta=False for full output
-                (default: True)
+                First scan is always full. Pass delta=False for full output.
+                Off for filtered reads (exported_only, min_complexity,
+                start_line/end_line, kinds, name_pattern) (default: True)
+            start_line / end_line: Only symbols whose declaration range
+                overlaps this 1-based, inclusive window — for a region of a
+                huge file. Overlap, not containment: a function starting
+                above start_line whose body reaches into the window is
+                included, as is its enclosing class. Either bound may be
+                omitted. The whole file is still parsed (default: None)
         Semantics & display:
             mode: Saliency weight profile — "balanced" (default) or "active"
                 (weights actively-edited code higher in skeleton selection)
+            exported_only: Only the file's public API — exported declarations
+                and exported members of exported types, judged by the
+                language's own visibility rules (Go capitalization incl. the
+                receiver type, Python leading underscores, public/pub/export
+                keywords elsewhere). Imports are dropped (default: False)
+            min_complexity: Only functions/methods with cyclomatic complexity
+                >= this (1 + if/for/case/&&/||; closures count toward their
+                enclosing function). Exact for Go, branches+1 elsewhere.
+                Containers stay as context for kept members (default: None)
+            kinds: Only these symbol kinds — any of "function", "method",
+                "type" (struct/class/enum/alias/typedef), "interface" (incl.
+                traits/protocols), "const", "var", "import", "macro"
+                (C/C++ #define), "key" (JSON/YAML/TOML). Containers
+                stay as context for kept members (default: None = all)
+            name_pattern: Only symbols whose name this regex matches —
+                searched, so anchor it: "^Handle", "Test$". Methods match
+                by their bare name; containers stay as context for kept
+                members, and with kinds both must hold. An invalid regex
+                is an error (default: None = all)
+            qualified_names: Match methods against name_pattern as
+                Type.Method (Go receiver or enclosing class), e.g.
+                "^Server\\." (default: False)
             condense: Show code as condensed method skeletons (pseudocode without
                 line numbers) — every function gets a shallow depth-2 outline, the
                 most salient get full depth (default: True; set False for verbatim
@@ -528,7 +779,37 @@ def scan_file(
             show_decorators: Include decorators like @property, @staticmethod (default: True)
             show_docstrings: Include first line of docstrings (default: True)
             show_complexity: Show complexity metrics for long/complex functions (default: False)
-            output_format: Output format - "tree" or "json" (default: "tree")
+            verbosity: Fields returned — "names" (kind and name only: no
+                positions, signatures, docs or code), "signatures"
+                (+ positions, signatures, modifiers) or "full". An output
+                filter only; focus= reads ignore it (default: the
+                verbosity of a .scannerrc at or above the file, else "full")
+            redact_strings: String literal values in signatures,
+                decorators, skeletons, excerpts and focus= reads — "off",
+                "length" (<string:N>) or "hash" (<string:#1a2b3c4d>, equal
+                values stay equal), for code with secrets or personal data
+                (default: the redact_strings of a .scannerrc, else "off")
+            reference_format: A clickable "ref" on every JSON node and
+                parse error — "plain" (path:line:col, the path as given) or
+                "vscode" (vscode://file/<absolute path>:line:col), columns
+                1-based (default: "none")
+            positions: JSON nodes carry "positions": go/token-style
+                {filename, offset, line, column} start/end pairs for the
+                declaration ("range"), its identifier ("name_range") and its
+                body ("body_range"); offsets are bytes in the file on disk.
+                Languages without byte offsets give whole-line ranges only
+                (default: False)
+            invalid_encoding: A file that isn't valid UTF-8 — "warn" parses
+                it anyway (the grammars tolerate stray bytes; text cut from
+                them shows U+FFFD) with an "encoding_warning" naming the
+                count and the first bad byte, in the file-info line and the
+                JSON; "skip" returns it unparsed with skipped =
+                "invalid_encoding" (default: "warn")
+            output_format: Output format - "tree", "json", "json-stable"
+                (sorted keys and nodes, for snapshot diffs) or "lsp" (an LSP
+                DocumentSymbol[] as textDocument/documentSymbol returns it:
+                SymbolKind numbers, 0-based UTF-16 ranges, selectionRange on
+                the name, Go methods nested under their type) (default: "tree")
 
     Returns:
         Formatted structure output (tree or JSON)
@@ -550,15 +831,35 @@ def scan_file(
         - validate_email (email: str) -> bool @48 # Validate email format
     """
     try:
+        options = find_config(file_path).apply(
+            {"verbosity": verbosity, "redact_strings": redact_strings},
+            defaults={"verbosity": "full", "redact_strings": "off"})
+        verbosity, redact_strings = options["verbosity"], options["redact_strings"]
+        check_verbosity(verbosity)
+        check_redact_mode(redact_strings)
+        check_reference_format(reference_format)
+        check_kinds(kinds)
+        names = compile_name_pattern(name_pattern)
+        check_encoding_mode(invalid_encoding)
+        if start_line is not None and end_line is not None and start_line > end_line:
+            return [TextContent(type="text", text=(
+                f"Error: start_line ({start_line}) is after end_line ({end_line})"))]
+
         # depth is an alias carried over from preview_directory; map it to the
         # native cost lever. Explicit budget always wins; "deep" == full (None).
         if budget is None and depth is not None:
             budget = {"quick": 300, "normal": 1500, "deep": None}.get(depth)
 
+        # Filtered reads are lookups, not structure diffs: an "unchanged"
+        # answer would hide the window or subset that was asked for
+        if (exported_only or min_complexity is not None or start_line is not None
+                or end_line is not None or kinds or names is not None):
+            delta = False
+
         # Delta: unchanged since this session's previous scan → one line.
         # Focused reads bypass delta entirely — they request content, not
         # structure changes
-        if delta and focus is None and output_format != "json":
+        if delta and focus is None and output_format not in _STRUCTURED_FORMATS:
             age = scan_memory.file_unchanged(file_path)
             if age is not None:
                 return [TextContent(type="text", text=(
@@ -574,7 +875,8 @@ def scan_file(
         line_edits = recent_line_edits(file_path) if churn else None
 
         structures = scanner.scan_file(file_path, budget=budget,
-                                       line_edits=line_edits, mode=mode)
+                                       line_edits=line_edits, mode=mode,
+                                       invalid_encoding=invalid_encoding)
 
         if structures is None:
             supported = ", ".join(scanner.get_supported_extensions())
@@ -585,14 +887,16 @@ def scan_file(
 
         if churn and structures[0].type == "file-info" and structures[0].file_metadata is not None:
             structures[0].file_metadata["churn_90d"] = churn
+        structures = redact_structures(structures, redact_strings, file_path)
 
         if focus is not None:
-            source_lines = Path(file_path).read_text(errors="replace").split("\n")
+            source_lines = redact_code(Path(file_path).read_text(errors="replace"), redact_strings,
+                                       single_quoted_strings(file_path)).split("\n")
             return [TextContent(type="text", text=format_focus(
                 file_path, structures, source_lines, focus))]
 
         delta_note = ""
-        if delta and output_format != "json":
+        if delta and output_format not in _STRUCTURED_FORMATS:
             source_lines = Path(file_path).read_text(errors="replace").split("\n")
             diff = scan_memory.diff_and_record(file_path, structures, source_lines)
             if diff is not None:
@@ -603,23 +907,42 @@ def scan_file(
                     f"{unchanged} unchanged — code detail only for changed"
                     f"{removed}; delta=False for everything)\n")
 
+        if exported_only:
+            language = scanner.registry.get(Path(file_path).suffix.lower())
+            if language is not None:
+                structures = filter_exported(structures, language)
+        if min_complexity is not None:
+            structures = filter_min_complexity(structures, min_complexity)
+        if start_line is not None or end_line is not None:
+            structures = filter_line_range(structures, start_line, end_line)
+        if kinds or names:
+            structures = filter_symbols(structures, kinds, names, qualified_names)
+
         # Format output
-        if output_format == "json":
-            return [TextContent(type="text", text=json.dumps(_structures_to_json(structures, file_path), indent=2))]
+        if output_format == "lsp":
+            return [TextContent(type="text", text=json.dumps(
+                document_symbols(structures, Path(file_path).read_bytes()), indent=2))]
+        if output_format in _JSON_FORMATS:
+            return [TextContent(type="text", text=_dump_json(select_fields(add_references(
+                _structures_to_json(structures, file_path, return_dict=True,
+                                    positions=SourcePositions(Path(file_path).read_bytes(),
+                                                              file_path) if positions else None),
+                reference_format), verbosity), output_format))]
         else:
             # Use custom formatter with options
-            custom_formatter = TreeFormatter(
+            custom_formatter = TreeFormatter(**tree_options(
+                verbosity,
                 show_signatures=show_signatures,
                 show_decorators=show_decorators,
                 show_docstrings=show_docstrings,
                 show_complexity=show_complexity,
                 condense=condense
-            )
+            ))
             result = delta_note + custom_formatter.format(file_path, structures)
             result += _connectivity_note(file_path)
             return [TextContent(type="text", text=result)]
 
-    except FileNotFoundError as e:
+    except (FileNotFoundError, ValueError) as e:
         return [TextContent(type="text", text=f"Error: {e}")]
     except Exception as e:
         return [TextContent(type="text", text=f"Error scanning file: {e}")]
@@ -633,12 +956,38 @@ def scan_directory(
     directory: str,
     pattern: str = "**/*",
     max_files: Optional[int] = None,
-    respect_gitignore: bool = True,
+    respect_gitignore: Optional[bool] = None,
     exclude_patterns: Optional[list[str]] = None,
+    skip_dirs: Optional[list[str]] = None,
     delta: bool = True,
     mode: str = "balanced",
     depth: Optional[str] = None,
-    output_format: str = "tree"
+    output_format: str = "tree",
+    timeout: Optional[float] = None,
+    git_diff_base: Optional[str] = None,
+    max_file_size: Optional[int] = None,
+    exclude_generated: bool = False,
+    include: Optional[list[str]] = None,
+    exclude: Optional[list[str]] = None,
+    build_tags: Optional[list[str]] = None,
+    follow_symlinks: bool = False,
+    confine_to_root: bool = False,
+    limit: Optional[int] = None,
+    cursor: Optional[str] = None,
+    kinds: Optional[list[str]] = None,
+    name_pattern: Optional[str] = None,
+    qualified_names: bool = False,
+    verbosity: Optional[str] = None,
+    redact_strings: Optional[str] = None,
+    path_style: str = "absolute",
+    reference_format: str = "none",
+    group_by_package: bool = False,
+    max_depth: Optional[int] = None,
+    report_depth_limit: bool = False,
+    modified_since: Optional[str] = None,
+    roots: Optional[list[str]] = None,
+    positions: bool = False,
+    invalid_encoding: str = "warn"
 ) -> list[TextContent]:
     """
     Scan directory and show compact overview of all file structures (code, docs, markdown, config, text).
@@ -682,18 +1031,90 @@ def scan_directory(
 
     Respects .gitignore by default (excludes node_modules, .venv, etc.)
 
+    Project defaults: a .scannerrc (or scanner.toml) TOML file at or above
+    directory sets respect_gitignore, skip_dirs, max_file_size, verbosity,
+    redact_strings and the number of parallel file scans (workers). Arguments passed here
+    win over the file, the file wins over the built-in defaults; a
+    malformed file is an error, not ignored.
+
     Args (tiered — most calls need only Common):
         Common:
             directory: Directory path to scan
             pattern: Glob pattern (default: "**/*" = recursive all files)
+            roots: More directories scanned together with directory as one
+                result — a service and a shared library that have no common
+                parent. max_files, the server limits and timeout count the
+                combined files; paths are relative to their own root, and a
+                relative path two roots both have is prefixed with the
+                root's name ("lib/config.go"). The tree shows one tree per
+                root, CODE HEALTH covers them all; project config comes
+                from directory (default: None = directory only)
         Cost & slicing:
             max_files: Maximum files to process (default: None = unlimited)
-            respect_gitignore: Respect .gitignore exclusions (default: True)
+            limit: Page size in files — the result is paged in sorted path
+                order and ends with a next_cursor while files remain
+                (JSON: {files, offset, total_files, next_cursor}). The CODE
+                HEALTH section comes with the last page (default: None =
+                one response)
+            cursor: next_cursor of the previous page, with the same limit
+                and scan arguments. Valid while the file set is unchanged;
+                a page after files were added or removed says so
+                (default: None = first page)
+            respect_gitignore: Respect .gitignore exclusions (default:
+                project config, else True)
             exclude_patterns: Additional patterns to exclude (gitignore syntax)
+            include: Doublestar globs relative to directory — only matching
+                files are scanned, e.g. ["**/*_test.go"] (default: None = all)
+            exclude: Doublestar globs to leave out, e.g. ["internal/**"];
+                beats include, and adds to gitignore/exclude_patterns and
+                the directory's .scanignore (default: None)
+            skip_dirs: Directory names never descended into; replaces the
+                built-in noise list (hidden dirs, node_modules, vendor, build
+                output, caches). Pass e.g. ["node_modules"] to include vendor/
+                (default: None = project config, else built-in list)
             delta: Re-scans aggregate files unchanged since YOUR previous scan
                 in this session to a single line — full detail only for changed
                 or new files. The CODE HEALTH section always covers everything.
-                Pass delta=False for full output (default: True)
+                Pass delta=False for full output. Off when kinds or
+                name_pattern is set (default: True)
+            timeout: Seconds before the scan stops and returns what it has,
+                with a note (default: SCANTOOL_SCAN_TIMEOUT, 120)
+                The server also caps files walked, bytes parsed and
+                seconds per file (SCANTOOL_MAX_SCAN_FILES 100000,
+                SCANTOOL_MAX_SCAN_BYTES 1 GiB, SCANTOOL_PARSE_TIMEOUT 30) —
+                hitting one returns partial results the same way
+            git_diff_base: Only files changed against this git ref
+                ("main", "origin/main", "HEAD~3") — working tree incl.
+                untracked files; deleted files skipped, renamed ones scanned
+                at their new path. Errors outside a git repo (default: None)
+            modified_since: Only files modified after this RFC 3339 time,
+                e.g. "2024-05-01T09:00:00Z" (no offset = server local
+                time). Directories are still walked; older files are left
+                out entirely. With limit, a quick "what's new" view
+                (default: None = all files)
+            max_file_size: Supported files above this many bytes are listed
+                but not parsed (giant generated files); 0 = no limit
+                (default: None = project config, else 5 MB). Skipped files
+                are summarized at the end
+            exclude_generated: Leave out generated files (Go "// Code
+                generated ... DO NOT EDIT." header) so counts and health
+                cover hand-written code only (default: False)
+            build_tags: Leave out Go files whose //go:build (or // +build)
+                constraint these tags don't satisfy — the complete tag set,
+                GOOS/GOARCH included: ["linux", "amd64"]. Constraints are
+                reported per file either way (default: None = no filtering)
+            follow_symlinks: Descend into symlinked directories; each
+                directory and file is visited once, so link cycles and two
+                links to one directory are safe (default: False)
+            confine_to_root: Error on a symlink (file, or followed directory)
+                that resolves outside directory (default: False)
+            max_depth: Subdirectory levels to descend — 0 = files directly
+                in directory only, 1 = plus one level, ... Deeper
+                directories are not walked at all, for a quick shallow
+                overview of a huge tree (default: None = unlimited)
+            report_depth_limit: List the directories max_depth did not
+                enter in the skipped note (JSON: skipped "max_depth")
+                instead of leaving them out silently (default: False)
         Semantics & display:
             mode: Saliency weight profile for the per-file glimpse lines —
                 "balanced" (default) or "active" (weights actively-edited
@@ -702,7 +1123,53 @@ def scan_directory(
                 bird's-eye tier, so there is no depth axis to set. Passing it
                 triggers a one-line usage hint pointing at the right lever
                 (pattern for breadth; scan_file/preview_directory for depth)
-            output_format: "tree" or "json" (default: "tree")
+            output_format: "tree", "json", "json-stable", "sarif" or "csv"
+                (default: "tree"). "json-stable" sorts keys, paths and nodes (start
+                line, name) for snapshot diffs and hashing. "sarif" emits SARIF 2.1.0 findings (high cyclomatic
+                complexity, parse errors) for GitHub code scanning / CI,
+                with paths relative to directory. "csv" is one RFC 4180
+                row per symbol for spreadsheets — path, kind, name,
+                parent, line, end_line, signature, complexity, exported,
+                has_doc, doc — paths as path_style says; a page of it
+                carries no cursor, so export without limit
+            kinds: Only these symbol kinds per file, as in scan_file; the
+                CODE HEALTH section still covers every symbol
+                (default: None = all)
+            name_pattern: Only symbols whose name this regex matches, as in
+                scan_file — a lightweight symbol search over the tree
+                (default: None = all)
+            qualified_names: Match methods as Type.Method (default: False)
+            verbosity: Fields per node in JSON output — "names",
+                "signatures" or "full", as in scan_file. The tree is
+                already the compact inline view (default: project config,
+                else "full")
+            redact_strings: String literal values in signatures, glimpse
+                lines and skeletons — "off", "length" or "hash", as in
+                scan_file (default: project config, else "off")
+            path_style: Paths in JSON output (keys, "file", parse_errors):
+                "absolute" for opening in an editor, or "relative" to
+                directory with "/" separators on every OS, for stable
+                diffs and fixtures. The tree is relative either way
+                (default: "absolute")
+            reference_format: A clickable "ref" on every JSON node and
+                parse error — "plain" (path:line:col, path as path_style
+                shows it) or "vscode" (vscode://file/<absolute path>:line:col,
+                absolute whatever path_style says) (default: "none")
+            positions: JSON nodes carry go/token-style "positions" ranges,
+                filename as path_style shows it — see scan_file
+                (default: False)
+            invalid_encoding: Files that aren't valid UTF-8 are parsed with
+                an "encoding_warning" ("warn") or listed unparsed with
+                skipped = "invalid_encoding" ("skip") — see scan_file
+                (default: "warn")
+            group_by_package: Organize results by (directory, package) —
+                JSON: {"packages": [{dir, package, files}]} in directory
+                order, with files keyed as usual (paged: "packages" in
+                place of "files"). A directory with a package and its
+                external _test package gives two groups; files declaring
+                no package are their directory's group with package null.
+                The tree labels directories with their packages
+                (default: False)
 
     Returns:
         Hierarchical tree with compact inline structures
@@ -716,8 +1183,31 @@ def scan_directory(
 
         # Shallow scan (1 level)
         scan_directory(".", pattern="*/*")
+
+        # Large tree in pages of 200 files
+        scan_directory(".", limit=200)  # then cursor=<next_cursor>
     """
     try:
+        options = find_config(directory).apply(
+            {"respect_gitignore": respect_gitignore, "skip_dirs": skip_dirs,
+             "max_file_size": max_file_size, "verbosity": verbosity,
+             "redact_strings": redact_strings, "workers": None},
+            defaults={"respect_gitignore": True, "max_file_size": DEFAULT_MAX_FILE_SIZE,
+                      "verbosity": "full", "redact_strings": "off"})
+        respect_gitignore, skip_dirs, verbosity, redact_strings = (
+            options["respect_gitignore"], options["skip_dirs"], options["verbosity"],
+            options["redact_strings"])
+        check_verbosity(verbosity)
+        check_redact_mode(redact_strings)
+        check_kinds(kinds)
+        names = compile_name_pattern(name_pattern)
+        check_path_style(path_style)
+        check_encoding_mode(invalid_encoding)
+        check_reference_format(reference_format)
+        since = _parse_timestamp("modified_since", modified_since) \
+            if modified_since is not None else None
+        if cursor is not None and limit is None:
+            raise ValueError("cursor needs the limit of the scan that issued it")
         # depth has no analog here — scan_directory is already the shallow tier.
         # Accept it (no crash) but flag it as non-optimal tool use, in-loop.
         depth_note = ""
@@ -729,16 +1219,44 @@ def scan_directory(
                 "one level); for deeper per-file detail use scan_file(budget=) "
                 "or preview_directory(depth=).\n\n")
 
-        results = scanner.scan_directory(
-            directory=directory,
-            pattern=pattern,
-            respect_gitignore=respect_gitignore,
-            exclude_patterns=exclude_patterns,
-            mode=mode
-        )
+        try:
+            results = _limited_scan(
+                directory,
+                pattern,
+                timeout=timeout,
+                respect_gitignore=respect_gitignore,
+                exclude_patterns=exclude_patterns,
+                mode=mode,
+                skip_dirs=skip_dirs,
+                git_diff_base=git_diff_base,
+                max_file_size=options["max_file_size"] or None,
+                workers=options["workers"],
+                exclude_generated=exclude_generated,
+                include=include,
+                exclude=exclude,
+                build_tags=build_tags,
+                follow_symlinks=follow_symlinks,
+                confine_to_root=confine_to_root,
+                max_depth=max_depth,
+                report_depth_limit=report_depth_limit,
+                modified_since=since,
+                roots=roots,
+                invalid_encoding=invalid_encoding,
+            )
+        except ScanCancelled as e:
+            # Partial results beat none: keep what finished, say so up front
+            results = e.results
+            remedy = _limit_remedy(e) if isinstance(e, LimitExceeded) else "timeout"
+            depth_note = (
+                f"Note: scan {e.reason} — partial results ({len(results)} files). "
+                f"Narrow pattern or raise {remedy} for a complete scan.\n\n") + depth_note
 
         if not results:
-            return [TextContent(type="text", text=depth_note + f"No supported files found in {directory} matching {pattern}")]
+            changed = f" changed since {git_diff_base}" if git_diff_base else ""
+            if modified_since is not None:
+                changed += f" modified since {modified_since}"
+            scope = ", ".join([directory, *(roots or [])])
+            return [TextContent(type="text", text=depth_note + f"No supported files{changed} found in {scope} matching {pattern}")]
 
         # Apply max_files limit if specified
         if max_files is not None and len(results) > max_files:
@@ -748,19 +1266,72 @@ def scan_directory(
         else:
             warning = depth_note
 
-        if output_format == "json":
-            json_results = {}
+        page = None
+        if limit is not None:
+            page = paginate(results, limit, cursor)
+            if page.stale:
+                warning += ("Note: files were added or removed since this cursor was "
+                            "issued — pages may skip or repeat files; restart without "
+                            "cursor for a consistent listing\n\n")
+            all_results, results = results, page.results
+        if redact_strings != "off":
+            results = {path: redact_structures(structures, redact_strings, path)
+                       for path, structures in results.items()}
+
+        if output_format == "sarif":
+            # Machine-consumed: no notes in front of the JSON document
+            return [TextContent(type="text", text=format_sarif(collect_findings(results), directory))]
+
+        all_roots = [str(Path(d).resolve()) for d in [directory, *(roots or [])]]
+        if output_format == "csv":
+            # A document to save and open like sarif: no notes in front of the header row
+            if kinds or names:
+                results = {path: filter_symbols(structures, kinds, names, qualified_names)
+                           if structures else structures for path, structures in results.items()}
+            styled = style_paths(results, all_roots, path_style)
+            return [TextContent(type="text", text=format_csv(results, styled.__getitem__))]
+
+        if output_format in _JSON_FORMATS:
+            json_results, json_keys = {}, {}
+            root = all_roots[0]
+            styled = style_paths(results, all_roots, path_style)
             for file_path, structures in results.items():
+                if structures and (kinds or names):
+                    structures = filter_symbols(structures, kinds, names, qualified_names)
                 if structures:
-                    json_results[file_path] = _structures_to_json(structures, file_path, return_dict=True)
-            return [TextContent(type="text", text=warning + json.dumps(json_results, indent=2))]
+                    key = json_keys[file_path] = styled[file_path]
+                    json_results[key] = select_fields(add_references(
+                        _structures_to_json(
+                            structures, key, return_dict=True,
+                            positions=_source_positions(structures, file_path, key)
+                            if positions else None),
+                        reference_format, file_path), verbosity)
+            listing = "files"
+            if group_by_package:
+                listing = "packages"
+                json_results = [
+                    {"dir": group.dir, "package": group.package,
+                     "files": {json_keys[p]: json_results[json_keys[p]] for p in group.files}}
+                    for group in group_by_package_dirs(
+                        {p: s for p, s in results.items() if p in json_keys}, root)]
+            if page is not None:
+                json_results = {listing: json_results, "offset": page.offset,
+                                "total_files": page.total, "next_cursor": page.next_cursor}
+            elif group_by_package:
+                json_results = {"packages": json_results}
+            return [TextContent(type="text", text=warning + _dump_json(json_results, output_format))]
         else:
-            _annotate_churn(results, directory)
+            for root in all_roots:
+                _annotate_churn({p: s for p, s in results.items()
+                                 if root_of(p, all_roots) == root}, root)
 
             # Delta: files unchanged since this session's previous scan are
             # aggregated to one line; full detail only for changed/new files.
             # Health runs on the FULL set regardless — "unreferenced" must
-            # see references living in unchanged files.
+            # see references living in unchanged files. A kinds or
+            # name_pattern query is a lookup, not a structure diff: no delta.
+            if kinds or names is not None:
+                delta = False
             unchanged_paths = []
             display_results = results
             if delta:
@@ -776,33 +1347,223 @@ def scan_directory(
                 if unchanged_paths:
                     display_results = {p: s for p, s in results.items()
                                        if p not in set(unchanged_paths)}
+            if kinds or names is not None:
+                display_results = {p: filter_symbols(s, kinds, names, qualified_names) if s else s
+                                   for p, s in display_results.items()}
+
+            page_note = ""
+            if page is not None:
+                page_note = (f"\npage: files {page.offset + 1}-{page.offset + len(results)} "
+                             f"of {page.total}")
+                if page.next_cursor:
+                    page_note += f" — next: cursor=\"{page.next_cursor}\""
 
             if delta and not display_results:
-                names = ", ".join(sorted(Path(p).name for p in unchanged_paths))
+                unchanged_names = ", ".join(sorted(Path(p).name for p in unchanged_paths))
                 return [TextContent(type="text", text=depth_note + (
                     f"{directory}: all {len(unchanged_paths)} files unchanged "
-                    f"since last scan in this session ({names}) — "
-                    f"delta=False for full output"))]
+                    f"since last scan in this session ({unchanged_names}) — "
+                    f"delta=False for full output") + page_note)]
 
             # ALWAYS use compact inline format for directory scans
             custom_formatter = DirectoryFormatter(
                 include_structures=True,
-                flatten_structures=True  # Always flat for directory overview
+                flatten_structures=True,  # Always flat for directory overview
+                show_packages=group_by_package
             )
-            result = warning + custom_formatter.format(directory, display_results)
+            if roots:
+                by_root: dict[str, dict] = {root: {} for root in all_roots}
+                for path, structures in display_results.items():
+                    by_root[root_of(path, all_roots)][path] = structures
+                result = warning + "\n\n".join(custom_formatter.format(root, files)
+                                                for root, files in by_root.items() if files)
+            else:
+                result = warning + custom_formatter.format(directory, display_results)
             if unchanged_paths:
-                names = ", ".join(sorted(Path(p).name for p in unchanged_paths))
+                unchanged_names = ", ".join(sorted(Path(p).name for p in unchanged_paths))
                 result += (f"\nunchanged since last scan ({len(unchanged_paths)} "
-                           f"files): {names} (delta=False for everything)")
-            result += analyze_health(results)
+                           f"files): {unchanged_names} (delta=False for everything)")
+            result += _skipped_note(results)
+            result += page_note
+            if page is None:
+                result += analyze_health(results)
+            elif page.next_cursor is None:
+                result += analyze_health(all_results)
             return [TextContent(type="text", text=result)]
 
-    except FileNotFoundError as e:
+    except (FileNotFoundError, ValueError) as e:
         return [TextContent(type="text", text=f"Error: {e}")]
     except Exception as e:
         return [TextContent(type="text", text=f"Error scanning directory: {e}")]
 
 
+@mcp.tool(
+    tags={"local", "directory", "watch"},
+    description="Wait for files under a directory to be created, modified or deleted and return each change with the file's fresh scan - every change is also pushed to the client as a log notification as it settles"
+)
+async def watch_directory(
+    directory: str,
+    pattern: str = "**/*",
+    timeout: float = 30.0,
+    max_events: int = 20,
+    respect_gitignore: bool = True,
+    ctx: Optional[Context] = None,
+) -> list[TextContent]:
+    """
+    Watch a directory for changes and report them as they settle.
+
+    **When to use this vs other tools:**
+    - Use watch_directory() in a long session to learn what someone else
+      edited, instead of re-scanning the whole tree on a timer
+    - Use scan_diff() for what changed against a git ref
+
+    Files present when the call starts are the baseline. Rapid successive
+    writes (editors save twice) are debounced into one event. Each settled
+    change is sent right away as a log notification ("modified
+    src/app.py"); the call returns after max_events changes or timeout
+    seconds, whichever comes first, with every change and the fresh scan
+    of each created or modified file. Files over scan_directory's size limit
+    and binary files come back as skipped stubs, as in scan_directory.
+
+    Args (tiered — most calls need only Common):
+        Common:
+            directory: Directory to watch
+            timeout: Seconds to wait for changes (default: 30)
+        Filtering:
+            pattern: Glob pattern, as for scan_directory (default: "**/*")
+            respect_gitignore: Skip .gitignore'd files (default: True)
+        Cost & slicing:
+            max_events: Return after this many changes (default: 20)
+
+    Returns:
+        One block per change: kind and path, then the file's structure
+        (nothing for a deletion); "No changes" when the timeout passed
+    """
+    try:
+        if not Path(directory).is_dir():
+            raise FileNotFoundError(f"Directory not found: {directory}")
+        if timeout <= 0 or max_events < 1:
+            raise ValueError("timeout must be positive and max_events at least 1")
+        loop = asyncio.get_running_loop()
+        queue: asyncio.Queue = asyncio.Queue()
+        cancel = threading.Event()
+
+        def run():
+            try:
+                for event in watch(directory, pattern, cancel=cancel,
+                                   respect_gitignore=respect_gitignore, scanner=scanner):
+                    loop.call_soon_threadsafe(queue.put_nowait, event)
+            except Exception as e:
+                loop.call_soon_threadsafe(queue.put_nowait, e)
+
+        watcher = threading.Thread(target=run, daemon=True)
+        watcher.start()
+        events: list[ScanEvent] = []
+        deadline = loop.time() + timeout
+        try:
+            while len(events) < max_events:
+                try:
+                    item = await asyncio.wait_for(queue.get(), max(0.0, deadline - loop.time()))
+                except asyncio.TimeoutError:
+                    break
+                if isinstance(item, Exception):
+                    raise item
+                events.append(item)
+                if ctx is not None:
+                    await ctx.info(f"{item.kind} {item.path}")
+        finally:
+            cancel.set()
+        return [TextContent(type="text", text=_format_events(events, directory, timeout))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error watching directory: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "archive", "exploration"},
+    description="Scan a .zip / .tar.gz / .tgz / .tar.bz2 / .tar.xz archive in memory - same overview as scan_directory, keyed by path inside the archive, nothing extracted to disk"
+)
+def scan_archive(
+    archive_path: str,
+    include: Optional[list[str]] = None,
+    exclude: Optional[list[str]] = None,
+    max_file_size: Optional[int] = None,
+    kinds: Optional[list[str]] = None,
+    name_pattern: Optional[str] = None,
+    qualified_names: bool = False,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Scan the files of an archive without extracting it.
+
+    For submissions, vendored dependencies and release tarballs. Entries
+    are read into memory and scanned like scan_file_content; results are
+    keyed by the path inside the archive ("pkg/server.go"). Entries with
+    absolute or ".." paths and symlinks are ignored. Decompressed size is
+    bounded per entry (max_file_size) and in total
+    ($SCANTOOL_MAX_ARCHIVE_BYTES, default 256 MiB) — past the total the
+    scan stops with partial results and a note.
+
+    Args:
+        archive_path: Path to a .zip, .tar, .tar.gz/.tgz, .tar.bz2 or .tar.xz
+        include: Doublestar globs over entry paths — only matching entries
+            are scanned, e.g. ["**/*.go"] (default: None = all)
+        exclude: Doublestar globs of entries to leave out; beats include
+            (default: None)
+        max_file_size: Supported entries decompressing to more than this
+            many bytes are listed but not parsed; 0 = no limit
+            (default: None = 5 MB)
+        kinds: Only these symbol kinds per entry, as in scan_file
+            (default: None = all)
+        name_pattern: Only symbols whose name this regex matches, as in
+            scan_file (default: None = all)
+        qualified_names: Match methods as Type.Method (default: False)
+        output_format: "tree", "json" or "json-stable" (default: "tree")
+
+    Returns:
+        Directory-style tree of the archive, or JSON keyed by entry path
+    """
+    try:
+        check_kinds(kinds)
+        names = compile_name_pattern(name_pattern)
+        note = ""
+        try:
+            results = scanner.scan_archive(
+                archive_path, include=include, exclude=exclude,
+                max_file_size=(DEFAULT_MAX_FILE_SIZE if max_file_size is None
+                               else max_file_size or None),
+                max_total_bytes=_ARCHIVE_MAX_BYTES or None)
+        except LimitExceeded as e:
+            results = e.results
+            remedy = (" or raise $SCANTOOL_MAX_ARCHIVE_BYTES (server limit)"
+                      if e.limit == "max_total_bytes" else "")
+            note = (f"Note: scan {e.reason} — partial results ({len(results)} entries). "
+                    f"Narrow include{remedy} for a complete scan.\n\n")
+
+        if not results:
+            return [TextContent(type="text", text=note + f"No files found in {archive_path}")]
+        if kinds or names:
+            results = {name: filter_symbols(structures, kinds, names, qualified_names)
+                       if structures else structures for name, structures in results.items()}
+
+        if output_format in _JSON_FORMATS:
+            json_results = {name: _structures_to_json(structures, name, return_dict=True)
+                            for name, structures in results.items() if structures}
+            return [TextContent(type="text", text=note + _dump_json(json_results, output_format))]
+
+        # Entries as paths under the archive, so the directory tree nests them
+        root = Path(archive_path)
+        tree = DirectoryFormatter(include_structures=True, flatten_structures=True).format(
+            str(root), {str(root / name): structures for name, structures in results.items()})
+        return [TextContent(type="text", text=note + tree + _skipped_note(results))]
+
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error scanning archive: {e}")]
+
+
 @mcp.tool(
     tags={"local", "diff", "review"},
     description="Structural diff against a git ref - which functions are new/changed/removed since HEAD/main/a release, with condensed skeletons. USE THIS INSTEAD of git diff for review and 'what changed' questions"
@@ -850,6 +1611,50 @@ def scan_diff(
         return [TextContent(type="text", text=f"Error diffing: {e}")]
 
 
+@mcp.tool(
+    tags={"local", "diff", "review"},
+    description="Symbol-level diff between two directory trees - added, removed and modified (signature or body changed) declarations, matched by symbol ID. For trees without a shared git history; use scan_diff for a ref"
+)
+def diff_symbols(
+    before: str,
+    after: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Diff the declarations of two directory trees.
+
+    Symbols are matched by symbol ID within the same relative directory, so
+    a declaration that moved between files of one package is unchanged. A
+    matched symbol is modified when its signature or its source
+    (whitespace-normalized) differs. Renames appear as removed + added.
+
+    Args:
+        before: Directory with the old tree
+        after: Directory with the new tree
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" (default) or "json"
+
+    Returns:
+        Added, removed and modified symbols with file:line per side
+    """
+    try:
+        for directory in (before, after):
+            if not Path(directory).is_dir():
+                raise FileNotFoundError(f"Directory not found: {directory}")
+        before_results = _complete_scan(before, "**/*", respect_gitignore=respect_gitignore)
+        after_results = _complete_scan(after, "**/*", respect_gitignore=respect_gitignore)
+        diff = diff_scans(before_results, after_results,
+                          str(Path(before).resolve()), str(Path(after).resolve()))
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(diff.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_symbol_diff(diff))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error diffing symbols: {e}")]
+
+
 @mcp.tool(
     tags={"local", "analysis", "review", "divergence"},
     description="Audit a directory for peer divergence - functions that break a call pattern their siblings across the codebase follow (peers calling X also call Y, this one doesn't). A REVIEW HINT to look at, not a verified bug list. Silent on a consistent codebase. Use to hunt drift, dead/missing connectivity, or misaligned implementations - cheaper and more focused than preview_directory when divergence is all you want"
@@ -902,6 +1707,196 @@ def find_divergence(
         return [TextContent(type="text", text=f"Error analyzing directory: {e}")]
 
 
+@mcp.tool(
+    tags={"local", "directory", "analysis"},
+    description="Rank the files of a directory by where to focus first - a composite of max/avg cyclomatic complexity, TODO/FIXME count and lines of code, with a per-file breakdown of what drives the score. Weights configurable, or sort by one metric. A triage view, not a quality verdict"
+)
+def hotspots(
+    directory: str,
+    top: int = DEFAULT_TOP,
+    sort_by: str = "score",
+    weights: Optional[dict[str, float]] = None,
+    pattern: str = "**/*",
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Rank code files by complexity, TODO markers and size.
+
+    Each metric is divided by its maximum in the scanned files, weighted
+    and summed into a 0-100 score, so the ranking is relative to this
+    codebase. Each row shows the metrics and how many points each one
+    contributed — why a file ranks high, not just that it does.
+
+    Args:
+        directory: Root directory to rank
+        top: Number of files returned (default: 10)
+        sort_by: "score" (default), or one metric to rank by alone —
+            "max_complexity", "avg_complexity", "todos", "lines"
+        weights: Metric -> weight for the score, e.g. {"todos": 1.0};
+            metrics left out keep their default (max_complexity 0.35,
+            avg_complexity 0.25, todos 0.2, lines 0.2). 0 drops a metric
+        pattern: Glob of files considered (default: "**/*")
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is the
+            ranked list [{file, score, max_complexity, max_function,
+            avg_complexity, functions, todos, lines, shares}]
+
+    Returns:
+        Ranked files with their metrics and score breakdown
+    """
+    try:
+        results = _complete_scan(directory, pattern, respect_gitignore=respect_gitignore)
+        spots = find_hotspots(results, top=top, sort_by=sort_by, weights=weights)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([s.to_dict() for s in spots],
+                                                             indent=2))]
+        return [TextContent(type="text", text=format_hotspots(spots, directory, sort_by))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error ranking hotspots: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "directory", "analysis"},
+    description="SHA-256 per file of a directory (raw bytes, matches sha256sum) and optionally a body hash per symbol, plus the groups of byte-identical files. For cache invalidation and dedup: compare hashes between calls instead of re-reading files. Symbol hashes are line-based, not AST-normalized"
+)
+def content_hashes(
+    directory: str,
+    pattern: str = "**/*",
+    include_symbols: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Content hash per file and body hash per symbol.
+
+    The file hash is SHA-256 of the bytes on disk, BOM and line endings
+    included. A symbol's body hash is sha1 of its source lines with
+    trailing whitespace and blank lines dropped — a raw text hash, so a
+    comment or indentation edit inside a function changes it. The same
+    hashes are in scan_directory's JSON (content_hash, body_hash).
+
+    Args:
+        directory: Root directory to hash
+        pattern: Glob of files considered (default: "**/*")
+        include_symbols: Also list every declaration's id, line and
+            body hash under its file (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is
+            {files: [{file, content_hash, symbols?: [{id, line,
+            body_hash}]}], identical: [[file, ...]]}
+
+    Returns:
+        Hashes per file, then the groups of identical files
+    """
+    try:
+        results = _complete_scan(directory, pattern, respect_gitignore=respect_gitignore)
+        hashes = collect_hashes(results)
+        if output_format == "json":
+            data = {"files": [h.to_dict(include_symbols) for h in hashes],
+                    "identical": identical_files(hashes)}
+            return [TextContent(type="text", text=json.dumps(data, indent=2))]
+        return [TextContent(type="text",
+                            text=format_hashes(hashes, directory, include_symbols))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error hashing directory: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "file", "analysis"},
+    description="Fenced code blocks of Markdown files (a file, or every .md under a directory) with their language, and the Go blocks (or other languages on request) run through the parser: symbols and parse errors at their line in the Markdown file. For 'do my README examples still parse' checks - syntax only, not compilation"
+)
+def scan_code_blocks(
+    path: str,
+    languages: Optional[list[str]] = None,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Scan the code samples inside Markdown files.
+
+    Each ``` or ~~~ block's info string names its language; blocks in one
+    of the requested languages are scanned as a file of that language
+    (named README.md@14.go), and their symbols and parse errors are
+    reported with line numbers shifted to the Markdown file. Other blocks
+    are listed, not scanned.
+
+    Args:
+        path: A Markdown file, or a directory whose Markdown files are
+            scanned
+        languages: Info-string languages to scan — names (go, golang,
+            python) or extensions (py, rs) (default: ["go"])
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file each block with its lines, language, symbols and parse
+        errors
+    """
+    try:
+        blocks = scan_markdown(path, scanner, languages or DEFAULT_BLOCK_LANGUAGES,
+                               respect_gitignore=respect_gitignore)
+        if output_format == "json":
+            return [TextContent(type="text",
+                                text=json.dumps([b.to_dict() for b in blocks], indent=2))]
+        return [TextContent(type="text", text=format_code_blocks(blocks, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error scanning code blocks: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "directory", "analysis"},
+    description="Sizing before a deep scan: per file the number of functions, methods, classes, types... (by node kind, any depth) and lines, plus grand totals - no symbol list, so the response stays tiny. Files are parsed as in scan_directory"
+)
+def count_symbols(
+    directory: str,
+    pattern: str = "**/*",
+    per_file: bool = True,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Count symbols by kind and lines per file, without listing them.
+
+    Every file is parsed as scan_directory would (and cached the same
+    way); only the aggregates are returned. Counts are by node type at any
+    depth, so a method inside a class counts as a method.
+
+    Args:
+        directory: Root directory to size up
+        pattern: Glob of files considered (default: "**/*")
+        per_file: Include the per-file rows, not just the totals
+            (default: True)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is
+            {total: {files, skipped, lines, counts, parse_errors}, files?:
+            [{file, language, lines, counts, parse_errors}]}
+
+    Returns:
+        Totals, then "file  N lines: counts" per file
+    """
+    try:
+        results = _complete_scan(directory, pattern, respect_gitignore=respect_gitignore)
+        files, totals = count_file_symbols(results)
+        if output_format == "json":
+            data = {"total": totals.to_dict()}
+            if per_file:
+                data["files"] = [f.to_dict() for f in files]
+            return [TextContent(type="text", text=json.dumps(data, indent=2))]
+        return [TextContent(type="text",
+                            text=format_counts(files, totals, directory, per_file))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error counting symbols: {e}")]
+
+
 @mcp.tool(
     tags={"local", "search", "filter"},
     description="Search across all file types - BEST FIRST CALL for targeted questions, USE INSTEAD of Grep: content_pattern finds text WITH structural context (enclosing function/class/section) plus leads to definitions; name/type/decorator find structures"
@@ -943,7 +1938,8 @@ def search_structures(
         Semantics & display:
             has_decorator: Filter by decorator (e.g., "@property", "@staticmethod")
             min_complexity: Minimum complexity (lines) to include
-            output_format: Output format - "tree" or "json" (default: "tree")
+            output_format: Output format - "tree", "json" or "json-stable"
+                (sorted keys and nodes, for snapshot diffs) (default: "tree")
 
     Returns:
         Matching structures with line numbers and metadata
@@ -960,7 +1956,7 @@ def search_structures(
     """
     try:
         # Scan directory (recursively scan all files)
-        results = scanner.scan_directory(directory, "**/*")
+        results = _complete_scan(directory)
 
         if content_pattern is not None:
             found = search_content(results, content_pattern)
@@ -994,11 +1990,11 @@ def search_structures(
             return [TextContent(type="text", text="No structures found matching the criteria")]
 
         # Format output
-        if output_format == "json":
+        if output_format in _JSON_FORMATS:
             json_results = {}
             for file_path, structures in matching.items():
                 json_results[file_path] = _structures_to_json(structures, file_path, return_dict=True)
-            return [TextContent(type="text", text=json.dumps(json_results, indent=2))]
+            return [TextContent(type="text", text=_dump_json(json_results, output_format))]
         else:
             outputs = []
             for file_path, structures in sorted(matching.items()):
@@ -1010,82 +2006,2150 @@ def search_structures(
         return [TextContent(type="text", text=f"Error searching: {e}")]
 
 
-def _filter_structures(
-    structures: list[StructureNode],
-    type_filter: Optional[str] = None,
-    name_pattern: Optional[str] = None,
-    has_decorator: Optional[str] = None,
-    min_complexity: Optional[int] = None
-) -> list[StructureNode]:
-    """Filter structures based on criteria."""
-    results = []
+@mcp.tool(
+    tags={"local", "search", "navigation"},
+    description="Find where a symbol is defined across a directory - exact, prefix or case-insensitive substring match on names; methods also match as Type.Method. Returns file:line per definition"
+)
+def find_symbol(
+    directory: str,
+    query: str,
+    match_mode: str = "exact",
+    respect_gitignore: bool = True,
+    max_results: int = 50,
+    use_index: bool = False
+) -> list[TextContent]:
+    """
+    Find symbol definitions by name across a directory.
 
-    for node in structures:
-        # Check filters
-        match = True
+    **When to use this vs other tools:**
+    - Use find_symbol() for "where is X defined" → file:line answers, no regex
+    - Use search_structures() for pattern/type/decorator queries or text hits
 
-        if type_filter and node.type != type_filter:
-            match = False
+    Methods match both by bare name ("GetUser") and qualified by their type
+    ("UserService.GetUser") — including Go methods, which are declared outside
+    their type.
 
-        if name_pattern and not re.search(name_pattern, node.name):
-            match = False
+    Args (tiered — most calls need only Common):
+        Common:
+            directory: Directory to search in
+            query: Symbol name, or Type.Method
+            match_mode: "exact" (default), "prefix", or "substring"
+                (case-insensitive)
+        Cost & slicing:
+            respect_gitignore: Respect .gitignore patterns (default: True)
+            max_results: Cap on listed definitions (default: 50)
+            use_index: Answer from the persistent symbol index of this
+                directory (under $SCANTOOL_INDEX_DIR, default
+                ~/.cache/scantool/index): built on first use, then only
+                files whose mtime or size changed are re-parsed — also
+                across server restarts (default: False)
 
-        if has_decorator and (not node.decorators or not any(has_decorator in d for d in node.decorators)):
-            match = False
+    Returns:
+        One line per definition: path:line, kind, qualified name, signature
 
-        if min_complexity and node.complexity:
-            if node.complexity.get("lines", 0) < min_complexity:
-                match = False
+    Examples:
+        find_symbol("./src", "FormatUser")
+        find_symbol("./src", "UserService.", match_mode="prefix")
+    """
+    try:
+        if use_index:
+            root = str(Path(directory).resolve())
+            with _symbol_indexes_lock:
+                index = _symbol_indexes.get((root, respect_gitignore))
+                if index is None:
+                    index = _symbol_indexes[(root, respect_gitignore)] = SymbolIndex(
+                        root, respect_gitignore=respect_gitignore)
+            update = index.update()
+            found = index.lookup(query, match_mode)
+            text = format_locations(found, query, max_results)
+            return [TextContent(type="text", text=f"{text}\nindex: {update}")]
+        results = _complete_scan(directory, "**/*", respect_gitignore=respect_gitignore)
+        found = find_symbol_locations(results, query, match_mode)
+        return [TextContent(type="text", text=format_locations(found, query, max_results))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except ValueError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error searching symbols: {e}")]
 
-        if match:
-            results.append(node)
 
-        # Recurse into children
-        if node.children:
-            filtered_children = _filter_structures(
-                node.children,
-                type_filter=type_filter,
-                name_pattern=name_pattern,
-                has_decorator=has_decorator,
-                min_complexity=min_complexity
-            )
-            results.extend(filtered_children)
+@mcp.tool(
+    tags={"local", "search", "navigation"},
+    description="Every symbol of a directory as one flat list sorted by file then line, each with its file path, qualified name, kind, line range and signature - for search indexes and tables, no per-file nesting to flatten"
+)
+def list_all_symbols(
+    directory: str,
+    pattern: str = "**/*",
+    kinds: Optional[list[str]] = None,
+    respect_gitignore: bool = True,
+    max_results: int = 500,
+    reference_format: str = "none",
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List all symbol definitions across a directory, flattened.
+
+    The same declarations find_symbol searches, as one globally sorted
+    stream instead of a tree per file: members follow their container,
+    methods carry their owner type in the qualified name (Type.Method).
+
+    Args:
+        directory: Directory to scan
+        pattern: Glob pattern for files (default: "**/*")
+        kinds: Only these symbol kinds — function, method, type, interface,
+            const, var, import, macro, key (default: all)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        max_results: Cap on listed symbols in tree output (default: 500;
+            JSON always lists all)
+        reference_format: JSON only: a clickable "ref" per symbol —
+            "plain" (path:line:1) or "vscode" (vscode://file/<absolute
+            path>:line:1) (default: "none")
+        output_format: "tree" or "json" (default: "tree"). JSON is a list of
+            {file, name, qualified_name, type, start_line, end_line,
+            signature?, symbol_id?, body_hash?, ref?}
+
+    Returns:
+        One line per symbol: path:line, kind, qualified name, signature
+    """
+    try:
+        check_reference_format(reference_format)
+        results = _complete_scan(directory, pattern, respect_gitignore=respect_gitignore)
+        symbols = symbol_table(results, kinds)
+        if output_format == "json":
+            entries = [s.to_dict() for s in symbols]
+            if reference_format != "none":
+                for entry in entries:
+                    entry["ref"] = reference(entry["file"], entry["start_line"], None,
+                                             reference_format)
+            return [TextContent(type="text", text=json.dumps(entries, indent=2))]
+        return [TextContent(type="text", text=format_symbol_table(symbols, directory, max_results))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except ValueError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error listing symbols: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "file", "navigation"},
+    description="Exact source of one symbol (function, method, type) in a file, doc comment included - by name, Type.Method or symbol ID"
+)
+def get_symbol_source(
+    file_path: str,
+    symbol: str,
+    output_format: str = "text",
+    root: Optional[str] = None
+) -> list[TextContent]:
+    """
+    Return one declaration's source text, nothing else.
+
+    The snippet runs from the doc comment directly above the declaration
+    (and its decorators) to the parser's end of the node — closing brace
+    included, the next declaration excluded. Lines are verbatim: original
+    indentation, no line-number prefixes.
+
+    Use scan_file(focus=...) instead when the surrounding skeleton matters.
+
+    Args:
+        file_path: File containing the symbol
+        symbol: Bare name ("ValidateEmail"), Type.Method ("UserService.GetUser")
+            or symbol ID from JSON results ("method:users.UserService.GetUser")
+        output_format: "text" (default: a one-line header, then the source)
+            or "json" ({file, symbol, id, type, start_line, end_line, source})
+        root: Directory the symbol ID came from a scan of — IDs name the
+            package path below it. Default: the file's directory
+
+    Returns:
+        The snippet, or an error listing candidates when the symbol is
+        missing or ambiguous
+    """
+    try:
+        structures = scanner.scan_file(file_path, root=root)
+        if structures is None:
+            return [TextContent(type="text", text=f"Error: Unsupported file type: {file_path}")]
+        snippet = extract_symbol_source(
+            structures, file_path, Path(file_path).read_bytes(), symbol,
+            scanner.registry.get(Path(file_path).suffix.lower()))
+        location = snippet.location
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps({
+                "file": file_path,
+                "symbol": location.qualified_name,
+                "id": location.symbol_id,
+                "type": location.type,
+                "start_line": snippet.start_line,
+                "end_line": snippet.end_line,
+                "source": snippet.source,
+            }, indent=2))]
+        header = (f"{file_path}:{snippet.start_line}-{snippet.end_line} "
+                  f"{location.type} {location.qualified_name}")
+        return [TextContent(type="text", text=f"{header}\n{snippet.source}")]
+    except (FileNotFoundError, LookupError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error reading symbol source: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="List every Go interface in a file or directory with its method signatures and embedded interfaces - what a concrete type must provide"
+)
+def list_interfaces(
+    path: str,
+    respect_gitignore: bool = True
+) -> list[TextContent]:
+    """
+    List Go interfaces with their method sets.
+
+    Each interface shows its methods with signatures reconstructed from the
+    syntax tree, and embedded interfaces by their referenced name
+    ("io.Reader") — not expanded, since that needs cross-package type
+    resolution.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+
+    Returns:
+        Interfaces grouped per file: name, line range, embeds, methods
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        return [TextContent(type="text", text=format_interfaces(list_go_interfaces(files), path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error listing interfaces: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Find the concrete Go types in a directory whose method sets satisfy an interface (structural match, pointer vs value receivers handled)"
+)
+def find_implementers(
+    path: str,
+    interface: str,
+    respect_gitignore: bool = True
+) -> list[TextContent]:
+    """
+    Find concrete types that implement a Go interface.
+
+    Pragmatic structural check without type checking: a type qualifies when
+    it has every interface method with the same name and parameter/result
+    types as written. Value-receiver methods count for T and *T,
+    pointer-receiver methods only for *T — such types are listed as *T.
+    Embedded interfaces declared in scope are expanded; ones from other
+    modules (io.Reader) can't be checked and are reported.
+
+    Args:
+        path: Go file or directory to search
+        interface: Interface name ("UserRepository"; a "pkg." prefix is ignored)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+
+    Returns:
+        Implementing types with location, plus notes on what wasn't checked
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        result = find_go_implementers(files, interface)
+        return [TextContent(type="text", text=format_implementers(result, interface))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding implementers: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Sweep Go files for TODO/FIXME/HACK comments (or custom tags) with file, line, author - read from comment nodes, never string literals"
+)
+def scan_comments(
+    path: str,
+    tags: Optional[list[str]] = None,
+    respect_gitignore: bool = True
+) -> list[TextContent]:
+    """
+    Collect tagged comments (TODO, FIXME, HACK, ...) from Go sources.
+
+    Comments come from the syntax tree, so "TODO" inside a string literal
+    never matches. A marker is a comment line starting with a tag,
+    optionally followed by an author and a colon: "// TODO(marius): retry".
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        tags: Tags to look for, case-sensitive (default: TODO, FIXME, HACK)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+
+    Returns:
+        Markers grouped per file: line, tag, author if given, text
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        return [TextContent(type="text", text=format_comments(scan_go_comments(files, tags), path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error scanning comments: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Go package import graph for a directory: package -> import edges flagged std/internal/external, plus import cycles among internal packages"
+)
+def import_graph(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Compute which Go packages import which.
+
+    Imports are aggregated per package (directory). Each edge is flagged:
+    "std" (standard library — no dot in the first path element),
+    "internal" (under the module path of the nearest go.mod) or
+    "external" (third-party). Cycles among internal packages are listed.
+
+    Args:
+        path: Directory to analyze (a single .go file also works)
+        include_tests: Count _test.go files (default: False — external
+            test packages would show up as cycles onto the tested package)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON has
+            {module, packages, edges: [{from, to, kind}], cycles}
+
+    Returns:
+        Imports grouped per package and kind, then any import cycles
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        graph = build_import_graph(files, path, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(graph.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_import_graph(graph, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error building import graph: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Imports of Go files in detail: path, alias (dot and blank imports included) and whether the package qualifier is used in the file - finds unused imports, e.g. in generated code"
+)
+def list_imports(
+    path: str,
+    unused_only: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List each Go file's import specs with alias and usage.
+
+    Usage is best effort: an import counts as used when its name (the alias,
+    else the last path element without a version suffix) qualifies a
+    selector or type in the file. Blank ("_") and dot (".") imports have no
+    qualifier and are reported as such, never as unused.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        unused_only: List only imports whose qualifier never appears
+            (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON maps each
+            file to [{path, name, line, blank, dot, used}]
+
+    Returns:
+        Per file: alias, path, line and used / UNUSED / blank / dot
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        if output_format == "json":
+            data = {go_file.path: [imp.to_dict() for imp in import_list(go_file)
+                                   if not unused_only or imp.used is False]
+                    for go_file in files}
+            return [TextContent(type="text", text=json.dumps(data, indent=2))]
+        return [TextContent(type="text", text=format_import_list(files, path, unused_only))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error listing imports: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview"},
+    description="One-call summary of the Go package in a directory: file count, exported vs unexported symbols, types with their methods (across files), package doc, stray package-name warnings"
+)
+def summarize_package(
+    directory: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Summarize one Go package — the .go files directly in a directory.
+
+    Methods are attached to their receiver type whichever file declares
+    them. A directory whose files disagree on the package name (a stray
+    `package main`) gets a warning; external test packages (pkg_test)
+    are expected and not flagged.
+
+    Args:
+        directory: Package directory (subdirectories are separate packages)
+        include_tests: Count symbols of _test.go files too (default: False;
+            test files are always counted as files)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Package name, counts, doc summary line, types with methods, warnings
+    """
+    try:
+        target = Path(directory)
+        if target.is_file():
+            target = target.parent
+        files = _load_go(str(target), respect_gitignore=respect_gitignore)
+        summary = summarize_go_package(files, str(target), include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(summary.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_package_summary(summary))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error summarizing package: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview"},
+    description="Orientation for a Go directory: its package name(s), and from the nearest go.mod the module path, Go version and the directory's import path. Module fields are empty without a go.mod"
+)
+def package_info(
+    directory: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Package name and module identity of one Go directory.
+
+    The package is what most of the .go files directly in the directory
+    declare; every name found is listed (an external pkg_test package
+    too). go.mod is looked up at and above the directory, like the go
+    tool does. Without one (GOPATH-style trees, loose files) only the
+    package name is returned.
+
+    Args:
+        directory: Package directory (a .go file means its directory)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Package name(s), file counts, module path, go version, import path
+    """
+    try:
+        target = Path(directory)
+        if target.is_file():
+            target = target.parent
+        files = _load_go(str(target), respect_gitignore=respect_gitignore)
+        info = go_package_info(files, str(target))
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(info.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_package_info(info))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error reading package info: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview", "docs"},
+    description="The exported API of a Go package as one compilable stub .go file - doc comments, signatures, exported fields, consts and vars, panic(\"stub\") bodies, only the imports needed. A go doc view an LLM or mock generator can use as Go"
+)
+def render_api_stub(
+    directory: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Render one Go package's public API as stub source.
+
+    Types are followed by their exported methods; struct fields and
+    interface methods that aren't exported are replaced by a "contains
+    filtered or unexported fields" comment. The source is gofmt-formatted
+    when gofmt is on PATH. Values that name unexported identifiers are
+    kept as written, so such a stub needs a hand edit to compile.
+
+    Args:
+        directory: Package directory (subdirectories are separate packages;
+            _test.go files are left out)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" (the Go source itself) or "json" (source
+            plus package, imports, symbol count, gofmt flag)
+            (default: "tree")
+
+    Returns:
+        Go source of the stub file
+    """
+    try:
+        target = Path(directory)
+        if target.is_file():
+            target = target.parent
+        files = _load_go(str(target), respect_gitignore=respect_gitignore)
+        stub = render_go_api_stub(files, str(target))
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(stub.to_dict(), indent=2))]
+        if stub.package is None:
+            return [TextContent(type="text", text=f"No Go package in {directory}")]
+        return [TextContent(type="text", text=stub.source)]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error rendering API stub: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "cleanup"},
+    description="Unexported Go functions, methods and types never referenced anywhere in their package - heuristic cleanup candidates (reflection, cgo, go:linkname uses are invisible)"
+)
+def find_dead_code(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Flag unexported declarations no file of their package mentions.
+
+    References are identifier uses of any kind (calls, types, x.name
+    selectors); methods match by name, whatever the receiver. init and
+    main are never flagged. Candidates, not proof: symbols used only via
+    reflection, cgo //export or go:linkname are reported too.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        include_tests: Count _test.go files as references - and check
+            their declarations (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: unreferenced functions, methods (Type.name) and types
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        symbols = find_go_dead_code(files, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([s.to_dict() for s in symbols], indent=2))]
+        return [TextContent(type="text", text=format_dead_code(symbols, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding dead code: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality", "docs"},
+    description="Exported Go functions, methods (on exported types), types, consts and vars without a doc comment, with file and line - the golint 'should have comment' list, for filling in docs"
+)
+def find_undocumented(
+    path: str,
+    include_generated: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List exported declarations that have no doc comment.
+
+    A doc comment is the comment group directly above a declaration; one
+    that is empty or only directives (//go:generate, //nolint:...) does
+    not count. Methods are checked only when their receiver type is
+    exported. In a grouped const/var/type declaration the comment on the
+    group covers its specs. _test.go files are never checked.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        include_generated: Also check files marked "// Code generated ...
+            DO NOT EDIT." (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: undocumented functions, methods (Type.Name), types,
+        consts and vars with their line
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        symbols = find_go_undocumented(files, include_generated=include_generated)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([s.to_dict() for s in symbols], indent=2))]
+        return [TextContent(type="text", text=format_undocumented(symbols, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding undocumented symbols: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Go naming review per package: exported names that stutter with their package (user.UserService -> user.Service), and types whose methods name the receiver inconsistently (s in one method, self in another) or generically (this, self), with a suggested name"
+)
+def naming_report(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Report package-name stutter and inconsistent receiver names.
+
+    Stutter: an exported top-level name that starts with its package name
+    followed by an upper-case letter, which callers read twice
+    (user.UserService). Receivers: every method of a type, across the
+    package's files, should name its receiver the same way and not this
+    or self; a type that doesn't is listed with the names in use, the
+    most common one suggested and the methods that differ.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Stutter candidates with the shorter name, then per type its
+        receiver names and the methods to rename
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        report = go_naming_report(files, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(report.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_naming_report(report, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error reporting naming: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Go exported functions and methods whose parameters or results name an unexported type of their own package (func New() *client), after unwrapping pointers, slices, maps, channels and generic arguments - APIs other packages can call but can't fully use"
+)
+def find_leaked_unexported(
+    path: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Find exported signatures that expose unexported types.
+
+    Another package can call `func New() *client` but can't declare a
+    variable of its result type or pass one along in its own signatures.
+    Checks every exported function, and exported method of an exported
+    type, in non-test files: each parameter and result type is unwrapped
+    to the named types inside and matched against the unexported types
+    the package declares. Aliases and the function's own type parameters
+    never match.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file, each leaking parameter or result with the unexported
+        type and where it is declared
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        leaks = find_go_leaked_unexported(files)
+        if output_format == "json":
+            return [TextContent(type="text",
+                                text=json.dumps([leak.to_dict() for leak in leaks], indent=2))]
+        return [TextContent(type="text", text=format_leaked_unexported(leaks, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding leaked unexported types: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview"},
+    description="Go API surface metric: exported functions, methods, types, consts and vars counted per package and in total - a cheap number to snapshot in CI and alert on unexpected API growth, with the kind breakdown that says what grew. Top-level declarations only, no bodies"
+)
+def api_surface(
+    path: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Count the exported symbols of Go packages by kind.
+
+    Counts what other packages can name: exported functions, methods of
+    exported types, types (aliases too), consts and vars, one per name,
+    in non-test files. The JSON is stable for the same code, so CI can
+    store it and compare totals or kinds between commits.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is
+            {total, counts: {function, method, type, const, var},
+            packages: [{directory, package, total, counts}]}
+
+    Returns:
+        The grand total with its breakdown, then one line per package
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        surface = go_api_surface(files)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(surface.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_api_surface(surface, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error measuring API surface: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Go functions, methods and func literals with more than max_params parameters (a, b, c int counts 3) and struct types with more than max_fields fields, with locations - long parameter lists and wide structs to refactor ('pass an options struct'). Thresholds are the team's convention"
+)
+def find_wide_signatures(
+    path: str,
+    max_params: Optional[int] = DEFAULT_MAX_PARAMS,
+    max_fields: Optional[int] = DEFAULT_MAX_FIELDS,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Flag long parameter lists and wide structs.
+
+    Parameters are counted by name, so a grouped `host, port string` is
+    two; an unnamed or variadic parameter is one, the receiver none.
+    Fields are counted the same way, an embedded type as one.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        max_params: Most parameters allowed; None skips the check
+            (default: 5)
+        max_fields: Most struct fields allowed; None skips the check
+            (default: 15)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file each function or struct over its limit, with its count
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        wide = find_go_wide_signatures(files, max_params=max_params, max_fields=max_fields,
+                                       include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([w.to_dict() for w in wide],
+                                                             indent=2))]
+        return [TextContent(type="text", text=format_wide_signatures(
+            wide, path, max_params=max_params, max_fields=max_fields))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding wide signatures: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Go functions, methods and func literals whose body spans at least min_lines lines (opening to closing brace; doc comment and signature not counted), longest first with file, line and length - long functions to split in review"
+)
+def find_long_functions(
+    path: str,
+    min_lines: int = DEFAULT_MIN_LINES,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List long function bodies, longest first.
+
+    Length is the body block's span, both brace lines included, so a
+    long doc comment or a wrapped signature doesn't count. Simpler than
+    complexity: a flat 200-line sequence of steps is reported too.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        min_lines: Shortest body reported, in lines (default: 50)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        One line per function: body length, kind, name and location
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        functions = find_go_long_functions(files, min_lines=min_lines,
+                                           include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([f.to_dict() for f in functions],
+                                                             indent=2))]
+        return [TextContent(type="text", text=format_long_functions(
+            functions, path, min_lines=min_lines))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding long functions: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Go 'x, err := f()' calls whose x is used, or whose err is overwritten, before err is checked - likely nil-pointer dereferences after a failed call. Same-block, straight-line heuristic: false positives possible"
+)
+def find_unchecked_errors(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Flag error results that are not looked at before the value is.
+
+    For each `x, err := f()` (or `=`) the following statements of the
+    same block are read in order: the first one mentioning err counts as
+    the check. A use of x before it, or a new assignment to err, is
+    reported. Checks on other branches or in helpers are not seen, and an
+    inner err that shadows the outer one is treated as the same.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: the assignment with its function, and where its value is
+        used (or its error overwritten) before the check
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        findings = find_go_unchecked_errors(files, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([f.to_dict() for f in findings], indent=2))]
+        return [TextContent(type="text", text=format_unchecked_errors(findings, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding unchecked errors: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Per Go function or method returning an error: how its same-package call sites handle that error - checked, returned, used, or dropped (assigned to _, or called as a bare statement / go / defer) - with the ignore ratio, most-dropped first. Finds APIs whose errors are habitually ignored"
+)
+def error_handling_report(
+    path: str,
+    min_calls: int = 1,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Rank error-returning functions by how often their callers drop the error.
+
+    Call sites are resolved within each package the way call_graph does
+    (package functions; methods through receiver, parameter, variable
+    and field types). Same package only: callers in other packages and
+    calls through interfaces or function values are not counted, so an
+    exported function's ratio reflects its own package's use of it.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        min_calls: Only functions with at least this many call sites
+            (default: 1)
+        include_tests: Count calls in _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is a list
+            of {name, file, line, calls, dropped, ignore_ratio, handling:
+            {checked, returned, used, blank, ignored}, sites: [{file,
+            line, caller, handling}]}
+
+    Returns:
+        Per function: dropped / calls with the handling tally and the
+        sites that drop the error
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        report = go_error_handling_report(files, include_tests=include_tests,
+                                          min_calls=min_calls)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([f.to_dict() for f in report],
+                                                             indent=2))]
+        return [TextContent(type="text", text=format_error_handling(report, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error building error handling report: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Every Go type assertion x.(T) and type switch, with the asserted types and enclosing function - single-value assertions that panic on a mismatch flagged apart from the panic-safe comma-ok form"
+)
+def find_type_assertions(
+    path: str,
+    panicking_only: bool = False,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List type assertions and type switches, marking the ones that can panic.
+
+    `v, ok := x.(T)` (also `=` and `var v, ok = ...`) is comma-ok and
+    safe; any other use of x.(T) — single-value assignment, argument,
+    return value, method call on the result — panics when x holds another
+    type. Type switches never panic and come with their case types and
+    whether they have a default.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        panicking_only: Only the assertions that can panic (default: False)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: "@line function: x.(T)" with its form, and type switches
+        with their cases
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        assertions = find_go_type_assertions(files, include_tests=include_tests,
+                                             panicking_only=panicking_only)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([a.to_dict() for a in assertions], indent=2))]
+        return [TextContent(type="text", text=format_type_assertions(assertions, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding type assertions: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Where Go code can crash the process: panic() and log.Panic* calls apart from os.Exit and log.Fatal* (no deferred calls, no recover), each with file, line and enclosing function. Callee sets configurable; exits outside package main counted"
+)
+def find_panics(
+    path: str,
+    panic_calls: Optional[list[str]] = None,
+    exit_calls: Optional[list[str]] = None,
+    exclude_main: bool = False,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List explicit panics and process exits with their enclosing function.
+
+    A panic unwinds and can be recovered by a deferred recover() (noted
+    when the function defers one); an exit ends the process at once,
+    deferred calls skipped — in library code, a bug. Callees are import
+    path + function, resolved through each file's imports and aliases.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        panic_calls: Callees counted as panics (default: panic, log.Panic,
+            log.Panicf, log.Panicln)
+        exit_calls: Callees counted as exits (default: os.Exit, log.Fatal,
+            log.Fatalf, log.Fatalln) — e.g. add
+            "github.com/sirupsen/logrus.Fatal"
+        exclude_main: Leave out files of package main (default: False)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: "@line function: callee(args)" with panic or exit
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        calls = find_go_panics(files, include_tests=include_tests, panic_calls=panic_calls,
+                               exit_calls=exit_calls, exclude_main=exclude_main)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([c.to_dict() for c in calls], indent=2))]
+        return [TextContent(type="text", text=format_panics(calls, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding panics: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Every Go func init() - each occurrence listed, several per file and per package - with file, line, its run order within the package (files by name, then source order) and the functions it calls. Audits implicit startup behavior"
+)
+def find_inits(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List every init function with its run order and the calls it makes.
+
+    init functions run on import, before main, and share one name, so a
+    symbol listing shows at most one of them. Here each declaration is
+    its own entry, numbered in the order Go runs a package's inits.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: "@line-end init #order of total: calls ..."
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        inits = find_go_inits(files, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([i.to_dict() for i in inits], indent=2))]
+        return [TextContent(type="text", text=format_inits(inits, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding init functions: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview"},
+    description="Go //go:generate directives (exactly as go generate matches them: column 0, no space after //) with file, line, package and command - the code-generation steps a package runs and can re-run, and where its generated files come from"
+)
+def find_generate_directives(
+    path: str,
+    include_tests: bool = True,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List the //go:generate commands of Go packages.
+
+    A directive is a line comment starting at column 0 with
+    "//go:generate" and a space or tab — "// go:generate" and indented
+    ones are ignored by the go tool and here. "-command NAME ..." lines
+    define an alias for later directives and are marked as such.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        include_tests: Read _test.go files too, as go generate does
+            (default: True)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        The programs run, then per file each directive's line and command
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        directives = find_go_generate_directives(files, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text",
+                                text=json.dumps([d.to_dict() for d in directives], indent=2))]
+        return [TextContent(type="text", text=format_generate_directives(directives, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding go:generate directives: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Every Go composite literal of one type - T{...}, &T{...}, pkg.T{...} and elided elements of []T{{...}} - with file, line, enclosing function and the fields each sets or omits, plus how often each declared field is set across the tree. Shows how a type is really constructed"
+)
+def find_constructions(
+    path: str,
+    type_name: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Find the composite literals that construct a type.
+
+    "User" matches User{...} in any package and x.User{...} for any
+    qualifier; "models.User" narrows to the package named models (its
+    unqualified literals, and qualified ones through an import of it).
+    Positional literals are mapped to field names by the struct
+    declaration when it is in the scanned tree, which is also what the
+    omitted fields are computed against.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        type_name: The type, "T" or "pkg.T"
+        include_tests: Count literals in _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is {type,
+            declaration, declaring_packages, declared_fields,
+            field_counts, constructions:
+            [{file, line, function, package, type, pointer, fields,
+            positional, omitted}]}
+
+    Returns:
+        How often each field is set, then per file "@line function:
+        &T{fields}" with the fields left out
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        report = find_go_constructions(files, type_name, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(report.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_constructions(report, path))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding constructions: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Go concurrency surface map: every go statement (go func(){}() attributed to the launching function), make(chan T[, n]) with buffered/unbuffered when the capacity is a literal, channel sends and receives (select cases marked), each with file, line and enclosing function"
+)
+def concurrency_report(
+    path: str,
+    kinds: Optional[list[str]] = None,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List goroutine launches and channel operations with their enclosing function.
+
+    A go statement belongs to the function that runs it; operations
+    inside a launched closure read "func literal in F". make(chan T) and
+    a literal capacity of 0 are unbuffered, any other integer literal is
+    buffered; a capacity held in a variable or constant is reported as
+    written, buffering unknown.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        kinds: Some of "go", "make", "send", "receive" (default: all)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: "@line function: expression" with the kind and channel
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        ops = find_go_concurrency(files, include_tests=include_tests, kinds=kinds)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([op.to_dict() for op in ops], indent=2))]
+        return [TextContent(type="text", text=format_concurrency(ops, path))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error building concurrency report: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Every Go defer statement with its enclosing function and the deferred call as written, flagging defers inside for loops (they run at function return, not per iteration) and deferred calls that drop an error, like defer f.Close()"
+)
+def find_defers(
+    path: str,
+    loops_only: bool = False,
+    error_methods: Optional[list[str]] = None,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List defer statements, flagging defers in loops and dropped errors.
+
+    A defer in a for body piles up until the function returns; one inside
+    a func literal called per iteration doesn't count. A deferred call
+    drops an error when its same-package declaration returns one, or —
+    callee outside the package — when it is a method named in
+    error_methods.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        loops_only: Only the defers inside a loop (default: False)
+        error_methods: Method names taken to return an error when the
+            callee isn't declared in the package (default: Close, Flush,
+            Sync, Commit, Rollback)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: "@line function: defer call" with the loop and error flags
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        defers = find_go_defers(files, include_tests=include_tests, loops_only=loops_only,
+                                error_methods=error_methods)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([d.to_dict() for d in defers], indent=2))]
+        return [TextContent(type="text", text=format_defers(defers, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding defers: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Go variables shadowed inside a function - an inner :=, var, parameter, range or type switch variable reusing the name of one in an enclosing scope (x, err := f() inside an if hiding the outer err), with both locations. Real block scoping: blocks, if/for/switch init clauses, case clauses, closures"
+)
+def find_shadowing(
+    path: str,
+    names: Optional[list[str]] = None,
+    include_self_copies: bool = False,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Find declarations that shadow a variable of an enclosing scope.
+
+    Scopes follow the Go spec: the function's parameters and outer block,
+    every nested block, the init clause of if/for/switch/select, each case
+    clause, and func literals inside the function. A := only declares the
+    names new to its own scope, so `x, err := f()` beside an earlier err
+    in the same block is an assignment, not a shadow. Package-level names
+    are not tracked.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        names: Only these names, e.g. ["err"] (default: all)
+        include_self_copies: Also report the idiomatic v := v and
+            switch v := v.(type) (default: False)
+        include_tests: Check _test.go files too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Per file: "@line function: name (kind) shadows kind @line"
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        shadows = find_go_shadowing(files, include_tests=include_tests, names=names,
+                                    include_self_copies=include_self_copies)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([s.to_dict() for s in shadows], indent=2))]
+        return [TextContent(type="text", text=format_shadowing(shadows, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding shadowed variables: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "cleanup"},
+    description="Copy-pasted Go functions - groups of functions/methods whose bodies are identical, or identical up to renamed identifiers (comments and formatting ignored). Semantic clones are not detected"
+)
+def find_duplicates(
+    path: str,
+    min_lines: int = DEFAULT_DUPLICATE_LINES,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Group Go functions with duplicated bodies across a directory tree.
+
+    Bodies are compared as syntax-tree tokens, so comments, whitespace and
+    gofmt differences never matter. "exact" groups are token-identical;
+    "renamed" groups differ only in identifier names (variables, fields,
+    types, called functions), renamed consistently. Literals must match.
+    Same logic written differently, or a block copied into a longer
+    function, is not found.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        min_lines: Only functions of at least this many lines, declaration
+            through closing brace (default: 5)
+        include_tests: Compare _test.go functions too (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Duplicate groups, largest first, each with its locations
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        groups = find_go_duplicates(files, min_lines=min_lines, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([g.to_dict() for g in groups], indent=2))]
+        return [TextContent(type="text", text=format_duplicates(groups, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding duplicates: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "testing"},
+    description="Go test, benchmark, fuzz and example functions in _test.go files, counted by kind, each with the function it appears to test by naming convention (heuristic, not coverage)"
+)
+def scan_tests(
+    path: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Find and classify the functions go test runs.
+
+    Recognized by go test's signature rules: TestXxx(*testing.T),
+    BenchmarkXxx(*testing.B), FuzzXxx(*testing.F), ExampleXxx() and
+    TestMain(*testing.M), honouring an aliased or dot-imported testing
+    package. Lookalikes such as Testify() or TestX(t *T) are not counted.
+
+    Each test's "target" is a naming-convention guess — TestFormatUser →
+    FormatUser, TestStore_Get → Store.Get — and exported functions no test
+    name points at are listed. This is a heuristic, not coverage: use
+    go test -cover for that.
+
+    Args:
+        path: Directory to analyze, every package under it (a single .go
+            file also works)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        Counts by kind, tests per file with their targets, and untested
+        exported functions
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        suite = scan_go_tests(files)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(suite.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_tests(suite, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error scanning tests: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview", "docs"},
+    description="Mermaid classDiagram of a Go package: types with fields and methods, embedding and field-holding relationships between package-local types, and interface implementations (..|>). Renders in GitHub and mkdocs as-is"
+)
+def class_diagram(
+    directory: str,
+    respect_gitignore: bool = True,
+    output_format: str = "mermaid"
+) -> list[TextContent]:
+    """
+    Draw the types of one Go package as a Mermaid class diagram.
+
+    Every named type of the package's non-test files becomes a class with
+    its fields and methods (+ exported, - unexported), methods gathered
+    from all files. Relationships between package-local types: *-- for an
+    embedded struct, --|> for an embedded interface, --> for a field that
+    holds another type ("*" through slices and maps), ..|> for a type
+    that structurally implements a local interface.
+
+    Args:
+        directory: Package directory (subdirectories are separate packages)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "mermaid" (diagram source), "markdown" (wrapped in a
+            ```mermaid fence, ready to paste into docs) or "json"
+            (default: "mermaid")
+
+    Returns:
+        Mermaid classDiagram source, or the types and edges as JSON
+    """
+    try:
+        target = Path(directory)
+        if target.is_file():
+            target = target.parent
+        files = _load_go(str(target), respect_gitignore=respect_gitignore)
+        diagram = build_class_diagram(files, str(target))
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(diagram.to_dict(), indent=2))]
+        text = format_mermaid(diagram)
+        if output_format == "markdown":
+            text = f"```mermaid\n{text}\n```"
+        return [TextContent(type="text", text=text)]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error building class diagram: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview"},
+    description="Go embedding relationships of one package: per struct/interface the types it embeds and, separately, the local types embedding it, a forest outer → embedded, and the fields and methods gained through embedding (Go's promotion rules, same package only)"
+)
+def type_hierarchy(
+    directory: str,
+    include_promoted: bool = True,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Show what embeds what in one Go package.
+
+    "embeds" lists a type's embedded types as written (Base, *Cache,
+    sync.Mutex); "embedded by" the package-local types embedding it — two
+    lists, so the direction is never ambiguous. The forest starts at the
+    types nothing local embeds. Promoted members follow Go's selector
+    rule: shallowest depth wins, a tie at the same depth is ambiguous and
+    not promoted, the type's own members shadow embedded ones. Members of
+    types from other packages are unknown.
+
+    Args:
+        directory: Package directory (subdirectories are separate packages)
+        include_promoted: List the fields and methods each type gains
+            through embedding (default: True)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
+
+    Returns:
+        The embedding forest, then per type its embeds, embedded-by and
+        promoted members
+    """
+    try:
+        target = Path(directory)
+        if target.is_file():
+            target = target.parent
+        files = _load_go(str(target), respect_gitignore=respect_gitignore)
+        hierarchy = build_type_hierarchy(files, str(target), include_promoted=include_promoted)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(hierarchy.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_type_hierarchy(hierarchy))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error building type hierarchy: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "overview"},
+    description="Transitive field-type dependencies of one Go type within its package: local types reached through fields (pointers, slices, maps, arrays unwrapped) with depth, other packages' types as leaves, and the owner → type edges with the fields creating them"
+)
+def type_dependencies(
+    directory: str,
+    type_name: str,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Follow a Go type's fields to every package-local type it depends on.
 
-    return results
+    Field types are unwrapped (*T, []T, [n]T, map[K]V, chan T, generic
+    arguments) to named types; local ones are followed breadth-first,
+    pkg.T types are listed as external leaves, builtins are left out. A
+    non-struct type depends on its underlying type. Interface methods and
+    func-typed fields are not followed.
 
+    Args:
+        directory: Package directory (subdirectories are separate packages)
+        type_name: Type to start from, as declared ("User")
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree")
 
-def _structures_to_json(structures: list[StructureNode], file_path: str, return_dict: bool = False):
-    """Convert structures to JSON format."""
+    Returns:
+        Local and external dependencies with their depth, then the edges
+    """
+    try:
+        target = Path(directory)
+        if target.is_file():
+            target = target.parent
+        files = _load_go(str(target), respect_gitignore=respect_gitignore)
+        result = find_go_type_dependencies(files, str(target), type_name)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(result.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_type_dependencies(result))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error tracing type dependencies: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Which Go files aren't gofmt-clean: formatted / unformatted / unknown (syntax errors, no gofmt) per file, optional unified diff"
+)
+def check_formatting(
+    path: str,
+    include_diff: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Check Go files against gofmt without writing anything.
+
+    Each file's bytes go to gofmt on stdin and its output is compared with
+    the original. A file with syntax errors is "unknown", never
+    "unformatted" — can't tell is not badly formatted. Needs gofmt on
+    PATH; without it every file is unknown.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        include_diff: Add a unified diff original → gofmt output for
+            unformatted files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is a list
+            of {file, gofmt, reason?, diff?}
+
+    Returns:
+        Tally per state, then every file that isn't clean
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        checks = check_go_formatting(files, with_diff=include_diff)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([c.to_dict() for c in checks], indent=2))]
+        return [TextContent(type="text", text=format_format_checks(checks, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error checking formatting: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "security"},
+    description="Every Go string literal with file, line, enclosing function/const, interpreted vs raw, escapes decoded - optional regex filter for secret audits or i18n; never matches comments"
+)
+def extract_strings(
+    path: str,
+    pattern: Optional[str] = None,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    redact_strings: Optional[str] = None,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Collect string literals from Go sources.
+
+    Literals come from the syntax tree, so text in comments never shows
+    up. Interpreted strings ("...") are decoded (\\n, \\x41, \\u00e9);
+    raw strings (`...`) are kept verbatim. Import paths and struct tags
+    are left out.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        pattern: Regex searched in the decoded value, e.g.
+            "^[A-Za-z0-9+/]{32,}={0,2}$" for base64-looking secrets
+            (default: all literals)
+        include_tests: Include _test.go files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        redact_strings: "off", "length" or "hash" — values replaced after
+            pattern matched the real ones, so a search for secrets reports
+            where they are without showing them (default: the
+            redact_strings of a .scannerrc, else "off")
+        output_format: "tree" or "json" (default: "tree"). JSON is a list
+            of {file, line, kind, value, container}
+
+    Returns:
+        Per file: line, enclosing declaration and value of each literal
+    """
+    try:
+        redact_strings = _redact_mode(path, redact_strings)
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        if not include_tests:
+            files = [f for f in files if not f.path.endswith("_test.go")]
+        try:
+            literals = extract_go_strings(files, pattern)
+        except re.error as e:
+            return [TextContent(type="text", text=f"Error: Invalid pattern {pattern!r}: {e}")]
+        for literal in literals:
+            literal.value = redact_value(literal.value, redact_strings)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([s.to_dict() for s in literals],
+                                                             indent=2, ensure_ascii=False))]
+        return [TextContent(type="text", text=format_strings(literals, path))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error extracting strings: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "security"},
+    description="Every Go regexp.Compile/MustCompile call with its pattern, location and enclosing function - MustCompile (panics) vs Compile (returns an error), patterns built at runtime flagged, static patterns checked against RE2 syntax to catch invalid ones before they run"
+)
+def find_regexes(
+    path: str,
+    check: bool = True,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List regular expressions compiled with the regexp package.
+
+    A pattern that is a string literal, a concatenation of literals or a
+    package-level string constant is decoded and, with check, run through
+    the syntax rules of Go's regexp parser (RE2: no lookaround, no
+    backreferences, repeat counts up to 1000). Other patterns are dynamic
+    and shown as written — a MustCompile of one panics on bad input.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        check: Report RE2 syntax errors of static patterns (default: True)
+        include_tests: Include _test.go files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON is a list
+            of {file, line, function, call, panics, dynamic, expression,
+            pattern, error?}
+
+    Returns:
+        Per file: line, enclosing function, call and pattern, with invalid
+        and dynamic patterns flagged
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        calls = find_go_regexes(files, include_tests=include_tests, check=check)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([c.to_dict() for c in calls],
+                                                             indent=2, ensure_ascii=False))]
+        return [TextContent(type="text", text=format_regexes(calls, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error finding regexes: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Go package-level constants with their values: iota enums computed (A, B, C = 0, 1, 2), typed constants with their type, unevaluable expressions left empty with a note"
+)
+def list_constants(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    redact_strings: Optional[str] = None,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List const declarations with their folded values.
+
+    iota and implicitly repeated specs are replayed like the compiler
+    does; literals, other constants of the package, operators,
+    conversions and len/min/max are evaluated. What can't be evaluated
+    statically (time.Second, function calls) has no value and a note
+    instead — never a guess.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        include_tests: Include _test.go files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        redact_strings: String values and literals in expressions — "off",
+            "length" or "hash", as in scan_file (default: the
+            redact_strings of a .scannerrc, else "off")
+        output_format: "tree" or "json" (default: "tree"). JSON is a list
+            of blocks {file, line, grouped, constants: [{name, type,
+            value, expression, iota?, note?}]}
+
+    Returns:
+        Per file: const blocks, one "Name Type = value" line per constant
+    """
+    try:
+        redact_strings = _redact_mode(path, redact_strings)
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        blocks = list_go_constants(files, include_tests=include_tests)
+        for constant in (c for b in blocks for c in b.constants):
+            constant.value = redact_code(constant.value, redact_strings, single_quotes=False)
+            constant.expression = redact_code(constant.expression, redact_strings,
+                                              single_quotes=False)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([b.to_dict() for b in blocks],
+                                                             indent=2, ensure_ascii=False))]
+        return [TextContent(type="text", text=format_constants(blocks, path))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error listing constants: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Go package-level variables (global mutable state) with their type, declared or inferred, and initializer — zero values, static initializers and code that runs at package init told apart"
+)
+def list_globals(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    redact_strings: Optional[str] = None,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    List package-level var declarations, grouped as written.
+
+    Every variable gets its declared type, or one inferred from a simple
+    initializer (literals, T{...}, &T{...}, conversions, make/new, func
+    literals), and its initializer on one line. "zero" vars have none,
+    "static" ones run no code of the program, "runtime" ones call a
+    function at package init (var cache = newCache()). Blank vars are
+    not listed.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        include_tests: Include _test.go files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        redact_strings: String literals in initializers — "off", "length"
+            or "hash", as in scan_file (default: the redact_strings of a
+            .scannerrc, else "off")
+        output_format: "tree" or "json" (default: "tree"). JSON is a list
+            of blocks {file, line, grouped, vars: [{name, file, line,
+            exported, type, initializer, init, type_inferred?, result?}]}
+
+    Returns:
+        Per file: var blocks, one "name Type = initializer  [init]" line
+        per variable
+    """
+    try:
+        redact_strings = _redact_mode(path, redact_strings)
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        blocks = list_go_globals(files, include_tests=include_tests)
+        for variable in (v for b in blocks for v in b.vars):
+            variable.initializer = redact_code(variable.initializer, redact_strings,
+                                               single_quotes=False)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps([b.to_dict() for b in blocks],
+                                                             indent=2, ensure_ascii=False))]
+        return [TextContent(type="text", text=format_globals(blocks, path))]
+    except (FileNotFoundError, ValueError) as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error listing globals: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis", "quality"},
+    description="Go struct field tags parsed the way reflect.StructTag does: malformed tags (unbalanced quotes, missing colon, unquoted value, bad escape) that frameworks silently stop reading at, pairs not separated by spaces, duplicate keys, and optionally keys outside an allow-list (json, db, validate...)"
+)
+def validate_struct_tags(
+    path: str,
+    keys: Optional[list[str]] = None,
+    include_valid: bool = False,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Check every struct field tag for syntax errors.
+
+    A tag is parsed as reflect.StructTag.Lookup reads it: space-separated
+    key:"value" pairs, the value a valid double-quoted Go string. Lookup
+    stops at the first syntax error, so the keys after it are invisible
+    to encoding/json and the rest — reported with the error. Pairs
+    missing the separating space and repeated keys are reported as go
+    vet does.
+
+    Args:
+        path: Go file or directory (walked with scan_directory's rules)
+        keys: Allowed tag keys, e.g. ["json", "db", "validate"]; any other
+            key is reported (default: None = no key check)
+        include_valid: List tags without problems too, with their pairs
+            (default: False)
+        include_tests: Include _test.go files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON lists
+            tags shown as {file, line, struct, field, tag, pairs, problems}
+
+    Returns:
+        Tally, then per file the tags with problems: line, struct.field,
+        the tag as written and what is wrong with it
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        tags = find_go_struct_tags(files, include_tests=include_tests, known_keys=keys)
+        if output_format == "json":
+            shown = tags if include_valid else [t for t in tags if t.problems]
+            return [TextContent(type="text", text=json.dumps([t.to_dict() for t in shown],
+                                                             indent=2, ensure_ascii=False))]
+        return [TextContent(type="text", text=format_struct_tags(tags, path, include_valid))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error validating struct tags: {e}")]
+
+
+@mcp.tool(
+    tags={"local", "go", "analysis"},
+    description="Go call graph within each package: function -> callee edges, resolved to same-package declarations (methods via known receiver/variable types) or flagged external/unresolved"
+)
+def call_graph(
+    path: str,
+    include_tests: bool = False,
+    respect_gitignore: bool = True,
+    output_format: str = "tree"
+) -> list[TextContent]:
+    """
+    Compute who calls what inside each Go package.
+
+    Every call in a function or method body becomes an edge from the
+    enclosing declaration. Same-package functions resolve to their
+    declaration; x.Method(...) resolves when x's type is known (receiver,
+    parameter, variable from a literal / new(T) / constructor result,
+    struct field). Imported-package calls are "external"; interface
+    method calls and function values stay "unresolved" as written.
+
+    Args:
+        path: Directory to analyze (a single .go file also works)
+        include_tests: Include _test.go files (default: False)
+        respect_gitignore: Respect .gitignore patterns (default: True)
+        output_format: "tree" or "json" (default: "tree"). JSON has
+            {functions, edges: [{from, to, expression, kind, file, lines, target?}]}
+
+    Returns:
+        Per file: each function with its calls and where they resolve to
+    """
+    try:
+        files = _load_go(path, respect_gitignore=respect_gitignore)
+        graph = build_go_call_graph(files, include_tests=include_tests)
+        if output_format == "json":
+            return [TextContent(type="text", text=json.dumps(graph.to_dict(), indent=2))]
+        return [TextContent(type="text", text=format_call_graph(graph, path))]
+    except FileNotFoundError as e:
+        return [TextContent(type="text", text=f"Error: {e}")]
+    except Exception as e:
+        return [TextContent(type="text", text=f"Error building call graph: {e}")]
+
+
+@mcp.tool(
+    tags={"meta", "schema"},
+    description="JSON Schema (draft 2020-12) of the output_format=\"json\" results - for building typed clients"
+)
+def get_result_schema() -> list[TextContent]:
+    """
+    Return the JSON Schema describing output_format="json" results.
 
-    def node_to_dict(node: StructureNode) -> dict:
-        """Convert a single node to dictionary."""
-        result = {
-            "type": node.type,
-            "name": node.name,
-            "start_line": node.start_line,
-            "end_line": node.end_line,
+    Covers one file result (scan_file, scan_file_content) and the path-keyed
+    map of file results (scan_directory, search_structures).
+
+    Returns:
+        JSON Schema document (draft 2020-12)
+    """
+    return [TextContent(type="text", text=json.dumps(result_schema(), indent=2))]
+
+
+# Every output_format value some tool accepts; each tool's docstring says which
+_OUTPUT_FORMATS = {
+    "tree": "compact text for reading (the default of most tools)",
+    "json": "structured result; scan results follow get_result_schema",
+    "json-stable": "json with sorted keys and nodes, for snapshot diffs",
+    "sarif": "SARIF 2.1.0 findings (scan_directory)",
+    "csv": "one RFC 4180 row per symbol, for spreadsheets (scan_directory)",
+    "lsp": "LSP DocumentSymbol[] of textDocument/documentSymbol (scan_file, scan_file_content)",
+    "mermaid": "Mermaid diagram source (class_diagram)",
+    "markdown": "Mermaid source in a fenced block (class_diagram)",
+}
+
+
+def _tool_options() -> dict[str, dict]:
+    """Per registered tool of this module: its option names, required first."""
+    tools = {}
+    for tool in list(globals().values()):
+        fn = getattr(tool, "fn", None)
+        if not inspect.isfunction(fn) or fn.__module__ != __name__:
+            continue
+        parameters = inspect.signature(fn).parameters.values()
+        tools[fn.__name__] = {
+            "required": [p.name for p in parameters if p.default is inspect.Parameter.empty],
+            "optional": [p.name for p in parameters if p.default is not inspect.Parameter.empty],
         }
+    return dict(sorted(tools.items()))
+
+
+def _languages() -> list[dict]:
+    """Registered language handlers with their extensions; plugins are the
+    ones registered from outside this package."""
+    registry = scanner.registry
+    extensions: dict[type, list[str]] = {}
+    for extension in registry.get_supported_extensions():
+        extensions.setdefault(registry.get_class(extension), []).append(extension)
+    return sorted(({"name": cls.get_language_name(), "extensions": exts,
+                    "plugin": not cls.__module__.startswith("scantool.languages")}
+                   for cls, exts in extensions.items()),
+                  key=lambda language: language["name"].lower())
+
+
+@mcp.tool(
+    tags={"meta"},
+    description="What this server supports, answered from memory: version, registered language parsers (plugins included) with their extensions, output formats, symbol kinds, verbosity levels and every tool's option names"
+)
+def capabilities() -> list[TextContent]:
+    """
+    Describe the server for clients that adapt at runtime.
+
+    Languages come from the live registry, so parsers registered by a
+    plugin or register_language() show up (flagged "plugin"). Nothing is
+    read from disk.
+
+    Returns:
+        JSON: {server, version, languages: [{name, extensions, plugin}],
+        output_formats, kinds, verbosity, tools: {name: {required, optional}}}
+    """
+    return [TextContent(type="text", text=json.dumps({
+        "server": mcp.name,
+        "version": __version__,
+        "languages": _languages(),
+        "output_formats": _OUTPUT_FORMATS,
+        "kinds": list(KIND_NODE_TYPES),
+        "verbosity": list(VERBOSITY_LEVELS),
+        "tools": _tool_options(),
+    }, indent=2))]
+
+
+# ── Resources ────────────────────────────────────────────────────────────────
+# scan://<relative-path> resolves against _RESOURCE_ROOT; the listing is paged
+# through scan-index:// (see scan_resources for the cursor scheme)
+
+@mcp.resource(
+    f"{SCAN_SCHEME}://{{path*}}",
+    mime_type="application/json",
+    tags={"exploration", "resource"},
+    description="Structure of one file under the resource root as the JSON file result "
+                "(same shape as scan_file output_format=\"json\")"
+)
+def scan_resource(path: str) -> str:
+    file_path = resolve_resource_path(path, _RESOURCE_ROOT)
+    structures = scanner.scan_file(str(file_path), root=str(_RESOURCE_ROOT))
+    if structures is None:
+        raise ValueError(f"Unsupported file type: {path}")
+    return _structures_to_json(structures, file_path.relative_to(_RESOURCE_ROOT).as_posix())
+
+
+@mcp.resource(
+    INDEX_URI,
+    mime_type="application/json",
+    tags={"exploration", "resource"},
+    description="First page of scan:// resources under the resource root; continue "
+                "with scan-index://files/<nextCursor> until nextCursor is null"
+)
+def scan_index() -> str:
+    return json.dumps(list_resource_page(scanner, _RESOURCE_ROOT), indent=2)
+
+
+@mcp.resource(
+    f"{INDEX_URI}/{{cursor}}",
+    mime_type="application/json",
+    tags={"exploration", "resource"},
+    description="Next page of scan:// resources, after the page that returned the cursor"
+)
+def scan_index_page(cursor: str) -> str:
+    return json.dumps(list_resource_page(scanner, _RESOURCE_ROOT, cursor), indent=2)
+
+
+# ── Prompts ──────────────────────────────────────────────────────────────────
+# Guided workflows built from the analysis tools' findings (prompts/list)
+
+@mcp.prompt(
+    name="review_brief",
+    tags={"local", "review"},
+    description="Code-review brief for a directory: its hotspots, functions of high cyclomatic "
+                "complexity, exported Go symbols without doc comments and TODO/FIXME/HACK "
+                "markers, with file:line locations and what the review should deliver"
+)
+def review_brief(
+    directory: str,
+    min_complexity: int = DEFAULT_MIN_COMPLEXITY,
+    top: int = DEFAULT_TOP
+) -> str:
+    """
+    Assemble a review brief from a scan of directory.
+
+    Args:
+        directory: Directory to review
+        min_complexity: Lowest cyclomatic complexity listed (default: 10)
+        top: Entries per section, the rest counted (default: 10)
+    """
+    try:
+        if top < 1:
+            raise ValueError(f"top must be at least 1, got {top}")
+        if not Path(directory).is_dir():
+            raise FileNotFoundError(f"Directory not found: {directory}")
+        # One bounded walk: the Go checks read the scan's own .go files
+        results = _complete_scan(directory)
+        brief = build_review_brief(directory, results, go_files_of_scan(results),
+                                   min_complexity=min_complexity, top=top)
+        return format_review_brief(brief, top=top)
+    except FileNotFoundError as e:
+        return f"Error: {e}"
+    except Exception as e:
+        return f"Error building review brief: {e}"
+
+
+def _filter_structures(
+    structures: list[StructureNode],
+    type_filter: Optional[str] = None,
+    name_pattern: Optional[str] = None,
+    has_decorator: Optional[str] = None,
+    min_complexity: Optional[int] = None
+) -> list[StructureNode]:
+    """Filter structures based on criteria."""
+    results = []
+
+    for node in structures:
+        # Check filters
+        match = True
+
+        if type_filter and node.type != type_filter:
+            match = False
 
-        if node.signature:
-            result["signature"] = node.signature
-        if node.decorators:
-            result["decorators"] = node.decorators
-        if node.docstring:
-            result["docstring"] = node.docstring
-        if node.modifiers:
-            result["modifiers"] = node.modifiers
-        if node.complexity:
-            result["complexity"] = node.complexity
+        if name_pattern and not re.search(name_pattern, node.name):
+            match = False
+
+        if has_decorator and (not node.decorators or not any(has_decorator in d for d in node.decorators)):
+            match = False
+
+        if min_complexity and node.complexity:
+            if node.complexity.get("lines", 0) < min_complexity:
+                match = False
+
+        if match:
+            results.append(node)
+
+        # Recurse into children
         if node.children:
-            result["children"] = [node_to_dict(child) for child in node.children]
+            filtered_children = _filter_structures(
+                node.children,
+                type_filter=type_filter,
+                name_pattern=name_pattern,
+                has_decorator=has_decorator,
+                min_complexity=min_complexity
+            )
+            results.extend(filtered_children)
+
+    return results
+
+
+def _skipped_note(results: dict) -> str:
+    """One line listing files the scan did not parse, with the reason."""
+    skipped = skipped_files(results)
+    if not skipped:
+        return ""
+    entries = ", ".join(
+        f"{Path(f.path).name} ({f.reason}, {f.detail})" if f.reason == SKIP_TOO_LARGE
+        else f"{Path(f.path).name} ({f.reason})"
+        for f in skipped)
+    return f"\nskipped ({len(skipped)} files, not parsed): {entries}"
+
+
+def _parse_timestamp(option: str, value: str) -> datetime:
+    """An RFC 3339 timestamp ("Z" or an offset; without one, local time);
+    ValueError naming the option otherwise."""
+    try:
+        return datetime.fromisoformat(value)
+    except ValueError:
+        raise ValueError(f"{option} must be an RFC 3339 timestamp like "
+                         f"2024-05-01T09:00:00Z, got {value!r}") from None
+
+
+def _dump_json(data, output_format: str) -> str:
+    """JSON text of a file result or path map in the requested ordering."""
+    return dumps_stable(data) if output_format == "json-stable" else json.dumps(data, indent=2)
 
-        return result
 
-    data = {
-        "file": file_path,
-        "structures": [node_to_dict(s) for s in structures]
-    }
+def _format_events(events: list[ScanEvent], directory: str, timeout: float) -> str:
+    if not events:
+        return f"No changes in {directory} within {timeout:g}s"
+    blocks = [f"{len(events)} changes in {directory}"]
+    for event in events:
+        if event.structures:
+            blocks.append(f"{event.kind}: {formatter.format(event.path, event.structures)}")
+        else:
+            blocks.append(f"{event.kind}: {event.path}")
+    return "\n\n".join(blocks)
+
+
+class ScanIncomplete(Exception):
+    """A bounded scan stopped before the scope was read whole. Raised for
+    analyses that must not answer from part of a tree (counts, hotspots,
+    symbol lookups, the Go checks); the message names the limit to raise."""
+
+
+def _limit_remedy(e: ScanCancelled) -> str:
+    """What to raise for a complete scan after e stopped it."""
+    if isinstance(e, LimitExceeded):
+        return f"${_SCAN_LIMITS[e.limit][0]} (server limit)"
+    return "$SCANTOOL_SCAN_TIMEOUT (server limit)"
+
+
+def _limited_scan(directory: str, pattern: str = "**/*", timeout: Optional[float] = None,
+                  **options) -> dict[str, Optional[list[StructureNode]]]:
+    """scanner.scan_directory under the server's defensive limits: the scan
+    timeout, _SCAN_LIMITS and _FILE_TIMEOUT_SECONDS. Every directory scan
+    of a tool goes through here; ScanCancelled passes through."""
+    return scanner.scan_directory(
+        directory, pattern,
+        cache=scan_cache,
+        timeout=timeout if timeout is not None else _SCAN_TIMEOUT_SECONDS,
+        file_timeout=_FILE_TIMEOUT_SECONDS or None,
+        **{limit: value or None for limit, (_, value) in _SCAN_LIMITS.items()},
+        **options)
+
 
+def _complete_scan(directory: str, pattern: str = "**/*",
+                   **options) -> dict[str, Optional[list[StructureNode]]]:
+    """_limited_scan for tools that need the whole scope: a stopped scan is a
+    ScanIncomplete error, not partial results."""
+    try:
+        return _limited_scan(directory, pattern, **options)
+    except ScanCancelled as e:
+        raise ScanIncomplete(f"scan {e.reason} — narrow the scope or raise "
+                             f"{_limit_remedy(e)} for a complete result") from e
+
+
+def _load_go(path: str, respect_gitignore: bool = True) -> list[GoFile]:
+    """load_go_files under the server's file count, byte and time limits (a
+    load past one is a ScanIncomplete error)."""
+    try:
+        return load_go_files(path, respect_gitignore=respect_gitignore,
+                             max_files=_SCAN_LIMITS["max_files"][1] or None,
+                             max_total_bytes=_SCAN_LIMITS["max_total_bytes"][1] or None,
+                             timeout=_SCAN_TIMEOUT_SECONDS or None)
+    except ScanCancelled as e:
+        raise ScanIncomplete(f"Go file load {e.reason} — narrow the scope or raise "
+                             f"{_limit_remedy(e)} for a complete result") from e
+
+
+def _source_positions(structures: list[StructureNode], file_path: str,
+                      key: str) -> Optional[SourcePositions]:
+    """Positions of a parsed file's source; None for skipped and unsupported
+    stubs (too large, binary, unreadable, directories below max_depth),
+    whose path may not even be a readable file."""
+    if skip_reason(structures) or is_unsupported_stub(structures):
+        return None
+    try:
+        return SourcePositions(Path(file_path).read_bytes(), key)
+    except OSError:
+        return None
+
+
+def _structures_to_json(structures: list[StructureNode], file_path: str, return_dict: bool = False,
+                        positions: Optional[SourcePositions] = None):
+    """Convert structures to JSON format."""
+    data = file_to_dict(structures, file_path, positions)
     return data if return_dict else json.dumps(data, indent=2)
 
 
@@ -0,0 +1,104 @@
+"""
+FILE: source_text.py
+
+PROBLEM:
+  Files saved on Windows come with CRLF line endings, sometimes a UTF-8
+  BOM. Tree-sitter counts rows by "\n" only: a lone "\r" (old Mac
+  endings, mixed files) joins two lines the editor shows apart, and a BOM
+  is an unexpected character before the first token of the file.
+
+SOLUTION:
+  normalize_source() runs on the bytes of a text file before parsing:
+    - a leading UTF-8 BOM is dropped
+    - "\r\n" and lone "\r" become "\n"
+  Every line break stays exactly one line break, so line N of the result
+  is line N in the editor — reported lines don't shift. Byte offsets are
+  the one position that does: restore_offsets() maps them back onto the
+  file's bytes after the scan. UTF-16 columns need no mapping — the
+  dropped "\r" sits at a line end, and editors don't count the BOM
+  either. Files with neither come back as the same object, no copy.
+
+  Bytes that aren't valid UTF-8 (a Latin-1 comment, a truncated write)
+  parse all the same — tree-sitter tolerates them — but every name,
+  signature and excerpt cut from them shows U+FFFD where they were.
+  encoding_warning() says so, with the first bad byte, so a result can
+  flag the file instead of showing garbled text silently.
+
+SCOPE:
+  ✓ scan_file, scan_content, the symbol index and the Go analyses
+  ✓ encoding_warning in scan_file and directory scans
+  ✗ UTF-16/32 files are left as they are
+"""
+
+from bisect import bisect_left
+from typing import Optional
+
+_UTF8_BOM = b"\xef\xbb\xbf"
+
+# What scan_file does with a file that isn't valid UTF-8: parse it and
+# record an encoding_warning, or list it unparsed as skipped
+ENCODING_MODES = ("warn", "skip")
+
+
+def normalize_source(source: bytes) -> bytes:
+    """source without a leading UTF-8 BOM and with "\n" line endings."""
+    if source.startswith(_UTF8_BOM):
+        source = source[len(_UTF8_BOM):]
+    if b"\r" in source:
+        source = source.replace(b"\r\n", b"\n").replace(b"\r", b"\n")
+    return source
+
+
+def restore_offsets(structures: list, original: bytes) -> None:
+    """Byte offsets of structures, scanned from normalize_source(original),
+    made offsets into original again (children too)."""
+    bom = len(_UTF8_BOM) if original.startswith(_UTF8_BOM) else 0
+    # Normalized offset of each "\n" whose "\r" was dropped
+    dropped, index = [], original.find(b"\r\n", bom)
+    while index != -1:
+        dropped.append(index - bom - len(dropped))
+        index = original.find(b"\r\n", index + 2)
+    if not bom and not dropped:
+        return
+
+    def file_offset(offset: int) -> int:
+        return offset + bom + bisect_left(dropped, offset)
+
+    def walk(nodes: list) -> None:
+        for node in nodes:
+            if node.start_offset is not None:
+                node.start_offset = file_offset(node.start_offset)
+            if node.end_offset is not None:
+                node.end_offset = file_offset(node.end_offset)
+            if node.name_span is not None:
+                node.name_span = (file_offset(node.name_span[0]), file_offset(node.name_span[1]))
+            if node.body_span is not None:
+                node.body_span = (file_offset(node.body_span[0]), file_offset(node.body_span[1]))
+            if node.children:
+                walk(node.children)
+
+    walk(structures)
+
+
+def check_encoding_mode(invalid_encoding: str) -> None:
+    if invalid_encoding not in ENCODING_MODES:
+        raise ValueError(f"Unknown invalid_encoding {invalid_encoding!r} — "
+                         f"use one of: {', '.join(ENCODING_MODES)}")
+
+
+def encoding_warning(source: bytes) -> Optional[str]:
+    """What is wrong with source as UTF-8 — how many invalid sequences,
+    where the first one is — or None when it decodes cleanly."""
+    try:
+        source.decode("utf-8")
+        return None
+    except UnicodeDecodeError as e:
+        first = e.start
+    # One U+FFFD per invalid sequence, less the ones the file spells out
+    count = (source.decode("utf-8", errors="replace").count("\ufffd")
+             - source.count("\ufffd".encode("utf-8")))
+    line = source.count(b"\n", 0, first) + 1
+    column = first - (source.rfind(b"\n", 0, first) + 1) + 1
+    sequences = "sequence" if count == 1 else "sequences"
+    return (f"invalid UTF-8: {count} {sequences}, first at line {line}, column {column} "
+            f"(byte {first}); text shows U+FFFD there")
@@ -0,0 +1,46 @@
+"""
+FILE: stable_json.py
+
+PROBLEM:
+  Scan results get diffed in tests and hashed for caches. The default JSON
+  keeps source and walk order, which is right for reading but ties the
+  bytes to details like which optional keys a node happened to get first.
+
+SOLUTION:
+  A canonical serialization for output_format="json-stable": every object
+  has its keys sorted, directory results are keyed in sorted path order,
+  and node lists ("structures", "children") are ordered by (start line,
+  name, type) — the file-info node stays first. Same data as "json", only
+  the order differs, so the result schema holds for both.
+
+SCOPE:
+  ✓ Byte-identical output for identical scan results
+  ✗ Paths are kept as scanned — absolute paths still differ between machines
+"""
+
+import json
+
+_NODE_LISTS = ("structures", "children")
+
+
+def _node_key(node) -> tuple:
+    if not isinstance(node, dict):
+        return (1, 0, "", "")
+    return (node.get("type") != "file-info", node.get("start_line", 0),
+            node.get("name", ""), node.get("type", ""))
+
+
+def stabilize(data):
+    """data with node lists ordered; key order is left to dumps_stable."""
+    if isinstance(data, dict):
+        return {key: (sorted((stabilize(item) for item in value), key=_node_key)
+                      if key in _NODE_LISTS and isinstance(value, list) else stabilize(value))
+                for key, value in data.items()}
+    if isinstance(data, list):
+        return [stabilize(item) for item in data]
+    return data
+
+
+def dumps_stable(data) -> str:
+    """Canonical JSON text of a scan result (file result or path map)."""
+    return json.dumps(stabilize(data), indent=2, sort_keys=True)
@@ -0,0 +1,142 @@
+"""
+FILE: symbol_counts.py
+
+PROBLEM:
+  Before a token-heavy scan of an unfamiliar repo the useful question is
+  "how big is it?" — how many functions, types and lines, and where. A
+  full scan answers it, but its payload is every symbol with signatures
+  and skeletons, most of which the caller is about to throw away.
+
+SOLUTION:
+  Scan as usual (parsing still happens, the result cache applies) and
+  keep only aggregates: per file the number of nodes of each type at any
+  depth (function, method, class, struct, ...), the file-info line
+  breakdown and the parse error count; then the sums over all files.
+
+SCOPE:
+  ✓ Every language the scanner parses; counts are by node type as the
+    language reports it, so "function" in Python and Go mean the same
+  ✗ Files listed but not parsed (unsupported, too large, binary) are
+    counted as skipped, with no kinds or lines
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from .languages import StructureNode, is_unsupported_stub, skip_reason
+
+# Nodes that are bookkeeping, not declarations
+_NON_SYMBOL_TYPES = {"file-info", "imports", "error", "parse-error"}
+
+
+@dataclass
+class FileCounts:
+    file: str
+    language: Optional[str] = None
+    lines: Optional[dict] = None  # line_counts.count_lines breakdown
+    counts: dict[str, int] = field(default_factory=dict)  # node type -> nodes, any depth
+    parse_errors: int = 0
+
+    def to_dict(self) -> dict:
+        return {"file": self.file, "language": self.language, "lines": self.lines,
+                "counts": self.counts, "parse_errors": self.parse_errors}
+
+
+@dataclass
+class CountTotals:
+    files: int = 0
+    skipped: int = 0  # listed, not parsed
+    lines: dict[str, int] = field(default_factory=dict)
+    counts: dict[str, int] = field(default_factory=dict)
+    parse_errors: int = 0
+
+    def to_dict(self) -> dict:
+        return {"files": self.files, "skipped": self.skipped, "lines": self.lines,
+                "counts": self.counts, "parse_errors": self.parse_errors}
+
+
+def _ordered(counts: dict[str, int]) -> dict[str, int]:
+    """Most frequent kind first, ties by name."""
+    return dict(sorted(counts.items(), key=lambda item: (-item[1], item[0])))
+
+
+def count_file(file_path: str, structures: list[StructureNode]) -> FileCounts:
+    """Node counts by type, line breakdown and parse errors of one scan."""
+    entry = FileCounts(file_path)
+    if structures and structures[0].type == "file-info":
+        metadata = structures[0].file_metadata or {}
+        entry.language = metadata.get("language")
+        entry.lines = metadata.get("lines")
+    counts: dict[str, int] = {}
+
+    def walk(nodes: list[StructureNode]):
+        for node in nodes:
+            if node.type == "parse-error":
+                entry.parse_errors += 1
+            elif node.type not in _NON_SYMBOL_TYPES:
+                counts[node.type] = counts.get(node.type, 0) + 1
+            walk(node.children)
+
+    walk(structures)
+    entry.counts = _ordered(counts)
+    return entry
+
+
+def count_symbols(results: dict[str, Optional[list[StructureNode]]]
+                  ) -> tuple[list[FileCounts], CountTotals]:
+    """Per parsed file of a directory scan, by path, and the totals."""
+    files, totals = [], CountTotals()
+    for file_path in sorted(results):
+        structures = results[file_path]
+        if structures is None:
+            continue
+        if is_unsupported_stub(structures) or skip_reason(structures) is not None:
+            totals.skipped += 1
+            continue
+        entry = count_file(file_path, structures)
+        files.append(entry)
+        totals.files += 1
+        totals.parse_errors += entry.parse_errors
+        for kind, count in entry.counts.items():
+            totals.counts[kind] = totals.counts.get(kind, 0) + count
+        for key, count in (entry.lines or {}).items():
+            totals.lines[key] = totals.lines.get(key, 0) + count
+    totals.counts = _ordered(totals.counts)
+    return files, totals
+
+
+def _kinds(counts: dict[str, int]) -> str:
+    return ", ".join(f"{count} {kind}" for kind, count in counts.items()) or "no symbols"
+
+
+def format_counts(files: list[FileCounts], totals: CountTotals, directory: str,
+                  per_file: bool = True) -> str:
+    """Totals line, then "file  N lines: counts" per file unless per_file
+    is off."""
+    if not files:
+        return f"No parsed files in {directory}"
+
+    root = Path(directory).resolve()
+
+    def shown(file_path: str) -> str:
+        try:
+            return Path(file_path).resolve().relative_to(root).as_posix()
+        except ValueError:
+            return file_path
+
+    summary = f"{totals.files} files"
+    if totals.skipped:
+        summary += f" ({totals.skipped} skipped)"
+    if "total" in totals.lines:
+        summary += f", {totals.lines['total']} lines"
+    lines = [f"{summary} in {directory}: {_kinds(totals.counts)}"]
+    if totals.parse_errors:
+        lines.append(f"{totals.parse_errors} parse errors")
+    if per_file:
+        lines.append("")
+        for entry in files:
+            size = f"  {entry.lines['total']} lines" if entry.lines and "total" in entry.lines else ""
+            errors = f" [{entry.parse_errors} parse errors]" if entry.parse_errors else ""
+            lines.append(f"{shown(entry.file)}{size}: {_kinds(entry.counts)}{errors}")
+    return "\n".join(lines)
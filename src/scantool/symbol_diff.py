@@ -0,0 +1,130 @@
+"""
+FILE: symbol_diff.py
+
+PROBLEM:
+  scan_diff answers "what changed since a git ref" per file and node name.
+  Two trees that are not one repo's history — a vendored copy and its
+  upstream, two release tarballs, a generated tree before and after
+  regeneration — have no ref to diff against, and a function that moved
+  to another file of its package shows up as removed here, new there.
+
+SOLUTION:
+  Diff two scans by symbol identity: every declaration keyed by its
+  symbol ID (symbol_ids) and the directory of its file relative to the
+  tree root, so a Go function moved between files of one package is the
+  same symbol. A symbol in only one scan is added or removed; one in both
+  is modified when its signature or its body hash (whitespace-normalized
+  source, delta.block_hash) differs.
+
+SCOPE:
+  ✓ Any language the scanner understands; nested members are symbols of
+    their own (a changed method also modifies its class)
+  ✓ Moves within a directory are not changes; the file is reported per side
+  ✗ Renames are a removal plus an addition — identity is the name
+  ✗ Symbols without an ID (scans that skipped assign_symbol_ids) are ignored
+"""
+
+import os
+from dataclasses import dataclass, field
+from typing import Optional
+
+from .symbol_search import SymbolLocation, index_symbols
+
+
+@dataclass
+class SymbolChange:
+    """One symbol present in both scans whose signature or body differs."""
+
+    before: SymbolLocation
+    after: SymbolLocation
+    changes: list[str]  # "signature" and/or "body"
+
+    def to_dict(self) -> dict:
+        return {"id": self.after.symbol_id, "changes": self.changes,
+                "before": _location_dict(self.before), "after": _location_dict(self.after)}
+
+
+@dataclass
+class SymbolDiff:
+    added: list[SymbolLocation] = field(default_factory=list)
+    removed: list[SymbolLocation] = field(default_factory=list)
+    modified: list[SymbolChange] = field(default_factory=list)
+    unchanged: int = 0
+
+    def to_dict(self) -> dict:
+        return {
+            "added": [_location_dict(loc) for loc in self.added],
+            "removed": [_location_dict(loc) for loc in self.removed],
+            "modified": [change.to_dict() for change in self.modified],
+            "unchanged": self.unchanged,
+        }
+
+
+def _location_dict(loc: SymbolLocation) -> dict:
+    return {"id": loc.symbol_id, "file": loc.file, "type": loc.type,
+            "name": loc.qualified_name, "start_line": loc.start_line,
+            "end_line": loc.end_line, "signature": loc.signature}
+
+
+def _symbols_by_key(results: dict,
+                    root: Optional[str]) -> dict[tuple[str, str], SymbolLocation]:
+    symbols = {}
+    for loc in index_symbols(results):
+        if not loc.symbol_id:
+            continue
+        path = os.path.relpath(loc.file, root) if root else loc.file
+        symbols[(os.path.dirname(path), loc.symbol_id)] = loc
+    return symbols
+
+
+def diff_scans(before: dict, after: dict, before_root: Optional[str] = None,
+               after_root: Optional[str] = None) -> SymbolDiff:
+    """Added, removed and modified symbols between two scan_directory
+    results. Roots make the two sides' paths comparable; without them
+    file paths are compared as given."""
+    old = _symbols_by_key(before, before_root)
+    new = _symbols_by_key(after, after_root)
+    diff = SymbolDiff()
+    for key, loc in new.items():
+        previous = old.get(key)
+        if previous is None:
+            diff.added.append(loc)
+            continue
+        changes = []
+        if previous.signature != loc.signature:
+            changes.append("signature")
+        if previous.body_hash != loc.body_hash:
+            changes.append("body")
+        if changes:
+            diff.modified.append(SymbolChange(previous, loc, changes))
+        else:
+            diff.unchanged += 1
+    diff.removed = [loc for key, loc in old.items() if key not in new]
+    return diff
+
+
+def format_symbol_diff(diff: SymbolDiff) -> str:
+    """Tree view: one section per change kind, one line per symbol."""
+    if not (diff.added or diff.removed or diff.modified):
+        return f"No symbol changes ({diff.unchanged} unchanged)"
+
+    lines = [f"{len(diff.added)} added, {len(diff.removed)} removed, "
+             f"{len(diff.modified)} modified, {diff.unchanged} unchanged"]
+
+    def line(loc: SymbolLocation, note: str = "") -> str:
+        sig = f" {loc.signature}" if loc.signature else ""
+        return f"  {loc.type} {loc.qualified_name}{sig}  {loc.file}:{loc.start_line}{note}"
+
+    if diff.added:
+        lines.append("added:")
+        lines.extend(line(loc) for loc in diff.added)
+    if diff.removed:
+        lines.append("removed:")
+        lines.extend(line(loc) for loc in diff.removed)
+    if diff.modified:
+        lines.append("modified:")
+        for change in diff.modified:
+            lines.append(line(change.after, f" [{', '.join(change.changes)}]"))
+            if "signature" in change.changes:
+                lines.append(f"    was: {change.before.signature or '(none)'}")
+    return "\n".join(lines)
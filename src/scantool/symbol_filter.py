@@ -0,0 +1,223 @@
+"""
+FILE: symbol_filter.py
+
+PROBLEM:
+  A full scan returns every declaration. When the question is "what is this
+  file's API", private helpers drown out what matters — and a client-side
+  filter forces every consumer to re-implement visibility rules per language.
+
+SOLUTION:
+  Output filters over the scanned StructureNode tree. The tree shape is kept
+  (same node type, name, line numbers), so filtered output flows through the
+  same formatters and JSON path as a regular scan. Visibility is decided by
+  the language (BaseLanguage.is_exported), not guessed here.
+
+  Symbol kinds (filter_kinds) are language-neutral categories over the
+  node types the languages emit: "type" is struct/class/enum/alias, "import"
+  is the import group, and so on (KIND_NODE_TYPES).
+
+  Names (filter_names) are matched with a regex searched in the name, so
+  "^Handle" and "Test$" anchor as written. Methods match by their bare
+  name, or — qualified — as Type.Method, the type being the Go receiver
+  or the enclosing class.
+
+SCOPE:
+  ✓ Pure output filters — parsing cost is unchanged (the whole file is still
+    parsed; a line window only narrows what is returned)
+  ✓ file-info metadata always survives
+"""
+
+import re
+from dataclasses import replace
+from typing import Callable, Optional
+
+from .languages import BaseLanguage, StructureNode, function_kind
+
+# Nodes that describe the file rather than declare a symbol
+_METADATA_TYPES = {"file-info"}
+
+# Symbol kind -> node types counted as that kind, across languages
+KIND_NODE_TYPES: dict[str, set[str]] = {
+    "function": {"function"},
+    "method": {"method", "constructor"},
+    "type": {"struct", "class", "type", "type-alias", "typealias", "typedef", "enum",
+             "record", "union"},
+    "interface": {"interface", "protocol", "trait"},
+    "const": {"const", "constant"},
+    "var": {"var", "variable", "property"},
+    "import": {"imports", "import", "use", "using", "require"},
+    "macro": {"macro"},
+    "key": {"key"},
+}
+
+
+def filter_exported(structures: list[StructureNode],
+                    language: BaseLanguage) -> list[StructureNode]:
+    """Keep only exported declarations (and exported members and fields of
+    exported containers). Imports, parse errors and private nodes are
+    dropped with their whole subtree. Returns new nodes, the input
+    untouched."""
+
+    def keep(nodes: list[StructureNode],
+             parent: Optional[StructureNode]) -> list[StructureNode]:
+        kept = []
+        for node in nodes:
+            if node.type in _METADATA_TYPES:
+                kept.append(node)
+                continue
+            if node.type in ("imports", "error", "parse-error"):
+                continue
+            if not language.is_exported(node, parent):
+                continue
+            fields = node.fields
+            if fields is not None:
+                fields = [f for f in fields if language.is_exported_field(f)]
+            kept.append(replace(node, children=keep(node.children, node), fields=fields))
+        return kept
+
+    return keep(structures, None)
+
+
+def cyclomatic_complexity(node: StructureNode) -> Optional[int]:
+    """Cyclomatic complexity of a function node. Exact where the language
+    computes it (complexity["cyclomatic"], Go); otherwise approximated as
+    branches + 1. None for nodes without complexity data."""
+    if not node.complexity:
+        return None
+    if "cyclomatic" in node.complexity:
+        return node.complexity["cyclomatic"]
+    return node.complexity.get("branches", 0) + 1
+
+
+def filter_min_complexity(structures: list[StructureNode],
+                          threshold: int) -> list[StructureNode]:
+    """Keep only functions/methods with cyclomatic complexity >= threshold.
+    Containers (classes, ...) survive only as the path to a kept member.
+    Returns new nodes, the input untouched."""
+
+    def keep(nodes: list[StructureNode]) -> list[StructureNode]:
+        kept = []
+        for node in nodes:
+            if node.type in _METADATA_TYPES:
+                kept.append(node)
+                continue
+            children = keep(node.children)
+            score = cyclomatic_complexity(node)
+            is_function = node.type in ("function", "method")
+            if (is_function and score is not None and score >= threshold) or children:
+                kept.append(replace(node, children=children))
+        return kept
+
+    return keep(structures)
+
+
+def filter_line_range(structures: list[StructureNode], start_line: Optional[int] = None,
+                      end_line: Optional[int] = None) -> list[StructureNode]:
+    """Keep only nodes whose declaration range overlaps [start_line, end_line]
+    (1-based, inclusive; a missing bound is open). Overlap, not containment:
+    a function declared above the window whose body runs into it is kept,
+    and so is a container spanning the window — with its members narrowed
+    to the window too. Returns new nodes, the input untouched."""
+    low = start_line if start_line is not None else 1
+    high = end_line if end_line is not None else float("inf")
+
+    def keep(nodes: list[StructureNode]) -> list[StructureNode]:
+        kept = []
+        for node in nodes:
+            if node.type in _METADATA_TYPES:
+                kept.append(node)
+                continue
+            if node.start_line > high or node.end_line < low:
+                continue
+            kept.append(replace(node, children=keep(node.children)))
+        return kept
+
+    return keep(structures)
+
+
+def check_kinds(kinds: Optional[list[str]]) -> None:
+    """ValueError for a kind filter_kinds doesn't know."""
+    unknown = [kind for kind in kinds or [] if kind not in KIND_NODE_TYPES]
+    if unknown:
+        raise ValueError(f"Unknown symbol kind(s) {', '.join(map(repr, unknown))} — "
+                         f"use {', '.join(KIND_NODE_TYPES)}")
+
+
+def _filter_matching(structures: list[StructureNode],
+                     matches: Callable[[StructureNode, Optional[StructureNode]], bool]
+                     ) -> list[StructureNode]:
+    """Nodes matches(node, parent) accepts, the others only as the path to
+    one; new nodes, the input untouched."""
+
+    def keep(nodes: list[StructureNode],
+             parent: Optional[StructureNode]) -> list[StructureNode]:
+        kept = []
+        for node in nodes:
+            if node.type in _METADATA_TYPES or node.type == "error":
+                kept.append(node)
+                continue
+            children = keep(node.children, node)
+            if matches(node, parent) or children:
+                kept.append(replace(node, children=children))
+        return kept
+
+    return keep(structures, None)
+
+
+def filter_kinds(structures: list[StructureNode], kinds: list[str]) -> list[StructureNode]:
+    """Keep only nodes of the given symbol kinds (KIND_NODE_TYPES). Other
+    nodes survive only as the path to a kept one; a kept node's members are
+    narrowed too. Returns new nodes and leaves the input untouched, so
+    cached scan results can be filtered."""
+    node_types = set().union(*(KIND_NODE_TYPES[kind] for kind in kinds))
+    return _filter_matching(structures, lambda node, parent: node.type in node_types)
+
+
+def compile_name_pattern(name_pattern: Optional[str]) -> Optional[re.Pattern]:
+    """name_pattern compiled for filter_names (None stays None); ValueError
+    saying what is wrong with an invalid regex."""
+    if name_pattern is None:
+        return None
+    try:
+        return re.compile(name_pattern)
+    except re.error as e:
+        raise ValueError(f"Invalid name_pattern {name_pattern!r}: {e}") from None
+
+
+def qualified_name(node: StructureNode, parent: Optional[StructureNode] = None) -> str:
+    """Type.Method for a method with a receiver or an enclosing type, the
+    bare name for everything else."""
+    owner = node.receiver_type or (parent.name if parent is not None else None)
+    if function_kind(node) == "method" and owner:
+        return f"{owner}.{node.name}"
+    return node.name or ""
+
+
+def _name_matches(pattern: re.Pattern, qualified: bool):
+    def matches(node: StructureNode, parent: Optional[StructureNode]) -> bool:
+        name = qualified_name(node, parent) if qualified else node.name or ""
+        return pattern.search(name) is not None
+    return matches
+
+
+def filter_names(structures: list[StructureNode], pattern: re.Pattern,
+                 qualified: bool = False) -> list[StructureNode]:
+    """Keep only nodes whose name the pattern matches (re.search; methods
+    as Type.Method when qualified), other nodes as the path to one, like
+    filter_kinds."""
+    return _filter_matching(structures, _name_matches(pattern, qualified))
+
+
+def filter_symbols(structures: list[StructureNode], kinds: Optional[list[str]] = None,
+                   names: Optional[re.Pattern] = None,
+                   qualified: bool = False) -> list[StructureNode]:
+    """filter_kinds and filter_names in one pass, as the scan tools combine
+    them: a node is kept when it is of one of kinds and its name matches."""
+    if not kinds:
+        return filter_names(structures, names, qualified) if names is not None else structures
+    if names is None:
+        return filter_kinds(structures, kinds)
+    node_types = set().union(*(KIND_NODE_TYPES[kind] for kind in kinds))
+    name_matches = _name_matches(names, qualified)
+    return _filter_matching(structures, lambda node, parent: (
+        node.type in node_types and name_matches(node, parent)))
@@ -0,0 +1,84 @@
+"""
+FILE: symbol_ids.py
+
+PROBLEM:
+  Clients that cache scan results need to point at "that function" across
+  calls. Line numbers are the only handle a scan gives, and they shift with
+  every edit above the declaration.
+
+SOLUTION:
+  A deterministic ID per declaration built only from its identity:
+  kind, namespace and qualified name — "method:internal/users.Service.Get".
+  The namespace is the package path relative to the scan root: for Go the
+  file's directory (an import path below the module root; the package
+  name for files in the root itself), elsewhere the file's path without
+  its suffix ("pkg/utils"). Go methods are qualified by their receiver
+  type (BaseLanguage.owner_name); nested members by their container chain.
+
+  Each declaration also gets a body hash (delta.block_hash of its source
+  lines), so two scans can tell "same symbol, edited" from "same symbol".
+  It is a text hash, not an AST rendering: trailing whitespace and blank
+  lines are ignored, comment and indentation edits are not.
+
+SCOPE:
+  ✓ Same declaration → same ID across scans, wherever it moves in the file
+  ✓ Repeated identities in one file (Go's several init()) get "#2", "#3"
+    in declaration order
+  ✓ Unique within a scan root: two "package util" directories or two
+    utils.py files get different namespaces
+  ✗ Relative to the root: the same file scanned from another root (or on
+    its own, where the root is its directory) gets another namespace
+"""
+
+import os
+from pathlib import Path
+from typing import Optional
+
+from .delta import block_hash
+from .languages import BaseLanguage, StructureNode
+
+# Nodes that describe the file or group statements rather than declare a symbol
+_NON_SYMBOL_TYPES = {"file-info", "imports", "error", "parse-error"}
+
+
+def symbol_id(kind: str, namespace: str, qualified_name: str) -> str:
+    return f"{kind}:{namespace}.{qualified_name}"
+
+
+def symbol_namespace(file_path: str, root: Optional[str], package: Optional[str]) -> str:
+    """The package path of file_path below root (None: the file's own
+    directory), "/"-separated. package is the name the file declares, for
+    languages that have one (Go): the directory is the package then, and
+    an external test package ("users_test") keeps its suffix."""
+    relative = Path(os.path.relpath(file_path, root)) if root is not None \
+        else Path(Path(file_path).name)
+    directory = relative.parent.as_posix()
+    if package is None:
+        return relative.with_suffix("").as_posix()
+    if directory == ".":
+        return package
+    return directory + "_test" if package.endswith("_test") else directory
+
+
+def assign_symbol_ids(structures: list[StructureNode], file_path: str,
+                      language: BaseLanguage, source_code: bytes,
+                      root: Optional[str] = None) -> None:
+    """Set node.symbol_id and node.body_hash on every declaration node, in
+    place. root: the scan root namespaces are relative to (symbol_namespace)."""
+    namespace = symbol_namespace(file_path, root, language.extract_namespace(source_code))
+    source_lines = source_code.decode("utf-8", errors="replace").split("\n")
+    seen: dict[str, int] = {}
+
+    def assign(nodes: list[StructureNode], container: Optional[str]):
+        for node in nodes:
+            if node.type in _NON_SYMBOL_TYPES:
+                continue
+            owner = container or language.owner_name(node)
+            qualified = f"{owner}.{node.name}" if owner else node.name
+            base = symbol_id(node.type, namespace, qualified)
+            seen[base] = seen.get(base, 0) + 1
+            node.symbol_id = base if seen[base] == 1 else f"{base}#{seen[base]}"
+            node.body_hash = block_hash(node, source_lines)
+            assign(node.children, qualified)
+
+    assign(structures, None)
@@ -0,0 +1,173 @@
+"""
+FILE: symbol_search.py
+
+PROBLEM:
+  "Where is FormatUser defined?" should be one call. search_structures
+  answers it only with a regex the caller has to get right, and it never
+  matches the way people actually name methods — `UserService.GetUser`.
+
+SOLUTION:
+  Index every scanned declaration with its location and a qualified name
+  (container chain, or the owner type for languages that declare methods
+  outside the type — BaseLanguage.owner_name). A query matches the bare
+  name or the qualified form, exactly, by prefix, or as a case-insensitive
+  substring.
+
+  symbol_table() is the same index as one flat list for tables and
+  search indexes: every symbol of the scan with its file, sorted by file
+  then line, optionally narrowed to symbol kinds.
+
+SCOPE:
+  ✓ Any language the scanner understands (same index for Go and Python)
+  ✗ Definitions only — references/call sites are code_map's job
+"""
+
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Callable, Optional
+
+from .languages import StructureNode, get_language
+from .symbol_filter import KIND_NODE_TYPES, check_kinds
+
+MATCH_MODES = ("exact", "prefix", "substring")
+
+# Nodes that describe the file or a failure rather than declare a symbol
+_NON_SYMBOL_TYPES = {"file-info", "imports", "error", "parse-error"}
+
+_MAX_RESULTS = 50
+
+
+@dataclass
+class SymbolLocation:
+    """One declaration: where it is and how it can be referred to."""
+
+    file: str
+    name: str
+    qualified_name: str      # "UserService.GetUser"; == name at top level
+    type: str                # StructureNode type: function, method, struct, ...
+    start_line: int
+    end_line: int
+    signature: Optional[str] = None
+    symbol_id: Optional[str] = None
+    body_hash: Optional[str] = None
+
+    def to_dict(self) -> dict:
+        data = {"file": self.file, "name": self.name, "qualified_name": self.qualified_name,
+                "type": self.type, "start_line": self.start_line, "end_line": self.end_line}
+        for key in ("signature", "symbol_id", "body_hash"):
+            if getattr(self, key) is not None:
+                data[key] = getattr(self, key)
+        return data
+
+
+def index_symbols(results: dict) -> list[SymbolLocation]:
+    """Flatten scan_directory output into symbol locations, in file order."""
+    index = []
+    for file_path, structures in results.items():
+        if not structures:
+            continue
+        language = get_language(Path(file_path).suffix.lower())
+
+        def walk(nodes: list[StructureNode], chain: list[str]):
+            for node in nodes:
+                if node.type in _NON_SYMBOL_TYPES:
+                    continue
+                owner = language.owner_name(node) if language and not chain else None
+                prefix = chain or ([owner] if owner else [])
+                index.append(SymbolLocation(
+                    file=file_path,
+                    name=node.name,
+                    qualified_name=".".join(prefix + [node.name]),
+                    type=node.type,
+                    start_line=node.start_line,
+                    end_line=node.end_line,
+                    signature=node.signature,
+                    symbol_id=node.symbol_id,
+                    body_hash=node.body_hash,
+                ))
+                walk(node.children, chain + [node.name])
+
+        walk(structures, [])
+    return index
+
+
+def symbol_matcher(query: str, match_mode: str = "exact") -> Callable[[SymbolLocation], bool]:
+    """Predicate: does a symbol's bare or qualified name match query.
+
+    match_mode: "exact", "prefix" (both case-sensitive) or "substring"
+    (case-insensitive). Raises ValueError for anything else.
+    """
+    if match_mode not in MATCH_MODES:
+        raise ValueError(
+            f"Unknown match_mode {match_mode!r} (expected one of {', '.join(MATCH_MODES)})")
+
+    if match_mode == "exact":
+        def matches(name: str) -> bool:
+            return name == query
+    elif match_mode == "prefix":
+        def matches(name: str) -> bool:
+            return name.startswith(query)
+    else:
+        needle = query.lower()
+
+        def matches(name: str) -> bool:
+            return needle in name.lower()
+
+    return lambda loc: matches(loc.name) or matches(loc.qualified_name)
+
+
+def find_symbol(results: dict, query: str,
+                match_mode: str = "exact") -> list[SymbolLocation]:
+    """Symbols whose bare or qualified name matches query (see
+    symbol_matcher for the modes)."""
+    matches = symbol_matcher(query, match_mode)
+    return [loc for loc in index_symbols(results) if matches(loc)]
+
+
+def symbol_table(results: dict, kinds: Optional[list[str]] = None) -> list[SymbolLocation]:
+    """Every symbol of scan_directory output as one list sorted by file,
+    then line (a container before its members); kinds narrows to symbol
+    kinds (symbol_filter.KIND_NODE_TYPES, ValueError for unknown ones)."""
+    check_kinds(kinds)
+    symbols = index_symbols(results)
+    if kinds:
+        node_types = set().union(*(KIND_NODE_TYPES[kind] for kind in kinds))
+        symbols = [s for s in symbols if s.type in node_types]
+    # Stable: symbols starting on one line keep their walk order
+    symbols.sort(key=lambda s: (s.file, s.start_line))
+    return symbols
+
+
+def format_symbol_table(symbols: list[SymbolLocation], directory: str,
+                        max_results: Optional[int] = None) -> str:
+    """One "path:line  type Qualified.name signature" line per symbol."""
+    if not symbols:
+        return f"No symbols found in {directory}"
+
+    files = len({s.file for s in symbols})
+    lines = [f"{len(symbols)} symbols in {files} files under {directory}"]
+    shown = symbols if max_results is None else symbols[:max_results]
+    for loc in shown:
+        sig = f" {loc.signature}" if loc.signature else ""
+        lines.append(f"{loc.file}:{loc.start_line}  {loc.type} {loc.qualified_name}{sig}")
+    if len(shown) < len(symbols):
+        lines.append(f"+{len(symbols) - len(shown)} more — narrow with kinds or pattern, "
+                     f"or use output_format=\"json\"")
+    return "\n".join(lines)
+
+
+def format_locations(locations: list[SymbolLocation], query: str,
+                     max_results: int = _MAX_RESULTS) -> str:
+    """One line per symbol: path:line, kind, qualified name, signature."""
+    if not locations:
+        return f"No symbols matching '{query}'"
+
+    lines = [f"{len(locations)} symbols matching '{query}'"]
+    for loc in locations[:max_results]:
+        sig = f" {loc.signature}" if loc.signature else ""
+        lines.append(f"{loc.file}:{loc.start_line}  {loc.type} {loc.qualified_name}{sig}"
+                     f" @{loc.start_line}-{loc.end_line}")
+    if len(locations) > max_results:
+        lines.append(f"+{len(locations) - max_results} more — use match_mode=\"exact\" "
+                     f"or a longer query")
+    return "\n".join(lines)
@@ -0,0 +1,110 @@
+"""
+FILE: symbol_source.py
+
+PROBLEM:
+  Once an agent knows a symbol exists it wants that declaration's source —
+  not the whole file, and not a focus view wrapped in skeleton context. A
+  guessed line range cuts off the doc comment or runs into the next
+  declaration.
+
+SOLUTION:
+  Resolve a symbol in one file by name, Type.Method or symbol ID (the
+  "id" of JSON results) to its node, and slice the source lines from the
+  node's start to its end line — the parser's end position, so the closing
+  brace is in and whatever follows is out. The doc comment directly above
+  (contiguous comment lines, per the language's comment tokens) and
+  single-line decorators are prepended.
+
+SCOPE:
+  ✓ Same lookup as find_symbol (bare or qualified name), plus symbol IDs
+  ✓ Whole lines, original indentation and line endings untouched
+  ✗ Multi-line decorators (@app.route(\\n ...)) keep only their last line
+  ✗ A declaration sharing a line with another keeps the whole line
+"""
+
+from dataclasses import dataclass
+from typing import Optional
+
+from .languages import BaseLanguage, StructureNode
+from .symbol_search import SymbolLocation, index_symbols
+
+
+@dataclass
+class SymbolSource:
+    location: SymbolLocation
+    start_line: int  # first line of the snippet (doc comment included)
+    end_line: int
+    source: str
+
+
+def _resolve(structures: list[StructureNode], file_path: str,
+             query: str) -> list[SymbolLocation]:
+    locations = index_symbols({file_path: structures})
+    if ":" in query:
+        return [loc for loc in locations if loc.symbol_id == query]
+    exact = [loc for loc in locations if loc.qualified_name == query]
+    return exact or [loc for loc in locations if loc.name == query]
+
+
+def _leading_lines(location: SymbolLocation, node: Optional[StructureNode],
+                   lines: list[bytes], comment_rows: dict[int, int]) -> int:
+    """First line of the snippet: walk up over own-line comments ending on
+    the line above (comment_rows: end row -> start row, 0-based) and over
+    decorator lines."""
+    row = location.start_line - 1  # 0-based row of the declaration
+    while row > 0:
+        above = row - 1
+        if above in comment_rows:
+            row = comment_rows[above]
+        elif node is not None and node.decorators and lines[above].lstrip().startswith(b"@"):
+            row = above
+        else:
+            break
+    return row + 1
+
+
+def _comment_rows(language: Optional[BaseLanguage], source_code: bytes,
+                  lines: list[bytes]) -> dict[int, int]:
+    """end row -> start row of comments that stand on their own lines."""
+    spans = language.comment_spans(source_code) if language else None
+    rows = {}
+    for (start_row, start_col), (end_row, _) in spans or ():
+        if start_row < len(lines) and not lines[start_row][:start_col].strip():
+            rows[end_row] = start_row
+    return rows
+
+
+def _find_node(structures: list[StructureNode], location: SymbolLocation) -> Optional[StructureNode]:
+    for node in structures:
+        if (node.name, node.start_line, node.type) == (
+                location.name, location.start_line, location.type):
+            return node
+        found = _find_node(node.children, location)
+        if found is not None:
+            return found
+    return None
+
+
+def symbol_source(structures: list[StructureNode], file_path: str, source_code: bytes,
+                  query: str, language: Optional[BaseLanguage] = None) -> SymbolSource:
+    """Source of the one symbol query names. Raises LookupError when it
+    matches nothing or several symbols (the message lists candidates)."""
+    matches = _resolve(structures, file_path, query)
+    if not matches:
+        available = ", ".join(loc.qualified_name
+                              for loc in index_symbols({file_path: structures})[:20])
+        raise LookupError(f"No symbol '{query}' in {file_path}. Symbols: {available}")
+    if len(matches) > 1:
+        listed = "\n".join(f"  {loc.symbol_id or loc.qualified_name} @{loc.start_line}"
+                           for loc in matches[:10])
+        raise LookupError(f"'{query}' is ambiguous ({len(matches)} matches) — "
+                          f"use Type.Method or a symbol ID:\n{listed}")
+
+    location = matches[0]
+    # Bytes: tree-sitter rows and columns count \n-separated lines in bytes
+    lines = source_code.splitlines(keepends=True)
+    start = _leading_lines(location, _find_node(structures, location), lines,
+                           _comment_rows(language, source_code, lines))
+    end = min(location.end_line, len(lines))
+    return SymbolSource(location=location, start_line=start, end_line=end,
+                        source=b"".join(lines[start - 1:end]).decode("utf-8", errors="replace"))
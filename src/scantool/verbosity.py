@@ -0,0 +1,80 @@
+"""
+FILE: verbosity.py
+
+PROBLEM:
+  A full file result carries docs, signatures, positions, complexity and
+  code skeletons. A client with a small context budget often only needs
+  "what is declared here" — names and kinds — and pays for the rest.
+
+SOLUTION:
+  Three output levels, applied after the scan:
+    names       type and name of every node (children kept)
+    signatures  + positions, signatures, modifiers, decorators, receivers
+    full        everything (default)
+  JSON results are filtered by key; the tree output is the same choice of
+  fields expressed as TreeFormatter options.
+
+SCOPE:
+  ✓ Pure output filter — parsing and saliency cost are unchanged
+  ✓ File-level skip reasons and parse errors survive every level
+  ✗ Directory tree output is already the compact inline view — only
+    JSON directory results are filtered
+"""
+
+VERBOSITY_LEVELS = ("names", "signatures", "full")
+
+_NAME_KEYS = {"type", "name", "children", "ref"}
+_SIGNATURE_KEYS = _NAME_KEYS | {"start_line", "end_line", "line_count", "id", "body_hash",
+                                "signature", "full_signature", "modifiers", "decorators",
+                                "visibility", "receiver_type", "receiver_kind", "func_kind",
+                                "methods", "start_offset", "end_offset",
+                                "start_utf16_column", "end_utf16_column", "deprecated",
+                                "positions"}
+_NODE_KEYS = {"names": _NAME_KEYS, "signatures": _SIGNATURE_KEYS}
+
+_FILE_NAME_KEYS = {"file", "structures", "language", "package", "build_constraint",
+                   "encoding_warning", "skipped", "parse_errors"}
+_FILE_KEYS = {"names": _FILE_NAME_KEYS,
+              "signatures": _FILE_NAME_KEYS | {"lines", "generated", "content_hash"}}
+
+
+def check_verbosity(verbosity: str) -> None:
+    """ValueError naming the levels when verbosity is not one of them."""
+    if verbosity not in VERBOSITY_LEVELS:
+        raise ValueError(f"Invalid verbosity {verbosity!r}. Use "
+                         + ", ".join(repr(level) for level in VERBOSITY_LEVELS) + ".")
+
+
+def select_fields(data: dict, verbosity: str) -> dict:
+    """A JSON file result (as built for output_format="json") reduced to the
+    keys of the verbosity level; "full" returns it unchanged."""
+    if verbosity == "full":
+        return data
+    node_keys = _NODE_KEYS[verbosity]
+
+    def node(item: dict) -> dict:
+        kept = {key: value for key, value in item.items() if key in node_keys}
+        if "children" in kept:
+            kept["children"] = [node(child) for child in kept["children"]]
+        return kept
+
+    result = {key: value for key, value in data.items() if key in _FILE_KEYS[verbosity]}
+    result["structures"] = [node(item) for item in data.get("structures", [])]
+    return result
+
+
+def tree_options(verbosity: str, show_signatures: bool = True, show_decorators: bool = True,
+                 show_docstrings: bool = True, show_complexity: bool = False,
+                 condense: bool = True) -> dict:
+    """TreeFormatter keyword arguments for the level: the show_* flags the
+    caller asked for, narrowed by what the level drops."""
+    full = verbosity == "full"
+    return {
+        "show_signatures": show_signatures and verbosity != "names",
+        "show_decorators": show_decorators and verbosity != "names",
+        "show_docstrings": show_docstrings and full,
+        "show_complexity": show_complexity and full,
+        "show_positions": verbosity != "names",
+        "show_code": full,
+        "condense": condense,
+    }
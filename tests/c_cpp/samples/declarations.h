@@ -0,0 +1,55 @@
+/* C header mixing the declaration forms a cgo project keeps next to its Go code */
+
+#ifndef DECLARATIONS_H
+#define DECLARATIONS_H
+
+#include <stddef.h>
+
+// Size of the read buffer
+#define BUFFER_SIZE 4096
+
+// Smaller of two values
+#define MIN(a, b) ((a) < (b) ? (a) : (b))
+
+// A point on the plane
+typedef struct {
+    double x;
+    double y;
+} Point;
+
+// Linked list node
+typedef struct node {
+    int value;
+    struct node *next;
+} node_t, *node_ptr;
+
+// Tagged value
+union value {
+    long integer;
+    double real;
+};
+
+// Byte count used by the API
+typedef unsigned long byte_count;
+
+// Comparison callback
+typedef int (*compare_fn)(const void *a, const void *b);
+
+// Log levels
+typedef enum {
+    LEVEL_DEBUG,
+    LEVEL_ERROR
+} level;
+
+// Open a handle
+int handle_open(const char *path, int flags);
+
+#ifdef _WIN32
+// Windows path separator
+#define SEPARATOR '\\'
+#else
+// POSIX path separator
+#define SEPARATOR '/'
+#endif
+
+#endif /* DECLARATIONS_H */
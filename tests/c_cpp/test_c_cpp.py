@@ -1,5 +1,6 @@
 """Tests for C/C++ scanner."""
 
+from scantool.languages.cpp import CCppLanguage
 from scantool.scanner import FileScanner
 
 
@@ -283,3 +284,47 @@ def test_includes(file_scanner):
     assert includes is not None, "Should find includes group"
     assert includes.start_line > 0, "Should have valid start line"
     assert includes.end_line >= includes.start_line, "End line should be >= start line"
+
+
+def test_c_header_declarations(file_scanner):
+    """typedefs, macros, unions and prototypes in a C header."""
+    structures = file_scanner.scan_file("tests/c_cpp/samples/declarations.h")
+    assert structures[0].file_metadata["language"] == "C"
+    by_name = {s.name: s for s in structures if s.type != "file-info"}
+
+    # Macros, include guard left out; both #ifdef branches are listed
+    assert "DECLARATIONS_H" not in by_name
+    assert by_name["BUFFER_SIZE"].type == "macro"
+    assert by_name["BUFFER_SIZE"].docstring == "Size of the read buffer"
+    assert by_name["BUFFER_SIZE"].end_line == by_name["BUFFER_SIZE"].start_line
+    assert by_name["MIN"].signature == "(a, b)"
+    separators = [s for s in structures if s.name == "SEPARATOR"]
+    assert [s.docstring for s in separators] == ["Windows path separator",
+                                                 "POSIX path separator"]
+
+    # An anonymous typedef'd struct is named by its typedef
+    point = by_name["Point"]
+    assert point.type == "struct" and "typedef" in point.modifiers
+    assert point.docstring == "A point on the plane"
+    assert by_name["level"].type == "enum"
+
+    # A tagged one keeps its tag; the aliases are typedef nodes
+    assert by_name["node"].type == "struct"
+    assert by_name["node_t"].type == "typedef"
+    assert by_name["node_t"].signature == "struct node"
+    assert by_name["node_ptr"].signature == "struct node *node_ptr"
+    assert by_name["byte_count"].signature == "unsigned long"
+    assert by_name["compare_fn"].signature == \
+        "int (*compare_fn)(const void *a, const void *b)"
+
+    assert by_name["value"].type == "union"
+    assert by_name["handle_open"].type == "function"
+    assert by_name["handle_open"].signature == "int (const char *path, int flags)"
+
+
+def test_file_language_names():
+    """.c and .h report C, the C++ extensions C++."""
+    assert CCppLanguage.get_file_language_name("main.c") == "C"
+    assert CCppLanguage.get_file_language_name("api.H") == "C"
+    assert CCppLanguage.get_file_language_name("service.cpp") == "C++"
+    assert CCppLanguage.get_file_language_name("service.hpp") == "C++"
@@ -6,6 +6,7 @@ import pytest
 
 from scantool.scanner import FileScanner
 from scantool.formatter import TreeFormatter
+from scantool.golang.syntax import load_go_files
 
 
 @pytest.fixture
@@ -53,6 +54,20 @@ def basic_sample(sample_dir, request):
     return sample_dir(language) / f"basic{ext}"
 
 
+def write_tree(root: Path, files: dict[str, str]) -> None:
+    """Write files (path relative to root -> content), creating directories."""
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+def load_go_tree(root: Path, files: dict[str, str]) -> list:
+    """write_tree, then root's Go files as the Go tools load them."""
+    write_tree(root, files)
+    return load_go_files(str(root))
+
+
 def validate_line_range_invariants(structures, parent=None, parent_name="root"):
     """Validate universal line range invariants for any scanner.
 
@@ -0,0 +1,41 @@
+// Package repository shows interface satisfaction across receiver kinds.
+package repository
+
+import "errors"
+
+// User is a stored account
+type User struct {
+	ID   int64
+	Name string
+}
+
+// UserRepository abstracts user persistence
+type UserRepository interface {
+	Get(id int64) (*User, error)
+	Save(u *User) error
+}
+
+// InMemoryUserRepo keeps users in a map; methods need a pointer receiver
+type InMemoryUserRepo struct {
+	users map[int64]*User
+}
+
+func (r *InMemoryUserRepo) Get(id int64) (*User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return u, nil
+}
+
+func (r *InMemoryUserRepo) Save(u *User) error {
+	r.users[u.ID] = u
+	return nil
+}
+
+// ReadOnlyRepo only reads, so it does not satisfy UserRepository
+type ReadOnlyRepo struct{}
+
+func (ReadOnlyRepo) Get(id int64) (*User, error) {
+	return nil, nil
+}
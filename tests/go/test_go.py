@@ -2,6 +2,9 @@
 
 from pathlib import Path
 
+from scantool.file_json import file_to_dict
+from scantool.languages import parse_errors
+from scantool.languages.go import GoLanguage
 from scantool.scanner import FileScanner
 
 
@@ -169,3 +172,481 @@ def test_multiple_return_values(file_scanner):
     # Should show both parameters and return types
     assert "username" in create_user.signature or "string" in create_user.signature, \
         f"Should show parameters, got: {create_user.signature}"
+
+
+def test_full_signature(tmp_path):
+    """full_signature rebuilds the header with name, grouping and variadics."""
+    src = (
+        "package users\n"
+        "\n"
+        "type UserService struct{}\n"
+        "\n"
+        "func CreateUser(username, email string) (*User, error) { return nil, nil }\n"
+        "\n"
+        "func (s *UserService) GetUser(id int64) (*User, error) { return nil, nil }\n"
+        "\n"
+        "func Join(sep string, parts ...string) (out string) { return }\n"
+        "\n"
+        "func hook(int, string) {}\n"
+    )
+    path = tmp_path / "users.go"
+    path.write_text(src)
+
+    structures = FileScanner().scan_file(str(path))
+    by_name = {s.name: s for s in structures}
+
+    assert by_name["CreateUser"].full_signature == "CreateUser(username, email string) (*User, error)"
+    assert by_name["GetUser"].full_signature == "(s *UserService) GetUser(id int64) (*User, error)"
+    assert by_name["Join"].full_signature == "Join(sep string, parts ...string) (out string)"
+    assert by_name["hook"].full_signature == "hook(int, string)"
+    # The name-less signature used by the tree view is unchanged
+    assert by_name["GetUser"].signature == "(s *UserService) (id int64) (*User, error)"
+
+
+def test_doc_comments(tmp_path):
+    """Full doc comments attach to declarations; a comment separated by a
+    blank line does not; the package doc lands in file metadata."""
+    src = (
+        "// Package users manages accounts.\n"
+        "//\n"
+        "// It talks to the store.\n"
+        "package users\n"
+        "\n"
+        "// Store persists users.\n"
+        "// Safe for concurrent use.\n"
+        "type Store struct{}\n"
+        "\n"
+        "// TODO: unrelated note\n"
+        "\n"
+        "func Lookup() {}\n"
+        "\n"
+        "/* Save writes\n"
+        "   a user. */\n"
+        "func (s *Store) Save() {}\n"
+    )
+    path = tmp_path / "users.go"
+    path.write_text(src)
+
+    structures = FileScanner().scan_file(str(path))
+    by_name = {s.name: s for s in structures}
+
+    assert structures[0].file_metadata["doc"] == \
+        "Package users manages accounts.\n\nIt talks to the store."
+    assert by_name["Store"].doc == "Store persists users.\nSafe for concurrent use."
+    assert by_name["Store"].docstring == "Store persists users."
+    assert by_name["Lookup"].doc is None and by_name["Lookup"].docstring is None
+    assert by_name["Save"].doc == "Save writes\na user."
+
+
+def test_const_and_var_doc_comments(tmp_path):
+    """Each name of a const or var declaration is a node; a spec of a
+    group has its own doc or, without one, the group's; a comment trailing
+    the line above is not a doc."""
+    src = (
+        "package limits\n"
+        "\n"
+        "// Size limits, in bytes.\n"
+        "const (\n"
+        "\t// MaxBody caps a request body.\n"
+        "\tMaxBody int64 = 1 << 20\n"
+        "\tMaxHeader = 8192 // per header\n"
+        "\tmaxLine = 512\n"
+        ")\n"
+        "\n"
+        "// ErrClosed is returned after Close.\n"
+        "// Safe to compare with ==.\n"
+        "var ErrClosed = errors.New(\"closed\")\n"
+        "\n"
+        "var x, y int\n"
+    )
+    path = tmp_path / "limits.go"
+    path.write_text(src)
+
+    structures = FileScanner().scan_file(str(path))
+    by_name = {s.name: s for s in structures if s.type in ("const", "var")}
+
+    assert [(s.type, s.name, s.start_line) for s in by_name.values()] == [
+        ("const", "MaxBody", 6), ("const", "MaxHeader", 7), ("const", "maxLine", 8),
+        ("var", "ErrClosed", 13), ("var", "x", 15), ("var", "y", 15)]
+    assert by_name["MaxBody"].doc == "MaxBody caps a request body."
+    assert by_name["MaxBody"].signature == "int64"
+    assert by_name["MaxHeader"].doc == "Size limits, in bytes."
+    assert by_name["maxLine"].doc == "Size limits, in bytes."
+    assert by_name["maxLine"].visibility == "unexported"
+    assert by_name["ErrClosed"].doc == "ErrClosed is returned after Close.\nSafe to compare with ==."
+    assert by_name["ErrClosed"].docstring == "ErrClosed is returned after Close."
+    assert by_name["x"].doc is None and by_name["y"].signature == "int"
+
+
+def test_cyclomatic_complexity(tmp_path):
+    """Base 1; each if/for/non-default case/&&/|| adds one; closures count
+    toward the enclosing function."""
+    src = (
+        "package calc\n"
+        "\n"
+        "func Flat() int { return 1 }\n"
+        "\n"
+        "func Kind(n int) string {\n"
+        "\tswitch n {\n"
+        "\tcase 1:\n"
+        "\t\treturn \"one\"\n"
+        "\tcase 2, 3:\n"
+        "\t\treturn \"few\"\n"
+        "\tdefault:\n"
+        "\t\treturn \"many\"\n"
+        "\t}\n"
+        "}\n"
+        "\n"
+        "func Walk(xs []int, ok bool) {\n"
+        "\tfor _, x := range xs {\n"
+        "\t\tif x > 0 && ok || x < -10 {\n"
+        "\t\t\tgo func() {\n"
+        "\t\t\t\tif x == 1 {\n"
+        "\t\t\t\t}\n"
+        "\t\t\t}()\n"
+        "\t\t}\n"
+        "\t}\n"
+        "}\n"
+    )
+    path = tmp_path / "calc.go"
+    path.write_text(src)
+
+    by_name = {s.name: s for s in FileScanner().scan_file(str(path))}
+
+    assert by_name["Flat"].complexity["cyclomatic"] == 1
+    assert by_name["Kind"].complexity["cyclomatic"] == 3
+    # for + if + && + || + closure's if
+    assert by_name["Walk"].complexity["cyclomatic"] == 6
+
+
+def test_struct_fields(tmp_path):
+    """Struct fields keep names, source types, raw tags and embedded types."""
+    src = (
+        "package models\n"
+        "\n"
+        "type User struct {\n"
+        "\tBase\n"
+        "\t*sync.Mutex\n"
+        "\tID        int64             `json:\"id\" db:\"user_id\"`\n"
+        "\tFirst, Last string\n"
+        "\tMeta      map[string][]byte // free-form\n"
+        "}\n"
+        "\n"
+        "type Empty struct{}\n"
+        "\n"
+        "type Reader interface{ Read() }\n"
+    )
+    path = tmp_path / "models.go"
+    path.write_text(src)
+
+    by_name = {s.name: s for s in FileScanner().scan_file(str(path))}
+    fields = [(f.name, f.type, f.tag) for f in by_name["User"].fields]
+
+    assert fields == [
+        ("", "Base", None),
+        ("", "*sync.Mutex", None),
+        ("ID", "int64", '`json:"id" db:"user_id"`'),
+        ("First", "string", None),
+        ("Last", "string", None),
+        ("Meta", "map[string][]byte", None),
+    ]
+    assert by_name["Empty"].fields == []
+    assert by_name["Reader"].fields is None
+
+
+def test_grouped_type_declaration(tmp_path):
+    """Each type of a type ( ... ) group is its own node, with its own
+    lines, fields and doc comment (the group's when it has none)."""
+    src = (
+        "package models\n"
+        "\n"
+        "// Models of the API.\n"
+        "type (\n"
+        "\t// Account is a login.\n"
+        "\tAccount struct {\n"
+        "\t\tID int64 `json:\"id\"`\n"
+        "\t}\n"
+        "\tName string\n"
+        "\tID = int64\n"
+        ")\n"
+    )
+    path = tmp_path / "models.go"
+    path.write_text(src)
+
+    by_name = {s.name: s for s in FileScanner().scan_file(str(path))
+               if s.type in ("struct", "type")}
+
+    assert [(s.name, s.type, s.start_line, s.end_line) for s in by_name.values()] == [
+        ("Account", "struct", 6, 8), ("Name", "type", 9, 9), ("ID", "type", 10, 10)]
+    assert [(f.name, f.type, f.tag) for f in by_name["Account"].fields] == [
+        ("ID", "int64", '`json:"id"`')]
+    assert by_name["Account"].docstring == "Account is a login."
+    assert by_name["Name"].docstring == "Models of the API."
+
+
+def test_method_receivers(tmp_path):
+    """Methods name their receiver base type; types list their methods in
+    the file. Nodes stay flat."""
+    src = (
+        "package users\n"
+        "\n"
+        "type UserService struct{}\n"
+        "\n"
+        "type Cache[K comparable] struct{}\n"
+        "\n"
+        "func (s *UserService) GetUser(id int64) {}\n"
+        "\n"
+        "func (s UserService) CreateUser() {}\n"
+        "\n"
+        "func (c *Cache[K]) Get(key K) {}\n"
+        "\n"
+        "func (Remote) Ping() {}\n"
+    )
+    path = tmp_path / "users.go"
+    path.write_text(src)
+
+    structures = FileScanner().scan_file(str(path))
+    by_name = {s.name: s for s in structures}
+
+    assert [(n, by_name[n].receiver_type) for n in ("GetUser", "CreateUser", "Get", "Ping")] == [
+        ("GetUser", "UserService"), ("CreateUser", "UserService"),
+        ("Get", "Cache"), ("Ping", "Remote")]
+    assert by_name["UserService"].methods == ["GetUser", "CreateUser"]
+    assert by_name["Cache"].methods == ["Get"]
+    assert by_name["GetUser"] in structures
+    assert by_name["UserService"].children == []
+
+
+def test_visibility_and_receiver_kind(tmp_path):
+    """Pointer vs value receivers from the receiver's type node — generic
+    and parenthesized pointers included; visibility from identifier case."""
+    src = (
+        "package users\n"
+        "\n"
+        "type store[K comparable] struct{}\n"
+        "\n"
+        "func (s *store[K]) Put() {}\n"
+        "\n"
+        "func (s store[K]) Len() int { return 0 }\n"
+        "\n"
+        "func (p (*store[K])) Parens() {}\n"
+        "\n"
+        "func (*store[K]) Anonymous() {}\n"
+        "\n"
+        "func (ptr ptrLike) Value() {}\n"
+        "\n"
+        "func New() {}\n"
+    )
+    path = tmp_path / "users.go"
+    path.write_text(src)
+
+    by_name = {s.name: s for s in FileScanner().scan_file(str(path))}
+
+    assert {n: by_name[n].receiver_kind for n in ("Put", "Len", "Parens", "Anonymous", "Value")} == {
+        "Put": "pointer", "Len": "value", "Parens": "pointer", "Anonymous": "pointer",
+        "Value": "value"}
+    assert by_name["New"].receiver_kind is None
+    assert [by_name[n].visibility for n in ("store", "Put", "New")] == [
+        "unexported", "exported", "exported"]
+
+
+def test_func_kind_in_json(tmp_path):
+    """Methods and free functions carry func_kind; other nodes don't."""
+    path = tmp_path / "users.go"
+    path.write_text("package users\n\ntype S struct{}\n\n"
+                    "func (s *S) Get() {}\n\nfunc New() *S { return nil }\n")
+
+    nodes = file_to_dict(FileScanner().scan_file(str(path)), str(path))["structures"]
+
+    assert {n["name"]: n.get("func_kind") for n in nodes if n["type"] != "file-info"} == {
+        "S": None, "Get": "method", "New": "function"}
+
+
+def test_uses_packages(tmp_path):
+    """Import qualifiers a body refers to — aliases included, parameters
+    and locals of the same name hiding the package."""
+    path = tmp_path / "users.go"
+    path.write_text(
+        "package users\n\n"
+        "import (\n\t\"errors\"\n\t\"net/url\"\n\t\"time\"\n"
+        "\tyaml \"gopkg.in/yaml.v3\"\n)\n\n"
+        "type Service struct{ clock time.Time }\n\n"
+        "func CreateUser(name string) (time.Time, error) {\n"
+        "\tif name == \"\" {\n\t\treturn time.Time{}, errors.New(\"empty\")\n\t}\n"
+        "\tvar d time.Duration\n\treturn time.Now().Add(d), nil\n}\n\n"
+        "func (s *Service) Link(url *url.URL) string { return url.String() }\n\n"
+        "func Load(b []byte) error { var v any; return yaml.Unmarshal(b, &v) }\n\n"
+        "func Plain() int { return 1 }\n")
+
+    by_name = {s.name: s for s in FileScanner().scan_file(str(path))}
+
+    assert by_name["CreateUser"].uses_packages == ["errors", "time"]
+    assert by_name["Link"].uses_packages is None  # the url parameter hides package url
+    assert by_name["Load"].uses_packages == ["yaml"]
+    assert by_name["Plain"].uses_packages is None
+    nodes = file_to_dict(list(by_name.values()), str(path))["structures"]
+    assert {n["name"]: n.get("uses_packages") for n in nodes if n["type"] == "function"} == {
+        "CreateUser": ["errors", "time"], "Load": ["yaml"], "Plain": None}
+
+
+def test_generated_header(tmp_path):
+    """The gofmt convention: "// Code generated ... DO NOT EDIT." counts only
+    as a line comment before the package clause."""
+    cases = {
+        "gen.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage pb\n",
+        "after_doc.go": "// Package pb is generated.\n//\n"
+                        "// Code generated by mockgen. DO NOT EDIT.\npackage pb\n",
+        "late.go": "package pb\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n",
+        "block.go": "/* Code generated by x. DO NOT EDIT. */\npackage pb\n",
+        "no_period.go": "// Code generated by x. DO NOT EDIT\npackage pb\n",
+        "manual.go": "// Package pb: not generated.\npackage pb\n",
+    }
+    generated = {}
+    for name, src in cases.items():
+        (tmp_path / name).write_text(src)
+        meta = FileScanner().scan_file(str(tmp_path / name))[0].file_metadata
+        generated[name] = meta.get("generated", False)
+
+    assert generated == {"gen.go": True, "after_doc.go": True, "late.go": False,
+                         "block.go": False, "no_period.go": False, "manual.go": False}
+
+
+def test_file_parsed_once(tmp_path, monkeypatch):
+    """scan() and the file-info hooks after it (doc, generated, imports,
+    package, symbol IDs, line counts) share one parse of the file."""
+    src = '// Package a does things.\npackage a\n\nimport "fmt"\n\nfunc A() { fmt.Println() }\n'
+    path = tmp_path / "a.go"
+    path.write_text(src)
+    parsed = []
+
+    class CountingParser:
+        def __init__(self, parser):
+            self.parser = parser
+
+        def parse(self, source, *args, **kwargs):
+            parsed.append(source)
+            return self.parser.parse(source, *args, **kwargs)
+
+    init = GoLanguage.__init__
+
+    def counting_init(self, **kwargs):
+        init(self, **kwargs)
+        self.parser = CountingParser(self.parser)
+
+    monkeypatch.setattr(GoLanguage, "__init__", counting_init)
+    meta = FileScanner().scan_file(str(path))[0].file_metadata
+
+    assert (meta["doc"], meta["package"], meta["imports"][0]["path"]) == (
+        "Package a does things.", "a", "fmt")
+    assert parsed.count(src.encode()) == 1
+
+
+def test_byte_offsets_and_utf16_columns(tmp_path):
+    """Offsets index the file's bytes; UTF-16 columns count code units, so
+    é is one unit (two bytes) and 👋 two units (four bytes)."""
+    src = (
+        "package greet // Grüße\n"
+        "\n"
+        '/* é👋 */ func F() { _ = "ü" }\n'
+    )
+    path = tmp_path / "greet.go"
+    path.write_text(src, encoding="utf-8")
+    data = src.encode("utf-8")
+
+    func = next(s for s in FileScanner().scan_file(str(path)) if s.name == "F")
+
+    assert data[func.start_offset:func.end_offset].decode() == 'func F() { _ = "ü" }'
+    assert (func.start_utf16_column, func.end_utf16_column) == (10, 30)
+
+
+def test_deprecated_and_annotations(tmp_path):
+    """Deprecated needs a paragraph starting "Deprecated:" (the Go
+    convention); "@key: value" lines become annotations."""
+    src = (
+        "package users\n"
+        "\n"
+        "// OldLookup finds a user.\n"
+        "//\n"
+        "// Deprecated: use Lookup.\n"
+        "// @owner: accounts-team\n"
+        "func OldLookup() {}\n"
+        "\n"
+        "// Lookup finds a user. Deprecated: never.\n"
+        "// @owner: accounts-team\n"
+        "// @since: v1.4\n"
+        "func Lookup() {}\n"
+        "\n"
+        "// Deprecated: the store is read-only now.\n"
+        "type Store struct{}\n"
+    )
+    path = tmp_path / "users.go"
+    path.write_text(src)
+
+    by_name = {s.name: s for s in FileScanner().scan_file(str(path))}
+
+    assert by_name["OldLookup"].deprecated is True
+    assert by_name["OldLookup"].annotations == {"owner": "accounts-team"}
+    assert by_name["Lookup"].deprecated is None
+    assert by_name["Lookup"].annotations == {"owner": "accounts-team", "since": "v1.4"}
+    assert by_name["Store"].deprecated is True and by_name["Store"].annotations is None
+
+
+def test_partial_parse_keeps_declarations_and_errors(tmp_path):
+    """A mid-edit typo costs the broken spot, not the file: declarations
+    that parsed are kept, and each broken spot is a positioned error."""
+    src = (
+        "package users\n"
+        "\n"
+        "type User struct {\n"
+        "\tName string\n"
+        "}\n"
+        "\n"
+        "func Total(a, b int) int {\n"
+        "\treturn a +\n"
+        "}\n"
+        "\n"
+        "func Valid() {}\n"
+    )
+    path = tmp_path / "users.go"
+    path.write_text(src)
+
+    structures = FileScanner().scan_file(str(path))
+
+    names = {s.name for s in structures}
+    assert {"User", "Total", "Valid"} <= names
+    errors = parse_errors(str(path), structures)
+    assert errors and all(7 <= e.line <= 9 for e in errors)
+
+
+def test_bom_and_crlf_keep_positions(tmp_path):
+    """A BOM-prefixed CRLF file reports the lines of its LF twin, and its
+    offsets still index the file's own bytes."""
+    src = (
+        "// Package users keeps accounts.\n"
+        "package users\n"
+        "\n"
+        "type User struct {\n"
+        "\tName string\n"
+        "}\n"
+        "\n"
+        "// Rename changes the name.\n"
+        "func (u *User) Rename(name string) {\n"
+        "\tu.Name = name\n"
+        "}\n"
+        "\n"
+        "func Valid() bool { return true }\n"
+    )
+    lf_path = tmp_path / "lf.go"
+    lf_path.write_bytes(src.encode("utf-8"))
+    crlf_path = tmp_path / "crlf.go"
+    data = b"\xef\xbb\xbf" + src.replace("\n", "\r\n").encode("utf-8")
+    crlf_path.write_bytes(data)
+
+    def positions(path):
+        return [(s.type, s.name, s.start_line, s.end_line)
+                for s in FileScanner().scan_file(str(path)) if s.type != "file-info"]
+
+    assert positions(crlf_path) == positions(lf_path)
+    assert not parse_errors(str(crlf_path), FileScanner().scan_file(str(crlf_path)))
+    valid = next(s for s in FileScanner().scan_file(str(crlf_path)) if s.name == "Valid")
+    assert data[valid.start_offset:valid.end_offset] == b"func Valid() bool { return true }"
@@ -0,0 +1,107 @@
+"""Tests for config key outlines: JSON, YAML and TOML keys with value types."""
+
+from scantool.languages import get_language
+from scantool.languages.config import JsonLanguage, TomlLanguage, YamlLanguage
+
+
+def outline(nodes):
+    return [(n.name, n.signature, outline(n.children)) if n.children else (n.name, n.signature)
+            for n in nodes if n.type == "key"]
+
+
+def test_language_per_format():
+    assert isinstance(get_language(".json"), JsonLanguage)
+    assert isinstance(get_language(".yml"), YamlLanguage)
+    assert get_language(".yaml").get_language_name() == "YAML"
+    assert get_language(".toml").get_language_name() == "TOML"
+    assert get_language(".ini").get_language_name() == "Config"
+
+
+def test_json_keys_two_levels():
+    source = b"""{
+  // comments and trailing commas are accepted
+  "name": "app",
+  "version": 1.5,
+  "private": true,
+  "scripts": {
+    "build": "tsc",
+    "nested": {"deep": 1},
+  },
+  "files": ["a", {"x": 1}],
+  "none": null,
+}
+"""
+    nodes = JsonLanguage().scan(source)
+    assert outline(nodes) == [
+        ("name", "string"), ("version", "number"), ("private", "bool"),
+        ("scripts", "object", [("build", "string"), ("nested", "object")]),
+        ("files", "array"), ("none", "null")]
+    assert (nodes[3].start_line, nodes[3].end_line) == (6, 9)
+
+
+def test_json_parse_error_keeps_keys_before_it():
+    nodes = JsonLanguage().scan(b'{"a": 1, "b": [1, 2,\n "c" 3}')
+    assert [n.name for n in nodes if n.type == "key"] == ["a", "b"]
+    assert nodes[-1].type == "parse-error"
+    assert nodes[-1].start_line == 2
+
+
+def test_yaml_keys_documents_and_block_scalars():
+    source = b"""# compose
+version: "3.8"
+services:
+  web:
+    image: nginx
+  db:
+    image: postgres
+x-defaults: &default
+  restart: always
+list:
+- a
+script: |
+  echo hi
+  a: b
+count: 3
+enabled: false
+empty:
+alias: *default
+---
+second: doc
+"""
+    assert outline(YamlLanguage().scan(source)) == [
+        ("version", "string"),
+        ("services", "object", [("web", "object"), ("db", "object")]),
+        ("x-defaults", "object", [("restart", "string")]),
+        ("list", "array"), ("script", "string"), ("count", "number"),
+        ("enabled", "bool"), ("empty", "null"), ("alias", "alias"), ("second", "string")]
+
+
+def test_toml_tables_and_arrays_of_tables():
+    source = b'''title = "x"
+created = 1979-05-27T07:32:00Z
+
+[project]
+name = "scantool"
+dependencies = [
+    "a",
+]
+scripts.run = "main"
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+'''
+    nodes = TomlLanguage().scan(source)
+    assert outline(nodes) == [
+        ("title", "string"), ("created", "datetime"),
+        ("project", "object", [("name", "string"), ("dependencies", "array"),
+                               ("scripts", "object")]),
+        ("servers", "array", [("name", "string")])]
+    assert nodes[2].children[1].end_line == 8
+
+
+def test_toml_parse_error():
+    nodes = TomlLanguage().scan(b'a = 1\nb = = 2\n')
+    assert nodes[-1].type == "parse-error"
@@ -0,0 +1,123 @@
+"""Tests for the language registry: registering languages from outside the
+package, priority between claimants, and registration during scans."""
+
+import threading
+
+import pytest
+
+from scantool.languages import (
+    BaseLanguage,
+    StructureNode,
+    get_language,
+    get_registry,
+    register_language,
+)
+from scantool.languages.go import GoLanguage
+from scantool.scanner import FileScanner
+
+
+class DslLanguage(BaseLanguage):
+    """In-house DSL: one "rule NAME" per line."""
+
+    @classmethod
+    def get_extensions(cls) -> list[str]:
+        return [".rules"]
+
+    @classmethod
+    def get_language_name(cls) -> str:
+        return "Rules"
+
+    def scan(self, source_code: bytes):
+        return [StructureNode(type="rule", name=line.split()[1], start_line=i, end_line=i)
+                for i, line in enumerate(source_code.decode().splitlines(), start=1)
+                if line.startswith("rule ")]
+
+    def extract_imports(self, file_path: str, content: str):
+        return []
+
+    def find_entry_points(self, file_path: str, content: str):
+        return []
+
+
+class LowPriorityGo(DslLanguage):
+    @classmethod
+    def get_extensions(cls) -> list[str]:
+        return [".go"]
+
+    @classmethod
+    def get_priority(cls) -> int:
+        return GoLanguage.get_priority()
+
+
+@pytest.fixture
+def registry():
+    """The global registry, restored after the test."""
+    registry = get_registry()
+    languages, instances = dict(registry._languages), dict(registry._instances)
+    yield registry
+    registry._languages.clear()
+    registry._languages.update(languages)
+    registry._instances.clear()
+    registry._instances.update(instances)
+
+
+class TestRegisterLanguage:
+    def test_builtin_go_registered_through_register(self, registry):
+        assert registry.get_class(".go") is GoLanguage
+        assert registry.register(GoLanguage) == [".go"]
+        assert registry.get_class(".go") is GoLanguage
+
+    def test_external_language_is_scanned(self, registry, tmp_path):
+        assert register_language(DslLanguage) == [".rules"]
+        path = tmp_path / "auth.rules"
+        path.write_text("rule deny_all\n# comment\nrule allow_admin\n")
+
+        structures = FileScanner().scan_file(str(path))
+
+        assert [(s.type, s.name) for s in structures if s.type == "rule"] == [
+            ("rule", "deny_all"), ("rule", "allow_admin")]
+        assert ".rules" in FileScanner().get_supported_extensions()
+
+    def test_equal_priority_keeps_existing(self, registry):
+        assert register_language(LowPriorityGo) == []
+        assert registry.get_class(".go") is GoLanguage
+
+    def test_override_replaces_cached_instance(self, registry):
+        class HighPriorityRules(DslLanguage):
+            @classmethod
+            def get_priority(cls) -> int:
+                return DslLanguage.get_priority() + 1
+
+        register_language(DslLanguage)
+        assert isinstance(get_language(".rules"), DslLanguage)
+
+        register_language(HighPriorityRules)
+
+        assert type(get_language(".rules")) is HighPriorityRules
+
+    def test_rejects_non_language(self, registry):
+        with pytest.raises(TypeError):
+            register_language(object)
+
+    def test_register_during_concurrent_lookups(self, registry):
+        classes = [type(f"Rules{i}", (DslLanguage,), {
+            "get_extensions": classmethod(lambda cls, i=i: [f".rules{i}"])})
+            for i in range(20)]
+        errors = []
+
+        def lookup():
+            try:
+                for _ in range(200):
+                    assert get_language(".go") is not None
+            except Exception as e:  # surfaced below; a thread can't fail the test
+                errors.append(e)
+
+        readers = [threading.Thread(target=lookup) for _ in range(4)]
+        writers = [threading.Thread(target=register_language, args=(cls,)) for cls in classes]
+        for thread in readers + writers:
+            thread.start()
+        for thread in readers + writers:
+            thread.join()
+
+        assert not errors
+        assert all(registry.get_class(f".rules{i}") is classes[i] for i in range(20))
@@ -152,3 +152,34 @@ def test_line_range_invariants(file_scanner):
     structures = file_scanner.scan_file("tests/python/samples/edge_cases.py")
     validate_line_range_invariants(structures)
 
+
+
+def test_decorated_async_and_nested_methods(tmp_path):
+    """Decorated defs start at the def line, async is a modifier, nested class
+    methods stay under their class, and the language is reported."""
+    src = (
+        "import functools\n"
+        "\n"
+        "@functools.cache\n"
+        "async def fetch(url):\n"
+        "    return url\n"
+        "\n"
+        "class Outer:\n"
+        "    class Inner:\n"
+        "        @staticmethod\n"
+        "        def build():\n"
+        "            return 1\n"
+    )
+    path = tmp_path / "mod.py"
+    path.write_text(src)
+
+    structures = FileScanner().scan_file(str(path))
+
+    assert structures[0].file_metadata["language"] == "Python"
+    fetch = next(s for s in structures if s.name == "fetch")
+    assert fetch.start_line == 4
+    assert "async" in fetch.modifiers
+    inner = next(c for c in next(s for s in structures if s.name == "Outer").children
+                 if c.name == "Inner")
+    build = next(c for c in inner.children if c.name == "build")
+    assert (build.type, build.start_line) == ("method", 10)
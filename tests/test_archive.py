@@ -0,0 +1,123 @@
+"""Tests for archive scanning: zip and tar entries scanned in memory, keyed
+by entry path, with zip-slip and decompression-bomb guards."""
+
+import io
+import json
+import tarfile
+import zipfile
+
+import pytest
+
+from scantool.archive import ArchiveEntry, read_limited, safe_entry_name
+from scantool.languages import SKIP_TOO_LARGE, skip_reason
+from scantool.scanner import FileScanner, LimitExceeded
+from scantool.server import scan_archive
+
+FILES = {
+    "proj/main.go": "package main\n\nfunc main() {}\n",
+    "proj/util/strings.py": "def slugify(text):\n    return text.lower()\n",
+    "proj/README.txt": "hello\n",
+    "proj/node_modules/dep/index.js": "function dep() {}\n",
+}
+
+
+def make_zip(path, files, extra=()):
+    with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as archive:
+        for name, content in {**files, **dict(extra)}.items():
+            archive.writestr(name, content)
+    return str(path)
+
+
+def make_tar(path, files):
+    with tarfile.open(path, "w:gz") as archive:
+        for name, content in files.items():
+            data = content.encode()
+            info = tarfile.TarInfo(name)
+            info.size = len(data)
+            archive.addfile(info, io.BytesIO(data))
+        link = tarfile.TarInfo("proj/link.go")
+        link.type = tarfile.SYMTYPE
+        link.linkname = "/etc/passwd"
+        archive.addfile(link)
+    return str(path)
+
+
+class TestEntryNames:
+    @pytest.mark.parametrize("name, expected", [
+        ("src/a.go", "src/a.go"), ("./src//a.go", "src/a.go"), ("src\\a.go", "src/a.go"),
+        ("../evil.go", None), ("src/../../evil.go", None), ("/etc/passwd", None),
+        ("C:/windows/a.go", None), ("./", None)])
+    def test_safe_entry_name(self, name, expected):
+        assert safe_entry_name(name) == expected
+
+    def test_read_limited_ignores_declared_size(self):
+        entry = ArchiveEntry("bomb.go", 10, 0.0, lambda: io.BytesIO(b"x" * 1000))
+
+        assert read_limited(entry, 100) is None
+        assert read_limited(entry, 1000) == b"x" * 1000
+
+
+class TestScanArchive:
+    def test_zip_entries_keyed_by_path(self, tmp_path):
+        path = make_zip(tmp_path / "proj.zip", FILES, extra={"../evil.go": "package evil\n"})
+
+        results = FileScanner().scan_archive(path)
+
+        assert list(results) == ["proj/main.go", "proj/util/strings.py", "proj/README.txt"]
+        names = [n.name for n in results["proj/main.go"]]
+        assert names[0] == "main.go" and "main" in names
+
+    def test_tar_gz_skips_symlinks(self, tmp_path):
+        path = make_tar(tmp_path / "proj.tar.gz", FILES)
+
+        results = FileScanner().scan_archive(path, include=["**/*.go"])
+
+        assert list(results) == ["proj/main.go"]
+
+    def test_oversized_entry_listed_not_parsed(self, tmp_path):
+        path = make_zip(tmp_path / "big.zip", {"gen.go": "package gen\n" + "// x\n" * 1000})
+
+        results = FileScanner().scan_archive(path, max_file_size=100)
+
+        assert skip_reason(results["gen.go"]) == SKIP_TOO_LARGE
+
+    def test_total_bytes_limit(self, tmp_path):
+        files = {f"f{i}.go": "package f\n" + "// padding\n" * 10 for i in range(5)}
+        path = make_zip(tmp_path / "many.zip", files)
+
+        with pytest.raises(LimitExceeded) as info:
+            FileScanner().scan_archive(path, max_total_bytes=300)
+
+        assert info.value.limit == "max_total_bytes"
+        assert list(info.value.results) == ["f0.go", "f1.go"]
+
+    def test_not_an_archive(self, tmp_path):
+        path = tmp_path / "plain.zip"
+        path.write_text("not an archive")
+
+        with pytest.raises(ValueError):
+            FileScanner().scan_archive(str(path))
+
+
+class TestTool:
+    def test_tree(self, tmp_path):
+        path = make_zip(tmp_path / "proj.zip", FILES)
+
+        text = scan_archive.fn(path)[0].text
+
+        assert text.startswith("proj.zip/")
+        assert "main.go" in text and "slugify" in text
+        assert "node_modules" not in text
+
+    def test_json(self, tmp_path):
+        path = make_tar(tmp_path / "proj.tgz", FILES)
+
+        data = json.loads(scan_archive.fn(path, output_format="json")[0].text)
+
+        assert sorted(data) == ["proj/README.txt", "proj/main.go", "proj/util/strings.py"]
+        assert data["proj/main.go"]["file"] == "proj/main.go"
+
+    def test_missing_archive(self, tmp_path):
+        text = scan_archive.fn(str(tmp_path / "nope.zip"))[0].text
+
+        assert text.startswith("Error: Archive not found")
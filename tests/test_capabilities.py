@@ -0,0 +1,73 @@
+"""Tests for the capabilities tool: languages from the live registry,
+output formats, and each tool's option names."""
+
+import json
+
+import pytest
+
+from scantool import __version__
+from scantool.languages import BaseLanguage, get_registry, register_language
+from scantool.server import capabilities
+
+
+class HclLanguage(BaseLanguage):
+    @classmethod
+    def get_extensions(cls) -> list[str]:
+        return [".hcl", ".tfvars"]
+
+    @classmethod
+    def get_language_name(cls) -> str:
+        return "HCL"
+
+    def scan(self, source_code: bytes):
+        return []
+
+    def extract_imports(self, file_path: str, content: str):
+        return []
+
+    def find_entry_points(self, file_path: str, content: str):
+        return []
+
+
+@pytest.fixture
+def registry():
+    registry = get_registry()
+    languages = dict(registry._languages)
+    yield registry
+    registry._languages.clear()
+    registry._languages.update(languages)
+
+
+def described():
+    return json.loads(capabilities.fn()[0].text)
+
+
+class TestCapabilities:
+    def test_server_and_formats(self):
+        data = described()
+
+        assert data["version"] == __version__
+        assert {"tree", "json", "json-stable", "sarif"} <= set(data["output_formats"])
+        assert "method" in data["kinds"]
+        assert data["verbosity"] == ["names", "signatures", "full"]
+
+    def test_builtin_languages(self):
+        go = next(language for language in described()["languages"]
+                  if ".go" in language["extensions"])
+
+        assert go == {"name": "Go", "extensions": [".go"], "plugin": False}
+
+    def test_registered_language_is_a_plugin(self, registry):
+        register_language(HclLanguage)
+
+        hcl = next(language for language in described()["languages"]
+                   if language["name"] == "HCL")
+
+        assert hcl == {"name": "HCL", "extensions": [".hcl", ".tfvars"], "plugin": True}
+
+    def test_tool_options(self):
+        tools = described()["tools"]
+
+        assert tools["scan_file"]["required"] == ["file_path"]
+        assert "kinds" in tools["scan_file"]["optional"]
+        assert tools["capabilities"] == {"required": [], "optional": []}
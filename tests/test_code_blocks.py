@@ -0,0 +1,84 @@
+"""Tests for code_blocks: fenced code blocks of Markdown files, scanned."""
+
+import json
+
+from scantool.code_blocks import block_suffix, fenced_blocks, format_code_blocks, scan_markdown
+from scantool.languages import get_registry
+from scantool.scanner import FileScanner
+from scantool.server import scan_code_blocks as scan_code_blocks_tool
+
+README = """# Example
+
+```go
+package demo
+
+func Hello() string { return "hi" }
+```
+
+Run it:
+
+```bash
+go run .
+```
+
+~~~golang title="broken"
+func Broken( {
+~~~
+
+```go
+```
+"""
+
+
+def blocks_of(tmp_path, languages=("go",)):
+    (tmp_path / "README.md").write_text(README)
+    (tmp_path / "docs").mkdir()
+    (tmp_path / "docs" / "notes.txt").write_text("```go\nfunc Skipped( {\n```\n")
+    return scan_markdown(str(tmp_path), FileScanner(), languages)
+
+
+def test_fences_and_info_strings():
+    blocks = fenced_blocks(README.encode(), "README.md")
+    assert [(b.line, b.end_line, b.language, b.content_line) for b in blocks] == [
+        (3, 7, "go", 4), (11, 13, "bash", 12), (15, 17, "golang", 16), (19, 20, "go", 20)]
+    assert blocks[0].content.startswith("package demo\n")
+    assert blocks[3].content == ""
+
+
+def test_block_suffix():
+    registry = get_registry()
+    assert block_suffix("golang", ["go"], registry) == ".go"
+    assert block_suffix("py", ["python"], registry) == ".py"
+    assert block_suffix("bash", ["go"], registry) is None
+    assert block_suffix(None, ["go"], registry) is None
+
+
+def test_go_blocks_scanned_with_markdown_lines(tmp_path):
+    blocks = blocks_of(tmp_path)
+    assert len(blocks) == 4  # notes.txt is not Markdown
+    hello, bash, broken, empty = blocks
+    assert hello.scanned and not hello.errors
+    assert [(s.type, s.name, s.start_line) for s in hello.symbols] == [("function", "Hello", 6)]
+    assert not bash.scanned
+    assert broken.scanned and broken.errors
+    assert broken.errors[0].line == 16 and broken.errors[0].file.endswith("README.md")
+    assert empty.scanned and not empty.errors and not empty.symbols
+
+
+def test_format_and_tool(tmp_path):
+    text = format_code_blocks(blocks_of(tmp_path), "docs")
+    assert text.splitlines()[0] == \
+        "4 fenced code blocks in docs: 3 scanned, 1 with parse errors"
+    assert "- @3-7 go: function Hello" in text
+    assert "- @11-13 bash (not scanned)" in text
+    assert format_code_blocks([], "docs") == "No fenced code blocks found in docs"
+
+    readme = str(tmp_path / "README.md")
+    data = json.loads(scan_code_blocks_tool.fn(readme, output_format="json")[0].text)
+    assert [b["scanned"] for b in data] == [True, False, True, True]
+    assert data[0]["symbols"][0] == {"type": "function", "name": "Hello", "line": 6,
+                                     "end_line": 6}
+    data = json.loads(scan_code_blocks_tool.fn(readme, languages=["bash"],
+                                               output_format="json")[0].text)
+    assert [b["scanned"] for b in data] == [False, False, False, False]  # no bash parser
+    assert scan_code_blocks_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,79 @@
+"""Tests for content_hashes: SHA-256 per file, body hash per symbol and
+groups of identical files."""
+
+import hashlib
+import json
+from pathlib import Path
+
+from scantool.content_hashes import (collect_hashes, content_hash, format_hashes,
+                                     identical_files)
+from scantool.languages import StructureNode
+from scantool.server import content_hashes
+
+SOURCE = b"\xef\xbb\xbfdef greet(name):\r\n    return f'hi {name}'\r\n"
+
+
+def info(digest):
+    return StructureNode(type="file-info", name="f", start_line=1, end_line=1,
+                         file_metadata={"content_hash": digest})
+
+
+def function(name, line, body_hash):
+    return StructureNode(type="function", name=name, start_line=line, end_line=line + 1,
+                         symbol_id=f"function:m.{name}", body_hash=body_hash)
+
+
+def test_collect_hashes_per_file_and_symbol():
+    method = StructureNode(type="method", name="run", start_line=4, end_line=5,
+                           symbol_id="method:m.Job.run", body_hash="b" * 40)
+    job = StructureNode(type="class", name="Job", start_line=3, end_line=5,
+                        symbol_id="class:m.Job", body_hash="c" * 40, children=[method])
+    results = {"/r/b.py": [info("2" * 64), function("f", 1, "a" * 40), job],
+               "/r/a.py": [info("1" * 64)],
+               "/r/unread.bin": [StructureNode(type="file-info", name="unread.bin",
+                                               start_line=1, end_line=1,
+                                               file_metadata={"unsupported": True})],
+               "/r/none.txt": None}
+    hashes = collect_hashes(results)
+    assert [h.file for h in hashes] == ["/r/a.py", "/r/b.py"]
+    assert [s.symbol_id for s in hashes[1].symbols] == [
+        "function:m.f", "class:m.Job", "method:m.Job.run"]
+    assert hashes[1].to_dict(include_symbols=True)["symbols"][2] == {
+        "id": "method:m.Job.run", "line": 4, "body_hash": "b" * 40}
+    assert "symbols" not in hashes[0].to_dict()
+    assert content_hash(results["/r/unread.bin"]) is None
+
+
+def test_identical_files_grouped():
+    results = {"/r/x/util.py": [info("1" * 64)], "/r/vendor/util.py": [info("1" * 64)],
+               "/r/main.py": [info("2" * 64)]}
+    hashes = collect_hashes(results)
+    assert identical_files(hashes) == [["/r/vendor/util.py", "/r/x/util.py"]]
+    text = format_hashes(hashes, "/r")
+    assert "1 groups of identical files:" in text
+    assert "- vendor/util.py, x/util.py" in text
+    assert f"{'2' * 64}  main.py" in text
+
+
+def test_scan_hashes_raw_bytes(tmp_path):
+    (tmp_path / "a.py").write_bytes(SOURCE)
+    (tmp_path / "copy.py").write_bytes(SOURCE)
+    (tmp_path / "b.py").write_bytes(SOURCE.replace(b"hi", b"hello"))
+
+    data = json.loads(content_hashes.fn(str(tmp_path), include_symbols=True,
+                                        output_format="json")[0].text)
+    files = {Path(f["file"]).name: f for f in data["files"]}
+    # BOM and CRLF included: the hash of the bytes on disk
+    assert files["a.py"]["content_hash"] == hashlib.sha256(SOURCE).hexdigest()
+    assert files["b.py"]["content_hash"] != files["a.py"]["content_hash"]
+    assert [Path(f).name for f in data["identical"][0]] == ["a.py", "copy.py"]
+
+    greet = files["a.py"]["symbols"][0]
+    assert greet["id"].endswith(".greet")
+    assert greet["body_hash"] == files["copy.py"]["symbols"][0]["body_hash"]
+    assert greet["body_hash"] != files["b.py"]["symbols"][0]["body_hash"]
+
+
+def test_missing_directory_is_an_error(tmp_path):
+    text = content_hashes.fn(str(tmp_path / "missing"))[0].text
+    assert text.startswith("Error")
@@ -0,0 +1,70 @@
+"""Tests for csv_export: one RFC 4180 row per symbol of a scan."""
+
+import csv
+import io
+
+from scantool.csv_export import COLUMNS, export_csv, format_csv, symbol_rows
+from scantool.languages import StructureNode
+from scantool.server import scan_directory
+
+
+def python_result():
+    run = StructureNode(type="method", name="run", start_line=3, end_line=8,
+                        signature="(self, limit: int = 10)",
+                        doc='Runs the job, "fast".\n\nStops at limit.',
+                        complexity={"lines": 6, "depth": 2, "branches": 2})
+    hidden = StructureNode(type="method", name="_step", start_line=10, end_line=11)
+    job = StructureNode(type="class", name="Job", start_line=1, end_line=11,
+                        children=[run, hidden])
+    helper = StructureNode(type="function", name="_helper", start_line=14, end_line=15,
+                           complexity={"lines": 2, "depth": 1, "branches": 0})
+    info = StructureNode(type="file-info", name="jobs.py", start_line=1, end_line=1,
+                         file_metadata={"language": "Python"})
+    return [info, StructureNode(type="imports", name="import os", start_line=1, end_line=1),
+            job, helper]
+
+
+def test_rows_per_symbol_with_parent_exported_and_doc():
+    rows = symbol_rows({"/src/jobs.py": python_result()}, lambda path: "jobs.py")
+    assert [(r["kind"], r["name"], r["parent"], r["complexity"], r["exported"], r["has_doc"])
+            for r in rows] == [
+        ("class", "Job", "", "", "true", "false"),
+        ("method", "run", "Job", 3, "true", "true"),
+        ("method", "_step", "Job", "", "false", "false"),
+        ("function", "_helper", "", 1, "false", "false"),
+    ]
+    assert {r["path"] for r in rows} == {"jobs.py"}
+    assert set(rows[0]) == set(COLUMNS)
+
+
+def test_rfc4180_quoting_round_trips():
+    text = format_csv({"/src/jobs.py": python_result()})
+    assert text.startswith(",".join(COLUMNS) + "\r\n")
+    assert '"(self, limit: int = 10)"' in text
+    assert '"Runs the job, ""fast"".\n\nStops at limit."' in text
+
+    parsed = list(csv.DictReader(io.StringIO(text, newline="")))
+    assert len(parsed) == 4
+    assert parsed[1]["doc"] == 'Runs the job, "fast".\n\nStops at limit.'
+    assert parsed[1]["line"] == "3"
+
+
+def test_export_to_writer_counts_rows():
+    out = io.StringIO(newline="")
+    assert export_csv({"/src/jobs.py": python_result(), "/src/logo.png": None}, out) == 4
+    assert out.getvalue().count("\r\n") == 5  # header and rows; the doc's own breaks stay \n
+
+
+def test_scan_directory_csv(tmp_path):
+    (tmp_path / "users.go").write_text(
+        "package users\n\n// Get returns a user, or nil.\nfunc Get(id int) *User { return nil }\n\n"
+        "type User struct{}\n\nfunc (u *User) name() string { return \"\" }\n")
+    text = scan_directory.fn(str(tmp_path), output_format="csv", path_style="relative")[0].text
+    rows = list(csv.DictReader(io.StringIO(text, newline="")))
+    assert [(r["path"], r["kind"], r["name"], r["exported"], r["has_doc"]) for r in rows] == [
+        ("users.go", "function", "Get", "true", "true"),
+        ("users.go", "struct", "User", "true", "false"),
+        ("users.go", "method", "name", "false", "false"),
+    ]
+    assert rows[0]["doc"] == "Get returns a user, or nil."
+    assert rows[2]["parent"] == "User"
@@ -143,6 +143,30 @@ class TestMemoryTTL:
         assert "sec ago" in second or "min ago" in second
 
 
+class TestFilteredScanFile:
+    def test_line_window_on_unchanged_file(self, tmp_path):
+        path = tmp_path / "mod.py"
+        path.write_text(SOURCE_V1)
+
+        _scan(path)
+        first = _scan(path, start_line=6, end_line=10)
+        second = _scan(path, start_line=6, end_line=10)
+
+        for window in (first, second):
+            assert "unchanged since" not in window
+            assert "beta" in window and "alpha" not in window
+
+    def test_kinds_and_name_pattern_on_unchanged_file(self, tmp_path):
+        path = tmp_path / "mod.py"
+        path.write_text(SOURCE_V1)
+
+        _scan(path)
+        out = _scan(path, kinds=["function"], name_pattern="^al")
+
+        assert "unchanged since" not in out
+        assert "alpha" in out and "beta" not in out
+
+
 class TestScanDirectoryDelta:
     def test_all_unchanged_aggregates(self, tmp_path):
         (tmp_path / "a.py").write_text(SOURCE_V1)
@@ -166,6 +190,25 @@ class TestScanDirectoryDelta:
         assert "a.py" in out.split("unchanged since")[0]   # changed file shown in full
         assert "unchanged since last scan (1 files): b.py" in out
 
+    def test_kinds_query_after_scan_is_not_aggregated(self, tmp_path):
+        (tmp_path / "a.py").write_text(SOURCE_V1)
+        scan_directory.fn(str(tmp_path), pattern="**/*.py")
+
+        out = scan_directory.fn(str(tmp_path), pattern="**/*.py", kinds=["function"])[0].text
+
+        assert "unchanged since" not in out
+        assert "alpha" in out
+
+    def test_name_pattern_query_after_scan_is_not_aggregated(self, tmp_path):
+        (tmp_path / "a.py").write_text(SOURCE_V1)
+        (tmp_path / "b.py").write_text("def gamma():\n    return fetch_thing()\n")
+        scan_directory.fn(str(tmp_path), pattern="**/*.py")
+
+        out = scan_directory.fn(str(tmp_path), pattern="**/*.py", name_pattern="^gam")[0].text
+
+        assert "unchanged since" not in out
+        assert "gamma" in out and "alpha" not in out
+
     def test_delta_false_full(self, tmp_path):
         (tmp_path / "a.py").write_text(SOURCE_V1)
         scan_directory.fn(str(tmp_path), pattern="**/*.py")
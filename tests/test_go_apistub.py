@@ -0,0 +1,148 @@
+"""Tests for golang.apistub: a package's exported API rendered as stub Go
+source."""
+
+import json
+import shutil
+import subprocess
+
+import pytest
+
+from scantool.golang import apistub
+from scantool.golang.apistub import render_api_stub
+from scantool.server import render_api_stub as render_api_stub_tool
+from conftest import load_go_tree
+
+requires_go = pytest.mark.skipif(shutil.which("go") is None, reason="go not installed")
+
+STORE = """// Package store keeps users.
+package store
+
+import (
+	"context"
+	"errors"
+	sq "database/sql"
+	"strings"
+)
+
+// ErrNotFound is returned for unknown IDs.
+var ErrNotFound = errors.New("not found")
+
+var cache = map[string]string{}
+
+// Kind of user.
+type Kind int
+
+const (
+	Admin Kind = iota
+	Guest
+	internal
+)
+
+const maxUsers = 10
+
+// Store is a user store.
+type Store struct {
+	// DB is the connection.
+	DB    *sq.DB
+	Name  string `json:"name"`
+	mu    chan struct{}
+	a, B  int
+}
+
+// Finder finds users.
+type Finder interface {
+	Find(ctx context.Context, id string) (*User, error)
+	reset()
+}
+
+type User struct{ ID string }
+
+// Open connects.
+func Open(dsn string) (*Store, error) {
+	return &Store{Name: strings.TrimSpace(dsn)}, nil
+}
+
+// Get returns one user.
+func (s *Store) Get(ctx context.Context, id string) (*User, error) {
+	return nil, ErrNotFound
+}
+
+func (s *Store) lock() {}
+
+func helper() {}
+"""
+
+
+@pytest.fixture
+def no_gofmt(monkeypatch):
+    monkeypatch.setattr(apistub, "gofmt_path", lambda: None)
+
+
+def stub_of(tmp_path, files=None):
+    return render_api_stub(load_go_tree(tmp_path, files or {"store.go": STORE}), str(tmp_path))
+
+
+class TestRenderApiStub:
+    def test_declarations(self, tmp_path, no_gofmt):
+        stub = stub_of(tmp_path)
+        source = stub.source
+
+        assert source.startswith("// Code generated by scantool render_api_stub. DO NOT EDIT.\n\n"
+                                 "// Package store keeps users.\npackage store\n")
+        assert stub.gofmt is False
+        assert stub.imports == ["context", "database/sql", "errors"]  # strings: body only
+        assert 'import (\n\t"context"\n\tsq "database/sql"\n\t"errors"\n)' in source
+        assert '// ErrNotFound is returned for unknown IDs.\nvar ErrNotFound = errors.New("not found")' in source
+        assert "cache" not in source and "maxUsers" not in source
+        assert "const (\n\tAdmin Kind = iota\n\tGuest\n\tinternal\n)" in source  # iota group whole
+        assert ('func Open(dsn string) (*Store, error) {\n\tpanic("stub")\n}') in source
+        assert "helper" not in source and "lock" not in source
+
+    def test_types_and_methods(self, tmp_path, no_gofmt):
+        source = stub_of(tmp_path).source
+
+        assert ("// Store is a user store.\ntype Store struct {\n\t// DB is the connection.\n"
+                "\tDB    *sq.DB\n\tName  string `json:\"name\"`\n\tB int\n"
+                "\t// contains filtered or unexported fields\n}") in source
+        assert ("type Finder interface {\n\tFind(ctx context.Context, id string) (*User, error)\n"
+                "\t// contains filtered or unexported fields\n}") in source
+        assert "type User struct {\n\tID string\n}" in source
+        # methods right after their type
+        assert source.index("func (s *Store) Get(ctx context.Context, id string) (*User, error)") > \
+            source.index("type Store struct")
+        assert source.index("func (s *Store) Get") < source.index("type Finder")
+
+    def test_test_files_and_other_packages_ignored(self, tmp_path, no_gofmt):
+        stub = stub_of(tmp_path, {"a.go": "package a\n\nfunc A() {}\n",
+                                  "a_test.go": "package a\n\nfunc TestHelper() {}\n"})
+
+        assert stub.symbols == 1
+        assert "TestHelper" not in stub.source
+
+    def test_no_package(self, tmp_path):
+        assert render_api_stub([], str(tmp_path)).package is None
+
+    @requires_go
+    def test_stub_compiles(self, tmp_path):
+        stub = stub_of(tmp_path)
+        module = tmp_path / "check"
+        module.mkdir()
+        (module / "go.mod").write_text("module check\n\ngo 1.21\n")
+        (module / "stub.go").write_text(stub.source)
+
+        result = subprocess.run(["go", "vet", "./..."], cwd=module, capture_output=True, text=True)
+
+        assert result.returncode == 0, result.stderr
+
+
+class TestTool:
+    def test_json(self, tmp_path):
+        (tmp_path / "store.go").write_text(STORE)
+
+        data = json.loads(render_api_stub_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data["package"] == "store"
+        assert data["source"].startswith("// Code generated by scantool render_api_stub.")
+
+    def test_missing_directory(self, tmp_path):
+        assert render_api_stub_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
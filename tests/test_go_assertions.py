@@ -0,0 +1,101 @@
+"""Tests for golang.assertions: type assertions with their form, and type
+switches with their cases."""
+
+import json
+
+from scantool.golang.assertions import find_type_assertions, format_type_assertions
+from scantool.golang.syntax import load_go_files
+from scantool.server import find_type_assertions as find_type_assertions_tool
+from conftest import load_go_tree
+
+HANDLER = """package handler
+
+var defaultConn, hasConn = pool.Get().(*Conn)
+
+func Handle(v interface{}) error {
+	c := v.(*Conn)
+	if s, ok := v.(fmt.Stringer); ok {
+		log(s)
+	}
+	var n, ok = v.(int)
+	_, _ = n, ok
+	v.(io.Closer).Close()
+	switch x := v.(type) {
+	case int, int64:
+		return nil
+	case *Conn:
+		return x.err
+	default:
+		return nil
+	}
+}
+
+func (s *Server) each(items []any) {
+	for _, item := range items {
+		go func() {
+			send(item.(string))
+		}()
+	}
+	switch items[0].(type) {
+	case nil:
+	}
+}
+"""
+
+
+def assertions_of(tmp_path, **kwargs):
+    return find_type_assertions(load_go_tree(tmp_path, {"handler.go": HANDLER}), **kwargs)
+
+
+class TestFindTypeAssertions:
+    def test_assertions_and_forms(self, tmp_path):
+        found = assertions_of(tmp_path)
+
+        assert [(a.line, a.function, a.kind, a.operand, a.types, a.can_panic) for a in found] == [
+            (3, "(package level)", "assertion", "pool.Get()", ["*Conn"], False),
+            (6, "Handle", "assertion", "v", ["*Conn"], True),
+            (7, "Handle", "assertion", "v", ["fmt.Stringer"], False),
+            (10, "Handle", "assertion", "v", ["int"], False),
+            (12, "Handle", "assertion", "v", ["io.Closer"], True),
+            (13, "Handle", "switch", "v", ["int", "int64", "*Conn"], False),
+            (26, "func literal in Server.each", "assertion", "item", ["string"], True),
+            (29, "Server.each", "switch", "items[0]", ["nil"], False),
+        ]
+        switch = found[5]
+        assert switch.has_default and switch.binding == "x"
+        assert not found[7].has_default and found[7].binding is None
+
+    def test_panicking_only(self, tmp_path):
+        assert [a.line for a in assertions_of(tmp_path, panicking_only=True)] == [6, 12, 26]
+
+    def test_tests_skipped(self, tmp_path):
+        (tmp_path / "handler_test.go").write_text(HANDLER)
+
+        assert find_type_assertions(load_go_files(str(tmp_path))) == []
+        assert len(find_type_assertions(load_go_files(str(tmp_path)), include_tests=True)) == 8
+
+    def test_format(self, tmp_path):
+        text = format_type_assertions(assertions_of(tmp_path), "pkg")
+
+        assert text.startswith("8 type assertions/switches in pkg "
+                               "(3 can panic, 3 comma-ok, 2 type switch)")
+        assert "- @6 Handle: v.(*Conn)  [can panic]" in text
+        assert "- @7 Handle: v.(fmt.Stringer)  comma-ok" in text
+        assert "- @13 Handle: switch x := v.(type)  cases: int, int64, *Conn, default" in text
+        assert format_type_assertions([], "pkg") == \
+            "No type assertions or type switches found in pkg"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "handler.go").write_text(HANDLER)
+
+        data = json.loads(find_type_assertions_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data[1] == {"file": str((tmp_path / "handler.go").resolve()), "line": 6,
+                           "function": "Handle", "kind": "assertion", "operand": "v",
+                           "types": ["*Conn"], "comma_ok": False, "can_panic": True}
+        assert data[5]["has_default"] is True and data[5]["binding"] == "x"
+
+    def test_missing_path(self, tmp_path):
+        assert find_type_assertions_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
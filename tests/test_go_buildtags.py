@@ -0,0 +1,47 @@
+"""Tests for golang.buildtags: build constraints read from the file header
+in both comment forms, and evaluated against a tag set."""
+
+import pytest
+
+from scantool.golang.buildtags import find_constraint, satisfied
+
+
+class TestFindConstraint:
+    def test_go_build_line(self):
+        source = b"// Copyright 2024.\n\n//go:build linux && (amd64 || arm64)\n\npackage a\n"
+
+        assert find_constraint(source) == "linux && (amd64 || arm64)"
+
+    def test_plus_build_lines_rewritten(self):
+        source = (b"/* License\n * text\n */\n"
+                  b"// +build linux,amd64 darwin\n// +build !cgo\n\npackage a\n")
+
+        assert find_constraint(source) == "((linux && amd64) || darwin) && !cgo"
+
+    def test_go_build_wins_over_plus_build(self):
+        source = b"//go:build linux\n// +build linux darwin\n\npackage a\n"
+
+        assert find_constraint(source) == "linux"
+
+    def test_only_before_package_clause(self):
+        assert find_constraint(b"package a\n\n//go:build linux\n") is None
+        assert find_constraint(b"// Package a does things.\npackage a\n") is None
+
+
+class TestSatisfied:
+    def test_operators_and_precedence(self):
+        expression = "linux && amd64 || !cgo && darwin"
+
+        assert satisfied(expression, ["linux", "amd64"])
+        assert satisfied(expression, ["darwin"])
+        assert not satisfied(expression, ["darwin", "cgo"])
+        assert not satisfied("linux && (amd64 || arm64)", ["linux", "386"])
+
+    def test_release_tags_count_as_present(self):
+        assert satisfied("go1.21", [])
+        assert not satisfied("!go1.18", [])
+
+    @pytest.mark.parametrize("expression", ["linux &&", "(linux", "linux)", "a b", "a ? b"])
+    def test_malformed(self, expression):
+        with pytest.raises(ValueError):
+            satisfied(expression, ["linux"])
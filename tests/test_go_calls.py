@@ -0,0 +1,150 @@
+"""Tests for golang.calls: intra-package call edges, resolution of
+functions and methods through known types, external/unresolved calls."""
+
+import json
+from pathlib import Path
+
+from scantool.golang.calls import build_call_graph, format_call_graph
+from scantool.server import call_graph
+from conftest import load_go_tree
+
+SERVICE = """package users
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Repository interface {
+	Save(u User) error
+}
+
+type User struct{ Email string }
+
+type Auditor struct{}
+
+func (a *Auditor) Record(event string) {}
+
+type UserService struct {
+	repo  Repository
+	audit *Auditor
+}
+
+func NewUserService(repo Repository) *UserService {
+	return &UserService{repo: repo, audit: &Auditor{}}
+}
+
+// CreateUser validates and stores a user
+func (s *UserService) CreateUser(email string) error {
+	if !ValidateEmail(email) {
+		return fmt.Errorf("invalid email %q", email)
+	}
+	s.audit.Record("create")
+	return s.repo.Save(User{Email: strings.ToLower(email)})
+}
+
+func ValidateEmail(email string) bool {
+	return len(email) > 0 && email[0] != '@'
+}
+
+func run() {
+	svc := NewUserService(nil)
+	svc.CreateUser("a@b.c")
+	svc.CreateUser("d@e.f")
+	u := User(User{})
+	_ = u
+	cb := func() { ValidateEmail("x") }
+	cb()
+}
+"""
+
+
+def graph_of(tmp_path, files=None):
+    return build_call_graph(load_go_tree(tmp_path, files or {"users.go": SERVICE}))
+
+
+def edges_of(graph, caller):
+    return {e.expression: e for e in graph.edges if e.caller == caller}
+
+
+class TestCallGraph:
+    def test_create_user_edges(self, tmp_path):
+        edges = edges_of(graph_of(tmp_path), "UserService.CreateUser")
+
+        assert edges["ValidateEmail"].kind == "function"
+        assert edges["ValidateEmail"].target_line == 36
+        assert edges["s.repo.Save"].kind == "unresolved"  # interface field
+        assert edges["fmt.Errorf"].kind == "external"
+        assert edges["strings.ToLower"].kind == "external"
+
+    def test_method_resolved_through_struct_field(self, tmp_path):
+        edge = edges_of(graph_of(tmp_path), "UserService.CreateUser")["s.audit.Record"]
+
+        assert (edge.kind, edge.callee, edge.target_line) == ("method", "Auditor.Record", 16)
+
+    def test_variable_typed_from_constructor_result(self, tmp_path):
+        edges = edges_of(graph_of(tmp_path), "run")
+
+        assert edges["svc.CreateUser"].callee == "UserService.CreateUser"
+        assert edges["svc.CreateUser"].lines == [42, 43]
+        assert edges["NewUserService"].kind == "function"
+
+    def test_builtins_and_conversions_are_not_edges(self, tmp_path):
+        graph = graph_of(tmp_path)
+
+        assert "len" not in edges_of(graph, "ValidateEmail")
+        assert "User" not in edges_of(graph, "run")
+
+    def test_closure_calls_belong_to_enclosing_function(self, tmp_path):
+        edges = edges_of(graph_of(tmp_path), "run")
+
+        assert edges["ValidateEmail"].lines == [46]
+        assert edges["cb"].kind == "unresolved"
+
+    def test_promoted_method_through_embedding(self, tmp_path):
+        source = ("package p\n\ntype Base struct{}\n\nfunc (Base) Close() {}\n\n"
+                  "type Conn struct{ Base }\n\nfunc use(c Conn) { c.Close() }\n")
+        edge = edges_of(graph_of(tmp_path, {"p.go": source}), "use")["c.Close"]
+
+        assert (edge.kind, edge.callee) == ("method", "Base.Close")
+
+    def test_generic_receivers_and_test_files(self, tmp_path):
+        files = load_go_tree(tmp_path, {
+            "stack.go": "package p\n\ntype Stack[T any] struct{ items []T }\n\n"
+                        "func (s *Stack[T]) grow() {}\n\nfunc (s *Stack[T]) Push(v T) { s.grow() }\n",
+            "stack_test.go": "package p\n\nfunc TestPush(s *Stack[int]) { s.Push(1) }\n",
+        })
+
+        graph = build_call_graph(files)
+        edge = edges_of(graph, "Stack.Push")["s.grow"]
+        assert (edge.kind, edge.callee, edge.target_line) == ("method", "Stack.grow", 5)
+        assert "TestPush" not in [f.name for f in graph.functions]
+        edge = edges_of(build_call_graph(files, include_tests=True), "TestPush")["s.Push"]
+        assert (edge.kind, edge.callee) == ("method", "Stack.Push")
+
+    def test_packages_resolve_separately(self, tmp_path):
+        graph = graph_of(tmp_path, {
+            "a/a.go": "package a\n\nfunc Helper() {}\n",
+            "b/b.go": "package b\n\nfunc main() { Helper() }\n",
+        })
+
+        assert edges_of(graph, "main")["Helper"].kind == "unresolved"
+
+    def test_format_lists_calls_under_callers(self, tmp_path):
+        text = format_call_graph(graph_of(tmp_path), str(tmp_path))
+
+        assert "- UserService.CreateUser @28" in text
+        assert "    ValidateEmail users.go@36" in text
+        assert "    s.audit.Record → Auditor.Record users.go@16" in text
+        assert "    s.repo.Save  [unresolved]" in text
+
+    def test_tool_json(self, tmp_path):
+        (tmp_path / "users.go").write_text(SERVICE)
+        data = json.loads(call_graph.fn(str(tmp_path), output_format="json")[0].text)
+
+        edge = next(e for e in data["edges"] if e["expression"] == "ValidateEmail")
+        assert edge["from"] == "UserService.CreateUser"
+        assert edge["target"]["line"] == 36
+
+    def test_tool_missing_path(self, tmp_path):
+        assert call_graph.fn(str(Path(tmp_path) / "missing"))[0].text.startswith("Error:")
@@ -0,0 +1,64 @@
+"""Tests for golang.comments: tagged comments come from comment nodes only,
+with optional author attribution."""
+
+from scantool.golang.comments import format_comments, scan_comments
+from conftest import load_go_tree
+
+SOURCE = '''\
+package jobs
+
+// TODO(marius): retry with backoff
+func Run() string {
+\t// FIXME handle nil queue
+\tmsg := "TODO: this is a string, not a comment"
+\treturn msg // HACK: until the scheduler lands
+}
+
+/*
+ * TODO: split this file
+ * not a marker line
+ */
+
+// NOTE: custom tag
+// see the TODO list in the README
+'''
+
+
+def markers_in(tmp_path, tags=None):
+    return scan_comments(load_go_tree(tmp_path, {"jobs.go": SOURCE}), tags)
+
+
+class TestScanComments:
+    def test_default_tags_skip_string_literals(self, tmp_path):
+        found = [(m.line, m.tag, m.text) for m in markers_in(tmp_path)]
+
+        assert found == [
+            (3, "TODO", "retry with backoff"),
+            (5, "FIXME", "handle nil queue"),
+            (7, "HACK", "until the scheduler lands"),
+            (11, "TODO", "split this file"),
+        ]
+
+    def test_author_extracted(self, tmp_path):
+        first = markers_in(tmp_path)[0]
+
+        assert first.author == "marius"
+        assert all(m.author is None for m in markers_in(tmp_path)[1:])
+
+    def test_custom_tags_replace_defaults(self, tmp_path):
+        found = markers_in(tmp_path, tags=["NOTE"])
+
+        assert [(m.line, m.tag, m.text) for m in found] == [(15, "NOTE", "custom tag")]
+
+    def test_tags_are_whole_case_sensitive_words(self, tmp_path):
+        source = "package jobs\n\n// TODOS live in the tracker\n// todo: lower case\n// FIXME:\n"
+
+        found = scan_comments(load_go_tree(tmp_path, {"notes.go": source}))
+
+        assert [(m.line, m.tag, m.text) for m in found] == [(5, "FIXME", "")]
+
+    def test_format_groups_and_tallies(self, tmp_path):
+        text = format_comments(markers_in(tmp_path), "jobs")
+
+        assert text.startswith("4 tagged comments in jobs (FIXME 1, HACK 1, TODO 2)")
+        assert "- @3 TODO(marius): retry with backoff" in text
@@ -0,0 +1,123 @@
+"""Tests for golang.concurrency: goroutine launches and channel operations
+with their enclosing function."""
+
+import json
+
+import pytest
+
+from scantool.golang.concurrency import find_concurrency, format_concurrency
+from scantool.server import concurrency_report
+from conftest import load_go_tree
+
+POOL = """package pool
+
+const queueSize = 8
+
+type Pool struct {
+	jobs chan Job
+	done chan struct{}
+}
+
+func New() *Pool {
+	p := &Pool{jobs: make(chan Job, queueSize), done: make(chan struct{})}
+	go p.loop()
+	return p
+}
+
+func (p *Pool) loop() {
+	for {
+		select {
+		case job := <-p.jobs:
+			job.Run()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func Fanout(inputs []int) []int {
+	results := make(chan int, 4)
+	errs := make(chan error, 0)
+	for _, in := range inputs {
+		go func(n int) {
+			results <- n * 2
+		}(in)
+	}
+	out := make([]int, 0, len(inputs))
+	for range inputs {
+		out = append(out, <-results)
+	}
+	_ = errs
+	return out
+}
+"""
+
+
+def ops_of(tmp_path, **kwargs):
+    return find_concurrency(load_go_tree(tmp_path, {"pool.go": POOL}), **kwargs)
+
+
+def test_go_func_literal_attributed_to_launcher(tmp_path):
+    ops = ops_of(tmp_path, kinds=["go", "send"])
+
+    assert [(op.kind, op.function, op.target) for op in ops] == [
+        ("go", "New", "p.loop"),
+        ("go", "Fanout", "func literal"),
+        ("send", "func literal in Fanout", "results"),
+    ]
+
+
+def test_buffered_and_unbuffered_makes(tmp_path):
+    makes = ops_of(tmp_path, kinds=["make"])
+
+    # make([]int, ...) is not a channel
+    assert [(op.target, op.buffered, op.capacity) for op in makes] == [
+        ("chan Job", None, "queueSize"),
+        ("chan struct{}", False, None),
+        ("chan int", True, "4"),
+        ("chan error", False, "0"),
+    ]
+
+
+def test_receives_and_select_cases(tmp_path):
+    receives = ops_of(tmp_path, kinds=["receive"])
+
+    assert [(op.function, op.target, op.select) for op in receives] == [
+        ("Pool.loop", "p.jobs", True),
+        ("Pool.loop", "p.done", True),
+        ("Fanout", "results", False),
+    ]
+
+
+def test_package_level_ranges_and_tests(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "ready.go": "package pool\n\nvar ready = make(chan struct{}, 0x0)\n\n"
+                    "func Drain(ch chan int) {\n\tfor v := range ch {\n\t\t_ = v\n\t}\n}\n",
+        "ready_test.go": "package pool\n\nfunc helper() { go Drain(nil) }\n",
+    })
+
+    # a range over a channel is not listed; 0x0 is an unbuffered capacity
+    assert [(op.kind, op.function, op.target, op.buffered) for op in find_concurrency(files)] == [
+        ("make", "(package level)", "chan struct{}", False)]
+    assert [(op.kind, op.function) for op in find_concurrency(files, include_tests=True)] == [
+        ("make", "(package level)"), ("go", "helper")]
+
+
+def test_unknown_kind(tmp_path):
+    with pytest.raises(ValueError, match="Unknown kinds"):
+        ops_of(tmp_path, kinds=["close"])
+
+
+def test_format_and_tool(tmp_path):
+    text = format_concurrency(ops_of(tmp_path), "pool")
+    assert text.splitlines()[0] == ("10 concurrency operations in pool (2 go, "
+                                    "4 make (1 buffered, 2 unbuffered), 1 send, 3 receive)")
+    assert "- @28 Fanout: make(chan int, 4)  [buffered]" in text
+    assert "[capacity queueSize, unknown]" in text
+    assert "[receive on p.done, select case]" in text
+
+    data = json.loads(concurrency_report.fn(str(tmp_path), output_format="json")[0].text)
+    assert data[0]["kind"] == "make" and data[0]["buffered"] is None
+    assert "select" not in data[0]
+    assert concurrency_report.fn(str(tmp_path), kinds=["close"])[0].text.startswith("Error")
+    assert concurrency_report.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,115 @@
+"""Tests for golang.constants: const blocks with folded values — iota
+sequences, implicit repetition, typed constants, cross-file references
+and notes where nothing can be evaluated."""
+
+import json
+
+from scantool.golang.constants import format_constants, list_constants
+from scantool.server import list_constants as list_constants_tool
+from conftest import load_go_tree
+
+CAL = """package cal
+
+import "time"
+
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+)
+
+const (
+	_  = iota
+	KB = 1 << (10 * iota)
+	MB
+)
+
+const Name = "cal\\tendar"
+
+const (
+	Timeout = 5 * time.Second
+	Max     = Limit * 2
+	Ratio   = 3 / 2.0
+	Letter  = 'A'
+	Neg     = -7 / 2
+	Debug   = Max > 10
+	Short   = len(Name)
+	Small   = uint8(Limit)
+	Loop    = Loop + 1
+)
+"""
+
+LIMITS = """package cal
+
+const Limit = 100
+"""
+
+
+def constants_of(tmp_path):
+    blocks = list_constants(load_go_tree(tmp_path, {"cal.go": CAL, "limits.go": LIMITS}))
+    return blocks, {c.name: c for b in blocks for c in b.constants}
+
+
+class TestListConstants:
+    def test_iota_enum_typed(self, tmp_path):
+        _, constants = constants_of(tmp_path)
+
+        assert [(constants[n].type, constants[n].value, constants[n].iota)
+                for n in ("Sunday", "Monday", "Tuesday")] == [
+            ("Weekday", "0", 0), ("Weekday", "1", 1), ("Weekday", "2", 2)]
+        assert constants["Tuesday"].expression == "iota"
+
+    def test_repeated_expression_and_blank(self, tmp_path):
+        blocks, constants = constants_of(tmp_path)
+
+        assert (constants["KB"].value, constants["MB"].value) == ("1024", "1048576")
+        assert constants["MB"].iota == 2
+        assert "_" not in constants
+        assert [c.name for c in blocks[1].constants] == ["KB", "MB"]
+
+    def test_folded_values(self, tmp_path):
+        _, constants = constants_of(tmp_path)
+
+        assert {n: constants[n].value for n in
+                ("Name", "Max", "Ratio", "Letter", "Neg", "Debug", "Short")} == {
+            "Name": '"cal\\tendar"', "Max": "200", "Ratio": "1.5", "Letter": "65",
+            "Neg": "-3", "Debug": "true", "Short": "9"}
+        assert (constants["Small"].type, constants["Small"].value) == ("uint8", "100")
+        assert constants["Max"].type is None
+
+    def test_unevaluable_gets_note_not_value(self, tmp_path):
+        _, constants = constants_of(tmp_path)
+
+        assert constants["Timeout"].value is None
+        assert constants["Timeout"].note == "refers to time.Second"
+        assert constants["Loop"].note == "Loop is defined in terms of itself"
+
+    def test_format(self, tmp_path):
+        blocks, _ = constants_of(tmp_path)
+
+        text = format_constants(blocks, "cal")
+
+        assert text.startswith("16 constants in cal (14 evaluated)")
+        assert "- const ( @7\n    Sunday Weekday = 0  [iota 0]" in text
+        assert '- @19 Name = "cal\\tendar"' in text
+        assert "    Timeout = ? (refers to time.Second)" in text
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "limits.go").write_text(LIMITS)
+
+        data = json.loads(list_constants_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data == [{"file": str((tmp_path / "limits.go").resolve()), "line": 3,
+                         "grouped": False,
+                         "constants": [{"name": "Limit", "file": str((tmp_path / "limits.go").resolve()),
+                                        "line": 3, "type": None, "value": "100",
+                                        "expression": "100"}]}]
+
+    def test_no_constants(self, tmp_path):
+        (tmp_path / "a.go").write_text("package a\n")
+
+        assert list_constants_tool.fn(str(tmp_path))[0].text.startswith("No constants found")
@@ -0,0 +1,126 @@
+"""Tests for golang.constructions: composite literals of one type with the
+fields they set and omit."""
+
+import json
+
+import pytest
+
+from scantool.golang.constructions import find_constructions, format_constructions
+from scantool.server import find_constructions as find_constructions_tool
+from conftest import load_go_tree
+
+MODELS = """package models
+
+type User struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+var Guest = User{Name: "guest"}
+
+type UserService struct{}
+
+// CreateUser creates a new user
+func (s *UserService) CreateUser(username, email string) (*User, error) {
+	return &User{Name: username, Email: email}, nil
+}
+
+func fixtures() []User {
+	return []User{{ID: 1, Name: "a"}, {2, "b", "b@example.com"}}
+}
+"""
+
+API = """package api
+
+import m "example.com/app/models"
+
+type User struct{ Login string }
+
+func handler() {
+	_ = m.User{ID: 7}
+	_ = User{Login: "local"}
+	byName := map[string]*m.User{"x": {Email: "x@example.com"}}
+	_ = byName
+}
+"""
+
+
+def load(tmp_path):
+    return load_go_tree(tmp_path, {"models/user.go": MODELS, "api/handler.go": API})
+
+
+def test_literal_in_create_user(tmp_path):
+    report = find_constructions(load(tmp_path), "models.User")
+
+    create = next(c for c in report.constructions if c.function == "UserService.CreateUser")
+    assert create.pointer and create.type == "User"
+    assert create.fields == ["Name", "Email"]
+    assert create.omitted == ["ID"]
+    assert report.declared == ["ID", "Name", "Email"]
+
+
+def test_qualified_elided_and_positional(tmp_path):
+    report = find_constructions(load(tmp_path), "models.User")
+
+    assert [(c.function, c.type, c.fields) for c in report.constructions] == [
+        ("handler", "m.User", ["ID"]),
+        ("handler", "", ["Email"]),
+        ("(package level)", "User", ["Name"]),
+        ("UserService.CreateUser", "User", ["Name", "Email"]),
+        ("fixtures", "", ["ID", "Name"]),
+        ("fixtures", "", ["ID", "Name", "Email"]),
+    ]
+    positional = report.constructions[-1]
+    assert positional.positional and positional.omitted == []
+    # api's own User is a different type
+    assert all(c.fields != ["Login"] for c in report.constructions)
+    assert report.field_counts() == {"ID": 3, "Name": 4, "Email": 3}
+
+
+def test_unqualified_name_matches_every_package(tmp_path):
+    report = find_constructions(load(tmp_path), "User")
+
+    assert ["Login"] in [c.fields for c in report.constructions]
+    assert len(report.constructions) == 7
+    # Two packages declare a User: omitted fields can't be told
+    assert report.candidates == 2 and report.declared is None
+    assert all(c.omitted is None for c in report.constructions)
+    assert "declared in 2 packages" in format_constructions(report, "app")
+
+
+def test_invalid_type_name(tmp_path):
+    with pytest.raises(ValueError, match="T or pkg.T"):
+        find_constructions(load(tmp_path), "*User")
+
+
+def test_format_and_tool(tmp_path):
+    text = format_constructions(find_constructions(load(tmp_path), "models.User"), "app")
+    assert text.splitlines()[0] == "6 composite literals of models.User in app"
+    assert "fields set: ID 3/6, Name 4/6, Email 3/6" in text
+    assert "UserService.CreateUser: &User{Name, Email} — omits ID" in text
+    assert "fixtures: models.User (elided){ID, Name, Email (positional)}" in text
+
+    data = json.loads(find_constructions_tool.fn(str(tmp_path), "models.User",
+                                                 output_format="json")[0].text)
+    assert data["declared_fields"] == ["ID", "Name", "Email"]
+    assert len(data["constructions"]) == 6
+    assert find_constructions_tool.fn(str(tmp_path / "missing"), "User")[0].text.startswith(
+        "Error")
+
+
+def test_generic_literals_and_test_files(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "box/box.go": "package box\n\ntype Box[T any] struct {\n\tV  T\n\tOK bool\n}\n\n"
+                      "func Of[T any](v T) *Box[T] {\n\treturn &Box[T]{V: v}\n}\n",
+        "box/box_test.go": "package box\n\nvar empty = Box[int]{}\n",
+    })
+
+    report = find_constructions(files, "box.Box")
+
+    assert report.declared == ["V", "OK"]
+    assert [(c.function, c.type, c.pointer, c.fields, c.omitted)
+            for c in report.constructions] == [("Of", "Box", True, ["V"], ["OK"])]
+    with_tests = find_constructions(files, "box.Box", include_tests=True)
+    assert [(c.function, c.fields) for c in with_tests.constructions][-1] == \
+        ("(package level)", [])
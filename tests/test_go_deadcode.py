@@ -0,0 +1,121 @@
+"""Tests for golang.deadcode: unexported functions, methods and types with
+no reference in their package."""
+
+import json
+
+from scantool.golang.deadcode import find_dead_code, format_dead_code
+from scantool.golang.syntax import load_go_files
+from scantool.server import find_dead_code as find_dead_code_tool
+from conftest import write_tree
+
+STORE = """package store
+
+type Store struct{ cache *lru }
+
+type lru struct{}
+
+func (l *lru) get(key string) int { return 0 }
+
+func (l *lru) evict() {}
+
+type orphan struct{}
+
+func (o orphan) touch() {}
+
+func New() *Store { return &Store{cache: newLRU()} }
+
+func newLRU() *lru { return &lru{} }
+
+func (s *Store) Get(key string) int { return s.cache.get(key) }
+
+func unused() {}
+
+func countdown(n int) {
+	if n > 0 {
+		countdown(n - 1)
+	}
+}
+
+func init() {}
+"""
+
+HELPERS = """package store
+
+type closer interface{ close() }
+
+func (s *Store) close() {}
+
+func sortKeys() {}
+"""
+
+STORE_TEST = """package store
+
+import "testing"
+
+func TestSort(t *testing.T) { sortKeys() }
+
+func fixture() {}
+"""
+
+
+def dead_names(root, include_tests=False):
+    return [s.display_name for s in find_dead_code(load_go_files(str(root)),
+                                                   include_tests=include_tests)]
+
+
+class TestFindDeadCode:
+    def test_unreferenced_unexported_symbols(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE})
+
+        assert dead_names(tmp_path) == ["lru.evict", "orphan", "orphan.touch", "unused"]
+
+    def test_recursion_counts_as_use(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE})
+
+        assert "countdown" not in dead_names(tmp_path)
+
+    def test_references_across_files_and_interface_methods(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE, "helpers.go": HELPERS})
+
+        dead = dead_names(tmp_path)
+
+        assert "Store.close" not in dead  # kept alive by closer's method spec
+        assert "closer" in dead
+        assert "sortKeys" in dead
+
+    def test_test_files_only_on_request(self, tmp_path):
+        write_tree(tmp_path, {"helpers.go": HELPERS, "store_test.go": STORE_TEST})
+
+        assert "sortKeys" in dead_names(tmp_path)
+        with_tests = dead_names(tmp_path, include_tests=True)
+        assert "sortKeys" not in with_tests
+        assert "fixture" in with_tests
+
+    def test_packages_are_separate(self, tmp_path):
+        write_tree(tmp_path, {"a/a.go": "package a\n\nfunc helper() {}\n",
+                             "b/b.go": "package b\n\nfunc use() { helper() }\n"})
+
+        assert dead_names(tmp_path) == ["helper", "use"]
+
+    def test_format(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE})
+
+        text = format_dead_code(find_dead_code(load_go_files(str(tmp_path))), "pkg")
+
+        assert text.startswith("4 unreferenced unexported symbols in pkg (heuristic")
+        assert "- @9 method lru.evict" in text
+        assert format_dead_code([], "pkg") == "No unreferenced unexported symbols in pkg"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE})
+
+        data = json.loads(find_dead_code_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data[0] == {"name": "lru.evict", "kind": "method",
+                           "file": str((tmp_path / "store.go").resolve()), "line": 9,
+                           "receiver": "lru"}
+
+    def test_missing_path(self, tmp_path):
+        assert find_dead_code_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
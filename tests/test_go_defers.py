@@ -0,0 +1,101 @@
+"""Tests for golang.defers: defer statements with loop and dropped-error
+flags."""
+
+import json
+
+from scantool.golang.defers import find_defers, format_defers
+from scantool.server import find_defers as find_defers_tool
+from conftest import load_go_tree
+
+FILES = """package files
+
+import "os"
+
+type Store struct{}
+
+func (s *Store) Close() {}
+
+func (s *Store) Save() error { return nil }
+
+func Copy(paths []string) error {
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	for _, p := range paths {
+		func() {
+			g, _ := os.Open(p)
+			defer g.Close()
+		}()
+	}
+	return nil
+}
+
+func Use(s *Store) {
+	defer s.Close()
+	defer s.Save()
+	defer func() {
+		recover()
+	}()
+}
+"""
+
+
+def defers_of(tmp_path, **kwargs):
+    return find_defers(load_go_tree(tmp_path, {"files.go": FILES}), **kwargs)
+
+
+def test_defer_in_loop_and_in_closure(tmp_path):
+    defers = defers_of(tmp_path)
+
+    assert [(d.line, d.function, d.in_loop, d.loop_line) for d in defers[:2]] == [
+        (17, "Copy", True, 12),
+        # The closure returns every iteration: not a loop defer
+        (22, "func literal in Copy", False, None),
+    ]
+    assert [d.line for d in defers_of(tmp_path, loops_only=True)] == [17]
+
+
+def test_dropped_errors(tmp_path):
+    defers = defers_of(tmp_path)
+
+    assert [(d.call, d.drops_error) for d in defers] == [
+        ("f.Close()", True),
+        ("g.Close()", True),
+        # Store.Close is declared without an error result
+        ("s.Close()", False),
+        ("s.Save()", True),
+        ("func() {…}()", False),
+    ]
+    custom = defers_of(tmp_path, error_methods=["Flush"])
+    assert [d.drops_error for d in custom] == [False, False, False, True, False]
+
+
+def test_format_and_tool(tmp_path):
+    text = format_defers(defers_of(tmp_path), "files")
+    assert text.splitlines()[0] == "5 defers in files (1 inside a loop, 3 dropping an error)"
+    assert ("- @17 Copy: defer f.Close()  [in loop @12 — runs at function return; "
+            "error dropped]") in text
+    assert "- @29 Use: defer s.Close()\n" in text
+
+    data = json.loads(find_defers_tool.fn(str(tmp_path), loops_only=True,
+                                          output_format="json")[0].text)
+    assert [(d["line"], d["in_loop"], d["drops_error"]) for d in data] == [(17, True, True)]
+    assert find_defers_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
+
+
+def test_generic_receivers_and_test_files(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "buffer.go": "package files\n\ntype Buffer[T any] struct{ items []T }\n\n"
+                     "func (b *Buffer[T]) Drain() {\n\tfor range b.items {\n"
+                     "\t\tdefer println()\n\t}\n}\n",
+        "buffer_test.go": "package files\n\nfunc TestDrain() {\n\tdefer println()\n}\n",
+    })
+
+    assert [(d.function, d.in_loop, d.loop_line) for d in find_defers(files)] == [
+        ("Buffer.Drain", True, 6)]
+    assert [d.function for d in find_defers(files, include_tests=True)] == [
+        "Buffer.Drain", "TestDrain"]
@@ -0,0 +1,160 @@
+"""Tests for golang.diagram: Mermaid class diagrams of a package — members,
+embedding and field relationships, interface implementations."""
+
+import json
+
+from scantool.golang.diagram import build_class_diagram, format_mermaid
+from scantool.golang.syntax import load_go_files
+from scantool.server import class_diagram
+from conftest import load_go_tree
+
+TYPES = """package store
+
+import (
+	"io"
+	"sync"
+)
+
+type Base struct {
+	ID int
+}
+
+type User struct {
+	Name string
+	tags []string
+}
+
+type Store struct {
+	Base
+	sync.Mutex
+	users        map[string]*User
+	owner, admin *User
+	meta         struct{ A int }
+	events       <-chan Event
+}
+
+type Event string
+
+type Getter interface {
+	Get(key string) (*User, error)
+}
+
+type ReadGetter interface {
+	Getter
+	Read() int
+}
+
+type Closer interface {
+	io.Closer
+}
+
+type List[T any] struct {
+	items []T
+}
+
+type link = User
+"""
+
+METHODS = """package store
+
+func (s *Store) Get(key string) (*User, error) { return s.users[key], nil }
+
+func (s Store) Read() int { return 0 }
+
+func (u User) Get(key string) (*User, error) { return &u, nil }
+
+func (l *List[T]) Push(v T) {}
+"""
+
+
+def diagram_of(tmp_path):
+    files = load_go_tree(tmp_path, {"types.go": TYPES, "methods.go": METHODS,
+                                    "types_test.go": "package store\n\ntype fake struct{}\n"})
+    return build_class_diagram(files, str(tmp_path))
+
+
+def types_of(diagram):
+    return {t.name: t for t in diagram.types}
+
+
+def edges_of(diagram):
+    return [(e.source, e.kind, e.target, e.label, e.many) for e in diagram.edges]
+
+
+class TestBuildClassDiagram:
+    def test_types_and_members(self, tmp_path):
+        types = types_of(diagram_of(tmp_path))
+
+        assert list(types) == ["Base", "User", "Store", "Event", "Getter", "ReadGetter",
+                               "Closer", "List", "link"]
+        assert types["Store"].fields == [
+            "Base", "sync.Mutex", "users map[string]*User", "owner *User", "admin *User",
+            "meta struct", "events <-chan Event"]
+        assert types["Store"].methods == ["Get(key string) (*User, error)", "Read() int"]
+        assert types["List"].type_params == ["T"]
+        assert types["List"].methods == ["Push(v T)"]
+        assert types["Closer"].fields == ["io.Closer"]
+        assert (types["link"].kind, types["link"].underlying) == ("alias", "User")
+
+    def test_relationships(self, tmp_path):
+        assert edges_of(diagram_of(tmp_path)) == [
+            ("Store", "embeds", "Base", None, False),
+            ("Store", "field", "User", "users", True),
+            ("Store", "field", "User", "owner, admin", False),
+            ("Store", "field", "Event", "events", True),
+            ("ReadGetter", "embeds", "Getter", None, False),
+            ("Store", "implements", "Getter", "pointer", False),
+            ("User", "implements", "Getter", None, False),
+            ("Store", "implements", "ReadGetter", "pointer", False),
+        ]
+
+    def test_mermaid(self, tmp_path):
+        text = format_mermaid(diagram_of(tmp_path))
+        lines = text.splitlines()
+
+        assert lines[0] == "classDiagram"
+        assert "    class Store {" in lines
+        assert "        -events ←chan Event" in lines
+        assert "        +sync.Mutex" in lines
+        assert "        +Get(key string) (*User, error)" in lines
+        assert "    class List~T~ {" in lines
+        assert "    class `link` {" in lines
+        assert "        <<string>>" in lines
+        assert '    Store --> "*" User : users' in lines
+        assert "    Store *-- Base" in lines
+        assert "    ReadGetter --|> Getter" in lines
+        assert "    Store ..|> Getter : pointer" in lines
+        assert "{" not in "".join(line for line in lines if not line.endswith(" {"))
+
+    def test_type_set_members(self, tmp_path):
+        (tmp_path / "num.go").write_text(
+            "package num\n\ntype Number interface {\n\t~int | ~float64\n}\n")
+
+        text = format_mermaid(build_class_diagram(load_go_files(str(tmp_path)), str(tmp_path)))
+
+        assert "        ≈int | ≈float64" in text.splitlines()
+
+
+class TestTool:
+    def test_markdown_fence(self, tmp_path):
+        (tmp_path / "types.go").write_text(TYPES)
+
+        text = class_diagram.fn(str(tmp_path), output_format="markdown")[0].text
+
+        assert text.startswith("```mermaid\nclassDiagram\n")
+        assert text.endswith("\n```")
+
+    def test_json(self, tmp_path):
+        (tmp_path / "types.go").write_text(TYPES)
+        (tmp_path / "methods.go").write_text(METHODS)
+
+        data = json.loads(class_diagram.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data["package"] == "store"
+        assert {"source": "User", "target": "Getter", "kind": "implements",
+                "label": None, "many": False} in data["edges"]
+
+    def test_missing_directory(self, tmp_path):
+        text = class_diagram.fn(str(tmp_path / "nope"))[0].text
+
+        assert text.startswith("Error: Path not found")
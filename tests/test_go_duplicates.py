@@ -0,0 +1,141 @@
+"""Tests for golang.duplicates: function bodies grouped when identical or
+identical up to consistent renaming, comments and formatting ignored."""
+
+import json
+
+from scantool.golang.duplicates import find_duplicates, format_duplicates
+from scantool.golang.syntax import load_go_files
+from scantool.server import find_duplicates as find_duplicates_tool
+from conftest import load_go_tree
+
+USERS = """package store
+
+func loadUser(db *DB, id int) (*User, error) {
+	row := db.Query("SELECT * FROM users WHERE id = ?", id)
+	var u User
+	if err := row.Scan(&u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *Store) Flush() error {
+	for _, item := range s.items {
+		if err := s.write(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func short() int { return 1 }
+"""
+
+ACCOUNTS = """package store
+
+// loadAccount is loadUser with other names.
+func loadAccount(conn *DB, key int) (*Account, error) {
+	r := conn.Query("SELECT * FROM users WHERE id = ?", key) // same query
+	var a Account
+	if err := r.Scan(&a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (c *Cache) Flush() error {
+	for _, item := range c.items {
+		if err := c.write(item);   err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadOther(db *DB, id int) (*User, error) {
+	row := db.Query("SELECT * FROM other WHERE id = ?", id)
+	var u User
+	if err := row.Scan(&u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func two() int { return 1 }
+"""
+
+
+def groups_of(tmp_path, **kwargs):
+    files = load_go_tree(tmp_path, {"users.go": USERS, "accounts.go": ACCOUNTS})
+    return find_duplicates(files, **kwargs)
+
+
+def names(group):
+    return [f.name for f in group.functions]
+
+
+class TestFindDuplicates:
+    def test_renamed_copy_grouped(self, tmp_path):
+        load = next(g for g in groups_of(tmp_path) if "loadUser" in names(g))
+
+        assert names(load) == ["loadAccount", "loadUser"]
+        assert load.kind == "renamed"
+
+    def test_receiver_rename_and_formatting_are_renames(self, tmp_path):
+        flush = next(g for g in groups_of(tmp_path) if "Store.Flush" in names(g))
+
+        assert names(flush) == ["Cache.Flush", "Store.Flush"]
+        assert flush.kind == "renamed"
+        assert flush.lines == 8
+
+    def test_exact_copy_and_literal_differences(self, tmp_path):
+        (tmp_path / "users.go").write_text(USERS)
+        (tmp_path / "copy.go").write_text(USERS.replace("func short", "func other"))
+
+        groups = find_duplicates(load_go_files(str(tmp_path)))
+
+        assert [(g.kind, names(g)) for g in groups] == [
+            ("exact", ["loadUser", "loadUser"]), ("exact", ["Store.Flush", "Store.Flush"])]
+
+    def test_different_literal_is_no_duplicate(self, tmp_path):
+        assert all("loadOther" not in names(g) for g in groups_of(tmp_path))
+
+    def test_min_lines(self, tmp_path):
+        assert not any("short" in names(g) for g in groups_of(tmp_path))
+        assert any(names(g) == ["two", "short"] for g in groups_of(tmp_path, min_lines=1))
+
+    def test_generic_receivers_and_test_files(self, tmp_path):
+        generic = USERS[USERS.index("func (s *Store)"):USERS.index("func short")]
+        files = load_go_tree(tmp_path, {
+            "users.go": USERS, "users_test.go": USERS,
+            "set.go": "package store\n\n" + generic.replace("*Store", "*Set[T]")})
+
+        assert [(g.kind, names(g)) for g in find_duplicates(files)] == [
+            ("exact", ["Set.Flush", "Store.Flush"])]
+        assert [names(g) for g in find_duplicates(files, include_tests=True)] == [
+            ["Set.Flush", "Store.Flush", "Store.Flush"], ["loadUser", "loadUser"]]
+
+    def test_format(self, tmp_path):
+        text = format_duplicates(groups_of(tmp_path), "store")
+
+        assert text.startswith("2 duplicate groups in store (0 exact, 2 renamed)")
+        assert "\n- renamed, 2x, 8+ lines\n" in text
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "users.go").write_text(USERS)
+        (tmp_path / "accounts.go").write_text(ACCOUNTS)
+
+        data = json.loads(find_duplicates_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert [(g["kind"], [f["name"] for f in g["functions"]]) for g in data] == [
+            ("renamed", ["loadAccount", "loadUser"]),
+            ("renamed", ["Cache.Flush", "Store.Flush"])]
+
+    def test_none_found(self, tmp_path):
+        (tmp_path / "a.go").write_text("package a\n\nfunc A() {}\n")
+
+        assert find_duplicates_tool.fn(str(tmp_path))[0].text.startswith(
+            "No duplicate function bodies")
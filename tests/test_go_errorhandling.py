@@ -0,0 +1,112 @@
+"""Tests for golang.errorhandling: per error-returning function, how its
+same-package call sites handle the error."""
+
+import json
+
+from scantool.golang.errorhandling import error_handling_report, format_error_handling
+from scantool.server import error_handling_report as error_handling_report_tool
+from conftest import load_go_tree
+
+STORE = """package store
+
+type Store struct{}
+
+func (s *Store) Save(key string) error { return nil }
+
+func (s *Store) Load(key string) (string, error) { return "", nil }
+
+func Open(path string) (*Store, error) { return &Store{}, nil }
+
+func helper() int { return 1 }
+
+func run() error {
+	s, err := Open("db")
+	if err != nil {
+		return err
+	}
+	s.Save("a")
+	_ = s.Save("b")
+	defer s.Save("c")
+	if err := s.Save("d"); err != nil {
+		return err
+	}
+	v, _ := s.Load("x")
+	_, err = s.Load(v)
+	go func() {
+		s.Save("e")
+	}()
+	helper()
+	return s.Save("f")
+}
+
+func wrap(s *Store) error {
+	return fmt.Errorf("save: %w", s.Save("g"))
+}
+"""
+
+
+def report_of(tmp_path, **kwargs):
+    return error_handling_report(load_go_tree(tmp_path, {"store.go": STORE}), **kwargs)
+
+
+class TestErrorHandlingReport:
+    def test_ranked_by_ignore_ratio(self, tmp_path):
+        report = report_of(tmp_path)
+
+        assert [(f.name, len(f.sites), f.dropped) for f in report] == [
+            ("Store.Save", 7, 4), ("Store.Load", 2, 1), ("Open", 1, 0)]
+
+    def test_handling_per_site(self, tmp_path):
+        save, load, _ = report_of(tmp_path)
+
+        assert [(s.line, s.caller, s.handling) for s in save.sites] == [
+            (18, "run", "ignored"),
+            (19, "run", "blank"),
+            (20, "run", "ignored"),
+            (21, "run", "checked"),
+            (27, "func literal in run", "ignored"),
+            (30, "run", "returned"),
+            (34, "wrap", "used"),
+        ]
+        assert [s.handling for s in load.sites] == ["blank", "checked"]
+
+    def test_min_calls(self, tmp_path):
+        assert [f.name for f in report_of(tmp_path, min_calls=2)] == ["Store.Save", "Store.Load"]
+
+    def test_test_file_callers(self, tmp_path):
+        files = load_go_tree(tmp_path, {
+            "store.go": STORE,
+            "store_test.go": "package store\n\nfunc TestSave(s *Store) {\n\ts.Save(\"t\")\n}\n"})
+
+        save = error_handling_report(files)[0]
+        assert len(save.sites) == 7
+        save = error_handling_report(files, include_tests=True)[0]
+        assert (save.name, len(save.sites), save.dropped) == ("Store.Save", 8, 5)
+        assert [(s.caller, s.handling) for s in save.sites if s.file.endswith("_test.go")] == [
+            ("TestSave", "ignored")]
+
+    def test_format(self, tmp_path):
+        text = format_error_handling(report_of(tmp_path), "pkg")
+
+        assert text.startswith("3 error-returning functions with call sites in pkg "
+                               "(2 with dropped errors) — same-package calls only")
+        assert ("- Store.Save (store.go@5): 4/7 calls drop the error (57%) — "
+                "1 checked, 1 returned, 1 used, 1 blank, 3 ignored") in text
+        assert "    dropped: store.go@18 in run (ignored), store.go@19 in run (blank)" in text
+        assert format_error_handling([], "pkg") == \
+            "No error-returning functions with same-package call sites in pkg"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "store.go").write_text(STORE)
+
+        data = json.loads(error_handling_report_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data[1]["name"] == "Store.Load"
+        assert data[1]["handling"] == {"checked": 1, "returned": 0, "used": 0,
+                                       "blank": 1, "ignored": 0}
+        assert data[1]["ignore_ratio"] == 0.5
+
+    def test_missing_path(self, tmp_path):
+        assert error_handling_report_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
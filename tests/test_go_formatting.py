@@ -0,0 +1,113 @@
+"""Tests for golang.formatting: gofmt verdicts (formatted / unformatted /
+unknown) and diffs. A stub gofmt script stands in for the Go toolchain;
+the real-gofmt test is skipped when it isn't installed."""
+
+import json
+import shutil
+
+import pytest
+
+from scantool.golang.formatting import check_formatting, format_format_checks
+from scantool.server import check_formatting as check_formatting_tool
+from conftest import load_go_tree
+
+CLEAN = "package a\n\nfunc F() {}\n"
+MESSY = "package a\n\nfunc   F() {}\n"
+BROKEN = "package a\n\nfunc F( {\n"
+
+requires_gofmt = pytest.mark.skipif(shutil.which("gofmt") is None, reason="gofmt not installed")
+
+
+def fake_gofmt(tmp_path, output=CLEAN, exit_code=0):
+    """A gofmt stand-in that prints a fixed result, whatever its input."""
+    script = tmp_path / "bin" / "gofmt"
+    script.parent.mkdir()
+    script.write_text(f"#!/bin/sh\ncat > /dev/null\nprintf '%s' '{output}'\n"
+                      f"echo 'stdin:1:1: boom' >&2\nexit {exit_code}\n")
+    script.chmod(0o755)
+    return str(script)
+
+
+class TestCheckFormatting:
+    def test_formatted_and_unformatted_with_diff(self, tmp_path):
+        files = load_go_tree(tmp_path, {"clean.go": CLEAN, "messy.go": MESSY})
+
+        checks = check_formatting(files, with_diff=True, gofmt=fake_gofmt(tmp_path))
+
+        assert [(c.state, c.diff is None) for c in checks] == [
+            ("formatted", True), ("unformatted", False)]
+        assert "-func   F() {}\n+func F() {}\n" in checks[1].diff
+        assert checks[1].diff.startswith(f"--- a/{files[1].path}\n")
+
+    def test_diff_only_on_request(self, tmp_path):
+        files = load_go_tree(tmp_path, {"messy.go": MESSY})
+
+        check = check_formatting(files, gofmt=fake_gofmt(tmp_path))[0]
+
+        assert (check.state, check.diff) == ("unformatted", None)
+
+    def test_crlf_file_is_checked_as_on_disk(self, tmp_path):
+        files = load_go_tree(tmp_path, {"crlf.go": CLEAN.replace("\n", "\r\n")})
+
+        check = check_formatting(files, with_diff=True, gofmt=fake_gofmt(tmp_path))[0]
+
+        # The parsers see LF lines equal to gofmt's output; the file has CRLF
+        assert check.state == "unformatted"
+        assert "-package a\r\n" in check.diff and "+package a\n" in check.diff
+
+    def test_syntax_errors_are_unknown(self, tmp_path):
+        files = load_go_tree(tmp_path, {"broken.go": BROKEN})
+
+        check = check_formatting(files, gofmt=fake_gofmt(tmp_path))[0]
+
+        assert (check.state, check.reason) == ("unknown", "syntax errors")
+
+    def test_gofmt_failure_is_unknown(self, tmp_path):
+        files = load_go_tree(tmp_path, {"clean.go": CLEAN})
+
+        check = check_formatting(files, gofmt=fake_gofmt(tmp_path, exit_code=2))[0]
+
+        assert (check.state, check.reason) == ("unknown", "stdin:1:1: boom")
+
+    def test_missing_gofmt_is_unknown(self, tmp_path, monkeypatch):
+        monkeypatch.setattr(shutil, "which", lambda name: None)
+        files = load_go_tree(tmp_path, {"clean.go": CLEAN})
+
+        check = check_formatting(files)[0]
+
+        assert (check.state, check.reason) == ("unknown", "gofmt not found on PATH")
+
+    def test_format(self, tmp_path):
+        files = load_go_tree(tmp_path, {"broken.go": BROKEN, "clean.go": CLEAN, "messy.go": MESSY})
+
+        text = format_format_checks(check_formatting(files, gofmt=fake_gofmt(tmp_path)), "pkg")
+
+        assert text.splitlines()[0] == "gofmt: 3 files in pkg — 1 formatted, 1 unformatted, 1 unknown"
+        assert f"? {files[0].path} (syntax errors)" in text
+        assert f"✗ {files[2].path}" in text
+
+    @requires_gofmt
+    def test_real_gofmt(self, tmp_path):
+        files = load_go_tree(tmp_path, {"clean.go": CLEAN, "messy.go": MESSY})
+
+        assert [c.state for c in check_formatting(files)] == ["formatted", "unformatted"]
+
+    @requires_gofmt
+    def test_real_gofmt_rewrites_crlf_and_bom(self, tmp_path):
+        files = load_go_tree(tmp_path, {"bom.go": "\ufeff" + CLEAN,
+                                        "crlf.go": CLEAN.replace("\n", "\r\n")})
+
+        assert [c.state for c in check_formatting(files)] == ["unformatted", "unformatted"]
+
+
+class TestTool:
+    def test_json_output(self, tmp_path, monkeypatch):
+        gofmt = fake_gofmt(tmp_path)
+        monkeypatch.setattr(shutil, "which", lambda name: gofmt)
+        (tmp_path / "messy.go").write_text(MESSY)
+
+        data = json.loads(check_formatting_tool.fn(str(tmp_path), include_diff=True,
+                                                   output_format="json")[0].text)
+
+        assert data[0]["gofmt"] == "unformatted"
+        assert "+func F() {}" in data[0]["diff"]
@@ -0,0 +1,71 @@
+"""Tests for golang.generate: //go:generate directives."""
+
+import json
+
+from scantool.golang.generate import find_generate_directives, format_generate_directives
+from scantool.server import find_generate_directives as find_generate_directives_tool
+from conftest import load_go_tree
+
+KIND = """// Package kind has generated String methods.
+package kind
+
+//go:generate stringer -type=Kind
+//go:generate -command mock go run github.com/golang/mock/mockgen
+//go:generate mock -source=kind.go -destination=mock_kind.go
+
+// go:generate not-a-directive
+//go:generatex nope
+/*
+//go:generate inside-a-block
+*/
+
+type Kind int
+
+func f() {
+	//go:generate indented-is-ignored
+}
+"""
+
+TEST = """package kind
+
+//go:generate go run gen_test_data.go
+"""
+
+
+def directives_of(tmp_path, include_tests=True):
+    files = load_go_tree(tmp_path, {"kind.go": KIND, "kind_test.go": TEST})
+    return find_generate_directives(files, include_tests)
+
+
+def test_only_real_directives(tmp_path):
+    found = [(d.line, d.command) for d in directives_of(tmp_path)]
+    assert found == [
+        (4, "stringer -type=Kind"),
+        (5, "-command mock go run github.com/golang/mock/mockgen"),
+        (6, "mock -source=kind.go -destination=mock_kind.go"),
+        (3, "go run gen_test_data.go"),
+    ]
+
+
+def test_command_alias_and_program(tmp_path):
+    stringer, alias, mock, _ = directives_of(tmp_path)
+    assert stringer.program == "stringer" and stringer.alias is None
+    assert alias.alias == "mock" and alias.program == "mock"
+    assert mock.program == "mock"
+    assert stringer.package == "kind"
+
+
+def test_tests_optional(tmp_path):
+    assert len(directives_of(tmp_path, include_tests=False)) == 3
+
+
+def test_format_and_tool(tmp_path):
+    text = format_generate_directives(directives_of(tmp_path), "kind")
+    assert text.splitlines()[0] == "4 //go:generate directives in kind (runs go, mock, stringer)"
+    assert "- @5 -command mock go run github.com/golang/mock/mockgen  [defines mock]" in text
+    assert format_generate_directives([], "kind") == "No //go:generate directives found in kind"
+
+    data = json.loads(find_generate_directives_tool.fn(str(tmp_path), output_format="json")[0].text)
+    assert data[0] == {"file": data[0]["file"], "line": 4, "package": "kind",
+                       "command": "stringer -type=Kind", "program": "stringer"}
+    assert find_generate_directives_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
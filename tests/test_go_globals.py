@@ -0,0 +1,122 @@
+"""Tests for golang.globals: package-level vars with type, initializer and
+how they are initialized."""
+
+import json
+
+from scantool.golang.globals import format_globals, list_globals
+from scantool.golang.syntax import load_go_files
+from scantool.server import list_globals as list_globals_tool
+from conftest import load_go_tree
+
+STATE = """package state
+
+import (
+	"errors"
+	"sync"
+)
+
+type Registry struct{ items map[string]int }
+
+var mu sync.Mutex
+
+var (
+	// ErrMissing is returned for unknown keys.
+	ErrMissing = errors.New("missing")
+	limit      = 10
+	ratio      = -0.5
+	name       string = "state"
+	registry   = &Registry{items: map[string]int{}}
+	buffer     = make([]byte, 0, 64)
+	cache      = newCache()
+	handler    = func(key string) error { return load(key) }
+	size       = Registry(other)
+)
+
+var first, second = split()
+
+var x, y = 1, "two"
+
+var _ fmt.Stringer = (*Registry)(nil)
+
+func setup() {
+	var local = 3
+	_ = local
+}
+"""
+
+
+def globals_of(tmp_path, **kwargs):
+    return list_globals(load_go_tree(tmp_path, {"state.go": STATE}), **kwargs)
+
+
+class TestListGlobals:
+    def test_blocks_and_vars(self, tmp_path):
+        blocks = globals_of(tmp_path)
+
+        assert [(b.line, b.grouped, [v.name for v in b.vars]) for b in blocks] == [
+            (10, False, ["mu"]),
+            (12, True, ["ErrMissing", "limit", "ratio", "name", "registry", "buffer",
+                        "cache", "handler", "size"]),
+            (25, False, ["first", "second"]),
+            (27, False, ["x", "y"]),
+        ]
+
+    def test_types_and_init(self, tmp_path):
+        variables = {v.name: v for b in globals_of(tmp_path) for v in b.vars}
+
+        assert [(n, v.type, v.type_inferred, v.init) for n, v in variables.items()] == [
+            ("mu", "sync.Mutex", False, "zero"),
+            ("ErrMissing", "error", True, "runtime"),
+            ("limit", "int", True, "static"),
+            ("ratio", "float64", True, "static"),
+            ("name", "string", False, "static"),
+            ("registry", "*Registry", True, "static"),
+            ("buffer", "[]byte", True, "static"),
+            ("cache", None, False, "runtime"),
+            ("handler", "func(key string) error", True, "static"),
+            ("size", "Registry", True, "static"),
+            ("first", None, False, "runtime"),
+            ("second", None, False, "runtime"),
+            ("x", "int", True, "static"),
+            ("y", "string", True, "static"),
+        ]
+        assert variables["cache"].initializer == "newCache()"
+        assert (variables["first"].initializer, variables["first"].result) == ("split()", 0)
+        assert variables["second"].result == 1
+        assert variables["mu"].initializer is None
+        assert variables["ErrMissing"].exported and not variables["limit"].exported
+
+    def test_tests_skipped(self, tmp_path):
+        (tmp_path / "state_test.go").write_text("package state\n\nvar fixture = load()\n")
+
+        assert list_globals(load_go_files(str(tmp_path))) == []
+        assert [v.name for b in list_globals(load_go_files(str(tmp_path)), include_tests=True)
+                for v in b.vars] == ["fixture"]
+
+    def test_format(self, tmp_path):
+        text = format_globals(globals_of(tmp_path), "pkg")
+
+        assert text.startswith("14 package-level variables in pkg (1 zero, 9 static, 4 runtime)")
+        assert "- @10 mu sync.Mutex  [zero]" in text
+        assert "- var ( @12" in text
+        assert "    cache = newCache()  [runtime]" in text
+        assert "    limit = 10  (int)  [static]" in text
+        assert "- @25 second = split() [1]  [runtime]" in text
+        assert format_globals([], "pkg") == "No package-level variables found in pkg"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "state.go").write_text(STATE)
+
+        data = json.loads(list_globals_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data[0] == {"file": str((tmp_path / "state.go").resolve()), "line": 10,
+                           "grouped": False,
+                           "vars": [{"name": "mu", "file": str((tmp_path / "state.go").resolve()),
+                                     "line": 10, "exported": False, "type": "sync.Mutex",
+                                     "initializer": None, "init": "zero"}]}
+        assert data[2]["vars"][1]["result"] == 1
+
+    def test_missing_path(self, tmp_path):
+        assert list_globals_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
@@ -0,0 +1,184 @@
+"""Tests for golang.hierarchy: embeds and embedded-by per type, the
+embedding forest, and members promoted through embedding."""
+
+import json
+
+from scantool.golang.hierarchy import build_type_hierarchy, format_type_hierarchy
+from scantool.server import type_hierarchy
+from conftest import load_go_tree
+
+TYPES = """package server
+
+import "sync"
+
+type Logger interface {
+	Log(msg string)
+}
+
+type Base struct {
+	Logger
+	ID   int
+	Name string
+}
+
+type Cache struct {
+	size int
+}
+
+type Server struct {
+	Base
+	*Cache
+	sync.Mutex
+	Name string
+}
+
+type Plain struct {
+	n int
+}
+"""
+
+METHODS = """package server
+
+func (b Base) Describe() string { return b.Name }
+
+func (c *Cache) Flush() {}
+
+func (b *Base) Reset() {}
+"""
+
+
+def hierarchy_of(tmp_path, files=None, **kwargs):
+    files = load_go_tree(tmp_path, files or {"types.go": TYPES, "methods.go": METHODS})
+    return build_type_hierarchy(files, str(tmp_path), **kwargs)
+
+
+def types_of(hierarchy):
+    return {t.name: t for t in hierarchy.types}
+
+
+def promoted_of(hierarchy_type):
+    return {p.name: (p.member, ".".join(p.via), p.pointer_only)
+            for p in hierarchy_type.promoted}
+
+
+class TestBuildTypeHierarchy:
+    def test_embeds_and_embedded_by(self, tmp_path):
+        hierarchy = hierarchy_of(tmp_path)
+        types = types_of(hierarchy)
+
+        assert list(types) == ["Logger", "Base", "Cache", "Server"]  # Plain embeds nothing
+        assert [(e.type, e.target, e.kind, e.pointer) for e in types["Server"].embeds] == [
+            ("Base", "Base", "struct", False),
+            ("*Cache", "Cache", "struct", True),
+            ("sync.Mutex", None, "external", False)]
+        assert types["Server"].embedded_by == []
+        assert types["Base"].embedded_by == ["Server"]
+        assert types["Logger"].embedded_by == ["Base"]
+        assert types["Server"].unresolved == ["sync.Mutex"]
+        assert hierarchy.roots == ["Server"]
+
+    def test_promoted_members(self, tmp_path):
+        promoted = promoted_of(types_of(hierarchy_of(tmp_path))["Server"])
+
+        assert promoted == {
+            "Logger": ("field", "Base", False),
+            "ID": ("field", "Base", False),
+            "Describe": ("method", "Base", False),
+            "Reset": ("method", "Base", True),   # *Base receiver, Base embedded by value
+            "size": ("field", "Cache", False),
+            "Flush": ("method", "Cache", False),  # *Cache embedded
+            "Log": ("method", "Base.Logger", False),
+        }  # Name: Server's own field shadows Base.Name
+
+    def test_same_depth_is_ambiguous(self, tmp_path):
+        hierarchy = hierarchy_of(tmp_path, {"a.go": """package a
+
+type A struct{ ID int }
+
+type B struct{ ID int }
+
+type C struct {
+	A
+	B
+}
+"""})
+        c = types_of(hierarchy)["C"]
+
+        assert c.ambiguous == ["ID"]
+        assert "ID" not in promoted_of(c)
+        assert hierarchy.roots == ["C"]
+
+    def test_pointer_cycle(self, tmp_path):
+        hierarchy = hierarchy_of(tmp_path, {"a.go": """package a
+
+type A struct {
+	*B
+	x int
+}
+
+type B struct {
+	*A
+	y int
+}
+"""})
+
+        assert hierarchy.roots == ["A"]
+        assert promoted_of(types_of(hierarchy)["A"])["y"] == ("field", "B", False)
+        assert "  A (struct)" in format_type_hierarchy(hierarchy).splitlines()
+        assert "      *A (struct) (cycle)" in format_type_hierarchy(hierarchy).splitlines()
+
+    def test_generic_embeds_and_files_outside_the_package(self, tmp_path):
+        hierarchy = hierarchy_of(tmp_path, {
+            "a.go": "package a\n\ntype List[T any] struct{ items []T }\n\n"
+                    "func (l *List[T]) Len() int { return 0 }\n\n"
+                    "type Names struct {\n\t*List[string]\n}\n",
+            "a_test.go": "package a\n\ntype fixture struct{ Names }\n",
+            "sub/b.go": "package sub\n\ntype Other struct{ Names }\n",
+        })
+        names = types_of(hierarchy)["Names"]
+
+        assert [(e.type, e.target, e.pointer) for e in names.embeds] == [
+            ("*List[string]", "List", True)]
+        assert promoted_of(names) == {"items": ("field", "List", False),
+                                      "Len": ("method", "List", False)}
+        assert names.embedded_by == [] and hierarchy.roots == ["Names"]
+
+    def test_without_promoted(self, tmp_path):
+        hierarchy = hierarchy_of(tmp_path, include_promoted=False)
+
+        assert all(not t.promoted for t in hierarchy.types)
+
+    def test_format(self, tmp_path):
+        lines = format_type_hierarchy(hierarchy_of(tmp_path)).splitlines()
+
+        assert lines[0].endswith(": 4 types, 1 trees")
+        assert lines[2:6] == ["forest (outer → embedded):", "  Server (struct)",
+                              "    Base (struct)", "      Logger (interface)"]
+        assert "    *Cache (struct)" in lines
+        assert "  embeds: Base, *Cache, sync.Mutex (external)" in lines
+        assert "  embedded by: Server" in lines
+        assert "  promoted method: Reset()  via Base  [*T only]" in lines
+        assert "  promoted method: Log(msg string)  via Base.Logger" in lines
+
+
+class TestTool:
+    def test_json(self, tmp_path):
+        (tmp_path / "types.go").write_text(TYPES)
+
+        data = json.loads(type_hierarchy.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data["package"] == "server"
+        assert data["roots"] == ["Server"]
+        assert [t["name"] for t in data["types"]] == ["Logger", "Base", "Cache", "Server"]
+
+    def test_no_embedding(self, tmp_path):
+        (tmp_path / "a.go").write_text("package a\n\ntype A struct{ n int }\n")
+
+        text = type_hierarchy.fn(str(tmp_path))[0].text
+
+        assert text.startswith("No embedding in package a")
+
+    def test_missing_directory(self, tmp_path):
+        text = type_hierarchy.fn(str(tmp_path / "nope"))[0].text
+
+        assert text.startswith("Error: Path not found")
@@ -0,0 +1,142 @@
+"""Tests for golang.imports: per-package import edges, edge kinds from the
+go.mod module path, cycles among internal packages, per-file import
+details with usage."""
+
+import json
+
+from scantool.golang.imports import (
+    build_import_graph,
+    classify,
+    default_names,
+    format_import_graph,
+    import_list,
+)
+from scantool.golang.syntax import load_go_files
+from scantool.scanner import FileScanner
+from scantool.server import import_graph, list_imports
+from conftest import write_tree
+
+MODULE = {
+    "go.mod": "module example.com/shop\n\ngo 1.22\n",
+    "main.go": 'package main\n\nimport (\n\t"fmt"\n\t"example.com/shop/orders"\n)\n\n'
+               'func main() { fmt.Println(orders.Total()) }\n',
+    "orders/orders.go": 'package orders\n\nimport (\n\t"github.com/google/uuid"\n'
+                        '\t"example.com/shop/billing"\n)\n\nfunc Total() int { return billing.X }\n',
+    "orders/orders_test.go": 'package orders_test\n\nimport "example.com/shop/orders"\n',
+    "billing/billing.go": 'package billing\n\nimport _ "example.com/shop/orders"\n\nconst X = 1\n',
+}
+
+
+def graph_of(tmp_path, include_tests=False):
+    write_tree(tmp_path, MODULE)
+    return build_import_graph(load_go_files(str(tmp_path)), str(tmp_path),
+                              include_tests=include_tests)
+
+
+class TestImportGraph:
+    def test_edges_aggregated_per_package_with_kinds(self, tmp_path):
+        graph = graph_of(tmp_path)
+
+        edges = {(e.source, e.target): e.kind for e in graph.edges}
+        assert graph.module == "example.com/shop"
+        assert edges == {
+            ("example.com/shop", "fmt"): "std",
+            ("example.com/shop", "example.com/shop/orders"): "internal",
+            ("example.com/shop/orders", "github.com/google/uuid"): "external",
+            ("example.com/shop/orders", "example.com/shop/billing"): "internal",
+            ("example.com/shop/billing", "example.com/shop/orders"): "internal",
+        }
+
+    def test_internal_cycle_detected(self, tmp_path):
+        graph = graph_of(tmp_path)
+
+        assert graph.cycles == [["example.com/shop/billing", "example.com/shop/orders"]]
+        assert "IMPORT CYCLES (1)" in format_import_graph(graph, str(tmp_path))
+
+    def test_test_files_opt_in(self, tmp_path):
+        graph = graph_of(tmp_path, include_tests=True)
+
+        test_edge = next(e for e in graph.edges
+                         if any(f.endswith("orders_test.go") for f in e.files))
+        assert test_edge.target == "example.com/shop/orders"
+
+    def test_classify_without_module(self):
+        assert classify("net/http", None) == "std"
+        assert classify("golang.org/x/sync/errgroup", None) == "external"
+        assert classify("example.com/shop/orders", "example.com/shop") == "internal"
+
+    def test_tool_json_output(self, tmp_path):
+        write_tree(tmp_path, MODULE)
+
+        data = json.loads(import_graph.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert {"from": "example.com/shop", "to": "fmt", "kind": "std"} in data["edges"]
+        assert data["cycles"]
+
+
+DETAILS = """package app
+
+import (
+\t"fmt"
+\tstdlog "log"
+\t"os"
+\t_ "embed"
+\t. "strings"
+\t"gopkg.in/yaml.v3"
+\t"github.com/mattn/go-isatty"
+\t"example.com/api/v2"
+)
+
+var out = isatty.IsTerminal(0)
+
+func Run(c api.Client) error {
+\tfmt.Println(ToUpper("x"))
+\treturn yaml.Unmarshal(nil, nil)
+}
+"""
+
+
+def details_of(tmp_path):
+    (tmp_path / "app.go").write_text(DETAILS)
+    return {imp.path: imp for imp in import_list(load_go_files(str(tmp_path / "app.go"))[0])}
+
+
+class TestImportList:
+    def test_aliases_and_flags(self, tmp_path):
+        imports = details_of(tmp_path)
+
+        assert list(imports) == ["fmt", "log", "os", "embed", "strings", "gopkg.in/yaml.v3",
+                                 "github.com/mattn/go-isatty", "example.com/api/v2"]
+        assert imports["log"].name == "stdlog" and imports["fmt"].name is None
+        assert imports["embed"].blank and imports["embed"].used is None
+        assert imports["strings"].dot and imports["strings"].used is None
+        assert imports["fmt"].line == 4
+
+    def test_usage(self, tmp_path):
+        imports = details_of(tmp_path)
+
+        assert {path for path, imp in imports.items() if imp.used is False} == {"log", "os"}
+        assert imports["example.com/api/v2"].used  # qualified type api.Client
+
+    def test_default_names(self):
+        assert default_names("gopkg.in/yaml.v3") == {"yaml"}
+        assert "isatty" in default_names("github.com/mattn/go-isatty")
+        assert default_names("example.com/api/v2") == {"api"}
+
+    def test_in_file_result_metadata(self, tmp_path):
+        (tmp_path / "app.go").write_text(DETAILS)
+
+        metadata = FileScanner().scan_file(str(tmp_path / "app.go"))[0].file_metadata
+
+        assert metadata["imports"][1] == {"path": "log", "name": "stdlog", "line": 5,
+                                          "blank": False, "dot": False, "used": False}
+
+    def test_tool_unused_only(self, tmp_path):
+        (tmp_path / "app.go").write_text(DETAILS)
+
+        text = list_imports.fn(str(tmp_path), unused_only=True)[0].text
+
+        assert text.splitlines()[0].endswith("8 imports in 1 files under " + str(tmp_path)
+                                             + ", 2 unused")
+        assert '  stdlog "log" @5  UNUSED' in text
+        assert '"fmt"' not in text
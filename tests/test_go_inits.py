@@ -0,0 +1,70 @@
+"""Tests for golang.inits: every init function in run order."""
+
+import json
+
+from scantool.golang.inits import find_inits, format_inits
+from scantool.server import find_inits as find_inits_tool
+from conftest import load_go_tree
+
+DRIVERS = """package db
+
+import "database/sql"
+
+func init() {
+	sql.Register("fake", &fakeDriver{})
+}
+
+func init() {
+	defaultDSN = os.Getenv("DB_DSN")
+	if defaultDSN == "" {
+		defaultDSN = "memory"
+	}
+}
+
+type Conn struct{}
+
+func (c *Conn) init() {}
+"""
+
+A_CONFIG = """package db
+
+func init() { loadConfig(); loadConfig() }
+"""
+
+DB_TEST = """package db_test
+
+func init() {}
+"""
+
+
+def inits_of(tmp_path, **kwargs):
+    files = load_go_tree(tmp_path, {"drivers.go": DRIVERS, "a_config.go": A_CONFIG,
+                                    "db_test.go": DB_TEST})
+    return find_inits(files, **kwargs)
+
+
+def test_each_init_listed_in_run_order(tmp_path):
+    inits = inits_of(tmp_path)
+    assert [(i.file.rsplit("/", 1)[1], i.line, i.order, i.of) for i in inits] == [
+        ("a_config.go", 3, 1, 3), ("drivers.go", 5, 2, 3), ("drivers.go", 9, 3, 3)]
+    assert inits[0].calls == ["loadConfig"]
+    assert inits[1].calls == ["sql.Register"]
+    assert inits[2].calls == ["os.Getenv"]
+    assert inits[2].end_line == 14
+
+
+def test_test_package_is_its_own_package(tmp_path):
+    inits = inits_of(tmp_path, include_tests=True)
+    test_init = next(i for i in inits if i.file.endswith("db_test.go"))
+    assert (test_init.package, test_init.order, test_init.of) == ("db_test", 1, 1)
+    assert "4 init functions" in format_inits(inits, "db") and "across 2 packages" in format_inits(inits, "db")
+
+
+def test_format_and_tool(tmp_path):
+    text = format_inits(inits_of(tmp_path), str(tmp_path))
+    assert "- @5-7 init #2 of 3: calls sql.Register" in text
+    assert format_inits([], "x") == "No init functions found in x"
+
+    data = json.loads(find_inits_tool.fn(str(tmp_path), output_format="json")[0].text)
+    assert [d["line"] for d in data] == [3, 5, 9]
+    assert find_inits_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
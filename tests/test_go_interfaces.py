@@ -0,0 +1,138 @@
+"""Tests for golang.interfaces: interface method sets read from the syntax
+tree, embeds listed by referenced name, implementers matched structurally."""
+
+from pathlib import Path
+
+from scantool.golang.interfaces import (
+    find_implementers,
+    format_implementers,
+    format_interfaces,
+    list_interfaces,
+)
+from scantool.golang.syntax import load_go_files
+from conftest import load_go_tree
+
+SAMPLES = Path(__file__).parent / "go" / "samples"
+
+SOURCE = '''\
+package store
+
+import "io"
+
+type (
+\t// Getter fetches by key
+\tGetter interface {
+\t\tGet(ctx context.Context, key string) (value []byte, err error)
+\t}
+
+\tStore interface {
+\t\tGetter
+\t\tio.Closer
+\t\tPut(key string, values ...[]byte) error
+\t}
+)
+
+type Number interface {
+\t~int | ~float64
+}
+
+type impl struct{}
+'''
+
+
+def interfaces_in(tmp_path, source=SOURCE):
+    return {i.name: i for i in list_interfaces(load_go_tree(tmp_path, {"store.go": source}))}
+
+
+class TestListInterfaces:
+    def test_methods_have_reconstructed_signatures(self, tmp_path):
+        found = interfaces_in(tmp_path)
+
+        assert [m.signature for m in found["Getter"].methods] == \
+            ["Get(ctx context.Context, key string) (value []byte, err error)"]
+        assert [m.signature for m in found["Store"].methods] == \
+            ["Put(key string, values ...[]byte) error"]
+
+    def test_embeds_listed_by_referenced_name(self, tmp_path):
+        found = interfaces_in(tmp_path)
+
+        assert found["Store"].embeds == ["Getter", "io.Closer"]
+        assert found["Number"].methods == []
+
+    def test_structs_are_not_interfaces(self, tmp_path):
+        assert "impl" not in interfaces_in(tmp_path)
+
+    def test_directory_scope_and_format(self):
+        interfaces = list_interfaces(load_go_files(str(SAMPLES)))
+        text = format_interfaces(interfaces, str(SAMPLES))
+
+        read_writer = next(i for i in interfaces if i.name == "ReadWriter")
+        assert read_writer.embeds == ["Reader", "Writer"]
+        assert "- ReadWriter @" in text and "    Close() error" in text
+
+
+class TestFindImplementers:
+    def test_sample_repo_implementer(self):
+        result = find_implementers(load_go_files(str(SAMPLES)), "UserRepository")
+
+        assert [i.type_name for i in result.implementers] == ["InMemoryUserRepo"]
+        assert result.implementers[0].pointer_only
+
+    def test_value_receivers_satisfy_both(self, tmp_path):
+        source = (
+            "package shapes\n"
+            "\n"
+            "type Shape interface{ Area() float64 }\n"
+            "\n"
+            "type Square struct{ side float64 }\n"
+            "\n"
+            "func (s Square) Area() float64 { return s.side * s.side }\n"
+            "\n"
+            "type Line struct{}\n"
+            "\n"
+            "func (l Line) Area(scale int) float64 { return 0 }\n"
+        )
+        result = find_implementers(load_go_tree(tmp_path, {"shapes.go": source}), "Shape")
+
+        assert [(i.type_name, i.pointer_only) for i in result.implementers] == [("Square", False)]
+
+    def test_embedded_interfaces_expanded_or_reported(self, tmp_path):
+        source = (
+            "package rw\n"
+            "\n"
+            "type Reader interface{ Read(p []byte) (int, error) }\n"
+            "\n"
+            "type ReadCloser interface {\n"
+            "\tReader\n"
+            "\tio.Closer\n"
+            "}\n"
+            "\n"
+            "type File struct{}\n"
+            "\n"
+            "func (f *File) Read(buf []byte) (n int, err error) { return }\n"
+        )
+        result = find_implementers(load_go_tree(tmp_path, {"rw.go": source}), "ReadCloser")
+
+        # Read matched despite different parameter names; io.Closer unknown
+        assert [i.type_name for i in result.implementers] == ["File"]
+        assert result.unresolved_embeds == ["io.Closer"]
+        assert "not checked: io.Closer" in format_implementers(result, "ReadCloser")
+
+    def test_generic_receivers_per_package(self, tmp_path):
+        files = load_go_tree(tmp_path, {
+            "set.go": "package c\n\ntype Lenner interface{ Len() int }\n\n"
+                      "type Set[T comparable] struct{ m map[T]bool }\n\n"
+                      "func (s Set[T]) Len() int { return len(s.m) }\n",
+            # Same type name in another package, without the method
+            "other/set.go": "package other\n\ntype Set struct{}\n",
+        })
+
+        result = find_implementers(files, "Lenner")
+
+        assert [(i.type_name, i.pointer_only, i.start_line) for i in result.implementers] == [
+            ("Set", False, 5)]
+
+    def test_unknown_interface(self, tmp_path):
+        result = find_implementers(load_go_tree(tmp_path, {"a.go": "package a\n"}), "Missing")
+
+        assert format_implementers(result, "Missing") == "No interface named Missing in scope"
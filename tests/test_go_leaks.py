@@ -0,0 +1,92 @@
+"""Tests for golang.leaks: exported signatures exposing unexported types."""
+
+import json
+
+from scantool.golang.leaks import find_leaked_unexported, format_leaked_unexported
+from scantool.server import find_leaked_unexported as find_leaked_unexported_tool
+from conftest import load_go_tree
+
+CLIENT = """package api
+
+type client struct{}
+
+type options map[string]string
+
+type id = string
+
+type Server struct{}
+
+func New() *client { return nil }
+
+func Configure(all []options, byName map[string]*client) {}
+
+func Lookup(key id) (*Server, error) { return nil, nil }
+
+func Pair() (Server, options) { return Server{}, nil }
+
+func Each[client any](items []client, visit func(*client)) {}
+
+func (s *Server) Client() client { return client{} }
+
+func (c *client) Close() *client { return c }
+
+func newClient() *client { return nil }
+"""
+
+MORE = """package api
+
+type handle chan<- options
+
+func Open(opts ...options) handle { return nil }
+"""
+
+TEST = """package api
+
+func Stub() *client { return nil }
+"""
+
+
+def leaks_of(tmp_path):
+    return find_leaked_unexported(load_go_tree(
+        tmp_path, {"client.go": CLIENT, "more.go": MORE, "client_test.go": TEST}))
+
+
+def test_parameters_and_results_unwrapped(tmp_path):
+    found = [(leak.function, leak.position, leak.leaked) for leak in leaks_of(tmp_path)]
+    assert found == [
+        ("New", "result", "client"),
+        ("Configure", "parameter all", "options"),
+        ("Configure", "parameter byName", "client"),
+        ("Pair", "result 2", "options"),
+        ("Server.Client", "result", "client"),
+        ("Open", "parameter opts", "options"),
+        ("Open", "result", "handle"),
+    ]
+
+
+def test_skips_aliases_type_params_unexported_functions_and_tests(tmp_path):
+    functions = {leak.function for leak in leaks_of(tmp_path)}
+    assert "Lookup" not in functions       # id is an alias
+    assert "Each" not in functions         # client is the type parameter there
+    assert "client.Close" not in functions and "Close" not in functions
+    assert "newClient" not in functions
+    assert "Stub" not in functions
+
+
+def test_declaration_recorded(tmp_path):
+    leak = leaks_of(tmp_path)[0]
+    assert leak.declared_file.endswith("client.go") and leak.declared_line == 3
+    assert leak.type_text == "*client"
+
+
+def test_format_and_tool(tmp_path):
+    text = format_leaked_unexported(leaks_of(tmp_path), "api")
+    assert text.splitlines()[0] == \
+        "5 exported functions and methods expose 3 unexported types in api"
+    assert "- @11 New: result *client → client (" in text
+    assert format_leaked_unexported([], "api") == \
+        "No exported functions with unexported types in their signatures in api"
+
+    data = json.loads(find_leaked_unexported_tool.fn(str(tmp_path), output_format="json")[0].text)
+    assert data[0]["function"] == "New" and data[0]["type"] == "*client"
+    assert find_leaked_unexported_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,93 @@
+"""Tests for golang.literals: string literals from the syntax tree with
+their enclosing declaration, escape decoding and the regex filter."""
+
+import json
+
+from scantool.golang.literals import decode_interpreted, extract_strings, format_strings
+from scantool.golang.syntax import load_go_files
+from scantool.server import extract_strings as extract_strings_tool
+from conftest import load_go_tree
+
+SOURCE = '''package config
+
+import "fmt"
+
+// Greeting is "not a literal" — comments never match.
+const Greeting = "hello\\tworld"
+
+var apiKey = `c2VjcmV0LXRva2VuLXZhbHVlLTEyMzQ1Njc4OTA=`
+
+type Settings struct {
+	Name string `json:"name"`
+}
+
+func (s *Settings) Describe() string {
+	return fmt.Sprintf("\\x41\\101 %s \\u00e9", s.Name)
+}
+
+func helper() func() string {
+	return func() string { return "closure" }
+}
+'''
+
+
+def literals_of(tmp_path, pattern=None):
+    return extract_strings(load_go_tree(tmp_path, {"config.go": SOURCE}), pattern)
+
+
+class TestExtractStrings:
+    def test_literals_with_containers(self, tmp_path):
+        found = [(s.line, s.kind, s.container, s.value) for s in literals_of(tmp_path)]
+
+        assert found == [
+            (6, "interpreted", "Greeting", "hello\tworld"),
+            (8, "raw", "apiKey", "c2VjcmV0LXRva2VuLXZhbHVlLTEyMzQ1Njc4OTA="),
+            (15, "interpreted", "Settings.Describe", "AA %s é"),
+            (19, "interpreted", "helper", "closure"),
+        ]
+
+    def test_pattern_filters_decoded_values(self, tmp_path):
+        found = literals_of(tmp_path, r"^[A-Za-z0-9+/]{32,}={0,2}$")
+
+        assert [s.container for s in found] == ["apiKey"]
+
+    def test_decode_escapes(self):
+        assert decode_interpreted(rb"a\nb\\\"c") == 'a\nb\\"c'
+        assert decode_interpreted(rb"\xc3\xa9\U0001F600") == "é😀"
+        assert decode_interpreted(rb"\xff") == "�"
+
+    def test_raw_strings_drop_carriage_returns(self, tmp_path):
+        (tmp_path / "raw.go").write_bytes(b"package a\n\nvar s = `one\r\ntwo`\n")
+
+        found = extract_strings(load_go_files(str(tmp_path)))
+
+        assert found[0].value == "one\ntwo"
+
+    def test_format(self, tmp_path):
+        text = format_strings(literals_of(tmp_path), "pkg")
+
+        assert text.startswith("4 string literals in pkg (1 raw)")
+        assert '- @6 Greeting: "hello\\tworld"' in text
+        assert "- @8 raw apiKey:" in text
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "config.go").write_text(SOURCE)
+
+        data = json.loads(extract_strings_tool.fn(str(tmp_path), pattern="é",
+                                                  output_format="json")[0].text)
+
+        assert [(d["line"], d["container"]) for d in data] == [(15, "Settings.Describe")]
+
+    def test_tests_excluded_by_default(self, tmp_path):
+        (tmp_path / "a_test.go").write_text('package a\n\nvar fixture = "x"\n')
+
+        assert extract_strings_tool.fn(str(tmp_path))[0].text.startswith("No string literals")
+        assert "fixture" in extract_strings_tool.fn(str(tmp_path), include_tests=True)[0].text
+
+    def test_invalid_pattern(self, tmp_path):
+        (tmp_path / "config.go").write_text(SOURCE)
+
+        assert extract_strings_tool.fn(str(tmp_path), pattern="(")[0].text.startswith(
+            "Error: Invalid pattern")
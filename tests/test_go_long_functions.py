@@ -0,0 +1,88 @@
+"""Tests for golang.long_functions: body length from brace to brace."""
+
+import json
+
+from scantool.golang.long_functions import find_long_functions, format_long_functions
+from scantool.server import find_long_functions as find_long_functions_tool
+from conftest import load_go_tree
+
+WORKER = """package worker
+
+type Pool struct{}
+
+// Run starts the pool.
+//
+// A long doc comment is not body:
+// it says what Run does,
+// and how,
+// over many lines.
+func (p *Pool) Run(
+	workers int,
+	queue chan int,
+) error {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range queue {
+				_ = job
+			}
+		}()
+	}
+	return nil
+}
+
+func short() {}
+
+func drain(queue chan int) {
+	for range queue {
+	}
+
+	close(queue)
+}
+"""
+
+
+def long_of(tmp_path, **options):
+    return find_long_functions(load_go_tree(tmp_path, {"worker.go": WORKER}), **options)
+
+
+def test_body_measured_brace_to_brace(tmp_path):
+    found = [(f.kind, f.name, f.body_lines, f.body_start, f.body_end)
+             for f in long_of(tmp_path, min_lines=3)]
+    assert found == [
+        ("method", "Pool.Run", 10, 14, 23),
+        ("function", "drain", 6, 27, 32),
+        ("func literal", "func literal in Pool.Run", 5, 16, 20),
+    ]
+    assert long_of(tmp_path) == []  # default: 50 lines
+
+
+def test_threshold_is_inclusive(tmp_path):
+    assert [f.name for f in long_of(tmp_path, min_lines=10)] == ["Pool.Run"]
+    assert [f.name for f in long_of(tmp_path, min_lines=1)][-1] == "short"
+
+
+def test_format_and_tool(tmp_path):
+    text = format_long_functions(long_of(tmp_path, min_lines=5), "worker", min_lines=5)
+    lines = text.splitlines()
+    assert lines[0] == "3 functions with bodies of 5+ lines in worker"
+    assert lines[1].startswith("- 10 lines  method Pool.Run  ")
+    assert lines[1].endswith("worker.go:11")
+    assert format_long_functions([], "worker") == "No functions with bodies of 50+ lines in worker"
+
+    data = json.loads(find_long_functions_tool.fn(str(tmp_path), min_lines=10,
+                                                  output_format="json")[0].text)
+    assert [(f["name"], f["line"], f["body_lines"]) for f in data] == [("Pool.Run", 11, 10)]
+    assert find_long_functions_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
+
+
+def test_generic_receiver_tests_and_bodyless_declarations(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "stack.go": "package worker\n\ntype Stack[T any] struct{}\n\n"
+                    "func (s *Stack[T]) Push(v T) {\n\t_ = v\n}\n\n"
+                    "//go:noescape\nfunc memmove(to, from, n uintptr)\n",
+        "stack_test.go": "package worker\n\nfunc TestPush(t *testing.T) {\n\tt.Log()\n}\n",
+    })
+
+    assert [f.name for f in find_long_functions(files, min_lines=1)] == ["Stack.Push"]
+    assert [f.name for f in find_long_functions(files, min_lines=1, include_tests=True)] == [
+        "Stack.Push", "TestPush"]
@@ -0,0 +1,112 @@
+"""Tests for golang.naming: package-name stutter and receiver names."""
+
+import json
+
+from scantool.golang.naming import format_naming_report, naming_report, stutters
+from scantool.server import naming_report as naming_report_tool
+from conftest import load_go_tree
+
+STORE = """package user
+
+type UserService struct{}
+
+type User struct{}
+
+type Username string
+
+const (
+	UserMaxAge = 120
+	userLimit  = 10
+)
+
+var UserCache = func() map[string]User {
+	var UserLocal map[string]User
+	return UserLocal
+}()
+
+func UserByID(id int) *User { return nil }
+
+func (s *UserService) Get(id int) *User { return nil }
+
+func (self *UserService) Put(u *User) {}
+
+func (u User) Name() string { return "" }
+
+func (u *User) Save() error { return nil }
+
+func (*User) Kind() string { return "user" }
+"""
+
+MORE = """package user
+
+func (svc *UserService) Delete(id int) {}
+
+func (s UserService) List() []User { return nil }
+
+func (this Username) String() string { return string(this) }
+"""
+
+MAIN = """package main
+
+func MainLoop() {}
+"""
+
+
+def report_of(tmp_path):
+    return naming_report(load_go_tree(tmp_path, {"store.go": STORE, "more.go": MORE,
+                                                 "cmd/main.go": MAIN}))
+
+
+def test_stutter_rule():
+    assert stutters("user", "UserService")
+    assert not stutters("user", "User")
+    assert not stutters("user", "Username")
+    assert not stutters("user", "userService")
+    assert stutters("http", "HTTPServer") and stutters("http", "HttpServer")
+
+
+def test_stutter_candidates(tmp_path):
+    report = report_of(tmp_path)
+    assert [(s.name, s.kind, s.suggestion) for s in report.stutters] == [
+        ("UserService", "type", "Service"), ("UserMaxAge", "const", "MaxAge"),
+        ("UserCache", "var", "Cache"), ("UserByID", "function", "ByID")]
+
+
+def test_receiver_names_grouped_per_type_across_files(tmp_path):
+    report = report_of(tmp_path)
+    by_type = {r.type_name: r for r in report.receivers}
+    assert set(by_type) == {"UserService", "Username"}  # User: u everywhere, *User unnamed
+    service = by_type["UserService"]
+    assert dict(service.counts) == {"svc": 1, "s": 2, "self": 1}
+    assert service.suggestion == "s"
+    assert service.generic == ["self"]
+    assert by_type["Username"].suggestion == "u"
+
+
+def test_generic_receivers_and_test_files(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "list.go": "package list\n\ntype List[T any] struct{}\n\n"
+                   "func (l *List[T]) Push(v T) {}\n\nfunc (list List[T]) Len() int { return 0 }\n",
+        "list_test.go": "package list\n\nfunc ListOf() {}\n\nfunc (lst *List[T]) reset() {}\n",
+    })
+
+    report = naming_report(files)
+    assert report.stutters == []
+    assert [(r.type_name, dict(r.counts), r.suggestion) for r in report.receivers] == [
+        ("List", {"l": 1, "list": 1}, "l")]
+    report = naming_report(files, include_tests=True)
+    assert [s.name for s in report.stutters] == ["ListOf"]
+    assert dict(report.receivers[0].counts) == {"l": 1, "list": 1, "lst": 1}
+
+
+def test_format_and_tool(tmp_path):
+    text = format_naming_report(report_of(tmp_path), "user")
+    assert "2 types with inconsistent receiver names" in text.splitlines()[0]
+    assert "- @3 type user.UserService → user.Service" in text
+    assert "svc ×1, s ×2, self ×1 — use s; generic: self" in text
+    assert "(self) Put" in text and "(s) Get" not in text
+
+    data = json.loads(naming_report_tool.fn(str(tmp_path), output_format="json")[0].text)
+    assert [s["suggestion"] for s in data["stutters"]][0] == "Service"
+    assert data["receivers"][0]["names"] == {"svc": 1, "s": 2, "self": 1}
+    assert naming_report_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,115 @@
+"""Tests for golang.panics: panic and process-exit calls with their
+enclosing function."""
+
+import json
+
+from scantool.golang.panics import find_panics, format_panics
+from scantool.server import find_panics as find_panics_tool
+from conftest import load_go_tree
+
+STORE = """package store
+
+import (
+	"log"
+	"os"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+var defaultStore = mustOpen("db")
+
+func mustOpen(path string) *Store {
+	s, err := Open(path)
+	if err != nil {
+		panic(fmt.Sprintf("open %s: %v", path, err))
+	}
+	return s
+}
+
+func (s *Store) Get(key string) (v string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("get: %v", r)
+		}
+	}()
+	panic("not implemented")
+}
+
+func Load() {
+	if err := load(); err != nil {
+		log.Fatalf("load: %v", err)
+	}
+	logger.Fatal("unreachable")
+	os.Exit(2)
+}
+
+// panic("in a comment") is not a call
+"""
+
+MAIN = """package main
+
+import "os"
+
+func main() {
+	os.Exit(run())
+}
+"""
+
+
+def panics_of(tmp_path, **kwargs):
+    return find_panics(load_go_tree(tmp_path, {"store.go": STORE, "cmd/main.go": MAIN}), **kwargs)
+
+
+def summary(calls):
+    return [(c.line, c.function, c.kind, c.callee) for c in calls]
+
+
+class TestFindPanics:
+    def test_panics_and_exits_with_enclosing_function(self, tmp_path):
+        calls = panics_of(tmp_path)
+
+        assert summary(calls) == [
+            (6, "main", "exit", "os.Exit"),
+            (15, "mustOpen", "panic", "panic"),
+            (26, "Store.Get", "panic", "panic"),
+            (31, "Load", "exit", "log.Fatalf"),
+            (34, "Load", "exit", "os.Exit"),
+        ]
+        assert calls[1].arguments == 'fmt.Sprintf("open %s: %v", path, err)'
+
+    def test_recover_in_the_function(self, tmp_path):
+        calls = panics_of(tmp_path)
+
+        assert [(c.function, c.recovered) for c in calls if c.kind == "panic"] == [
+            ("mustOpen", False), ("Store.Get", True)]
+
+    def test_configurable_callees_through_aliases(self, tmp_path):
+        calls = panics_of(tmp_path, panic_calls=[],
+                          exit_calls=["github.com/sirupsen/logrus.Fatal"])
+
+        assert summary(calls) == [(33, "Load", "exit", "github.com/sirupsen/logrus.Fatal")]
+
+    def test_exclude_main(self, tmp_path):
+        assert all(c.package == "store" for c in panics_of(tmp_path, exclude_main=True))
+
+    def test_format(self, tmp_path):
+        text = format_panics(panics_of(tmp_path), "pkg")
+
+        assert text.startswith("5 panic/exit calls in pkg (2 panic, 3 exit; "
+                               "2 exits outside package main)")
+        assert '- @26 Store.Get: panic("not implemented")  [panic, recovered in the function]' in text
+        assert "- @34 Load: os.Exit(2)  [exit — deferred calls don't run]" in text
+        assert format_panics([], "pkg") == "No panic or exit calls found in pkg"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "store.go").write_text(STORE)
+
+        data = json.loads(find_panics_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert [(d["kind"], d.get("recovered")) for d in data] == [
+            ("panic", False), ("panic", True), ("exit", None), ("exit", None)]
+
+    def test_missing_path(self, tmp_path):
+        assert find_panics_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
@@ -0,0 +1,95 @@
+"""Tests for golang.pkginfo: package name, module path, go version and
+import path of a Go directory."""
+
+import json
+
+from scantool.golang.imports import go_version
+from scantool.golang.pkginfo import format_package_info, package_info
+from scantool.golang.syntax import load_go_files
+from scantool.server import package_info as package_info_tool
+
+GO_MOD = "module example.com/app\n\ngo 1.22\n\nrequire golang.org/x/sync v0.7.0\n"
+
+
+def make_module(root):
+    (root / "go.mod").write_text(GO_MOD)
+    store = root / "internal" / "store"
+    store.mkdir(parents=True)
+    (store / "store.go").write_text("package store\n")
+    (store / "cache.go").write_text("package store\n")
+    (store / "store_test.go").write_text("package store_test\n")
+    (root / "main.go").write_text("package main\n")
+    return store
+
+
+class TestPackageInfo:
+    def test_module_and_import_path(self, tmp_path):
+        store = make_module(tmp_path)
+
+        info = package_info(load_go_files(str(tmp_path)), str(store))
+
+        assert info.package == "store"
+        assert info.packages == ["store", "store_test"]
+        assert (info.files, info.test_files) == (2, 1)
+        assert info.module == "example.com/app"
+        assert info.go_version == "1.22"
+        assert info.module_dir == str(tmp_path.resolve())
+        assert info.import_path == "example.com/app/internal/store"
+
+    def test_module_root_is_the_module_path(self, tmp_path):
+        make_module(tmp_path)
+
+        info = package_info(load_go_files(str(tmp_path)), str(tmp_path))
+
+        assert (info.package, info.import_path) == ("main", "example.com/app")
+
+    def test_nearest_go_mod_wins(self, tmp_path):
+        store = make_module(tmp_path)
+        (store / "go.mod").write_text("module example.com/store\n")
+
+        info = package_info(load_go_files(str(store)), str(store))
+
+        assert (info.module, info.import_path, info.go_version) == (
+            "example.com/store", "example.com/store", None)
+
+    def test_no_go_mod_keeps_package_name(self, tmp_path):
+        (tmp_path / "tool.go").write_text("package tool\n")
+
+        info = package_info(load_go_files(str(tmp_path)), str(tmp_path))
+
+        assert info.package == "tool"
+        assert (info.module, info.module_dir, info.go_version, info.import_path) == (
+            None, None, None, None)
+        assert "no go.mod" in format_package_info(info)
+
+    def test_go_version_ignores_other_directives(self, tmp_path):
+        (tmp_path / "go.mod").write_text("module m\n\ngodebug default=go1.21\n"
+                                         "toolchain go1.23.1\ngo 1.21.0\n")
+
+        assert go_version(tmp_path) == "1.21.0"
+        assert go_version(tmp_path / "missing") is None
+
+    def test_format(self, tmp_path):
+        store = make_module(tmp_path)
+
+        text = format_package_info(package_info(load_go_files(str(tmp_path)), str(store)))
+
+        assert text.splitlines() == [
+            f"package store — {store.resolve()}",
+            "2 files (+1 test); packages: store, store_test",
+            f"module example.com/app (go 1.22) — {tmp_path.resolve()}/go.mod",
+            "import path: example.com/app/internal/store",
+        ]
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        store = make_module(tmp_path)
+
+        data = json.loads(package_info_tool.fn(str(store / "store.go"), output_format="json")[0].text)
+
+        assert data["package"] == "store"
+        assert data["import_path"] == "example.com/app/internal/store"
+
+    def test_missing_path(self, tmp_path):
+        assert package_info_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
@@ -0,0 +1,137 @@
+"""Tests for golang.regexes: regexp compile calls, static vs dynamic
+patterns, and the RE2 syntax check."""
+
+import json
+
+import pytest
+
+from scantool.golang.regexes import find_regexes, format_regexes, re2_error
+from scantool.server import find_regexes as find_regexes_tool
+from conftest import load_go_tree
+
+VALIDATE = """package validate
+
+import (
+	"fmt"
+	re "regexp"
+)
+
+const idPattern = `^[a-z]{2}-\\d+$`
+
+var email = re.MustCompile(`^[^@\\s]+@[^@\\s]+$`)
+
+var lookahead = re.MustCompile("foo(?=bar)")
+
+func Check(field, value string) bool {
+	r, err := re.Compile("^" + field + "=")
+	if err != nil {
+		return false
+	}
+	id := re.MustCompile(idPattern)
+	quoted := re.MustCompile("\\\\d+\\\\.\\\\d+")
+	custom := re.MustCompile(fmt.Sprintf("^%s$", value))
+	return r.MatchString(value) && id.MatchString(value) && quoted.MatchString(value) &&
+		custom.MatchString(value)
+}
+"""
+
+
+def regexes_of(tmp_path, **kwargs):
+    return find_regexes(load_go_tree(tmp_path, {"validate.go": VALIDATE}), **kwargs)
+
+
+class TestFindRegexes:
+    def test_calls_through_aliased_import(self, tmp_path):
+        calls = regexes_of(tmp_path)
+
+        assert [(c.line, c.function, c.call, c.panics) for c in calls] == [
+            (10, "(package level)", "MustCompile", True),
+            (12, "(package level)", "MustCompile", True),
+            (15, "Check", "Compile", False),
+            (19, "Check", "MustCompile", True),
+            (20, "Check", "MustCompile", True),
+            (21, "Check", "MustCompile", True),
+        ]
+
+    def test_static_patterns_decoded(self, tmp_path):
+        calls = regexes_of(tmp_path)
+
+        assert [c.pattern for c in calls] == [
+            r"^[^@\s]+@[^@\s]+$", "foo(?=bar)", None, r"^[a-z]{2}-\d+$", r"\d+\.\d+", None]
+        assert calls[2].expression == '"^" + field + "="'
+
+    def test_invalid_pattern_flagged(self, tmp_path):
+        calls = regexes_of(tmp_path)
+
+        assert [c.error for c in calls] == [
+            None, "invalid or unsupported Perl syntax: `(?=`", None, None, None, None]
+        assert all(c.error is None for c in regexes_of(tmp_path, check=False))
+
+    def test_constants_across_files_and_test_files(self, tmp_path):
+        files = load_go_tree(tmp_path, {
+            "patterns.go": "package validate\n\nconst word = `\\w+`\n",
+            "match.go": "package validate\n\nimport \"regexp\"\n\n"
+                        "var w = regexp.MustCompile(word)\n",
+            # No regexp import: a parameter named regexp is not the package
+            "other.go": "package validate\n\nfunc g(regexp *P) { regexp.MustCompile(\"(\") }\n",
+            "match_test.go": "package validate\n\nimport \"regexp\"\n\n"
+                             "var t = regexp.MustCompile(`(`)\n",
+        })
+
+        assert [(c.function, c.pattern) for c in find_regexes(files)] == [
+            ("(package level)", r"\w+")]
+        calls = find_regexes(files, include_tests=True)
+        assert [(c.file.endswith("_test.go"), c.error) for c in calls] == [
+            (False, None), (True, "missing closing ): `(`")]
+
+    def test_format(self, tmp_path):
+        text = format_regexes(regexes_of(tmp_path), "pkg")
+
+        assert text.startswith("6 regexes in pkg (5 Must*, 1 returning an error; "
+                               "1 invalid, 2 dynamic)")
+        assert ("- @12 (package level): MustCompile `foo(?=bar)`  "
+                "[invalid: invalid or unsupported Perl syntax: `(?=` — panics]") in text
+        assert '- @15 Check: Compile ("^" + field + "=")  [dynamic]' in text
+        assert "[dynamic — panics on a bad pattern]" in text
+
+
+class TestRe2Error:
+    @pytest.mark.parametrize("pattern", [
+        r"^\d+$", r"(?i)abc", r"(?P<year>\d{4})-(?<month>\d\d)", r"a*?b+?", r"[]a-z]",
+        r"[[:alpha:]_]", r"\pL\p{Greek}", r"\Qa.b\E+", r"x{2,}", r"\x{263a}\012", r"a{",
+    ])
+    def test_valid(self, pattern):
+        assert re2_error(pattern) is None
+
+    @pytest.mark.parametrize("pattern, error", [
+        ("(ab", "missing closing ): `(ab`"),
+        ("ab)", "unexpected ): `ab)`"),
+        ("[a-z", "missing closing ]: `[a-z`"),
+        ("[z-a]", "invalid character class range: `z-a`"),
+        ("[[:foo:]]", "invalid character class range: `[:foo:]`"),
+        ("(?!x)", "invalid or unsupported Perl syntax: `(?!`"),
+        ("(?>x)", "invalid or unsupported Perl syntax: `(?>`"),
+        (r"(a)\1", r"invalid escape sequence: `\1`"),
+        (r"end\Z", r"invalid escape sequence: `\Z`"),
+        ("a**", "invalid nested repetition operator: `**`"),
+        ("a{2}*", "invalid nested repetition operator: `{2}*`"),
+        ("*a", "missing argument to repetition operator: `*`"),
+        ("x{1001}", "invalid repeat count: `{1001}`"),
+        ("x{3,2}", "invalid repeat count: `{3,2}`"),
+        ("a\\", "trailing backslash at end of expression: ``"),
+    ])
+    def test_invalid(self, pattern, error):
+        assert re2_error(pattern) == error
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "validate.go").write_text(VALIDATE)
+
+        data = json.loads(find_regexes_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert [c["dynamic"] for c in data] == [False, False, True, False, False, True]
+        assert data[1]["error"].startswith("invalid or unsupported Perl syntax")
+
+    def test_missing_path(self, tmp_path):
+        assert find_regexes_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
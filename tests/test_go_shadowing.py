@@ -0,0 +1,106 @@
+"""Tests for golang.shadowing: declarations hiding a variable of an
+enclosing scope."""
+
+import json
+
+from scantool.golang.shadowing import find_shadowing, format_shadowing
+from scantool.server import find_shadowing as find_shadowing_tool
+from conftest import load_go_tree
+
+STORE = """package store
+
+func Load(path string) (cfg *Config, err error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		cfg, err := parse(data)
+		_ = cfg
+		_ = err
+	}
+	if err := validate(data); err != nil {
+		return nil, err
+	}
+	for _, path := range paths(data) {
+		go func(path string) {
+			_ = path
+		}(path)
+	}
+	var x any = data
+	switch x := x.(type) {
+	case []byte:
+		_ = x
+	}
+	v := 1
+	{
+		v := v
+		_ = v
+	}
+	return cfg, nil
+}
+
+func Other() error {
+	err := first()
+	select {
+	case err := <-errs:
+		return err
+	default:
+	}
+	return err
+}
+"""
+
+
+def shadows_of(tmp_path, **kwargs):
+    return find_shadowing(load_go_tree(tmp_path, {"store.go": STORE}), **kwargs)
+
+
+def test_block_init_range_and_closure_scopes(tmp_path):
+    shadows = shadows_of(tmp_path)
+
+    # data, err := read(path) reuses the named result: not a shadow
+    assert [(s.line, s.function, s.name, s.kind, s.outer_kind, s.outer_line)
+            for s in shadows] == [
+        (9, "Load", "cfg", ":=", "result", 3),
+        (9, "Load", "err", ":=", "result", 3),
+        (13, "Load", "err", ":=", "result", 3),
+        (16, "Load", "path", "range", "param", 3),
+        (17, "func literal in Load", "path", "param", "range", 16),
+        (37, "Other", "err", "select", ":=", 35),
+    ]
+
+
+def test_names_filter_and_self_copies(tmp_path):
+    assert [s.line for s in shadows_of(tmp_path, names=["err"])] == [9, 13, 37]
+
+    copies = [s for s in shadows_of(tmp_path, include_self_copies=True) if s.self_copy]
+    assert [(s.line, s.name, s.kind) for s in copies] == [
+        (22, "x", "type switch"), (28, "v", ":=")]
+
+
+def test_generic_receiver_package_names_and_test_files(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "set.go": "package store\n\nvar err error\n\nfunc (s *Set[T]) Add(v T) {\n"
+                  "\terr := check(v)\n\tif ok := true; ok {\n\t\ts := s.items\n\t\t_ = s\n"
+                  "\t}\n\t_ = err\n}\n",
+        "set_test.go": "package store\n\nfunc TestAdd(t T) {\n\tfor _, t := range cases {\n"
+                       "\t\t_ = t\n\t}\n}\n",
+    })
+
+    # The package-level err is not tracked
+    assert [(s.line, s.function, s.name, s.kind, s.outer_kind, s.outer_line)
+            for s in find_shadowing(files)] == [(8, "Set.Add", "s", ":=", "receiver", 5)]
+    assert [(s.function, s.name, s.kind) for s in find_shadowing(files, include_tests=True)][-1] == \
+        ("TestAdd", "t", "range")
+
+
+def test_format_and_tool(tmp_path):
+    text = format_shadowing(shadows_of(tmp_path), "store")
+    assert text.splitlines()[0] == "6 shadowing declarations in store (3 of err)"
+    assert "- @13 Load: err (:=) shadows result @3" in text
+
+    data = json.loads(find_shadowing_tool.fn(str(tmp_path), names=["err"],
+                                             output_format="json")[0].text)
+    assert [(d["line"], d["outer_line"]) for d in data] == [(9, 3), (13, 3), (37, 35)]
+    assert find_shadowing_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,86 @@
+"""Tests for golang.structtags: struct tags parsed like reflect.StructTag,
+syntax problems and the key allow-list."""
+
+import json
+
+from scantool.golang.structtags import find_struct_tags, format_struct_tags, parse_struct_tag
+from scantool.server import validate_struct_tags
+from conftest import load_go_tree
+
+MODELS = """package models
+
+type User struct {
+	ID    int    `json:"id" db:"user_id"`
+	Name  string `json: "name"`
+	Email string `json:"email" db:"email`
+	Age   int    `json:"age",validate:"min=0"`
+	Base         `jsno:"base"`
+	Plain string
+}
+
+func handler() {
+	var req struct {
+		Token string "json:\\"token\\" json:\\"tok\\""
+	}
+	_ = req
+}
+"""
+
+
+def tags_of(tmp_path, **kwargs):
+    return find_struct_tags(load_go_tree(tmp_path, {"models.go": MODELS}), **kwargs)
+
+
+def test_parse_like_reflect():
+    assert parse_struct_tag('json:"id,omitempty" db:"id"') == (
+        [("json", "id,omitempty"), ("db", "id")], [])
+    assert parse_struct_tag('json:"a\\"b"') == ([("json", 'a"b')], [])
+    assert parse_struct_tag("json") == ([], ["missing colon after key json"])
+    assert parse_struct_tag('x:"\\q"')[1] == ["bad value for x: invalid escape '\\\\q'"]
+
+
+def test_problems_per_field(tmp_path):
+    tags = tags_of(tmp_path)
+    assert [(t.line, t.struct, t.field, t.problems) for t in tags] == [
+        (4, "User", "ID", []),
+        (5, "User", "Name", ["value of json is not quoted right after the colon"]),
+        (6, "User", "Email", ["unbalanced quotes: value of db is never closed"]),
+        (7, "User", "Age", ['no space after json:"age"']),
+        (8, "User", "Base", []),
+        (14, "handler", "Token", ["duplicate key json (Lookup finds the first)"]),
+    ]
+    # Lookup never reaches db after the broken email value
+    assert tags[2].pairs == [("json", "email")]
+
+
+def test_known_keys(tmp_path):
+    tags = tags_of(tmp_path, known_keys=["json", "db", "validate"])
+    assert tags[4].problems == ["unknown key jsno"]
+    assert tags[3].problems == ['no space after json:"age"', "unknown key ,validate"]
+
+
+def test_generic_structs_embedded_pointers_and_test_files(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "page.go": "package models\n\ntype Page[T any] struct {\n\tItems []T `json:\"items\"`\n"
+                   "\t*Cursor `json:\"cursor\"`\n\tPrev, Next int `json:\"-\"`\n}\n",
+        "page_test.go": "package models\n\ntype fixture struct {\n\tX int `json:x`\n}\n",
+    })
+
+    assert [(t.line, t.struct, t.field, t.problems) for t in find_struct_tags(files)] == [
+        (4, "Page", "Items", []), (5, "Page", "Cursor", []), (6, "Page", "Prev, Next", [])]
+    fixture = find_struct_tags(files, include_tests=True)[-1]
+    assert (fixture.struct, fixture.field, bool(fixture.problems)) == ("fixture", "X", True)
+
+
+def test_format_and_tool(tmp_path):
+    text = format_struct_tags(tags_of(tmp_path), "models")
+    assert text.splitlines()[0] == "6 struct tags in models, 4 with problems"
+    assert '- @5 User.Name `json: "name"`: value of json is not quoted right after the colon' in text
+    assert "User.ID" not in text
+    assert "User.ID" in format_struct_tags(tags_of(tmp_path), "models", include_valid=True)
+
+    data = json.loads(validate_struct_tags.fn(str(tmp_path), keys=["json", "db"],
+                                              output_format="json")[0].text)
+    assert [d["field"] for d in data] == ["Name", "Email", "Age", "Base", "Token"]
+    assert data[0]["pairs"] == []
+    assert validate_struct_tags.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,140 @@
+"""Tests for golang.summary: per-directory Go package summaries — counts,
+methods across files, package doc, stray package-name warnings."""
+
+import json
+
+from scantool.golang.summary import format_package_summary, summarize_package
+from scantool.golang.syntax import load_go_files
+from scantool.server import summarize_package as summarize_package_tool
+from conftest import write_tree
+
+STORE = """package store
+
+// Store keeps records.
+type Store struct{ items map[string]int }
+
+type cursor struct{}
+
+const Limit = 10
+
+var (
+	defaultStore = &Store{}
+	Version, build = "1", "dev"
+)
+
+func New() *Store { return &Store{} }
+
+func (s *Store) Get(key string) int { return s.items[key] }
+"""
+
+STORE_WRITE = """package store
+
+func (s *Store) Put(key string, v int) { s.items[key] = v }
+
+func (s *Store) flush() {}
+
+func (c cursor) Next() bool { return false }
+"""
+
+DOC = """// Package store is an in-memory record store.
+//
+// It is not safe for concurrent use.
+package store
+"""
+
+
+def summary_of(root, include_tests=False):
+    return summarize_package(load_go_files(str(root)), str(root), include_tests=include_tests)
+
+
+class TestSummarizePackage:
+    def test_methods_attached_across_files(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE, "write.go": STORE_WRITE})
+
+        types = {t.name: t for t in summary_of(tmp_path).types}
+
+        assert types["Store"].methods == ["Get", "Put", "flush"]
+        assert types["cursor"].methods == ["Next"]
+        assert [t.name for t in summary_of(tmp_path).types] == ["Store", "cursor"]
+
+    def test_exported_and_unexported_counts(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE, "write.go": STORE_WRITE})
+
+        summary = summary_of(tmp_path)
+
+        # Store, Limit, Version, New, Get, Put, Next / cursor, defaultStore, build, flush
+        assert (summary.exported, summary.unexported) == (7, 4)
+        assert summary.counts == {"types": 2, "constants": 1, "variables": 3,
+                                  "functions": 1, "methods": 4}
+        assert summary.package == "store"
+        assert summary.warnings == []
+
+    def test_doc_go_preferred(self, tmp_path):
+        write_tree(tmp_path, {"a.go": "// Package store is the wrong doc.\npackage store\n",
+                             "doc.go": DOC})
+
+        assert summary_of(tmp_path).doc.startswith("Package store is an in-memory")
+
+    def test_stray_package_main_warned(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE, "write.go": STORE_WRITE,
+                             "tool.go": "package main\n\nfunc main() {}\n"})
+
+        summary = summary_of(tmp_path)
+
+        assert summary.package == "store"
+        assert summary.warnings == [
+            "multiple package names in one directory: main (tool.go); store (store.go, write.go)"]
+
+    def test_external_test_package_not_warned(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE,
+                             "store_test.go": "package store_test\n\nfunc TestGet() {}\n"})
+
+        summary = summary_of(tmp_path)
+
+        assert summary.warnings == []
+        assert len(summary.test_files) == 1
+        assert summary.counts["functions"] == 1
+        assert summary_of(tmp_path, include_tests=True).counts["functions"] == 2
+
+    def test_subdirectories_are_other_packages(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE,
+                             "internal/cache/cache.go": "package cache\n\ntype Cache struct{}\n"})
+
+        summary = summary_of(tmp_path)
+
+        assert len(summary.files) == 1
+        assert [t.name for t in summary.types] == ["Store", "cursor"]
+
+    def test_method_on_undeclared_type_warned(self, tmp_path):
+        write_tree(tmp_path, {"write.go": STORE_WRITE})
+
+        warnings = summary_of(tmp_path).warnings
+
+        assert "method Store.Put on a type not declared here" in warnings
+
+    def test_text_format(self, tmp_path):
+        write_tree(tmp_path, {"doc.go": DOC, "store.go": STORE, "write.go": STORE_WRITE})
+
+        text = format_package_summary(summary_of(tmp_path))
+
+        assert text.startswith(f"package store — {tmp_path.resolve()}\n3 files (+0 test); "
+                               "7 exported, 4 unexported symbols")
+        assert "doc: Package store is an in-memory record store." in text
+        assert "- cursor (struct) store.go@6  1 method: Next" in text
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        write_tree(tmp_path, {"store.go": STORE, "write.go": STORE_WRITE})
+
+        data = json.loads(summarize_package_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data["package"] == "store"
+        assert data["files"] == 2
+        assert data["symbols"]["exported"] == 7
+        assert data["types"][0]["name"] == "Store"
+
+    def test_empty_directory(self, tmp_path):
+        text = summarize_package_tool.fn(str(tmp_path))[0].text
+
+        assert text.startswith("No Go files directly in")
@@ -0,0 +1,76 @@
+"""Tests for golang.surface: exported symbols counted by kind per package."""
+
+import json
+
+from scantool.golang.surface import api_surface, format_api_surface
+from scantool.server import api_surface as api_surface_tool
+from conftest import load_go_tree
+
+STORE = """package store
+
+type Store struct{}
+
+type ID = string
+
+type cache struct{}
+
+const (
+	MaxItems = 10
+	A, B     = 1, 2
+	limit    = 3
+)
+
+var Default, other = New(), 0
+
+func New() *Store { return nil }
+
+func helper() {}
+
+func (s *Store) Get(id ID) string { return "" }
+
+func (s *Store) put() {}
+
+func (c *cache) Flush() {}
+"""
+
+TEST = """package store
+
+func TestHelper() {}
+"""
+
+CMD = """package main
+
+func main() {}
+"""
+
+
+def surface_of(tmp_path):
+    return api_surface(load_go_tree(tmp_path, {"store.go": STORE, "store_test.go": TEST,
+                                               "cmd/main.go": CMD}))
+
+
+def test_counts_by_kind(tmp_path):
+    surface = surface_of(tmp_path)
+    store = next(p for p in surface.packages if p.package == "store")
+    assert dict(store.counts) == {"type": 2, "const": 3, "var": 1, "function": 1, "method": 1}
+    assert store.total == 8
+    main = next(p for p in surface.packages if p.package == "main")
+    assert main.total == 0
+
+
+def test_totals(tmp_path):
+    surface = surface_of(tmp_path)
+    assert surface.total == 8
+    assert surface.to_dict()["counts"] == {"function": 1, "method": 1, "type": 2,
+                                           "const": 3, "var": 1}
+
+
+def test_format_and_tool(tmp_path):
+    text = format_api_surface(surface_of(tmp_path), "repo")
+    assert text.splitlines()[0] == ("API surface of repo: 8 exported symbols (1 functions, "
+                                    "1 methods, 2 types, 3 consts, 1 vars) in 2 packages")
+    assert format_api_surface(api_surface([]), "repo") == "No Go packages found in repo"
+
+    data = json.loads(api_surface_tool.fn(str(tmp_path), output_format="json")[0].text)
+    assert data["total"] == 8 and len(data["packages"]) == 2
+    assert api_surface_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,86 @@
+"""Tests for golang.syntax loading: which files of a directory, or of a
+scan_directory result, are parsed."""
+
+import time
+from pathlib import Path
+
+import pytest
+
+from scantool.golang.syntax import go_files_of_scan, load_go_files
+from scantool.languages import StructureNode
+from scantool.scanner import FileScanner, ScanCancelled
+
+
+def names(files):
+    return sorted(Path(f.path).name for f in files)
+
+
+class TestLoadGoFiles:
+    def test_skips_files_the_scanner_refuses(self, tmp_path):
+        (tmp_path / "main.go").write_text("package main\n")
+        (tmp_path / "api.pb.go").write_text("package main\n")
+        (tmp_path / "blob.go").write_bytes(b"package main\x00\x01\x02")
+        (tmp_path / "big.go").write_text("package main\n" + "// x\n" * 100)
+        (tmp_path / "README.md").write_text("# not go\n")
+
+        files = load_go_files(str(tmp_path), max_file_size=200)
+
+        assert names(files) == ["main.go"]
+        assert files[0].package == "main"
+
+    def test_crlf_and_bom_normalized_raw_bytes_kept(self, tmp_path):
+        crlf = b"package a\r\n\r\nfunc F() {}\r\n"
+        bom = b"\xef\xbb\xbfpackage b\n"
+        (tmp_path / "crlf.go").write_bytes(crlf)
+        (tmp_path / "bom.go").write_bytes(bom)
+
+        by_name = {Path(f.path).name: f for f in load_go_files(str(tmp_path))}
+
+        assert by_name["crlf.go"].source == b"package a\n\nfunc F() {}\n"
+        assert by_name["crlf.go"].raw_source == crlf
+        assert (by_name["bom.go"].source, by_name["bom.go"].package) == (b"package b\n", "b")
+        assert by_name["bom.go"].raw_source == bom
+
+    def test_timeout_stops_the_walk(self, tmp_path, monkeypatch):
+        walked = []
+
+        def slow_walk(self, directory, *args, **kwargs):
+            for i in range(100):
+                walked.append(i)
+                time.sleep(0.02)
+                yield tmp_path / f"f{i}.go"
+
+        monkeypatch.setattr(FileScanner, "walk_files", slow_walk)
+        with pytest.raises(ScanCancelled):
+            load_go_files(str(tmp_path), timeout=0.05)
+
+        assert len(walked) < 10
+
+    def test_single_file_and_missing_path(self, tmp_path):
+        (tmp_path / "a.go").write_text("package a\n")
+        assert names(load_go_files(str(tmp_path / "a.go"))) == ["a.go"]
+        with pytest.raises(FileNotFoundError):
+            load_go_files(str(tmp_path / "missing"))
+
+
+class TestGoFilesOfScan:
+    def test_parses_scanned_go_files_and_skips_stubs(self, tmp_path):
+        (tmp_path / "api.go").write_text("package api\n\nfunc Get() {}\n")
+        (tmp_path / "big.go").write_text("package api\n")
+        (tmp_path / "notes.md").write_text("# Notes\n")
+        results = {
+            str(tmp_path / "api.go"): [StructureNode(type="function", name="Get",
+                                                     start_line=3, end_line=3)],
+            str(tmp_path / "big.go"): [StructureNode(type="file-info", name="big.go",
+                                                     start_line=1, end_line=1,
+                                                     file_metadata={"skipped": "too_large"})],
+            str(tmp_path / "notes.md"): [StructureNode(type="heading-1", name="Notes",
+                                                       start_line=1, end_line=1)],
+            str(tmp_path / "gone.go"): [StructureNode(type="function", name="F",
+                                                      start_line=1, end_line=1)],
+        }
+
+        files = go_files_of_scan(results)
+
+        assert names(files) == ["api.go"]
+        assert files[0].package == "api"
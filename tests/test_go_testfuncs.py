@@ -0,0 +1,140 @@
+"""Tests for golang.testfuncs: test, benchmark, fuzz and example functions
+found by go test's signature rules, with naming-convention targets."""
+
+import json
+
+from scantool.golang.syntax import load_go_files
+from scantool.golang.testfuncs import format_tests, scan_tests
+from scantool.server import scan_tests as scan_tests_tool
+from conftest import load_go_tree
+
+SOURCE = """package user
+
+type Store struct{}
+
+func FormatUser(u string) string { return u }
+
+func parse(s string) int { return 0 }
+
+func (s *Store) Get(k string) string { return k }
+
+func Delete() {}
+
+func helper() {}
+"""
+
+TESTS = """package user
+
+import "testing"
+
+func TestMain(m *testing.M) {}
+
+func TestFormatUser(t *testing.T) {}
+
+func TestFormatUserEmpty(t *testing.T) {}
+
+func Test_parse(t *testing.T) {}
+
+func TestStore_Get(t *testing.T) {}
+
+func TestNothing(t *testing.T) {}
+
+func BenchmarkFormatUser(b *testing.B) {}
+
+func FuzzParse(f *testing.F) {}
+
+func ExampleStore_Get() {}
+
+func Testify(t *testing.T) {}
+
+func TestWrongParam(t *T) {}
+
+func TestResult(t *testing.T) int { return 0 }
+
+func ExampleWithParam(t *testing.T) {}
+"""
+
+
+def suite_of(tmp_path):
+    return scan_tests(load_go_tree(tmp_path, {"user.go": SOURCE, "user_test.go": TESTS}))
+
+
+def kinds(suite):
+    return {t.name: t.kind for t in suite.tests}
+
+
+def targets(suite):
+    return {t.name: t.target for t in suite.tests}
+
+
+class TestScanTests:
+    def test_signature_rules(self, tmp_path):
+        assert kinds(suite_of(tmp_path)) == {
+            "TestMain": "main", "TestFormatUser": "test", "TestFormatUserEmpty": "test",
+            "Test_parse": "test", "TestStore_Get": "test", "TestNothing": "test",
+            "BenchmarkFormatUser": "benchmark", "FuzzParse": "fuzz",
+            "ExampleStore_Get": "example"}
+
+    def test_counts(self, tmp_path):
+        assert suite_of(tmp_path).counts() == {
+            "test": 5, "benchmark": 1, "fuzz": 1, "example": 1, "main": 1}
+
+    def test_aliased_and_dot_imported_testing(self, tmp_path):
+        (tmp_path / "a_test.go").write_text(
+            'package a\n\nimport tt "testing"\n\n'
+            "func TestAlias(t *tt.T) {}\n\nfunc TestPlain(t *testing.T) {}\n")
+        (tmp_path / "b_test.go").write_text(
+            'package a\n\nimport . "testing"\n\nfunc TestDot(t *T) {}\n')
+
+        assert kinds(scan_tests(load_go_files(str(tmp_path)))) == {
+            "TestAlias": "test", "TestDot": "test"}
+
+    def test_naming_targets(self, tmp_path):
+        assert targets(suite_of(tmp_path)) == {
+            "TestMain": None, "TestFormatUser": "FormatUser",
+            "TestFormatUserEmpty": "FormatUser", "Test_parse": "parse",
+            "TestStore_Get": "Store.Get", "TestNothing": None,
+            "BenchmarkFormatUser": "FormatUser", "FuzzParse": "parse",
+            "ExampleStore_Get": "Store.Get"}
+
+    def test_untested_exported_functions(self, tmp_path):
+        assert [f.name for f in suite_of(tmp_path).untested] == ["Delete"]
+
+    def test_test_package_links_to_package_under_test(self, tmp_path):
+        (tmp_path / "user.go").write_text(SOURCE)
+        (tmp_path / "user_ext_test.go").write_text(
+            'package user_test\n\nimport "testing"\n\nfunc TestDelete(t *testing.T) {}\n')
+
+        suite = scan_tests(load_go_files(str(tmp_path)))
+
+        assert targets(suite) == {"TestDelete": "Delete"}
+        assert [f.name for f in suite.untested] == ["FormatUser", "Store.Get"]
+
+    def test_format(self, tmp_path):
+        text = format_tests(suite_of(tmp_path), "user")
+
+        assert text.startswith("9 test functions in user: 5 test, 1 benchmark, 1 fuzz, "
+                               "1 example (+ TestMain)\n")
+        assert "targets by naming convention (heuristic): 7 of 9 matched" in text
+        assert "- @9 test TestFormatUserEmpty → FormatUser" in text
+        assert text.endswith("no test name points at (heuristic, 1): Delete")
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "user.go").write_text(SOURCE)
+        (tmp_path / "user_test.go").write_text(TESTS)
+
+        data = json.loads(scan_tests_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data["counts"]["test"] == 5
+        first = data["tests"][0]
+        assert first["file"].endswith("user_test.go")
+        assert {k: v for k, v in first.items() if k != "file"} == {
+            "name": "TestMain", "kind": "main", "line": 5, "target": None}
+        assert [f["name"] for f in data["untested"]] == ["Delete"]
+
+    def test_none_found(self, tmp_path):
+        (tmp_path / "a.go").write_text("package a\n\nfunc A() {}\n")
+
+        assert scan_tests_tool.fn(str(tmp_path))[0].text.startswith("No test functions found")
@@ -0,0 +1,96 @@
+"""Tests for golang.typedeps: transitive field-type dependencies of a type
+within its package."""
+
+import json
+
+from scantool.golang.typedeps import format_type_dependencies, type_dependencies
+from scantool.server import type_dependencies as type_dependencies_tool
+from conftest import load_go_tree
+
+SHOP = """package shop
+
+import (
+	"sync"
+	"time"
+)
+
+type User struct {
+	ID      int
+	Name    string
+	Created time.Time
+}
+
+type Order struct {
+	ID     string
+	Buyer  *User
+	Items  []Item
+	Tags   map[string][]Tag
+	Parent *Order
+	Meta   struct {
+		Source Channel
+	}
+	mu     sync.Mutex
+	Notify func(Order) error
+}
+
+type Item struct {
+	Price Money
+	Qty   int
+}
+
+type Money int64
+
+type Tag = string
+
+type Channel string
+
+type Page[T any] struct {
+	Items []T
+	Next  *Page[T]
+	Owner User
+}
+"""
+
+
+def deps_of(tmp_path, type_name):
+    return type_dependencies(load_go_tree(tmp_path, {"shop.go": SHOP}), str(tmp_path), type_name)
+
+
+def test_user_has_only_an_external_leaf(tmp_path):
+    result = deps_of(tmp_path, "User")
+    assert [(d.name, d.kind, d.depth) for d in result.dependencies] == [
+        ("time.Time", "external", 1)]
+    assert [(e.source, e.target, e.fields) for e in result.edges] == [
+        ("User", "time.Time", ["Created"])]
+
+
+def test_unwrapping_breadth_first_and_cycles(tmp_path):
+    result = deps_of(tmp_path, "Order")
+    assert [(d.name, d.kind, d.depth) for d in result.dependencies] == [
+        ("User", "struct", 1), ("Item", "struct", 1), ("Tag", "alias", 1),
+        ("Channel", "other", 1), ("sync.Mutex", "external", 1),
+        ("time.Time", "external", 2), ("Money", "other", 2)]
+    edges = {(e.source, e.target): e.fields for e in result.edges}
+    assert edges[("Order", "Order")] == ["Parent"]
+    assert edges[("Order", "Channel")] == ["Meta"]  # inline struct field
+    assert ("Order", "Money") not in edges
+    assert edges[("Item", "Money")] == ["Price"]
+
+
+def test_type_parameters_are_not_dependencies(tmp_path):
+    result = deps_of(tmp_path, "Page")
+    assert [d.name for d in result.dependencies] == ["User", "time.Time"]
+    assert [(e.source, e.target) for e in result.edges][:2] == [("Page", "Page"), ("Page", "User")]
+
+
+def test_format_and_tool(tmp_path):
+    text = format_type_dependencies(deps_of(tmp_path, "Item"))
+    assert "depends on 1 types: 1 local, 0 external" in text.splitlines()[0]
+    assert "- Item → Money  (Price)" in text
+    assert format_type_dependencies(deps_of(tmp_path, "Nope")).startswith("No type named Nope")
+
+    data = json.loads(type_dependencies_tool.fn(str(tmp_path), "User", output_format="json")[0].text)
+    assert data["package"] == "shop"
+    assert [d["name"] for d in data["dependencies"]] == ["time.Time"]
+    assert data["dependencies"][0]["external"] is True
+    assert type_dependencies_tool.fn(str(tmp_path / "missing"), "User")[0].text.startswith("Error")
@@ -0,0 +1,124 @@
+"""Tests for golang.unchecked: values used, or errors overwritten, before
+the error of the call is checked."""
+
+import json
+
+from scantool.golang.syntax import load_go_files
+from scantool.golang.unchecked import find_unchecked_errors, format_unchecked_errors
+from scantool.server import find_unchecked_errors as find_unchecked_errors_tool
+from conftest import load_go_tree
+
+SERVICE = """package service
+
+func (s *Service) CreateUser(name string) error {
+	user, err := s.repo.New(name)
+	user.Name = name
+	if err != nil {
+		return err
+	}
+	return user.Save()
+}
+
+func Checked(id string) (*User, error) {
+	u, err := find(id)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func Returned(id string) (*User, error) {
+	u, err := find(id)
+	return u, err
+}
+
+func Overwritten() error {
+	a, err := first()
+	b, err := second(a)
+	if err != nil {
+		return err
+	}
+	return b.Close()
+}
+
+func Wrapped() error {
+	_, err := first()
+	err = wrap(err)
+	return err
+}
+
+func InLoop(ids []string) {
+	for _, id := range ids {
+		u, parseErr := find(id)
+		go func() {
+			defer u.Close()
+		}()
+		log(parseErr)
+	}
+}
+
+func Ignored() {
+	u, _ := find("x")
+	u.Close()
+}
+"""
+
+
+def unchecked_of(tmp_path, source=SERVICE, **kwargs):
+    return find_unchecked_errors(load_go_tree(tmp_path, {"service.go": source}), **kwargs)
+
+
+class TestFindUncheckedErrors:
+    def test_use_before_check(self, tmp_path):
+        findings = unchecked_of(tmp_path)
+
+        assert [(f.line, f.function, f.kind, f.variable, f.use_line) for f in findings] == [
+            (4, "Service.CreateUser", "used", "user", 5),
+            (26, "Overwritten", "overwritten", "err", 27),
+            (42, "InLoop", "used", "u", 44),
+        ]
+        assert findings[0].statement == "user, err := s.repo.New(name)"
+        assert findings[2].error == "parseErr"
+
+    def test_function_literal(self, tmp_path):
+        findings = unchecked_of(tmp_path, """package p
+
+var handler = func() {
+	f, err := open()
+	f.Read()
+	_ = err
+}
+""")
+
+        assert [(f.function, f.variable) for f in findings] == [("func literal", "f")]
+
+    def test_tests_skipped(self, tmp_path):
+        (tmp_path / "service_test.go").write_text(SERVICE)
+
+        assert find_unchecked_errors(load_go_files(str(tmp_path))) == []
+        assert len(find_unchecked_errors(load_go_files(str(tmp_path)),
+                                         include_tests=True)) == 3
+
+    def test_format(self, tmp_path):
+        text = format_unchecked_errors(unchecked_of(tmp_path), "pkg")
+
+        assert text.startswith("3 possibly unchecked errors in pkg (2 used, 1 overwritten)")
+        assert ("- @4 Service.CreateUser: user, err := s.repo.New(name)  — "
+                "user used @5 before err is checked") in text
+        assert "err overwritten @27 before it is checked" in text
+        assert format_unchecked_errors([], "pkg") == "No unchecked errors found in pkg"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "service.go").write_text(SERVICE)
+
+        data = json.loads(find_unchecked_errors_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data[0] == {"file": str((tmp_path / "service.go").resolve()), "line": 4,
+                           "function": "Service.CreateUser",
+                           "statement": "user, err := s.repo.New(name)", "error": "err",
+                           "kind": "used", "variable": "user", "use_line": 5}
+
+    def test_missing_path(self, tmp_path):
+        assert find_unchecked_errors_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
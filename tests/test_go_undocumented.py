@@ -0,0 +1,109 @@
+"""Tests for golang.undocumented: exported declarations without a doc
+comment."""
+
+import json
+
+from scantool.golang.syntax import load_go_files
+from scantool.golang.undocumented import find_undocumented, format_undocumented
+from scantool.server import find_undocumented as find_undocumented_tool
+
+API = """package api
+
+// Client talks to the service.
+type Client struct{}
+
+type Options struct{}
+
+type internal struct{}
+
+// Get fetches one item.
+func (c *Client) Get(id string) error { return nil }
+
+func (c *Client) Delete(id string) error { return nil }
+
+func (i internal) Exported() {}
+
+//
+func New() *Client { return nil }
+
+//go:noinline
+func Run() {}
+
+/* Close releases the client. */
+func Close() {}
+
+// Detached comment, not a doc comment.
+
+func Stop() {}
+
+func helper() {}
+
+// Limits of the API.
+const (
+	MaxItems = 100
+	MinItems = 1
+)
+
+const (
+	// Version of the wire format.
+	Version = 2
+	Revision, Build = 3, 4
+	secret = "x"
+)
+
+var Default, fallback = New(), New()
+"""
+
+
+def undocumented_names(root, **kwargs):
+    return [(s.kind, s.display_name)
+            for s in find_undocumented(load_go_files(str(root)), **kwargs)]
+
+
+class TestFindUndocumented:
+    def test_exported_without_doc(self, tmp_path):
+        (tmp_path / "api.go").write_text(API)
+
+        assert undocumented_names(tmp_path) == [
+            ("type", "Options"),
+            ("method", "Client.Delete"),
+            ("function", "New"),    # empty comment
+            ("function", "Run"),    # directive only
+            ("function", "Stop"),   # comment separated by a blank line
+            ("const", "Revision"),
+            ("const", "Build"),
+            ("var", "Default"),
+        ]
+
+    def test_tests_and_generated_files_skipped(self, tmp_path):
+        (tmp_path / "api_test.go").write_text("package api\n\nfunc Fixture() {}\n")
+        (tmp_path / "gen.go").write_text(
+            "// Code generated by tool. DO NOT EDIT.\n\npackage api\n\nfunc Generated() {}\n")
+
+        assert undocumented_names(tmp_path) == []
+        assert undocumented_names(tmp_path, include_generated=True) == [
+            ("function", "Generated")]
+
+    def test_format(self, tmp_path):
+        (tmp_path / "api.go").write_text(API)
+
+        text = format_undocumented(find_undocumented(load_go_files(str(tmp_path))), "pkg")
+
+        assert text.startswith("8 exported symbols without a doc comment in pkg "
+                               "(1 type, 1 method, 3 function, 2 const, 1 var)")
+        assert "- @13 method Client.Delete" in text
+        assert format_undocumented([], "pkg") == "Every exported symbol in pkg has a doc comment"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "api.go").write_text(API)
+
+        data = json.loads(find_undocumented_tool.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert data[1] == {"name": "Client.Delete", "kind": "method",
+                           "file": str((tmp_path / "api.go").resolve()), "line": 13,
+                           "receiver": "Client"}
+
+    def test_missing_path(self, tmp_path):
+        assert find_undocumented_tool.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
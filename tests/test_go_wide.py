@@ -0,0 +1,83 @@
+"""Tests for golang.wide: long parameter lists and wide structs."""
+
+import json
+
+from scantool.golang.wide import find_wide_signatures, format_wide_signatures
+from scantool.server import find_wide_signatures as find_wide_signatures_tool
+from conftest import load_go_tree
+
+CLIENT = """package client
+
+type Options struct {
+	Host, Port string
+	Timeout    int
+	Logger
+}
+
+type Client struct{}
+
+func Dial(host, port, user, pass string) error { return nil }
+
+func Open(string, int, bool) {}
+
+func (c *Client) Send(ctx Context, to string, body []byte, retries ...int) {}
+
+func run() {
+	handle := func(a, b, c, d int) {}
+	type row struct{ A, B, C, D int }
+	_ = handle
+}
+"""
+
+
+def wide_of(tmp_path, **limits):
+    return find_wide_signatures(load_go_tree(tmp_path, {"client.go": CLIENT}), **limits)
+
+
+def test_grouped_names_counted(tmp_path):
+    found = [(w.kind, w.name, w.count) for w in wide_of(tmp_path, max_params=3, max_fields=3)]
+    assert found == [
+        ("struct", "Options", 4),
+        ("function", "Dial", 4),
+        ("method", "Client.Send", 4),
+        ("func literal", "func literal in run", 4),
+        ("struct", "row", 4),
+    ]
+
+
+def test_thresholds_and_disabled_checks(tmp_path):
+    assert wide_of(tmp_path) == []  # defaults: 5 parameters, 15 fields
+    assert [w.kind for w in wide_of(tmp_path, max_params=2, max_fields=None)] == [
+        "function", "function", "method", "func literal"]
+    assert {w.name for w in wide_of(tmp_path, max_params=None, max_fields=3)} == {"Options", "row"}
+
+
+def test_type_parameters_and_test_files(tmp_path):
+    files = load_go_tree(tmp_path, {
+        "pair.go": "package client\n\ntype Pair[K, V, W any] struct {\n\tKey K\n\tVal V\n}\n\n"
+                   "func (p *Pair[K, V, W]) Set(k K, v V) {}\n\n"
+                   "func Zip[A, B, C any](a []A, b []B) []C { return nil }\n",
+        "pair_test.go": "package client\n\nfunc check(a, b, c int) {}\n",
+    })
+
+    # Type parameters are neither parameters nor fields
+    assert find_wide_signatures(files, max_params=2, max_fields=2) == []
+    found = find_wide_signatures(files, max_params=1, max_fields=1, include_tests=True)
+    assert [(w.kind, w.name, w.count) for w in found] == [
+        ("struct", "Pair", 2), ("method", "Pair.Set", 2), ("function", "Zip", 2),
+        ("function", "check", 3)]
+
+
+def test_format_and_tool(tmp_path):
+    text = format_wide_signatures(wide_of(tmp_path, max_params=3, max_fields=3), "client",
+                                  max_params=3, max_fields=3)
+    assert text.splitlines()[0] == ("3 functions with more than 3 parameters, "
+                                    "2 structs with more than 3 fields in client")
+    assert "- @11 function Dial: 4 parameters (max 3)" in text
+    assert format_wide_signatures([], "client", max_fields=None) == \
+        "No functions with more than 5 parameters in client"
+
+    data = json.loads(find_wide_signatures_tool.fn(str(tmp_path), max_params=3,
+                                                   output_format="json")[0].text)
+    assert [w["name"] for w in data] == ["Dial", "Client.Send", "func literal in run"]
+    assert find_wide_signatures_tool.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
@@ -0,0 +1,120 @@
+"""Tests for hotspots: files ranked by complexity, TODO markers and size,
+with the score broken down per metric."""
+
+import json
+
+import pytest
+
+from scantool.hotspots import check_weights, find_hotspots, format_hotspots
+from scantool.languages import StructureNode
+from scantool.server import hotspots
+
+
+def function(name, cyclomatic, **kwargs):
+    return StructureNode(type="function", name=name, start_line=1, end_line=2,
+                         complexity={"lines": 2, "depth": 1, "branches": 0,
+                                     "cyclomatic": cyclomatic}, **kwargs)
+
+
+def info(code_lines):
+    return StructureNode(type="file-info", name="f", start_line=1, end_line=1,
+                         file_metadata={"lines": {"total": code_lines + 5, "code": code_lines,
+                                                  "comment": 3, "blank": 2}})
+
+
+@pytest.fixture
+def results(tmp_path):
+    """Three files: one complex, one full of TODOs, one long and simple;
+    plus a file with a TODO but no functions."""
+    def write(name, text):
+        path = tmp_path / name
+        path.write_text(text)
+        return str(path)
+
+    complex_file = write("engine.txt", "")
+    todo_file = write("notes.txt", "TODO: a\nFIXME b\nHACK c\nTODOS is not one\nXXX d\n")
+    long_file = write("table.txt", "TODO once\n")
+    empty_file = write("README.txt", "TODO in prose\n")
+    method = StructureNode(type="method", name="Run", start_line=3, end_line=9,
+                           receiver_type="Engine",
+                           complexity={"cyclomatic": 20})
+    return {
+        complex_file: [info(100), function("helper", 4), method],
+        todo_file: [info(50), function("note", 2)],
+        long_file: [info(400), function("row", 1), function("col", 1)],
+        empty_file: [info(10)],
+    }
+
+
+class TestFindHotspots:
+    def test_ranked_by_score(self, results):
+        spots = find_hotspots(results)
+
+        assert [(s.file.rsplit("/", 1)[-1], s.score) for s in spots] == [
+            ("engine.txt", 65.0), ("notes.txt", 30.2), ("table.txt", 28.9), ("README.txt", 5.5)]
+        engine = spots[0]
+        assert (engine.max_complexity, engine.max_function, engine.avg_complexity,
+                engine.functions, engine.todos, engine.lines) == (20, "Engine.Run", 12.0, 2, 0, 100)
+        assert engine.shares == {"max_complexity": 35.0, "avg_complexity": 25.0,
+                                 "todos": 0.0, "lines": 5.0}
+
+    def test_markers_counted_as_words(self, results):
+        notes = next(s for s in find_hotspots(results) if s.file.endswith("notes.txt"))
+
+        assert notes.todos == 4
+
+    def test_sort_by_metric_and_top(self, results):
+        spots = find_hotspots(results, sort_by="todos", top=2)
+
+        assert [s.file.rsplit("/", 1)[-1] for s in spots] == ["notes.txt", "README.txt"]
+
+    def test_weights(self, results):
+        spots = find_hotspots(results, weights={"max_complexity": 0, "avg_complexity": 0,
+                                                "todos": 0, "lines": 1})
+
+        assert [s.file.rsplit("/", 1)[-1] for s in spots][:2] == ["table.txt", "engine.txt"]
+        assert spots[0].score == 100.0
+
+    def test_files_without_code_skipped(self, tmp_path):
+        path = tmp_path / "plain.txt"
+        path.write_text("nothing here\n")
+
+        assert find_hotspots({str(path): [info(1)], str(tmp_path / "x.bin"): None}) == []
+
+    def test_invalid_options(self, results):
+        with pytest.raises(ValueError, match="sort_by"):
+            find_hotspots(results, sort_by="size")
+        with pytest.raises(ValueError, match="top"):
+            find_hotspots(results, top=0)
+        with pytest.raises(ValueError, match="Unknown hotspot metric"):
+            check_weights({"churn": 1})
+        with pytest.raises(ValueError, match="negative"):
+            check_weights({"todos": -1})
+        with pytest.raises(ValueError, match="above 0"):
+            check_weights(dict.fromkeys(("max_complexity", "avg_complexity", "todos", "lines"), 0))
+
+    def test_format(self, results, tmp_path):
+        text = format_hotspots(find_hotspots(results), str(tmp_path))
+
+        assert text.startswith("Top 4 hotspots in ")
+        assert "1. engine.txt  score 65" in text
+        assert ("   complexity max 20 (Engine.Run) avg 12 over 2 functions · "
+                "0 TODOs · 100 lines") in text
+        assert "   score from: max complexity 35, avg complexity 25, lines 5" in text
+        assert format_hotspots([], "src") == "No code files with functions or TODO markers in src"
+
+
+class TestTool:
+    def test_json_output(self, tmp_path):
+        (tmp_path / "calc.go").write_text(
+            "package calc\n\n// TODO: split\nfunc Calc(n int) int {\n"
+            "\tif n > 0 {\n\t\treturn 1\n\t}\n\treturn 0\n}\n")
+
+        data = json.loads(hotspots.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert [(d["max_function"], d["max_complexity"], d["todos"]) for d in data] == \
+            [("Calc", 2, 1)]
+
+    def test_errors(self, tmp_path):
+        assert hotspots.fn(str(tmp_path / "nope"))[0].text.startswith("Error:")
+        assert hotspots.fn(str(tmp_path), sort_by="size")[0].text.startswith("Error: Unknown sort_by")
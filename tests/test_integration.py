@@ -134,3 +134,19 @@ def test_scan_directory_propagates_mode(monkeypatch):
 
     assert results
     assert seen_modes and all(m == "active" for m in seen_modes)
+
+
+@pytest.mark.parametrize("sample", ["go/samples/basic.go", "python/samples/basic.py"])
+def test_scan_content_matches_scan_file(sample):
+    """In-memory content scans exactly like the same bytes on disk; only the
+    file-info metadata (no timestamps for content) differs."""
+    path = Path(__file__).parent / sample
+    scanner = FileScanner()
+    formatter = TreeFormatter()
+
+    from_disk = scanner.scan_file(str(path))
+    from_content = scanner.scan_content(path.read_bytes(), path.name, include_metadata=True)
+
+    assert from_content[0].file_metadata["source"] == "content"
+    assert formatter.format(path.name, from_content[1:]) == \
+        formatter.format(path.name, from_disk[1:])
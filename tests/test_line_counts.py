@@ -0,0 +1,53 @@
+"""Tests for line_counts: code/comment/blank split from comment tokens, and
+per-symbol line counts in the JSON output."""
+
+import json
+
+from scantool.line_counts import count_lines
+from scantool.scanner import FileScanner
+from scantool.server import _structures_to_json
+
+GO_SOURCE = '''\
+package calc
+
+// Add sums two numbers.
+func Add(a, b int) int { // trailing comment: still code
+\t/* block comment
+
+\t   spanning a blank line */
+\ts := "// not a comment"
+\treturn a + b + len(s)
+}
+'''
+
+
+def scan(tmp_path, filename, source):
+    path = tmp_path / filename
+    path.write_text(source)
+    return FileScanner().scan_file(str(path))
+
+
+class TestFileLineCounts:
+    def test_go_breakdown_uses_comment_tokens(self, tmp_path):
+        structures = scan(tmp_path, "calc.go", GO_SOURCE)
+
+        assert structures[0].file_metadata["lines"] == {
+            "total": 10, "code": 5, "comment": 4, "blank": 1}
+
+    def test_language_without_comment_tokens_reports_total_and_blank(self):
+        assert count_lines(b"a\n\nb\n", None) == {"total": 3, "blank": 1}
+
+    def test_empty_source(self):
+        assert count_lines(b"", []) == {"total": 0, "code": 0, "comment": 0, "blank": 0}
+
+
+class TestSymbolLineCount:
+    def test_json_carries_line_count_and_file_lines(self, tmp_path):
+        structures = scan(tmp_path, "calc.go", GO_SOURCE)
+
+        data = json.loads(_structures_to_json(structures, str(tmp_path / "calc.go")))
+
+        add = next(n for n in data["structures"] if n["name"] == "Add")
+        assert add["line_count"] == add["end_line"] - add["start_line"] + 1 == 7
+        assert data["lines"]["total"] == 10
+        assert "line_count" not in data["structures"][0]  # file-info
@@ -0,0 +1,134 @@
+"""Tests for lsp_symbols: scan results as LSP DocumentSymbol trees — kinds,
+0-based UTF-16 ranges, selectionRange on the name, Go methods nested."""
+
+import json
+
+from scantool.languages import StructureNode
+from scantool.lsp_symbols import CLASS, FUNCTION, METHOD, STRUCT, document_symbols
+from scantool.server import scan_file, scan_file_content
+
+USERS = (
+    "package users\n"
+    "\n"
+    "type User struct {\n"
+    "\tName string\n"
+    "}\n"
+    "\n"
+    "func (u *User) User() string { return u.Name }\n"
+    "\n"
+    "// Grüße returns a greeting.\n"
+    "func Grüße() string { return \"hi\" }\n"
+)
+
+
+def declaration(source: bytes, text: str, node_type: str, name: str, **kwargs) -> StructureNode:
+    """Node over the first occurrence of text, positioned like the scanner does."""
+    start = source.index(text.encode("utf-8"))
+    end = start + len(text.encode("utf-8"))
+    line_start = source.rfind(b"\n", 0, start) + 1
+    end_line_start = source.rfind(b"\n", 0, end) + 1
+
+    def utf16(a, b):
+        return len(source[a:b].decode("utf-8").encode("utf-16-le")) // 2
+
+    return StructureNode(type=node_type, name=name,
+                         start_line=source.count(b"\n", 0, start) + 1,
+                         end_line=source.count(b"\n", 0, end) + 1,
+                         start_offset=start, end_offset=end,
+                         start_utf16_column=utf16(line_start, start),
+                         end_utf16_column=utf16(end_line_start, end), **kwargs)
+
+
+def users_structures(source: bytes) -> list[StructureNode]:
+    user = declaration(source, "type User struct {\n\tName string\n}", "struct", "User",
+                       methods=["User"])
+    method = declaration(source, "func (u *User) User() string { return u.Name }",
+                         "method", "User", receiver_type="User", signature="() string")
+    greet = declaration(source, 'func Grüße() string { return "hi" }', "function", "Grüße",
+                        signature="() string")
+    info = StructureNode(type="file-info", name="users.go", start_line=1, end_line=1)
+    return [info, user, method, greet]
+
+
+def span(start_line, start_char, end_line, end_char):
+    return {"start": {"line": start_line, "character": start_char},
+            "end": {"line": end_line, "character": end_char}}
+
+
+class TestDocumentSymbols:
+    def test_kinds_ranges_and_nesting(self):
+        source = USERS.encode("utf-8")
+
+        symbols = document_symbols(users_structures(source), source)
+
+        assert [(s["name"], s["kind"]) for s in symbols] == [("User", STRUCT), ("Grüße", FUNCTION)]
+        user = symbols[0]
+        assert user["range"] == span(2, 0, 4, 1)
+        assert user["selectionRange"] == span(2, 5, 2, 9)
+        method = user["children"][0]
+        assert (method["name"], method["kind"], method["detail"]) == ("User", METHOD, "() string")
+        # The name after the receiver, not the receiver type
+        assert method["selectionRange"] == span(6, 15, 6, 19)
+
+    def test_utf16_columns(self):
+        source = USERS.encode("utf-8")
+
+        greet = document_symbols(users_structures(source), source)[1]
+
+        assert greet["selectionRange"] == span(9, 5, 9, 10)
+        assert greet["range"] == span(9, 0, 9, 35)
+
+    def test_crlf_and_bom(self):
+        lf = USERS.encode("utf-8")
+        crlf = b"\xef\xbb\xbf" + USERS.replace("\n", "\r\n").encode("utf-8")
+        structures = users_structures(lf)
+        # Offsets index the file's own bytes, as scan_file restores them
+        for node in structures[1:]:
+            text = lf[node.start_offset:node.end_offset].replace(b"\n", b"\r\n")
+            node.start_offset = crlf.index(text)
+            node.end_offset = node.start_offset + len(text)
+
+        assert document_symbols(structures, crlf) == document_symbols(users_structures(lf), lf)
+
+    def test_methods_of_other_files_stay_top_level(self):
+        source = b"package users\n\nfunc (a Admin) Ban() {}\n"
+        method = declaration(source, "func (a Admin) Ban() {}", "method", "Ban",
+                             receiver_type="Admin")
+
+        assert [s["name"] for s in document_symbols([method], source)] == ["Ban"]
+
+    def test_nodes_without_columns_span_whole_lines(self):
+        source = b"# Title\n\nText\n\n## Usage\nmore\n"
+        heading = StructureNode(type="heading", name="Usage", start_line=5, end_line=6)
+        nested = StructureNode(type="class", name="Box", start_line=1, end_line=3,
+                               children=[heading])
+
+        symbol = document_symbols([nested], source)[0]
+
+        assert symbol["kind"] == CLASS
+        assert symbol["selectionRange"] == span(0, 0, 0, 0)  # name not written out
+        assert symbol["children"][0]["range"] == span(4, 0, 5, 4)
+        assert symbol["children"][0]["selectionRange"] == span(4, 3, 4, 8)
+
+    def test_imports_and_errors_skipped(self):
+        nodes = [StructureNode(type="imports", name="imports", start_line=1, end_line=1),
+                 StructureNode(type="parse-error", name="x", start_line=2, end_line=2)]
+
+        assert document_symbols(nodes, b"import x\n?\n") == []
+
+
+class TestTool:
+    def test_scan_file_lsp(self, tmp_path):
+        path = tmp_path / "users.go"
+        path.write_bytes(USERS.encode("utf-8"))
+
+        symbols = json.loads(scan_file.fn(str(path), output_format="lsp")[0].text)
+
+        assert [(s["name"], s["kind"]) for s in symbols] == [("User", STRUCT), ("Grüße", FUNCTION)]
+        assert [(c["name"], c["kind"]) for c in symbols[0]["children"]] == [("User", METHOD)]
+        assert symbols[0]["children"][0]["selectionRange"] == span(6, 15, 6, 19)
+
+    def test_scan_file_content_lsp(self):
+        symbols = json.loads(scan_file_content.fn(USERS, "users.go", output_format="lsp")[0].text)
+
+        assert symbols[1]["range"] == span(9, 0, 9, 35)
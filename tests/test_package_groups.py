@@ -0,0 +1,58 @@
+"""Tests for package_groups: directory results grouped by directory and
+declared package."""
+
+import json
+
+from scantool.languages import StructureNode
+from scantool.package_groups import group_by_package
+from scantool.server import scan_directory
+
+
+def result(package=None):
+    metadata = {"language": "Go"} if package else {"language": "Python"}
+    if package:
+        metadata["package"] = package
+    return [StructureNode(type="file-info", name="f", start_line=1, end_line=1,
+                          file_metadata=metadata),
+            StructureNode(type="function", name="f", start_line=1, end_line=2)]
+
+
+def test_groups_in_directory_order_with_test_packages_apart(tmp_path):
+    root = str(tmp_path)
+    results = {f"{root}/users/users.go": result("users"),
+               f"{root}/users/users_test.go": result("users_test"),
+               f"{root}/users/store.go": result("users"),
+               f"{root}/users/scripts/seed.py": result(),
+               f"{root}/main.go": result("main"),
+               f"{root}/tools.py": result(),
+               f"{root}/api/v1/api.go": result("v1")}
+
+    groups = group_by_package(results, root)
+    assert [(g.dir, g.package, [f.rsplit("/", 1)[1] for f in g.files]) for g in groups] == [
+        (".", "main", ["main.go"]),
+        (".", None, ["tools.py"]),
+        ("api/v1", "v1", ["api.go"]),
+        ("users", "users", ["store.go", "users.go"]),
+        ("users", "users_test", ["users_test.go"]),
+        ("users/scripts", None, ["seed.py"]),
+    ]
+
+
+def test_scan_directory_grouped(tmp_path):
+    (tmp_path / "users").mkdir()
+    (tmp_path / "users" / "users.go").write_text("package users\n\nfunc Get() {}\n")
+    (tmp_path / "users" / "users_test.go").write_text("package users_test\n\nfunc TestGet() {}\n")
+    (tmp_path / "README.md").write_text("# Tool\n")
+
+    data = json.loads(scan_directory.fn(str(tmp_path), output_format="json", delta=False,
+                                        group_by_package=True, path_style="relative")[0].text)
+    assert [(g["dir"], g["package"], list(g["files"])) for g in data["packages"]] == [
+        (".", None, ["README.md"]),
+        ("users", "users", ["users/users.go"]),
+        ("users", "users_test", ["users/users_test.go"]),
+    ]
+    assert data["packages"][1]["files"]["users/users.go"]["package"] == "users"
+
+    tree = scan_directory.fn(str(tmp_path), delta=False, group_by_package=True)[0].text
+    assert "users/ (2 files, 2 functions) [package users, users_test]" in tree
+    assert "users_test.go (package users_test)" in tree
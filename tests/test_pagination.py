@@ -0,0 +1,86 @@
+"""Tests for pagination: cursor pages over scan_directory results tile the
+sorted file set exactly, and say so when the set changed between calls."""
+
+import json
+
+import pytest
+
+from scantool.pagination import decode_cursor, encode_cursor, paginate
+from scantool.server import scan_directory
+
+RESULTS = {f"/repo/{name}.go": [] for name in "edcba"}
+
+
+def all_pages(results, limit):
+    pages, cursor = [], None
+    while True:
+        page = paginate(results, limit, cursor)
+        pages.append(page)
+        cursor = page.next_cursor
+        if cursor is None:
+            return pages
+
+
+class TestPaginate:
+    def test_pages_tile_the_sorted_set(self):
+        pages = all_pages(RESULTS, 2)
+
+        assert [list(p.results) for p in pages] == [
+            ["/repo/a.go", "/repo/b.go"], ["/repo/c.go", "/repo/d.go"], ["/repo/e.go"]]
+        assert [p.offset for p in pages] == [0, 2, 4]
+        assert all(p.total == 5 and not p.stale for p in pages)
+
+    def test_exact_multiple_has_no_empty_last_page(self):
+        pages = all_pages(RESULTS, 5)
+
+        assert len(pages) == 1 and pages[0].next_cursor is None
+
+    def test_changed_set_is_flagged_stale_and_resumes_after_path(self):
+        first = paginate(RESULTS, 2)
+        changed = {**RESULTS, "/repo/aa.go": []}
+
+        page = paginate(changed, 2, first.next_cursor)
+
+        assert page.stale
+        assert list(page.results) == ["/repo/c.go", "/repo/d.go"]
+
+    def test_cursor_round_trip(self):
+        assert decode_cursor(encode_cursor("/repo/b.go", "abc")) == ("/repo/b.go", "abc")
+
+    def test_invalid_cursor_and_limit(self):
+        with pytest.raises(ValueError, match="Invalid cursor"):
+            paginate(RESULTS, 2, "not-a-cursor")
+        with pytest.raises(ValueError):
+            paginate(RESULTS, 0)
+
+
+class TestTool:
+    def make_tree(self, root):
+        for name in ("a", "b", "c"):
+            (root / f"{name}.go").write_text(f"package p\n\nfunc {name.upper()}() {{}}\n")
+
+    def test_json_pages(self, tmp_path):
+        self.make_tree(tmp_path)
+
+        first = json.loads(scan_directory.fn(str(tmp_path), output_format="json", delta=False,
+                                             limit=2)[0].text)
+        second = json.loads(scan_directory.fn(str(tmp_path), output_format="json", delta=False,
+                                              limit=2, cursor=first["next_cursor"])[0].text)
+
+        assert (first["offset"], first["total_files"], len(first["files"])) == (0, 3, 2)
+        assert list(second["files"]) == [str(tmp_path.resolve() / "c.go")]
+        assert second["next_cursor"] is None
+
+    def test_tree_page_footer(self, tmp_path):
+        self.make_tree(tmp_path)
+
+        text = scan_directory.fn(str(tmp_path), delta=False, limit=2)[0].text
+
+        assert "page: files 1-2 of 3 — next: cursor=" in text
+
+    def test_cursor_without_limit(self, tmp_path):
+        self.make_tree(tmp_path)
+
+        text = scan_directory.fn(str(tmp_path), cursor=encode_cursor("a", "b"))[0].text
+
+        assert text.startswith("Error: cursor needs the limit")
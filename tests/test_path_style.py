@@ -0,0 +1,59 @@
+"""Tests for path_style: absolute vs root-relative result paths, with "/"
+separators for relative ones on every OS."""
+
+import json
+import ntpath
+from types import SimpleNamespace
+
+import pytest
+
+from scantool import path_style
+from scantool.path_style import check_path_style, style_path
+from scantool.server import scan_directory
+
+
+class TestStylePath:
+    def test_relative_to_root(self, tmp_path):
+        path = str(tmp_path / "pkg" / "api.go")
+
+        assert style_path(path, str(tmp_path), "relative") == "pkg/api.go"
+        assert style_path(path, str(tmp_path), "absolute") == path
+
+    def test_outside_root(self, tmp_path):
+        path = str(tmp_path / "other" / "x.go")
+
+        assert style_path(path, str(tmp_path / "root"), "relative") == "../other/x.go"
+
+    def test_windows_separators(self, monkeypatch):
+        monkeypatch.setattr(path_style, "os", SimpleNamespace(path=ntpath, sep="\\"))
+
+        assert style_path(r"C:\repo\pkg\api.go", r"C:\repo", "relative") == "pkg/api.go"
+        assert style_path(r"D:\other\x.go", r"C:\repo", "relative") == r"D:\other\x.go"
+
+    def test_unknown_style(self):
+        with pytest.raises(ValueError, match="path_style"):
+            check_path_style("posix")
+
+
+class TestScanDirectory:
+    def test_json_paths(self, tmp_path):
+        (tmp_path / "pkg").mkdir()
+        (tmp_path / "pkg" / "notes.txt").write_text("Title\n=====\n\nbody\n")
+
+        data = json.loads(scan_directory.fn(str(tmp_path), output_format="json",
+                                            path_style="relative")[0].text)
+
+        assert list(data) == ["pkg/notes.txt"]
+        assert data["pkg/notes.txt"]["file"] == "pkg/notes.txt"
+
+    def test_default_is_absolute(self, tmp_path):
+        (tmp_path / "notes.txt").write_text("Title\n=====\n\nbody\n")
+
+        data = json.loads(scan_directory.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert list(data) == [str((tmp_path / "notes.txt").resolve())]
+
+    def test_unknown_style(self, tmp_path):
+        text = scan_directory.fn(str(tmp_path), path_style="posix")[0].text
+
+        assert text.startswith("Error: Unknown path_style 'posix'")
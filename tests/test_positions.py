@@ -0,0 +1,114 @@
+"""Tests for positions: go/token-style Position math, exact Go ranges for
+declarations, names and bodies, and the whole-line fallback."""
+
+import json
+from pathlib import Path
+
+from scantool.file_json import file_to_dict
+from scantool.languages import StructureNode
+from scantool.positions import SourcePositions
+from scantool.server import scan_directory, scan_file
+
+SOURCE = (
+    "package shapes\n"
+    "\n"
+    "// Area of a rectangle.\n"
+    "func Area(w, h int) int {\n"
+    "\treturn w * h\n"
+    "}\n"
+    "\n"
+    "type Point struct {\n"
+    "\tX, Y int\n"
+    "}\n"
+)
+
+
+def sliced(source: bytes, span: dict) -> bytes:
+    return source[span["start"]["offset"]:span["end"]["offset"]]
+
+
+class TestSourcePositions:
+    def test_position_of_offset(self):
+        positions = SourcePositions(b"ab\ncd\n", "x.go")
+
+        assert positions.position(0) == {"filename": "x.go", "offset": 0, "line": 1, "column": 1}
+        assert positions.position(4) == {"filename": "x.go", "offset": 4, "line": 2, "column": 2}
+        assert positions.position(6)["line"] == 3
+
+    def test_columns_count_bytes(self):
+        positions = SourcePositions("// ü\nx".encode("utf-8"), "x.go")
+
+        assert positions.position(5)["column"] == 6
+        assert positions.position(6) == {"filename": "x.go", "offset": 6, "line": 2, "column": 1}
+
+    def test_line_range_without_offsets(self):
+        source = b"a = 1\r\ndef f():\r\n    pass\r\n"
+        node = StructureNode(type="function", name="f", start_line=2, end_line=3)
+
+        ranges = SourcePositions(source, "f.py").of(node)
+
+        assert sliced(source, ranges["range"]) == b"def f():\r\n    pass"
+        assert "name_range" not in ranges and "body_range" not in ranges
+
+    def test_file_info_has_none(self):
+        node = StructureNode(type="file-info", name="f.py", start_line=1, end_line=1)
+
+        assert SourcePositions(b"", "f.py").of(node) is None
+
+    def test_only_with_positions(self):
+        node = StructureNode(type="function", name="f", start_line=1, end_line=1)
+
+        assert "positions" not in file_to_dict([node], "f.py")["structures"][0]
+        data = file_to_dict([node], "f.py", SourcePositions(b"def f(): pass\n", "f.py"))
+        assert data["structures"][0]["positions"]["range"]["end"]["column"] == 14
+
+
+class TestGoPositions:
+    def scan(self, tmp_path, source: bytes):
+        path = tmp_path / "shapes.go"
+        path.write_bytes(source)
+        text = scan_file.fn(str(path), positions=True, output_format="json", delta=False)[0].text
+        return {node["name"]: node["positions"] for node in json.loads(text)["structures"]
+                if node["type"] != "file-info" and "positions" in node}
+
+    def test_function_ranges(self, tmp_path):
+        source = SOURCE.encode("utf-8")
+        area = self.scan(tmp_path, source)["Area"]
+
+        assert sliced(source, area["range"]).startswith(b"func Area(")
+        assert sliced(source, area["name_range"]) == b"Area"
+        assert sliced(source, area["body_range"]) == b"{\n\treturn w * h\n}"
+        assert area["name_range"]["start"] == {"filename": str(tmp_path / "shapes.go"),
+                                               "offset": 45, "line": 4, "column": 6}
+
+    def test_type_body_is_type_expression(self, tmp_path):
+        source = SOURCE.encode("utf-8")
+        point = self.scan(tmp_path, source)["Point"]
+
+        assert sliced(source, point["name_range"]) == b"Point"
+        assert sliced(source, point["body_range"]) == b"struct {\n\tX, Y int\n}"
+
+    def test_offsets_index_crlf_file(self, tmp_path):
+        source = SOURCE.replace("\n", "\r\n").encode("utf-8")
+        area = self.scan(tmp_path, source)["Area"]
+
+        assert sliced(source, area["name_range"]) == b"Area"
+        assert area["name_range"]["start"]["line"] == 4
+
+
+class TestDirectoryPositions:
+    def test_stubs_have_no_positions(self, tmp_path):
+        (tmp_path / "shapes.go").write_text(SOURCE)
+        (tmp_path / "big.go").write_text("package shapes\n" + "// filler\n" * 200)
+        (tmp_path / "deep" / "er").mkdir(parents=True)
+        (tmp_path / "deep" / "er" / "x.go").write_text("package er\n")
+
+        text = scan_directory.fn(str(tmp_path), "**/*.go", output_format="json", positions=True,
+                                 max_file_size=1000, max_depth=1, report_depth_limit=True,
+                                 delta=False)[0].text
+        files = {Path(k).name: v for k, v in json.loads(text).items()}
+
+        assert any("positions" in node for node in files["shapes.go"]["structures"])
+        assert not any("positions" in node for node in files["big.go"]["structures"])
+        assert files["er"]["skipped"] == "max_depth"
+        assert not any("positions" in node for node in files["er"]["structures"])
@@ -0,0 +1,71 @@
+"""Tests for FileScanner post-processors: per-file hooks that enrich or
+replace a parsed result."""
+
+from scantool.file_json import file_to_dict
+from scantool.languages import SKIP_POST_PROCESS, parse_errors, skip_reason
+from scantool.scanner import FileResult, FileScanner
+
+
+def write_sources(tmp_path):
+    (tmp_path / "a.py").write_text("def alpha():\n    return 1\n")
+    (tmp_path / "b.py").write_text("def beta():\n    return 2\n")
+    (tmp_path / "notes.bin").write_bytes(b"\x00\x01")
+
+
+def test_processors_run_in_order_and_set_meta(tmp_path):
+    write_sources(tmp_path)
+    calls = []
+
+    def count_functions(result: FileResult):
+        calls.append(("count", result.file))
+        result.meta["functions"] = sum(1 for s in result.structures if s.type == "function")
+
+    def link_docs(result: FileResult):
+        calls.append(("link", result.file))
+        result.meta["docs"] = f"https://docs.example.com/{result.meta['functions']}"
+
+    scanner = FileScanner(post_processors=[count_functions]).add_post_processor(link_docs)
+    results = scanner.scan_directory(str(tmp_path))
+
+    a = str(tmp_path / "a.py")
+    assert calls[:2] == [("count", a), ("link", a)]
+    # notes.bin is listed, not parsed: no processor sees it
+    assert {file for _, file in calls} == {a, str(tmp_path / "b.py")}
+    data = file_to_dict(results[a], a)
+    assert data["meta"] == {"functions": 1, "docs": "https://docs.example.com/1"}
+
+
+def test_failing_processor_is_that_files_error(tmp_path):
+    write_sources(tmp_path)
+
+    def reject_beta(result: FileResult):
+        if result.file.endswith("b.py"):
+            raise ValueError("no betas")
+        result.meta["ok"] = True
+
+    def never_after_failure(result: FileResult):
+        assert not result.file.endswith("b.py")
+
+    scanner = FileScanner(post_processors=[reject_beta, never_after_failure])
+    results = scanner.scan_directory(str(tmp_path))
+
+    b = str(tmp_path / "b.py")
+    assert skip_reason(results[b]) == SKIP_POST_PROCESS
+    [error] = parse_errors(b, results[b])
+    assert error.reason == SKIP_POST_PROCESS
+    assert error.message == "Post-processor reject_beta failed: no betas"
+    # The rest of the scan is unaffected
+    a = str(tmp_path / "a.py")
+    assert results[a][0].file_metadata["meta"] == {"ok": True}
+
+
+def test_scan_content_and_replaced_structures(tmp_path):
+    def functions_only(result: FileResult):
+        result.structures = [s for s in result.structures if s.type == "function"]
+
+    scanner = FileScanner().add_post_processor(functions_only)
+    structures = scanner.scan_content("import os\n\ndef f():\n    pass\n", "m.py",
+                                      include_metadata=True)
+    assert [s.name for s in structures] == ["f"]
+    # Unsupported files aren't parsed, so nothing is post-processed
+    assert scanner.scan_content("x", "notes.unknown") is None
@@ -0,0 +1,77 @@
+"""Tests for FileScanner read retries: a file that fails to parse right
+after a write is re-read once the save has finished."""
+
+import os
+import time
+
+from scantool import scanner as scanner_module
+from scantool.languages import parse_errors
+from scantool.scanner import FileScanner
+
+BROKEN = "package edit\n\nfunc Saved() {}\n\n) ) )\n"
+FIXED = "package edit\n\nfunc Saved() {}\n\nfunc Later() {}\n"
+
+
+def names(structures):
+    return [s.name for s in structures if s.type == "function"]
+
+
+def test_rereads_when_the_file_changes_during_the_backoff(tmp_path, monkeypatch):
+    path = tmp_path / "edit.go"
+    path.write_text(BROKEN)
+    waits = []
+
+    def finish_save(seconds):
+        waits.append(seconds)
+        path.write_text(FIXED)
+
+    monkeypatch.setattr(scanner_module.time, "sleep", finish_save)
+    structures = FileScanner().scan_file(str(path))
+    assert waits == [scanner_module.READ_RETRY_BACKOFF]
+    assert names(structures) == ["Saved", "Later"]
+    assert not parse_errors(str(path), structures)
+    assert structures[0].file_metadata["size"] == len(FIXED)
+
+
+def test_settled_file_keeps_its_errors(tmp_path, monkeypatch):
+    path = tmp_path / "broken.go"
+    path.write_text(BROKEN)
+    waits = []
+    monkeypatch.setattr(scanner_module.time, "sleep", waits.append)
+    structures = FileScanner(read_retries=3).scan_file(str(path))
+    assert len(waits) == 1  # unchanged after the first wait: not retried further
+    assert parse_errors(str(path), structures)
+
+
+def test_backoff_doubles_until_retries_run_out(tmp_path, monkeypatch):
+    path = tmp_path / "busy.go"
+    path.write_text(BROKEN)
+    waits = []
+
+    def still_writing(seconds):
+        waits.append(seconds)
+        path.write_text(BROKEN + "#" * len(waits))
+
+    monkeypatch.setattr(scanner_module.time, "sleep", still_writing)
+    structures = FileScanner(read_retries=3).scan_file(str(path))
+    backoff = scanner_module.READ_RETRY_BACKOFF
+    assert waits == [backoff, backoff * 2, backoff * 4]
+    assert parse_errors(str(path), structures)  # reported, not swallowed
+
+
+def test_no_retry_for_old_files_or_when_disabled(tmp_path, monkeypatch):
+    old = tmp_path / "old.go"
+    old.write_text(BROKEN)
+    an_hour_ago = time.time() - 3600
+    os.utime(old, (an_hour_ago, an_hour_ago))
+    fresh = tmp_path / "fresh.go"
+    fresh.write_text(BROKEN)
+    valid = tmp_path / "valid.go"
+    valid.write_text(FIXED)
+    waits = []
+    monkeypatch.setattr(scanner_module.time, "sleep", waits.append)
+
+    FileScanner().scan_file(str(old))
+    FileScanner(read_retries=0).scan_file(str(fresh))
+    FileScanner().scan_file(str(valid))
+    assert waits == []
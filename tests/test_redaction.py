@@ -0,0 +1,74 @@
+"""Tests for redaction: string literal values replaced by their length or
+a short hash in scan output."""
+
+import json
+
+from scantool.languages import StructureNode
+from scantool.redaction import redact_code, redact_structures, redact_value
+from scantool.server import extract_strings, list_constants, scan_file, scan_file_content
+
+
+def test_placeholders():
+    assert redact_value("sk-live-123", "length") == "<string:11>"
+    assert redact_value("sk-live-123", "hash") == redact_value("sk-live-123", "hash")
+    assert redact_value("sk-live-123", "hash") != redact_value("sk-live-124", "hash")
+    assert redact_value("sk-live-123", "hash").startswith("<string:#")
+    assert redact_value("sk-live-123", "off") == "sk-live-123"
+
+
+def test_code_literals_and_quoting():
+    assert redact_code('def f(token="sk-1", mode=\'x\', n=2):', "length") == \
+        "def f(token=<string:4>, mode=<string:1>, n=2):"
+    assert redact_code('x = f"hi {name}" + rb"\\x00"', "length") == \
+        "x = <string:9> + <string:4>"
+    # runes stay where single quotes are characters
+    assert redact_code("c := 'a' + \"b\"", "length", single_quotes=False) == "c := 'a' + <string:1>"
+    # a multi-line literal keeps its line breaks
+    assert redact_code('q = """one\ntwo"""\nr = 1', "length") == "q = <string:7>\n\nr = 1"
+    assert redact_code('x = "a"', "off") == 'x = "a"'
+
+
+def test_structures_are_copied():
+    method = StructureNode(type="method", name="connect", start_line=2, end_line=3,
+                           signature='(self, dsn="postgres://admin:pw@db")',
+                           decorators=['@route("/admin")'],
+                           code_skeleton=['return open("secret.key")'])
+    cls = StructureNode(type="class", name="Db", start_line=1, end_line=3, children=[method])
+
+    redacted = redact_structures([cls], "length", "db.py")[0].children[0]
+    assert redacted.signature == "(self, dsn=<string:22>)"
+    assert redacted.decorators == ["@route(<string:6>)"]
+    assert redacted.code_skeleton == ["return open(<string:10>)"]
+    assert method.signature == '(self, dsn="postgres://admin:pw@db")'
+
+
+def test_scan_tools(tmp_path):
+    source = 'API_KEY = "sk-live-123"\n\n\ndef connect(dsn="postgres://admin@db"):\n    return dsn\n'
+    (tmp_path / "db.py").write_text(source)
+
+    text = scan_file.fn(str(tmp_path / "db.py"), redact_strings="length", delta=False)[0].text
+    assert "postgres://" not in text
+    assert "<string:19>" in text
+    focus = scan_file.fn(str(tmp_path / "db.py"), focus="connect", redact_strings="hash")[0].text
+    assert "admin@db" not in focus and "<string:#" in focus
+
+    data = json.loads(scan_file_content.fn(source, "db.py", redact_strings="length",
+                                           output_format="json")[0].text)
+    assert "postgres://" not in json.dumps(data)
+    assert scan_file_content.fn(source, "db.py", redact_strings="mask")[0].text.startswith("Error")
+
+    (tmp_path / ".scannerrc").write_text('redact_strings = "length"\n')
+    assert "postgres://" not in scan_file.fn(str(tmp_path / "db.py"), delta=False)[0].text
+    assert "postgres://" in scan_file.fn(str(tmp_path / "db.py"), redact_strings="off",
+                                         delta=False)[0].text
+
+
+def test_go_values(tmp_path):
+    (tmp_path / "conf.go").write_text('package conf\n\nconst Token = "sk-live-123"\n\n'
+                                      'func dial() string { return "db.internal:5432" }\n')
+
+    data = json.loads(extract_strings.fn(str(tmp_path), pattern="internal", redact_strings="length",
+                                         output_format="json")[0].text)
+    assert [(d["line"], d["value"]) for d in data] == [(5, "<string:16>")]
+    constants = list_constants.fn(str(tmp_path), redact_strings="length")[0].text
+    assert "sk-live" not in constants and "- @3 Token = <string:11>" in constants
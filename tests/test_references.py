@@ -0,0 +1,65 @@
+"""Tests for references: clickable path:line:col and vscode:// refs."""
+
+import json
+from pathlib import Path
+
+import pytest
+
+from scantool.references import add_references, check_reference_format, reference
+from scantool.server import list_all_symbols, scan_directory, scan_file
+
+
+def test_plain_and_vscode_forms(tmp_path):
+    path = tmp_path / "my dir" / "users.go"
+    assert reference("src/users.go", 42, 6, "plain") == "src/users.go:42:6"
+    assert reference("src/users.go", 42, None, "plain") == "src/users.go:42:1"
+    uri = reference("users.go", 3, 2, "vscode", str(path))
+    assert uri == f"vscode://file{path.resolve().as_posix().replace(' ', '%20')}:3:2"
+    assert reference("x.go", 1, 1, "none") is None
+
+
+def test_vscode_resolves_relative_paths(tmp_path, monkeypatch):
+    monkeypatch.chdir(tmp_path)
+    uri = reference("pkg/users.go", 7, 1, "vscode")
+    assert uri.startswith("vscode://file/") and uri.endswith("/pkg/users.go:7:1")
+    assert Path(uri[len("vscode://file"):].rsplit(":", 2)[0]).is_absolute()
+
+
+def test_unknown_format():
+    with pytest.raises(ValueError, match="reference_format"):
+        check_reference_format("emacs")
+
+
+def test_add_references_nodes_and_parse_errors():
+    data = {"file": "a.go", "structures": [
+        {"type": "file-info", "name": "a.go", "start_line": 1, "end_line": 1},
+        {"type": "struct", "name": "S", "start_line": 3, "end_line": 5, "start_utf16_column": 0,
+         "children": [{"type": "method", "name": "M", "start_line": 4, "end_line": 4,
+                       "start_utf16_column": 4}]}],
+        "parse_errors": [{"line": 9, "column": 3, "message": "invalid syntax"}]}
+    add_references(data, "plain")
+    assert "ref" not in data["structures"][0]
+    assert data["structures"][1]["ref"] == "a.go:3:1"
+    assert data["structures"][1]["children"][0]["ref"] == "a.go:4:5"
+    assert data["parse_errors"][0]["ref"] == "a.go:9:3"
+
+
+def test_tools(tmp_path):
+    (tmp_path / "users.go").write_text("package users\n\nfunc Get() {}\n\ntype S struct{}\n")
+    path = str(tmp_path / "users.go")
+
+    data = json.loads(scan_file.fn(path, output_format="json", reference_format="plain")[0].text)
+    refs = [n["ref"] for n in data["structures"] if n["type"] != "file-info"]
+    assert refs == [f"{path}:3:1", f"{path}:5:1"]
+
+    listing = json.loads(scan_directory.fn(str(tmp_path), output_format="json", delta=False,
+                                           path_style="relative", verbosity="names",
+                                           reference_format="vscode")[0].text)
+    node = next(n for n in listing["users.go"]["structures"] if n["name"] == "Get")
+    assert node["ref"] == reference(path, 3, 1, "vscode")
+
+    symbols = json.loads(list_all_symbols.fn(str(tmp_path), output_format="json",
+                                             reference_format="plain")[0].text)
+    assert symbols[0]["ref"] == f"{symbols[0]['file']}:3:1"
+    assert scan_file.fn(path, output_format="json",
+                        reference_format="emacs")[0].text.startswith("Error")
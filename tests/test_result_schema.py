@@ -0,0 +1,123 @@
+"""Tests for result_schema: real JSON output validates against the published
+schema, and a field the schema doesn't list fails validation."""
+
+import json
+from pathlib import Path
+
+from scantool.result_schema import result_schema
+from scantool.server import scan_directory, scan_file
+
+TESTS_DIR = Path(__file__).parent
+
+
+def schema_errors(instance, schema, root, path="$"):
+    """Minimal draft 2020-12 validator for the keywords the schema uses
+    ($ref, anyOf, type incl. type lists, required, properties,
+    additionalProperties, items, minimum) — enough to keep the schema honest without a dependency."""
+    if "$ref" in schema:
+        target = root
+        for part in schema["$ref"].lstrip("#/").split("/"):
+            target = target[part]
+        return schema_errors(instance, target, root, path)
+    if "anyOf" in schema:
+        branches = [schema_errors(instance, s, root, path) for s in schema["anyOf"]]
+        return [] if any(not b for b in branches) else min(branches, key=len)
+
+    errors = []
+    expected = schema.get("type")
+    python_types = {"object": dict, "array": list, "string": str, "integer": int,
+                    "boolean": bool, "null": type(None)}
+
+    def is_type(name: str) -> bool:
+        return isinstance(instance, python_types[name]) and not (
+            name == "integer" and isinstance(instance, bool))
+
+    if expected and not any(is_type(name) for name in
+                            (expected if isinstance(expected, list) else [expected])):
+        return [f"{path}: expected {expected}, got {type(instance).__name__}"]
+    if "minimum" in schema and instance < schema["minimum"]:
+        errors.append(f"{path}: {instance} < {schema['minimum']}")
+    if isinstance(instance, dict):
+        for key in schema.get("required", []):
+            if key not in instance:
+                errors.append(f"{path}: missing {key}")
+        properties = schema.get("properties", {})
+        for key, value in instance.items():
+            if key in properties:
+                errors += schema_errors(value, properties[key], root, f"{path}.{key}")
+            elif schema.get("additionalProperties") is False:
+                errors.append(f"{path}: unexpected property {key}")
+            elif isinstance(schema.get("additionalProperties"), dict):
+                errors += schema_errors(value, schema["additionalProperties"], root,
+                                        f"{path}.{key}")
+    if isinstance(instance, list) and "items" in schema:
+        for i, item in enumerate(instance):
+            errors += schema_errors(item, schema["items"], root, f"{path}[{i}]")
+    return errors
+
+
+def validate(instance):
+    schema = result_schema()
+    return schema_errors(instance, schema, schema)
+
+
+class TestResultSchema:
+    def test_go_file_result_validates(self):
+        path = TESTS_DIR / "go" / "samples" / "basic.go"
+        data = json.loads(scan_file.fn(str(path), output_format="json", delta=False)[0].text)
+
+        assert data["structures"]
+        assert validate(data) == []
+
+    def test_positions_validate(self):
+        path = TESTS_DIR / "go" / "samples" / "basic.go"
+        data = json.loads(scan_file.fn(str(path), positions=True, output_format="json",
+                                       delta=False)[0].text)
+
+        assert any("positions" in node for node in data["structures"])
+        assert validate(data) == []
+
+    def test_python_file_result_validates(self):
+        path = TESTS_DIR / "python" / "samples" / "basic.py"
+        data = json.loads(scan_file.fn(str(path), output_format="json", delta=False)[0].text)
+
+        assert validate(data) == []
+
+    def test_directory_result_validates(self):
+        text = scan_directory.fn(str(TESTS_DIR / "golden" / "fixture_dir"),
+                                 output_format="json", delta=False)[0].text
+
+        data = json.loads(text[text.index("{"):])
+        assert data
+        assert validate(data) == []
+
+    def test_directory_page_validates(self):
+        text = scan_directory.fn(str(TESTS_DIR / "golden" / "fixture_dir"),
+                                 output_format="json", delta=False, limit=2)[0].text
+
+        data = json.loads(text[text.index("{"):])
+        assert len(data["files"]) == 2 and data["next_cursor"]
+        assert validate(data) == []
+
+    def test_package_groups_validate(self):
+        text = scan_directory.fn(str(TESTS_DIR / "golden" / "fixture_dir"), output_format="json",
+                                 delta=False, group_by_package=True)[0].text
+
+        data = json.loads(text[text.index("{"):])
+        assert data["packages"]
+        assert validate(data) == []
+
+    def test_parse_errors_validate(self, tmp_path):
+        path = tmp_path / "broken.go"
+        path.write_text("package a\n\n) ) )\n\nfunc F() {}\n")
+        data = json.loads(scan_file.fn(str(path), output_format="json", delta=False)[0].text)
+
+        assert data["parse_errors"]
+        assert validate(data) == []
+
+    def test_unlisted_field_is_rejected(self):
+        data = {"file": "a.go", "structures": [
+            {"type": "function", "name": "f", "start_line": 1, "end_line": 2,
+             "surprise": True}]}
+
+        assert any("surprise" in e for e in validate(data))
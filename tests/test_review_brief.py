@@ -0,0 +1,111 @@
+"""Tests for review_brief: findings gathered from a scan and written into
+the review_brief prompt message."""
+
+import pytest
+
+from scantool import server
+from scantool.golang.comments import CommentMarker
+from scantool.golang.undocumented import UndocumentedSymbol
+from scantool.hotspots import Hotspot
+from scantool.languages import StructureNode
+from scantool.review_brief import (
+    ReviewBrief,
+    build_review_brief,
+    complex_functions,
+    format_review_brief,
+)
+
+
+def function(name, line, cyclomatic, node_type="function", **kwargs):
+    return StructureNode(type=node_type, name=name, start_line=line, end_line=line + 5,
+                         complexity={"cyclomatic": cyclomatic}, **kwargs)
+
+
+def results(root):
+    info = StructureNode(type="file-info", name="a.go", start_line=1, end_line=1,
+                         file_metadata={"lines": {"code": 40}})
+    holder = StructureNode(type="class", name="Holder", start_line=20, end_line=40,
+                           children=[function("run", 21, 14, "method")])
+    return {
+        str(root / "a.go"): [info, function("Parse", 3, 12),
+                             function("Get", 10, 3, "method", receiver_type="Store")],
+        str(root / "b.py"): [holder, function("tiny", 50, 1)],
+    }
+
+
+class TestComplexFunctions:
+    def test_threshold_and_order(self, tmp_path):
+        found = [(f.name, f.complexity) for f in complex_functions(results(tmp_path), 10)]
+
+        assert found == [("Holder.run", 14), ("Parse", 12)]
+
+    def test_receiver_qualifies_methods(self, tmp_path):
+        names = [f.name for f in complex_functions(results(tmp_path), 3)]
+
+        assert "Store.Get" in names
+
+
+class TestFormat:
+    def brief(self, tmp_path, **overrides):
+        fields = dict(
+            directory=str(tmp_path), files=2, min_complexity=10, go_files=1,
+            hotspots=[Hotspot(file=str(tmp_path / "a.go"), score=80.5, max_complexity=12,
+                              max_function="Parse", todos=1, lines=40)],
+            complex_functions=complex_functions(results(tmp_path), 10),
+            undocumented=[UndocumentedSymbol("Parse", "function", str(tmp_path / "a.go"), 3)],
+            markers=[CommentMarker(str(tmp_path / "a.go"), 7, "TODO", "retry", "marius")])
+        fields.update(overrides)
+        return ReviewBrief(**fields)
+
+    def test_findings_with_relative_locations(self, tmp_path):
+        text = format_review_brief(self.brief(tmp_path))
+
+        assert text.startswith(f"Review the code in {tmp_path} (2 code files scanned).")
+        assert "1. a.go — score 80.5: complexity max 12 (Parse), 1 TODOs, 40 lines" in text
+        assert "- b.py:21 Holder.run — complexity 14" in text
+        assert "- a.go:3 function Parse" in text
+        assert "- a.go:7 TODO(marius): retry" in text
+        assert "## What to deliver" in text
+
+    def test_sections_cut_at_top(self, tmp_path):
+        text = format_review_brief(self.brief(tmp_path), top=1)
+
+        assert "## Functions with cyclomatic complexity of 10 or more (2)" in text
+        assert "- b.py:21 Holder.run — complexity 14\n- +1 more" in text
+        assert "Parse — complexity 12" not in text
+
+    def test_empty_sections_say_so(self, tmp_path):
+        text = format_review_brief(self.brief(tmp_path, hotspots=[], complex_functions=[],
+                                              undocumented=[], markers=[], go_files=0))
+
+        assert text.count("- None found.") == 2
+        assert text.count("- No Go files in this directory.") == 2
+
+
+class TestBuild:
+    def test_gathers_all_sections(self, tmp_path):
+        brief = build_review_brief(str(tmp_path), results(tmp_path), [], min_complexity=12)
+
+        assert brief.files == 2
+        assert [f.name for f in brief.complex_functions] == ["Holder.run", "Parse"]
+        assert brief.undocumented == [] and brief.markers == []
+
+    def test_invalid_threshold(self, tmp_path):
+        with pytest.raises(ValueError, match="min_complexity"):
+            build_review_brief(str(tmp_path), {}, [], min_complexity=0)
+
+
+class TestPrompt:
+    def test_prompt_message(self, tmp_path):
+        (tmp_path / "notes.txt").write_text("Notes\n=====\n")
+
+        text = server.review_brief.fn(str(tmp_path), min_complexity=5, top=3)
+
+        assert text.startswith(f"Review the code in {tmp_path}")
+        assert "- No Go files in this directory." in text
+
+    def test_missing_directory_is_an_error_message(self, tmp_path):
+        text = server.review_brief.fn(str(tmp_path / "missing"))
+
+        assert text == f"Error: Directory not found: {tmp_path / 'missing'}"
+
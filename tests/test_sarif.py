@@ -0,0 +1,58 @@
+"""Tests for findings + sarif: scan results become SARIF 2.1.0 with rules
+referenced by id and index, and 1-based regions."""
+
+from scantool.findings import RULES, collect_findings
+from scantool.sarif import export_sarif
+from scantool.scanner import FileScanner
+
+BRANCHY_GO = "package calc\n\nfunc Branchy(n int) int {\n" + "".join(
+    f"\tif n == {i} {{\n\t\treturn {i}\n\t}}\n" for i in range(5)) + "\treturn n\n}\n"
+
+
+def scan_tree(tmp_path):
+    (tmp_path / "calc.go").write_text(BRANCHY_GO)
+    (tmp_path / "ok.py").write_text("def fine():\n    return 1\n")
+    return FileScanner().scan_directory(str(tmp_path))
+
+
+class TestCollectFindings:
+    def test_complexity_above_threshold(self, tmp_path):
+        findings = collect_findings(scan_tree(tmp_path), complexity_threshold=5)
+
+        assert [(f.rule_id, f.symbol, f.start_line) for f in findings] == \
+            [("complexity/cyclomatic", "Branchy", 3)]
+        assert "complexity 6" in findings[0].message
+
+    def test_nothing_below_threshold(self, tmp_path):
+        assert collect_findings(scan_tree(tmp_path)) == []
+
+
+class TestExportSarif:
+    def test_rules_and_results_are_wired(self, tmp_path):
+        findings = collect_findings(scan_tree(tmp_path), complexity_threshold=5)
+
+        log = export_sarif(findings, str(tmp_path))
+
+        assert log["version"] == "2.1.0"
+        run = log["runs"][0]
+        rules = run["tool"]["driver"]["rules"]
+        result = run["results"][0]
+        assert rules[result["ruleIndex"]]["id"] == result["ruleId"] == "complexity/cyclomatic"
+        assert result["level"] == RULES["complexity/cyclomatic"].level
+
+    def test_region_is_one_based_and_relative(self, tmp_path):
+        findings = collect_findings(scan_tree(tmp_path), complexity_threshold=5)
+
+        location = export_sarif(findings, str(tmp_path))["runs"][0]["results"][0]["locations"][0]
+        physical = location["physicalLocation"]
+
+        assert physical["artifactLocation"] == {"uri": "calc.go", "uriBaseId": "%SRCROOT%"}
+        assert physical["region"]["startLine"] == 3
+        assert physical["region"]["startColumn"] == 1
+        assert physical["region"]["endLine"] == BRANCHY_GO.count("\n")
+
+    def test_empty_log_is_valid_shape(self):
+        log = export_sarif([])
+
+        assert log["runs"][0]["results"] == []
+        assert log["runs"][0]["tool"]["driver"]["rules"] == []
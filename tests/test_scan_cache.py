@@ -0,0 +1,108 @@
+"""Tests for scan_cache: directory scans re-parse only files whose cache key
+changed, and a cache hit is indistinguishable from a fresh scan."""
+
+import os
+
+from scantool.scan_cache import LRUScanCache, ScanCache, scan_cache_key
+from scantool.scanner import FileScanner
+
+
+def write_repo(root):
+    (root / "a.py").write_text("def a():\n    return 1\n")
+    (root / "b.py").write_text("def b():\n    return 2\n")
+
+
+def counting_scanner(monkeypatch):
+    """FileScanner whose scan_file calls are recorded by file name."""
+    scanner = FileScanner()
+    parsed = []
+    original = scanner.scan_file
+
+    def scan_file(file_path, *args, **kwargs):
+        parsed.append(os.path.basename(file_path))
+        return original(file_path, *args, **kwargs)
+
+    monkeypatch.setattr(scanner, "scan_file", scan_file)
+    return scanner, parsed
+
+
+class TestDirectoryCache:
+    def test_unchanged_files_are_not_reparsed(self, tmp_path, monkeypatch):
+        write_repo(tmp_path)
+        scanner, parsed = counting_scanner(monkeypatch)
+        cache = LRUScanCache()
+
+        first = scanner.scan_directory(str(tmp_path), cache=cache)
+        parsed.clear()
+        second = scanner.scan_directory(str(tmp_path), cache=cache)
+
+        assert parsed == []
+        assert {p: [repr(n) for n in s] for p, s in first.items()} == \
+            {p: [repr(n) for n in s] for p, s in second.items()}
+
+    def test_only_edited_file_is_reparsed(self, tmp_path, monkeypatch):
+        write_repo(tmp_path)
+        scanner, parsed = counting_scanner(monkeypatch)
+        cache = LRUScanCache()
+        scanner.scan_directory(str(tmp_path), cache=cache)
+        parsed.clear()
+
+        (tmp_path / "b.py").write_text("def b():\n    return 2\n\n\ndef c():\n    pass\n")
+        results = scanner.scan_directory(str(tmp_path), cache=cache)
+
+        assert parsed == ["b.py"]
+        b_names = [n.name for n in results[str((tmp_path / "b.py").resolve())]]
+        assert "c" in b_names
+
+    def test_content_hash_catches_mtime_preserving_edit(self, tmp_path, monkeypatch):
+        write_repo(tmp_path)
+        path = tmp_path / "a.py"
+        scanner, parsed = counting_scanner(monkeypatch)
+        plain, hashed = LRUScanCache(), LRUScanCache()
+        scanner.scan_directory(str(tmp_path), cache=plain)
+        scanner.scan_directory(str(tmp_path), cache=hashed, cache_content_hash=True)
+        parsed.clear()
+
+        stat = path.stat()
+        path.write_text("def z():\n    return 1\n")  # same size
+        os.utime(path, ns=(stat.st_atime_ns, stat.st_mtime_ns))
+
+        scanner.scan_directory(str(tmp_path), cache=plain)
+        assert parsed == []
+        scanner.scan_directory(str(tmp_path), cache=hashed, cache_content_hash=True)
+        assert parsed == ["a.py"]
+
+    def test_mutating_results_does_not_poison_cache(self, tmp_path):
+        write_repo(tmp_path)
+        cache = LRUScanCache()
+        scanner = FileScanner()
+
+        first = scanner.scan_directory(str(tmp_path), cache=cache)
+        for structures in first.values():
+            structures[0].file_metadata["churn_90d"] = 99
+            structures.clear()
+        second = scanner.scan_directory(str(tmp_path), cache=cache)
+
+        assert all(s and "churn_90d" not in s[0].file_metadata for s in second.values())
+
+
+class TestLRUScanCache:
+    def test_evicts_least_recently_used(self):
+        cache = LRUScanCache(max_entries=2)
+        cache.put(("a",), [])
+        cache.put(("b",), [])
+        cache.get(("a",))
+        cache.put(("c",), [])
+
+        assert cache.get(("b",)) is None
+        assert cache.get(("a",)) == [] and cache.get(("c",)) == []
+
+    def test_is_a_scan_cache(self):
+        assert isinstance(LRUScanCache(), ScanCache)
+
+    def test_key_tracks_mode_and_missing_files(self, tmp_path):
+        path = tmp_path / "a.py"
+        path.write_text("x = 1\n")
+
+        assert scan_cache_key(str(path), "balanced") != scan_cache_key(str(path), "active")
+        assert scan_cache_key(str(tmp_path / "missing.py")) is None
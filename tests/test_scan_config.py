@@ -0,0 +1,127 @@
+"""Tests for scan_config: loading and validating the project config file,
+and argument > config > built-in precedence in the scan tools."""
+
+import json
+
+import pytest
+
+from scantool.scan_config import ConfigError, ScanConfig, find_config, load_config
+from scantool.server import scan_directory, scan_file
+
+
+def write_config(directory, text, name=".scannerrc"):
+    path = directory / name
+    path.write_text(text)
+    return path
+
+
+class TestLoadConfig:
+    def test_options(self, tmp_path):
+        path = write_config(tmp_path, 'workers = 2\nskip_dirs = ["vendor"]\n'
+                                      'max_file_size = 1_000\nrespect_gitignore = false\n'
+                                      'verbosity = "names"\n')
+
+        assert load_config(str(path)) == ScanConfig(
+            path=str(path.resolve()), workers=2, skip_dirs=("vendor",), max_file_size=1000,
+            respect_gitignore=False, verbosity="names")
+
+    def test_invalid_toml(self, tmp_path):
+        path = write_config(tmp_path, "workers = \n")
+
+        with pytest.raises(ConfigError, match=r"\.scannerrc: invalid TOML"):
+            load_config(str(path))
+
+    def test_unknown_option(self, tmp_path):
+        path = write_config(tmp_path, "concurrency = 4\n")
+
+        with pytest.raises(ConfigError, match="unknown option.*concurrency.*known: workers"):
+            load_config(str(path))
+
+    @pytest.mark.parametrize("line, message", [
+        ("workers = 0", "workers must be an integer of at least 1"),
+        ('skip_dirs = "vendor"', "skip_dirs must be a list of directory names"),
+        ("max_file_size = -1", "max_file_size must be a byte count"),
+        ('respect_gitignore = "yes"', "respect_gitignore must be true or false"),
+        ('verbosity = "all"', "verbosity must be one of names, signatures, full"),
+        ('redact_strings = "mask"', "redact_strings must be one of off, length, hash"),
+    ])
+    def test_wrong_types(self, tmp_path, line, message):
+        path = write_config(tmp_path, line + "\n")
+
+        with pytest.raises(ConfigError, match=message):
+            load_config(str(path))
+
+    def test_missing_file(self, tmp_path):
+        with pytest.raises(FileNotFoundError):
+            load_config(str(tmp_path / ".scannerrc"))
+
+
+class TestFindConfig:
+    def test_nearest_at_or_above(self, tmp_path):
+        write_config(tmp_path, "workers = 3\n", "scanner.toml")
+        (tmp_path / "pkg" / "sub").mkdir(parents=True)
+
+        assert find_config(str(tmp_path / "pkg" / "sub")).workers == 3
+        assert find_config(str(tmp_path / "pkg" / "sub" / "file.go")).workers == 3
+
+    def test_scannerrc_first(self, tmp_path):
+        write_config(tmp_path, "workers = 3\n", "scanner.toml")
+        write_config(tmp_path, "workers = 1\n")
+
+        assert find_config(str(tmp_path)).workers == 1
+
+    def test_none(self, tmp_path):
+        assert find_config(str(tmp_path)) == ScanConfig()
+
+    def test_precedence(self):
+        config = ScanConfig(verbosity="names")
+
+        assert config.apply({"verbosity": "full"}, {"verbosity": "signatures"}) == {
+            "verbosity": "full"}
+        assert config.apply({"verbosity": None}, {"verbosity": "full"}) == {"verbosity": "names"}
+        assert ScanConfig().apply({"verbosity": None}, {"verbosity": "full"}) == {
+            "verbosity": "full"}
+
+
+class TestTools:
+    def test_config_sets_defaults(self, tmp_path):
+        write_config(tmp_path, "max_file_size = 10\nrespect_gitignore = false\n")
+        (tmp_path / ".gitignore").write_text("ignored.txt\n")
+        (tmp_path / "ignored.txt").write_text("small\n")
+        (tmp_path / "notes.txt").write_text("Title\n=====\n\nsome longer body\n")
+
+        data = json.loads(scan_directory.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert sorted(p.rsplit("/", 1)[-1] for p in data) == [
+            ".gitignore", ".scannerrc", "ignored.txt", "notes.txt"]
+        assert data[str((tmp_path / "notes.txt").resolve())]["skipped"] == "too_large"
+
+    def test_arguments_win(self, tmp_path):
+        write_config(tmp_path, "max_file_size = 10\nrespect_gitignore = false\n")
+        (tmp_path / ".gitignore").write_text("ignored.txt\n")
+        (tmp_path / "ignored.txt").write_text("small\n")
+        (tmp_path / "notes.txt").write_text("Title\n=====\n\nsome longer body\n")
+
+        data = json.loads(scan_directory.fn(str(tmp_path), output_format="json",
+                                            max_file_size=0, respect_gitignore=True)[0].text)
+
+        assert "skipped" not in data[str((tmp_path / "notes.txt").resolve())]
+        assert str((tmp_path / "ignored.txt").resolve()) not in data
+
+    def test_scan_file_verbosity(self, tmp_path):
+        write_config(tmp_path, 'verbosity = "names"\n')
+        (tmp_path / "notes.txt").write_text("Title\n=====\n\nbody\n")
+
+        configured = json.loads(scan_file.fn(str(tmp_path / "notes.txt"), output_format="json")[0].text)
+        passed = json.loads(scan_file.fn(str(tmp_path / "notes.txt"), output_format="json",
+                                         verbosity="full")[0].text)
+
+        assert set(configured["structures"][0]) <= {"type", "name", "children"}
+        assert "start_line" in passed["structures"][0]
+
+    def test_malformed_config_is_an_error(self, tmp_path):
+        write_config(tmp_path, "workers = [\n")
+
+        text = scan_directory.fn(str(tmp_path))[0].text
+
+        assert text.startswith("Error: ") and "invalid TOML" in text
@@ -0,0 +1,758 @@
+"""Tests for FileScanner.scan_directory walk options: what gets descended
+into, what gets skipped, and how results are keyed."""
+
+import io
+import json
+import os
+import shutil
+import subprocess
+import threading
+import time
+from datetime import datetime, timedelta, timezone
+from pathlib import Path
+
+import pytest
+
+from scantool.languages import (
+    SKIP_BINARY,
+    SKIP_MAX_DEPTH,
+    SKIP_PANIC,
+    SKIP_PERMISSION,
+    SKIP_TIMED_OUT,
+    SKIP_TOO_LARGE,
+    SkippedFile,
+    is_unsupported_stub,
+    parse_errors,
+    skip_reason,
+    skipped_files,
+)
+from scantool.file_json import file_to_dict
+from scantool.scanner import FileScanner, LimitExceeded, ScanCancelled, SymlinkOutsideRoot
+from scantool import server
+from scantool.server import scan_directory as scan_directory_tool
+
+
+def make_tree(root: Path, files: dict[str, str]) -> None:
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+def scanned_names(results: dict, root: Path) -> set[str]:
+    return {Path(p).relative_to(root.resolve()).as_posix() for p in results}
+
+
+TREE = {
+    "main.go": "package main\n\nfunc main() {}\n",
+    "vendor/lib/lib.go": "package lib\n\nfunc Vendored() {}\n",
+    "node_modules/pkg/index.js": "function dep() {}\n",
+    ".tools/gen.py": "def gen():\n    pass\n",
+}
+
+
+class TestSkipDirs:
+    def test_default_skips_noise_dirs(self, tmp_path):
+        make_tree(tmp_path, TREE)
+
+        names = scanned_names(FileScanner().scan_directory(str(tmp_path)), tmp_path)
+
+        assert names == {"main.go"}
+
+    def test_explicit_list_replaces_defaults(self, tmp_path):
+        make_tree(tmp_path, TREE)
+
+        results = FileScanner().scan_directory(str(tmp_path), skip_dirs=["node_modules"])
+
+        names = scanned_names(results, tmp_path)
+        assert "vendor/lib/lib.go" in names
+        assert ".tools/gen.py" in names
+        assert "node_modules/pkg/index.js" not in names
+
+    def test_git_dir_always_skipped(self, tmp_path):
+        make_tree(tmp_path, {**TREE, ".git/hooks/pre-commit.py": "x = 1\n"})
+
+        results = FileScanner().scan_directory(str(tmp_path), skip_dirs=[])
+
+        assert not any("/.git/" in p for p in results)
+
+    def test_symlink_cycle_terminates(self, tmp_path):
+        make_tree(tmp_path, {"pkg/a.py": "def a():\n    pass\n"})
+        (tmp_path / "pkg" / "loop").symlink_to(tmp_path, target_is_directory=True)
+
+        names = scanned_names(FileScanner().scan_directory(str(tmp_path)), tmp_path)
+
+        assert names == {"pkg/a.py"}
+
+
+def deny_listing(monkeypatch, denied: Path) -> None:
+    """os.walk fails to list `denied` as it would without read permission
+    (chmod can't deny root, who runs some CI containers)."""
+    real_scandir = os.scandir
+
+    def scandir(path="."):
+        if Path(path) == denied:
+            raise PermissionError(13, "Permission denied", str(path))
+        return real_scandir(path)
+
+    monkeypatch.setattr(os, "scandir", scandir)
+
+
+class TestUnreadableEntries:
+    def test_denied_subdirectory_skipped_and_reported(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, {"a.py": "x = 1\n", "secret/b.py": "y = 1\n",
+                             "z/c.py": "z = 1\n"})
+        deny_listing(monkeypatch, tmp_path.resolve() / "secret")
+
+        results = FileScanner().scan_directory(str(tmp_path))
+
+        assert scanned_names(results, tmp_path) == {"a.py", "secret", "z/c.py"}
+        assert skipped_files(results) == [SkippedFile(
+            str(tmp_path.resolve() / "secret"), SKIP_PERMISSION, "Permission denied")]
+
+    def test_denied_root_raises(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, {"a.py": "x = 1\n"})
+        deny_listing(monkeypatch, tmp_path.resolve())
+
+        with pytest.raises(PermissionError):
+            FileScanner().scan_directory(str(tmp_path))
+
+    def test_unreadable_file_is_a_stub(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, {"a.py": "x = 1\n", "b.py": "y = 1\n"})
+        real_scan = FileScanner.scan_file
+
+        def scan_file(self, file_path, *args, **kwargs):
+            if file_path.endswith("a.py"):
+                raise PermissionError(13, "Permission denied", file_path)
+            return real_scan(self, file_path, *args, **kwargs)
+
+        monkeypatch.setattr(FileScanner, "scan_file", scan_file)
+        results = FileScanner().scan_directory(str(tmp_path), workers=1)
+
+        assert [(Path(f.path).name, f.reason) for f in skipped_files(results)] == [
+            ("a.py", SKIP_PERMISSION)]
+        assert skip_reason(results[str(tmp_path.resolve() / "b.py")]) is None
+
+
+class TestFollowSymlinks:
+    def test_off_by_default(self, tmp_path):
+        make_tree(tmp_path, {"root/main.py": "x = 1\n", "shared/lib.py": "y = 1\n"})
+        (tmp_path / "root" / "shared").symlink_to(tmp_path / "shared", target_is_directory=True)
+
+        names = scanned_names(FileScanner().scan_directory(str(tmp_path / "root")),
+                              tmp_path / "root")
+
+        assert names == {"main.py"}
+
+    def test_follows_directory_links_at_link_path(self, tmp_path):
+        make_tree(tmp_path, {"root/main.py": "x = 1\n", "shared/lib.py": "y = 1\n"})
+        (tmp_path / "root" / "shared").symlink_to(tmp_path / "shared", target_is_directory=True)
+
+        results = FileScanner().scan_directory(str(tmp_path / "root"), follow_symlinks=True)
+
+        assert scanned_names(results, tmp_path / "root") == {"main.py", "shared/lib.py"}
+
+    def test_cycles_and_duplicate_targets_visited_once(self, tmp_path):
+        make_tree(tmp_path, {"pkg/a.py": "def a():\n    pass\n"})
+        (tmp_path / "pkg" / "loop").symlink_to(tmp_path, target_is_directory=True)
+        (tmp_path / "alias").symlink_to(tmp_path / "pkg", target_is_directory=True)
+        (tmp_path / "b.py").symlink_to(tmp_path / "pkg" / "a.py")
+
+        results = FileScanner().scan_directory(str(tmp_path), follow_symlinks=True)
+
+        assert scanned_names(results, tmp_path) == {"b.py"}
+
+    def test_confine_to_root(self, tmp_path):
+        make_tree(tmp_path, {"root/main.py": "x = 1\n", "outside/lib.py": "y = 1\n"})
+        (tmp_path / "root" / "ext").symlink_to(tmp_path / "outside", target_is_directory=True)
+
+        with pytest.raises(SymlinkOutsideRoot, match="points outside the scan root"):
+            FileScanner().scan_directory(str(tmp_path / "root"), follow_symlinks=True,
+                                         confine_to_root=True)
+
+    def test_confine_to_root_checks_file_links(self, tmp_path):
+        make_tree(tmp_path, {"root/main.py": "x = 1\n", "outside/lib.py": "y = 1\n"})
+        (tmp_path / "root" / "lib.py").symlink_to(tmp_path / "outside" / "lib.py")
+
+        with pytest.raises(SymlinkOutsideRoot):
+            FileScanner().scan_directory(str(tmp_path / "root"), confine_to_root=True)
+
+
+class TestGitignore:
+    def test_nested_gitignore_scoped_to_its_subtree(self, tmp_path):
+        make_tree(tmp_path, {
+            "gen.py": "def top():\n    pass\n",
+            "pkg/.gitignore": "gen.py\n",
+            "pkg/gen.py": "def generated():\n    pass\n",
+            "pkg/sub/gen.py": "def deeper():\n    pass\n",
+            "pkg/keep.py": "def keep():\n    pass\n",
+        })
+
+        names = scanned_names(FileScanner().scan_directory(str(tmp_path)), tmp_path)
+
+        assert names == {"gen.py", "pkg/keep.py"}
+
+    def test_nested_negation_reincludes_root_ignore(self, tmp_path):
+        make_tree(tmp_path, {
+            ".gitignore": "*_gen.py\n",
+            "a_gen.py": "def a():\n    pass\n",
+            "pkg/.gitignore": "!b_gen.py\n",
+            "pkg/b_gen.py": "def b():\n    pass\n",
+        })
+
+        names = scanned_names(FileScanner().scan_directory(str(tmp_path)), tmp_path)
+
+        assert names == {"pkg/b_gen.py"}
+
+    def test_dir_only_and_anchored_patterns(self, tmp_path):
+        make_tree(tmp_path, {
+            ".gitignore": "out/\n/tmp\n",
+            "out/a.py": "def a():\n    pass\n",
+            "src/out.py": "def b():\n    pass\n",
+            "tmp/c.py": "def c():\n    pass\n",
+            "src/tmp/d.py": "def d():\n    pass\n",
+        })
+
+        names = scanned_names(FileScanner().scan_directory(str(tmp_path)), tmp_path)
+
+        assert names == {"src/out.py", "src/tmp/d.py"}
+
+    def test_respect_gitignore_off(self, tmp_path):
+        make_tree(tmp_path, {
+            "pkg/.gitignore": "*.py\n",
+            "pkg/a.py": "def a():\n    pass\n",
+        })
+
+        results = FileScanner().scan_directory(str(tmp_path), respect_gitignore=False)
+
+        assert scanned_names(results, tmp_path) == {"pkg/a.py"}
+
+
+class TestWorkers:
+    FILES = {f"pkg{i}/mod{j}.py": f"def f{i}_{j}():\n    return {j}\n"
+             for i in range(3) for j in range(4)}
+
+    def test_parallel_matches_sequential_order(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        sequential = FileScanner().scan_directory(str(tmp_path), workers=1)
+        parallel = FileScanner().scan_directory(str(tmp_path), workers=4)
+
+        assert list(parallel) == list(sequential)
+        assert [[repr(n) for n in s] for s in parallel.values()] == \
+            [[repr(n) for n in s] for s in sequential.values()]
+
+    def test_failing_file_does_not_abort_scan(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, self.FILES)
+        scanner = FileScanner()
+        original = scanner.scan_file
+
+        def scan_file(file_path, *args, **kwargs):
+            if file_path.endswith("mod2.py"):
+                raise RuntimeError("boom")
+            return original(file_path, *args, **kwargs)
+
+        monkeypatch.setattr(scanner, "scan_file", scan_file)
+        results = scanner.scan_directory(str(tmp_path), workers=4)
+
+        failed = [p for p, s in results.items() if s[0].type == "error"]
+        assert len(failed) == 3 and all(p.endswith("mod2.py") for p in failed)
+        assert len(results) == len(self.FILES)
+
+
+class TestCancellation:
+    FILES = TestWorkers.FILES
+
+    def test_set_event_stops_before_any_file(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+        cancel = threading.Event()
+        cancel.set()
+
+        with pytest.raises(ScanCancelled) as excinfo:
+            FileScanner().scan_directory(str(tmp_path), cancel=cancel)
+
+        assert excinfo.value.reason == "cancelled"
+        assert excinfo.value.results == {}
+
+    def test_cancel_mid_scan_keeps_finished_files(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, self.FILES)
+        scanner = FileScanner()
+        cancel = threading.Event()
+        original = scanner.scan_file
+
+        def scan_file(file_path, *args, **kwargs):
+            cancel.set()  # first file finishes, the rest are skipped
+            return original(file_path, *args, **kwargs)
+
+        monkeypatch.setattr(scanner, "scan_file", scan_file)
+        with pytest.raises(ScanCancelled) as excinfo:
+            scanner.scan_directory(str(tmp_path), workers=1, cancel=cancel)
+
+        partial = excinfo.value.results
+        assert len(partial) == 1
+        assert all(structures is not None for structures in partial.values())
+
+    def test_timeout_reports_timed_out(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        with pytest.raises(ScanCancelled) as excinfo:
+            FileScanner().scan_directory(str(tmp_path), timeout=-1)
+
+        assert excinfo.value.reason == "timed out"
+
+    def test_generous_timeout_scans_everything(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        results = FileScanner().scan_directory(str(tmp_path), workers=4, timeout=60,
+                                               cancel=threading.Event())
+
+        assert len(results) == len(self.FILES)
+
+
+class TestResourceLimits:
+    FILES = TestWorkers.FILES
+
+    def test_max_files_stops_walk_with_parsed_partial_results(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        with pytest.raises(LimitExceeded) as excinfo:
+            FileScanner().scan_directory(str(tmp_path), max_files=5)
+
+        assert isinstance(excinfo.value, ScanCancelled)
+        assert excinfo.value.limit == "max_files"
+        assert excinfo.value.reason == "stopped at max_files=5"
+        assert len(excinfo.value.results) == 5
+        assert all(structures is not None for structures in excinfo.value.results.values())
+
+    def test_limits_not_hit_scan_everything(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        results = FileScanner().scan_directory(str(tmp_path), max_files=len(self.FILES),
+                                               max_total_bytes=10_000, parse_timeout=30)
+
+        assert len(results) == len(self.FILES)
+
+    def test_max_total_bytes(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+        size = len(self.FILES["pkg0/mod0.py"])
+
+        with pytest.raises(LimitExceeded) as excinfo:
+            FileScanner().scan_directory(str(tmp_path), max_total_bytes=3 * size + 1)
+
+        assert excinfo.value.limit == "max_total_bytes"
+        assert len(excinfo.value.results) == 3
+
+    def test_parse_timeout_names_slow_file(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, self.FILES)
+        scanner = FileScanner()
+        original = scanner.scan_file
+
+        def scan_file(file_path, *args, **kwargs):
+            if file_path.endswith("pkg0/mod1.py"):
+                time.sleep(1)
+            return original(file_path, *args, **kwargs)
+
+        monkeypatch.setattr(scanner, "scan_file", scan_file)
+        with pytest.raises(LimitExceeded) as excinfo:
+            scanner.scan_directory(str(tmp_path), workers=1, parse_timeout=0.2)
+
+        assert excinfo.value.limit == "parse_timeout"
+        assert excinfo.value.file.endswith("pkg0/mod1.py")
+        assert scanned_names(excinfo.value.results, tmp_path) == {"pkg0/mod0.py"}
+
+    def test_file_timeout_skips_slow_file_and_goes_on(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, self.FILES)
+        scanner = FileScanner()
+        original = scanner.scan_file
+
+        def scan_file(file_path, *args, **kwargs):
+            if file_path.endswith("pkg0/mod1.py"):
+                time.sleep(1)
+            return original(file_path, *args, **kwargs)
+
+        monkeypatch.setattr(scanner, "scan_file", scan_file)
+        results = scanner.scan_directory(str(tmp_path), workers=2, file_timeout=0.2)
+
+        assert len(results) == len(self.FILES)
+        slow = str(tmp_path / "pkg0/mod1.py")
+        assert [(Path(f.path).name, f.reason) for f in skipped_files(results)] == [
+            ("mod1.py", SKIP_TIMED_OUT)]
+        assert parse_errors(slow, results[slow])[0].to_dict() == {
+            "line": 1, "message": "Parse timed out after 0.2s", "reason": "timed_out"}
+
+
+class TestServerLimits:
+    FILES = TestWorkers.FILES
+
+    def test_analysis_tools_answer_with_the_limit_to_raise(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, self.FILES)
+        monkeypatch.setitem(server._SCAN_LIMITS, "max_files", ("SCANTOOL_MAX_SCAN_FILES", 5))
+
+        text = server.count_symbols.fn(str(tmp_path))[0].text
+
+        assert text.startswith("Error counting symbols: scan stopped at max_files=5")
+        assert "$SCANTOOL_MAX_SCAN_FILES (server limit)" in text
+
+    def test_go_tools_load_under_the_limits(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, {f"g{i}.go": f"package p\n\nvar V{i} = {i}\n" for i in range(4)})
+        monkeypatch.setitem(server._SCAN_LIMITS, "max_total_bytes",
+                            ("SCANTOOL_MAX_SCAN_BYTES", 40))
+
+        text = server.list_globals.fn(str(tmp_path))[0].text
+
+        assert text.startswith("Error listing globals: Go file load stopped at max_total_bytes=40")
+        assert "$SCANTOOL_MAX_SCAN_BYTES (server limit)" in text
+
+
+requires_git = pytest.mark.skipif(shutil.which("git") is None, reason="git not installed")
+
+
+def _git(cwd, *args):
+    subprocess.run(["git", "-c", "user.name=test", "-c", "user.email=test@test", *args],
+                   cwd=cwd, check=True, capture_output=True)
+
+
+@requires_git
+class TestGitDiffBase:
+    def make_repo(self, root: Path):
+        make_tree(root, {"keep.go": "package a\n", "edit.go": "package a\n",
+                         "old.go": "package a\n", "gone.go": "package a\n"})
+        _git(root, "init", "-q")
+        _git(root, "add", ".")
+        _git(root, "commit", "-qm", "base")
+        (root / "edit.go").write_text("package a\n\nfunc F() {}\n")
+        _git(root, "mv", "old.go", "new.go")
+        _git(root, "rm", "-q", "gone.go")
+        (root / "added.go").write_text("package a\n")
+
+    def test_only_changed_files_scanned(self, tmp_path):
+        self.make_repo(tmp_path)
+
+        results = FileScanner().scan_directory(str(tmp_path), "**/*.go", git_diff_base="HEAD")
+
+        assert sorted(Path(p).name for p in results) == ["added.go", "edit.go", "new.go"]
+
+    def test_not_a_repo_is_an_error(self, tmp_path):
+        make_tree(tmp_path, {"a.go": "package a\n"})
+
+        with pytest.raises(ValueError, match="not in a git repository"):
+            FileScanner().scan_directory(str(tmp_path), git_diff_base="HEAD")
+
+    def test_unknown_ref_is_an_error(self, tmp_path):
+        self.make_repo(tmp_path)
+
+        with pytest.raises(ValueError, match="Unknown git ref"):
+            FileScanner().scan_directory(str(tmp_path), git_diff_base="no-such-ref")
+
+
+class TestMaxFileSize:
+    def test_large_file_listed_as_skipped_stub(self, tmp_path):
+        make_tree(tmp_path, {"small.py": "x = 1\n", "big.py": "x = 1\n" * 100})
+
+        results = FileScanner().scan_directory(str(tmp_path), max_file_size=100)
+
+        big = results[str(tmp_path / "big.py")]
+        assert is_unsupported_stub(big)
+        assert big[0].file_metadata["skipped"] == SKIP_TOO_LARGE
+        assert skip_reason(results[str(tmp_path / "small.py")]) is None
+
+    def test_skipped_files_collects_every_reason(self, tmp_path, monkeypatch):
+        make_tree(tmp_path, {"big.py": "x = 1\n" * 100, "bad.py": "y = 2\n", "ok.py": "z = 3\n"})
+        scanner = FileScanner()
+        original = scanner.scan_file
+
+        def scan_file(file_path, *args, **kwargs):
+            if file_path.endswith("bad.py"):
+                raise RuntimeError("boom")
+            return original(file_path, *args, **kwargs)
+
+        monkeypatch.setattr(scanner, "scan_file", scan_file)
+        results = scanner.scan_directory(str(tmp_path), max_file_size=100, workers=1)
+
+        assert [(Path(f.path).name, f.reason) for f in skipped_files(results)] == [
+            ("bad.py", SKIP_PANIC), ("big.py", SKIP_TOO_LARGE)]
+
+    def test_no_limit(self, tmp_path):
+        make_tree(tmp_path, {"big.py": "x = 1\n" * 100})
+
+        results = FileScanner().scan_directory(str(tmp_path), max_file_size=None)
+
+        assert skipped_files(results) == []
+
+
+class TestParseErrors:
+    def test_syntax_errors_positioned(self, tmp_path):
+        make_tree(tmp_path, {"broken.go": "package a\n\nfunc F() {}\n\n) ) )\n",
+                             "ok.go": "package a\n"})
+        results = FileScanner().scan_directory(str(tmp_path))
+
+        errors = parse_errors(str(tmp_path / "broken.go"), results[str(tmp_path / "broken.go")])
+
+        assert errors and all(e.message == "invalid syntax" for e in errors)
+        assert errors[0].line >= 5 and errors[0].column >= 1
+        assert parse_errors(str(tmp_path / "ok.go"), results[str(tmp_path / "ok.go")]) == []
+
+    def test_scan_failure_is_one_unpositioned_error(self, tmp_path, monkeypatch, caplog):
+        make_tree(tmp_path, {"bad.py": "y = 2\n"})
+        scanner = FileScanner()
+
+        def scan_file(file_path, *args, **kwargs):
+            raise RuntimeError("boom")
+
+        monkeypatch.setattr(scanner, "scan_file", scan_file)
+        path = str(tmp_path / "bad.py")
+        errors = parse_errors(path, scanner.scan_directory(str(tmp_path), workers=1)[path])
+
+        assert [(e.line, e.column, e.message) for e in errors] == [(1, None, "Failed to scan: boom")]
+        assert errors[0].to_dict() == {"line": 1, "message": "Failed to scan: boom",
+                                       "reason": "panic"}
+        [record] = [r for r in caplog.records if r.name == "scantool.scanner"]
+        assert path in record.getMessage()
+        assert record.exc_info[0] is RuntimeError
+
+
+class TestBinaryDetection:
+    def test_nul_byte_file_skipped_as_binary(self, tmp_path):
+        (tmp_path / "blob.c").write_bytes(b"\x7fELF\x02\x01\x00\x00garbage")
+        make_tree(tmp_path, {"real.c": "int main(void) { return 0; }\n"})
+
+        results = FileScanner().scan_directory(str(tmp_path))
+
+        assert skip_reason(results[str(tmp_path / "blob.c")]) == SKIP_BINARY
+        assert skip_reason(results[str(tmp_path / "real.c")]) is None
+
+    def test_utf16_with_bom_is_text(self, tmp_path):
+        (tmp_path / "wide.py").write_bytes("x = 1\n".encode("utf-16"))
+
+        results = FileScanner().scan_directory(str(tmp_path))
+
+        assert skip_reason(results[str(tmp_path / "wide.py")]) != SKIP_BINARY
+
+
+class TestExcludeGenerated:
+    FILES = {
+        "api.pb.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\nfunc X() {}\n",
+        "api.go": "package api\n\nfunc Y() {}\n",
+    }
+
+    def test_generated_files_flagged_by_default(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        results = FileScanner().scan_directory(str(tmp_path))
+
+        assert results[str(tmp_path / "api.pb.go")][0].file_metadata["generated"] is True
+        assert "generated" not in results[str(tmp_path / "api.go")][0].file_metadata
+
+    def test_exclude_generated_drops_them(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        for workers in (1, 2):
+            results = FileScanner().scan_directory(str(tmp_path), exclude_generated=True,
+                                                   workers=workers)
+            assert scanned_names(results, tmp_path) == {"api.go"}
+
+
+class TestBuildTags:
+    FILES = {
+        "poll_linux.go": "//go:build linux && !386\n\npackage poll\n\nfunc Wait() {}\n",
+        "poll_bsd.go": "// +build darwin freebsd\n\npackage poll\n\nfunc Wait() {}\n",
+        "poll.go": "package poll\n\nfunc New() {}\n",
+        "gen.go": "//go:build ignore\n\npackage main\n",
+    }
+
+    def test_constraint_reported_without_filtering(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        results = FileScanner().scan_directory(str(tmp_path))
+
+        meta = {Path(p).name: s[0].file_metadata for p, s in results.items()}
+        assert meta["poll_linux.go"]["build_constraint"] == "linux && !386"
+        assert meta["poll_bsd.go"]["build_constraint"] == "darwin || freebsd"
+        assert "build_constraint" not in meta["poll.go"]
+
+    def test_build_tags_drop_unsatisfied(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+
+        for tags, expected in (
+                (["linux", "amd64"], {"poll_linux.go", "poll.go"}),
+                (["darwin", "arm64"], {"poll_bsd.go", "poll.go"}),
+                ([], {"poll.go"})):
+            results = FileScanner().scan_directory(str(tmp_path), build_tags=tags)
+            assert scanned_names(results, tmp_path) == expected
+
+
+class TestIncludeExclude:
+    FILES = {
+        "main.go": "package main\n",
+        "main_test.go": "package main\n",
+        "internal/db/db.go": "package db\n",
+        "internal/db/db_test.go": "package db\n",
+        "cmd/tool/tool.go": "package main\n",
+    }
+
+    def scan(self, tmp_path, **kwargs):
+        make_tree(tmp_path, self.FILES)
+        return scanned_names(FileScanner().scan_directory(str(tmp_path), **kwargs), tmp_path)
+
+    def test_include_doublestar(self, tmp_path):
+        assert self.scan(tmp_path, include=["**/*_test.go"]) == {
+            "main_test.go", "internal/db/db_test.go"}
+
+    def test_exclude_subtree(self, tmp_path):
+        assert self.scan(tmp_path, exclude=["internal/**"]) == {
+            "main.go", "main_test.go", "cmd/tool/tool.go"}
+
+    def test_exclude_wins_over_include(self, tmp_path):
+        assert self.scan(tmp_path, include=["**/*_test.go"], exclude=["internal/**"]) == {
+            "main_test.go"}
+
+    def test_star_does_not_cross_directories(self, tmp_path):
+        assert self.scan(tmp_path, include=["*.go"]) == {"main.go", "main_test.go"}
+
+    def test_composes_with_gitignore(self, tmp_path):
+        (tmp_path / ".gitignore").write_text("cmd/\n")
+
+        assert self.scan(tmp_path, exclude=["**/*_test.go"]) == {
+            "main.go", "internal/db/db.go"}
+
+
+class TestScanignore:
+    FILES = TestIncludeExclude.FILES
+
+    def scan(self, tmp_path, scanignore, **kwargs):
+        make_tree(tmp_path, self.FILES)
+        (tmp_path / ".scanignore").write_text(scanignore)
+        return scanned_names(FileScanner().scan_directory(str(tmp_path), **kwargs), tmp_path)
+
+    def test_patterns_and_comments(self, tmp_path):
+        scanignore = "# generated and tests\n\n**/*_test.go\ncmd/\n"
+
+        assert self.scan(tmp_path, scanignore) == {"main.go", "internal/db/db.go"}
+
+    def test_composes_with_tool_excludes(self, tmp_path):
+        assert self.scan(tmp_path, "cmd/**\n", exclude=["internal/**"]) == {
+            "main.go", "main_test.go"}
+
+    def test_only_read_at_scan_root(self, tmp_path):
+        (tmp_path / "internal").mkdir()
+        (tmp_path / "internal" / ".scanignore").write_text("db/**\n")
+
+        assert "internal/db/db.go" in self.scan(tmp_path, "")
+
+
+class TestMaxDepth:
+    FILES = TestIncludeExclude.FILES
+
+    def scan(self, tmp_path, **kwargs):
+        make_tree(tmp_path, self.FILES)
+        return FileScanner().scan_directory(str(tmp_path), **kwargs)
+
+    def test_depth_levels(self, tmp_path):
+        assert scanned_names(self.scan(tmp_path, max_depth=0), tmp_path) == {
+            "main.go", "main_test.go"}
+        assert scanned_names(self.scan(tmp_path, max_depth=1), tmp_path) == {
+            "main.go", "main_test.go"}
+        assert scanned_names(self.scan(tmp_path, max_depth=2), tmp_path) == set(self.FILES)
+
+    def test_deeper_directories_listed_on_request(self, tmp_path):
+        results = self.scan(tmp_path, max_depth=1, report_depth_limit=True)
+
+        assert [f.path for f in skipped_files(results) if f.reason == SKIP_MAX_DEPTH] == [
+            str(tmp_path.resolve() / "cmd" / "tool"),
+            str(tmp_path.resolve() / "internal" / "db")]
+        assert scanned_names(self.scan(tmp_path, max_depth=1), tmp_path) == {
+            "main.go", "main_test.go"}  # silent without report_depth_limit
+
+    def test_negative_depth(self, tmp_path):
+        with pytest.raises(ValueError, match="max_depth"):
+            self.scan(tmp_path, max_depth=-1)
+
+
+class TestModifiedSince:
+    FILES = {"old.go": "package a\n", "new.go": "package a\n", "notes.txt": "old\n",
+             "old/deep/fresh.go": "package deep\n", "old/deep/stale.go": "package deep\n"}
+    CUTOFF = datetime(2024, 5, 1, 12, 0, tzinfo=timezone.utc)
+
+    def make(self, root: Path):
+        make_tree(root, self.FILES)
+        before = (self.CUTOFF - timedelta(days=30)).timestamp()
+        after = (self.CUTOFF + timedelta(hours=1)).timestamp()
+        for name in ("old.go", "notes.txt", "old/deep/stale.go", "old/deep", "old"):
+            os.utime(root / name, (before, before))
+        for name in ("new.go", "old/deep/fresh.go"):
+            os.utime(root / name, (after, after))
+
+    def test_only_files_after_the_cutoff(self, tmp_path):
+        self.make(tmp_path)
+
+        results = FileScanner().scan_directory(str(tmp_path), modified_since=self.CUTOFF)
+
+        # Old directories are still walked; old files leave no stub
+        assert scanned_names(results, tmp_path) == {"new.go", "old/deep/fresh.go"}
+
+    def test_older_files_do_not_count_toward_max_files(self, tmp_path):
+        self.make(tmp_path)
+
+        results = FileScanner().scan_directory(str(tmp_path), modified_since=self.CUTOFF,
+                                               max_files=2)
+
+        assert scanned_names(results, tmp_path) == {"new.go", "old/deep/fresh.go"}
+
+    def test_tool_parses_rfc3339(self, tmp_path):
+        self.make(tmp_path)
+
+        data = json.loads(scan_directory_tool.fn(str(tmp_path), modified_since="2024-05-01T12:00:00Z",
+                                            output_format="json", delta=False)[0].text)
+
+        assert scanned_names(data, tmp_path) == {"new.go", "old/deep/fresh.go"}
+        text = scan_directory_tool.fn(str(tmp_path), modified_since="last tuesday")[0].text
+        assert text.startswith("Error: modified_since must be an RFC 3339 timestamp")
+
+
+class TestStream:
+    FILES = {**TestWorkers.FILES, "README.txt": "notes\n"}
+
+    def test_one_json_line_per_file(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+        out = io.StringIO()
+
+        written = FileScanner().scan_directory_stream(str(tmp_path), out, workers=4)
+
+        lines = out.getvalue().splitlines()
+        assert written == len(lines) == len(self.FILES)
+        results = FileScanner().scan_directory(str(tmp_path))
+        assert sorted(json.loads(line)["file"] for line in lines) == sorted(results)
+        by_file = {json.loads(line)["file"]: json.loads(line) for line in lines}
+        assert all(by_file[path] == file_to_dict(structures, path)
+                   for path, structures in results.items())
+
+    def test_on_file_results_are_not_kept(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+        seen = {}
+
+        results = FileScanner().scan_directory(str(tmp_path),
+                                               on_file=seen.__setitem__)
+
+        assert sorted(results) == sorted(seen)
+        assert all(structures is None for structures in results.values())
+        assert all(structures for structures in seen.values())
+
+    def test_lines_before_an_error_are_complete(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+        out = io.StringIO()
+
+        with pytest.raises(LimitExceeded):
+            FileScanner().scan_directory_stream(str(tmp_path), out, max_files=5, workers=1)
+
+        lines = out.getvalue().splitlines()
+        assert len(lines) == 5 and out.getvalue().endswith("\n")
+        assert all(json.loads(line)["structures"] for line in lines)
+
+    def test_relative_paths(self, tmp_path):
+        make_tree(tmp_path, self.FILES)
+        out = io.StringIO()
+
+        FileScanner().scan_directory_stream(str(tmp_path), out, path_style="relative")
+
+        assert sorted(json.loads(line)["file"] for line in out.getvalue().splitlines()) == \
+            sorted(self.FILES)
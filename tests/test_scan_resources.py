@@ -0,0 +1,100 @@
+"""Tests for scan:// resources: URI resolution, paged listing and reads."""
+
+import json
+
+import pytest
+
+from scantool import server
+from scantool.scan_resources import (
+    decode_cursor,
+    encode_cursor,
+    list_page,
+    resolve_resource_path,
+    resource_uri,
+)
+from scantool.scanner import FileScanner
+
+
+def make_tree(root, files):
+    for relative, content in files.items():
+        path = root / relative
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+class TestResolve:
+    def test_relative_path_resolves_under_root(self, tmp_path):
+        make_tree(tmp_path, {"pkg/a.py": "x = 1\n"})
+        assert resolve_resource_path("pkg/a.py", tmp_path) == (tmp_path / "pkg/a.py").resolve()
+
+    def test_percent_encoded_path(self, tmp_path):
+        make_tree(tmp_path, {"my dir/a b.py": "x = 1\n"})
+        uri = resource_uri("my dir/a b.py")
+        assert uri == "scan://my%20dir/a%20b.py"
+        assert resolve_resource_path(uri[len("scan://"):], tmp_path).name == "a b.py"
+
+    @pytest.mark.parametrize("path", ["../outside.py", "pkg/../../outside.py", ""])
+    def test_escaping_the_root_is_rejected(self, tmp_path, path):
+        (tmp_path / "root").mkdir()
+        (tmp_path / "outside.py").write_text("x = 1\n")
+        with pytest.raises(ValueError):
+            resolve_resource_path(path, tmp_path / "root")
+
+    def test_missing_file(self, tmp_path):
+        with pytest.raises(FileNotFoundError):
+            resolve_resource_path("nope.py", tmp_path)
+
+
+class TestListPage:
+    def test_lists_supported_files_sorted(self, tmp_path):
+        make_tree(tmp_path, {"b.py": "x = 1\n", "a/c.go": "package a\n", "notes.bin": "\x00"})
+        page = list_page(FileScanner(), tmp_path)
+        assert [r["uri"] for r in page["resources"]] == ["scan://a/c.go", "scan://b.py"]
+        assert page["nextCursor"] is None
+
+    def test_pages_cover_every_file_once(self, tmp_path):
+        make_tree(tmp_path, {f"m{i:02d}.py": "x = 1\n" for i in range(25)})
+        seen, cursor = [], None
+        while True:
+            page = list_page(FileScanner(), tmp_path, cursor, page_size=10)
+            seen.extend(r["name"] for r in page["resources"])
+            cursor = page["nextCursor"]
+            if cursor is None:
+                break
+        assert seen == [f"m{i:02d}.py" for i in range(25)]
+
+    def test_cursor_survives_files_added_before_it(self, tmp_path):
+        make_tree(tmp_path, {"b.py": "", "c.py": "", "d.py": ""})
+        first = list_page(FileScanner(), tmp_path, page_size=2)
+        (tmp_path / "a.py").write_text("")
+        rest = list_page(FileScanner(), tmp_path, first["nextCursor"], page_size=2)
+        assert [r["name"] for r in rest["resources"]] == ["d.py"]
+
+    def test_cursor_round_trip_and_invalid(self):
+        assert decode_cursor(encode_cursor("pkg/ü.py")) == "pkg/ü.py"
+        with pytest.raises(ValueError):
+            decode_cursor("not base64!")
+
+
+class TestServerResources:
+    @pytest.fixture(autouse=True)
+    def root(self, tmp_path, monkeypatch):
+        monkeypatch.setattr(server, "_RESOURCE_ROOT", tmp_path.resolve())
+        return tmp_path
+
+    def test_read_returns_json_file_result(self, root):
+        make_tree(root, {"pkg/a.py": "def hello():\n    pass\n"})
+        data = json.loads(server.scan_resource.fn("pkg/a.py"))
+        assert data["file"] == "pkg/a.py"
+        assert any(s["name"] == "hello" for s in data["structures"])
+
+    def test_unsupported_file_raises(self, root):
+        make_tree(root, {"data.unknownext": "x"})
+        with pytest.raises(ValueError):
+            server.scan_resource.fn("data.unknownext")
+
+    def test_index_pages_link_to_readable_resources(self, root):
+        make_tree(root, {"a.py": "x = 1\n"})
+        index = json.loads(server.scan_index.fn())
+        assert index["resources"][0]["uri"] == "scan://a.py"
+        assert json.loads(server.scan_index_page.fn(encode_cursor("a.py")))["resources"] == []
@@ -0,0 +1,96 @@
+"""Tests for scanning several roots as one set: combined limits, nested
+roots, and relative paths disambiguated by root."""
+
+import json
+
+import pytest
+
+from scantool.path_style import root_of, style_paths
+from scantool.scanner import FileScanner, LimitExceeded
+from scantool.server import scan_directory
+
+NOTES = "Title\n=====\n\nbody\n"
+
+
+@pytest.fixture
+def two_roots(tmp_path):
+    for root, names in (("service", ["config.txt", "main.txt"]), ("lib", ["config.txt", "util.txt"])):
+        (tmp_path / root).mkdir()
+        for name in names:
+            (tmp_path / root / name).write_text(NOTES)
+    return str(tmp_path / "service"), str(tmp_path / "lib")
+
+
+class TestStylePaths:
+    def test_collisions_prefixed_with_root_name(self, two_roots):
+        service, lib = two_roots
+        paths = [f"{service}/config.txt", f"{service}/main.txt",
+                 f"{lib}/config.txt", f"{lib}/util.txt"]
+
+        assert list(style_paths(paths, [service, lib], "relative").values()) == [
+            "service/config.txt", "main.txt", "lib/config.txt", "util.txt"]
+        assert style_paths(paths, [service, lib], "absolute")[paths[0]] == paths[0]
+
+    def test_same_root_names_use_absolute_root(self, tmp_path):
+        first, second = tmp_path / "a" / "src", tmp_path / "b" / "src"
+        paths = [str(first / "x.go"), str(second / "x.go")]
+
+        styled = style_paths(paths, [str(first), str(second)], "relative")
+
+        assert styled[paths[0]] == f"{first.as_posix()}/x.go"
+
+    def test_deepest_root_wins(self, tmp_path):
+        inner = tmp_path / "pkg"
+
+        assert root_of(str(inner / "a.go"), [str(tmp_path), str(inner)]) == str(inner)
+        assert root_of(str(tmp_path / "pkgx" / "a.go"), [str(tmp_path), str(inner)]) == str(tmp_path)
+
+
+class TestScanner:
+    def test_roots_merged_in_order(self, two_roots):
+        service, lib = two_roots
+
+        results = FileScanner().scan_directory(service, roots=[lib], workers=1)
+
+        assert [p.split("/")[-2:] for p in results] == [
+            ["service", "config.txt"], ["service", "main.txt"],
+            ["lib", "config.txt"], ["lib", "util.txt"]]
+
+    def test_max_files_counts_the_combined_set(self, two_roots):
+        service, lib = two_roots
+
+        with pytest.raises(LimitExceeded) as raised:
+            FileScanner().scan_directory(service, roots=[lib], max_files=3, workers=1)
+
+        assert len(raised.value.results) == 3
+
+    def test_nested_root_scanned_once(self, tmp_path):
+        (tmp_path / "pkg").mkdir()
+        (tmp_path / "pkg" / "notes.txt").write_text(NOTES)
+
+        results = FileScanner().scan_directory(str(tmp_path), roots=[str(tmp_path / "pkg")])
+
+        assert len(results) == 1
+
+    def test_missing_root(self, two_roots):
+        with pytest.raises(FileNotFoundError, match="nowhere"):
+            FileScanner().scan_directory(two_roots[0], roots=["nowhere"])
+
+
+class TestTool:
+    def test_json_relative_paths(self, two_roots):
+        service, lib = two_roots
+
+        data = json.loads(scan_directory.fn(service, roots=[lib], output_format="json",
+                                            path_style="relative")[0].text)
+
+        assert sorted(data) == ["lib/config.txt", "main.txt", "service/config.txt", "util.txt"]
+        assert data["lib/config.txt"]["file"] == "lib/config.txt"
+
+    def test_tree_per_root(self, two_roots):
+        service, lib = two_roots
+
+        text = scan_directory.fn(service, roots=[lib], delta=False)[0].text
+
+        assert "service/" in text and "lib/" in text
+        assert text.index("main.txt") < text.index("util.txt")
@@ -0,0 +1,95 @@
+"""Tests for source_text: BOM and line-ending normalization before parsing,
+byte offsets mapped back onto the file, and invalid UTF-8 reported."""
+
+import pytest
+
+from scantool.languages import StructureNode, skip_reason
+from scantool.scanner import FileScanner
+from scantool.source_text import (
+    check_encoding_mode,
+    encoding_warning,
+    normalize_source,
+    restore_offsets,
+)
+
+
+class TestNormalizeSource:
+    def test_bom_and_line_endings(self):
+        assert normalize_source(b"\xef\xbb\xbfa\r\nb\rc\nd") == b"a\nb\nc\nd"
+
+    def test_line_count_kept(self):
+        source = b"\xef\xbb\xbfone\r\ntwo\r\n\r\nthree\rfour\n"
+
+        assert normalize_source(source).count(b"\n") == 5
+
+    def test_unchanged_source_not_copied(self):
+        source = b"package main\n"
+
+        assert normalize_source(source) is source
+
+    def test_bom_only_at_start(self):
+        assert normalize_source(b"a\xef\xbb\xbf") == b"a\xef\xbb\xbf"
+
+
+class TestRestoreOffsets:
+    def test_offsets_index_the_file(self):
+        original = b"\xef\xbb\xbfpackage p\r\n\r\nfunc F() {\r\n}\r\n"
+        normalized = normalize_source(original)
+        start = normalized.index(b"func")
+        node = StructureNode(type="function", name="F", start_line=3, end_line=4,
+                             start_offset=start, end_offset=normalized.index(b"}") + 1)
+        child = StructureNode(type="param", name="x", start_line=3, end_line=3,
+                              start_offset=start, end_offset=start + 4)
+        node.children = [child]
+
+        restore_offsets([node], original)
+
+        assert original[node.start_offset:node.end_offset] == b"func F() {\r\n}"
+        assert original[child.start_offset:child.end_offset] == b"func"
+
+    def test_lone_cr_keeps_offsets(self):
+        original = b"a\rfunc"
+        node = StructureNode(type="function", name="F", start_line=2, end_line=2,
+                             start_offset=2, end_offset=6)
+
+        restore_offsets([node], original)
+
+        assert (node.start_offset, node.end_offset) == (2, 6)
+
+
+class TestEncodingWarning:
+    def test_valid_source_has_none(self):
+        assert encoding_warning("// Grüße\npackage p\n".encode("utf-8")) is None
+
+    def test_counts_and_locates_invalid_bytes(self):
+        warning = encoding_warning(b"package p\n// caf\xe9 \xff\n")
+
+        assert warning.startswith("invalid UTF-8: 2 sequences, first at line 2, column 7 (byte 16)")
+
+    def test_spelled_out_replacement_character_is_valid(self):
+        warning = encoding_warning("�".encode("utf-8") + b"\xc3")
+
+        assert "1 sequence," in warning
+
+    def test_scan_file_warns_and_parses(self, tmp_path):
+        path = tmp_path / "notes.txt"
+        path.write_bytes(b"Title\n=====\n\ncaf\xe9 au lait\n")
+
+        structures = FileScanner().scan_file(str(path))
+
+        assert "line 4" in structures[0].file_metadata["encoding_warning"]
+        assert skip_reason(structures) is None
+
+    def test_scan_file_skip_mode(self, tmp_path):
+        path = tmp_path / "latin1.go"
+        path.write_bytes(b"package p\n\n// caf\xe9\nfunc F() {}\n")
+
+        structures = FileScanner().scan_file(str(path), invalid_encoding="skip")
+
+        assert skip_reason(structures) == "invalid_encoding"
+        assert "line 3" in structures[0].file_metadata["encoding_warning"]
+        assert len(structures) == 1
+
+    def test_unknown_mode(self):
+        with pytest.raises(ValueError, match="invalid_encoding"):
+            check_encoding_mode("lossy")
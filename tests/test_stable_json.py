@@ -0,0 +1,61 @@
+"""Tests for stable_json: canonical ordering of scan results for
+output_format="json-stable"."""
+
+import json
+
+from scantool.server import scan_directory, scan_file
+from scantool.stable_json import dumps_stable, stabilize
+
+GO = """package shop
+
+func Zeta() {}
+
+type Cart struct{}
+
+func (c *Cart) Add() {}
+
+func Alpha() {}
+"""
+
+
+class TestStabilize:
+    def test_nodes_by_line_then_name_file_info_first(self):
+        data = {"structures": [
+            {"type": "function", "name": "b", "start_line": 3},
+            {"type": "function", "name": "a", "start_line": 3},
+            {"type": "file-info", "name": "x.go", "start_line": 9},
+            {"type": "class", "name": "C", "start_line": 1, "children": [
+                {"type": "method", "name": "z", "start_line": 2},
+                {"type": "method", "name": "y", "start_line": 2}]},
+        ]}
+
+        nodes = stabilize(data)["structures"]
+
+        assert [n["name"] for n in nodes] == ["x.go", "C", "a", "b"]
+        assert [n["name"] for n in nodes[1]["children"]] == ["y", "z"]
+
+    def test_keys_sorted_and_other_lists_untouched(self):
+        text = dumps_stable({"b": 1, "a": {"decorators": ["z", "y"], "c": 2}})
+
+        assert text == json.dumps({"a": {"c": 2, "decorators": ["z", "y"]}, "b": 1}, indent=2)
+
+
+class TestTools:
+    def test_scan_file_json_stable_same_data_as_json(self, tmp_path):
+        path = tmp_path / "shop.go"
+        path.write_text(GO)
+
+        plain = json.loads(scan_file.fn(str(path), output_format="json", delta=False)[0].text)
+        stable = scan_file.fn(str(path), output_format="json-stable", delta=False)[0].text
+
+        assert json.loads(stable) == json.loads(dumps_stable(plain))
+        assert stable == scan_file.fn(str(path), output_format="json-stable")[0].text
+
+    def test_scan_directory_paths_sorted(self, tmp_path):
+        for name in ("b.go", "a.go"):
+            (tmp_path / name).write_text(GO)
+
+        text = scan_directory.fn(str(tmp_path), output_format="json-stable", delta=False)[0].text
+
+        data = json.loads(text)
+        assert list(data) == sorted(data)
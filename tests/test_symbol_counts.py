@@ -0,0 +1,63 @@
+"""Tests for symbol_counts: node counts by kind and lines per file, and
+their totals."""
+
+import json
+
+from scantool.languages import StructureNode
+from scantool.server import count_symbols
+from scantool.symbol_counts import count_file, count_symbols as count_results, format_counts
+
+
+def node(type, name, children=()):
+    return StructureNode(type=type, name=name, start_line=1, end_line=2,
+                         children=list(children))
+
+
+def info(lines, language="Python"):
+    return StructureNode(type="file-info", name="f", start_line=1, end_line=1,
+                         file_metadata={"language": language, "lines": lines})
+
+
+def test_counts_any_depth_without_bookkeeping_nodes():
+    structures = [info({"total": 20, "code": 15, "blank": 5}),
+                  node("imports", "imports"),
+                  node("class", "Job", [node("method", "run"), node("method", "stop")]),
+                  node("function", "main", [node("parse-error", "bad")])]
+    entry = count_file("/r/job.py", structures)
+    assert entry.counts == {"method": 2, "class": 1, "function": 1}
+    assert entry.parse_errors == 1
+    assert entry.to_dict()["lines"] == {"total": 20, "code": 15, "blank": 5}
+
+
+def test_totals_skip_unparsed_files():
+    results = {"/r/b.py": [info({"total": 10}), node("function", "f")],
+               "/r/a.go": [info({"total": 5}, "Go"), node("function", "g"), node("struct", "S")],
+               "/r/big.py": [StructureNode(type="file-info", name="big.py", start_line=1,
+                                           end_line=1, file_metadata={"skipped": "too_large"})],
+               "/r/logo.bin": [StructureNode(type="file-info", name="logo.bin", start_line=1,
+                                             end_line=1, file_metadata={"unsupported": True})],
+               "/r/none": None}
+    files, totals = count_results(results)
+    assert [f.file for f in files] == ["/r/a.go", "/r/b.py"]
+    assert totals.to_dict() == {"files": 2, "skipped": 2, "lines": {"total": 15},
+                                "counts": {"function": 2, "struct": 1}, "parse_errors": 0}
+
+    text = format_counts(files, totals, "/r")
+    assert text.splitlines()[0] == "2 files (2 skipped), 15 lines in /r: 2 function, 1 struct"
+    assert "a.go  5 lines: 1 function, 1 struct" in text
+    assert "b.py" not in format_counts(files, totals, "/r", per_file=False)
+
+
+def test_tool_on_directory(tmp_path):
+    (tmp_path / "app.py").write_text("class A:\n    def run(self):\n        pass\n\n\n"
+                                     "def main():\n    A().run()\n")
+    (tmp_path / "util.py").write_text("def helper():\n    return 1\n")
+
+    data = json.loads(count_symbols.fn(str(tmp_path), output_format="json")[0].text)
+    assert data["total"]["files"] == 2
+    assert data["total"]["counts"]["function"] == 2
+    assert data["total"]["counts"]["class"] == 1
+    assert {f["file"].rsplit("/", 1)[-1] for f in data["files"]} == {"app.py", "util.py"}
+    assert "files" not in json.loads(count_symbols.fn(str(tmp_path), per_file=False,
+                                                      output_format="json")[0].text)
+    assert count_symbols.fn(str(tmp_path / "missing"))[0].text.startswith("Error")
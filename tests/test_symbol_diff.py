@@ -0,0 +1,122 @@
+"""Tests for symbol_diff: added, removed and modified symbols between two
+trees, matched by symbol ID."""
+
+import json
+
+from scantool.scanner import FileScanner
+from scantool.server import diff_symbols
+from scantool.symbol_diff import diff_scans, format_symbol_diff
+
+USERS = '''\
+package users
+
+type UserService struct{}
+
+func (s *UserService) GetUser(id int64) error { return nil }
+
+func FormatUser(name string) string { return name }
+
+func DeleteUser(id int64) {}
+'''
+
+USERS_AFTER = '''\
+package users
+
+type UserService struct{}
+
+// GetUser now takes a context.
+func (s *UserService) GetUser(ctx any, id int64) error { return nil }
+
+func FormatUser(name string) string {
+\treturn "<" + name + ">"
+}
+'''
+
+
+def write_tree(root, files):
+    for rel_path, source in files.items():
+        path = root / rel_path
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(source)
+    return root
+
+
+def scan_pair(tmp_path, before_files, after_files):
+    before = write_tree(tmp_path / "before", before_files)
+    after = write_tree(tmp_path / "after", after_files)
+    scanner = FileScanner()
+    return diff_scans(scanner.scan_directory(str(before)), scanner.scan_directory(str(after)),
+                      str(before.resolve()), str(after.resolve()))
+
+
+def names(locations):
+    return sorted(loc.qualified_name for loc in locations)
+
+
+class TestDiffScans:
+    def test_added_removed_modified(self, tmp_path):
+        diff = scan_pair(tmp_path, {"users.go": USERS},
+                         {"users.go": USERS_AFTER + "\nfunc ListUsers() {}\n"})
+
+        assert names(diff.added) == ["ListUsers"]
+        assert names(diff.removed) == ["DeleteUser"]
+        assert {c.after.qualified_name: c.changes for c in diff.modified} == {
+            "UserService.GetUser": ["signature", "body"],
+            "FormatUser": ["body"],
+        }
+        assert diff.unchanged == 1
+
+    def test_whitespace_and_line_shifts_are_not_changes(self, tmp_path):
+        shifted = USERS.replace("package users\n", "package users\n\n\n").replace(
+            "{ return name }", "{ return name }   ")
+
+        diff = scan_pair(tmp_path, {"users.go": USERS}, {"users.go": shifted})
+
+        assert not (diff.added or diff.removed or diff.modified)
+
+    def test_move_within_package_is_unchanged(self, tmp_path):
+        head, _, delete = USERS.rpartition("func DeleteUser")
+
+        diff = scan_pair(tmp_path, {"users.go": USERS},
+                         {"users.go": head, "delete.go": "package users\n\nfunc DeleteUser" + delete})
+
+        assert not (diff.added or diff.removed or diff.modified)
+
+    def test_same_id_in_other_directory_is_another_symbol(self, tmp_path):
+        diff = scan_pair(tmp_path, {"a/users.go": USERS}, {"b/users.go": USERS})
+
+        assert len(diff.added) == len(diff.removed) == 4
+
+    def test_format(self, tmp_path):
+        diff = scan_pair(tmp_path, {"users.go": USERS}, {"users.go": USERS_AFTER})
+
+        text = format_symbol_diff(diff)
+
+        assert text.splitlines()[0] == "0 added, 1 removed, 2 modified, 1 unchanged"
+        was = [line for line in text.splitlines() if line.startswith("    was: ")]
+        assert len(was) == 1 and was[0].endswith("(id int64) error")
+
+
+class TestTool:
+    def test_json(self, tmp_path):
+        write_tree(tmp_path / "before", {"users.go": USERS})
+        write_tree(tmp_path / "after", {"users.go": USERS_AFTER})
+
+        data = json.loads(diff_symbols.fn(str(tmp_path / "before"), str(tmp_path / "after"),
+                                          output_format="json")[0].text)
+
+        assert [s["id"] for s in data["removed"]] == ["function:users.DeleteUser"]
+        assert data["modified"][0]["before"]["file"].endswith("before/users.go")
+
+    def test_identical_trees(self, tmp_path):
+        write_tree(tmp_path / "before", {"users.go": USERS})
+        write_tree(tmp_path / "after", {"users.go": USERS})
+
+        text = diff_symbols.fn(str(tmp_path / "before"), str(tmp_path / "after"))[0].text
+
+        assert text == "No symbol changes (4 unchanged)"
+
+    def test_missing_directory(self, tmp_path):
+        text = diff_symbols.fn(str(tmp_path / "nope"), str(tmp_path))[0].text
+
+        assert text.startswith("Error: Directory not found")
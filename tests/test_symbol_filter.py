@@ -0,0 +1,327 @@
+"""Tests for symbol_filter: output filters keep the scan's node shape and
+leave visibility decisions to the language."""
+
+import pytest
+
+from scantool.languages import StructField, StructureNode, get_language
+from scantool.scanner import FileScanner
+from scantool.server import scan_file
+from scantool.symbol_filter import (
+    check_kinds,
+    compile_name_pattern,
+    filter_exported,
+    filter_kinds,
+    filter_line_range,
+    filter_min_complexity,
+    filter_names,
+    filter_symbols,
+)
+
+GO_SOURCE = '''\
+package users
+
+import "fmt"
+
+// Service is the public entry point
+type Service struct {
+\tstore *store
+}
+
+type store struct{}
+
+// Lookup is exported on an exported receiver
+func (s *Service) Lookup(id int) string {
+\treturn fmt.Sprint(id)
+}
+
+// Flush is capitalized, but its receiver type is unexported
+func (s *store) Flush() error {
+\treturn nil
+}
+
+func NewService() *Service {
+\treturn &Service{}
+}
+
+func helper() {}
+'''
+
+PY_SOURCE = '''\
+class Public:
+    def run(self):
+        return self._step()
+
+    def _step(self):
+        return 1
+
+
+class _Hidden:
+    def run(self):
+        return 2
+
+
+def api():
+    return Public()
+
+
+def _internal():
+    return None
+'''
+
+
+def names(structures):
+    out = []
+    for node in structures:
+        if node.type != "file-info":
+            out.append(node.name)
+        out.extend(f"{node.name}.{child}" for child in names(node.children))
+    return out
+
+
+def scan(tmp_path, filename, source):
+    path = tmp_path / filename
+    path.write_text(source)
+    return FileScanner().scan_file(str(path))
+
+
+class TestExportedOnlyGo:
+    def test_unexported_receiver_excluded_even_if_capitalized(self, tmp_path):
+        structures = scan(tmp_path, "users.go", GO_SOURCE)
+
+        kept = names(filter_exported(structures, get_language(".go")))
+
+        assert "Lookup" in kept
+        assert "Flush" not in kept
+        assert "store" not in kept
+
+    def test_exported_declarations_kept_private_dropped(self, tmp_path):
+        structures = scan(tmp_path, "users.go", GO_SOURCE)
+
+        kept = names(filter_exported(structures, get_language(".go")))
+
+        assert {"Service", "NewService"} <= set(kept)
+        assert "helper" not in kept
+        assert "import statements" not in kept
+
+    def test_node_shape_is_preserved(self, tmp_path):
+        structures = scan(tmp_path, "users.go", GO_SOURCE)
+        original = next(s for s in structures if s.name == "NewService")
+
+        kept = filter_exported(structures, get_language(".go"))
+
+        node = next(s for s in kept if s.name == "NewService")
+        assert (node.type, node.start_line, node.end_line) == \
+            (original.type, original.start_line, original.end_line)
+        assert kept[0].type == "file-info"
+
+    def test_fields_narrowed_embedded_by_type_name(self):
+        fields = [StructField("ID", "int"), StructField("secret", "string"),
+                  StructField("", "*sync.Mutex"), StructField("", "base"),
+                  StructField("", "Page[T]")]
+        structures = [StructureNode(type="struct", name="User", start_line=1, end_line=7,
+                                    fields=fields)]
+
+        kept = filter_exported(structures, get_language(".go"))
+
+        assert [f.name or f.type for f in kept[0].fields] == ["ID", "*sync.Mutex", "Page[T]"]
+        assert len(structures[0].fields) == 5
+
+    def test_input_left_untouched(self, tmp_path):
+        structures = scan(tmp_path, "users.go", GO_SOURCE)
+        before = names(structures)
+
+        filter_exported(structures, get_language(".go"))
+
+        assert names(structures) == before
+
+    def test_tool_upper_case_suffix(self, tmp_path):
+        path = tmp_path / "USERS.GO"
+        path.write_text(GO_SOURCE)
+
+        text = scan_file.fn(str(path), exported_only=True)[0].text
+
+        assert "NewService" in text
+        assert "helper" not in text
+
+
+class TestExportedOnlyPython:
+    def test_underscore_names_and_private_containers_dropped(self, tmp_path):
+        structures = scan(tmp_path, "mod.py", PY_SOURCE)
+
+        kept = names(filter_exported(structures, get_language(".py")))
+
+        assert "Public" in kept and "Public.run" in kept and "api" in kept
+        assert "Public._step" not in kept
+        assert "_Hidden" not in kept and "_internal" not in kept
+
+
+class TestMinComplexity:
+    SOURCE = '''\
+package calc
+
+type Box struct{}
+
+func Simple() {}
+
+func (b *Box) Branchy(n int) int {
+\tif n > 1 {
+\t\treturn 1
+\t}
+\tfor n > 0 {
+\t\tn--
+\t}
+\treturn n
+}
+'''
+
+    def test_keeps_functions_at_or_above_threshold(self, tmp_path):
+        structures = scan(tmp_path, "calc.go", self.SOURCE)
+
+        kept = names(filter_min_complexity(structures, 3))
+
+        assert kept == ["Branchy"]
+
+    def test_python_containers_kept_for_context(self, tmp_path):
+        source = (
+            "class Worker:\n"
+            "    def run(self, x):\n"
+            "        if x:\n"
+            "            return 1\n"
+            "        return 0\n"
+            "\n"
+            "    def noop(self):\n"
+            "        pass\n"
+        )
+        structures = scan(tmp_path, "worker.py", source)
+
+        kept = names(filter_min_complexity(structures, 2))
+
+        assert kept == ["Worker", "Worker.run"]
+        assert names(structures) == ["Worker", "Worker.run", "Worker.noop"]
+
+
+class TestLineRange:
+    SOURCE = (
+        "def before():\n"      # 1
+        "    return 0\n"       # 2
+        "\n"                   # 3
+        "def spans():\n"       # 4
+        "    a = 1\n"          # 5
+        "    b = 2\n"          # 6
+        "    return a + b\n"   # 7
+        "\n"                   # 8
+        "class Holder:\n"      # 9
+        "    def one(self):\n" # 10
+        "        return 1\n"   # 11
+        "\n"                   # 12
+        "    def two(self):\n" # 13
+        "        return 2\n"   # 14
+    )
+
+    def test_function_starting_before_window_is_kept(self, tmp_path):
+        structures = scan(tmp_path, "mod.py", self.SOURCE)
+
+        kept = names(filter_line_range(structures, 6, 7))
+
+        assert kept == ["spans"]
+
+    def test_container_members_narrowed_to_window(self, tmp_path):
+        structures = scan(tmp_path, "mod.py", self.SOURCE)
+
+        kept = names(filter_line_range(structures, 13, None))
+
+        assert kept == ["Holder", "Holder.two"]
+
+    def test_input_left_untouched(self, tmp_path):
+        structures = scan(tmp_path, "mod.py", self.SOURCE)
+
+        filter_line_range(structures, 13, None)
+
+        assert names(structures) == ["before", "spans", "Holder", "Holder.one", "Holder.two"]
+
+    def test_open_start_and_file_info_survives(self, tmp_path):
+        structures = scan(tmp_path, "mod.py", self.SOURCE)
+
+        kept = filter_line_range(structures, None, 2)
+
+        assert kept[0].type == "file-info"
+        assert names(kept) == ["before"]
+
+
+class TestKinds:
+    @staticmethod
+    def tree():
+        def node(type_, name, line, children=()):
+            return StructureNode(type=type_, name=name, start_line=line, end_line=line,
+                                 children=list(children))
+
+        return [node("file-info", "mod", 1),
+                node("imports", "import statements", 2),
+                node("class", "Holder", 4, [node("method", "run", 5),
+                                            node("property", "size", 6)]),
+                node("interface", "Runner", 8),
+                node("function", "main", 10)]
+
+    def test_types_only(self):
+        assert names(filter_kinds(self.tree(), ["type", "interface"])) == ["Holder", "Runner"]
+
+    def test_container_kept_as_context(self):
+        assert names(filter_kinds(self.tree(), ["method", "import"])) == [
+            "import statements", "Holder", "Holder.run"]
+
+    def test_input_left_untouched(self):
+        structures = self.tree()
+
+        filter_kinds(structures, ["function"])
+
+        assert names(structures) == ["import statements", "Holder", "Holder.run",
+                                     "Holder.size", "Runner", "main"]
+
+    def test_go_scan(self, tmp_path):
+        structures = scan(tmp_path, "users.go", GO_SOURCE)
+
+        kept = filter_kinds(structures, ["function"])
+
+        assert kept[0].type == "file-info"
+        assert names(kept) == ["NewService", "helper"]
+
+    def test_unknown_kind(self):
+        check_kinds(None)
+        with pytest.raises(ValueError, match="'struct'"):
+            check_kinds(["function", "struct"])
+
+
+class TestNames:
+    tree = staticmethod(TestKinds.tree)
+
+    def test_regex_searched_in_bare_names(self):
+        assert names(filter_names(self.tree(), compile_name_pattern("^R"))) == ["Runner"]
+        assert names(filter_names(self.tree(), compile_name_pattern("un$"))) == [
+            "Holder", "Holder.run"]
+
+    def test_qualified_method_names(self):
+        pattern = compile_name_pattern(r"^Holder\.")
+
+        assert names(filter_names(self.tree(), pattern)) == []
+        assert names(filter_names(self.tree(), pattern, qualified=True)) == [
+            "Holder", "Holder.run"]
+
+    def test_go_receiver_qualifies_top_level_methods(self, tmp_path):
+        structures = scan(tmp_path, "users.go", GO_SOURCE)
+
+        kept = filter_names(structures, compile_name_pattern(r"^Service\."), qualified=True)
+
+        assert kept[0].type == "file-info"
+        assert names(kept) == ["Lookup"]
+
+    def test_combined_with_kinds(self):
+        pattern = compile_name_pattern("^(Holder|run)$")
+
+        assert names(filter_symbols(self.tree(), ["method"], pattern)) == ["Holder", "Holder.run"]
+        assert names(filter_symbols(self.tree(), ["type"], pattern)) == ["Holder"]
+        assert names(filter_symbols(self.tree())) == names(self.tree())
+
+    def test_invalid_regex(self):
+        assert compile_name_pattern(None) is None
+        with pytest.raises(ValueError, match="Invalid name_pattern '\\(Handle'"):
+            compile_name_pattern("(Handle")
@@ -0,0 +1,124 @@
+"""Tests for symbol_ids: IDs come from declaration identity only, so they
+survive edits that move declarations around."""
+
+from pathlib import Path
+
+from scantool.scanner import FileScanner
+from scantool.symbol_ids import symbol_namespace
+
+from conftest import write_tree
+
+GO_SOURCE = '''\
+package users
+
+type UserService struct{}
+
+func (s *UserService) GetUser(id int) string {
+\treturn ""
+}
+
+func NewUserService() *UserService {
+\treturn &UserService{}
+}
+
+func init() {}
+
+func init() {}
+'''
+
+
+def ids(structures):
+    out = {}
+    for node in structures:
+        if node.symbol_id:
+            out[node.name] = out.get(node.name, []) + [node.symbol_id]
+        for name, values in ids(node.children).items():
+            out[name] = out.get(name, []) + values
+    return out
+
+
+def scan(tmp_path, filename, source):
+    path = tmp_path / filename
+    path.write_text(source)
+    return FileScanner().scan_file(str(path))
+
+
+def directory_ids(root, files):
+    """ids() per file of a directory scan, by path relative to root."""
+    write_tree(root, files)
+    results = FileScanner().scan_directory(str(root))
+    return {str(path.relative_to(root.resolve())): ids(structures)
+            for path, structures in ((Path(p), s) for p, s in results.items())}
+
+
+class TestGoSymbolIds:
+    def test_methods_qualified_by_package_and_receiver(self, tmp_path):
+        found = ids(scan(tmp_path, "users.go", GO_SOURCE))
+
+        assert found["GetUser"] == ["method:users.UserService.GetUser"]
+        assert found["NewUserService"] == ["function:users.NewUserService"]
+        assert found["UserService"] == ["struct:users.UserService"]
+
+    def test_repeated_init_disambiguated_in_order(self, tmp_path):
+        found = ids(scan(tmp_path, "users.go", GO_SOURCE))
+
+        init_ids = [i for name, values in found.items() if "init" in name for i in values]
+        assert len(init_ids) == 2 and init_ids[1] == init_ids[0] + "#2"
+
+    def test_ids_stable_when_lines_shift(self, tmp_path):
+        before = ids(scan(tmp_path, "users.go", GO_SOURCE))
+        shifted = GO_SOURCE.replace("package users\n", "package users\n\n// Added\n\nconst x = 1\n")
+
+        after = ids(scan(tmp_path, "users.go", shifted))
+
+        assert after["GetUser"] == before["GetUser"]
+        assert after["NewUserService"] == before["NewUserService"]
+
+    def test_namespaced_by_directory_below_the_root(self, tmp_path):
+        util = "package util\n\nfunc Join() {}\n"
+
+        found = directory_ids(tmp_path, {"a/util/join.go": util, "b/util/join.go": util,
+                                         "main.go": "package main\n\nfunc main() {}\n"})
+
+        assert found["a/util/join.go"]["Join"] == ["function:a/util.Join"]
+        assert found["b/util/join.go"]["Join"] == ["function:b/util.Join"]
+        assert found["main.go"]["main"] == ["function:main.main"]
+
+    def test_scan_file_root(self, tmp_path):
+        write_tree(tmp_path, {"internal/users/users.go": GO_SOURCE})
+        path = tmp_path / "internal" / "users" / "users.go"
+
+        found = ids(FileScanner().scan_file(str(path), root=str(tmp_path)))
+
+        assert found["GetUser"] == ["method:internal/users.UserService.GetUser"]
+
+
+class TestSymbolNamespace:
+    def test_go_package_path(self):
+        assert symbol_namespace("/r/internal/users/a.go", "/r", "users") == "internal/users"
+        assert symbol_namespace("/r/internal/users/a_test.go", "/r", "users_test") == \
+            "internal/users_test"
+        assert symbol_namespace("/r/a.go", "/r", "main") == "main"
+        assert symbol_namespace("/r/internal/users/a.go", None, "users") == "users"
+
+    def test_path_without_suffix_without_a_package(self):
+        assert symbol_namespace("/r/pkg/utils.py", "/r", None) == "pkg/utils"
+        assert symbol_namespace("/r/pkg/utils.py", None, None) == "utils"
+
+
+class TestPythonSymbolIds:
+    def test_members_qualified_by_container_and_file_stem(self, tmp_path):
+        source = "class Worker:\n    def run(self):\n        pass\n\ndef main():\n    pass\n"
+
+        found = ids(scan(tmp_path, "jobs.py", source))
+
+        assert found["run"] == ["method:jobs.Worker.run"]
+        assert found["main"] == ["function:jobs.main"]
+
+    def test_same_file_name_in_two_directories(self, tmp_path):
+        source = "def helper():\n    pass\n"
+
+        found = directory_ids(tmp_path, {"a/utils.py": source, "b/utils.py": source})
+
+        assert found["a/utils.py"]["helper"] == ["function:a/utils.helper"]
+        assert found["b/utils.py"]["helper"] == ["function:b/utils.helper"]
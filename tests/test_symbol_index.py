@@ -0,0 +1,179 @@
+"""Tests for symbol_index: a persistent symbol index that re-parses only
+files whose mtime or size changed."""
+
+import json
+import threading
+
+import pytest
+
+from scantool.server import find_symbol as find_symbol_tool
+from scantool.symbol_index import INDEX_VERSION, SymbolIndex
+
+GO_SOURCE = '''\
+package users
+
+type UserService struct{}
+
+func (s *UserService) GetUser(id int64) error { return nil }
+
+func FormatUser(name string) string { return name }
+'''
+
+PY_SOURCE = '''\
+class Formatter:
+    def format_user(self, user):
+        return str(user)
+'''
+
+
+@pytest.fixture
+def tree(tmp_path):
+    root = tmp_path / "repo"
+    root.mkdir()
+    (root / "users.go").write_text(GO_SOURCE)
+    (root / "fmt.py").write_text(PY_SOURCE)
+    (root / "node_modules").mkdir()
+    (root / "node_modules" / "dep.py").write_text("def dep():\n    pass\n")
+    return root
+
+
+def open_index(tree, **kwargs):
+    return SymbolIndex(str(tree), index_path=str(tree.parent / "index.json"), **kwargs)
+
+
+def qualified(locations):
+    return sorted(loc.qualified_name for loc in locations)
+
+
+class TestBuild:
+    def test_lookup_and_by_kind(self, tree):
+        index = open_index(tree)
+
+        update = index.build()
+
+        assert (update.parsed, update.removed) == (2, 0)
+        assert qualified(index.lookup("GetUser")) == ["UserService.GetUser"]
+        assert qualified(index.lookup("format", match_mode="substring")) == [
+            "FormatUser", "Formatter", "Formatter.format_user"]
+        assert qualified(index.by_kind("method")) == ["Formatter.format_user",
+                                                      "UserService.GetUser"]
+        assert not index.lookup("dep")
+
+    def test_persists_across_instances(self, tree):
+        open_index(tree).build()
+
+        reopened = open_index(tree)
+        update = reopened.update()
+
+        assert (update.parsed, update.unchanged) == (0, 2)
+        assert reopened.lookup("FormatUser")[0].start_line == 7
+
+    def test_index_of_another_root_is_ignored(self, tree, tmp_path):
+        path = tree.parent / "index.json"
+        path.write_text(json.dumps({"version": INDEX_VERSION, "root": str(tmp_path / "other"),
+                                    "files": {}}))
+
+        assert len(open_index(tree)) == 0
+
+    def test_concurrent_saves(self, tree):
+        index = open_index(tree)
+        index.build()
+        errors = []
+
+        def save():
+            try:
+                index.save()
+            except Exception as e:
+                errors.append(e)
+
+        threads = [threading.Thread(target=save) for _ in range(8)]
+        for thread in threads:
+            thread.start()
+        for thread in threads:
+            thread.join()
+
+        assert errors == []
+        assert [p.name for p in tree.parent.iterdir() if p.name.endswith(".tmp")] == []
+        assert len(open_index(tree)) == len(index)
+
+    def test_corrupt_index_starts_empty(self, tree):
+        (tree.parent / "index.json").write_text("{not json")
+
+        assert open_index(tree).update().parsed == 2
+
+
+class TestUpdate:
+    def test_reparses_changed_adds_new_drops_deleted(self, tree):
+        index = open_index(tree)
+        index.build()
+        (tree / "users.go").write_text(GO_SOURCE + "\nfunc DeleteUser() {}\n")
+        (tree / "extra.py").write_text("def helper():\n    pass\n")
+        (tree / "fmt.py").unlink()
+
+        update = index.update()
+
+        assert (update.parsed, update.removed, update.unchanged) == (2, 1, 0)
+        assert qualified(index.lookup("DeleteUser")) == ["DeleteUser"]
+        assert qualified(index.lookup("helper")) == ["helper"]
+        assert not index.lookup("Formatter")
+
+    def test_changed_paths_only(self, tree):
+        index = open_index(tree)
+        index.build()
+        (tree / "users.go").write_text(GO_SOURCE + "\nfunc DeleteUser() {}\n")
+        (tree / "fmt.py").write_text("class Renamed:\n    pass\n")
+
+        update = index.update(["users.go"])
+
+        assert (update.parsed, update.removed) == (1, 0)
+        assert index.lookup("DeleteUser") and index.lookup("Formatter")
+
+    def test_deleted_path_dropped(self, tree):
+        index = open_index(tree)
+        index.build()
+        (tree / "fmt.py").unlink()
+
+        assert index.update([str(tree / "fmt.py")]).removed == 1
+        assert not index.lookup("Formatter")
+
+    def test_path_outside_root(self, tree):
+        with pytest.raises(ValueError):
+            open_index(tree).update(["../elsewhere.py"])
+
+    def test_saved_only_when_changed(self, tree):
+        index = open_index(tree)
+        index.build()
+        path = tree.parent / "index.json"
+        path.write_text(path.read_text() + " ")
+
+        index.update()
+
+        assert path.read_text().endswith(" ")
+
+
+class TestTool:
+    def test_find_symbol_use_index(self, tree, tmp_path, monkeypatch):
+        monkeypatch.setenv("SCANTOOL_INDEX_DIR", str(tmp_path / "indexes"))
+
+        first = find_symbol_tool.fn(str(tree), "FormatUser", use_index=True)[0].text
+        second = find_symbol_tool.fn(str(tree), "GetUser", use_index=True)[0].text
+
+        assert first.startswith("1 symbols matching 'FormatUser'")
+        assert first.endswith("index: 2 parsed, 0 removed, 0 unchanged")
+        assert "UserService.GetUser" in second
+        assert second.endswith("index: 0 parsed, 0 removed, 2 unchanged")
+        assert list((tmp_path / "indexes").glob("*.json"))
+
+    def test_gitignore_setting_has_its_own_index(self, tree, tmp_path, monkeypatch):
+        monkeypatch.setenv("SCANTOOL_INDEX_DIR", str(tmp_path / "indexes"))
+        (tree / ".gitignore").write_text("ignored.py\n")
+        (tree / "ignored.py").write_text("def ignored():\n    pass\n")
+
+        default = find_symbol_tool.fn(str(tree), "ignored", use_index=True)[0].text
+        everything = find_symbol_tool.fn(str(tree), "ignored", use_index=True,
+                                         respect_gitignore=False)[0].text
+        again = find_symbol_tool.fn(str(tree), "ignored", use_index=True)[0].text
+
+        assert "ignored.py" not in default and "ignored.py" in everything
+        assert again.endswith("index: 0 parsed, 0 removed, 2 unchanged")
+        assert len(list((tmp_path / "indexes").glob("*.json"))) == 2
@@ -0,0 +1,112 @@
+"""Tests for symbol_search: definitions are found by bare or qualified name,
+across languages, with each match mode."""
+
+import json
+
+import pytest
+
+from scantool.scanner import FileScanner
+from scantool.server import list_all_symbols
+from scantool.symbol_search import find_symbol, format_symbol_table, index_symbols, symbol_table
+
+GO_SOURCE = '''\
+package users
+
+type UserService struct{}
+
+func (s *UserService) GetUser(id int64) error { return nil }
+
+func FormatUser(name string) string { return name }
+'''
+
+PY_SOURCE = '''\
+class Formatter:
+    def format_user(self, user):
+        return str(user)
+'''
+
+
+@pytest.fixture
+def results(tmp_path):
+    (tmp_path / "users.go").write_text(GO_SOURCE)
+    (tmp_path / "fmt.py").write_text(PY_SOURCE)
+    return FileScanner().scan_directory(str(tmp_path))
+
+
+def qualified(locations):
+    return sorted(loc.qualified_name for loc in locations)
+
+
+class TestFindSymbol:
+    def test_exact_match_reports_file_and_line(self, results):
+        found = find_symbol(results, "FormatUser")
+
+        assert len(found) == 1
+        assert found[0].file.endswith("users.go") and found[0].start_line == 7
+
+    def test_go_method_matches_bare_and_qualified(self, results):
+        assert qualified(find_symbol(results, "GetUser")) == ["UserService.GetUser"]
+        assert qualified(find_symbol(results, "UserService.GetUser")) == ["UserService.GetUser"]
+
+    def test_python_method_qualified_by_class(self, results):
+        assert qualified(find_symbol(results, "Formatter.format_user")) == \
+            ["Formatter.format_user"]
+
+    def test_prefix_is_case_sensitive(self, results):
+        assert qualified(find_symbol(results, "UserService.", "prefix")) == \
+            ["UserService.GetUser"]
+        assert find_symbol(results, "userservice", "prefix") == []
+
+    def test_substring_is_case_insensitive_across_languages(self, results):
+        assert qualified(find_symbol(results, "formatuser", "substring")) == ["FormatUser"]
+        assert qualified(find_symbol(results, "FORMAT_USER", "substring")) == \
+            ["Formatter.format_user"]
+
+    def test_unknown_mode_rejected(self, results):
+        with pytest.raises(ValueError):
+            find_symbol(results, "x", "fuzzy")
+
+    def test_index_skips_file_info_and_imports(self, results):
+        types = {loc.type for loc in index_symbols(results)}
+
+        assert "file-info" not in types and "imports" not in types
+
+
+class TestSymbolTable:
+    def test_sorted_by_file_then_line(self, results):
+        table = symbol_table(results)
+
+        assert [(loc.file.rsplit("/", 1)[-1], loc.start_line, loc.qualified_name)
+                for loc in table] == [
+            ("fmt.py", 1, "Formatter"),
+            ("fmt.py", 2, "Formatter.format_user"),
+            ("users.go", 3, "UserService"),
+            ("users.go", 5, "UserService.GetUser"),
+            ("users.go", 7, "FormatUser"),
+        ]
+
+    def test_kinds(self, results):
+        assert qualified(symbol_table(results, ["method"])) == [
+            "Formatter.format_user", "UserService.GetUser"]
+        with pytest.raises(ValueError, match="Unknown symbol kind"):
+            symbol_table(results, ["widget"])
+
+    def test_format_caps_lines(self, results):
+        text = format_symbol_table(symbol_table(results), "src", max_results=2)
+
+        assert text.splitlines()[0] == "5 symbols in 2 files under src"
+        assert len(text.splitlines()) == 4
+        assert text.splitlines()[-1].startswith("+3 more")
+
+    def test_tool_json(self, tmp_path):
+        (tmp_path / "users.go").write_text(GO_SOURCE)
+
+        data = json.loads(list_all_symbols.fn(str(tmp_path), output_format="json")[0].text)
+
+        assert [(d["qualified_name"], d["type"], d["start_line"]) for d in data] == [
+            ("UserService", "struct", 3), ("UserService.GetUser", "method", 5),
+            ("FormatUser", "function", 7)]
+        assert all(d["file"].endswith("users.go") for d in data)
+
+    def test_tool_unknown_kind(self, tmp_path):
+        assert list_all_symbols.fn(str(tmp_path), kinds=["widget"])[0].text.startswith("Error:")
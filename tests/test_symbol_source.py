@@ -0,0 +1,129 @@
+"""Tests for symbol_source: one declaration's verbatim source, doc comment
+included, resolved by name, Type.Method or symbol ID."""
+
+import json
+
+import pytest
+
+from scantool.languages import get_language
+from scantool.scanner import FileScanner
+from scantool.server import get_symbol_source
+from scantool.symbol_source import symbol_source
+
+GO = """package users
+
+// UserService handles users.
+type UserService struct{}
+
+// GetUser loads a user.
+//
+// It never caches.
+func (s *UserService) GetUser(id int) error {
+	if id < 0 {
+		return nil
+	}
+	return nil
+}
+func After() {}
+
+// Orphan comment.
+
+func GetUser() {}
+
+func init() {}
+
+func init() {}
+"""
+
+PY = """class Store:
+    # Persisted in the repo.
+    @staticmethod
+    def save(item):
+        return item
+
+
+def save():
+    pass
+"""
+
+
+def source_of(tmp_path, name, content, query):
+    path = tmp_path / name
+    path.write_text(content)
+    structures = FileScanner().scan_file(str(path))
+    return symbol_source(structures, str(path), path.read_bytes(), query,
+                         get_language(path.suffix))
+
+
+class TestSymbolSource:
+    def test_method_with_doc_comment_and_closing_brace(self, tmp_path):
+        snippet = source_of(tmp_path, "users.go", GO, "UserService.GetUser")
+
+        assert snippet.source.startswith("// GetUser loads a user.\n//\n// It never caches.\n")
+        assert snippet.source.endswith("\treturn nil\n}\n")
+        assert "After" not in snippet.source
+        assert (snippet.start_line, snippet.end_line) == (6, 14)
+
+    def test_comment_separated_by_blank_line_not_included(self, tmp_path):
+        snippet = source_of(tmp_path, "users.go", GO, "function:users.GetUser")
+
+        assert snippet.source == "func GetUser() {}\n"
+
+    def test_bare_name_prefers_exact_qualified_match(self, tmp_path):
+        snippet = source_of(tmp_path, "users.go", GO, "GetUser")
+
+        assert snippet.start_line == 19
+
+    def test_repeated_init_ambiguous_then_picked_by_id(self, tmp_path):
+        with pytest.raises(LookupError, match="ambiguous.*\n  function:users.init @21"):
+            source_of(tmp_path, "users.go", GO, "init")
+        snippet = source_of(tmp_path, "users.go", GO, "function:users.init#2")
+
+        assert snippet.start_line == 23
+
+    def test_unknown_symbol(self, tmp_path):
+        with pytest.raises(LookupError, match="No symbol 'Missing'"):
+            source_of(tmp_path, "users.go", GO, "Missing")
+
+    def test_python_method_keeps_indentation_and_decorator(self, tmp_path):
+        snippet = source_of(tmp_path, "store.py", PY, "Store.save")
+
+        assert snippet.source == ("    # Persisted in the repo.\n    @staticmethod\n"
+                                  "    def save(item):\n        return item\n")
+
+
+class TestTool:
+    def test_text_output(self, tmp_path):
+        path = tmp_path / "users.go"
+        path.write_text(GO)
+
+        text = get_symbol_source.fn(str(path), "After")[0].text
+
+        assert text == f"{path}:15-15 function After\nfunc After() {{}}\n"
+
+    def test_json_output(self, tmp_path):
+        path = tmp_path / "users.go"
+        path.write_text(GO)
+
+        data = json.loads(get_symbol_source.fn(str(path), "UserService.GetUser",
+                                               output_format="json")[0].text)
+
+        assert data["id"] == "method:users.UserService.GetUser"
+        assert data["start_line"] == 6
+
+    def test_id_from_a_directory_scan(self, tmp_path):
+        (tmp_path / "users").mkdir()
+        path = tmp_path / "users" / "users.go"
+        path.write_text(GO)
+
+        text = get_symbol_source.fn(str(path), "method:users.UserService.GetUser",
+                                    root=str(tmp_path))[0].text
+
+        assert text.startswith(f"{path}:")
+
+    def test_errors(self, tmp_path):
+        path = tmp_path / "users.go"
+        path.write_text(GO)
+
+        assert get_symbol_source.fn(str(path), "Nope")[0].text.startswith("Error: No symbol")
+        assert get_symbol_source.fn(str(tmp_path / "x.go"), "A")[0].text.startswith("Error:")
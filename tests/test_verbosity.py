@@ -0,0 +1,98 @@
+"""Tests for verbosity: names / signatures / full output levels for scan
+results, JSON and tree."""
+
+import json
+
+from scantool.server import scan_directory, scan_file, scan_file_content
+from scantool.verbosity import select_fields
+
+GO = """package shop
+
+// Cart holds items.
+type Cart struct {
+	Items []string
+}
+
+// Add appends an item.
+func (c *Cart) Add(item string) error {
+	c.Items = append(c.Items, item)
+	return nil
+}
+"""
+
+RESULT = {"file": "shop.go", "language": "go", "doc": "Package shop.",
+          "lines": {"total": 12, "blank": 2},
+          "structures": [{"type": "struct", "name": "Cart", "start_line": 4, "end_line": 6,
+                          "line_count": 3, "doc": "Cart holds items.", "methods": ["Add"],
+                          "children": [{"type": "field", "name": "Items", "start_line": 5,
+                                        "end_line": 5, "complexity": {"lines": 1}}]}]}
+
+
+class TestSelectFields:
+    def test_names(self):
+        assert select_fields(RESULT, "names") == {
+            "file": "shop.go", "language": "go",
+            "structures": [{"type": "struct", "name": "Cart",
+                            "children": [{"type": "field", "name": "Items"}]}]}
+
+    def test_signatures_keeps_positions_drops_docs(self):
+        cart = select_fields(RESULT, "signatures")["structures"][0]
+
+        assert cart == {"type": "struct", "name": "Cart", "start_line": 4, "end_line": 6,
+                        "line_count": 3, "methods": ["Add"],
+                        "children": [{"type": "field", "name": "Items",
+                                      "start_line": 5, "end_line": 5}]}
+
+    def test_full_unchanged(self):
+        assert select_fields(RESULT, "full") is RESULT
+
+
+class TestTools:
+    def test_scan_file_json_names(self, tmp_path):
+        path = tmp_path / "shop.go"
+        path.write_text(GO)
+
+        data = json.loads(scan_file.fn(str(path), output_format="json",
+                                       verbosity="names")[0].text)
+
+        assert [(n["type"], n["name"]) for n in data["structures"]][1:] == [
+            ("struct", "Cart"), ("method", "Add")]
+        assert all(set(n) <= {"type", "name", "children"} for n in data["structures"])
+
+    def test_scan_file_tree_names(self, tmp_path):
+        path = tmp_path / "shop.go"
+        path.write_text(GO)
+
+        text = scan_file.fn(str(path), verbosity="names", delta=False)[0].text
+
+        assert text.splitlines()[0] == "shop.go"
+        assert "@" not in text and "# " not in text and "error" not in text
+        assert "- Add" in text
+
+    def test_scan_file_tree_signatures(self, tmp_path):
+        path = tmp_path / "shop.go"
+        path.write_text(GO)
+
+        text = scan_file.fn(str(path), verbosity="signatures", delta=False)[0].text
+
+        assert "- Add (c *Cart) (item string) error @9" in text
+        assert "appends" not in text and "append(" not in text
+
+    def test_scan_file_content_and_directory(self, tmp_path):
+        (tmp_path / "shop.go").write_text(GO)
+
+        tree = scan_file_content.fn(GO, "shop.go", verbosity="signatures")[0].text
+        data = json.loads(scan_directory.fn(str(tmp_path), output_format="json",
+                                            verbosity="names", delta=False)[0].text)
+
+        assert "@9" in tree and "appends" not in tree
+        assert all("start_line" not in n for result in data.values()
+                   for n in result["structures"])
+
+    def test_invalid_level(self, tmp_path):
+        path = tmp_path / "shop.go"
+        path.write_text(GO)
+
+        text = scan_file.fn(str(path), verbosity="terse")[0].text
+
+        assert text == "Error: Invalid verbosity 'terse'. Use 'names', 'signatures', 'full'."
@@ -0,0 +1,150 @@
+"""Tests for watch: polled change events with debounce, scan guards and
+cancellation. A scripted clock and wait hook drive the polls — no sleeps."""
+
+import asyncio
+import os
+import threading
+import time
+
+from scantool.scanner import FileScanner
+from scantool.server import watch_directory
+from scantool.watch import watch
+
+DEBOUNCE = 0.2
+STEP = 0.125  # fake seconds per poll: a change settles two polls after it stops
+
+
+class Script:
+    """wait hook for watch(): each poll first runs the next step (a file
+    change, or None for an idle poll), then the fake clock advances."""
+
+    def __init__(self, steps):
+        self.steps = list(steps)
+        self.now = 0.0
+
+    def clock(self):
+        return self.now
+
+    def wait(self, interval):
+        if not self.steps:
+            return True
+        step = self.steps.pop(0)
+        if step is not None:
+            step()
+        self.now += STEP
+        return False
+
+
+def events_of(root, steps, **kwargs):
+    script = Script(steps)
+    return list(watch(str(root), clock=script.clock, wait=script.wait,
+                      debounce=DEBOUNCE, **kwargs))
+
+
+IDLE = [None, None, None]
+
+
+def age(*directories):
+    """Directory mtimes an hour back: older than watch's racy window, and
+    any entry created later changes them."""
+    past = time.time_ns() - 3600 * 10**9
+    for directory in directories:
+        os.utime(directory, ns=(past, past))
+
+
+class CountingScanner(FileScanner):
+    walks = 0
+
+    def walk_files(self, *args, **kwargs):
+        self.walks += 1
+        return super().walk_files(*args, **kwargs)
+
+
+class TestWatch:
+    def test_baseline_files_produce_no_events(self, tmp_path):
+        (tmp_path / "a.py").write_text("def a():\n    pass\n")
+
+        assert events_of(tmp_path, IDLE) == []
+
+    def test_create_modify_delete_with_fresh_results(self, tmp_path):
+        path = tmp_path / "b.py"
+
+        events = events_of(tmp_path, [
+            lambda: path.write_text("def first():\n    pass\n"), *IDLE,
+            lambda: path.write_text("def second():\n    return 1\n"), *IDLE,
+            path.unlink, *IDLE,
+        ])
+
+        assert [(e.kind, e.path) for e in events] == [
+            ("created", str(path)), ("modified", str(path)), ("deleted", str(path))]
+        assert any(n.name == "first" for n in events[0].structures)
+        assert any(n.name == "second" for n in events[1].structures)
+        assert events[2].structures is None
+
+    def test_rapid_writes_debounced_to_one_event(self, tmp_path):
+        path = tmp_path / "c.py"
+        path.write_text("x = 0\n")
+
+        # Writes one poll apart, faster than the debounce window
+        events = events_of(tmp_path, [
+            *(lambda i=i: path.write_text(f"x = {i}{' ' * i}\n") for i in range(1, 4)), *IDLE])
+
+        assert [e.kind for e in events] == ["modified"]
+
+    def test_in_place_edits_need_no_walk(self, tmp_path):
+        (tmp_path / "sub").mkdir()
+        path = tmp_path / "sub" / "d.py"
+        path.write_text("x = 1\n")
+        age(tmp_path / "sub", tmp_path)
+        scanner = CountingScanner()
+
+        events = events_of(tmp_path, [lambda: path.write_text("x = 22\n"), *IDLE],
+                           scanner=scanner)
+
+        assert [(e.kind, e.path) for e in events] == [("modified", str(path))]
+        assert scanner.walks == 1  # the baseline; the edit was seen by stat alone
+
+    def test_file_created_in_a_subdirectory(self, tmp_path):
+        (tmp_path / "sub").mkdir()
+        (tmp_path / "sub" / "d.py").write_text("x = 1\n")
+        age(tmp_path / "sub", tmp_path)
+        created = tmp_path / "sub" / "e.py"
+
+        events = events_of(tmp_path, [lambda: created.write_text("y = 1\n"), *IDLE])
+
+        assert [(e.kind, e.path) for e in events] == [("created", str(created))]
+
+    def test_periodic_rescan(self, tmp_path):
+        age(tmp_path)
+        scanner = CountingScanner()
+
+        events_of(tmp_path, [None] * 5, scanner=scanner, rescan=STEP * 2)
+
+        assert scanner.walks == 3  # baseline, then every second poll
+
+    def test_large_and_binary_files_are_skipped_stubs(self, tmp_path):
+        events = events_of(tmp_path, [
+            lambda: (tmp_path / "big.py").write_text("x = 1\n" * 20),
+            lambda: (tmp_path / "blob.py").write_bytes(b"x = 1\x00\x01\x02"), *IDLE,
+        ], max_file_size=50)
+
+        assert [(e.kind, e.structures[0].file_metadata["skipped"]) for e in events] == [
+            ("created", "too_large"), ("created", "binary")]
+
+    def test_cancel_stops_the_generator(self, tmp_path):
+        cancel = threading.Event()
+        cancel.set()
+
+        assert list(watch(str(tmp_path), cancel=cancel)) == []
+
+
+class TestTool:
+    def test_no_changes_within_timeout(self, tmp_path):
+        text = asyncio.run(watch_directory.fn(str(tmp_path), timeout=0.05))[0].text
+
+        assert text == f"No changes in {tmp_path} within 0.05s"
+
+    def test_missing_directory(self, tmp_path):
+        text = asyncio.run(watch_directory.fn(str(tmp_path / "missing"), timeout=0.05))[0].text
+
+        assert text.startswith("Error: Directory not found")
@@ -4,6 +4,7 @@ import tempfile
 import os
 from pathlib import Path
 
+from scantool.file_json import file_to_dict
 from scantool.scanner import FileScanner
 
 
@@ -187,3 +188,65 @@ def test_fallback_mode():
 
     finally:
         os.unlink(temp_path)
+
+
+def test_type_aliases_and_exported_constants(file_scanner, tmp_path):
+    """type aliases and exported non-function consts get their own kinds."""
+    source = (
+        "export type UserId = string | number;\n"
+        "type Pair<T> = [T, T];\n"
+        "/** Default page size */\n"
+        "export const PAGE_SIZE: number = 50, MAX_PAGES = 10;\n"
+        "export const fetchPage = async (n: number) => n;\n"
+        "const internal = 1;\n"
+        "export let counter = 0;\n"
+    )
+    path = tmp_path / "api.ts"
+    path.write_text(source)
+
+    structures = file_scanner.scan_file(str(path))
+    kinds = {s.name: s.type for s in structures if s.type != "file-info"}
+
+    assert kinds == {
+        "UserId": "type-alias",
+        "Pair": "type-alias",
+        "PAGE_SIZE": "constant",
+        "MAX_PAGES": "constant",
+        "fetchPage": "function",
+    }
+    alias = next(s for s in structures if s.name == "Pair")
+    assert alias.signature == "<T> = [T, T]"
+    page_size = next(s for s in structures if s.name == "PAGE_SIZE")
+    assert page_size.signature == ": number"
+    assert page_size.docstring == "Default page size"
+    assert "export" in page_size.modifiers
+
+
+def test_language_reported_per_extension(file_scanner, tmp_path):
+    """One handler covers both languages; file metadata tells them apart."""
+    (tmp_path / "a.ts").write_text("export function f() {}\n")
+    (tmp_path / "b.mjs").write_text("export function g() {}\n")
+
+    ts = file_scanner.scan_file(str(tmp_path / "a.ts"))
+    js = file_scanner.scan_file(str(tmp_path / "b.mjs"))
+
+    assert ts[0].file_metadata["language"] == "TypeScript"
+    assert js[0].file_metadata["language"] == "JavaScript"
+
+
+def test_func_kind(file_scanner):
+    """Free functions, methods and arrow functions bound to a const in JSON."""
+    path = "tests/typescript/samples/basic.ts"
+    data = file_to_dict(file_scanner.scan_file(path), path)
+
+    def kinds(nodes):
+        for node in nodes:
+            if "func_kind" in node:
+                yield node["name"], node["func_kind"]
+            yield from kinds(node.get("children", []))
+
+    found = dict(kinds(data["structures"]))
+    assert found["generateId"] == "function"
+    assert found["calculateStats"] == "func_literal"
+    assert found["constructor"] == "method"
+    assert "AuthService" not in found